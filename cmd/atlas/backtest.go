@@ -5,18 +5,22 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"text/tabwriter"
 	"time"
 
+	"github.com/newthinker/atlas/internal/app"
 	"github.com/newthinker/atlas/internal/backtest"
 	"github.com/newthinker/atlas/internal/collector"
 	"github.com/newthinker/atlas/internal/collector/crypto"
 	"github.com/newthinker/atlas/internal/collector/eastmoney"
 	"github.com/newthinker/atlas/internal/collector/yahoo"
+	"github.com/newthinker/atlas/internal/config"
 	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/router"
+	prismstore "github.com/newthinker/atlas/internal/storage/prism"
 	"github.com/newthinker/atlas/internal/strategy"
-	"github.com/newthinker/atlas/internal/strategy/ma_crossover"
-	"github.com/newthinker/atlas/internal/strategy/price_percentile"
+	"github.com/newthinker/atlas/internal/strategy/builtin"
 	"github.com/spf13/cobra"
 )
 
@@ -26,6 +30,13 @@ var (
 	backtestSymbol string
 	backtestFrom   string
 	backtestTo     string
+	backtestCosts  string
+	backtestFill   string
+	backtestBench  string
+	backtestFunds  string
+	backtestReport string
+	backtestRouter bool
+	backtestCrisis bool
 )
 
 var backtestCmd = &cobra.Command{
@@ -40,6 +51,13 @@ func init() {
 	backtestCmd.Flags().StringVar(&backtestSymbol, "symbol", "", "Symbol to backtest (required)")
 	backtestCmd.Flags().StringVar(&backtestFrom, "from", "", "Start date YYYY-MM-DD (required)")
 	backtestCmd.Flags().StringVar(&backtestTo, "to", "", "End date YYYY-MM-DD (required)")
+	backtestCmd.PersistentFlags().StringVar(&backtestCosts, "costs", "market", "Execution costs and trading rules: market (per-symbol market model) or none")
+	backtestCmd.PersistentFlags().StringVar(&backtestBench, "benchmark", "", "Benchmark symbol for alpha/beta (default: CSI 300, HSI or S&P 500 by market; none to disable)")
+	backtestCmd.PersistentFlags().StringVar(&backtestFunds, "fundamentals", "auto", "Point-in-time fundamentals for valuation strategies: auto (prism store, then EPS reconstruction), prism, eps or none")
+	backtestCmd.Flags().BoolVar(&backtestRouter, "router", false, "Replay the configured router filters (min confidence, actions, cooldown, percentile step) on bar time and trade only signals that would have been notified")
+	backtestCmd.PersistentFlags().BoolVar(&backtestCrisis, "crisis-gate", false, "Replay the configured crisis_gate over the crisis monitor's history: each bar sees the system state evaluated the day before")
+	backtestCmd.Flags().StringVar(&backtestReport, "report", "", "Also write a self-contained HTML tearsheet to this file")
+	backtestCmd.PersistentFlags().StringVar(&backtestFill, "fill", string(backtest.FillSameClose), "When signals execute: same_close, next_open, next_close or next_vwap")
 
 	backtestCmd.MarkFlagRequired("symbol")
 	backtestCmd.MarkFlagRequired("from")
@@ -53,7 +71,38 @@ func init() {
 type backtestDeps struct {
 	provider   backtest.OHLCVProvider
 	strategies *strategy.Engine
+	settings   backtestSettings
 	out        io.Writer
+	report     string // HTML tearsheet path; "" = none
+}
+
+// backtestSettings are the run options shared by the backtest commands.
+type backtestSettings struct {
+	models    backtest.ModelSelector // nil = frictionless fills
+	fill      backtest.FillModel     // "" = same-bar close
+	benchmark string                 // "" = market default, "none" = no benchmark
+	// fundamentals feeds valuation strategies; nil makes them fail with
+	// backtest.ErrNoFundamentals.
+	fundamentals backtest.FundamentalProvider
+	// filter replays notification routing; nil trades every signal.
+	filter func() backtest.SignalFilter
+	// gate replays the crisis gate ahead of filter; nil gates nothing.
+	gate backtest.SignalGate
+}
+
+// prefetchedProvider serves the already-fetched bars of one symbol and
+// delegates every other symbol (the benchmark) to the underlying provider.
+type prefetchedProvider struct {
+	symbol string
+	data   []core.OHLCV
+	backtest.OHLCVProvider
+}
+
+func (p prefetchedProvider) FetchHistory(symbol string, start, end time.Time, interval string) ([]core.OHLCV, error) {
+	if symbol == p.symbol {
+		return p.data, nil
+	}
+	return p.OHLCVProvider.FetchHistory(symbol, start, end, interval)
 }
 
 // staticOHLCVProvider serves pre-fetched bars to the engine, so the history is
@@ -67,29 +116,154 @@ func (s staticOHLCVProvider) FetchHistory(symbol string, start, end time.Time, i
 }
 
 func runBacktest(cmd *cobra.Command, args []string) error {
-	// backtest 全程不读配置，故必须显式装配闸门：下面三家 collector 在构造函数里
-	// 快照 policy.Default()，不装就拿到懒构造的无账本 Gate，
-	// cache.enabled / cache.ttl / 整个 collector.topics 静默失效。
-	ensurePolicyGate()
+	// backtest 只为估值回看年数与 Prism 库路径读配置（不可读时退化为默认值），故必须
+	// 显式装配闸门：下面三家 collector 在构造函数里快照 policy.Default()，不装就拿到
+	// 懒构造的无账本 Gate，cache.enabled / cache.ttl / 整个 collector.topics 静默失效。
+	cfg := ensurePolicyGate()
 	reg := collector.NewRegistry()
 	reg.Register(yahoo.New())
 	reg.Register(eastmoney.New())
 	reg.Register(crypto.New())
 
-	provider := collector.SelectForSymbol(reg, backtestSymbol)
-	if provider == nil {
+	if collector.SelectForSymbol(reg, backtestSymbol) == nil {
 		return fmt.Errorf("no collector available for symbol %s", backtestSymbol)
 	}
 
+	settings, err := backtestSettingsFromFlags()
+	if err != nil {
+		return err
+	}
+	provider := registryProvider{reg: reg}
+	funds, closeFunds, err := fundamentalFeed(backtestFunds, cfg, provider)
+	if err != nil {
+		return err
+	}
+	defer closeFunds()
+	settings.fundamentals = funds
+	if backtestRouter {
+		routerCfg := app.RouterConfig(cfg)
+		settings.filter = func() backtest.SignalFilter { return router.New(routerCfg, nil, nil) }
+	}
+	if backtestCrisis {
+		gate, closeGate, err := newReplayCrisisGate(cfg.CrisisGate)
+		if err != nil {
+			return err
+		}
+		defer closeGate()
+		settings.gate = gate
+	}
+	strategies, closeStrategies := withConfiguredStrategies(newBacktestEngine(), cfg, os.Stderr)
+	defer closeStrategies()
+	deps := backtestDeps{provider: provider, strategies: strategies, settings: settings, out: os.Stdout, report: backtestReport}
+	return executeBacktest(deps, args[0], backtestSymbol, backtestFrom, backtestTo)
+}
+
+// newBacktestEngine registers the strategies that can be backtested offline.
+// The valuation strategies read point-in-time fundamentals from the
+// --fundamentals feed (defaults as in export-signals).
+func newBacktestEngine() *strategy.Engine {
 	engine := strategy.NewEngine()
-	engine.Register(ma_crossover.New(50, 200))
-	// price_percentile works on OHLCV alone, so it backtests offline. pe_percentile
-	// is intentionally not registered: it reads a precomputed PE percentile from an
-	// online valuation source the backtest engine does not provide.
-	engine.Register(price_percentile.New())
+	for _, s := range builtin.New() {
+		engine.Register(s)
+	}
+	return engine
+}
 
-	deps := backtestDeps{provider: provider, strategies: engine, out: os.Stdout}
-	return executeBacktest(deps, args[0], backtestSymbol, backtestFrom, backtestTo)
+// fundamentalFeed maps the --fundamentals flag to a point-in-time fundamental
+// feed: "prism" reads the valuation rows stored by `atlas prism refresh`,
+// "eps" rebuilds PE from yahoo EPS history over prices, "auto" tries prism
+// (when enabled) before EPS, and "none" disables valuation strategies. release
+// closes the prism store and is safe to call for every kind.
+func fundamentalFeed(kind string, cfg *config.Config, prices backtest.OHLCVProvider) (feed backtest.FundamentalProvider, release func(), err error) {
+	release = func() {}
+	lookback := cfg.Valuation.LookbackYears
+	eps := backtest.NewEPSFundamentals(prices, yahoo.New(), lookback)
+
+	openPrism := func() (*backtest.PrismFundamentals, error) {
+		prismCfg := cfg.Prism
+		prismCfg.ApplyDefaults()
+		store, err := prismstore.Open(prismCfg.DBPath)
+		if err != nil {
+			return nil, fmt.Errorf("opening prism store: %w", err)
+		}
+		release = func() { store.Close() }
+		return backtest.NewPrismFundamentals(store, lookback), nil
+	}
+
+	switch kind {
+	case "none":
+		return nil, release, nil
+	case "eps":
+		return eps, release, nil
+	case "prism":
+		p, err := openPrism()
+		if err != nil {
+			return nil, release, err
+		}
+		return p, release, nil
+	case "auto", "":
+		if !cfg.Prism.Enabled {
+			return eps, release, nil
+		}
+		p, err := openPrism()
+		if err != nil {
+			return nil, release, err
+		}
+		return backtest.FundamentalChain{p, eps}, release, nil
+	}
+	return nil, release, fmt.Errorf("unknown --fundamentals %q (want auto, prism, eps or none)", kind)
+}
+
+// backtestSettingsFromFlags reads the --costs and --fill flags.
+func backtestSettingsFromFlags() (backtestSettings, error) {
+	models, err := executionModels(backtestCosts)
+	if err != nil {
+		return backtestSettings{}, err
+	}
+	fill, err := backtest.ParseFillModel(backtestFill)
+	if err != nil {
+		return backtestSettings{}, err
+	}
+	return backtestSettings{models: models, fill: fill, benchmark: backtestBench}, nil
+}
+
+// resolveBenchmark picks the benchmark symbol for a run on symbol: the
+// --benchmark value, nothing for "none", or the market default.
+func resolveBenchmark(flag, symbol string) string {
+	switch flag {
+	case "none":
+		return ""
+	case "":
+		return defaultBenchmark(symbol)
+	}
+	return flag
+}
+
+// defaultBenchmark maps symbol's market to the benchmark export-ohlcv uses
+// for it (benchmarkForMarket). Markets without a qlib bundle have none.
+func defaultBenchmark(symbol string) string {
+	switch collector.MarketForSymbol(symbol) {
+	case core.MarketCNA:
+		return benchmarkForMarket("cn")
+	case core.MarketHK:
+		return benchmarkForMarket("hk")
+	case core.MarketUS:
+		return benchmarkForMarket("us")
+	}
+	return ""
+}
+
+// executionModels maps the --costs flag to a per-symbol execution model
+// selector: "market" applies each symbol's market costs and trading rules,
+// "none" fills frictionlessly at the signal price.
+func executionModels(kind string) (backtest.ModelSelector, error) {
+	switch kind {
+	case "market", "":
+		return backtest.MarketModels, nil
+	case "none":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown --costs %q (want market or none)", kind)
 }
 
 // parseBacktestDate parses a YYYY-MM-DD date, wrapping failures with which flag
@@ -136,12 +310,38 @@ func executeBacktest(deps backtestDeps, strategyName, symbol, fromStr, toStr str
 		return nil
 	}
 
-	result, err := backtest.New(staticOHLCVProvider{data: data}).Run(context.Background(), strat, symbol, from, to)
+	bt := backtest.New(prefetchedProvider{symbol: symbol, data: data, OHLCVProvider: deps.provider},
+		backtest.WithExecutionModels(deps.settings.models),
+		backtest.WithFillModel(deps.settings.fill),
+		backtest.WithBenchmark(func(s string) string { return resolveBenchmark(deps.settings.benchmark, s) }),
+		backtest.WithFundamentals(deps.settings.fundamentals),
+		backtest.WithSignalFilter(deps.settings.filter),
+		backtest.WithSignalGate(deps.settings.gate),
+	)
+	result, err := bt.Run(context.Background(), strat, symbol, from, to)
 	if err != nil {
 		return fmt.Errorf("running backtest: %w", err)
 	}
 
 	printBacktestResult(deps.out, result, from, to)
+	if deps.report != "" {
+		if err := writeBacktestReport(deps.report, result); err != nil {
+			return err
+		}
+		fmt.Fprintf(deps.out, "\nReport written to %s\n", deps.report)
+	}
+	return nil
+}
+
+// writeBacktestReport renders r as an HTML tearsheet at path.
+func writeBacktestReport(path string, r *backtest.Result) error {
+	html, err := backtest.RenderReportHTML(r)
+	if err != nil {
+		return fmt.Errorf("rendering report: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(html), 0o644); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
 	return nil
 }
 
@@ -150,7 +350,14 @@ func printBacktestResult(out io.Writer, r *backtest.Result, from, to time.Time)
 	fmt.Fprintln(out, "=== ATLAS Backtest ===")
 	fmt.Fprintf(out, "Strategy: %s\n", r.Strategy)
 	fmt.Fprintf(out, "Symbol:   %s\n", r.Symbol)
-	fmt.Fprintf(out, "Period:   %s to %s\n\n", from.Format(dateLayout), to.Format(dateLayout))
+	fmt.Fprintf(out, "Period:   %s to %s\n", from.Format(dateLayout), to.Format(dateLayout))
+	if r.FillModel != "" && r.FillModel != backtest.FillSameClose {
+		fmt.Fprintf(out, "Fills:    %s\n", r.FillModel)
+	}
+	if r.Execution != nil {
+		fmt.Fprintf(out, "Costs:    %s\n", r.Execution.Describe())
+	}
+	fmt.Fprintln(out)
 
 	s := r.Stats
 	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
@@ -162,5 +369,80 @@ func printBacktestResult(out io.Writer, r *backtest.Result, from, to time.Time)
 	fmt.Fprintf(w, "Total Return:\t%.2f%%\n", s.TotalReturn)
 	fmt.Fprintf(w, "Max Drawdown:\t%.2f%%\n", s.MaxDrawdown)
 	fmt.Fprintf(w, "Sharpe Ratio:\t%.2f\n", s.SharpeRatio)
+	printEquityStats(w, s)
+	if r.Execution != nil {
+		fmt.Fprintf(w, "Fees Paid:\t%.2f\n", totalFees(r.Trades))
+		fmt.Fprintf(w, "Rejected Orders:\t%d\n", len(r.Rejections))
+	}
+	if r.SkippedBars > 0 {
+		fmt.Fprintf(w, "Skipped Bars:\t%d (analysis errors)\n", r.SkippedBars)
+	}
 	w.Flush()
+	if r.Routing != nil {
+		printRouting(out, r.Routing)
+	}
+}
+
+// printRouting renders the router replay: what would have been notified, the
+// unfiltered comparison and each signal's decision.
+func printRouting(out io.Writer, rt *backtest.Routing) {
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "=== Router Replay ===")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Notified:\t%d of %d signals\n", rt.Notified, len(rt.Signals))
+	reasons := make([]string, 0, len(rt.SuppressedBy))
+	for reason := range rt.SuppressedBy {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		fmt.Fprintf(w, "Suppressed (%s):\t%d\n", reason, rt.SuppressedBy[reason])
+	}
+	u := rt.Unfiltered
+	fmt.Fprintf(w, "Every Signal Return:\t%.2f%% (%d trades, Sharpe %.2f, max DD %.2f%%)\n",
+		u.TotalReturn, u.TotalTrades, u.SharpeRatio, u.MaxDrawdown)
+	w.Flush()
+
+	if len(rt.Signals) == 0 {
+		return
+	}
+	fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Date\tAction\tConfidence\tDecision")
+	for _, s := range rt.Signals {
+		decision := "notified"
+		if !s.Notified {
+			decision = "suppressed: " + s.Reason
+		}
+		fmt.Fprintf(w, "%s\t%s\t%.2f\t%s\n", s.Signal.GeneratedAt.Format(dateLayout), s.Signal.Action, s.Signal.Confidence, decision)
+	}
+	w.Flush()
+}
+
+// printEquityStats renders the equity-curve and benchmark rows shared by the
+// single-symbol and portfolio reports.
+func printEquityStats(w io.Writer, s backtest.Stats) {
+	fmt.Fprintf(w, "Annualized Return:\t%.2f%%\n", s.AnnualizedReturn)
+	fmt.Fprintf(w, "Volatility:\t%.2f%%\n", s.Volatility)
+	fmt.Fprintf(w, "Sortino Ratio:\t%.2f\n", s.SortinoRatio)
+	fmt.Fprintf(w, "Calmar Ratio:\t%.2f\n", s.CalmarRatio)
+	fmt.Fprintf(w, "Exposure:\t%.2f%%\n", s.Exposure)
+	fmt.Fprintf(w, "Turnover:\t%.2fx/yr\n", s.Turnover)
+	fmt.Fprintf(w, "Avg Holding:\t%.1f days\n", s.AvgHoldingDays)
+	if s.Benchmark == "" {
+		fmt.Fprintf(w, "Benchmark:\tunavailable\n")
+		return
+	}
+	fmt.Fprintf(w, "Benchmark:\t%s (%.2f%%)\n", s.Benchmark, s.BenchmarkReturn)
+	fmt.Fprintf(w, "Alpha:\t%.2f%%\n", s.Alpha)
+	fmt.Fprintf(w, "Beta:\t%.2f\n", s.Beta)
+}
+
+// totalFees sums the fees of both legs of every trade.
+func totalFees(trades []backtest.Trade) float64 {
+	var fees float64
+	for _, t := range trades {
+		fees += t.Fees
+	}
+	return fees
 }
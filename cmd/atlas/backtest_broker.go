@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/newthinker/atlas/internal/app"
+	"github.com/newthinker/atlas/internal/backtest"
+	"github.com/newthinker/atlas/internal/broker"
+	"github.com/newthinker/atlas/internal/config"
+	"github.com/newthinker/atlas/internal/router"
+	"github.com/newthinker/atlas/internal/strategy"
+	"github.com/spf13/cobra"
+)
+
+var (
+	brokerBTStrategies     []string
+	brokerBTSymbols        []string
+	brokerBTFrom           string
+	brokerBTTo             string
+	brokerBTCapital        float64
+	brokerBTSizePct        float64
+	brokerBTMaxPositionPct float64
+	brokerBTMaxDailyLoss   float64
+	brokerBTMaxPositions   int
+	brokerBTRouter         bool
+)
+
+var backtestBrokerCmd = &cobra.Command{
+	Use:   "broker",
+	Short: "Backtest strategies through the live execution chain on a paper broker",
+	Long: "Replay one or more strategies over a set of symbols (default: the config " +
+		"watchlist) and send every signal through the same ExecutionManager, " +
+		"RiskChecker and PositionTracker live trading uses, against a paper broker " +
+		"running on bar time. Sizing and risk limits default to the config's " +
+		"broker.execution and broker.risk settings; the flags override them.",
+	Args: cobra.NoArgs,
+	RunE: runBrokerBacktest,
+}
+
+func init() {
+	f := backtestBrokerCmd.Flags()
+	f.StringSliceVar(&brokerBTStrategies, "strategies", nil, "Comma-separated strategy names (required)")
+	f.StringSliceVar(&brokerBTSymbols, "symbols", nil, "Comma-separated symbols (default: config watchlist)")
+	f.StringVar(&brokerBTFrom, "from", "", "Start date YYYY-MM-DD (required)")
+	f.StringVar(&brokerBTTo, "to", "", "End date YYYY-MM-DD (required)")
+	f.Float64Var(&brokerBTCapital, "capital", backtest.DefaultInitialCapital, "Initial paper account cash")
+	f.Float64Var(&brokerBTSizePct, "size-pct", 0, "Order size as percent of account value (default: broker.execution.default_size_pct)")
+	f.Float64Var(&brokerBTMaxPositionPct, "max-position-pct", 0, "Largest order as percent of account value (default: broker.risk.max_position_pct)")
+	f.Float64Var(&brokerBTMaxDailyLoss, "max-daily-loss-pct", 0, "Daily loss that halts trading, percent (default: broker.risk.max_daily_loss_pct)")
+	f.IntVar(&brokerBTMaxPositions, "max-positions", 0, "Maximum open positions (default: broker.risk.max_open_positions)")
+	f.BoolVar(&brokerBTRouter, "router", false, "Replay the configured router filters on bar time and execute only signals that would have been notified")
+
+	backtestBrokerCmd.MarkFlagRequired("strategies")
+	backtestBrokerCmd.MarkFlagRequired("from")
+	backtestBrokerCmd.MarkFlagRequired("to")
+
+	backtestCmd.AddCommand(backtestBrokerCmd)
+}
+
+// brokerBTParams holds the parsed CLI inputs for a broker backtest. Zero
+// sizing and risk values keep the config settings.
+type brokerBTParams struct {
+	Strategies     []string
+	Symbols        []string
+	From, To       string
+	Capital        float64
+	SizePct        float64
+	MaxPositionPct float64
+	MaxDailyLoss   float64
+	MaxPositions   int
+}
+
+// brokerBTDeps holds the injectable dependencies of the broker backtest
+// command.
+type brokerBTDeps struct {
+	provider   backtest.OHLCVProvider
+	strategies *strategy.Engine
+	watchlist  []config.WatchlistItem
+	broker     config.BrokerConfig // execution and risk settings to start from
+	settings   backtestSettings
+	out        io.Writer
+}
+
+func runBrokerBacktest(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfigOrDefaults()
+	if err != nil {
+		return err
+	}
+	provider := registryProvider{reg: newCollectorRegistry(cfg)}
+	funds, closeFunds, err := fundamentalFeed(backtestFunds, cfg, provider)
+	if err != nil {
+		return err
+	}
+	defer closeFunds()
+	settings := backtestSettings{benchmark: backtestBench, fundamentals: funds}
+	if brokerBTRouter {
+		routerCfg := app.RouterConfig(cfg)
+		settings.filter = func() backtest.SignalFilter { return router.New(routerCfg, nil, nil) }
+	}
+	if backtestCrisis {
+		gate, closeGate, err := newReplayCrisisGate(cfg.CrisisGate)
+		if err != nil {
+			return err
+		}
+		defer closeGate()
+		settings.gate = gate
+	}
+	strategies, closeStrategies := withConfiguredStrategies(newBacktestEngine(), cfg, os.Stderr)
+	defer closeStrategies()
+	deps := brokerBTDeps{
+		provider:   provider,
+		strategies: strategies,
+		watchlist:  cfg.Watchlist,
+		broker:     cfg.Broker,
+		settings:   settings,
+		out:        os.Stdout,
+	}
+	return executeBrokerBacktest(deps, brokerBTParams{
+		Strategies:     brokerBTStrategies,
+		Symbols:        brokerBTSymbols,
+		From:           brokerBTFrom,
+		To:             brokerBTTo,
+		Capital:        brokerBTCapital,
+		SizePct:        brokerBTSizePct,
+		MaxPositionPct: brokerBTMaxPositionPct,
+		MaxDailyLoss:   brokerBTMaxDailyLoss,
+		MaxPositions:   brokerBTMaxPositions,
+	})
+}
+
+// executeBrokerBacktest validates inputs, resolves the asset set, replays the
+// execution chain and renders the result.
+func executeBrokerBacktest(deps brokerBTDeps, p brokerBTParams) error {
+	from, err := parseBacktestDate("from", p.From)
+	if err != nil {
+		return err
+	}
+	to, err := parseBacktestDate("to", p.To)
+	if err != nil {
+		return err
+	}
+	if to.Before(from) {
+		return fmt.Errorf("end date must be after start date")
+	}
+
+	strats := make([]strategy.Strategy, 0, len(p.Strategies))
+	for _, name := range p.Strategies {
+		s, ok := deps.strategies.Get(name)
+		if !ok {
+			names := deps.strategies.GetStrategyNames()
+			slices.Sort(names)
+			return fmt.Errorf("unknown strategy %q (available: %s)", name, strings.Join(names, ", "))
+		}
+		strats = append(strats, s)
+	}
+
+	assets, err := resolvePortfolioAssets(p.Symbols, deps.watchlist, p.Strategies)
+	if err != nil {
+		return err
+	}
+
+	exec, risk := brokerBTLimits(deps.broker, p)
+	if exec.DefaultSizePct <= 0 || exec.DefaultSizePct > 100 {
+		return fmt.Errorf("order size percent must be in (0, 100], got %g", exec.DefaultSizePct)
+	}
+
+	bt := backtest.NewBrokerBacktester(deps.provider, backtest.BrokerConfig{
+		InitialCapital: p.Capital,
+		Execution:      exec,
+		Risk:           risk,
+		Filter:         deps.settings.filter,
+		Gate:           deps.settings.gate,
+		Benchmark:      resolveBenchmark(deps.settings.benchmark, assets[0].Symbol),
+		Fundamentals:   deps.settings.fundamentals,
+	})
+	result, err := bt.Run(context.Background(), strats, assets, from, to)
+	if err != nil {
+		return fmt.Errorf("running broker backtest: %w", err)
+	}
+
+	printBrokerResult(deps.out, result)
+	return nil
+}
+
+// brokerBTLimits starts from the configured execution and risk settings, as
+// buildExecution wires them for live trading, and applies the flag overrides.
+func brokerBTLimits(cfg config.BrokerConfig, p brokerBTParams) (broker.ExecutionConfig, broker.RiskConfig) {
+	exec := broker.ExecutionConfig{
+		Mode:           broker.ExecutionMode(cfg.Execution.Mode),
+		BatchTime:      cfg.Execution.BatchTime,
+		DefaultSizePct: cfg.Execution.DefaultSizePct,
+	}
+	risk := broker.RiskConfig{
+		MaxPositionPct:   cfg.Risk.MaxPositionPct,
+		MaxDailyLossPct:  cfg.Risk.MaxDailyLossPct,
+		MaxOpenPositions: cfg.Risk.MaxOpenPositions,
+	}
+	if p.SizePct > 0 {
+		exec.DefaultSizePct = p.SizePct
+	}
+	if p.MaxPositionPct > 0 {
+		risk.MaxPositionPct = p.MaxPositionPct
+	}
+	if p.MaxDailyLoss > 0 {
+		risk.MaxDailyLossPct = p.MaxDailyLoss
+	}
+	if p.MaxPositions > 0 {
+		risk.MaxOpenPositions = p.MaxPositions
+	}
+	return exec, risk
+}
+
+// printBrokerResult renders the summary, order outcomes and the orders the
+// execution chain turned down.
+func printBrokerResult(out io.Writer, r *backtest.BrokerResult) {
+	fmt.Fprintln(out, "=== ATLAS Broker Backtest ===")
+	fmt.Fprintf(out, "Strategies: %s\n", strings.Join(r.Strategies, ", "))
+	fmt.Fprintf(out, "Symbols:    %s\n", strings.Join(r.Symbols, ", "))
+	if len(r.MissingSymbols) > 0 {
+		fmt.Fprintf(out, "No data:    %s\n", strings.Join(r.MissingSymbols, ", "))
+	}
+	fmt.Fprintf(out, "Period:     %s to %s\n", r.StartDate.Format(dateLayout), r.EndDate.Format(dateLayout))
+	fmt.Fprintf(out, "Execution:  %s mode, %.2f%% per order\n", r.Execution.Mode, r.Execution.DefaultSizePct)
+	fmt.Fprintf(out, "Risk:       max position %.2f%%, max daily loss %.2f%%, max %d positions\n",
+		r.Risk.MaxPositionPct, r.Risk.MaxDailyLossPct, r.Risk.MaxOpenPositions)
+	fmt.Fprintln(out)
+
+	s := r.Stats
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Initial Capital:\t%.2f\n", r.InitialCapital)
+	fmt.Fprintf(w, "Final Equity:\t%.2f\n", r.FinalEquity)
+	fmt.Fprintf(w, "Signals:\t%d\n", len(r.Signals))
+	fmt.Fprintf(w, "Orders:\t%d (%d filled, %d rejected, %d failed)\n", len(r.Orders), r.Filled, r.Rejected, r.Failed)
+	fmt.Fprintf(w, "Trades:\t%d\n", len(r.Trades))
+	fmt.Fprintf(w, "Open Positions:\t%d\n", len(r.Positions))
+	fmt.Fprintf(w, "Win Rate:\t%.2f%%\n", s.WinRate)
+	fmt.Fprintf(w, "Total Return:\t%.2f%%\n", s.TotalReturn)
+	fmt.Fprintf(w, "Max Drawdown:\t%.2f%%\n", s.MaxDrawdown)
+	fmt.Fprintf(w, "Sharpe Ratio:\t%.2f\n", s.SharpeRatio)
+	printEquityStats(w, s)
+	w.Flush()
+
+	if len(r.RejectedBy) > 0 {
+		fmt.Fprintln(out, "\nOrders not filled:")
+		reasons := make([]string, 0, len(r.RejectedBy))
+		for reason := range r.RejectedBy {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+		w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, reason := range reasons {
+			fmt.Fprintf(w, "%s:\t%d\n", reason, r.RejectedBy[reason])
+		}
+		w.Flush()
+	}
+
+	if len(r.Orders) > 0 {
+		fmt.Fprintln(out)
+		w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "Date\tSymbol\tAction\tStatus\tQty\tPrice\tReason")
+		for _, o := range r.Orders {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%.2f\t%s\n", o.Time.Format(dateLayout), o.Signal.Symbol,
+				o.Signal.Action, o.Status, o.Quantity, o.Signal.Price, o.Reason)
+		}
+		w.Flush()
+	}
+
+	if r.Routing != nil {
+		printRouting(out, r.Routing)
+	}
+}
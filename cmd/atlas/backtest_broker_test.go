@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/newthinker/atlas/internal/config"
+)
+
+func TestExecuteBrokerBacktest_ReportsRiskRejections(t *testing.T) {
+	var buf bytes.Buffer
+	deps := brokerBTDeps{
+		provider:   &stubProvider{data: sampleOHLCV()},
+		strategies: engineWith("mock"),
+		broker:     config.Defaults().Broker,
+		settings:   backtestSettings{benchmark: "none"},
+		out:        &buf,
+	}
+	err := executeBrokerBacktest(deps, brokerBTParams{
+		Strategies:   []string{"mock"},
+		Symbols:      []string{"AAPL", "MSFT"},
+		From:         "2026-01-01",
+		To:           "2026-01-10",
+		Capital:      10000,
+		SizePct:      10,
+		MaxPositions: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"confirm mode, 10.00% per order",
+		"max 1 positions",
+		"Orders not filled:",
+		"max open positions reached:",
+		"MSFT",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q.\n--- output ---\n%s", want, out)
+		}
+	}
+}
+
+func TestBrokerBTLimits_FlagsOverrideConfig(t *testing.T) {
+	cfg := config.Defaults().Broker
+	exec, risk := brokerBTLimits(cfg, brokerBTParams{MaxPositionPct: 25})
+	if exec.DefaultSizePct != cfg.Execution.DefaultSizePct || risk.MaxOpenPositions != cfg.Risk.MaxOpenPositions {
+		t.Errorf("unset flags should keep the config: %+v %+v", exec, risk)
+	}
+	if risk.MaxPositionPct != 25 {
+		t.Errorf("MaxPositionPct = %v, want 25", risk.MaxPositionPct)
+	}
+}
+
+func TestExecuteBrokerBacktest_InvalidSize(t *testing.T) {
+	deps := brokerBTDeps{provider: &stubProvider{data: sampleOHLCV()}, strategies: engineWith("mock"), out: &bytes.Buffer{}}
+	err := executeBrokerBacktest(deps, brokerBTParams{
+		Strategies: []string{"mock"}, Symbols: []string{"AAPL"}, From: "2026-01-01", To: "2026-01-10",
+	})
+	if err == nil || !strings.Contains(err.Error(), "order size") {
+		t.Fatalf("expected an order size error without a configured size, got %v", err)
+	}
+}
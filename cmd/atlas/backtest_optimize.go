@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/newthinker/atlas/internal/backtest"
+	"github.com/newthinker/atlas/internal/storage/backtestrun"
+	"github.com/newthinker/atlas/internal/strategy"
+	"github.com/spf13/cobra"
+)
+
+var (
+	optimizeSymbol    string
+	optimizeFrom      string
+	optimizeTo        string
+	optimizeParams    []string
+	optimizeMethod    string
+	optimizeSamples   int
+	optimizeSeed      int64
+	optimizeObjective string
+	optimizeMaxDD     float64
+	optimizeFolds     int
+	optimizeInSample  float64
+	optimizeTop       int
+	optimizeNoSave    bool
+)
+
+var backtestOptimizeCmd = &cobra.Command{
+	Use:   "optimize [strategy]",
+	Short: "Search a strategy's parameters on historical data",
+	Long: "Backtest a strategy over a grid or random sample of its parameters, rank " +
+		"the runs by an objective (sharpe, cagr or return_dd) and optionally check " +
+		"the winners walk-forward on out-of-sample windows. Results are saved in " +
+		"the storage.backtests.path database for later comparison.",
+	Example: "  atlas backtest optimize ma_crossover --symbol AAPL --from 2018-01-01 --to 2024-12-31 \\\n" +
+		"    --param fast_period=5:30:5 --param slow_period=50:200:50 --folds 4",
+	Args: cobra.ExactArgs(1),
+	RunE: runOptimizeBacktest,
+}
+
+func init() {
+	f := backtestOptimizeCmd.Flags()
+	f.StringVar(&optimizeSymbol, "symbol", "", "Symbol to optimize on (required)")
+	f.StringVar(&optimizeFrom, "from", "", "Start date YYYY-MM-DD (required)")
+	f.StringVar(&optimizeTo, "to", "", "End date YYYY-MM-DD (required)")
+	f.StringArrayVar(&optimizeParams, "param", nil, "Param range name=min:max[:step] or name=v1,v2,... (repeatable, required)")
+	f.StringVar(&optimizeMethod, "method", string(backtest.SearchGrid), "Search method: grid or random")
+	f.IntVar(&optimizeSamples, "samples", backtest.DefaultSamples, "Parameter sets drawn by random search")
+	f.Int64Var(&optimizeSeed, "seed", 1, "Random search seed")
+	f.StringVar(&optimizeObjective, "objective", string(backtest.ObjectiveSharpe), "Ranking objective: sharpe, cagr or return_dd")
+	f.Float64Var(&optimizeMaxDD, "max-drawdown", backtest.DefaultMaxDrawdown, "Max drawdown percent allowed by the return_dd objective")
+	f.IntVar(&optimizeFolds, "folds", 0, "Walk-forward folds (0 = no walk-forward)")
+	f.Float64Var(&optimizeInSample, "in-sample", 0.7, "In-sample fraction of each walk-forward fold")
+	f.IntVar(&optimizeTop, "top", 10, "Ranked trials to print")
+	f.BoolVar(&optimizeNoSave, "no-save", false, "Do not save the result")
+
+	backtestOptimizeCmd.MarkFlagRequired("symbol")
+	backtestOptimizeCmd.MarkFlagRequired("from")
+	backtestOptimizeCmd.MarkFlagRequired("to")
+	backtestOptimizeCmd.MarkFlagRequired("param")
+
+	backtestCmd.AddCommand(backtestOptimizeCmd)
+}
+
+// optimizeParamsIn holds the parsed CLI inputs for an optimization.
+type optimizeParamsIn struct {
+	Strategy    string
+	Symbol      string
+	From, To    string
+	Ranges      []string
+	Method      string
+	Samples     int
+	Seed        int64
+	Objective   string
+	MaxDrawdown float64
+	Folds       int
+	InSample    float64
+	Top         int
+}
+
+// optimizeDeps holds the injectable dependencies of the optimize command.
+type optimizeDeps struct {
+	provider   backtest.OHLCVProvider
+	strategies *strategy.Engine
+	settings   backtestSettings
+	store      *backtestrun.SQLiteStore // nil = do not save
+	out        io.Writer
+}
+
+func runOptimizeBacktest(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfigOrDefaults()
+	if err != nil {
+		return err
+	}
+	settings, err := backtestSettingsFromFlags()
+	if err != nil {
+		return err
+	}
+	provider := registryProvider{reg: newCollectorRegistry(cfg)}
+	funds, closeFunds, err := fundamentalFeed(backtestFunds, cfg, provider)
+	if err != nil {
+		return err
+	}
+	defer closeFunds()
+	settings.fundamentals = funds
+
+	strategies, closeStrategies := withConfiguredStrategies(newBacktestEngine(), cfg, os.Stderr)
+	defer closeStrategies()
+	deps := optimizeDeps{
+		provider:   provider,
+		strategies: strategies,
+		settings:   settings,
+		out:        os.Stdout,
+	}
+	if !optimizeNoSave {
+		store, err := backtestrun.NewSQLiteStore(cfg.Storage.Backtests.Path)
+		if err != nil {
+			return fmt.Errorf("opening optimization store: %w", err)
+		}
+		defer store.Close()
+		deps.store = store
+	}
+	return executeOptimize(deps, optimizeParamsIn{
+		Strategy:    args[0],
+		Symbol:      optimizeSymbol,
+		From:        optimizeFrom,
+		To:          optimizeTo,
+		Ranges:      optimizeParams,
+		Method:      optimizeMethod,
+		Samples:     optimizeSamples,
+		Seed:        optimizeSeed,
+		Objective:   optimizeObjective,
+		MaxDrawdown: optimizeMaxDD,
+		Folds:       optimizeFolds,
+		InSample:    optimizeInSample,
+		Top:         optimizeTop,
+	})
+}
+
+// executeOptimize validates inputs, runs the search and renders the ranking.
+func executeOptimize(deps optimizeDeps, p optimizeParamsIn) error {
+	from, err := parseBacktestDate("from", p.From)
+	if err != nil {
+		return err
+	}
+	to, err := parseBacktestDate("to", p.To)
+	if err != nil {
+		return err
+	}
+	if !to.After(from) {
+		return fmt.Errorf("end date must be after start date")
+	}
+	method, err := backtest.ParseSearchMethod(p.Method)
+	if err != nil {
+		return err
+	}
+	objective, err := backtest.ParseObjective(p.Objective)
+	if err != nil {
+		return err
+	}
+	ranges := make([]backtest.ParamRange, 0, len(p.Ranges))
+	for _, spec := range p.Ranges {
+		r, err := parseParamRange(spec)
+		if err != nil {
+			return err
+		}
+		if method == backtest.SearchGrid && len(r.Values) == 0 && r.Step <= 0 && r.Max > r.Min {
+			return fmt.Errorf("--param %s: grid search needs a step (name=min:max:step)", r.Name)
+		}
+		ranges = append(ranges, r)
+	}
+
+	strat, ok := deps.strategies.Get(p.Strategy)
+	if !ok {
+		names := deps.strategies.GetStrategyNames()
+		slices.Sort(names)
+		return fmt.Errorf("unknown strategy %q (available: %s)", p.Strategy, strings.Join(names, ", "))
+	}
+
+	data, err := deps.provider.FetchHistory(p.Symbol, from, to, "1d")
+	if err != nil {
+		return fmt.Errorf("fetching history for %s: %w", p.Symbol, err)
+	}
+	if len(data) == 0 {
+		fmt.Fprintf(deps.out, "No historical data for %s between %s and %s.\n",
+			p.Symbol, from.Format(dateLayout), to.Format(dateLayout))
+		return nil
+	}
+
+	bt := backtest.New(prefetchedProvider{symbol: p.Symbol, data: data, OHLCVProvider: deps.provider},
+		backtest.WithExecutionModels(deps.settings.models),
+		backtest.WithFillModel(deps.settings.fill),
+		backtest.WithBenchmark(func(s string) string { return resolveBenchmark(deps.settings.benchmark, s) }),
+		backtest.WithFundamentals(deps.settings.fundamentals),
+	)
+	req := backtest.OptimizeRequest{
+		Symbol:      p.Symbol,
+		Start:       from,
+		End:         to,
+		Params:      ranges,
+		Method:      method,
+		Samples:     p.Samples,
+		Seed:        p.Seed,
+		Objective:   objective,
+		MaxDrawdown: p.MaxDrawdown,
+	}
+	if p.Folds > 0 {
+		req.WalkForward = &backtest.WalkForward{Folds: p.Folds, InSample: p.InSample}
+	}
+	result, err := bt.Optimize(context.Background(), strat, req, nil)
+	if err != nil {
+		return fmt.Errorf("running optimization: %w", err)
+	}
+	if deps.store != nil {
+		if err := deps.store.SaveOptimization(context.Background(), result); err != nil {
+			return fmt.Errorf("saving optimization: %w", err)
+		}
+	}
+
+	printOptimizeResult(deps.out, result, p.Top)
+	return nil
+}
+
+// parseParamRange parses a --param spec: name=min:max[:step] for a numeric
+// range (integer when every bound is written as an integer) or
+// name=v1,v2,... for explicit values.
+func parseParamRange(spec string) (backtest.ParamRange, error) {
+	name, value, ok := strings.Cut(spec, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || value == "" {
+		return backtest.ParamRange{}, fmt.Errorf("invalid --param %q (want name=min:max[:step] or name=v1,v2)", spec)
+	}
+	r := backtest.ParamRange{Name: name}
+
+	if !strings.Contains(value, ":") {
+		for _, v := range strings.Split(value, ",") {
+			r.Values = append(r.Values, parseParamValue(strings.TrimSpace(v)))
+		}
+		return r, nil
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return backtest.ParamRange{}, fmt.Errorf("invalid --param %q (want name=min:max[:step])", spec)
+	}
+	nums := make([]float64, len(parts))
+	r.Integer = true
+	for i, s := range parts {
+		n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return backtest.ParamRange{}, fmt.Errorf("invalid --param %q: %w", spec, err)
+		}
+		nums[i] = n
+		if _, err := strconv.Atoi(strings.TrimSpace(s)); err != nil {
+			r.Integer = false
+		}
+	}
+	r.Min, r.Max = nums[0], nums[1]
+	if len(nums) == 3 {
+		r.Step = nums[2]
+	}
+	if r.Max < r.Min || r.Step < 0 {
+		return backtest.ParamRange{}, fmt.Errorf("invalid --param %q: need min <= max and a positive step", spec)
+	}
+	return r, nil
+}
+
+// parseParamValue types one explicit value as a config file would: int,
+// float, bool, then string.
+func parseParamValue(s string) any {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	if b, err := strconv.ParseBool(s); err == nil {
+		return b
+	}
+	return s
+}
+
+// formatParams renders params as sorted name=value pairs.
+func formatParams(params map[string]any) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%v", k, params[k])
+	}
+	return strings.Join(parts, " ")
+}
+
+// printOptimizeResult renders the top trials and the walk-forward folds.
+func printOptimizeResult(out io.Writer, r *backtest.OptimizeResult, top int) {
+	fmt.Fprintln(out, "=== ATLAS Optimize ===")
+	fmt.Fprintf(out, "Strategy:  %s\n", r.Strategy)
+	fmt.Fprintf(out, "Symbol:    %s\n", r.Symbol)
+	fmt.Fprintf(out, "Period:    %s to %s\n", r.Start.Format(dateLayout), r.End.Format(dateLayout))
+	objective := string(r.Objective)
+	if r.Objective == backtest.ObjectiveReturnDD {
+		objective = fmt.Sprintf("%s (max drawdown %.1f%%)", objective, r.MaxDrawdown)
+	}
+	fmt.Fprintf(out, "Search:    %s, %d trials\n", r.Method, len(r.Trials))
+	fmt.Fprintf(out, "Objective: %s\n", objective)
+	if r.ID != "" {
+		fmt.Fprintf(out, "Saved as:  %s\n", r.ID)
+	}
+	fmt.Fprintln(out)
+
+	if r.Best == nil {
+		fmt.Fprintln(out, "No trial met the objective.")
+	}
+	if top <= 0 || top > len(r.Trials) {
+		top = len(r.Trials)
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Rank\tScore\tReturn\tCAGR\tMax DD\tSharpe\tTrades\tParams")
+	for i, t := range r.Trials[:top] {
+		if t.Error != "" {
+			fmt.Fprintf(w, "%d\t-\t-\t-\t-\t-\t-\t%s (%s)\n", i+1, formatParams(t.Params), t.Error)
+			continue
+		}
+		score := fmt.Sprintf("%.2f", t.Score)
+		if !t.Eligible {
+			score += "*"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%.2f%%\t%.2f%%\t%.2f%%\t%.2f\t%d\t%s\n", i+1, score,
+			t.Stats.TotalReturn, t.Stats.AnnualizedReturn, t.Stats.MaxDrawdown, t.Stats.SharpeRatio, t.Trades, formatParams(t.Params))
+	}
+	w.Flush()
+	if r.Objective == backtest.ObjectiveReturnDD {
+		fmt.Fprintln(out, "* exceeds the drawdown limit")
+	}
+
+	if len(r.Folds) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "\nWalk-forward (%d folds):\n", len(r.Folds))
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Fold\tIn-sample\tOut-of-sample\tIS Score\tOOS Score\tOOS Return\tParams")
+	for i, f := range r.Folds {
+		fmt.Fprintf(w, "%d\t%s..%s\t%s..%s\t%.2f\t%.2f\t%.2f%%\t%s\n", i+1,
+			f.InSampleStart.Format(dateLayout), f.InSampleEnd.Format(dateLayout),
+			f.OutSampleStart.Format(dateLayout), f.OutSampleEnd.Format(dateLayout),
+			f.InSample.Score, f.OutOfSample.Score, f.OutOfSample.Stats.TotalReturn, formatParams(f.InSample.Params))
+	}
+	w.Flush()
+	if s := r.WalkForward; s != nil {
+		fmt.Fprintf(out, "Mean score: in-sample %.2f, out-of-sample %.2f (efficiency %.2f)\n",
+			s.InSampleScore, s.OutOfSampleScore, s.Efficiency)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/newthinker/atlas/internal/storage/backtestrun"
+	"github.com/newthinker/atlas/internal/strategy"
+	"github.com/newthinker/atlas/internal/strategy/ma_crossover"
+)
+
+func TestParseParamRange(t *testing.T) {
+	r, err := parseParamRange("fast_period=5:30:5")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if r.Name != "fast_period" || r.Min != 5 || r.Max != 30 || r.Step != 5 || !r.Integer {
+		t.Errorf("range = %+v", r)
+	}
+	if r, _ := parseParamRange("buy_pe=10:15.5:0.5"); r.Integer {
+		t.Errorf("fractional bounds parsed as integer: %+v", r)
+	}
+	r, err = parseParamRange("mode=10,12.5,fast")
+	if err != nil || len(r.Values) != 3 || r.Values[0] != 10 || r.Values[1] != 12.5 || r.Values[2] != "fast" {
+		t.Errorf("values = %+v, %v", r.Values, err)
+	}
+	for _, bad := range []string{"fast_period", "=1:2", "x=5:1:1", "x=1:2:3:4", "x=a:b"} {
+		if _, err := parseParamRange(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
+
+func TestExecuteOptimize_RanksAndSaves(t *testing.T) {
+	engine := strategy.NewEngine()
+	engine.Register(ma_crossover.New(2, 3))
+	store, err := backtestrun.NewSQLiteStore(filepath.Join(t.TempDir(), "backtests.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	var buf bytes.Buffer
+	deps := optimizeDeps{provider: &stubProvider{data: sampleOHLCV()}, strategies: engine, store: store, out: &buf}
+	err = executeOptimize(deps, optimizeParamsIn{
+		Strategy: "ma_crossover", Symbol: "AAPL", From: "2026-01-01", To: "2026-01-05",
+		Ranges: []string{"fast_period=1:2:1", "slow_period=2,3"}, Objective: "cagr", Folds: 2, InSample: 0.6,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"=== ATLAS Optimize ===", "4 trials", "fast_period=1 slow_period=2", "Walk-forward (2 folds)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q.\n--- output ---\n%s", want, out)
+		}
+	}
+	// fast_period=2 slow_period=2 breaks the strategy's 0 < fast < slow rule.
+	if !strings.Contains(out, "fast_period=2 slow_period=2 (ma_crossover: periods") {
+		t.Errorf("rejected set not reported.\n--- output ---\n%s", out)
+	}
+
+	saved, err := store.ListOptimizations(context.Background())
+	if err != nil || len(saved) != 1 || saved[0].Strategy != "ma_crossover" || saved[0].Trials != 4 {
+		t.Errorf("saved = %+v, %v", saved, err)
+	}
+}
+
+func TestExecuteOptimize_InvalidInput(t *testing.T) {
+	cases := map[string]optimizeParamsIn{
+		"grid without step": {Ranges: []string{"fast_period=5:30"}},
+		"bad objective":     {Ranges: []string{"fast_period=5,10"}, Objective: "luck"},
+		"unknown strategy":  {Ranges: []string{"fast_period=5,10"}, Strategy: "ghost"},
+	}
+	for name, p := range cases {
+		prov := &stubProvider{data: sampleOHLCV()}
+		if p.Strategy == "" {
+			p.Strategy = "mock"
+		}
+		p.Symbol, p.From, p.To = "AAPL", "2026-01-01", "2026-01-05"
+		err := executeOptimize(optimizeDeps{provider: prov, strategies: engineWith("mock"), out: &bytes.Buffer{}}, p)
+		if err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+		if prov.calls != 0 {
+			t.Errorf("%s: provider should not be called", name)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/newthinker/atlas/internal/backtest"
+	"github.com/newthinker/atlas/internal/config"
+	"github.com/newthinker/atlas/internal/strategy"
+	"github.com/spf13/cobra"
+)
+
+var (
+	portfolioStrategies   []string
+	portfolioSymbols      []string
+	portfolioFrom         string
+	portfolioTo           string
+	portfolioCapital      float64
+	portfolioSizing       string
+	portfolioSizePct      float64
+	portfolioMaxPositions int
+)
+
+var backtestPortfolioCmd = &cobra.Command{
+	Use:   "portfolio",
+	Short: "Backtest strategies over many symbols with one shared cash pool",
+	Long: "Run one or more strategies over a set of symbols (default: the config " +
+		"watchlist, honouring each item's strategy binding) against a single cash " +
+		"pool with concurrent positions, and report the combined equity curve and " +
+		"per-symbol contribution.",
+	Args: cobra.NoArgs,
+	RunE: runPortfolioBacktest,
+}
+
+func init() {
+	f := backtestPortfolioCmd.Flags()
+	f.StringSliceVar(&portfolioStrategies, "strategies", nil, "Comma-separated strategy names (required)")
+	f.StringSliceVar(&portfolioSymbols, "symbols", nil, "Comma-separated symbols (default: config watchlist)")
+	f.StringVar(&portfolioFrom, "from", "", "Start date YYYY-MM-DD (required)")
+	f.StringVar(&portfolioTo, "to", "", "End date YYYY-MM-DD (required)")
+	f.Float64Var(&portfolioCapital, "capital", backtest.DefaultInitialCapital, "Initial capital")
+	f.StringVar(&portfolioSizing, "sizing", "equal", "Position sizing: equal, fixed or confidence")
+	f.Float64Var(&portfolioSizePct, "size-pct", 10, "Percent of equity per position for fixed/confidence sizing")
+	f.IntVar(&portfolioMaxPositions, "max-positions", 0, "Maximum concurrent positions (0 = no cap)")
+
+	backtestPortfolioCmd.MarkFlagRequired("strategies")
+	backtestPortfolioCmd.MarkFlagRequired("from")
+	backtestPortfolioCmd.MarkFlagRequired("to")
+
+	backtestCmd.AddCommand(backtestPortfolioCmd)
+}
+
+// portfolioParams holds the parsed CLI inputs for a portfolio backtest.
+type portfolioParams struct {
+	Strategies   []string
+	Symbols      []string
+	From, To     string
+	Capital      float64
+	Sizing       string
+	SizePct      float64
+	MaxPositions int
+}
+
+// portfolioDeps holds the injectable dependencies of the portfolio command.
+type portfolioDeps struct {
+	provider   backtest.OHLCVProvider
+	strategies *strategy.Engine
+	watchlist  []config.WatchlistItem // used when no --symbols are given
+	settings   backtestSettings
+	out        io.Writer
+}
+
+func runPortfolioBacktest(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfigOrDefaults()
+	if err != nil {
+		return err
+	}
+	settings, err := backtestSettingsFromFlags()
+	if err != nil {
+		return err
+	}
+	provider := registryProvider{reg: newCollectorRegistry(cfg)}
+	funds, closeFunds, err := fundamentalFeed(backtestFunds, cfg, provider)
+	if err != nil {
+		return err
+	}
+	defer closeFunds()
+	settings.fundamentals = funds
+	strategies, closeStrategies := withConfiguredStrategies(newBacktestEngine(), cfg, os.Stderr)
+	defer closeStrategies()
+	deps := portfolioDeps{
+		provider:   provider,
+		strategies: strategies,
+		watchlist:  cfg.Watchlist,
+		settings:   settings,
+		out:        os.Stdout,
+	}
+	return executePortfolioBacktest(deps, portfolioParams{
+		Strategies:   portfolioStrategies,
+		Symbols:      portfolioSymbols,
+		From:         portfolioFrom,
+		To:           portfolioTo,
+		Capital:      portfolioCapital,
+		Sizing:       portfolioSizing,
+		SizePct:      portfolioSizePct,
+		MaxPositions: portfolioMaxPositions,
+	})
+}
+
+// executePortfolioBacktest validates inputs, resolves the asset set, runs the
+// portfolio engine and renders the result.
+func executePortfolioBacktest(deps portfolioDeps, p portfolioParams) error {
+	from, err := parseBacktestDate("from", p.From)
+	if err != nil {
+		return err
+	}
+	to, err := parseBacktestDate("to", p.To)
+	if err != nil {
+		return err
+	}
+	if to.Before(from) {
+		return fmt.Errorf("end date must be after start date")
+	}
+
+	strats := make([]strategy.Strategy, 0, len(p.Strategies))
+	for _, name := range p.Strategies {
+		s, ok := deps.strategies.Get(name)
+		if !ok {
+			names := deps.strategies.GetStrategyNames()
+			slices.Sort(names)
+			return fmt.Errorf("unknown strategy %q (available: %s)", name, strings.Join(names, ", "))
+		}
+		strats = append(strats, s)
+	}
+
+	assets, err := resolvePortfolioAssets(p.Symbols, deps.watchlist, p.Strategies)
+	if err != nil {
+		return err
+	}
+
+	// Equal weight splits into one slot per allowed position when capped.
+	sizeParam := p.SizePct
+	if p.Sizing == "equal" || p.Sizing == "" {
+		sizeParam = float64(p.MaxPositions)
+	}
+	sizing, err := backtest.NewSizingPolicy(p.Sizing, sizeParam)
+	if err != nil {
+		return err
+	}
+
+	bt := backtest.NewPortfolio(deps.provider, backtest.PortfolioConfig{
+		InitialCapital: p.Capital,
+		Sizing:         sizing,
+		MaxPositions:   p.MaxPositions,
+		Models:         deps.settings.models,
+		Fill:           deps.settings.fill,
+		Benchmark:      resolveBenchmark(deps.settings.benchmark, assets[0].Symbol),
+		Fundamentals:   deps.settings.fundamentals,
+	})
+	result, err := bt.Run(context.Background(), strats, assets, from, to)
+	if err != nil {
+		return fmt.Errorf("running portfolio backtest: %w", err)
+	}
+
+	printPortfolioResult(deps.out, result)
+	return nil
+}
+
+// resolvePortfolioAssets picks the assets to backtest. Explicit symbols run
+// every requested strategy. Otherwise the watchlist is used: an item bound to
+// strategies keeps only the requested ones (and is dropped when none remain),
+// an unbound item runs them all.
+func resolvePortfolioAssets(symbols []string, watchlist []config.WatchlistItem, strategies []string) ([]backtest.Asset, error) {
+	if len(symbols) > 0 {
+		assets := make([]backtest.Asset, 0, len(symbols))
+		for _, s := range symbols {
+			assets = append(assets, backtest.Asset{Symbol: s})
+		}
+		return assets, nil
+	}
+
+	var assets []backtest.Asset
+	for _, a := range backtest.AssetsFromWatchlist(watchlist) {
+		if len(a.Strategies) > 0 {
+			var keep []string
+			for _, name := range a.Strategies {
+				if slices.Contains(strategies, name) {
+					keep = append(keep, name)
+				}
+			}
+			if len(keep) == 0 {
+				continue
+			}
+			a.Strategies = keep
+		}
+		assets = append(assets, a)
+	}
+	if len(assets) == 0 {
+		return nil, fmt.Errorf("no watchlist symbols bound to %s and no --symbols provided", strings.Join(strategies, ", "))
+	}
+	return assets, nil
+}
+
+// printPortfolioResult renders the portfolio summary and per-symbol
+// contribution tables.
+func printPortfolioResult(out io.Writer, r *backtest.PortfolioResult) {
+	fmt.Fprintln(out, "=== ATLAS Portfolio Backtest ===")
+	fmt.Fprintf(out, "Strategies: %s\n", strings.Join(r.Strategies, ", "))
+	fmt.Fprintf(out, "Symbols:    %s\n", strings.Join(r.Symbols, ", "))
+	if len(r.MissingSymbols) > 0 {
+		fmt.Fprintf(out, "No data:    %s\n", strings.Join(r.MissingSymbols, ", "))
+	}
+	fmt.Fprintf(out, "Period:     %s to %s\n", r.StartDate.Format(dateLayout), r.EndDate.Format(dateLayout))
+	if r.FillModel != "" && r.FillModel != backtest.FillSameClose {
+		fmt.Fprintf(out, "Fills:      %s\n", r.FillModel)
+	}
+	fmt.Fprintln(out)
+
+	s := r.Stats
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Initial Capital:\t%.2f\n", r.InitialCapital)
+	fmt.Fprintf(w, "Final Equity:\t%.2f\n", r.FinalEquity)
+	fmt.Fprintf(w, "Signals:\t%d\n", len(r.Signals))
+	fmt.Fprintf(w, "Trades:\t%d\n", len(r.Trades))
+	fmt.Fprintf(w, "Win Rate:\t%.2f%%\n", s.WinRate)
+	fmt.Fprintf(w, "Total Return:\t%.2f%%\n", s.TotalReturn)
+	fmt.Fprintf(w, "Max Drawdown:\t%.2f%%\n", s.MaxDrawdown)
+	fmt.Fprintf(w, "Sharpe Ratio:\t%.2f\n", s.SharpeRatio)
+	printEquityStats(w, s)
+	if len(r.Execution) > 0 {
+		var fees float64
+		for _, t := range r.Trades {
+			fees += t.Fees
+		}
+		fmt.Fprintf(w, "Fees Paid:\t%.2f\n", fees)
+		fmt.Fprintf(w, "Rejected Orders:\t%d\n", len(r.Rejections))
+	}
+	w.Flush()
+
+	fmt.Fprintln(out, "\nContribution by symbol:")
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Symbol\tTrades\tRealized\tUnrealized\tContribution")
+	for _, c := range r.Contributions {
+		fmt.Fprintf(w, "%s\t%d\t%.2f\t%.2f\t%.2f%%\n", c.Symbol, c.Trades, c.RealizedPnL, c.UnrealizedPnL, c.Contribution)
+	}
+	w.Flush()
+}
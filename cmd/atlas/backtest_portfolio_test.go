@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/newthinker/atlas/internal/config"
+)
+
+func TestExecutePortfolioBacktest_RendersContribution(t *testing.T) {
+	var buf bytes.Buffer
+	deps := portfolioDeps{
+		provider:   &stubProvider{data: sampleOHLCV()},
+		strategies: engineWith("mock"),
+		out:        &buf,
+	}
+	err := executePortfolioBacktest(deps, portfolioParams{
+		Strategies: []string{"mock"},
+		Symbols:    []string{"AAPL", "MSFT"},
+		From:       "2026-01-01",
+		To:         "2026-01-10",
+		Capital:    10000,
+		Sizing:     "equal",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Final Equity", "Contribution by symbol", "AAPL", "MSFT"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q.\n--- output ---\n%s", want, out)
+		}
+	}
+}
+
+func TestExecutePortfolioBacktest_UnknownStrategy(t *testing.T) {
+	prov := &stubProvider{data: sampleOHLCV()}
+	deps := portfolioDeps{provider: prov, strategies: engineWith("mock"), out: &bytes.Buffer{}}
+	err := executePortfolioBacktest(deps, portfolioParams{
+		Strategies: []string{"ghost"}, Symbols: []string{"AAPL"}, From: "2026-01-01", To: "2026-01-10",
+	})
+	if err == nil || !strings.Contains(err.Error(), "mock") {
+		t.Fatalf("expected unknown-strategy error listing available strategies, got %v", err)
+	}
+	if prov.calls != 0 {
+		t.Error("provider should not be called for an unknown strategy")
+	}
+}
+
+func TestExecutePortfolioBacktest_InvalidSizing(t *testing.T) {
+	deps := portfolioDeps{provider: &stubProvider{data: sampleOHLCV()}, strategies: engineWith("mock"), out: &bytes.Buffer{}}
+	err := executePortfolioBacktest(deps, portfolioParams{
+		Strategies: []string{"mock"}, Symbols: []string{"AAPL"}, From: "2026-01-01", To: "2026-01-10",
+		Sizing: "fixed", SizePct: 0,
+	})
+	if err == nil {
+		t.Fatal("expected error for fixed sizing with 0%")
+	}
+}
+
+func TestResolvePortfolioAssets_WatchlistBinding(t *testing.T) {
+	watchlist := []config.WatchlistItem{
+		{Symbol: "AAPL", Strategies: []string{"ma_crossover", "pe_percentile"}},
+		{Symbol: "MSFT", Strategies: []string{"pe_percentile"}},
+		{Symbol: "QQQ"},
+	}
+	assets, err := resolvePortfolioAssets(nil, watchlist, []string{"ma_crossover"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(assets) != 2 {
+		t.Fatalf("assets = %+v, want AAPL and QQQ", assets)
+	}
+	if assets[0].Symbol != "AAPL" || len(assets[0].Strategies) != 1 || assets[0].Strategies[0] != "ma_crossover" {
+		t.Errorf("AAPL binding not narrowed: %+v", assets[0])
+	}
+	if assets[1].Symbol != "QQQ" || assets[1].Strategies != nil {
+		t.Errorf("unbound item should run all strategies: %+v", assets[1])
+	}
+
+	if _, err := resolvePortfolioAssets(nil, watchlist[1:2], []string{"ma_crossover"}); err == nil {
+		t.Error("expected error when no watchlist item matches")
+	}
+}
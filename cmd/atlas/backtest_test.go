@@ -11,11 +11,17 @@ package main
 import (
 	"bytes"
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/newthinker/atlas/internal/app"
+	"github.com/newthinker/atlas/internal/backtest"
+	"github.com/newthinker/atlas/internal/config"
 	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/router"
 	"github.com/newthinker/atlas/internal/strategy"
 )
 
@@ -161,3 +167,170 @@ func TestExecuteBacktest_InvalidDate(t *testing.T) {
 		t.Fatal("expected error for invalid --from date")
 	}
 }
+
+func TestExecuteBacktest_ExecutionSettings(t *testing.T) {
+	var buf bytes.Buffer
+	deps := backtestDeps{
+		provider:   &stubProvider{data: sampleOHLCV()},
+		strategies: engineWith("mock"),
+		settings:   backtestSettings{models: backtest.MarketModels, fill: backtest.FillNextOpen},
+		out:        &buf,
+	}
+	if err := executeBacktest(deps, "mock", "AAPL", "2026-01-01", "2026-01-10"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Fills:    next_open", "Costs:    US", "Fees Paid:", "Rejected Orders:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q.\n--- output ---\n%s", want, out)
+		}
+	}
+}
+
+func TestExecuteBacktest_ReportsSkippedBars(t *testing.T) {
+	var buf bytes.Buffer
+	// The built-in cn_liquidity has no statistics store: every bar fails.
+	deps := backtestDeps{provider: &stubProvider{data: sampleOHLCV()}, strategies: newBacktestEngine(), out: &buf}
+	if err := executeBacktest(deps, "cn_liquidity", "000300.SH", "2026-01-01", "2026-01-10"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "5 (analysis errors)") {
+		t.Errorf("output missing the skipped bars.\n--- output ---\n%s", out)
+	}
+}
+
+func TestExecutionModels(t *testing.T) {
+	if m, err := executionModels("market"); err != nil || m == nil {
+		t.Errorf("market: got %v, %v", m, err)
+	}
+	if m, err := executionModels("none"); err != nil || m != nil {
+		t.Errorf("none: want nil selector, got %v, %v", m, err)
+	}
+	if _, err := executionModels("free"); err == nil {
+		t.Error("expected error for unknown --costs value")
+	}
+}
+
+func TestResolveBenchmark(t *testing.T) {
+	tests := []struct{ flag, symbol, want string }{
+		{"", "600519.SH", "000300.SH"},
+		{"", "0700.HK", "^HSI"},
+		{"", "AAPL", "^GSPC"},
+		{"", "BTC-USD", ""},
+		{"none", "AAPL", ""},
+		{"QQQ", "AAPL", "QQQ"},
+	}
+	for _, tt := range tests {
+		if got := resolveBenchmark(tt.flag, tt.symbol); got != tt.want {
+			t.Errorf("resolveBenchmark(%q, %q) = %q, want %q", tt.flag, tt.symbol, got, tt.want)
+		}
+	}
+}
+
+func TestExecuteBacktest_ReportsBenchmark(t *testing.T) {
+	var buf bytes.Buffer
+	deps := backtestDeps{provider: &stubProvider{data: sampleOHLCV()}, strategies: engineWith("mock"), out: &buf}
+	if err := executeBacktest(deps, "mock", "AAPL", "2026-01-01", "2026-01-10"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Annualized Return:", "Sortino Ratio:", "Exposure:", "Benchmark:", "^GSPC", "Beta:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q.\n--- output ---\n%s", want, out)
+		}
+	}
+}
+
+func TestExecuteBacktest_WritesReport(t *testing.T) {
+	var buf bytes.Buffer
+	path := filepath.Join(t.TempDir(), "report.html")
+	deps := backtestDeps{provider: &stubProvider{data: sampleOHLCV()}, strategies: engineWith("mock"), out: &buf, report: path}
+	if err := executeBacktest(deps, "mock", "AAPL", "2026-01-01", "2026-01-10"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	html, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("report not written: %v", err)
+	}
+	if !strings.Contains(string(html), "Backtest: mock on AAPL") || !strings.Contains(buf.String(), "Report written to "+path) {
+		t.Errorf("unexpected report or output.\n--- output ---\n%s", buf.String())
+	}
+}
+
+func TestExecuteBacktest_RouterReplay(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := config.Defaults()
+	routerCfg := app.RouterConfig(cfg)
+	deps := backtestDeps{
+		provider:   &stubProvider{data: sampleOHLCV()},
+		strategies: engineWith("mock"),
+		out:        &buf,
+		settings:   backtestSettings{filter: func() backtest.SignalFilter { return router.New(routerCfg, nil, nil) }},
+	}
+	if err := executeBacktest(deps, "mock", "AAPL", "2026-01-01", "2026-01-10"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The mock's signals carry no confidence, so the default floor drops all.
+	out := buf.String()
+	for _, want := range []string{"=== Router Replay ===", "0 of 3 signals", "Suppressed (min_confidence):  3", "suppressed: min_confidence", "Every Signal Return:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q.\n--- output ---\n%s", want, out)
+		}
+	}
+}
+
+// peFeed serves the same PE on every bar.
+type peFeed float64
+
+func (f peFeed) FundamentalsAt(symbol string, bars []core.OHLCV) ([]*core.Fundamental, error) {
+	out := make([]*core.Fundamental, len(bars))
+	for i := range bars {
+		out[i] = &core.Fundamental{Symbol: symbol, PE: float64(f), PEPercentile: -1}
+	}
+	return out, nil
+}
+
+func TestExecuteBacktest_ValuationStrategy(t *testing.T) {
+	var buf bytes.Buffer
+	deps := backtestDeps{provider: &stubProvider{data: sampleOHLCV()}, strategies: newBacktestEngine(), out: &buf}
+	err := executeBacktest(deps, "pe_band", "AAPL", "2026-01-01", "2026-01-10")
+	if !errors.Is(err, backtest.ErrNoFundamentals) {
+		t.Fatalf("err = %v, want ErrNoFundamentals without a feed", err)
+	}
+
+	deps.settings.fundamentals = peFeed(10) // below pe_band's 15 → buy
+	if err := executeBacktest(deps, "pe_band", "AAPL", "2026-01-01", "2026-01-10"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Strategy: pe_band") || !strings.Contains(strings.Join(strings.Fields(buf.String()), " "), "Trades: 1") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestFundamentalFeed(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Prism.DBPath = filepath.Join(t.TempDir(), "prism.db")
+
+	feed, release, err := fundamentalFeed("none", cfg, &stubProvider{})
+	if err != nil || feed != nil {
+		t.Errorf("none = %v, %v; want no feed", feed, err)
+	}
+	release()
+
+	feed, release, err = fundamentalFeed("auto", cfg, &stubProvider{})
+	if _, ok := feed.(*backtest.EPSFundamentals); err != nil || !ok {
+		t.Errorf("auto without prism = %T, %v; want EPS reconstruction", feed, err)
+	}
+	release()
+
+	cfg.Prism.Enabled = true
+	feed, release, err = fundamentalFeed("auto", cfg, &stubProvider{})
+	if chain, ok := feed.(backtest.FundamentalChain); err != nil || !ok || len(chain) != 2 {
+		t.Errorf("auto with prism = %T, %v; want prism then EPS", feed, err)
+	}
+	release()
+
+	if _, _, err := fundamentalFeed("lixinger", cfg, &stubProvider{}); err == nil {
+		t.Error("expected error for unknown --fundamentals")
+	}
+}
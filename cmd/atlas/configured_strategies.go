@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/newthinker/atlas/internal/config"
+	"github.com/newthinker/atlas/internal/strategy"
+	"github.com/newthinker/atlas/internal/strategy/builtin"
+	"github.com/newthinker/atlas/internal/strategy/cn_liquidity"
+	"github.com/newthinker/atlas/internal/strategy/composite"
+	"github.com/newthinker/atlas/internal/strategy/rule"
+)
+
+// configuredStrategies builds the enabled rule and composite strategies
+// declared in the strategies config. catalog holds the built-in strategies;
+// every rule, enabled or not, is added to it so composites can combine rules
+// too. Composite children take the params of their own strategies entry, so a
+// child does not have to be enabled on its own to be combined. Strategies
+// that fail to initialise are left out and reported in err.
+func configuredStrategies(entries map[string]config.StrategyConfig, catalog *strategy.Engine) ([]strategy.Strategy, error) {
+	var out []strategy.Strategy
+	var errs []error
+
+	for _, name := range entriesOfType(entries, rule.Type) {
+		if _, clash := catalog.Get(name); clash {
+			errs = append(errs, fmt.Errorf("%s: rule name clashes with a built-in strategy", name))
+			continue
+		}
+		r := rule.New(name)
+		if err := r.Init(strategy.Config{Enabled: entries[name].Enabled, Params: entries[name].Params}); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		catalog.Register(r)
+		if entries[name].Enabled {
+			out = append(out, r)
+		}
+	}
+
+	resolve := func(name string) (strategy.Strategy, error) {
+		base, ok := catalog.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown strategy %q", name)
+		}
+		if _, isRule := base.(*rule.Rule); isRule {
+			return base, nil // already built from its entry
+		}
+		return strategy.WithParams(base, entries[name].Params)
+	}
+	for _, name := range entriesOfType(entries, composite.Type) {
+		if !entries[name].Enabled {
+			continue
+		}
+		if _, clash := catalog.Get(name); clash {
+			errs = append(errs, fmt.Errorf("%s: composite name clashes with a built-in strategy", name))
+			continue
+		}
+		c := composite.New(name, resolve)
+		if err := c.Init(strategy.Config{Enabled: true, Params: entries[name].Params}); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		out = append(out, c)
+	}
+	return out, errors.Join(errs...)
+}
+
+// validateStrategyEntry is the config.StrategyValidator of the CLI: it
+// checks an entry's type, the params of a built-in strategy against its
+// declared schema and Init, and the expressions of a rule.
+func validateStrategyEntry(name string, entry config.StrategyConfig) error {
+	switch entry.Type {
+	case "":
+		// Entries that name no built-in are not registered anywhere.
+		if s, ok := builtin.Lookup(name); ok {
+			return s.Init(strategy.Config{Params: entry.Params})
+		}
+	case composite.Type:
+		// Composites are checked when they are built, against the
+		// strategies their conditions resolve to.
+	case rule.Type:
+		return rule.ValidateParams(entry.Params)
+	default:
+		return fmt.Errorf("unknown type %q (want composite or rule)", entry.Type)
+	}
+	return nil
+}
+
+// entriesOfType returns the sorted names of the entries of type typ.
+func entriesOfType(entries map[string]config.StrategyConfig, typ string) []string {
+	var names []string
+	for name, entry := range entries {
+		if entry.Type == typ {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// withConfiguredStrategies registers the configured rule and composite
+// strategies on engine for the CLI commands, warning on w about those
+// skipped, and returns engine. cn_liquidity is replaced on engine, and in the
+// catalog composites resolve against, by one reading the Hestia store named
+// by its strategies entry, as serve wires it; release closes that store.
+func withConfiguredStrategies(engine *strategy.Engine, cfg *config.Config, w io.Writer) (_ *strategy.Engine, release func()) {
+	catalog := newBacktestEngine()
+	entry, declared := cfg.Strategies["cn_liquidity"]
+	src, release, err := liquiditySource(entry.Params)
+	if err != nil && declared {
+		fmt.Fprintf(w, "warning: cn_liquidity has no PBOC statistics: %v\n", err)
+	}
+	liquidity := cn_liquidity.New(src)
+	engine.Register(liquidity)
+	catalog.Register(liquidity)
+
+	configured, err := configuredStrategies(cfg.Strategies, catalog)
+	if err != nil {
+		fmt.Fprintf(w, "warning: skipping configured strategies: %v\n", err)
+	}
+	for _, s := range configured {
+		engine.Register(s)
+	}
+	return engine, release
+}
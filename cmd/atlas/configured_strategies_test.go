@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/newthinker/atlas/internal/config"
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+func TestConfiguredStrategies_Composites(t *testing.T) {
+	entries := map[string]config.StrategyConfig{
+		// A child that is not enabled on its own still lends its params.
+		"rsi": {Enabled: false, Params: map[string]any{"period": 9}},
+		"value_trend": {Enabled: true, Type: "composite", Params: map[string]any{
+			"mode": "vote",
+			"conditions": []any{
+				map[string]any{"strategy": "rsi", "action": "buy"},
+				map[string]any{"strategy": "macd", "action": "buy"},
+				map[string]any{"strategy": "ma_crossover", "action": "buy"},
+			},
+		}},
+		"disabled": {Enabled: false, Type: "composite"},
+		"broken": {Enabled: true, Type: "composite", Params: map[string]any{
+			"conditions": []any{map[string]any{"strategy": "nope"}},
+		}},
+		"macd": {Enabled: true, Type: "composite"}, // clashes with the built-in
+	}
+
+	comps, err := configuredStrategies(entries, newBacktestEngine())
+	if len(comps) != 1 || comps[0].Name() != "value_trend" {
+		t.Fatalf("composites = %v", comps)
+	}
+	if err == nil || !strings.Contains(err.Error(), "broken") || !strings.Contains(err.Error(), `unknown strategy "nope"`) ||
+		!strings.Contains(err.Error(), "macd: composite name clashes") {
+		t.Errorf("err = %v", err)
+	}
+
+	specs := comps[0].RequiredData().Indicators
+	if !strings.Contains(strings.Join(specs, ","), "rsi_9") {
+		t.Errorf("child params not applied, indicators = %v", specs)
+	}
+}
+
+func TestWithConfiguredStrategies_RegistersOnCLIEngine(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Strategies = map[string]config.StrategyConfig{
+		"combo": {Enabled: true, Type: "composite", Params: map[string]any{
+			"conditions": []any{map[string]any{"strategy": "rsi"}, map[string]any{"strategy": "bollinger"}},
+		}},
+		"bad": {Enabled: true, Type: "composite"},
+		"dip": {Enabled: true, Type: "rule", Params: map[string]any{"entry": "rsi(14) < 30"}},
+	}
+	var warn bytes.Buffer
+	eng, release := withConfiguredStrategies(strategy.NewEngine(), cfg, &warn)
+	defer release()
+	for _, name := range []string{"combo", "dip"} {
+		if _, ok := eng.Get(name); !ok {
+			t.Errorf("%s not registered: %v", name, eng.GetStrategyNames())
+		}
+	}
+	if !strings.Contains(warn.String(), "bad: composite needs at least one condition") {
+		t.Errorf("warning = %q", warn.String())
+	}
+}
+
+func TestConfiguredStrategies_Rules(t *testing.T) {
+	entries := map[string]config.StrategyConfig{
+		"dip":   {Enabled: true, Type: "rule", Params: map[string]any{"entry": "close > sma(200) and rsi(14) < 30"}},
+		"quiet": {Enabled: false, Type: "rule", Params: map[string]any{"entry": "atr(14) / close < 0.01"}},
+		"rsi":   {Enabled: true, Type: "rule", Params: map[string]any{"entry": "close > 1"}}, // clashes with the built-in
+		"both": {Enabled: true, Type: "composite", Params: map[string]any{
+			"conditions": []any{map[string]any{"strategy": "dip"}, map[string]any{"strategy": "quiet"}},
+		}},
+	}
+	got, err := configuredStrategies(entries, newBacktestEngine())
+	names := make([]string, len(got))
+	for i, s := range got {
+		names[i] = s.Name()
+	}
+	// The disabled rule is not registered itself but still serves as a child.
+	if strings.Join(names, ",") != "dip,both" {
+		t.Errorf("configured = %v", names)
+	}
+	if err == nil || !strings.Contains(err.Error(), "rsi: rule name clashes") {
+		t.Errorf("err = %v", err)
+	}
+	if specs := strings.Join(got[1].RequiredData().Indicators, ","); specs != "sma_200,rsi_14,atr_14" {
+		t.Errorf("composite indicators = %v", specs)
+	}
+}
+
+func TestValidateStrategyEntry(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Strategies = map[string]config.StrategyConfig{
+		"ma_crossover": {Enabled: true, Params: map[string]any{"fast_period": 20, "slow_period": 100}},
+		"bollinger":    {Params: map[string]any{"period": 20, "k": 2.5, "mode": "breakout"}},
+		"my_strategy":  {Params: map[string]any{"anything": 1}},
+		"dip_buyer":    {Type: "rule", Params: map[string]any{"entry": "close > sma(200) and rsi(14) < 30"}},
+		"both":         {Type: "composite", Params: map[string]any{"conditions": []any{}}},
+	}
+	if err := cfg.Validate(validateStrategyEntry); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		entry config.StrategyConfig
+		want  string
+	}{
+		{"ma_crossover", config.StrategyConfig{Params: map[string]any{"fast_period": 20, "ma_type": "ema"}}, `strategies.ma_crossover: ma_crossover: unknown param "ma_type"`},
+		{"rsi", config.StrategyConfig{Params: map[string]any{"period": "14"}}, "strategies.rsi: rsi: period must be a number, got string"},
+		{"rsi", config.StrategyConfig{Params: map[string]any{"oversold": 130}}, "rsi: oversold must be at most 100, got 130"},
+		{"bollinger", config.StrategyConfig{Params: map[string]any{"mode": "trend"}}, `bollinger: mode must be one of reversion, breakout, got "trend"`},
+		{"macd", config.StrategyConfig{Params: map[string]any{"fast_period": 30}}, "macd: periods must satisfy 0 < fast_period < slow_period"},
+		{"bad", config.StrategyConfig{Type: "rule", Params: map[string]any{"entry": "close > sma(200"}}, `strategies.bad: entry: column 16: expected ")"`},
+		{"bad", config.StrategyConfig{Type: "rule", Params: map[string]any{"entry": "close + 1"}}, "strategies.bad: entry: rule must be a condition"},
+		{"bad", config.StrategyConfig{Type: "scripted"}, `strategies.bad: unknown type "scripted"`},
+	}
+	for _, tc := range cases {
+		cfg.Strategies = map[string]config.StrategyConfig{tc.name: tc.entry}
+		err := cfg.Validate(validateStrategyEntry)
+		if err == nil || !strings.Contains(err.Error(), tc.want) || !errors.Is(err, core.ErrConfigInvalid) {
+			t.Errorf("%s %+v: Validate() = %v, want %q", tc.name, tc.entry, err, tc.want)
+		}
+	}
+}
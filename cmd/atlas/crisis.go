@@ -148,7 +148,7 @@ func runCrisisBackfill(cmd *cobra.Command, args []string) error {
 	if to == "" {
 		to = time.Now().UTC().Format("2006-01-02")
 	}
-	ig := crisis.NewIngestor(fred.New(apiKey), yahoo.New(), st)
+	ig := crisis.NewIngestor(fred.New(apiKey), yahoo.New(), st, crisis.WithIndicators(ccfg.IndicatorDefs()))
 	rep, err := ig.IngestAll(ctx, backfillFrom, to)
 	if err != nil {
 		return err
@@ -237,7 +237,7 @@ func runCrisisEval(cmd *cobra.Command, args []string) error {
 	if apiKey == "" {
 		return fmt.Errorf("FRED key missing: set env %s or collectors.fred.api_key in the main config (-c)", ccfg.FRED.APIKeyEnv)
 	}
-	ig := crisis.NewIngestor(fred.New(apiKey), yahoo.New(), st)
+	ig := crisis.NewIngestor(fred.New(apiKey), yahoo.New(), st, crisis.WithIndicators(ccfg.IndicatorDefs()))
 
 	switch evalMode {
 	case "daily":
@@ -328,7 +328,7 @@ func executeCrisisEvalDaily(ctx context.Context, d crisisEvalDeps, dateOverride
 	if err := d.store.AppendEvaluations(ctx, res.Evaluations); err != nil {
 		return err
 	}
-	printDayResult(d.out, res)
+	printDayResult(d.out, d.cfg, res)
 
 	for _, msg := range crisis.Messages(d.cfg, nc) {
 		if d.sender == nil {
@@ -350,7 +350,7 @@ func buildNotifyContext(ctx context.Context, d crisisEvalDeps, res *crisis.DayRe
 	nc := crisis.NotifyContext{Res: res, Summary: summaryKind(res.Date, res.State)}
 
 	nc.PrevDay = map[string]crisis.Evaluation{}
-	for _, ind := range crisis.AllIndicators {
+	for _, ind := range d.cfg.IndicatorNames() {
 		evals, err := d.store.RecentIndicatorEvals(ctx, ind, 1)
 		if err != nil {
 			return nc, err
@@ -377,7 +377,7 @@ func buildNotifyContext(ctx context.Context, d crisisEvalDeps, res *crisis.DayRe
 
 	// P2 去重：仅"昨日非 STALE、今日 STALE"的指标发一次（通知设计 §2）
 	nc.StaleLastObs = map[string]string{}
-	for _, ind := range crisis.AllIndicators {
+	for _, ind := range d.cfg.IndicatorNames() {
 		if res.Results[ind].Status != crisis.StatusStale {
 			continue
 		}
@@ -406,7 +406,7 @@ func buildNotifyContext(ctx context.Context, d crisisEvalDeps, res *crisis.DayRe
 	// 月报趋势：仅 SummaryMonthly ∧ NORMAL 时组装（通知设计 §8）
 	if nc.Summary == crisis.SummaryMonthly && res.State == crisis.StateNormal {
 		nc.Trends = map[string]crisis.Trend{}
-		for _, ind := range crisis.AllIndicators {
+		for _, ind := range d.cfg.IndicatorNames() {
 			win, err := d.store.SeriesWindow(ctx, ind, res.Date, 21)
 			if err != nil {
 				return nc, err
@@ -536,28 +536,28 @@ func mustAddDays(date string, n int) string {
 	return t.AddDate(0, 0, n).Format("2006-01-02")
 }
 
-func printDayResult(w io.Writer, res *crisis.DayResult) {
+func printDayResult(w io.Writer, cfg *crisis.Config, res *crisis.DayResult) {
 	if res.Transitioned() {
 		fmt.Fprintf(w, "%s: %s → %s\n", res.Date, res.PrevState, res.State)
 	} else {
 		fmt.Fprintf(w, "%s: %s\n", res.Date, res.State)
 	}
-	for _, ind := range crisis.AllIndicators {
+	for _, ind := range cfg.IndicatorNames() {
 		r := res.Results[ind]
 		fmt.Fprintf(w, "  %-10s %-20s %10.2f  p5y=%.2f  %s\n", ind, r.Status, r.Value, r.Pct5y, r.Tag)
 	}
 }
 
 func runCrisisStatus(cmd *cobra.Command, args []string) error {
-	_, st, err := openCrisisStore()
+	ccfg, st, err := openCrisisStore()
 	if err != nil {
 		return err
 	}
 	defer st.Close()
-	return executeCrisisStatus(cmd.Context(), st, cmd.OutOrStdout())
+	return executeCrisisStatus(cmd.Context(), ccfg, st, cmd.OutOrStdout())
 }
 
-func executeCrisisStatus(ctx context.Context, st *crisis.Store, out io.Writer) error {
+func executeCrisisStatus(ctx context.Context, cfg *crisis.Config, st *crisis.Store, out io.Writer) error {
 	sys, err := st.LatestSystemEval(ctx)
 	if err != nil {
 		return err
@@ -571,7 +571,7 @@ func executeCrisisStatus(ctx context.Context, st *crisis.Store, out io.Writer) e
 		return err
 	}
 	fmt.Fprintf(out, "system state: %s (as of %s, %d eval days)\n", sys.SystemState, sys.TS, days)
-	for _, ind := range crisis.AllIndicators {
+	for _, ind := range cfg.IndicatorNames() {
 		evals, err := st.RecentIndicatorEvals(ctx, ind, 1)
 		if err != nil {
 			return err
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/newthinker/atlas/internal/backtest"
+	"github.com/newthinker/atlas/internal/crisis"
+)
+
+var (
+	evaluateFrom      string
+	evaluateTo        string
+	evaluateBenchmark string
+	evaluateThreshold float64
+	evaluateWindow    int
+)
+
+// evaluatePeakLookbackYears 基准价格往前多取的年数：窗口首日若已在回撤中，
+// 运行峰值需要窗口之前的高点才能认出该事件。
+const evaluatePeakLookbackYears = 1
+
+var crisisEvaluateCmd = &cobra.Command{
+	Use:   "evaluate",
+	Short: "Measure whether crisis states led benchmark drawdowns",
+	Long: `Replays the evaluation pipeline over [--from, --to] (zero writes), fetches
+the benchmark's daily closes from the collectors, and finds every drawdown of
+at least --threshold. For each alarm level (WATCH, BREWING, CRISIS, each
+including the more severe states) it reports the hit rate, late and missed
+episodes, average lead time and false alarms, plus time spent in each state.
+Writes a JSON report and an HTML report (the replay report with an evaluation
+section) under reports/.`,
+	RunE: runCrisisEvaluate,
+}
+
+func init() {
+	crisisEvaluateCmd.Flags().StringVar(&evaluateFrom, "from", "", "start date YYYY-MM-DD (required)")
+	crisisEvaluateCmd.Flags().StringVar(&evaluateTo, "to", "", "end date YYYY-MM-DD (required)")
+	crisisEvaluateCmd.Flags().StringVar(&evaluateBenchmark, "benchmark", "^GSPC", "benchmark symbol whose drawdowns are scored")
+	crisisEvaluateCmd.Flags().Float64Var(&evaluateThreshold, "threshold", 0.10, "peak-to-trough decline that makes a drawdown episode (0.10 = 10%)")
+	crisisEvaluateCmd.Flags().IntVar(&evaluateWindow, "window", 90, "calendar days before a breach an alarm must be in force to count as a hit")
+	crisisCmd.AddCommand(crisisEvaluateCmd)
+}
+
+// crisisEvaluateDeps 注入依赖使 evaluate 流程可单测（模式同 crisisReportDeps）。
+type crisisEvaluateDeps struct {
+	cfg    *crisis.Config
+	store  *crisis.Store
+	prices backtest.OHLCVProvider
+	out    io.Writer
+	outDir string
+}
+
+func runCrisisEvaluate(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfigOrDefaults()
+	if err != nil {
+		return err
+	}
+	ccfg, st, err := openCrisisStore()
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+	deps := crisisEvaluateDeps{
+		cfg: ccfg, store: st, prices: registryProvider{reg: newCollectorRegistry(cfg)},
+		out: cmd.OutOrStdout(), outDir: "reports",
+	}
+	opt := crisis.LeadTimeOptions{Threshold: evaluateThreshold, WindowDays: evaluateWindow}
+	return executeCrisisEvaluate(cmd.Context(), deps, evaluateFrom, evaluateTo, evaluateBenchmark, opt)
+}
+
+func executeCrisisEvaluate(ctx context.Context, d crisisEvaluateDeps, from, to, benchmark string, opt crisis.LeadTimeOptions) error {
+	if from == "" || to == "" {
+		return fmt.Errorf("--from and --to are required")
+	}
+	fromT, err := time.Parse(dateLayout, from)
+	if err != nil {
+		return fmt.Errorf("bad date %q: want YYYY-MM-DD", from)
+	}
+	toT, err := time.Parse(dateLayout, to)
+	if err != nil {
+		return fmt.Errorf("bad date %q: want YYYY-MM-DD", to)
+	}
+	if from > to {
+		return fmt.Errorf("--from %s is after --to %s", from, to)
+	}
+
+	sr := d.store.Reader(ctx)
+	days, err := crisis.ReplayRange(d.cfg, sr, from, to)
+	if err != nil {
+		return err
+	}
+	if len(days) == 0 {
+		return fmt.Errorf("no observations between %s and %s — run backfill first", from, to)
+	}
+	bars, err := d.prices.FetchHistory(benchmark, fromT.AddDate(-evaluatePeakLookbackYears, 0, 0), toT, "1d")
+	if err == nil && len(bars) == 0 {
+		err = fmt.Errorf("no data")
+	}
+	if err != nil {
+		return fmt.Errorf("benchmark %s: %w", benchmark, err)
+	}
+	prices := make([]crisis.Point, 0, len(bars))
+	for _, b := range bars {
+		prices = append(prices, crisis.Point{Date: b.Time.Format(dateLayout), Value: b.Close})
+	}
+	rep, err := crisis.EvaluateLeadTime(days, prices, opt)
+	if err != nil {
+		return err
+	}
+	rep.Benchmark = benchmark
+
+	printLeadTimeReport(d.out, rep)
+
+	if err := os.MkdirAll(d.outDir, 0o755); err != nil {
+		return err
+	}
+	base := filepath.Join(d.outDir, fmt.Sprintf("crisis-evaluate-%s-%s", from, to))
+	js, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(base+".json", js, 0o644); err != nil {
+		return err
+	}
+	html, err := crisis.RenderEvaluationHTML(d.cfg, days, sr, rep)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(base+".html", []byte(html), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(d.out, "\nJSON 报告已写入 %s.json\nHTML 报告已写入 %s.html\n", base, base)
+	return nil
+}
+
+// printLeadTimeReport 终端摘要：各级别计分 + 各状态停留；逐事件明细见 JSON/HTML。
+func printLeadTimeReport(w io.Writer, rep *crisis.LeadTimeReport) {
+	fmt.Fprintf(w, "%s ~ %s · benchmark %s · drawdown ≥ %.0f%% · window %d days · %d episodes\n\n",
+		rep.From, rep.To, rep.Benchmark, rep.Threshold*100, rep.WindowDays, len(rep.Episodes))
+	fmt.Fprintf(w, "%-9s %5s %5s %6s %9s %9s %7s %6s %8s %10s\n",
+		"level", "hits", "late", "missed", "hit_rate", "avg_lead", "alarms", "false", "pending", "precision")
+	for _, l := range rep.Levels {
+		fmt.Fprintf(w, "%-9s %5d %5d %6d %8.0f%% %8.0fd %7d %6d %8d %9.0f%%\n",
+			l.Level, l.Hits, l.Late, l.Missed, l.HitRate*100, l.AvgLeadDays, l.Alarms, l.FalseAlarms, l.Pending, l.Precision*100)
+	}
+	fmt.Fprintf(w, "\n%-9s %5s %6s %6s %9s %9s\n", "state", "days", "share", "spells", "avg_spell", "bench")
+	for _, s := range rep.States {
+		fmt.Fprintf(w, "%-9s %5d %5.0f%% %6d %8.1fd %+8.1f%%\n",
+			s.State, s.Days, s.Share*100, s.Spells, s.AvgSpellDays, s.BenchReturn*100)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/crisis"
+)
+
+// fakeBenchmark 记录请求并返回固定收盘序列。
+type fakeBenchmark struct {
+	bars      []core.OHLCV
+	err       error
+	gotSymbol string
+	gotStart  time.Time
+	gotEnd    time.Time
+}
+
+func (f *fakeBenchmark) FetchHistory(symbol string, start, end time.Time, interval string) ([]core.OHLCV, error) {
+	f.gotSymbol, f.gotStart, f.gotEnd = symbol, start, end
+	return f.bars, f.err
+}
+
+// benchmarkBars 06-25..07-10 工作日收盘 100，07-09 跌到 95、07-10 跌到 88（破 10%）。
+func benchmarkBars() []core.OHLCV {
+	var bars []core.OHLCV
+	for d := time.Date(2026, 6, 25, 0, 0, 0, 0, time.UTC); !d.After(time.Date(2026, 7, 10, 0, 0, 0, 0, time.UTC)); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			continue
+		}
+		c := 100.0
+		switch d.Day() {
+		case 9:
+			c = 95
+		case 10:
+			c = 88
+		}
+		bars = append(bars, core.OHLCV{Symbol: "^GSPC", Time: d, Close: c})
+	}
+	return bars
+}
+
+func TestExecuteCrisisEvaluate(t *testing.T) {
+	st := newCrisisTestStore(t)
+	seedReplayWatch(t, st) // 07-08 起 NORMAL→WATCH
+	bench := &fakeBenchmark{bars: benchmarkBars()}
+	var out strings.Builder
+	d := crisisEvaluateDeps{cfg: crisisTestConfig(), store: st, prices: bench, out: &out, outDir: t.TempDir()}
+
+	err := executeCrisisEvaluate(context.Background(), d, "2026-06-25", "2026-07-10", "^GSPC",
+		crisis.LeadTimeOptions{Threshold: 0.10, WindowDays: 30})
+	require.NoError(t, err)
+	assert.Equal(t, "^GSPC", bench.gotSymbol)
+	assert.Equal(t, "2025-06-25", bench.gotStart.Format(dateLayout)) // 多取一年供运行峰值
+	assert.Equal(t, "2026-07-10", bench.gotEnd.Format(dateLayout))
+
+	s := out.String()
+	assert.Contains(t, s, "benchmark ^GSPC · drawdown ≥ 10% · window 30 days · 1 episodes")
+	assert.Contains(t, s, "WATCH         1     0      0      100%        2d")
+
+	base := filepath.Join(d.outDir, "crisis-evaluate-2026-06-25-2026-07-10")
+	js, err := os.ReadFile(base + ".json")
+	require.NoError(t, err)
+	var rep crisis.LeadTimeReport
+	require.NoError(t, json.Unmarshal(js, &rep))
+	assert.Equal(t, "^GSPC", rep.Benchmark)
+	require.Len(t, rep.Episodes, 1)
+	assert.Equal(t, "2026-07-10", rep.Episodes[0].Breach)
+	assert.Equal(t, []crisis.Warning{{Level: crisis.StateWatch, Onset: "2026-07-08", LeadDays: 2}}, rep.Episodes[0].Warnings)
+
+	html, err := os.ReadFile(base + ".html")
+	require.NoError(t, err)
+	assert.Contains(t, string(html), "信号领先性评估")
+	assert.Contains(t, string(html), "NORMAL → WATCH") // 回放报告本体仍在
+
+	// 回放零落库
+	sys, err := st.LatestSystemEval(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, sys)
+}
+
+func TestExecuteCrisisEvaluateErrors(t *testing.T) {
+	st := newCrisisTestStore(t)
+	seedReplayWatch(t, st)
+	ctx := context.Background()
+	opt := crisis.LeadTimeOptions{Threshold: 0.10, WindowDays: 30}
+	d := crisisEvaluateDeps{cfg: crisisTestConfig(), store: st, prices: &fakeBenchmark{bars: benchmarkBars()},
+		out: &strings.Builder{}, outDir: t.TempDir()}
+
+	for _, tc := range []struct{ from, to, want string }{
+		{"", "2026-07-10", "--from and --to are required"},
+		{"2026-7-1", "2026-07-10", "bad date"},
+		{"2026-07-10", "2026-07-01", "is after"},
+		{"2027-01-04", "2027-01-08", "run backfill first"},
+	} {
+		assert.ErrorContains(t, executeCrisisEvaluate(ctx, d, tc.from, tc.to, "^GSPC", opt), tc.want)
+	}
+
+	d.prices = &fakeBenchmark{err: errors.New("yahoo down")}
+	assert.ErrorContains(t, executeCrisisEvaluate(ctx, d, "2026-06-25", "2026-07-10", "^GSPC", opt), "benchmark ^GSPC: yahoo down")
+	d.prices = &fakeBenchmark{}
+	assert.ErrorContains(t, executeCrisisEvaluate(ctx, d, "2026-06-25", "2026-07-10", "^GSPC", opt), "benchmark ^GSPC: no data")
+	d.prices = &fakeBenchmark{bars: benchmarkBars()}
+	assert.ErrorContains(t, executeCrisisEvaluate(ctx, d, "2026-06-25", "2026-07-10", "^GSPC",
+		crisis.LeadTimeOptions{Threshold: 10, WindowDays: 30}), "threshold")
+}
@@ -453,7 +453,7 @@ func TestExecuteCrisisStatus(t *testing.T) {
 	ctx := context.Background()
 
 	var buf strings.Builder
-	require.NoError(t, executeCrisisStatus(ctx, st, &buf))
+	require.NoError(t, executeCrisisStatus(ctx, crisisTestConfig(), st, &buf))
 	assert.Contains(t, buf.String(), "no evaluations yet")
 
 	seedObservations(t, st, "2026-07-10", 80)
@@ -464,7 +464,7 @@ func TestExecuteCrisisStatus(t *testing.T) {
 	require.NoError(t, executeCrisisEvalDaily(ctx, deps, ""))
 
 	buf.Reset()
-	require.NoError(t, executeCrisisStatus(ctx, st, &buf))
+	require.NoError(t, executeCrisisStatus(ctx, crisisTestConfig(), st, &buf))
 	out := buf.String()
 	assert.Contains(t, out, "NORMAL")
 	assert.Contains(t, out, "vix")
@@ -553,7 +553,7 @@ func TestMustAddDays(t *testing.T) {
 // printDayResult 的状态迁移分支(PrevState != State 打印 "→")。
 func TestPrintDayResultTransition(t *testing.T) {
 	var buf bytes.Buffer
-	printDayResult(&buf, &crisis.DayResult{
+	printDayResult(&buf, crisisTestConfig(), &crisis.DayResult{
 		Date: "2026-07-10", PrevState: crisis.StateNormal, State: crisis.StateWatch,
 		Results: map[string]crisis.IndicatorResult{},
 	})
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/newthinker/atlas/internal/config"
+	"github.com/newthinker/atlas/internal/crisis"
+	"github.com/newthinker/atlas/internal/crisisgate"
+)
+
+// crisisGatePolicies maps the crisis_gate policies, keyed by lowercased state
+// name as viper leaves them, onto the gate's per-state policies.
+func crisisGatePolicies(cfg config.CrisisGateConfig) map[crisis.SystemState]crisisgate.Policy {
+	policies := make(map[crisis.SystemState]crisisgate.Policy, len(cfg.Policies))
+	for state, p := range cfg.Policies {
+		policies[crisis.SystemState(strings.ToUpper(state))] = crisisgate.Policy{
+			SuppressBuys:       p.SuppressBuys,
+			BuyConfidenceScale: p.BuyConfidenceScale,
+			Warn:               p.Warn,
+		}
+	}
+	return policies
+}
+
+// crisisGateOptions maps crisis_gate.max_age_days; 0 keeps the gate default.
+func crisisGateOptions(cfg config.CrisisGateConfig) []crisisgate.Option {
+	return []crisisgate.Option{crisisgate.WithMaxAge(time.Duration(cfg.MaxAgeDays) * 24 * time.Hour)}
+}
+
+// openGateCrisisStore loads the crisis monitor config named by
+// crisis_gate.crisis_config and opens the store it writes.
+func openGateCrisisStore(cfg config.CrisisGateConfig) (*crisis.Config, *crisis.Store, error) {
+	return loadCrisisStore("crisis gate", cfg.CrisisConfig)
+}
+
+// openCrisisWebStore is openGateCrisisStore for crisis_web.crisis_config.
+func openCrisisWebStore(cfg config.CrisisWebConfig) (*crisis.Config, *crisis.Store, error) {
+	return loadCrisisStore("crisis dashboard", cfg.CrisisConfig)
+}
+
+// loadCrisisStore loads a crisis monitor config and opens its store; errors
+// are prefixed with who asked.
+func loadCrisisStore(who, path string) (*crisis.Config, *crisis.Store, error) {
+	ccfg, err := crisis.LoadConfig(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", who, err)
+	}
+	st, err := crisis.NewStore(ccfg.Storage.Path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", who, err)
+	}
+	return ccfg, st, nil
+}
+
+// newLiveCrisisGate builds the serve-time gate over the latest system
+// evaluation in the crisis store. release closes the store.
+func newLiveCrisisGate(cfg config.CrisisGateConfig) (gate *crisisgate.Gate, release func(), err error) {
+	_, st, err := openGateCrisisStore(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	gate = crisisgate.New(crisisgate.NewStoreSource(st), crisisGatePolicies(cfg), crisisGateOptions(cfg)...)
+	return gate, func() { st.Close() }, nil
+}
+
+// newReplayCrisisGate builds the backtest gate over a replay of the whole
+// crisis store, so each bar sees the state the monitor would have reported
+// then. release closes the store.
+func newReplayCrisisGate(cfg config.CrisisGateConfig) (gate *crisisgate.Gate, release func(), err error) {
+	ccfg, st, err := openGateCrisisStore(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	src := crisisgate.NewReplaySource(func() ([]crisis.ReplayDay, error) {
+		return crisis.ReplayRange(ccfg, st.Reader(context.Background()), "0000-01-01", time.Now().Format(dateLayout))
+	})
+	gate = crisisgate.New(src, crisisGatePolicies(cfg), crisisGateOptions(cfg)...)
+	return gate, func() { st.Close() }, nil
+}
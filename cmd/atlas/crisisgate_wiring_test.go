@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/config"
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/crisis"
+	"github.com/newthinker/atlas/internal/crisisgate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrisisGatePolicies(t *testing.T) {
+	got := crisisGatePolicies(config.CrisisGateConfig{Policies: map[string]config.CrisisPolicyConfig{
+		"brewing": {BuyConfidenceScale: 0.5, Warn: true},
+		"crisis":  {SuppressBuys: true},
+	}})
+	assert.Equal(t, map[crisis.SystemState]crisisgate.Policy{
+		crisis.StateBrewing: {BuyConfidenceScale: 0.5, Warn: true},
+		crisis.StateCrisis:  {SuppressBuys: true},
+	}, got)
+}
+
+func TestNewLiveCrisisGate(t *testing.T) {
+	cfgPath, dbPath := writeTempCrisisConfigDB(t)
+	gateCfg := config.CrisisGateConfig{
+		CrisisConfig: cfgPath,
+		Policies:     map[string]config.CrisisPolicyConfig{"crisis": {SuppressBuys: true}},
+	}
+
+	today := time.Now().Format(dateLayout)
+	st, err := crisis.NewStore(dbPath)
+	require.NoError(t, err)
+	require.NoError(t, st.AppendEvaluations(context.Background(), []crisis.Evaluation{
+		{TS: today, EvalAt: today, SystemState: crisis.StateCrisis, Status: crisis.StatusRed},
+	}))
+	require.NoError(t, st.Close())
+
+	gate, release, err := newLiveCrisisGate(gateCfg)
+	require.NoError(t, err)
+	defer release()
+	_, reason, err := gate.Apply(core.Signal{Action: core.ActionBuy, Confidence: 0.9}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, crisisgate.ReasonCrisisState, reason)
+
+	gateCfg.CrisisConfig = filepath.Join(t.TempDir(), "missing.yaml")
+	_, _, err = newLiveCrisisGate(gateCfg)
+	assert.ErrorContains(t, err, "crisis gate")
+}
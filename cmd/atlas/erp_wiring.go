@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/newthinker/atlas/internal/collector/akshare"
+	"github.com/newthinker/atlas/internal/collector/fred"
+	"github.com/newthinker/atlas/internal/config"
+	prismstore "github.com/newthinker/atlas/internal/storage/prism"
+	"github.com/newthinker/atlas/internal/strategy/erp"
+	"go.uber.org/zap"
+)
+
+// newERPStrategy builds the erp strategy over the prism store's PE history,
+// FRED yields when a FRED key is set (FRED_API_KEY, else the fred collector's
+// api_key) and the aktools CGB yields. Without prism the strategy is
+// registered but emits nothing.
+func newERPStrategy(cfg *config.Config, store *prismstore.Store, log *zap.Logger) *erp.Strategy {
+	var pe erp.PEHistory
+	if store != nil {
+		pe = prismPEHistory{store: store}
+	} else {
+		log.Warn("erp strategy has no PE history: prism is disabled")
+	}
+	prismCfg := cfg.Prism
+	prismCfg.ApplyDefaults()
+	sources := map[string]erp.YieldSource{
+		erp.SourceAkshare: akshareYields{c: akshare.New(prismCfg.AkshareBaseURL)},
+	}
+	key := os.Getenv("FRED_API_KEY")
+	if key == "" {
+		key = cfg.Collectors["fred"].APIKey
+	}
+	if key != "" {
+		sources[erp.SourceFRED] = fredYields{c: fred.New(key)}
+	} else {
+		log.Warn("erp strategy has no FRED yields: set FRED_API_KEY or collectors.fred.api_key")
+	}
+	return erp.New(pe, sources)
+}
+
+// seriesReader is the part of the prism store prismPEHistory reads.
+type seriesReader interface {
+	Series(symbol, from string) (*prismstore.SeriesData, error)
+}
+
+// prismPEHistory adapts the prism store's daily valuations to erp.PEHistory.
+type prismPEHistory struct {
+	store seriesReader
+}
+
+func (p prismPEHistory) PEHistory(symbol string, from time.Time) ([]erp.Point, error) {
+	sd, err := p.store.Series(symbol, from.Format("2006-01-02"))
+	if errors.Is(err, prismstore.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	pts := make([]erp.Point, 0, len(sd.Dates))
+	for i, d := range sd.Dates {
+		if math.IsNaN(sd.PETTM[i]) {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			return nil, fmt.Errorf("prism: %s: bad date %q", symbol, d)
+		}
+		pts = append(pts, erp.Point{Date: t, Value: sd.PETTM[i]})
+	}
+	return pts, nil
+}
+
+// fredYields adapts the FRED client to erp.YieldSource; series is a FRED
+// series ID such as DGS10.
+type fredYields struct {
+	c *fred.Client
+}
+
+func (f fredYields) Yields(series string, start, end time.Time) ([]erp.Point, error) {
+	obs, err := f.c.FetchSeries(context.Background(), series, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	pts := make([]erp.Point, 0, len(obs))
+	for _, o := range obs {
+		t, err := time.Parse("2006-01-02", o.Date)
+		if err != nil {
+			return nil, fmt.Errorf("fred %s: bad date %q", series, o.Date)
+		}
+		pts = append(pts, erp.Point{Date: t, Value: o.Value})
+	}
+	return pts, nil
+}
+
+// akshareYields adapts the aktools CGB yields to erp.YieldSource; series is a
+// tenor such as 10y.
+type akshareYields struct {
+	c *akshare.Client
+}
+
+func (a akshareYields) Yields(series string, start, end time.Time) ([]erp.Point, error) {
+	ys, err := a.c.FetchCGBYields(series, start, end)
+	if err != nil {
+		return nil, err
+	}
+	pts := make([]erp.Point, len(ys))
+	for i, y := range ys {
+		pts[i] = erp.Point{Date: y.Date, Value: y.Value}
+	}
+	return pts, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/collector/akshare"
+	"github.com/newthinker/atlas/internal/collector/fred"
+	prismstore "github.com/newthinker/atlas/internal/storage/prism"
+	"github.com/newthinker/atlas/internal/strategy/erp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSeries struct {
+	sd   *prismstore.SeriesData
+	err  error
+	from string
+}
+
+func (f *fakeSeries) Series(symbol, from string) (*prismstore.SeriesData, error) {
+	f.from = from
+	return f.sd, f.err
+}
+
+func TestPrismPEHistory(t *testing.T) {
+	store := &fakeSeries{sd: &prismstore.SeriesData{
+		Dates: []string{"2026-07-01", "2026-07-02", "2026-07-03"},
+		PETTM: []float64{20, math.NaN(), 21},
+	}}
+	pts, err := prismPEHistory{store: store}.PEHistory("^GSPC", time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, "2021-07-01", store.from)
+	require.Len(t, pts, 2, "days without PE are dropped")
+	assert.Equal(t, "2026-07-03", pts[1].Date.Format("2006-01-02"))
+	assert.Equal(t, 21.0, pts[1].Value)
+
+	// An index prism does not track has no history rather than an error.
+	store.err = fmt.Errorf("%w: X", prismstore.ErrNotFound)
+	pts, err = prismPEHistory{store: store}.PEHistory("X", time.Now())
+	assert.NoError(t, err)
+	assert.Empty(t, pts)
+}
+
+func TestFredYields(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DGS10", r.URL.Query().Get("series_id"))
+		assert.Equal(t, "2026-07-01", r.URL.Query().Get("observation_start"))
+		fmt.Fprint(w, `{"observations":[{"date":"2026-07-01","value":"4.25"},{"date":"2026-07-03","value":"."}]}`)
+	}))
+	defer srv.Close()
+
+	pts, err := fredYields{c: fred.NewWithBaseURL("k", srv.URL)}.Yields("DGS10",
+		time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 7, 3, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, pts, 1)
+	assert.Equal(t, 4.25, pts[0].Value)
+}
+
+func TestAkshareYields(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]any{{"日期": "2026-07-02", "中国国债收益率10年": 1.7}})
+	}))
+	defer srv.Close()
+
+	pts, err := akshareYields{c: akshare.New(srv.URL)}.Yields("10y",
+		time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 7, 3, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, pts, 1)
+	assert.Equal(t, erp.Point{Date: time.Date(2026, 7, 2, 0, 0, 0, 0, time.UTC), Value: 1.7}, pts[0])
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/newthinker/atlas/internal/broker/paper"
 	"github.com/newthinker/atlas/internal/config"
 	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
 	"go.uber.org/zap"
 )
 
@@ -23,6 +24,7 @@ type orderExecutor interface {
 // without constructing a full App.
 type executorSetter interface {
 	SetExecutor(app.SignalExecutor)
+	SetPositions(app.PositionSource)
 }
 
 // signalExecutor adapts core.Signal (from the analysis loop) to the broker
@@ -79,6 +81,27 @@ func (s *signalExecutor) SubmitSignal(ctx context.Context, sig core.Signal) erro
 	return nil
 }
 
+// trackerPositions exposes the execution chain's PositionTracker to the
+// analysis loop. It implements app.PositionSource.
+type trackerPositions struct {
+	tracker *broker.PositionTracker
+}
+
+// Position returns the tracked long position of symbol; short and flat
+// positions report none.
+func (p trackerPositions) Position(symbol string) (strategy.Position, bool) {
+	pos := p.tracker.GetPosition(symbol)
+	if pos.Quantity <= 0 {
+		return strategy.Position{}, false
+	}
+	return strategy.Position{
+		Quantity:     float64(pos.Quantity),
+		AverageCost:  pos.AverageCost,
+		EntryDate:    pos.OpenedAt,
+		UnrealizedPL: pos.UnrealizedPL,
+	}, true
+}
+
 // isExecutableAction reports whether a signal action warrants an order.
 // Non-directional actions (hold/watch/etc.) are skipped (boundary[1]).
 func isExecutableAction(a core.Action) bool {
@@ -135,7 +158,8 @@ func buildExecution(ctx context.Context, cfg *config.Config, log *zap.Logger) (*
 }
 
 // wireExecution builds the execution chain and, when present, injects the
-// signal adapter into the app via SetExecutor. It returns the ExecutionManager
+// signal adapter into the app via SetExecutor and the tracked positions via
+// SetPositions, so strategies see what the account holds. It returns the ExecutionManager
 // (nil when disabled/non-paper) so the caller can also expose it through the API
 // dependencies (functional[0]).
 func wireExecution(ctx context.Context, cfg *config.Config, setter executorSetter, log *zap.Logger) (*broker.ExecutionManager, error) {
@@ -147,5 +171,6 @@ func wireExecution(ctx context.Context, cfg *config.Config, setter executorSette
 		return nil, nil
 	}
 	setter.SetExecutor(newSignalExecutor(execManager, log))
+	setter.SetPositions(trackerPositions{tracker: execManager.Tracker()})
 	return execManager, nil
 }
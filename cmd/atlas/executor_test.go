@@ -61,9 +61,11 @@ func (s *stubExecutor) Execute(ctx context.Context, signal *core.Signal, price f
 	return s.result, s.err
 }
 
-// recordingSetter captures the executor passed to SetExecutor.
+// recordingSetter captures the executor passed to SetExecutor and the
+// positions passed to SetPositions.
 type recordingSetter struct {
 	executor  app.SignalExecutor
+	positions app.PositionSource
 	setCalled int
 }
 
@@ -72,6 +74,10 @@ func (r *recordingSetter) SetExecutor(e app.SignalExecutor) {
 	r.executor = e
 }
 
+func (r *recordingSetter) SetPositions(p app.PositionSource) {
+	r.positions = p
+}
+
 func paperBrokerConfig(mode string, sizePct, maxPositionPct float64) *config.Config {
 	cfg := config.Defaults()
 	cfg.Broker.Enabled = true
@@ -149,6 +155,27 @@ func TestWireExecution_PaperMode_InjectsBoth(t *testing.T) {
 	if setter.executor == nil {
 		t.Fatal("an executor must be injected into the app")
 	}
+	if setter.positions == nil {
+		t.Fatal("the tracked positions must be injected into the app")
+	}
+}
+
+func TestTrackerPositions(t *testing.T) {
+	tracker := broker.NewPositionTracker(paper.New(0))
+	positions := trackerPositions{tracker: tracker}
+	if _, ok := positions.Position("AAPL"); ok {
+		t.Fatal("nothing is held yet")
+	}
+
+	filledAt := time.Date(2024, 3, 4, 15, 0, 0, 0, time.UTC)
+	tracker.UpdateOnFill(&broker.Order{
+		Symbol: "AAPL", Side: broker.OrderSideBuy,
+		FilledQuantity: 10, AverageFillPrice: 100, FilledAt: &filledAt,
+	})
+	pos, ok := positions.Position("AAPL")
+	if !ok || pos.Quantity != 10 || pos.AverageCost != 100 || !pos.EntryDate.Equal(filledAt) {
+		t.Errorf("Position = %+v, %v", pos, ok)
+	}
 }
 
 // boundary[0]
@@ -287,6 +287,9 @@ func loadConfigOrDefaults() (*config.Config, error) {
 		if err != nil {
 			return nil, fmt.Errorf("loading config: %w", err)
 		}
+		if err := loaded.ValidateStrategies(validateStrategyEntry); err != nil {
+			return nil, fmt.Errorf("loading config: %w", err)
+		}
 		cfg = loaded
 	} else {
 		cfg = config.Defaults()
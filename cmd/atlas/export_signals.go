@@ -15,11 +15,7 @@ import (
 	"github.com/newthinker/atlas/internal/collector"
 	"github.com/newthinker/atlas/internal/core"
 	"github.com/newthinker/atlas/internal/strategy"
-	"github.com/newthinker/atlas/internal/strategy/dividend_yield"
-	"github.com/newthinker/atlas/internal/strategy/ma_crossover"
-	"github.com/newthinker/atlas/internal/strategy/pe_band"
-	"github.com/newthinker/atlas/internal/strategy/pe_percentile"
-	"github.com/newthinker/atlas/internal/strategy/price_percentile"
+	"github.com/newthinker/atlas/internal/strategy/builtin"
 	"github.com/spf13/cobra"
 )
 
@@ -78,16 +74,13 @@ var csvHeader = []string{"symbol", "date", "strategy", "action", "confidence", "
 // required so that `--strategies pe_band` reaches the explicit "requires
 // fundamentals" rejection in executeExport instead of the unknown-strategy
 // branch (design §2.1/§5). Registration is not execution: fundamentals
-// strategies are rejected by the whitelist before any replay.
+// strategies are rejected by the whitelist before any replay, so their
+// constructor thresholds are never exercised offline.
 func newExportEngine() *strategy.Engine {
 	e := strategy.NewEngine()
-	e.Register(ma_crossover.New(50, 200))
-	e.Register(price_percentile.New())
-	// Fundamentals strategies — registered only so the whitelist can reject them
-	// explicitly; the constructor thresholds are never exercised offline.
-	e.Register(pe_band.New(15, 30))
-	e.Register(dividend_yield.New(3.0))
-	e.Register(pe_percentile.New())
+	for _, s := range builtin.New() {
+		e.Register(s)
+	}
 	return e
 }
 
@@ -123,9 +116,11 @@ func runExportSignals(cmd *cobra.Command, args []string) error {
 		w = f
 	}
 
+	strategies, closeStrategies := withConfiguredStrategies(newExportEngine(), cfg, os.Stderr)
+	defer closeStrategies()
 	deps := exportDeps{
 		provider:   registryProvider{reg: reg},
-		strategies: newExportEngine(),
+		strategies: strategies,
 		out:        out,
 		errOut:     os.Stderr,
 	}
@@ -186,6 +186,16 @@ func TestNewExportEngine_RegistersAllFive(t *testing.T) {
 	}
 }
 
+func TestCLIEngines_RegisterIndicatorStrategies(t *testing.T) {
+	for label, eng := range map[string]*strategy.Engine{"export": newExportEngine(), "backtest": newBacktestEngine()} {
+		for _, name := range []string{"rsi", "macd", "bollinger"} {
+			if _, ok := eng.Get(name); !ok {
+				t.Errorf("%s engine missing strategy %q (registered: %v)", label, name, eng.GetStrategyNames())
+			}
+		}
+	}
+}
+
 func TestExportSignals_PEBandViaCLIEngineRejected(t *testing.T) {
 	// Drive the REAL CLI engine: pe_band must reach the fundamentals rejection,
 	// NOT the unknown-strategy branch (regression guard for plan T4 warning).
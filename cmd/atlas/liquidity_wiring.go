@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/newthinker/atlas/internal/hestia"
+	"github.com/newthinker/atlas/internal/macro/liquidity"
+	"github.com/newthinker/atlas/internal/strategy/cn_liquidity"
+	"go.uber.org/zap"
+)
+
+// newLiquidityStrategy builds the cn_liquidity strategy over the Hestia store
+// named by its hestia_config param. A store that cannot be opened, or has not
+// been created by `atlas hestia ingest` yet, leaves the strategy registered
+// but failing every analysis with the reason. release closes the store.
+func newLiquidityStrategy(params map[string]any, log *zap.Logger) (s *cn_liquidity.Strategy, release func()) {
+	src, release, err := liquiditySource(params)
+	if err != nil {
+		log.Warn("cn_liquidity strategy has no PBOC statistics", zap.Error(err))
+	}
+	return cn_liquidity.New(src), release
+}
+
+// liquiditySource opens the Hestia store named by the hestia_config param as
+// a cn_liquidity source. When it cannot be opened err says why and the
+// returned source reports the same error on every read. release closes the
+// store and is safe to call either way.
+func liquiditySource(params map[string]any) (src cn_liquidity.Source, release func(), err error) {
+	st, err := openLiquidityStore(params)
+	if err != nil {
+		return unavailableLiquidity{err: err}, func() {}, err
+	}
+	return hestiaLiquidity{store: st}, func() { _ = st.Close() }, nil
+}
+
+// openLiquidityStore opens the existing Hestia store named by the
+// hestia_config param. It does not create one: an empty store would only
+// hide that ingest never ran.
+func openLiquidityStore(params map[string]any) (*hestia.Store, error) {
+	path := cn_liquidity.DefaultHestiaConfig
+	if v, ok := params["hestia_config"].(string); ok && v != "" {
+		path = v
+	}
+	hcfg, err := hestia.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(hcfg.Storage.DBPath); err != nil {
+		return nil, fmt.Errorf("hestia store: %w", err)
+	}
+	return hestia.NewStore(hcfg.Storage.DBPath)
+}
+
+// publishedBeforeReader is the part of *hestia.Store hestiaLiquidity reads.
+type publishedBeforeReader interface {
+	PublishedBefore(ctx context.Context, date string) ([]hestia.Observation, error)
+}
+
+// hestiaLiquidity adapts the Hestia store to cn_liquidity.Source: the
+// liquidity series derived from the reports published before a date.
+type hestiaLiquidity struct {
+	store publishedBeforeReader
+}
+
+func (h hestiaLiquidity) Liquidity(before time.Time, impulseMonths int) (cn_liquidity.Series, error) {
+	obs, err := h.store.PublishedBefore(context.Background(), before.Format(dateLayout))
+	if err != nil {
+		return cn_liquidity.Series{}, err
+	}
+	series := cn_liquidity.Series{
+		Scissors:      liquidity.Scissors(obs),
+		TSFImpulse:    liquidity.TSFImpulse(obs, impulseMonths),
+		CreditImpulse: liquidity.CreditImpulse(obs),
+	}
+	for _, o := range obs {
+		series.PublishedAt = max(series.PublishedAt, o.Meta.PublishedAt)
+	}
+	return series, nil
+}
+
+// unavailableLiquidity is the source of a cn_liquidity strategy whose store
+// could not be opened: every read fails with the reason.
+type unavailableLiquidity struct {
+	err error
+}
+
+func (u unavailableLiquidity) Liquidity(time.Time, int) (cn_liquidity.Series, error) {
+	return cn_liquidity.Series{}, u.err
+}
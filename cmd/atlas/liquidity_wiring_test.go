@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/config"
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/hestia"
+	"github.com/newthinker/atlas/internal/strategy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type publishedBeforeStub struct {
+	obs  []hestia.Observation
+	date string
+}
+
+func (p *publishedBeforeStub) PublishedBefore(_ context.Context, date string) ([]hestia.Observation, error) {
+	p.date = date
+	return p.obs, nil
+}
+
+func TestHestiaLiquidity(t *testing.T) {
+	report := func(period, published string, values map[string]float64) hestia.Observation {
+		return hestia.Observation{Meta: hestia.Meta{Period: period, PeriodType: "monthly", PublishedAt: published}, Values: values}
+	}
+	store := &publishedBeforeStub{obs: []hestia.Observation{
+		report("2026-03", "2026-04-14", map[string]float64{hestia.FieldM1YoY: 2.0, hestia.FieldM2YoY: 7.0, hestia.FieldTSFStockYoY: 8.0}),
+		report("2026-09", "2026-10-15", map[string]float64{hestia.FieldM1YoY: 5.0, hestia.FieldM2YoY: 8.0, hestia.FieldTSFStockYoY: 8.5}),
+	}}
+
+	series, err := hestiaLiquidity{store: store}.Liquidity(time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), 6)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-16", store.date)
+	require.Len(t, series.Scissors, 2)
+	assert.InDelta(t, -3.0, series.Scissors[1].Value, 1e-9)
+	require.Len(t, series.TSFImpulse, 1)
+	assert.Equal(t, "2026-09", series.TSFImpulse[0].Period)
+	assert.InDelta(t, 0.5, series.TSFImpulse[0].Value, 1e-9)
+	assert.Empty(t, series.CreditImpulse)
+	assert.Equal(t, "2026-10-15", series.PublishedAt)
+}
+
+func writeHestiaConfig(t *testing.T, dbPath string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hestia.yaml")
+	yaml := fmt.Sprintf("storage:\n  db_path: %s\ndiscover:\n  index_url: https://example.invalid/\n  max_pages: 1\n  timeout: 5s\n", dbPath)
+	require.NoError(t, os.WriteFile(path, []byte(yaml), 0o644))
+	return path
+}
+
+func TestNewLiquidityStrategy_MissingStoreIsNotCreated(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "hestia.db")
+	s, release := newLiquidityStrategy(map[string]any{"hestia_config": writeHestiaConfig(t, dbPath)}, zap.NewNop())
+	defer release()
+	require.NotNil(t, s)
+	assert.NoFileExists(t, dbPath, "serve does not create an empty store")
+
+	s, release = newLiquidityStrategy(map[string]any{"hestia_config": "/nonexistent/hestia.yaml"}, zap.NewNop())
+	defer release()
+	assert.NotNil(t, s, "a bad hestia config leaves the strategy registered")
+}
+
+func TestWithConfiguredStrategies_WiresLiquidityStore(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "hestia.db")
+	st, err := hestia.NewStore(dbPath)
+	require.NoError(t, err)
+	require.NoError(t, st.Close())
+
+	cfg := config.Defaults()
+	cfg.Strategies = map[string]config.StrategyConfig{
+		"cn_liquidity": {Params: map[string]any{"hestia_config": writeHestiaConfig(t, dbPath)}},
+		"liq_combo": {Enabled: true, Type: "composite", Params: map[string]any{
+			"conditions": []any{map[string]any{"strategy": "cn_liquidity"}},
+		}},
+	}
+	var warn bytes.Buffer
+	eng, release := withConfiguredStrategies(newBacktestEngine(), cfg, &warn)
+	defer release()
+	assert.Empty(t, warn.String())
+
+	ctx := strategy.AnalysisContext{Symbol: "000300.SH", Market: core.MarketCNA,
+		OHLCV: []core.OHLCV{{Symbol: "000300.SH", Time: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), Close: 4000}}}
+	for _, name := range []string{"cn_liquidity", "liq_combo"} {
+		s, ok := eng.Get(name)
+		require.True(t, ok, name)
+		_, err := s.Analyze(ctx)
+		assert.NoError(t, err, "%s reads the wired store", name)
+	}
+}
+
+func TestWithConfiguredStrategies_MissingLiquidityStore(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Strategies = map[string]config.StrategyConfig{
+		"cn_liquidity": {Params: map[string]any{"hestia_config": "/nonexistent/hestia.yaml"}},
+	}
+	var warn bytes.Buffer
+	eng, release := withConfiguredStrategies(newBacktestEngine(), cfg, &warn)
+	defer release()
+	assert.Contains(t, warn.String(), "cn_liquidity has no PBOC statistics")
+
+	s, ok := eng.Get("cn_liquidity")
+	require.True(t, ok)
+	_, err := s.Analyze(strategy.AnalysisContext{Symbol: "000300.SH", Market: core.MarketCNA,
+		OHLCV: []core.OHLCV{{Symbol: "000300.SH", Time: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), Close: 4000}}})
+	assert.ErrorContains(t, err, "/nonexistent/hestia.yaml", "the analysis reports why the store is missing")
+}
+
+func TestOpenLiquidityStore(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "hestia.db")
+	created, err := hestia.NewStore(dbPath)
+	require.NoError(t, err)
+	require.NoError(t, created.Close())
+
+	st, err := openLiquidityStore(map[string]any{"hestia_config": writeHestiaConfig(t, dbPath)})
+	require.NoError(t, err)
+	defer st.Close()
+	obs, err := st.PublishedBefore(context.Background(), "2026-10-16")
+	require.NoError(t, err)
+	assert.Empty(t, obs)
+}
@@ -18,10 +18,16 @@ import (
 // 配置不可读时**退化而不阻断**：这些入口原本在配置无效时也能跑（crisis 靠环境变量拿
 // FRED key，backtest 压根不需要配置），补接线不应该把它们变成必须有合法配置才能启动。
 // 退化目标是内置策略表，即「限流/缓存按内置值、无 config 覆盖、无跨进程配额账本」。
-func ensurePolicyGate() {
-	if _, err := loadConfigOrDefaults(); err != nil {
-		initPolicyGate(config.Defaults(), nil)
+//
+// 返回装配所用的配置（不可读时为内置默认值），供 backtest 读取估值回看年数与 Prism
+// 库路径而不必二次加载——二次加载会再装一遍闸门。
+func ensurePolicyGate() *config.Config {
+	cfg, err := loadConfigOrDefaults()
+	if err != nil {
+		cfg = config.Defaults()
+		initPolicyGate(cfg, nil)
 	}
+	return cfg
 }
 
 // initPolicyGate 从配置构建 collector 策略闸门并装成进程内单例。
@@ -17,6 +17,7 @@ import (
 	"github.com/newthinker/atlas/internal/config"
 	atlasctx "github.com/newthinker/atlas/internal/context"
 	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/crisis"
 	"github.com/newthinker/atlas/internal/llm/factory"
 	"github.com/newthinker/atlas/internal/logger"
 	"github.com/newthinker/atlas/internal/meta"
@@ -26,12 +27,21 @@ import (
 	"github.com/newthinker/atlas/internal/notifier/telegram"
 	"github.com/newthinker/atlas/internal/notifier/webhook"
 	"github.com/newthinker/atlas/internal/prism/sankey"
+	"github.com/newthinker/atlas/internal/storage/backtestrun"
 	prismstore "github.com/newthinker/atlas/internal/storage/prism"
 	signalstore "github.com/newthinker/atlas/internal/storage/signal"
 	"github.com/newthinker/atlas/internal/strategy"
+	"github.com/newthinker/atlas/internal/strategy/bollinger"
+	"github.com/newthinker/atlas/internal/strategy/cn_liquidity"
+	"github.com/newthinker/atlas/internal/strategy/erp"
+	"github.com/newthinker/atlas/internal/strategy/exit"
 	"github.com/newthinker/atlas/internal/strategy/ma_crossover"
+	"github.com/newthinker/atlas/internal/strategy/macd"
+	"github.com/newthinker/atlas/internal/strategy/momentum_rotation"
 	"github.com/newthinker/atlas/internal/strategy/pe_percentile"
 	"github.com/newthinker/atlas/internal/strategy/price_percentile"
+	"github.com/newthinker/atlas/internal/strategy/rsi"
+	"github.com/newthinker/atlas/internal/strategy/valuation_percentile"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	_ "modernc.org/sqlite"
@@ -76,7 +86,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 	}
 
 	// Validate configuration
-	if err := cfg.Validate(); err != nil {
+	if err := cfg.Validate(validateStrategyEntry); err != nil {
 		return fmt.Errorf("config validation failed: %w", err)
 	}
 
@@ -118,35 +128,135 @@ func runServe(cmd *cobra.Command, args []string) error {
 	// are reused as alert sinks when the alert loop is enabled below.
 	notifiers := registerConfiguredNotifiers(cfg, application, log)
 
+	// Open the Prism store when enabled; nil keeps its API routes unregistered
+	// and the erp strategy without PE history. Closed on shutdown via defer
+	// (serve blocks until the signal handler returns).
+	var prismStore *prismstore.Store
+	var prismSankey *sankey.Service
+	prismCfg := cfg.Prism
+	prismCfg.ApplyDefaults()
+	if prismCfg.Enabled {
+		prismStore, err = prismstore.Open(prismCfg.DBPath)
+		if err != nil {
+			return fmt.Errorf("opening prism store: %w", err)
+		}
+		defer prismStore.Close()
+
+		// Templates are read once at startup: changing one needs a restart.
+		// A failure here must not take the whole server down, but it must not
+		// pass unnoticed either (AD-16) — serve keeps running with the earnings
+		// bridge routes unregistered, and the reason is in the log.
+		templates, err := sankey.LoadTemplates(sankeyTemplateDir)
+		switch {
+		case err != nil:
+			log.Error("prism sankey disabled: loading templates failed",
+				zap.String("dir", sankeyTemplateDir), zap.Error(err))
+		case len(templates) == 0:
+			log.Warn("prism sankey disabled: no templates configured",
+				zap.String("dir", sankeyTemplateDir))
+		default:
+			prismSankey = sankey.NewService(prismStore, templates)
+			log.Info("prism sankey enabled", zap.Int("templates", len(templates)))
+		}
+	}
+
 	// Create strategy engine and register strategies
 	strategies := strategy.NewEngine()
 	if strategyCfg, ok := cfg.Strategies["ma_crossover"]; ok && strategyCfg.Enabled {
-		// Get params with defaults
-		fastPeriod := 50
-		slowPeriod := 200
-		if fast, ok := strategyCfg.Params["fast_period"].(int); ok {
-			fastPeriod = fast
-		}
-		if slow, ok := strategyCfg.Params["slow_period"].(int); ok {
-			slowPeriod = slow
-		}
-		maStrategy := ma_crossover.New(fastPeriod, slowPeriod)
-		if err := maStrategy.Init(strategy.Config{Params: strategyCfg.Params}); err != nil {
-			log.Warn("failed to init ma_crossover strategy", zap.Error(err))
-		} else {
-			strategies.Register(maStrategy)
-			application.RegisterStrategy(maStrategy)
-		}
+		registerConfiguredStrategy(strategies, application, ma_crossover.New(50, 200), strategy.Config{Params: strategyCfg.Params}, log)
 	}
 
-	// Percentile strategies: position of price / PE within their own multi-year
-	// history. Both read enabled + params from config like ma_crossover above.
+	// Percentile strategies: position of price / PE / PB / PS / dividend yield
+	// / equity risk premium within their own multi-year history. All read
+	// enabled + params from config like ma_crossover above.
 	if strategyCfg, ok := cfg.Strategies["price_percentile"]; ok && strategyCfg.Enabled {
 		registerConfiguredStrategy(strategies, application, price_percentile.New(), strategy.Config{Params: strategyCfg.Params}, log)
 	}
 	if strategyCfg, ok := cfg.Strategies["pe_percentile"]; ok && strategyCfg.Enabled {
 		registerConfiguredStrategy(strategies, application, pe_percentile.New(), strategy.Config{Params: strategyCfg.Params}, log)
 	}
+	if strategyCfg, ok := cfg.Strategies["pb_percentile"]; ok && strategyCfg.Enabled {
+		registerConfiguredStrategy(strategies, application, valuation_percentile.NewPB(), strategy.Config{Params: strategyCfg.Params}, log)
+	}
+	if strategyCfg, ok := cfg.Strategies["ps_percentile"]; ok && strategyCfg.Enabled {
+		registerConfiguredStrategy(strategies, application, valuation_percentile.NewPS(), strategy.Config{Params: strategyCfg.Params}, log)
+	}
+	if strategyCfg, ok := cfg.Strategies["dividend_yield_percentile"]; ok && strategyCfg.Enabled {
+		registerConfiguredStrategy(strategies, application, valuation_percentile.NewDividendYield(), strategy.Config{Params: strategyCfg.Params}, log)
+	}
+	// erp reads its own PE and yield series; the composites below combine
+	// this wired instance too.
+	var erpStrategy *erp.Strategy
+	if strategyCfg, ok := cfg.Strategies["erp"]; ok {
+		erpStrategy = newERPStrategy(cfg, prismStore, log)
+		if strategyCfg.Enabled {
+			registerConfiguredStrategy(strategies, application, erpStrategy, strategy.Config{Params: strategyCfg.Params}, log)
+		}
+	}
+
+	// cn_liquidity reads the Hestia PBOC statistics store named by its
+	// hestia_config param; like erp, the composites below combine this wired
+	// instance too. The store is closed on shutdown.
+	var liquidityStrategy *cn_liquidity.Strategy
+	if strategyCfg, ok := cfg.Strategies["cn_liquidity"]; ok {
+		var closeLiquidity func()
+		liquidityStrategy, closeLiquidity = newLiquidityStrategy(strategyCfg.Params, log)
+		defer closeLiquidity()
+		if strategyCfg.Enabled {
+			registerConfiguredStrategy(strategies, application, liquidityStrategy, strategy.Config{Params: strategyCfg.Params}, log)
+		}
+	}
+
+	// Indicator strategies: RSI / MACD / Bollinger, defaults overridable by params.
+	if strategyCfg, ok := cfg.Strategies["rsi"]; ok && strategyCfg.Enabled {
+		registerConfiguredStrategy(strategies, application, rsi.New(14, 30, 70), strategy.Config{Params: strategyCfg.Params}, log)
+	}
+	if strategyCfg, ok := cfg.Strategies["macd"]; ok && strategyCfg.Enabled {
+		registerConfiguredStrategy(strategies, application, macd.New(12, 26, 9), strategy.Config{Params: strategyCfg.Params}, log)
+	}
+	if strategyCfg, ok := cfg.Strategies["bollinger"]; ok && strategyCfg.Enabled {
+		registerConfiguredStrategy(strategies, application, bollinger.New(20, 2, bollinger.ModeReversion), strategy.Config{Params: strategyCfg.Params}, log)
+	}
+
+	// Exit strategies close held positions; they see a position only when
+	// the broker execution chain is wired below.
+	if strategyCfg, ok := cfg.Strategies["stop_loss"]; ok && strategyCfg.Enabled {
+		registerConfiguredStrategy(strategies, application, exit.NewStopLoss(8), strategy.Config{Params: strategyCfg.Params}, log)
+	}
+	if strategyCfg, ok := cfg.Strategies["take_profit"]; ok && strategyCfg.Enabled {
+		registerConfiguredStrategy(strategies, application, exit.NewTakeProfit(20), strategy.Config{Params: strategyCfg.Params}, log)
+	}
+	if strategyCfg, ok := cfg.Strategies["atr_trailing_stop"]; ok && strategyCfg.Enabled {
+		registerConfiguredStrategy(strategies, application, exit.NewTrailingStop(22, 3), strategy.Config{Params: strategyCfg.Params}, log)
+	}
+	if strategyCfg, ok := cfg.Strategies["time_stop"]; ok && strategyCfg.Enabled {
+		registerConfiguredStrategy(strategies, application, exit.NewTimeStop(60), strategy.Config{Params: strategyCfg.Params}, log)
+	}
+
+	// Cross-sectional strategies rank every watchlist symbol they apply to at
+	// the end of each analysis cycle.
+	if strategyCfg, ok := cfg.Strategies["momentum_rotation"]; ok && strategyCfg.Enabled {
+		registerConfiguredStrategy(strategies, application, momentum_rotation.New(126, 3), strategy.Config{Params: strategyCfg.Params}, log)
+	}
+
+	// Rule strategies evaluate config expressions; composites combine the
+	// signals of the strategies above, whose children need not be enabled on
+	// their own.
+	catalog := newBacktestEngine()
+	if erpStrategy != nil {
+		catalog.Register(erpStrategy)
+	}
+	if liquidityStrategy != nil {
+		catalog.Register(liquidityStrategy)
+	}
+	configured, err := configuredStrategies(cfg.Strategies, catalog)
+	if err != nil {
+		log.Warn("skipping configured strategies", zap.Error(err))
+	}
+	for _, s := range configured {
+		strategies.Register(s)
+		application.RegisterStrategy(s)
+	}
 
 	// Set watchlist from config with full details (name, market, type, strategies)
 	for _, item := range cfg.Watchlist {
@@ -166,14 +276,32 @@ func runServe(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Create backtester with first available collector
-	var backtester *backtest.Backtester
-	collectors := application.GetCollectors()
-	if len(collectors) > 0 {
-		backtester = backtest.New(collectors[0])
-	} else {
-		// Create a default yahoo collector for backtesting
-		backtester = backtest.New(yahoo.New())
+	// Gate routed signals on the crisis monitor's system state when enabled.
+	// The monitor's own cron keeps the store current; the gate only reads it.
+	if cfg.CrisisGate.Enabled {
+		if gate, closeGate, err := newLiveCrisisGate(cfg.CrisisGate); err != nil {
+			log.Warn("failed to enable crisis gate", zap.Error(err))
+		} else {
+			defer closeGate()
+			application.SetSignalGate(gate)
+			log.Info("crisis gate enabled",
+				zap.String("crisis_config", cfg.CrisisGate.CrisisConfig),
+				zap.Int("policies", len(cfg.CrisisGate.Policies)),
+			)
+		}
+	}
+
+	// Serve the crisis monitor dashboard when enabled; nil answers its routes
+	// with "not enabled". Read-only, like the gate.
+	var crisisDash *crisis.Dashboard
+	if cfg.CrisisWeb.Enabled {
+		if ccfg, st, err := openCrisisWebStore(cfg.CrisisWeb); err != nil {
+			log.Warn("failed to enable crisis dashboard", zap.Error(err))
+		} else {
+			defer st.Close()
+			crisisDash = crisis.NewDashboard(ccfg, st)
+			log.Info("crisis dashboard enabled", zap.String("crisis_config", cfg.CrisisWeb.CrisisConfig))
+		}
 	}
 
 	// Create metrics registry if enabled
@@ -202,35 +330,37 @@ func runServe(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("wiring execution: %w", err)
 	}
 
-	// Open the Prism store when enabled; nil keeps its API routes unregistered.
-	// Closed on shutdown via defer (serve blocks until the signal handler returns).
-	var prismStore *prismstore.Store
-	var prismSankey *sankey.Service
-	prismCfg := cfg.Prism
-	prismCfg.ApplyDefaults()
-	if prismCfg.Enabled {
-		prismStore, err = prismstore.Open(prismCfg.DBPath)
-		if err != nil {
-			return fmt.Errorf("opening prism store: %w", err)
-		}
-		defer prismStore.Close()
+	// Create backtester with first available collector; fills pay each
+	// symbol's market costs and respect its lot and settlement rules, and
+	// runs are compared with the market's default benchmark. Valuation
+	// strategies read the stored prism valuations when prism is enabled and
+	// fall back to rebuilding PE from yahoo EPS history.
+	var backtestPrices backtest.OHLCVProvider
+	if collectors := application.GetCollectors(); len(collectors) > 0 {
+		backtestPrices = collectors[0]
+	} else {
+		// Create a default yahoo collector for backtesting
+		backtestPrices = yahoo.New()
+	}
+	var funds backtest.FundamentalChain
+	if prismStore != nil {
+		funds = append(funds, backtest.NewPrismFundamentals(prismStore, cfg.Valuation.LookbackYears))
+	}
+	funds = append(funds, backtest.NewEPSFundamentals(backtestPrices, yahoo.New(), cfg.Valuation.LookbackYears))
+	backtester := backtest.New(backtestPrices,
+		backtest.WithExecutionModels(backtest.MarketModels),
+		backtest.WithBenchmark(defaultBenchmark),
+		backtest.WithFundamentals(funds),
+	)
 
-		// Templates are read once at startup: changing one needs a restart.
-		// A failure here must not take the whole server down, but it must not
-		// pass unnoticed either (AD-16) — serve keeps running with the earnings
-		// bridge routes unregistered, and the reason is in the log.
-		templates, err := sankey.LoadTemplates(sankeyTemplateDir)
-		switch {
-		case err != nil:
-			log.Error("prism sankey disabled: loading templates failed",
-				zap.String("dir", sankeyTemplateDir), zap.Error(err))
-		case len(templates) == 0:
-			log.Warn("prism sankey disabled: no templates configured",
-				zap.String("dir", sankeyTemplateDir))
-		default:
-			prismSankey = sankey.NewService(prismStore, templates)
-			log.Info("prism sankey enabled", zap.Int("templates", len(templates)))
-		}
+	// Finished backtests and optimizations are kept for the run history;
+	// without the database they still run but are not kept.
+	var backtestRuns *backtestrun.SQLiteStore
+	if backtestRuns, err = backtestrun.NewSQLiteStore(cfg.Storage.Backtests.Path); err != nil {
+		log.Warn("backtest runs and optimizations will not be saved", zap.Error(err))
+		backtestRuns = nil
+	} else {
+		defer backtestRuns.Close()
 	}
 
 	// Create server dependencies
@@ -238,6 +368,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 		App:              application,
 		SignalStore:      sigStore,
 		Backtester:       backtester,
+		BacktestRuns:     backtestRuns,
 		Strategies:       strategies,
 		Metrics:          metricsReg,
 		ExecutionManager: execManager,
@@ -246,6 +377,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 		PrismLow:         prismCfg.LowPct,
 		PrismHigh:        prismCfg.HighPct,
 		PrismSankey:      prismSankey,
+		CrisisDashboard:  crisisDash,
 	}
 
 	// Create server config
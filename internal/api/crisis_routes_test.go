@@ -0,0 +1,35 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 危机看板未启用时，API 与页面都必须注册并答 404 说明原因，而不是落到 "/"
+// catch-all 的 200 dashboard（同 TestPrismAPIsAnswerJSON404WhenDisabled）。
+func TestCrisisRoutesAnswer404WhenDisabled(t *testing.T) {
+	srv := newTestServer(t, nil) // CrisisDashboard 为 nil
+	requireDashboardCatchAllPresent(t, srv)
+
+	for _, p := range []string{"/api/crisis/status", "/api/crisis/history", "/api/crisis/indicators/vix"} {
+		w := getPath(t, srv, p)
+		require.Equal(t, http.StatusNotFound, w.Code, p)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json", p)
+		var resp struct {
+			Data struct {
+				Error string `json:"error"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp), p)
+		assert.Contains(t, resp.Data.Error, "not enabled", p)
+	}
+
+	w := getPath(t, srv, "/crisis")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.NotContains(t, strings.ToLower(w.Body.String()), "<html")
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/newthinker/atlas/internal/api/job"
@@ -18,18 +19,24 @@ const backtestTimeout = 5 * time.Minute
 
 // BacktestRequest is the request body for starting a backtest.
 type BacktestRequest struct {
-	Symbol   string         `json:"symbol"`
-	Strategy string         `json:"strategy"`
-	Start    string         `json:"start"`
-	End      string         `json:"end"`
-	Params   map[string]any `json:"params,omitempty"`
+	Symbol   string `json:"symbol"`
+	Strategy string `json:"strategy"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	// Params overrides the registered strategy's params for this run only.
+	Params map[string]any `json:"params,omitempty"`
 }
 
 // BacktestHandler handles backtest API requests.
 type BacktestHandler struct {
-	jobStore   *job.Store
-	backtester *backtest.Backtester
-	strategies *strategy.Engine
+	jobStore      *job.Store
+	backtester    *backtest.Backtester
+	strategies    *strategy.Engine
+	optimizations OptimizationStore
+	runs          RunStore
+
+	mu    sync.Mutex
+	saved map[string]savedRun // job ID → stored run
 }
 
 // NewBacktestHandler creates a new backtest handler.
@@ -42,6 +49,7 @@ func NewBacktestHandler(
 		jobStore:   jobStore,
 		backtester: backtester,
 		strategies: strategies,
+		saved:      make(map[string]savedRun),
 	}
 }
 
@@ -53,7 +61,11 @@ func (h *BacktestHandler) Create(w http.ResponseWriter, r *http.Request) {
 			core.WrapError(core.ErrConfigInvalid, err))
 		return
 	}
+	h.start(w, req)
+}
 
+// start validates req and runs it as a background job.
+func (h *BacktestHandler) start(w http.ResponseWriter, req BacktestRequest) {
 	// Validate required fields
 	if req.Symbol == "" || req.Strategy == "" {
 		response.Error(w, http.StatusBadRequest,
@@ -82,6 +94,13 @@ func (h *BacktestHandler) Create(w http.ResponseWriter, r *http.Request) {
 			core.WrapError(core.ErrStrategyFailed, nil))
 		return
 	}
+	if len(req.Params) > 0 {
+		if strat, err = strategy.WithParams(strat, req.Params); err != nil {
+			response.Error(w, http.StatusBadRequest,
+				core.WrapError(core.ErrConfigInvalid, err))
+			return
+		}
+	}
 
 	// Create job
 	j := h.jobStore.Create("backtest")
@@ -91,7 +110,7 @@ func (h *BacktestHandler) Create(w http.ResponseWriter, r *http.Request) {
 	status := j.Status
 
 	// Run backtest in background
-	go h.runBacktest(jobID, strat, req.Symbol, start, end)
+	go h.runBacktest(jobID, strat, req, start, end)
 
 	response.JSON(w, http.StatusAccepted, map[string]any{
 		"job_id": jobID,
@@ -99,11 +118,12 @@ func (h *BacktestHandler) Create(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// runBacktest executes the backtest and updates job status.
+// runBacktest executes the backtest and updates job status. A finished run
+// is also kept in the run store when one is configured.
 func (h *BacktestHandler) runBacktest(
 	jobID string,
 	strat strategy.Strategy,
-	symbol string,
+	req BacktestRequest,
 	start, end time.Time,
 ) {
 	// Mark as running
@@ -114,7 +134,7 @@ func (h *BacktestHandler) runBacktest(
 	// Run backtest
 	ctx, cancel := context.WithTimeout(context.Background(), backtestTimeout)
 	defer cancel()
-	result, err := h.backtester.Run(ctx, strat, symbol, start, end)
+	result, err := h.backtester.Run(ctx, strat, req.Symbol, start, end)
 
 	if err != nil {
 		h.jobStore.Update(jobID, func(j *job.Job) {
@@ -124,6 +144,10 @@ func (h *BacktestHandler) runBacktest(
 		return
 	}
 
+	if h.runs != nil {
+		h.saveRun(ctx, jobID, req, result)
+	}
+
 	h.jobStore.Update(jobID, func(j *job.Job) {
 		j.Status = job.StatusComplete
 		j.Progress = 100
@@ -147,6 +171,13 @@ func (h *BacktestHandler) GetStatus(w http.ResponseWriter, r *http.Request, jobI
 
 	if j.Status == job.StatusComplete {
 		resp["result"] = j.Result
+		if saved, ok := h.savedRun(jobID); ok {
+			if saved.err != nil {
+				resp["warning"] = "run not saved to history: " + saved.err.Error()
+			} else {
+				resp["run_id"] = saved.id
+			}
+		}
 	}
 	if j.Status == job.StatusFailed && j.Error != nil {
 		resp["error"] = map[string]string{
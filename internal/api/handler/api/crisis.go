@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/newthinker/atlas/internal/api/response"
+	"github.com/newthinker/atlas/internal/crisis"
+)
+
+// CrisisDashboard is the subset of *crisis.Dashboard the handlers need.
+type CrisisDashboard interface {
+	Status(ctx context.Context) (*crisis.StatusView, error)
+	History(ctx context.Context, from, to string) (*crisis.HistoryView, error)
+	Indicator(ctx context.Context, name, from, to string) (*crisis.IndicatorHistory, error)
+}
+
+// CrisisHandler serves the crisis monitor JSON API.
+type CrisisHandler struct {
+	dash CrisisDashboard
+}
+
+func NewCrisisHandler(dash CrisisDashboard) *CrisisHandler {
+	return &CrisisHandler{dash: dash}
+}
+
+// errCrisisNotEnabled is the error returned when serve has no crisis store.
+// The routes are registered regardless, for the reason given at
+// errPrismNotEnabled.
+const errCrisisNotEnabled = "crisis monitor not enabled"
+
+// enabled answers a JSON 404 and reports false when no dashboard is wired.
+func (h *CrisisHandler) enabled(w http.ResponseWriter) bool {
+	if h.dash == nil {
+		response.JSON(w, http.StatusNotFound, map[string]any{"error": errCrisisNotEnabled})
+		return false
+	}
+	return true
+}
+
+// Status serves GET /api/crisis/status.
+func (h *CrisisHandler) Status(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	v, err := h.dash.Status(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err)
+		return
+	}
+	response.JSON(w, http.StatusOK, v)
+}
+
+// History serves GET /api/crisis/history?from=&to=.
+func (h *CrisisHandler) History(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	q := r.URL.Query()
+	v, err := h.dash.History(r.Context(), q.Get("from"), q.Get("to"))
+	if err != nil {
+		crisisError(w, err)
+		return
+	}
+	response.JSON(w, http.StatusOK, v)
+}
+
+// Indicator serves GET /api/crisis/indicators/{name}?from=&to=.
+func (h *CrisisHandler) Indicator(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled(w) {
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/api/crisis/indicators/")
+	if name == "" {
+		response.JSON(w, http.StatusBadRequest, map[string]any{"error": "indicator required"})
+		return
+	}
+	q := r.URL.Query()
+	v, err := h.dash.Indicator(r.Context(), name, q.Get("from"), q.Get("to"))
+	if err != nil {
+		crisisError(w, err)
+		return
+	}
+	response.JSON(w, http.StatusOK, v)
+}
+
+// crisisError maps the dashboard's caller errors to 400/404; anything else is
+// a store failure and answers a sanitized 500.
+func crisisError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, crisis.ErrInvalidRange):
+		response.JSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+	case errors.Is(err, crisis.ErrUnknownIndicator):
+		response.JSON(w, http.StatusNotFound, map[string]any{"error": err.Error()})
+	default:
+		response.Error(w, http.StatusInternalServerError, err)
+	}
+}
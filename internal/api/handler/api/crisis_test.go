@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newthinker/atlas/internal/api/response"
+	"github.com/newthinker/atlas/internal/crisis"
+)
+
+type fakeCrisisDashboard struct {
+	err            error
+	gotName        string
+	gotFrom, gotTo string
+}
+
+func (f *fakeCrisisDashboard) Status(context.Context) (*crisis.StatusView, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &crisis.StatusView{Date: "2026-07-10", State: crisis.StateWatch, StateDays: 3,
+		Indicators: []crisis.IndicatorStatus{{Name: "vix", Status: crisis.StatusAmber, Reading: "26.1"}}}, nil
+}
+
+func (f *fakeCrisisDashboard) History(_ context.Context, from, to string) (*crisis.HistoryView, error) {
+	f.gotFrom, f.gotTo = from, to
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &crisis.HistoryView{From: from, To: to, Transitions: []crisis.Transition{
+		{Date: "2026-07-08", From: crisis.StateNormal, To: crisis.StateWatch}}}, nil
+}
+
+func (f *fakeCrisisDashboard) Indicator(_ context.Context, name, from, to string) (*crisis.IndicatorHistory, error) {
+	f.gotName, f.gotFrom, f.gotTo = name, from, to
+	if f.err != nil {
+		return nil, f.err
+	}
+	if name != "vix" {
+		return nil, fmt.Errorf("%w: %s", crisis.ErrUnknownIndicator, name)
+	}
+	return &crisis.IndicatorHistory{Name: name, Thresholds: []crisis.Threshold{{Value: 25, Status: crisis.StatusAmber}}}, nil
+}
+
+func crisisData(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	var resp response.SuccessResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp.Data.(map[string]any)
+}
+
+func TestCrisisStatusAndHistory(t *testing.T) {
+	fake := &fakeCrisisDashboard{}
+	h := NewCrisisHandler(fake)
+
+	rec := httptest.NewRecorder()
+	h.Status(rec, httptest.NewRequest(http.MethodGet, "/api/crisis/status", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	data := crisisData(t, rec)
+	assert.Equal(t, "WATCH", data["state"])
+	assert.Equal(t, 3.0, data["state_days"])
+	ind := data["indicators"].([]any)[0].(map[string]any)
+	assert.Equal(t, "26.1", ind["reading"])
+	assert.Nil(t, ind["pct_5y"])
+
+	rec = httptest.NewRecorder()
+	h.History(rec, httptest.NewRequest(http.MethodGet, "/api/crisis/history?from=2026-07-01&to=2026-07-10", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2026-07-01", fake.gotFrom)
+	assert.Equal(t, "2026-07-10", fake.gotTo)
+	tr := crisisData(t, rec)["transitions"].([]any)[0].(map[string]any)
+	assert.Equal(t, "WATCH", tr["to"])
+}
+
+func TestCrisisIndicator(t *testing.T) {
+	fake := &fakeCrisisDashboard{}
+	h := NewCrisisHandler(fake)
+
+	rec := httptest.NewRecorder()
+	h.Indicator(rec, httptest.NewRequest(http.MethodGet, "/api/crisis/indicators/vix?from=2026-01-01", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "vix", fake.gotName)
+	assert.Equal(t, "2026-01-01", fake.gotFrom)
+	th := crisisData(t, rec)["thresholds"].([]any)[0].(map[string]any)
+	assert.Equal(t, 25.0, th["value"])
+	assert.Equal(t, "AMBER", th["status"])
+
+	rec = httptest.NewRecorder()
+	h.Indicator(rec, httptest.NewRequest(http.MethodGet, "/api/crisis/indicators/ted", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.Indicator(rec, httptest.NewRequest(http.MethodGet, "/api/crisis/indicators/", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestCrisisErrors(t *testing.T) {
+	// 区间非法 → 400 且回显原因；库错误 → 500 且不回显内部细节
+	h := NewCrisisHandler(&fakeCrisisDashboard{err: fmt.Errorf("%w: from after to", crisis.ErrInvalidRange)})
+	rec := httptest.NewRecorder()
+	h.History(rec, httptest.NewRequest(http.MethodGet, "/api/crisis/history?from=2026-07-10&to=2026-07-01", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "from after to")
+
+	h = NewCrisisHandler(&fakeCrisisDashboard{err: errors.New("database is locked")})
+	for _, call := range []func(http.ResponseWriter, *http.Request){h.Status, h.History, h.Indicator} {
+		rec = httptest.NewRecorder()
+		call(rec, httptest.NewRequest(http.MethodGet, "/api/crisis/indicators/vix", nil))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "locked")
+	}
+}
+
+func TestCrisisNotEnabled(t *testing.T) {
+	h := NewCrisisHandler(nil)
+	for _, call := range []func(http.ResponseWriter, *http.Request){h.Status, h.History, h.Indicator} {
+		rec := httptest.NewRecorder()
+		call(rec, httptest.NewRequest(http.MethodGet, "/api/crisis/status", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), errCrisisNotEnabled)
+	}
+}
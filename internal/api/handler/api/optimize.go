@@ -0,0 +1,191 @@
+// internal/api/handler/api/optimize.go
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/newthinker/atlas/internal/api/job"
+	"github.com/newthinker/atlas/internal/api/response"
+	"github.com/newthinker/atlas/internal/backtest"
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/storage/backtestrun"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// optimizeTimeout bounds one optimization job; a search runs many backtests.
+const optimizeTimeout = 30 * time.Minute
+
+// OptimizationStore keeps finished optimizations. It is satisfied by
+// *backtestrun.SQLiteStore.
+type OptimizationStore interface {
+	SaveOptimization(ctx context.Context, r *backtest.OptimizeResult) error
+	GetOptimization(ctx context.Context, id string) (*backtest.OptimizeResult, error)
+	ListOptimizations(ctx context.Context) ([]backtestrun.OptimizationSummary, error)
+}
+
+// OptimizeRequest is the request body for starting a parameter search.
+type OptimizeRequest struct {
+	Symbol   string `json:"symbol"`
+	Strategy string `json:"strategy"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	// Params are fixed for every trial; Ranges are searched.
+	Params      map[string]any        `json:"params,omitempty"`
+	Ranges      []backtest.ParamRange `json:"ranges"`
+	Method      string                `json:"method,omitempty"`    // grid | random
+	Samples     int                   `json:"samples,omitempty"`   // random draws
+	Seed        int64                 `json:"seed,omitempty"`      // random seed
+	Objective   string                `json:"objective,omitempty"` // sharpe | cagr | return_dd
+	MaxDrawdown float64               `json:"max_drawdown,omitempty"`
+	WalkForward *backtest.WalkForward `json:"walk_forward,omitempty"`
+}
+
+// SetOptimizationStore makes finished optimizations persist to store and
+// enables the optimization list and detail endpoints.
+func (h *BacktestHandler) SetOptimizationStore(store OptimizationStore) {
+	h.optimizations = store
+}
+
+// Optimize starts a parameter search job.
+func (h *BacktestHandler) Optimize(w http.ResponseWriter, r *http.Request) {
+	var req OptimizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest,
+			core.WrapError(core.ErrConfigInvalid, err))
+		return
+	}
+	if req.Symbol == "" || req.Strategy == "" || len(req.Ranges) == 0 {
+		response.Error(w, http.StatusBadRequest,
+			core.WrapError(core.ErrConfigMissing, nil))
+		return
+	}
+
+	start, err := time.Parse("2006-01-02", req.Start)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest,
+			core.WrapError(core.ErrConfigInvalid, err))
+		return
+	}
+	end, err := time.Parse("2006-01-02", req.End)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest,
+			core.WrapError(core.ErrConfigInvalid, err))
+		return
+	}
+	method, err := backtest.ParseSearchMethod(req.Method)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest,
+			core.WrapError(core.ErrConfigInvalid, err))
+		return
+	}
+	objective, err := backtest.ParseObjective(req.Objective)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest,
+			core.WrapError(core.ErrConfigInvalid, err))
+		return
+	}
+
+	strat, ok := h.strategies.Get(req.Strategy)
+	if !ok {
+		response.Error(w, http.StatusBadRequest,
+			core.WrapError(core.ErrStrategyFailed, nil))
+		return
+	}
+
+	opt := backtest.OptimizeRequest{
+		Symbol:      req.Symbol,
+		Start:       start,
+		End:         end,
+		Params:      req.Ranges,
+		Base:        req.Params,
+		Method:      method,
+		Samples:     req.Samples,
+		Seed:        req.Seed,
+		Objective:   objective,
+		MaxDrawdown: req.MaxDrawdown,
+		WalkForward: req.WalkForward,
+	}
+
+	j := h.jobStore.Create("optimize")
+	jobID := j.ID
+	status := j.Status
+
+	go h.runOptimize(jobID, strat, opt)
+
+	response.JSON(w, http.StatusAccepted, map[string]any{
+		"job_id": jobID,
+		"status": status,
+	})
+}
+
+// runOptimize executes the search, reporting progress on the job, and stores
+// the result when a store is configured.
+func (h *BacktestHandler) runOptimize(jobID string, strat strategy.Strategy, req backtest.OptimizeRequest) {
+	h.jobStore.Update(jobID, func(j *job.Job) {
+		j.Status = job.StatusRunning
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), optimizeTimeout)
+	defer cancel()
+	result, err := h.backtester.Optimize(ctx, strat, req, func(done, total int) {
+		h.jobStore.Update(jobID, func(j *job.Job) {
+			// 100 is reserved for the stored, complete job.
+			j.Progress = done * 99 / total
+		})
+	})
+	if err == nil && h.optimizations != nil {
+		err = h.optimizations.SaveOptimization(ctx, result)
+	}
+	if err != nil {
+		h.jobStore.Update(jobID, func(j *job.Job) {
+			j.Status = job.StatusFailed
+			j.Error = core.WrapError(core.ErrStrategyFailed, err)
+		})
+		return
+	}
+
+	h.jobStore.Update(jobID, func(j *job.Job) {
+		j.Status = job.StatusComplete
+		j.Progress = 100
+		j.Result = result
+	})
+}
+
+// ListOptimizations returns summaries of the stored optimizations, newest
+// first.
+func (h *BacktestHandler) ListOptimizations(w http.ResponseWriter, r *http.Request) {
+	if h.optimizations == nil {
+		response.Error(w, http.StatusServiceUnavailable,
+			core.WrapError(core.ErrConfigMissing, errors.New("optimization store not configured")))
+		return
+	}
+	list, err := h.optimizations.ListOptimizations(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err)
+		return
+	}
+	response.JSON(w, http.StatusOK, list)
+}
+
+// GetOptimization returns one stored optimization with all its trials.
+func (h *BacktestHandler) GetOptimization(w http.ResponseWriter, r *http.Request, id string) {
+	if h.optimizations == nil {
+		response.Error(w, http.StatusServiceUnavailable,
+			core.WrapError(core.ErrConfigMissing, errors.New("optimization store not configured")))
+		return
+	}
+	result, err := h.optimizations.GetOptimization(r.Context(), id)
+	if errors.Is(err, backtestrun.ErrOptimizationNotFound) {
+		response.Error(w, http.StatusNotFound, core.WrapError(core.ErrNoData, err))
+		return
+	}
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err)
+		return
+	}
+	response.JSON(w, http.StatusOK, result)
+}
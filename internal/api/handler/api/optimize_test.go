@@ -0,0 +1,176 @@
+// internal/api/handler/api/optimize_test.go
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/api/job"
+	"github.com/newthinker/atlas/internal/backtest"
+	"github.com/newthinker/atlas/internal/storage/backtestrun"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// periodStrategy rejects a non-positive "period" param.
+type periodStrategy struct {
+	MockStrategy
+	period int
+}
+
+func (s *periodStrategy) Name() string { return "period" }
+func (s *periodStrategy) Init(cfg strategy.Config) error {
+	if v, ok := strategy.IntParam(cfg.Params, "period"); ok {
+		s.period = v
+	}
+	if s.period <= 0 {
+		return errors.New("period must be positive")
+	}
+	return nil
+}
+
+// memOptimizations is an in-memory OptimizationStore.
+type memOptimizations struct {
+	results map[string]*backtest.OptimizeResult
+}
+
+func (m *memOptimizations) SaveOptimization(ctx context.Context, r *backtest.OptimizeResult) error {
+	r.ID = "opt-1"
+	m.results[r.ID] = r
+	return nil
+}
+
+func (m *memOptimizations) GetOptimization(ctx context.Context, id string) (*backtest.OptimizeResult, error) {
+	r, ok := m.results[id]
+	if !ok {
+		return nil, backtestrun.ErrOptimizationNotFound
+	}
+	return r, nil
+}
+
+func (m *memOptimizations) ListOptimizations(ctx context.Context) ([]backtestrun.OptimizationSummary, error) {
+	var out []backtestrun.OptimizationSummary
+	for _, r := range m.results {
+		out = append(out, backtestrun.OptimizationSummary{ID: r.ID, Strategy: r.Strategy})
+	}
+	return out, nil
+}
+
+func waitForJob(t *testing.T, store *job.Store, id string) *job.Job {
+	t.Helper()
+	for i := 0; i < 200; i++ {
+		j, err := store.Get(id)
+		if err != nil {
+			t.Fatalf("failed to get job: %v", err)
+		}
+		if j.Status == job.StatusComplete || j.Status == job.StatusFailed {
+			return j
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("job did not finish")
+	return nil
+}
+
+func jobID(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	id, _ := resp.Data["job_id"].(string)
+	return id
+}
+
+func TestBacktestHandler_Create_InvalidParams(t *testing.T) {
+	strategies := strategy.NewEngine()
+	strategies.Register(&periodStrategy{period: 5})
+	handler := NewBacktestHandler(job.NewStore(100, time.Hour), backtest.New(&MockOHLCVProvider{}), strategies)
+
+	body := bytes.NewBufferString(`{"symbol": "AAPL", "strategy": "period",
+		"start": "2023-01-01", "end": "2024-01-01", "params": {"period": -1}}`)
+	w := httptest.NewRecorder()
+	handler.Create(w, httptest.NewRequest("POST", "/api/v1/backtest", body))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestBacktestHandler_Optimize(t *testing.T) {
+	jobStore := job.NewStore(100, time.Hour)
+	strategies := strategy.NewEngine()
+	strategies.Register(&periodStrategy{period: 5})
+	handler := NewBacktestHandler(jobStore, backtest.New(&MockOHLCVProvider{}), strategies)
+	store := &memOptimizations{results: map[string]*backtest.OptimizeResult{}}
+	handler.SetOptimizationStore(store)
+
+	body := bytes.NewBufferString(`{"symbol": "AAPL", "strategy": "period",
+		"start": "2023-01-01", "end": "2024-01-01",
+		"ranges": [{"name": "period", "min": 0, "max": 2, "step": 1, "integer": true}],
+		"objective": "cagr"}`)
+	w := httptest.NewRecorder()
+	handler.Optimize(w, httptest.NewRequest("POST", "/api/v1/backtest/optimize", body))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", w.Code, w.Body)
+	}
+
+	j := waitForJob(t, jobStore, jobID(t, w))
+	if j.Type != "optimize" || j.Status != job.StatusComplete || j.Progress != 100 {
+		t.Fatalf("job = %+v", j)
+	}
+	res := j.Result.(*backtest.OptimizeResult)
+	if len(res.Trials) != 3 || res.Objective != backtest.ObjectiveCAGR {
+		t.Errorf("result = %+v", res)
+	}
+	if _, ok := store.results["opt-1"]; !ok {
+		t.Error("result was not stored")
+	}
+
+	w = httptest.NewRecorder()
+	handler.GetOptimization(w, httptest.NewRequest("GET", "/api/v1/backtest/optimizations/opt-1", nil), "opt-1")
+	if w.Code != http.StatusOK {
+		t.Errorf("get: expected 200, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	handler.GetOptimization(w, httptest.NewRequest("GET", "/api/v1/backtest/optimizations/nope", nil), "nope")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown id: expected 404, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	handler.ListOptimizations(w, httptest.NewRequest("GET", "/api/v1/backtest/optimizations", nil))
+	if w.Code != http.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte("opt-1")) {
+		t.Errorf("list: %d %s", w.Code, w.Body)
+	}
+}
+
+func TestBacktestHandler_Optimize_BadRequest(t *testing.T) {
+	strategies := strategy.NewEngine()
+	strategies.Register(&MockStrategy{})
+	handler := NewBacktestHandler(job.NewStore(100, time.Hour), backtest.New(&MockOHLCVProvider{}), strategies)
+
+	for name, body := range map[string]string{
+		"no ranges":     `{"symbol": "AAPL", "strategy": "mock", "start": "2023-01-01", "end": "2024-01-01"}`,
+		"bad objective": `{"symbol": "AAPL", "strategy": "mock", "start": "2023-01-01", "end": "2024-01-01", "ranges": [{"name": "x", "values": [1]}], "objective": "luck"}`,
+	} {
+		w := httptest.NewRecorder()
+		handler.Optimize(w, httptest.NewRequest("POST", "/api/v1/backtest/optimize", bytes.NewBufferString(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", name, w.Code)
+		}
+	}
+
+	// Without a store the saved results are unavailable rather than empty.
+	w := httptest.NewRecorder()
+	handler.ListOptimizations(w, httptest.NewRequest("GET", "/api/v1/backtest/optimizations", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("no store: expected 503, got %d", w.Code)
+	}
+}
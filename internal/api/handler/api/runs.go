@@ -0,0 +1,163 @@
+// internal/api/handler/api/runs.go
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/newthinker/atlas/internal/api/response"
+	"github.com/newthinker/atlas/internal/backtest"
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/storage/backtestrun"
+)
+
+// RunStore keeps finished backtest runs. It is satisfied by
+// *backtestrun.SQLiteStore.
+type RunStore interface {
+	Save(ctx context.Context, cfg backtestrun.Config, result *backtest.Result) (string, error)
+	Get(ctx context.Context, id string) (*backtestrun.Run, error)
+	List(ctx context.Context, filter backtestrun.ListFilter) ([]backtestrun.Summary, error)
+}
+
+// savedRun records where a job's result was stored.
+type savedRun struct {
+	id  string
+	err error
+}
+
+// SetRunStore makes finished backtests persist to store and enables the run
+// history endpoints.
+func (h *BacktestHandler) SetRunStore(store RunStore) {
+	h.runs = store
+}
+
+// saveRun stores a finished job's result. A failed save does not fail the
+// job: the result is still returned, with a warning.
+func (h *BacktestHandler) saveRun(ctx context.Context, jobID string, req BacktestRequest, result *backtest.Result) {
+	id, err := h.runs.Save(ctx, backtestrun.Config{
+		Strategy: req.Strategy,
+		Symbol:   req.Symbol,
+		Start:    req.Start,
+		End:      req.End,
+		Params:   req.Params,
+	}, result)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	// Forget jobs the job store has already expired.
+	for k := range h.saved {
+		if _, err := h.jobStore.Get(k); err != nil {
+			delete(h.saved, k)
+		}
+	}
+	h.saved[jobID] = savedRun{id: id, err: err}
+}
+
+func (h *BacktestHandler) savedRun(jobID string) (savedRun, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.saved[jobID]
+	return s, ok
+}
+
+// requireRuns answers 503 when no run store is configured.
+func (h *BacktestHandler) requireRuns(w http.ResponseWriter) bool {
+	if h.runs == nil {
+		response.Error(w, http.StatusServiceUnavailable,
+			core.WrapError(core.ErrConfigMissing, errors.New("backtest run store not configured")))
+		return false
+	}
+	return true
+}
+
+// ListRuns returns stored run summaries, newest first, filtered by the
+// strategy, symbol and limit query parameters.
+func (h *BacktestHandler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRuns(w) {
+		return
+	}
+	q := r.URL.Query()
+	filter := backtestrun.ListFilter{Strategy: q.Get("strategy"), Symbol: q.Get("symbol"), Limit: 50}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			response.Error(w, http.StatusBadRequest,
+				core.WrapError(core.ErrConfigInvalid, errors.New("limit must be a non-negative integer")))
+			return
+		}
+		filter.Limit = n
+	}
+	runs, err := h.runs.List(r.Context(), filter)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err)
+		return
+	}
+	response.JSON(w, http.StatusOK, runs)
+}
+
+// GetRun returns one stored run with its trades and equity curve.
+func (h *BacktestHandler) GetRun(w http.ResponseWriter, r *http.Request, id string) {
+	if !h.requireRuns(w) {
+		return
+	}
+	run, ok := h.loadRun(w, r, id)
+	if !ok {
+		return
+	}
+	response.JSON(w, http.StatusOK, run)
+}
+
+// CompareRuns lines up runs a and b (query parameters) side by side.
+func (h *BacktestHandler) CompareRuns(w http.ResponseWriter, r *http.Request) {
+	if !h.requireRuns(w) {
+		return
+	}
+	aID, bID := r.URL.Query().Get("a"), r.URL.Query().Get("b")
+	if aID == "" || bID == "" {
+		response.Error(w, http.StatusBadRequest,
+			core.WrapError(core.ErrConfigMissing, errors.New("compare needs run ids a and b")))
+		return
+	}
+	a, ok := h.loadRun(w, r, aID)
+	if !ok {
+		return
+	}
+	b, ok := h.loadRun(w, r, bID)
+	if !ok {
+		return
+	}
+	response.JSON(w, http.StatusOK, backtestrun.Compare(a, b))
+}
+
+// Rerun starts a new backtest job with a stored run's configuration.
+func (h *BacktestHandler) Rerun(w http.ResponseWriter, r *http.Request, id string) {
+	if !h.requireRuns(w) {
+		return
+	}
+	run, ok := h.loadRun(w, r, id)
+	if !ok {
+		return
+	}
+	h.start(w, BacktestRequest{
+		Symbol:   run.Config.Symbol,
+		Strategy: run.Config.Strategy,
+		Start:    run.Config.Start,
+		End:      run.Config.End,
+		Params:   run.Config.Params,
+	})
+}
+
+// loadRun fetches a run, answering 404 or 500 itself when it cannot.
+func (h *BacktestHandler) loadRun(w http.ResponseWriter, r *http.Request, id string) (*backtestrun.Run, bool) {
+	run, err := h.runs.Get(r.Context(), id)
+	if errors.Is(err, backtestrun.ErrNotFound) {
+		response.Error(w, http.StatusNotFound, core.WrapError(core.ErrNoData, err))
+		return nil, false
+	}
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	return run, true
+}
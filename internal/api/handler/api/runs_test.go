@@ -0,0 +1,166 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/api/job"
+	"github.com/newthinker/atlas/internal/api/response"
+	"github.com/newthinker/atlas/internal/backtest"
+	"github.com/newthinker/atlas/internal/storage/backtestrun"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+func newRunsHandler(t *testing.T) (*BacktestHandler, *backtestrun.SQLiteStore) {
+	t.Helper()
+	store, err := backtestrun.NewSQLiteStore(filepath.Join(t.TempDir(), "backtests.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+
+	strategies := strategy.NewEngine()
+	strategies.Register(&MockStrategy{})
+	h := NewBacktestHandler(job.NewStore(100, time.Hour), backtest.New(&MockOHLCVProvider{}), strategies)
+	h.SetRunStore(store)
+	return h, store
+}
+
+// finishJob polls a job until it is done and returns its final status body.
+func finishJob(t *testing.T, h *BacktestHandler, jobID string) map[string]any {
+	t.Helper()
+	for i := 0; i < 200; i++ {
+		w := httptest.NewRecorder()
+		h.GetStatus(w, httptest.NewRequest("GET", "/api/v1/backtest/"+jobID, nil), jobID)
+		var resp response.SuccessResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		data := resp.Data.(map[string]any)
+		if data["status"] == string(job.StatusComplete) || data["status"] == string(job.StatusFailed) {
+			return data
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", jobID)
+	return nil
+}
+
+func startJob(t *testing.T, h *BacktestHandler, body string) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.Create(w, httptest.NewRequest("POST", "/api/v1/backtest", bytes.NewBufferString(body)))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp response.SuccessResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Data.(map[string]any)["job_id"].(string)
+}
+
+func TestBacktestHandler_SavesRun(t *testing.T) {
+	h, store := newRunsHandler(t)
+
+	jobID := startJob(t, h, `{"symbol":"AAPL","strategy":"mock","start":"2023-01-01","end":"2024-01-01"}`)
+	data := finishJob(t, h, jobID)
+	runID, _ := data["run_id"].(string)
+	if runID == "" {
+		t.Fatalf("expected run_id in finished job, got %v", data)
+	}
+
+	run, err := store.Get(t.Context(), runID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if run.Config.Symbol != "AAPL" || run.Config.Strategy != "mock" || run.Config.Start != "2023-01-01" {
+		t.Errorf("stored config = %+v", run.Config)
+	}
+}
+
+func TestBacktestHandler_ListGetCompareRuns(t *testing.T) {
+	h, _ := newRunsHandler(t)
+	a := finishJob(t, h, startJob(t, h, `{"symbol":"AAPL","strategy":"mock","start":"2023-01-01","end":"2024-01-01"}`))["run_id"].(string)
+	b := finishJob(t, h, startJob(t, h, `{"symbol":"MSFT","strategy":"mock","start":"2023-01-01","end":"2024-01-01"}`))["run_id"].(string)
+
+	w := httptest.NewRecorder()
+	h.ListRuns(w, httptest.NewRequest("GET", "/api/v1/backtest/runs?symbol=MSFT", nil))
+	var list struct {
+		Data []backtestrun.Summary `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &list)
+	if w.Code != http.StatusOK || len(list.Data) != 1 || list.Data[0].ID != b {
+		t.Errorf("list = %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	h.GetRun(w, httptest.NewRequest("GET", "/api/v1/backtest/runs/"+a, nil), a)
+	if w.Code != http.StatusOK {
+		t.Errorf("get: expected 200, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h.CompareRuns(w, httptest.NewRequest("GET", "/api/v1/backtest/runs/compare?a="+a+"&b="+b, nil))
+	var cmp struct {
+		Data backtestrun.Comparison `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &cmp)
+	if w.Code != http.StatusOK || cmp.Data.A.ID != a || cmp.Data.B.ID != b || len(cmp.Data.Metrics) == 0 {
+		t.Errorf("compare = %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	h.CompareRuns(w, httptest.NewRequest("GET", "/api/v1/backtest/runs/compare?a="+a, nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("compare without b: expected 400, got %d", w.Code)
+	}
+}
+
+func TestBacktestHandler_Rerun(t *testing.T) {
+	h, store := newRunsHandler(t)
+	first := finishJob(t, h, startJob(t, h, `{"symbol":"AAPL","strategy":"mock","start":"2023-01-01","end":"2024-01-01"}`))["run_id"].(string)
+
+	w := httptest.NewRecorder()
+	h.Rerun(w, httptest.NewRequest("POST", "/api/v1/backtest/runs/"+first+"/rerun", nil), first)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp response.SuccessResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	second, _ := finishJob(t, h, resp.Data.(map[string]any)["job_id"].(string))["run_id"].(string)
+	if second == "" || second == first {
+		t.Fatalf("expected a new run, got %q", second)
+	}
+
+	run, _ := store.Get(t.Context(), second)
+	if run.Config.Symbol != "AAPL" || run.Config.End != "2024-01-01" {
+		t.Errorf("rerun config = %+v", run.Config)
+	}
+}
+
+func TestBacktestHandler_RunNotFound(t *testing.T) {
+	h, _ := newRunsHandler(t)
+
+	w := httptest.NewRecorder()
+	h.GetRun(w, httptest.NewRequest("GET", "/api/v1/backtest/runs/run_missing", nil), "run_missing")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("get: expected 404, got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	h.Rerun(w, httptest.NewRequest("POST", "/api/v1/backtest/runs/run_missing/rerun", nil), "run_missing")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("rerun: expected 404, got %d", w.Code)
+	}
+}
+
+func TestBacktestHandler_RunsWithoutStore(t *testing.T) {
+	h := NewBacktestHandler(job.NewStore(100, time.Hour), backtest.New(&MockOHLCVProvider{}), strategy.NewEngine())
+
+	w := httptest.NewRecorder()
+	h.ListRuns(w, httptest.NewRequest("GET", "/api/v1/backtest/runs", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", w.Code)
+	}
+}
@@ -0,0 +1,56 @@
+// internal/api/handler/api/strategies.go
+package api
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/newthinker/atlas/internal/api/response"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// StrategyLister provides the registered strategies.
+type StrategyLister interface {
+	GetAll() []strategy.Strategy
+}
+
+// StrategyInfo describes a registered strategy and the params it accepts.
+type StrategyInfo struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Params      []strategy.Param `json:"params"`
+}
+
+// StrategiesHandler handles strategy introspection requests.
+type StrategiesHandler struct {
+	strategies StrategyLister
+}
+
+// NewStrategiesHandler creates a new strategies handler.
+func NewStrategiesHandler(strategies StrategyLister) *StrategiesHandler {
+	return &StrategiesHandler{strategies: strategies}
+}
+
+// List returns every registered strategy with its param schema. Defaults are
+// the configured values, i.e. what a backtest uses when a param is not given.
+func (h *StrategiesHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	infos := []StrategyInfo{}
+	if h.strategies != nil {
+		for _, s := range h.strategies.GetAll() {
+			params, _ := strategy.ParamsOf(s)
+			if params == nil {
+				params = []strategy.Param{}
+			}
+			infos = append(infos, StrategyInfo{Name: s.Name(), Description: s.Description(), Params: params})
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	response.JSON(w, http.StatusOK, map[string]any{
+		"strategies": infos,
+		"count":      len(infos),
+	})
+}
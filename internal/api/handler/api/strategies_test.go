@@ -0,0 +1,57 @@
+// internal/api/handler/api/strategies_test.go
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/newthinker/atlas/internal/strategy"
+	"github.com/newthinker/atlas/internal/strategy/bollinger"
+	"github.com/newthinker/atlas/internal/strategy/rsi"
+)
+
+func TestStrategiesHandler_List(t *testing.T) {
+	engine := strategy.NewEngine()
+	engine.Register(rsi.New(21, 25, 75))
+	engine.Register(bollinger.New(20, 2, bollinger.ModeBreakout))
+	handler := NewStrategiesHandler(engine)
+
+	w := httptest.NewRecorder()
+	handler.List(w, httptest.NewRequest("GET", "/api/v1/strategies", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var resp struct {
+		Data struct {
+			Strategies []StrategyInfo `json:"strategies"`
+			Count      int            `json:"count"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding: %v", err)
+	}
+	got := resp.Data.Strategies
+	if resp.Data.Count != 2 || got[0].Name != "bollinger" || got[1].Name != "rsi" {
+		t.Fatalf("strategies = %+v", got)
+	}
+
+	period := got[1].Params[0]
+	if period.Name != "period" || period.Type != strategy.ParamInt || period.Default != 21.0 || *period.Min != 1 {
+		t.Errorf("rsi period = %+v, want the configured default 21", period)
+	}
+	mode := got[0].Params[2]
+	if mode.Default != "breakout" || len(mode.Options) != 2 {
+		t.Errorf("bollinger mode = %+v", mode)
+	}
+}
+
+func TestStrategiesHandler_MethodNotAllowed(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewStrategiesHandler(strategy.NewEngine()).List(w, httptest.NewRequest("POST", "/api/v1/strategies", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
@@ -2,21 +2,157 @@
 package web
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/newthinker/atlas/internal/backtest"
+	"github.com/newthinker/atlas/internal/storage/backtestrun"
 )
 
+// backtestHistoryLimit caps the run history shown on the backtest page.
+const backtestHistoryLimit = 50
+
+// BacktestRunStore is the read side of the backtest run history; it is
+// satisfied by *backtestrun.SQLiteStore.
+type BacktestRunStore interface {
+	Get(ctx context.Context, id string) (*backtestrun.Run, error)
+	List(ctx context.Context, filter backtestrun.ListFilter) ([]backtestrun.Summary, error)
+}
+
+// RunRow is one line of the run history.
+type RunRow struct {
+	backtestrun.Summary
+	Params string
+}
+
 type BacktestData struct {
-	Title  string
-	Result *backtest.Result
+	Title      string
+	Strategies []string
+	Forms      []StrategyForm // param form of each strategy
+	Result     *backtest.Result
+	// Run is the stored run being shown, nil for a fresh page.
+	Run        *backtestrun.Run
+	RunParams  string
+	Runs       []RunRow
+	Comparison *backtestrun.Comparison
+	// HasHistory is false when no run store is configured.
+	HasHistory bool
+}
+
+// SetBacktestRuns sets the store behind the run history, run view and
+// comparison on the backtest page.
+func (h *Handler) SetBacktestRuns(s BacktestRunStore) {
+	h.backtestRuns = s
 }
 
+// Backtest renders the backtest form and run history. ?run=ID shows a stored
+// run; ?a=ID&b=ID compares two side by side.
 func (h *Handler) Backtest(w http.ResponseWriter, r *http.Request) {
 	data := BacktestData{
-		Title:  "Backtest",
-		Result: nil,
+		Title:      "Backtest",
+		Strategies: []string{"ma_crossover", "pe_band", "dividend_yield"},
+		Forms:      h.strategyForms(),
+		HasHistory: h.backtestRuns != nil,
+	}
+	if h.strategyProvider != nil {
+		if names := h.strategyProvider.GetStrategyNames(); len(names) > 0 {
+			sort.Strings(names)
+			data.Strategies = names
+		}
+	}
+
+	if h.backtestRuns != nil {
+		ctx := r.Context()
+		q := r.URL.Query()
+		switch {
+		case q.Get("a") != "" && q.Get("b") != "":
+			a, err := h.backtestRuns.Get(ctx, q.Get("a"))
+			if err != nil {
+				runError(w, q.Get("a"), err)
+				return
+			}
+			b, err := h.backtestRuns.Get(ctx, q.Get("b"))
+			if err != nil {
+				runError(w, q.Get("b"), err)
+				return
+			}
+			c := backtestrun.Compare(a, b)
+			data.Comparison = &c
+		case q.Get("run") != "":
+			run, err := h.backtestRuns.Get(ctx, q.Get("run"))
+			if err != nil {
+				runError(w, q.Get("run"), err)
+				return
+			}
+			data.Run = run
+			data.RunParams = formatRunParams(run.Config.Params)
+			data.Result = run.Result
+		}
+
+		runs, err := h.backtestRuns.List(ctx, backtestrun.ListFilter{Limit: backtestHistoryLimit})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, run := range runs {
+			data.Runs = append(data.Runs, RunRow{Summary: run, Params: formatRunParams(run.Config.Params)})
+		}
 	}
 
 	h.render(w, "backtest.html", data)
 }
+
+// BacktestReport downloads the HTML tearsheet of the stored run ?run=ID.
+func (h *Handler) BacktestReport(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("run")
+	if h.backtestRuns == nil || id == "" {
+		http.NotFound(w, r)
+		return
+	}
+	run, err := h.backtestRuns.Get(r.Context(), id)
+	if err != nil {
+		runError(w, id, err)
+		return
+	}
+	html, err := backtest.RenderReportHTML(run.Result)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	name := fmt.Sprintf("backtest-%s-%s-%s.html", run.Config.Strategy, run.Config.Symbol, run.ID)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	w.Write([]byte(html))
+}
+
+// runError answers a failed run lookup: 404 for an unknown ID, 500 otherwise.
+func runError(w http.ResponseWriter, id string, err error) {
+	if errors.Is(err, backtestrun.ErrNotFound) {
+		http.Error(w, fmt.Sprintf("backtest run %s not found", id), http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// formatRunParams renders run params as sorted name=value pairs, "default"
+// when the run used the strategy's configured params.
+func formatRunParams(params map[string]any) string {
+	if len(params) == 0 {
+		return "default"
+	}
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%v", k, params[k])
+	}
+	return strings.Join(parts, " ")
+}
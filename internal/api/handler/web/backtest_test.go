@@ -0,0 +1,150 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/backtest"
+	"github.com/newthinker/atlas/internal/storage/backtestrun"
+)
+
+func newTestRunStore(t *testing.T) (*backtestrun.SQLiteStore, string, string) {
+	t.Helper()
+	s, err := backtestrun.NewSQLiteStore(filepath.Join(t.TempDir(), "backtests.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	ctx := context.Background()
+	a, err := s.Save(ctx, backtestrun.Config{Strategy: "ma_crossover", Symbol: "AAPL", Start: "2024-01-01", End: "2024-12-31",
+		Params: map[string]any{"slow_period": 20}},
+		&backtest.Result{Strategy: "ma_crossover", Symbol: "AAPL", Stats: backtest.Stats{TotalTrades: 3, TotalReturn: 8},
+			Equity: []backtest.EquityPoint{
+				{Time: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), Equity: 1000, Close: 100},
+				{Time: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), Equity: 1080, Close: 108},
+			},
+			Trades: []backtest.Trade{{EntryPrice: 100, Return: 0.08}}})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	b, err := s.Save(ctx, backtestrun.Config{Strategy: "ma_crossover", Symbol: "MSFT", Start: "2024-01-01", End: "2024-12-31",
+		Params: map[string]any{"slow_period": 50}},
+		&backtest.Result{Strategy: "ma_crossover", Symbol: "MSFT", Stats: backtest.Stats{TotalTrades: 5, TotalReturn: 11}})
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	return s, a, b
+}
+
+func getBacktest(t *testing.T, h *Handler, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.Backtest(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestBacktest_ListsRunHistory(t *testing.T) {
+	store, _, _ := newTestRunStore(t)
+	h := newTestHandler(t)
+	h.SetBacktestRuns(store)
+
+	rec := getBacktest(t, h, "/backtest")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "Run History") || !strings.Contains(body, "slow_period=50") {
+		t.Errorf("expected run history with params, got: %s", body)
+	}
+	if strings.Index(body, ">MSFT<") > strings.Index(body, ">AAPL<") {
+		t.Errorf("expected MSFT (newest) before AAPL")
+	}
+}
+
+func TestBacktest_ShowsStoredRun(t *testing.T) {
+	store, a, _ := newTestRunStore(t)
+	h := newTestHandler(t)
+	h.SetBacktestRuns(store)
+
+	rec := getBacktest(t, h, "/backtest?run="+a)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "Results: ma_crossover on AAPL") || !strings.Contains(body, `data-rerun="`+a+`"`) ||
+		!strings.Contains(body, "/backtest/report?run="+a) {
+		t.Errorf("expected stored run with re-run button and report link, got: %s", body)
+	}
+	if !strings.Contains(body, "8.00%") {
+		t.Errorf("expected the trade return rendered as a percentage, got: %s", body)
+	}
+}
+
+func TestBacktest_ComparesRuns(t *testing.T) {
+	store, a, b := newTestRunStore(t)
+	h := newTestHandler(t)
+	h.SetBacktestRuns(store)
+
+	rec := getBacktest(t, h, "/backtest?a="+a+"&b="+b)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"Comparison", "&#43;3.00%", "param slow_period"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in comparison, got: %s", want, body)
+		}
+	}
+}
+
+func TestBacktest_UnknownRun(t *testing.T) {
+	store, _, _ := newTestRunStore(t)
+	h := newTestHandler(t)
+	h.SetBacktestRuns(store)
+
+	if rec := getBacktest(t, h, "/backtest?run=run_missing"); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestBacktest_NoStoreHidesHistory(t *testing.T) {
+	h := newTestHandler(t)
+
+	rec := getBacktest(t, h, "/backtest?run=anything")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 with no store, got %d", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "Run History") {
+		t.Errorf("expected no run history without a store")
+	}
+}
+
+func TestBacktestReport_DownloadsTearsheet(t *testing.T) {
+	store, a, _ := newTestRunStore(t)
+	h := newTestHandler(t)
+	h.SetBacktestRuns(store)
+
+	rec := httptest.NewRecorder()
+	h.BacktestReport(rec, httptest.NewRequest(http.MethodGet, "/backtest/report?run="+a, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment") || !strings.Contains(cd, a+".html") {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+	if !strings.Contains(rec.Body.String(), "Backtest: ma_crossover on AAPL") {
+		t.Errorf("expected the tearsheet, got: %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.BacktestReport(rec, httptest.NewRequest(http.MethodGet, "/backtest/report?run=run_missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown run: expected 404, got %d", rec.Code)
+	}
+}
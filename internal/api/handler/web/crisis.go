@@ -0,0 +1,107 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/newthinker/atlas/internal/crisis"
+)
+
+// CrisisProvider supplies the crisis monitor page; *crisis.Dashboard
+// satisfies it.
+type CrisisProvider interface {
+	Status(ctx context.Context) (*crisis.StatusView, error)
+	History(ctx context.Context, from, to string) (*crisis.HistoryView, error)
+	Charts(ctx context.Context, from, to string) (*crisis.ChartsView, error)
+}
+
+// SetCrisisProvider wires the crisis monitor page.
+func (h *Handler) SetCrisisProvider(p CrisisProvider) {
+	h.crisisProvider = p
+}
+
+// crisisStateClass maps a system state to its banner colors (same palette as
+// the replay report: green / yellow / orange / red).
+func crisisStateClass(s crisis.SystemState) string {
+	switch s {
+	case crisis.StateWatch:
+		return "bg-yellow-100 text-yellow-800 border-yellow-400"
+	case crisis.StateBrewing:
+		return "bg-orange-100 text-orange-800 border-orange-400"
+	case crisis.StateCrisis:
+		return "bg-red-100 text-red-800 border-red-500"
+	}
+	return "bg-green-100 text-green-800 border-green-500"
+}
+
+// crisisStatusClass maps an indicator status to its badge colors.
+func crisisStatusClass(s crisis.Status) string {
+	switch s {
+	case crisis.StatusRed:
+		return "bg-red-100 text-red-700"
+	case crisis.StatusAmber:
+		return "bg-yellow-100 text-yellow-800"
+	case crisis.StatusGreen:
+		return "bg-green-100 text-green-700"
+	}
+	return "bg-gray-100 text-gray-600"
+}
+
+// crisisRow is one rendered indicator row of the status table.
+type crisisRow struct {
+	crisis.IndicatorStatus
+	Class string
+	Pct   string // "—" = 不展示
+}
+
+// Crisis renders the crisis monitor page at /crisis?from=&to=: current
+// state, indicator readings, the state timeline with its transitions and the
+// indicator charts.
+func (h *Handler) Crisis(w http.ResponseWriter, r *http.Request) {
+	if h.crisisProvider == nil {
+		http.Error(w, "crisis monitor not enabled", http.StatusNotFound)
+		return
+	}
+	ctx := r.Context()
+	q := r.URL.Query()
+	hist, err := h.crisisProvider.History(ctx, q.Get("from"), q.Get("to"))
+	if errors.Is(err, crisis.ErrInvalidRange) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	status, err := h.crisisProvider.Status(ctx)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	charts, err := h.crisisProvider.Charts(ctx, hist.From, hist.To)
+	if err != nil {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	rows := make([]crisisRow, 0, len(status.Indicators))
+	for _, ind := range status.Indicators {
+		row := crisisRow{IndicatorStatus: ind, Class: crisisStatusClass(ind.Status), Pct: "—"}
+		if ind.Pct5y != nil {
+			row.Pct = fmtVal(*ind.Pct5y*100, 0) + "%"
+		}
+		rows = append(rows, row)
+	}
+	// 迁移表新在前，与通知里"最近一次变化"的读法一致
+	transitions := make([]crisis.Transition, 0, len(hist.Transitions))
+	for i := len(hist.Transitions) - 1; i >= 0; i-- {
+		transitions = append(transitions, hist.Transitions[i])
+	}
+	h.render(w, "crisis.html", map[string]any{
+		"Title": "Cassandra 危机监控", "Status": status,
+		"StateClass": crisisStateClass(status.State), "Rows": rows,
+		"From": hist.From, "To": hist.To, "Days": len(hist.Days),
+		"Transitions": transitions, "Timeline": charts.Timeline, "Charts": charts.Charts,
+	})
+}
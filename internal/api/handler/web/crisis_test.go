@@ -0,0 +1,96 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newthinker/atlas/internal/crisis"
+)
+
+type fakeCrisis struct {
+	err error // History() 错误注入
+}
+
+func (f fakeCrisis) Status(context.Context) (*crisis.StatusView, error) {
+	pct := 0.93
+	return &crisis.StatusView{Date: "2026-07-10", State: crisis.StateBrewing, StateDays: 4, AmberCount: 3,
+		Indicators: []crisis.IndicatorStatus{
+			{Name: "vix", Layer: "情绪", Status: crisis.StatusRed, Reading: "35.0", Pct5y: &pct, Tag: crisis.TagStress},
+			{Name: "sofr_effr", Layer: "流动性", Status: crisis.StatusGreen, Reading: "2bp"},
+		}}, nil
+}
+
+func (f fakeCrisis) History(_ context.Context, from, to string) (*crisis.HistoryView, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &crisis.HistoryView{From: "2025-07-10", To: "2026-07-10", Days: make([]crisis.HistoryDay, 2),
+		Transitions: []crisis.Transition{
+			{Date: "2026-07-01", From: crisis.StateNormal, To: crisis.StateWatch},
+			{Date: "2026-07-07", From: crisis.StateWatch, To: crisis.StateBrewing},
+		}}, nil
+}
+
+func (f fakeCrisis) Charts(_ context.Context, from, to string) (*crisis.ChartsView, error) {
+	return &crisis.ChartsView{From: from, To: to, Timeline: template.HTML(`<svg class="timeline"></svg>`),
+		Charts: []crisis.Chart{
+			{Name: "vix", SVG: template.HTML(`<svg class="chart" id="vix"></svg>`)},
+			{Name: "move", Note: "区间内无观测数据"},
+		}}, nil
+}
+
+func newTestCrisisHandler(t *testing.T, p CrisisProvider) *Handler {
+	t.Helper()
+	h, err := NewHandlerWithFS(TemplateFS())
+	require.NoError(t, err)
+	h.SetCrisisProvider(p)
+	return h
+}
+
+func TestCrisisPage(t *testing.T) {
+	h := newTestCrisisHandler(t, fakeCrisis{})
+	rec := httptest.NewRecorder()
+	h.Crisis(rec, httptest.NewRequest(http.MethodGet, "/crisis", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+
+	assert.Contains(t, body, "BREWING")
+	assert.Contains(t, body, "已持续 4 个评估日")
+	assert.Contains(t, body, "93%")                                // vix 分位
+	assert.Contains(t, body, "—")                                  // sofr_effr 不展示分位
+	assert.Contains(t, body, `<svg class="chart" id="vix"></svg>`) // SVG 原样嵌入，不转义
+	assert.Contains(t, body, "区间内无观测数据")
+	assert.Contains(t, body, `value="2025-07-10"`) // 表单回填默认区间
+	// 迁移表新在前
+	assert.Less(t, strings.Index(body, "WATCH → BREWING"), strings.Index(body, "NORMAL → WATCH"))
+}
+
+func TestCrisisPageErrors(t *testing.T) {
+	h := newTestCrisisHandler(t, fakeCrisis{err: fmt.Errorf("%w: from after to", crisis.ErrInvalidRange)})
+	rec := httptest.NewRecorder()
+	h.Crisis(rec, httptest.NewRequest(http.MethodGet, "/crisis?from=2026-07-10&to=2026-07-01", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	h = newTestCrisisHandler(t, fakeCrisis{err: errors.New("database is locked")})
+	rec = httptest.NewRecorder()
+	h.Crisis(rec, httptest.NewRequest(http.MethodGet, "/crisis", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "locked")
+}
+
+func TestCrisisPageNotEnabled(t *testing.T) {
+	h, err := NewHandlerWithFS(TemplateFS())
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	h.Crisis(rec, httptest.NewRequest(http.MethodGet, "/crisis", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -30,7 +30,7 @@ var staticFS embed.FS
 var pageNames = []string{
 	"dashboard.html", "signals.html", "watchlist.html", "backtest.html", "settings.html",
 	"symbol_detail.html", "prism_board.html", "prism_detail.html", "prism_compare.html",
-	"prism_sankey.html", "prism_fundamental.html",
+	"prism_sankey.html", "prism_fundamental.html", "crisis.html",
 }
 
 // WatchlistItemData represents a watchlist item with metadata
@@ -81,9 +81,11 @@ type Handler struct {
 	strategyProvider  StrategyProvider
 	configProvider    ConfigProvider
 	signalStore       signal.Store
+	backtestRuns      BacktestRunStore
 	prismProvider     PrismProvider
 	sankeySvc         SankeyAnalyzer
 	fundamentalSvc    FundamentalProvider
+	crisisProvider    CrisisProvider
 	prismLow          float64
 	prismHigh         float64
 }
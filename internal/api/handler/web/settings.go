@@ -21,6 +21,7 @@ type SettingsData struct {
 	CooldownDuration string
 	EnabledActions   []string
 	Notifiers        []NotifierView
+	Strategies       []StrategyForm
 }
 
 // Settings renders the settings page
@@ -31,6 +32,7 @@ func (h *Handler) Settings(w http.ResponseWriter, r *http.Request) {
 		CooldownDuration: "4h",
 		EnabledActions:   []string{"Buy", "Sell", "StrongBuy", "StrongSell"},
 		Notifiers:        []NotifierView{},
+		Strategies:       h.strategyForms(),
 	}
 
 	// Get config from provider if available
@@ -0,0 +1,68 @@
+// internal/api/handler/web/strategies.go
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// StrategySource is the optional side of a StrategyProvider that exposes the
+// strategies themselves; *strategy.Engine implements both. Without it the
+// pages list strategy names but render no param forms.
+type StrategySource interface {
+	GetAll() []strategy.Strategy
+}
+
+// StrategyForm is a strategy's declared params rendered as form fields.
+type StrategyForm struct {
+	Name        string
+	Description string
+	Fields      []ParamField
+}
+
+// ParamField is one param input. Value is the default formatted for the
+// input; Step is the number input's step attribute.
+type ParamField struct {
+	strategy.Param
+	Value string
+	Step  string
+}
+
+// strategyForms builds the param forms of the registered strategies, sorted
+// by name. Strategies that declare no params get an empty form.
+func (h *Handler) strategyForms() []StrategyForm {
+	src, ok := h.strategyProvider.(StrategySource)
+	if !ok {
+		return nil
+	}
+	var forms []StrategyForm
+	for _, s := range src.GetAll() {
+		form := StrategyForm{Name: s.Name(), Description: s.Description()}
+		params, _ := strategy.ParamsOf(s)
+		for _, p := range params {
+			form.Fields = append(form.Fields, paramField(p))
+		}
+		forms = append(forms, form)
+	}
+	sort.Slice(forms, func(i, j int) bool { return forms[i].Name < forms[j].Name })
+	return forms
+}
+
+func paramField(p strategy.Param) ParamField {
+	f := ParamField{Param: p, Step: "any"}
+	switch p.Type {
+	case strategy.ParamInt:
+		f.Step = "1"
+		f.Value = fmt.Sprint(p.Default)
+	case strategy.ParamList:
+		if b, err := json.Marshal(p.Default); err == nil && string(b) != "null" {
+			f.Value = string(b)
+		}
+	default:
+		f.Value = fmt.Sprint(p.Default)
+	}
+	return f
+}
@@ -0,0 +1,75 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/newthinker/atlas/internal/strategy"
+	"github.com/newthinker/atlas/internal/strategy/bollinger"
+	"github.com/newthinker/atlas/internal/strategy/rule"
+)
+
+func strategyEngine(t *testing.T) *strategy.Engine {
+	t.Helper()
+	r := rule.New("dip")
+	if err := r.Init(strategy.Config{Params: map[string]any{"entry": "rsi(14) < 30"}}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	e := strategy.NewEngine()
+	e.Register(bollinger.New(20, 2.5, bollinger.ModeBreakout))
+	e.Register(r)
+	return e
+}
+
+func TestStrategyForms(t *testing.T) {
+	h := newTestHandler(t)
+	if forms := h.strategyForms(); forms != nil {
+		t.Errorf("no provider: forms = %v", forms)
+	}
+	h.SetStrategyProvider(strategyEngine(t))
+	forms := h.strategyForms()
+	if len(forms) != 2 || forms[0].Name != "bollinger" || forms[1].Name != "dip" {
+		t.Fatalf("forms = %+v", forms)
+	}
+	period, k := forms[0].Fields[0], forms[0].Fields[1]
+	if period.Value != "20" || period.Step != "1" || k.Value != "2.5" || k.Step != "any" {
+		t.Errorf("bollinger fields = %+v %+v", period, k)
+	}
+}
+
+func TestSettingsAndBacktest_RenderParamForms(t *testing.T) {
+	for label, templatesDir := range map[string]string{"embedded": "", "disk": "../../templates"} {
+		h := newTestHandler(t)
+		if templatesDir != "" {
+			var err error
+			if h, err = NewHandler(templatesDir); err != nil {
+				t.Fatalf("%s: NewHandler: %v", label, err)
+			}
+		}
+		h.SetStrategyProvider(strategyEngine(t))
+
+		rec := httptest.NewRecorder()
+		h.Settings(rec, httptest.NewRequest(http.MethodGet, "/settings", nil))
+		body := rec.Body.String()
+		for _, want := range []string{
+			`data-strategy="bollinger"`,
+			`name="period" data-type="int" data-default="20" value="20" step="1" min="2"`,
+			`<option value="breakout" selected>breakout</option>`,
+			`name="entry" data-type="string" data-default="rsi(14) &lt; 30"`,
+		} {
+			if !strings.Contains(body, want) {
+				t.Errorf("%s settings: missing %s", label, want)
+			}
+		}
+
+		rec = httptest.NewRecorder()
+		h.Backtest(rec, httptest.NewRequest(http.MethodGet, "/backtest", nil))
+		body = rec.Body.String()
+		if !strings.Contains(body, `class="strategy-params hidden mt-4" data-strategy="dip"`) ||
+			!strings.Contains(body, `name="confidence" data-type="number" data-default="0.7"`) {
+			t.Errorf("%s backtest: param form missing", label)
+		}
+	}
+}
@@ -17,8 +17,10 @@ import (
 	"github.com/newthinker/atlas/internal/broker"
 	"github.com/newthinker/atlas/internal/collector"
 	"github.com/newthinker/atlas/internal/config"
+	"github.com/newthinker/atlas/internal/crisis"
 	"github.com/newthinker/atlas/internal/metrics"
 	"github.com/newthinker/atlas/internal/prism/sankey"
+	"github.com/newthinker/atlas/internal/storage/backtestrun"
 	prismstore "github.com/newthinker/atlas/internal/storage/prism"
 	"github.com/newthinker/atlas/internal/storage/signal"
 	"github.com/newthinker/atlas/internal/strategy"
@@ -48,6 +50,7 @@ type Dependencies struct {
 	App              *app.App
 	SignalStore      signal.Store
 	Backtester       *backtest.Backtester
+	BacktestRuns     *backtestrun.SQLiteStore // nil = no run history; optimizations run but are not kept
 	Strategies       *strategy.Engine
 	Metrics          *metrics.Registry
 	ExecutionManager *broker.ExecutionManager
@@ -58,6 +61,8 @@ type Dependencies struct {
 	// PrismSankey 为 nil = 模板未配置或加载失败,不注册财报桥路由。
 	// 注册一个必然报错的端点比不注册更糟: 前端拿到 500 无从判断是配置问题还是故障。
 	PrismSankey *sankey.Service
+	// CrisisDashboard 为 nil = 危机监控看板未启用,路由照常注册并回 404。
+	CrisisDashboard *crisis.Dashboard
 }
 
 // watchlistAdapter adapts app.App to the web handler's WatchlistProvider interface
@@ -173,7 +178,17 @@ func (s *Server) setupRoutes(cfg Config, deps Dependencies) error {
 	signalsHandler := api.NewSignalsHandler(deps.SignalStore)
 	watchlistHandler := api.NewWatchlistHandler(deps.App)
 	backtestHandler := api.NewBacktestHandler(jobStore, deps.Backtester, deps.Strategies)
+	if deps.BacktestRuns != nil {
+		backtestHandler.SetRunStore(deps.BacktestRuns)
+		backtestHandler.SetOptimizationStore(deps.BacktestRuns)
+	}
 	analysisHandler := api.NewAnalysisHandler(deps.App)
+	// A typed-nil *strategy.Engine would pass the handler's nil check.
+	var strategyLister api.StrategyLister
+	if deps.Strategies != nil {
+		strategyLister = deps.Strategies
+	}
+	strategiesHandler := api.NewStrategiesHandler(strategyLister)
 	symbolsHandler := api.NewSymbolsHandler()
 
 	// Create symbol detail handler with collectors
@@ -238,6 +253,17 @@ func (s *Server) setupRoutes(cfg Config, deps Dependencies) error {
 	s.mux.Handle("/api/prism/sankey", wrapHandler(http.HandlerFunc(sankeyHandler.Sankey)))
 	s.mux.Handle("/api/prism/fundamental", wrapHandler(http.HandlerFunc(sankeyHandler.Fundamental)))
 
+	// Crisis monitor API — registered unconditionally for the same reason as
+	// the earnings bridge above, with the same typed-nil guard.
+	var crisisDash api.CrisisDashboard
+	if deps.CrisisDashboard != nil {
+		crisisDash = deps.CrisisDashboard
+	}
+	crisisHandler := api.NewCrisisHandler(crisisDash)
+	s.mux.Handle("/api/crisis/status", wrapHandler(http.HandlerFunc(crisisHandler.Status)))
+	s.mux.Handle("/api/crisis/history", wrapHandler(http.HandlerFunc(crisisHandler.History)))
+	s.mux.Handle("/api/crisis/indicators/", wrapHandler(http.HandlerFunc(crisisHandler.Indicator)))
+
 	// API v1 routes (with auth, metrics, logging)
 	s.mux.Handle("/api/v1/signals", wrapHandler(http.HandlerFunc(signalsHandler.List)))
 	s.mux.Handle("/api/v1/signals/", wrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -289,6 +315,7 @@ func (s *Server) setupRoutes(cfg Config, deps Dependencies) error {
 		})))
 	}
 
+	s.mux.Handle("/api/v1/strategies", wrapHandler(http.HandlerFunc(strategiesHandler.List)))
 	s.mux.Handle("/api/v1/backtest", wrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost {
 			backtestHandler.Create(w, r)
@@ -300,6 +327,34 @@ func (s *Server) setupRoutes(cfg Config, deps Dependencies) error {
 		jobID := strings.TrimPrefix(r.URL.Path, "/api/v1/backtest/")
 		backtestHandler.GetStatus(w, r, jobID)
 	})))
+	s.mux.Handle("/api/v1/backtest/optimize", wrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			backtestHandler.Optimize(w, r)
+		} else {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
+	s.mux.Handle("/api/v1/backtest/optimizations", wrapHandler(http.HandlerFunc(backtestHandler.ListOptimizations)))
+	s.mux.Handle("/api/v1/backtest/optimizations/", wrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/api/v1/backtest/optimizations/")
+		backtestHandler.GetOptimization(w, r, id)
+	})))
+	s.mux.Handle("/api/v1/backtest/runs", wrapHandler(http.HandlerFunc(backtestHandler.ListRuns)))
+	s.mux.Handle("/api/v1/backtest/runs/", wrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/v1/backtest/runs/")
+		switch {
+		case path == "compare":
+			backtestHandler.CompareRuns(w, r)
+		case strings.HasSuffix(path, "/rerun"):
+			if r.Method != http.MethodPost {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			backtestHandler.Rerun(w, r, strings.TrimSuffix(path, "/rerun"))
+		default:
+			backtestHandler.GetRun(w, r, path)
+		}
+	})))
 	s.mux.Handle("/api/v1/analysis/run", wrapHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == http.MethodPost {
 			analysisHandler.Trigger(w, r)
@@ -331,11 +386,15 @@ func (s *Server) setupRoutes(cfg Config, deps Dependencies) error {
 		if deps.SignalStore != nil {
 			webHandler.SetSignalStore(deps.SignalStore)
 		}
+		if deps.BacktestRuns != nil {
+			webHandler.SetBacktestRuns(deps.BacktestRuns)
+		}
 
 		s.mux.HandleFunc("/", webHandler.Dashboard)
 		s.mux.HandleFunc("/signals", webHandler.Signals)
 		s.mux.HandleFunc("/watchlist", webHandler.Watchlist)
 		s.mux.HandleFunc("/backtest", webHandler.Backtest)
+		s.mux.HandleFunc("/backtest/report", webHandler.BacktestReport)
 		s.mux.HandleFunc("/settings", webHandler.Settings)
 
 		// Symbol detail page
@@ -386,6 +445,12 @@ func (s *Server) setupRoutes(cfg Config, deps Dependencies) error {
 			symbol := strings.TrimPrefix(r.URL.Path, "/prism/fundamental/")
 			webHandler.PrismFundamental(w, r, symbol)
 		})
+
+		// Crisis monitor page, unconditional like the pages above.
+		if deps.CrisisDashboard != nil {
+			webHandler.SetCrisisProvider(deps.CrisisDashboard)
+		}
+		s.mux.HandleFunc("/crisis", webHandler.Crisis)
 	}
 
 	return nil
@@ -12,6 +12,7 @@ import (
 	"github.com/newthinker/atlas/internal/collector"
 	"github.com/newthinker/atlas/internal/config"
 	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/indicator"
 	"github.com/newthinker/atlas/internal/meta"
 	"github.com/newthinker/atlas/internal/notifier"
 	"github.com/newthinker/atlas/internal/router"
@@ -42,6 +43,11 @@ const (
 	TypeIndex  = "指数"
 )
 
+// intradayDays is how many calendar days of intraday bars a strategy
+// declaring an intraday timeframe gets: enough for the last few sessions and
+// within every collector's intraday limits.
+const intradayDays = 7
+
 // WatchlistItem represents an item in the watchlist with associated metadata
 type WatchlistItem struct {
 	Symbol     string
@@ -60,7 +66,10 @@ type App struct {
 	notifiers  *notifier.Registry
 	router     *router.Router
 	arbitrator signalArbitrator
+	gate       SignalGate
 	executor   SignalExecutor
+	positions  PositionSource
+	indicators *indicator.Registry
 
 	valuationSrc      ValuationSource
 	epsSrc            EPSSource
@@ -80,6 +89,19 @@ type App struct {
 	warned sync.Map
 }
 
+// RouterConfig maps the router section of cfg onto router.Config, as the app
+// routes signals; backtests replaying the router use the same mapping.
+func RouterConfig(cfg *config.Config) router.Config {
+	return router.Config{
+		MinConfidence:    cfg.Router.MinConfidence,
+		CooldownDuration: time.Duration(cfg.Router.CooldownHours) * time.Hour, // 0 = cooldown disabled (router.passesCooldown: now.Sub(last) < 0 is never true)
+		PercentileStep:   cfg.Router.PercentileStep,
+		BatchNotify:      cfg.Router.BatchNotify,
+		// EnabledActions stays hardcoded: config has no corresponding field (YAGNI).
+		EnabledActions: []core.Action{core.ActionBuy, core.ActionSell, core.ActionStrongBuy, core.ActionStrongSell},
+	}
+}
+
 // New creates a new App instance
 func New(cfg *config.Config, logger *zap.Logger) *App {
 	if logger == nil {
@@ -90,15 +112,7 @@ func New(cfg *config.Config, logger *zap.Logger) *App {
 	strategies := strategy.NewEngine()
 	notifiers := notifier.NewRegistry()
 
-	routerCfg := router.Config{
-		MinConfidence:    cfg.Router.MinConfidence,
-		CooldownDuration: time.Duration(cfg.Router.CooldownHours) * time.Hour, // 0 = cooldown disabled (router.passesCooldown: time.Since(last) < 0 is never true)
-		PercentileStep:   cfg.Router.PercentileStep,
-		BatchNotify:      cfg.Router.BatchNotify,
-		// EnabledActions stays hardcoded: config has no corresponding field (YAGNI).
-		EnabledActions: []core.Action{core.ActionBuy, core.ActionSell, core.ActionStrongBuy, core.ActionStrongSell},
-	}
-	r := router.New(routerCfg, notifiers, logger)
+	r := router.New(RouterConfig(cfg), notifiers, logger)
 
 	return &App{
 		cfg:               cfg,
@@ -107,6 +121,7 @@ func New(cfg *config.Config, logger *zap.Logger) *App {
 		strategies:        strategies,
 		notifiers:         notifiers,
 		router:            r,
+		indicators:        indicator.NewRegistry(),
 		watchlistItems:    []WatchlistItem{},
 		watchlistSet:      make(map[string]struct{}),
 		interval:          5 * time.Minute,
@@ -153,6 +168,13 @@ type ValuationSource interface {
 	FetchValuationPercentile(symbol string, lookbackYears int) (float64, error)
 }
 
+// MetricPercentileSource is the optional side of a ValuationSource that also
+// ranks PB, PS and dividend yield; *lixinger.Lixinger implements it. Without
+// it those percentiles are unavailable.
+type MetricPercentileSource interface {
+	FetchMetricPercentile(symbol, metric string, lookbackYears int) (float64, error)
+}
+
 // EPSSource provides a trailing diluted EPS history for a symbol. It is
 // satisfied by *yahoo.Yahoo.
 type EPSSource interface {
@@ -258,6 +280,36 @@ func (a *App) SetExecutor(e SignalExecutor) {
 	a.executor = e
 }
 
+// SignalGate adjusts or suppresses a signal before it is routed, returning
+// the reason when it suppresses it. It is satisfied by *crisisgate.Gate.
+type SignalGate interface {
+	Apply(sig core.Signal, now time.Time) (core.Signal, string, error)
+}
+
+// SetSignalGate puts g between the strategies and the router: every signal is
+// passed through it first. When unset, signals are routed as generated.
+func (a *App) SetSignalGate(g SignalGate) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.gate = g
+}
+
+// PositionSource reports what the account holds of a symbol; ok is false when
+// it holds none. Like SignalExecutor it is defined on the consuming side so
+// the app does not depend on the broker layer.
+type PositionSource interface {
+	Position(symbol string) (pos strategy.Position, ok bool)
+}
+
+// SetPositions makes the held position of each symbol available to its
+// strategies as AnalysisContext.Position, marked at the latest close. When
+// unset, strategies see no position.
+func (a *App) SetPositions(p PositionSource) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.positions = p
+}
+
 // SetWatchlist sets the symbols to monitor
 func (a *App) SetWatchlist(symbols []string) {
 	a.mu.Lock()
@@ -352,14 +404,19 @@ func (a *App) runAnalysisCycle(ctx context.Context) {
 		zap.Int("workers", workers),
 	)
 
+	// Each symbol's analysis context is kept for the cross-sectional
+	// strategies, which rank the watchlist once every symbol is analysed.
+	contexts := make([]*strategy.AnalysisContext, len(items))
+
 	// workers <= 1 keeps the original serial path for full backward compatibility.
 	if workers <= 1 {
-		for _, item := range items {
+		for i, item := range items {
 			if ctx.Err() != nil {
 				return
 			}
-			a.analyzeSymbolSafe(ctx, item)
+			contexts[i] = a.analyzeSymbolSafe(ctx, item)
 		}
+		a.analyzeGroups(ctx, items, contexts)
 		return
 	}
 
@@ -368,7 +425,7 @@ func (a *App) runAnalysisCycle(ctx context.Context) {
 	// analyzeSymbolSafe so they never cancel siblings or crash the process.
 	g, gctx := errgroup.WithContext(ctx)
 	g.SetLimit(workers)
-	for _, item := range items {
+	for i, item := range items {
 		if gctx.Err() != nil {
 			break // stop dispatching new symbols once cancelled
 		}
@@ -376,16 +433,81 @@ func (a *App) runAnalysisCycle(ctx context.Context) {
 			if gctx.Err() != nil {
 				return nil
 			}
-			a.analyzeSymbolSafe(gctx, item)
+			contexts[i] = a.analyzeSymbolSafe(gctx, item)
 			return nil
 		})
 	}
 	_ = g.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+	a.analyzeGroups(ctx, items, contexts)
+}
+
+// analyzeGroups runs every cross-sectional strategy over the items it applies
+// to — those bound to it and the unbound ones — that yielded an analysis
+// context this cycle, then routes their signals like the per-symbol ones.
+func (a *App) analyzeGroups(ctx context.Context, items []WatchlistItem, contexts []*strategy.AnalysisContext) {
+	defer func() {
+		if r := recover(); r != nil {
+			a.logger.Error("cross-sectional analysis panicked", zap.Any("panic", r))
+		}
+	}()
+	names := a.strategies.GetStrategyNames()
+	slices.Sort(names)
+	for _, name := range names {
+		if s, ok := a.strategies.Get(name); !ok || !strategy.IsCrossSectional(s) {
+			continue
+		}
+		var group []strategy.AnalysisContext
+		members := make(map[string]int)
+		for i, item := range items {
+			if contexts[i] == nil {
+				continue
+			}
+			if len(item.Strategies) > 0 && !slices.Contains(a.effectiveStrategies(item), name) {
+				continue
+			}
+			group = append(group, *contexts[i])
+			members[item.Symbol] = i
+		}
+		if len(group) == 0 {
+			continue
+		}
+
+		signals, err := a.strategies.AnalyzeGroup(ctx, name, group)
+		if err != nil {
+			a.logger.Error("cross-sectional analysis failed",
+				zap.String("strategy", name),
+				zap.Error(err),
+			)
+			continue
+		}
+		routed := 0
+		for _, sig := range signals {
+			i, ok := members[sig.Symbol]
+			if !ok {
+				continue // only the group's own symbols can be signalled
+			}
+			one := []core.Signal{sig}
+			enrichSignalMetadata(one, items[i], contexts[i].Fundamental)
+			a.dispatch(ctx, sig.Symbol, one)
+			routed++
+		}
+		if routed > 0 {
+			a.logger.Info("cross-sectional signals generated",
+				zap.String("strategy", name),
+				zap.Int("group", len(group)),
+				zap.Int("count", routed),
+			)
+		}
+	}
 }
 
 // analyzeSymbolSafe runs analyzeSymbol with panic recovery so a single symbol's
-// failure cannot crash the process or abort other symbols in the cycle.
-func (a *App) analyzeSymbolSafe(ctx context.Context, item WatchlistItem) {
+// failure cannot crash the process or abort other symbols in the cycle; a
+// panicking symbol has no analysis context.
+func (a *App) analyzeSymbolSafe(ctx context.Context, item WatchlistItem) *strategy.AnalysisContext {
 	defer func() {
 		if r := recover(); r != nil {
 			a.logger.Error("analyzeSymbol panicked, skipping symbol",
@@ -394,7 +516,7 @@ func (a *App) analyzeSymbolSafe(ctx context.Context, item WatchlistItem) {
 			)
 		}
 	}()
-	a.analyzeSymbol(ctx, item)
+	return a.analyzeSymbol(ctx, item)
 }
 
 // enrichSignalMetadata stamps watchlist display info onto outgoing signals so
@@ -426,7 +548,9 @@ func enrichSignalMetadata(signals []core.Signal, item WatchlistItem, fundamental
 }
 
 // analyzeSymbol fetches data and runs analysis for a single watchlist item.
-func (a *App) analyzeSymbol(ctx context.Context, item WatchlistItem) {
+// It returns the symbol's analysis context for the cross-sectional
+// strategies, nil when the item is skipped or has no data.
+func (a *App) analyzeSymbol(ctx context.Context, item WatchlistItem) *strategy.AnalysisContext {
 	symbol := item.Symbol
 
 	// Resolve which bound strategies actually apply to this asset type. A
@@ -436,14 +560,14 @@ func (a *App) analyzeSymbol(ctx context.Context, item WatchlistItem) {
 	if len(item.Strategies) > 0 {
 		effective = a.effectiveStrategies(item)
 		if len(effective) == 0 {
-			return
+			return nil
 		}
 	}
 
 	collectors := a.orderedCollectors(symbol)
 	if len(collectors) == 0 {
 		a.logger.Warn("no collectors available", zap.String("symbol", symbol))
-		return
+		return nil
 	}
 
 	// Fetch enough history for the most demanding bound strategy (trading days
@@ -470,7 +594,7 @@ func (a *App) analyzeSymbol(ctx context.Context, item WatchlistItem) {
 			zap.String("symbol", symbol),
 			zap.Error(fetchErr),
 		)
-		return
+		return nil
 	}
 
 	// Run analysis
@@ -480,9 +604,34 @@ func (a *App) analyzeSymbol(ctx context.Context, item WatchlistItem) {
 		OHLCV:  ohlcv,
 		Now:    time.Now(),
 	}
+	a.mu.RLock()
+	positions := a.positions
+	a.mu.RUnlock()
+	if positions != nil {
+		analysisCtx.PositionKnown = true
+		if pos, ok := positions.Position(symbol); ok {
+			marked := pos.MarkedAt(ohlcv[len(ohlcv)-1].Close)
+			analysisCtx.Position = &marked
+		}
+	}
 	// Assemble the PE-percentile fundamental only when a bound strategy needs it.
 	if a.needsFundamentals(effective) {
 		analysisCtx.Fundamental = a.buildFundamental(symbol, item.Type, ohlcv)
+		a.addValuationPercentiles(analysisCtx.Fundamental, a.requiredValuations(effective))
+	}
+	// Compute the declared indicators once for every strategy of this symbol.
+	if specs := a.requiredIndicators(effective); len(specs) > 0 {
+		indicators, err := a.indicators.Compute(ohlcv, specs)
+		if err != nil {
+			a.warnOnce("indicators:"+symbol, "some declared indicators could not be computed",
+				zap.String("symbol", symbol), zap.Error(err))
+		}
+		analysisCtx.Indicators = indicators
+	}
+
+	// Assemble the declared weekly, monthly and intraday bars.
+	if timeframes := a.requiredTimeframes(effective); len(timeframes) > 0 {
+		analysisCtx.Bars = a.timeframeBars(symbol, collectors, ohlcv, timeframes, end)
 	}
 
 	// Honour per-symbol strategy selection when configured; otherwise run all.
@@ -500,11 +649,11 @@ func (a *App) analyzeSymbol(ctx context.Context, item WatchlistItem) {
 			zap.String("symbol", symbol),
 			zap.Error(err),
 		)
-		return
+		return &analysisCtx
 	}
 
 	if len(signals) == 0 {
-		return
+		return &analysisCtx
 	}
 
 	// Resolve conflicting signals via the LLM arbitrator when enabled.
@@ -514,13 +663,42 @@ func (a *App) analyzeSymbol(ctx context.Context, item WatchlistItem) {
 	// titles without knowing the watchlist.
 	enrichSignalMetadata(signals, item, analysisCtx.Fundamental)
 
-	// Snapshot the executor under the lock so SetExecutor can run concurrently.
+	a.dispatch(ctx, symbol, signals)
+	return &analysisCtx
+}
+
+// dispatch routes the signals of symbol, then submits each routed one for
+// execution when an executor is wired.
+func (a *App) dispatch(ctx context.Context, symbol string, signals []core.Signal) {
+	// Snapshot the executor and gate under the lock so their setters can run
+	// concurrently.
 	a.mu.RLock()
-	executor := a.executor
+	executor, gate := a.executor, a.gate
 	a.mu.RUnlock()
 
 	// Route signals, then submit each for execution when an executor is wired.
 	for _, sig := range signals {
+		if gate != nil {
+			gated, reason, err := gate.Apply(sig, time.Now())
+			if err != nil {
+				// A gate that cannot read its state must not silence the
+				// strategies: the signal is routed as generated.
+				a.logger.Warn("signal gate failed; routing ungated",
+					zap.String("symbol", symbol),
+					zap.Error(err),
+				)
+			}
+			if reason != "" {
+				a.logger.Info("signal suppressed by gate",
+					zap.String("symbol", symbol),
+					zap.String("action", string(sig.Action)),
+					zap.String("reason", reason),
+				)
+				continue
+			}
+			sig = gated
+		}
+
 		routed, err := a.router.Route(sig)
 		if err != nil {
 			a.logger.Error("failed to route signal",
@@ -911,6 +1089,33 @@ func (a *App) buildFundamental(symbol, appType string, ohlcv []core.OHLCV) *core
 	}
 }
 
+// addValuationPercentiles fills f.Percentiles with the declared metrics
+// besides PE from the valuation source (lixinger cvpos). A metric it cannot
+// rank stays unavailable, with a warning.
+func (a *App) addValuationPercentiles(f *core.Fundamental, metrics []string) {
+	if f == nil {
+		return
+	}
+	for _, metric := range metrics {
+		if metric == core.MetricPE {
+			continue // buildFundamental's path
+		}
+		src, ok := a.valuationSrc.(MetricPercentileSource)
+		if !ok {
+			a.warnOnce("valpct:"+metric+":"+f.Symbol, "valuation percentile unavailable: no source ranks the metric",
+				zap.String("symbol", f.Symbol), zap.String("metric", metric))
+			continue
+		}
+		pct, err := src.FetchMetricPercentile(f.Symbol, metric, a.lixingerLookback())
+		if err != nil {
+			a.warnOnce("valpct:"+metric+":"+f.Symbol, "valuation percentile fetch failed",
+				zap.String("symbol", f.Symbol), zap.String("metric", metric), zap.Error(err))
+			continue
+		}
+		f.SetPercentile(metric, pct)
+	}
+}
+
 // fallbackReason classifies why the yahoo reconstruction path yielded to the
 // lixinger fallback, encoded into Fundamental.Source for observability.
 func fallbackReason(err error) string {
@@ -931,6 +1136,80 @@ func (a *App) needsFundamentals(names []string) bool {
 	return false
 }
 
+// requiredIndicators collects the indicator specs declared by the named
+// strategies, or by every registered strategy when names is empty, without
+// duplicates.
+func (a *App) requiredIndicators(names []string) []string {
+	return a.requiredUnion(names, func(d strategy.DataRequirements) []string { return d.Indicators })
+}
+
+// requiredTimeframes collects the timeframes declared by the named strategies
+// the way requiredIndicators collects indicator specs.
+func (a *App) requiredTimeframes(names []string) []string {
+	return a.requiredUnion(names, func(d strategy.DataRequirements) []string { return d.Timeframes })
+}
+
+// requiredValuations collects the valuation metrics declared by the named
+// strategies the way requiredIndicators collects indicator specs.
+func (a *App) requiredValuations(names []string) []string {
+	return a.requiredUnion(names, func(d strategy.DataRequirements) []string { return d.Valuations })
+}
+
+// requiredUnion merges pick over the requirements of the named strategies,
+// or of every registered strategy when names is empty, without duplicates.
+func (a *App) requiredUnion(names []string, pick func(strategy.DataRequirements) []string) []string {
+	var strats []strategy.Strategy
+	if len(names) == 0 {
+		strats = a.strategies.GetAll()
+	} else {
+		for _, n := range names {
+			if s, ok := a.strategies.Get(n); ok {
+				strats = append(strats, s)
+			}
+		}
+	}
+	var union []string
+	for _, s := range strats {
+		for _, v := range pick(s.RequiredData()) {
+			if !slices.Contains(union, v) {
+				union = append(union, v)
+			}
+		}
+	}
+	return union
+}
+
+// timeframeBars assembles the declared timeframes of a symbol: weekly and
+// monthly bars resampled from its daily bars, intraday bars over the last
+// intradayDays from the first of collectors that supports the interval.
+// Timeframes that cannot be had are left out, with a warning.
+func (a *App) timeframeBars(symbol string, collectors []collector.Collector, daily []core.OHLCV, timeframes []string, end time.Time) map[string][]core.OHLCV {
+	bars := strategy.ResampleAll(daily, timeframes)
+	for _, tf := range timeframes {
+		if strategy.Resampled(tf) {
+			continue
+		}
+		if !strategy.ValidTimeframe(tf) {
+			a.warnOnce("timeframe:"+tf, "unknown timeframe declared by a strategy", zap.String("timeframe", tf))
+			continue
+		}
+		for _, c := range collectors {
+			if !collector.SupportsInterval(c, tf) {
+				continue
+			}
+			if intraday, err := c.FetchHistory(symbol, end.AddDate(0, 0, -intradayDays), end, tf); err == nil && len(intraday) > 0 {
+				bars[tf] = intraday
+				break
+			}
+		}
+		if _, ok := bars[tf]; !ok {
+			a.warnOnce("timeframe:"+symbol+":"+tf, "no collector has intraday bars for the symbol",
+				zap.String("symbol", symbol), zap.String("timeframe", tf))
+		}
+	}
+	return bars
+}
+
 // RemoveFromWatchlist removes a symbol from the watchlist.
 func (a *App) RemoveFromWatchlist(symbol string) bool {
 	a.mu.Lock()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -28,10 +29,16 @@ type fakeStrategy struct {
 	assetTypes   []core.AssetType
 	priceHistory int
 	fundamentals bool
+	indicators   []string
+	timeframes   []string
+	valuations   []string
 	signals      []core.Signal
 
 	mu             sync.Mutex
-	gotFundamental *core.Fundamental // captured from the last Analyze call
+	gotFundamental *core.Fundamental    // captured from the last Analyze call
+	gotIndicators  map[string][]float64 // captured from the last Analyze call
+	gotPosition    *strategy.Position   // captured from the last Analyze call
+	gotBars        map[string][]core.OHLCV
 }
 
 func (f *fakeStrategy) Name() string        { return f.name }
@@ -41,12 +48,18 @@ func (f *fakeStrategy) RequiredData() strategy.DataRequirements {
 		AssetTypes:   f.assetTypes,
 		PriceHistory: f.priceHistory,
 		Fundamentals: f.fundamentals,
+		Indicators:   f.indicators,
+		Timeframes:   f.timeframes,
+		Valuations:   f.valuations,
 	}
 }
 func (f *fakeStrategy) Init(cfg strategy.Config) error { return nil }
 func (f *fakeStrategy) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
 	f.mu.Lock()
 	f.gotFundamental = ctx.Fundamental
+	f.gotIndicators = ctx.Indicators
+	f.gotPosition = ctx.Position
+	f.gotBars = ctx.Bars
 	f.mu.Unlock()
 	out := make([]core.Signal, len(f.signals))
 	copy(out, f.signals)
@@ -59,6 +72,24 @@ func (f *fakeStrategy) capturedFundamental() *core.Fundamental {
 	return f.gotFundamental
 }
 
+func (f *fakeStrategy) capturedIndicators() map[string][]float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.gotIndicators
+}
+
+func (f *fakeStrategy) capturedPosition() *strategy.Position {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.gotPosition
+}
+
+func (f *fakeStrategy) capturedBars() map[string][]core.OHLCV {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.gotBars
+}
+
 type mockCollector struct {
 	name       string
 	history    []core.OHLCV
@@ -304,6 +335,57 @@ func TestApp_PerSymbolStrategies(t *testing.T) {
 	}
 }
 
+// rankingStrategy is a cross-sectional stub that buys the alphabetically
+// first symbol of its group and records the group it was given.
+type rankingStrategy struct {
+	mockStrategy
+	mu    sync.Mutex
+	group []string
+}
+
+func (r *rankingStrategy) AnalyzeGroup(ctxs []strategy.AnalysisContext) ([]core.Signal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.group = nil
+	for _, c := range ctxs {
+		r.group = append(r.group, c.Symbol)
+	}
+	slices.Sort(r.group)
+	return []core.Signal{{Symbol: r.group[0], Action: core.ActionBuy, Confidence: 0.8}}, nil
+}
+
+func TestApp_RunsCrossSectionalStrategies(t *testing.T) {
+	for _, workers := range []int{1, 3} {
+		cfg := &config.Config{}
+		cfg.Analysis.Workers = workers
+		app := New(cfg, nil)
+		app.RegisterCollector(&mockCollector{name: "mock", history: sampleCloses(30)})
+		rank := &rankingStrategy{mockStrategy: mockStrategy{name: "rank"}}
+		app.RegisterStrategy(rank)
+		app.RegisterStrategy(&mockStrategy{name: "other"})
+		noti := &mockNotifier{name: "mock"}
+		app.RegisterNotifier(noti)
+
+		// BBB is bound to another strategy only, so it is not ranked.
+		app.AddToWatchlistWithDetails("CCC", "", "", "", nil)
+		app.AddToWatchlistWithDetails("BBB", "", "", "", []string{"other"})
+		app.AddToWatchlistWithDetails("DDD", "Dee", "", "", []string{"rank"})
+
+		app.RunOnce(context.Background())
+
+		rank.mu.Lock()
+		group := rank.group
+		rank.mu.Unlock()
+		if strings.Join(group, ",") != "CCC,DDD" {
+			t.Errorf("workers=%d: group = %v, want [CCC DDD]", workers, group)
+		}
+		got := noti.received()
+		if len(got) != 1 || got[0].Symbol != "CCC" || got[0].Strategy != "rank" {
+			t.Errorf("workers=%d: routed %+v, want one rank signal for CCC", workers, got)
+		}
+	}
+}
+
 func TestApp_PreferredCollectorTriedFirst(t *testing.T) {
 	cfg := &config.Config{}
 	app := New(cfg, nil)
@@ -586,6 +668,55 @@ func TestApp_Executor_ConcurrentSetAndRun(t *testing.T) {
 	wg.Wait()
 }
 
+// gateStub suppresses buys and marks the signals it passes.
+type gateStub struct{ err error }
+
+func (g gateStub) Apply(sig core.Signal, now time.Time) (core.Signal, string, error) {
+	if g.err != nil {
+		return sig, "", g.err
+	}
+	if sig.Action == core.ActionBuy {
+		return sig, "crisis_state", nil
+	}
+	sig.Metadata = map[string]any{"gated": true}
+	return sig, "", nil
+}
+
+func TestApp_SignalGate(t *testing.T) {
+	newGatedApp := func(gate SignalGate) (*App, *mockNotifier, *mockExecutor) {
+		app := New(&config.Config{}, nil)
+		app.RegisterCollector(&mockCollector{name: "mock", history: executorTestHistory()})
+		app.RegisterStrategy(&mockStrategy{name: "mock", signals: []core.Signal{
+			{Symbol: "TEST", Action: core.ActionBuy, Confidence: 0.8},
+			{Symbol: "TEST", Action: core.ActionSell, Confidence: 0.8},
+		}})
+		noti := &mockNotifier{name: "mock"}
+		app.RegisterNotifier(noti)
+		app.SetWatchlist([]string{"TEST"})
+		exec := &mockExecutor{}
+		app.SetExecutor(exec)
+		app.SetSignalGate(gate)
+		return app, noti, exec
+	}
+
+	app, noti, exec := newGatedApp(gateStub{})
+	app.RunOnce(context.Background())
+	got := noti.received()
+	if len(got) != 1 || got[0].Action != core.ActionSell || got[0].Metadata["gated"] != true {
+		t.Fatalf("gated signals routed = %+v, want only the marked sell", got)
+	}
+	if exec.count() != 1 {
+		t.Errorf("a suppressed signal must not be submitted: got %d submissions", exec.count())
+	}
+
+	// A failing gate routes signals as generated.
+	app, noti, _ = newGatedApp(gateStub{err: errors.New("store locked")})
+	app.RunOnce(context.Background())
+	if got := noti.received(); len(got) != 2 {
+		t.Errorf("failing gate: routed %d signals, want 2", len(got))
+	}
+}
+
 func TestApp_WatchlistManagement(t *testing.T) {
 	app := New(&config.Config{}, nil)
 
@@ -1229,6 +1360,171 @@ func TestAnalyzeSymbol_SkipsFundamentalWhenNotNeeded(t *testing.T) {
 	}
 }
 
+func TestAnalyzeSymbol_ComputesDeclaredIndicators(t *testing.T) {
+	a := New(&config.Config{}, zap.NewNop())
+	a.RegisterCollector(&mockCollector{name: "eastmoney", history: sampleCloses(300)})
+	rsi := &fakeStrategy{name: "rsi", priceHistory: 10, indicators: []string{"rsi_14", "bb_20_2"}}
+	macd := &fakeStrategy{name: "macd", priceHistory: 10, indicators: []string{"rsi_14", "macd"}}
+	plain := &fakeStrategy{name: "plain", priceHistory: 10}
+	a.RegisterStrategy(rsi)
+	a.RegisterStrategy(macd)
+	a.RegisterStrategy(plain)
+
+	a.analyzeSymbol(context.Background(), WatchlistItem{Symbol: "600519.SH", Type: TypeStock})
+
+	// One computation per symbol, shared by every strategy.
+	got := rsi.capturedIndicators()
+	for _, key := range []string{"rsi_14", "bb_20_2", "bb_20_2.upper", "macd", "macd.signal"} {
+		if len(got[key]) == 0 {
+			t.Errorf("missing indicator %q in %v", key, got)
+		}
+	}
+	if plain.capturedIndicators() == nil {
+		t.Error("strategies share the symbol's indicators")
+	}
+}
+
+func TestAnalyzeSymbol_NoIndicatorsWhenNoneDeclared(t *testing.T) {
+	a := New(&config.Config{}, zap.NewNop())
+	a.RegisterCollector(&mockCollector{name: "eastmoney", history: sampleCloses(300)})
+	plain := &fakeStrategy{name: "plain", priceHistory: 10}
+	a.RegisterStrategy(plain)
+
+	a.analyzeSymbol(context.Background(), WatchlistItem{Symbol: "600519.SH", Type: TypeStock, Strategies: []string{"plain"}})
+
+	if got := plain.capturedIndicators(); got != nil {
+		t.Errorf("expected no indicators, got %v", got)
+	}
+}
+
+type stubPositions map[string]strategy.Position
+
+func (s stubPositions) Position(symbol string) (strategy.Position, bool) {
+	pos, ok := s[symbol]
+	return pos, ok
+}
+
+func TestAnalyzeSymbol_PassesHeldPosition(t *testing.T) {
+	a := New(&config.Config{}, zap.NewNop())
+	history := sampleCloses(30)
+	a.RegisterCollector(&mockCollector{name: "eastmoney", history: history})
+	plain := &fakeStrategy{name: "plain", priceHistory: 10}
+	a.RegisterStrategy(plain)
+
+	a.analyzeSymbol(context.Background(), WatchlistItem{Symbol: "600519.SH", Type: TypeStock})
+	if got := plain.capturedPosition(); got != nil {
+		t.Errorf("no position source: expected no position, got %+v", got)
+	}
+
+	entry := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	a.SetPositions(stubPositions{"600519.SH": {Quantity: 100, AverageCost: 10, EntryDate: entry}})
+	a.analyzeSymbol(context.Background(), WatchlistItem{Symbol: "600519.SH", Type: TypeStock})
+	got := plain.capturedPosition()
+	last := history[len(history)-1].Close
+	if got == nil || got.Quantity != 100 || !got.EntryDate.Equal(entry) || got.UnrealizedPL != (last-10)*100 {
+		t.Errorf("position = %+v, want 100 @ 10 marked at %v", got, last)
+	}
+
+	a.SetPositions(stubPositions{})
+	a.analyzeSymbol(context.Background(), WatchlistItem{Symbol: "600519.SH", Type: TypeStock})
+	if got := plain.capturedPosition(); got != nil {
+		t.Errorf("symbol not held: expected no position, got %+v", got)
+	}
+}
+
+// intradayCollector serves hourly bars besides its daily history.
+type intradayCollector struct {
+	mockCollector
+	hourly []core.OHLCV
+}
+
+func (c *intradayCollector) Intervals() []string { return []string{"1h", "1d"} }
+func (c *intradayCollector) FetchHistory(symbol string, start, end time.Time, interval string) ([]core.OHLCV, error) {
+	if interval == "1h" {
+		return c.hourly, nil
+	}
+	return c.history, nil
+}
+
+func TestAnalyzeSymbol_AssemblesDeclaredTimeframes(t *testing.T) {
+	a := New(&config.Config{}, zap.NewNop())
+	a.RegisterCollector(&mockCollector{name: "eastmoney", history: sampleCloses(60)})
+	multi := &fakeStrategy{name: "multi", priceHistory: 10, timeframes: []string{"1w", "1mo", "1h"}}
+	plain := &fakeStrategy{name: "plain", priceHistory: 10}
+	a.RegisterStrategy(multi)
+	a.RegisterStrategy(plain)
+
+	a.analyzeSymbol(context.Background(), WatchlistItem{Symbol: "600519.SH", Type: TypeStock, Strategies: []string{"plain"}})
+	if got := plain.capturedBars(); got != nil {
+		t.Errorf("no timeframes declared: expected no bars, got %v", got)
+	}
+
+	// The plain collector has no intraday bars: weekly and monthly only.
+	a.analyzeSymbol(context.Background(), WatchlistItem{Symbol: "600519.SH", Type: TypeStock, Strategies: []string{"multi"}})
+	got := multi.capturedBars()
+	if len(got["1w"]) < 8 || len(got["1mo"]) < 2 {
+		t.Errorf("expected weekly and monthly bars, got %d weekly, %d monthly", len(got["1w"]), len(got["1mo"]))
+	}
+	if _, ok := got["1h"]; ok {
+		t.Error("no collector supports 1h: expected it to be left out")
+	}
+
+	b := New(&config.Config{}, zap.NewNop())
+	hourly := []core.OHLCV{{Close: 101, Interval: "1h"}, {Close: 102, Interval: "1h"}}
+	b.RegisterCollector(&intradayCollector{mockCollector: mockCollector{name: "eastmoney", history: sampleCloses(60)}, hourly: hourly})
+	b.RegisterStrategy(multi)
+	b.analyzeSymbol(context.Background(), WatchlistItem{Symbol: "600519.SH", Type: TypeStock, Strategies: []string{"multi"}})
+	if got := multi.capturedBars()["1h"]; len(got) != 2 || got[1].Close != 102 {
+		t.Errorf("1h bars = %v, want the collector's hourly bars", got)
+	}
+}
+
+// metricVal is a valuation source that also ranks PB, PS and dividend yield.
+type metricVal struct {
+	stubVal
+	pcts map[string]float64
+}
+
+func (m *metricVal) FetchMetricPercentile(symbol, metric string, lookbackYears int) (float64, error) {
+	if pct, ok := m.pcts[metric]; ok {
+		return pct, nil
+	}
+	return -1, errors.New("no such metric")
+}
+
+func TestAnalyzeSymbol_AddsDeclaredValuationPercentiles(t *testing.T) {
+	item := WatchlistItem{Symbol: "601398.SH", Type: TypeStock, Strategies: []string{"banks"}}
+	banks := &fakeStrategy{name: "banks", priceHistory: 10, fundamentals: true,
+		valuations: []string{core.MetricPB, core.MetricDividendYield}}
+
+	a := New(&config.Config{}, zap.NewNop())
+	a.RegisterCollector(&mockCollector{name: "eastmoney", history: sampleCloses(30)})
+	a.RegisterStrategy(banks)
+	a.SetValuationSources(&metricVal{stubVal: stubVal{pct: 30}, pcts: map[string]float64{core.MetricPB: 7}}, nil)
+	a.analyzeSymbol(context.Background(), item)
+
+	f := banks.capturedFundamental()
+	if f == nil || f.PEPercentile != 30 {
+		t.Fatalf("fundamental = %+v, want the PE percentile from the source", f)
+	}
+	if pb, ok := f.Percentile(core.MetricPB); !ok || pb != 7 {
+		t.Errorf("PB percentile = %v, %v, want 7", pb, ok)
+	}
+	if _, ok := f.Percentile(core.MetricDividendYield); ok {
+		t.Error("a failed fetch should leave the dividend yield percentile unavailable")
+	}
+
+	// A source that only ranks PE leaves the other metrics unavailable.
+	b := New(&config.Config{}, zap.NewNop())
+	b.RegisterCollector(&mockCollector{name: "eastmoney", history: sampleCloses(30)})
+	b.RegisterStrategy(banks)
+	b.SetValuationSources(&stubVal{pct: 30}, nil)
+	b.analyzeSymbol(context.Background(), item)
+	if f := banks.capturedFundamental(); f == nil || len(f.Percentiles) != 0 {
+		t.Errorf("fundamental = %+v, want no metric percentiles", f)
+	}
+}
+
 // --- Task 10 Step 3: CollectorRegistry exposure ---
 //
 // Context Checkpoint: done_criteria → test mapping
@@ -3,6 +3,8 @@ package backtest
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/newthinker/atlas/internal/core"
@@ -16,78 +18,147 @@ type OHLCVProvider interface {
 
 // Backtester runs strategy backtests against historical data
 type Backtester struct {
-	provider OHLCVProvider
+	provider  OHLCVProvider
+	capital   float64
+	models    ModelSelector
+	fill      FillModel
+	benchmark BenchmarkSelector
+	funds     FundamentalProvider
+	filter    func() SignalFilter
+	gate      SignalGate
+}
+
+// Option configures a Backtester.
+type Option func(*Backtester)
+
+// WithCapital sets the starting cash each all-in trade is sized from
+// (default DefaultInitialCapital). It matters once lot sizes and minimum
+// commissions apply.
+func WithCapital(capital float64) Option {
+	return func(b *Backtester) {
+		if capital > 0 {
+			b.capital = capital
+		}
+	}
+}
+
+// WithExecutionModels applies per-symbol costs, slippage and trading rules,
+// e.g. WithExecutionModels(MarketModels). Without it fills are frictionless.
+func WithExecutionModels(models ModelSelector) Option {
+	return func(b *Backtester) {
+		b.models = models
+	}
+}
+
+// WithFillModel sets when signals are executed (default FillSameClose).
+func WithFillModel(m FillModel) Option {
+	return func(b *Backtester) {
+		if m != "" {
+			b.fill = m
+		}
+	}
+}
+
+// WithBenchmark compares each run with the benchmark the selector picks for
+// its symbol, fetched from the same provider.
+func WithBenchmark(sel BenchmarkSelector) Option {
+	return func(b *Backtester) {
+		b.benchmark = sel
+	}
+}
+
+// WithFundamentals supplies point-in-time fundamentals to strategies that
+// declare RequiredData().Fundamentals. Without it such strategies fail with
+// ErrNoFundamentals.
+func WithFundamentals(feed FundamentalProvider) Option {
+	return func(b *Backtester) {
+		b.funds = feed
+	}
 }
 
 // New creates a new Backtester with the given OHLCV provider
-func New(provider OHLCVProvider) *Backtester {
-	return &Backtester{
+func New(provider OHLCVProvider, opts ...Option) *Backtester {
+	b := &Backtester{
 		provider: provider,
+		capital:  DefaultInitialCapital,
+		fill:     FillSameClose,
 	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
 }
 
 // Run executes a backtest for the given strategy and symbol over the specified time range
 func (b *Backtester) Run(ctx context.Context, strat strategy.Strategy, symbol string, start, end time.Time) (*Result, error) {
+	if strategy.IsCrossSectional(strat) {
+		return nil, fmt.Errorf("%s: %w", strat.Name(), ErrCrossSectional)
+	}
+
 	// Fetch historical data
 	ohlcv, err := b.provider.FetchHistory(symbol, start, end, "1d")
 	if err != nil {
 		return nil, err
 	}
 
-	if len(ohlcv) == 0 {
+	// Bars the provider returns before start only prime the strategy's
+	// lookback window; trading starts at the first bar on or after start.
+	first := sort.Search(len(ohlcv), func(i int) bool { return !ohlcv[i].Time.Before(start) })
+	if first == len(ohlcv) {
 		return nil, errors.New("no historical data available")
 	}
 
-	// Get strategy data requirements
-	req := strat.RequiredData()
-	windowSize := req.PriceHistory
-	if windowSize <= 0 {
-		windowSize = 1
+	funds, err := fundamentalsFor(b.funds, symbol, ohlcv, strat)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", strat.Name(), err)
 	}
 
-	var allSignals []core.Signal
-	skipped := 0
-
-	// Run strategy on each bar with rolling window
-	for i := 0; i < len(ohlcv); i++ {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
-
-		// Build rolling window
-		windowStart := max(0, i-windowSize+1)
-		window := ohlcv[windowStart : i+1]
-
-		// Create analysis context
-		analysisCtx := strategy.AnalysisContext{
-			Symbol: symbol,
-			OHLCV:  window,
-			Now:    ohlcv[i].Time,
-		}
+	var model *ExecutionModel
+	if b.models != nil {
+		model = b.models(symbol)
+	}
 
-		// Run strategy analysis
-		signals, err := strat.Analyze(analysisCtx)
+	var filter SignalFilter
+	var routing *Routing
+	if b.filter != nil {
+		filter = b.filter()
+	}
+	if b.filter != nil || b.gate != nil {
+		routing = newRouting()
+	}
+	allSignals, sim, skipped, err := b.replay(ctx, strat, symbol, ohlcv, first, funds, model, routing, filter)
+	if err != nil {
+		return nil, err
+	}
+	if routing != nil {
+		// What executing every signal would have given: a pass of its own,
+		// since the strategy sees the positions its signals left open and
+		// those differ once signals are dropped. BrokerBacktester.Run does
+		// the same.
+		_, unfiltered, _, err := b.replay(ctx, strat, symbol, ohlcv, first, funds, model, nil, nil)
 		if err != nil {
-			skipped++
-			continue // Skip bars with analysis errors
-		}
-
-		// Collect signals with price from OHLCV close
-		for _, sig := range signals {
-			sig.Price = ohlcv[i].Close
-			sig.Strategy = strat.Name()
-			sig.GeneratedAt = ohlcv[i].Time // bar time, never wall clock
-			allSignals = append(allSignals, sig)
+			return nil, err
 		}
+		routing.Unfiltered = CalculateStats(unfiltered.trades)
+		applyEquityStats(&routing.Unfiltered, unfiltered.equity, b.capital, unfiltered.trades)
 	}
 
-	// Convert signals to trades
-	trades := signalsToTrades(allSignals, ohlcv)
-
 	// Calculate statistics
-	stats := CalculateStats(trades)
+	stats := CalculateStats(sim.trades)
+	applyEquityStats(&stats, sim.equity, b.capital, sim.trades)
+	if b.benchmark != nil {
+		if bench := b.benchmark(symbol); bench != "" {
+			bars := ohlcv[first:]
+			if bench != symbol {
+				bars, err = b.provider.FetchHistory(bench, start, end, "1d")
+			}
+			// A missing benchmark leaves Stats.Benchmark empty rather than
+			// failing the run: the strategy's own statistics still stand.
+			if err == nil {
+				applyBenchmarkStats(&stats, bench, sim.equity, bars)
+			}
+		}
+	}
 
 	return &Result{
 		Strategy:    strat.Name(),
@@ -95,51 +166,221 @@ func (b *Backtester) Run(ctx context.Context, strat strategy.Strategy, symbol st
 		StartDate:   start,
 		EndDate:     end,
 		Signals:     allSignals,
-		Trades:      trades,
+		Trades:      sim.trades,
 		Stats:       stats,
 		SkippedBars: skipped,
+		FillModel:   b.fill,
+		Execution:   model,
+		Rejections:  sim.rejections,
+		Capital:     b.capital,
+		Equity:      sim.equity,
+		Routing:     routing,
 	}, nil
 }
 
-// signalsToTrades converts a series of signals into trades
+// replay runs strat over ohlcv from bar first on and simulates its trades
+// bar by bar alongside the analysis, so each bar's strategy sees the position
+// the signals before it left open. With routing set, signals pass the gate
+// and filter first and only those admitted are executed. Bars whose analysis
+// fails are skipped and counted.
+func (b *Backtester) replay(ctx context.Context, strat strategy.Strategy, symbol string, ohlcv []core.OHLCV, first int, funds []*core.Fundamental, model *ExecutionModel, routing *Routing, filter SignalFilter) (signals []core.Signal, sim simulation, skipped int, err error) {
+	acted := newTradeSim(symbol, ohlcv[first:], model, b.fill, b.capital)
+	for i := first; i < len(ohlcv); i++ {
+		select {
+		case <-ctx.Done():
+			return nil, simulation{}, 0, ctx.Err()
+		default:
+		}
+
+		bar, err := analyzeBar(strat, symbol, ohlcv, i, fundamentalAt(funds, i), acted.position(ohlcv[i]))
+		if err != nil {
+			skipped++
+			continue // Skip bars with analysis errors
+		}
+		signals = append(signals, bar...)
+		for _, sig := range bar {
+			if routing == nil {
+				acted.apply(sig)
+				continue
+			}
+			routed, ok, err := routing.route(b.gate, filter, sig)
+			if err != nil {
+				return nil, simulation{}, 0, fmt.Errorf("%s: %w", strat.Name(), err)
+			}
+			if ok {
+				acted.apply(routed)
+			}
+		}
+	}
+	return signals, acted.finish(), skipped, nil
+}
+
+// signalsToTrades converts a series of signals into frictionless trades
 func signalsToTrades(signals []core.Signal, ohlcv []core.OHLCV) []Trade {
-	var trades []Trade
-	var openTrade *Trade
+	return simulateTrades("", signals, ohlcv, nil, FillSameClose, DefaultInitialCapital).trades
+}
 
+// simulateTrades converts a series of signals into all-in trades, filling
+// each per timing and through model, starting from capital cash. Orders that
+// cannot be filled are returned as rejections and leave the position
+// unchanged.
+func simulateTrades(symbol string, signals []core.Signal, ohlcv []core.OHLCV, model *ExecutionModel, timing FillModel, capital float64) simulation {
+	sim := newTradeSim(symbol, ohlcv, model, timing, capital)
 	for _, sig := range signals {
-		switch sig.Action {
-		case core.ActionBuy, core.ActionStrongBuy:
-			// Only open a new trade if not already in a position
-			if openTrade == nil {
-				openTrade = &Trade{
-					EntrySignal: sig,
-					EntryPrice:  sig.Price,
-				}
+		sim.apply(sig)
+	}
+	return sim.finish()
+}
+
+// tradeSim replays one symbol's signals into all-in trades one signal at a
+// time, for simulateTrades and for runs whose strategies read the position.
+type tradeSim struct {
+	symbol     string
+	ohlcv      []core.OHLCV
+	capital    float64
+	cash       float64
+	fills      *fillSimulator
+	trades     []Trade
+	rejections []Rejection
+	ledger     []holding
+	open       *Trade
+	openBar    int
+}
+
+func newTradeSim(symbol string, ohlcv []core.OHLCV, model *ExecutionModel, timing FillModel, capital float64) *tradeSim {
+	return &tradeSim{
+		symbol:  symbol,
+		ohlcv:   ohlcv,
+		capital: capital,
+		cash:    capital,
+		fills:   newFillSimulator(model, timing, ohlcv),
+		openBar: -1,
+	}
+}
+
+func (s *tradeSim) reject(sig core.Signal, side Side, reason string) {
+	s.rejections = append(s.rejections, Rejection{Time: sig.GeneratedAt, Symbol: s.symbol, Side: side, Reason: reason})
+}
+
+// apply acts on one signal: a buy opens a trade when flat, a sell closes the
+// open one.
+func (s *tradeSim) apply(sig core.Signal) {
+	switch sig.Action {
+	case core.ActionBuy, core.ActionStrongBuy:
+		// Only open a new trade if not already in a position
+		if s.open == nil {
+			f, reason := s.fills.buy(sig, s.cash)
+			if reason != "" {
+				s.reject(sig, SideBuy, reason)
+				return
 			}
-		case core.ActionSell, core.ActionStrongSell:
-			// Close the open trade if we have one
-			if openTrade != nil {
-				sigCopy := sig
-				openTrade.ExitSignal = &sigCopy
-				openTrade.ExitPrice = sig.Price
-				openTrade.Return = (openTrade.ExitPrice - openTrade.EntryPrice) / openTrade.EntryPrice
-				trades = append(trades, *openTrade)
-				openTrade = nil
+			s.cash -= f.cost()
+			s.ledger = append(s.ledger, holding{at: f.at, cash: s.cash, quantity: f.quantity})
+			s.openBar = f.bar
+			s.open = &Trade{
+				EntrySignal: sig,
+				EntryTime:   f.at,
+				EntryPrice:  f.price,
+				Quantity:    f.quantity,
+				Fees:        f.fees,
 			}
 		}
+	case core.ActionSell, core.ActionStrongSell:
+		// Close the open trade if we have one
+		if s.open != nil {
+			f, reason := s.fills.sell(sig, s.openBar, s.open.Quantity)
+			if reason != "" {
+				s.reject(sig, SideSell, reason)
+				return
+			}
+			s.cash += f.proceeds()
+			s.ledger = append(s.ledger, holding{at: f.at, cash: s.cash})
+			sigCopy := sig
+			s.open.ExitSignal = &sigCopy
+			s.open.ExitTime = f.at
+			s.open.ExitPrice = f.price
+			s.open.Fees += f.fees
+			settleTrade(s.open)
+			s.trades = append(s.trades, *s.open)
+			s.open = nil
+		}
+	}
+}
+
+// position is the open trade as seen at bar's close, nil when flat or when
+// the entry fills after bar.
+func (s *tradeSim) position(bar core.OHLCV) *strategy.Position {
+	if s.open == nil || s.open.EntryTime.After(bar.Time) {
+		return nil
+	}
+	return &strategy.Position{
+		Quantity:    s.open.Quantity,
+		AverageCost: s.open.EntryPrice,
+		EntryDate:   s.open.EntryTime,
 	}
+}
 
-	// Append any open trade at end (position still open)
-	if openTrade != nil {
+// finish marks any trade still open at the last close and returns the run.
+func (s *tradeSim) finish() simulation {
+	trades := s.trades
+	if s.open != nil {
 		// Use the last OHLCV close as the current price for open positions
-		if len(ohlcv) > 0 {
-			openTrade.ExitPrice = ohlcv[len(ohlcv)-1].Close
-			openTrade.Return = (openTrade.ExitPrice - openTrade.EntryPrice) / openTrade.EntryPrice
+		t := *s.open
+		if len(s.ohlcv) > 0 {
+			t.ExitTime = s.ohlcv[len(s.ohlcv)-1].Time
+			t.ExitPrice = s.ohlcv[len(s.ohlcv)-1].Close
+			settleTrade(&t)
 		}
-		trades = append(trades, *openTrade)
+		trades = append(trades, t)
+	}
+	return simulation{
+		trades:     trades,
+		rejections: s.rejections,
+		equity:     equityCurve(s.ohlcv, s.ledger, s.capital),
 	}
+}
+
+// simulation is the outcome of replaying one symbol's signals.
+type simulation struct {
+	trades     []Trade
+	rejections []Rejection
+	equity     []EquityPoint
+}
+
+// holding is the cash and position size after a fill.
+type holding struct {
+	at       time.Time
+	cash     float64
+	quantity float64
+}
+
+// equityCurve marks the holdings in force at each bar's close. A fill counts
+// from the bar it happened on.
+func equityCurve(ohlcv []core.OHLCV, ledger []holding, capital float64) []EquityPoint {
+	curve := make([]EquityPoint, 0, len(ohlcv))
+	current := holding{cash: capital}
+	k := 0
+	for _, bar := range ohlcv {
+		for k < len(ledger) && !ledger[k].at.After(bar.Time) {
+			current = ledger[k]
+			k++
+		}
+		positions := current.quantity * bar.Close
+		curve = append(curve, EquityPoint{
+			Time:      bar.Time,
+			Cash:      current.cash,
+			Positions: positions,
+			Equity:    current.cash + positions,
+			Close:     bar.Close,
+		})
+	}
+	return curve
+}
 
-	return trades
+// settleTrade fills in Return and PnL from the trade's prices, size and fees.
+func settleTrade(t *Trade) {
+	t.Return = tradeReturn(t.EntryPrice, t.ExitPrice, t.Quantity, t.Fees)
+	t.PnL = (t.ExitPrice-t.EntryPrice)*t.Quantity - t.Fees
 }
 
 // max returns the larger of two integers
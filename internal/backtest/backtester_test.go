@@ -334,3 +334,130 @@ func TestMax(t *testing.T) {
 		}
 	}
 }
+
+// indicatorStrategy records the indicator series it is given on each bar.
+type indicatorStrategy struct {
+	seen []map[string][]float64
+}
+
+func (s *indicatorStrategy) Name() string        { return "indicators" }
+func (s *indicatorStrategy) Description() string { return "records indicators" }
+func (s *indicatorStrategy) RequiredData() strategy.DataRequirements {
+	return strategy.DataRequirements{PriceHistory: 5, Indicators: []string{"sma_3"}}
+}
+func (s *indicatorStrategy) Init(cfg strategy.Config) error { return nil }
+func (s *indicatorStrategy) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	s.seen = append(s.seen, ctx.Indicators)
+	return nil, nil
+}
+
+func TestRun_ComputesDeclaredIndicators(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	var bars []core.OHLCV
+	for i, c := range []float64{1, 2, 3, 4, 5, 6, 7} {
+		bars = append(bars, core.OHLCV{Symbol: "AAPL", Close: c, Time: day.AddDate(0, 0, i)})
+	}
+	strat := &indicatorStrategy{}
+	if _, err := New(&mockProvider{data: bars}).Run(context.Background(), strat, "AAPL", day, day.AddDate(0, 0, 6)); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(strat.seen) != len(bars) {
+		t.Fatalf("analyzed %d bars, want %d", len(strat.seen), len(bars))
+	}
+	// Computed over the 5-bar window ending at each bar, never past it.
+	last := strat.seen[len(strat.seen)-1]["sma_3"]
+	if len(last) != 3 || last[len(last)-1] != 6 {
+		t.Errorf("sma_3 on the last bar = %v, want 3 values ending at 6", last)
+	}
+	if first := strat.seen[0]["sma_3"]; len(first) != 0 {
+		t.Errorf("sma_3 on the first bar = %v, want empty", first)
+	}
+}
+
+// timeframeStrategy records the multi-timeframe bars it is given on each bar.
+type timeframeStrategy struct {
+	seen []map[string][]core.OHLCV
+}
+
+func (s *timeframeStrategy) Name() string        { return "timeframes" }
+func (s *timeframeStrategy) Description() string { return "records timeframes" }
+func (s *timeframeStrategy) RequiredData() strategy.DataRequirements {
+	return strategy.DataRequirements{PriceHistory: 14, Timeframes: []string{"1w", "1h"}}
+}
+func (s *timeframeStrategy) Init(cfg strategy.Config) error { return nil }
+func (s *timeframeStrategy) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	s.seen = append(s.seen, ctx.Bars)
+	return nil, nil
+}
+
+func TestRun_ResamplesDeclaredTimeframes(t *testing.T) {
+	monday := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	var closes []float64
+	for i := range 17 {
+		closes = append(closes, float64(100+i))
+	}
+	bars := closesToBars("AAPL", monday, closes...)
+	strat := &timeframeStrategy{}
+	if _, err := New(&mockProvider{data: bars}).Run(context.Background(), strat, "AAPL", monday, bars[len(bars)-1].Time); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// The last window covers days 3-16: the tail of week one, week two and
+	// the start of week three, whose bar closes at the last day seen.
+	last := strat.seen[len(strat.seen)-1]
+	weekly := last["1w"]
+	if len(weekly) != 3 || weekly[2].Close != 116 || !weekly[2].Time.Equal(bars[16].Time) {
+		t.Errorf("weekly bars on the last day = %+v", weekly)
+	}
+	if _, ok := last["1h"]; ok {
+		t.Error("intraday bars cannot be replayed and should be left out")
+	}
+}
+
+// holdStrategy buys whenever it is shown no position and sells once the one
+// it is shown has been held for hold days, recording every position it sees.
+type holdStrategy struct {
+	hold int
+	seen []*strategy.Position
+}
+
+func (s *holdStrategy) Name() string        { return "hold" }
+func (s *holdStrategy) Description() string { return "holds for a fixed time" }
+func (s *holdStrategy) RequiredData() strategy.DataRequirements {
+	return strategy.DataRequirements{PriceHistory: 1}
+}
+func (s *holdStrategy) Init(cfg strategy.Config) error { return nil }
+func (s *holdStrategy) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	s.seen = append(s.seen, ctx.Position)
+	switch {
+	case ctx.Position == nil:
+		return []core.Signal{{Symbol: ctx.Symbol, Action: core.ActionBuy, Confidence: 0.8}}, nil
+	case ctx.Now.Sub(ctx.Position.EntryDate) >= time.Duration(s.hold)*24*time.Hour:
+		return []core.Signal{{Symbol: ctx.Symbol, Action: core.ActionSell, Confidence: 0.8}}, nil
+	}
+	return nil, nil
+}
+
+func TestRun_SuppliesSimulatedPosition(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	bars := closesToBars("AAPL", day, 100, 101, 102, 103, 104, 105)
+	strat := &holdStrategy{hold: 2}
+	res, err := New(&mockProvider{data: bars}).Run(context.Background(), strat, "AAPL", day, day.AddDate(0, 0, 5))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// Bought on day 0 and 3, sold two days later each time.
+	if len(res.Trades) != 2 || !res.Trades[0].IsClosed() || !res.Trades[1].IsClosed() {
+		t.Fatalf("trades = %+v", res.Trades)
+	}
+	if !res.Trades[1].EntryTime.Equal(day.AddDate(0, 0, 3)) {
+		t.Errorf("second entry = %v, want day 3", res.Trades[1].EntryTime)
+	}
+	pos := strat.seen[1]
+	if strat.seen[0] != nil || pos == nil || pos.Quantity != 1000 || pos.AverageCost != 100 ||
+		!pos.EntryDate.Equal(day) || pos.UnrealizedPL != 1000 {
+		t.Errorf("positions seen = %v, %+v", strat.seen[0], pos)
+	}
+	if strat.seen[3] != nil {
+		t.Errorf("flat after the day-2 exit, got %+v", strat.seen[3])
+	}
+}
@@ -0,0 +1,425 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/newthinker/atlas/internal/broker"
+	"github.com/newthinker/atlas/internal/broker/paper"
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// BrokerConfig configures a backtest through the live execution chain.
+type BrokerConfig struct {
+	// InitialCapital is the paper account's starting cash; <=0 uses
+	// DefaultInitialCapital.
+	InitialCapital float64
+	// Execution configures the ExecutionManager. An empty Mode means auto;
+	// orders queued in confirm or batch mode are confirmed on their bar.
+	Execution broker.ExecutionConfig
+	// Risk configures the RiskChecker.
+	Risk broker.RiskConfig
+	// Filter, when set, replays notification routing as WithSignalFilter
+	// does: only signals the filter admits reach the execution chain.
+	Filter func() SignalFilter
+	// Gate, when set, replays the signal gate as WithSignalGate does, before
+	// Filter.
+	Gate SignalGate
+	// Benchmark is the symbol the equity curve is compared against; "" means
+	// none.
+	Benchmark string
+	// Fundamentals feeds strategies that need fundamental data; nil fails
+	// runs that include such a strategy with ErrNoFundamentals.
+	Fundamentals FundamentalProvider
+}
+
+// BrokerBacktester replays strategies over many symbols through the same
+// chain live trading uses: each signal goes to a broker.ExecutionManager,
+// which sizes it from DefaultSizePct, runs the RiskChecker and places the
+// order with a paper.PaperBroker. The broker runs on bar time and is marked to
+// market at every close, so position caps, MaxOpenPositions and the daily
+// loss limit act as they would have on each day. Orders fill at the signal's
+// bar close without costs; the execution layer knows no other price.
+type BrokerBacktester struct {
+	provider OHLCVProvider
+	cfg      BrokerConfig
+}
+
+// NewBrokerBacktester creates a broker backtester with the given OHLCV
+// provider and configuration.
+func NewBrokerBacktester(provider OHLCVProvider, cfg BrokerConfig) *BrokerBacktester {
+	if cfg.InitialCapital <= 0 {
+		cfg.InitialCapital = DefaultInitialCapital
+	}
+	if cfg.Execution.Mode == "" {
+		cfg.Execution.Mode = broker.ExecutionAuto
+	}
+	return &BrokerBacktester{provider: provider, cfg: cfg}
+}
+
+// OrderStatus is the outcome of sending one signal to the execution chain.
+type OrderStatus string
+
+const (
+	// OrderFilled means the order was placed and filled.
+	OrderFilled OrderStatus = "filled"
+	// OrderRejected means the ExecutionManager declined the signal: a risk
+	// check failed or the sized quantity was zero.
+	OrderRejected OrderStatus = "rejected"
+	// OrderFailed means the execution chain returned an error, e.g. the
+	// broker refused the order.
+	OrderFailed OrderStatus = "failed"
+)
+
+// OrderAttempt is one signal sent to the execution chain and what came of it.
+type OrderAttempt struct {
+	Time     time.Time
+	Signal   core.Signal
+	Status   OrderStatus
+	Side     broker.OrderSide // set for filled orders
+	Quantity int64            // filled quantity
+	Price    float64          // fill price
+	Reason   string           // why the order was rejected or failed
+}
+
+// BrokerResult holds the output of a broker backtest.
+type BrokerResult struct {
+	Strategies     []string
+	Symbols        []string
+	MissingSymbols []string
+	StartDate      time.Time
+	EndDate        time.Time
+	InitialCapital float64
+	FinalEquity    float64
+	Execution      broker.ExecutionConfig
+	Risk           broker.RiskConfig
+	// Signals holds every generated signal; Orders the ones sent to the
+	// execution chain, in the order they were sent.
+	Signals  []core.Signal
+	Orders   []OrderAttempt
+	Filled   int
+	Rejected int
+	Failed   int
+	// RejectedBy counts rejections and failures per reason, with the
+	// per-order figures stripped (e.g. "max open positions reached").
+	RejectedBy map[string]int
+	// Trades are round trips rebuilt from the fills: a trade opens when a
+	// position is established and closes when it is flat again; adds and
+	// partial sells are averaged in. Positions still held at the end are
+	// marked at the last close.
+	Trades []PortfolioTrade
+	// Positions are the broker's positions at the end of the run.
+	Positions   []broker.Position
+	Equity      []EquityPoint
+	Stats       Stats
+	Routing     *Routing
+	SkippedBars int
+}
+
+// brokerTrade accumulates the fills of one round trip.
+type brokerTrade struct {
+	trade    PortfolioTrade
+	held     int64
+	bought   float64 // notional of all buys
+	sold     float64 // notional of all sells
+	soldQty  int64
+	lastExit *core.Signal
+}
+
+// Run executes the broker backtest. As in PortfolioBacktester.Run, symbols
+// without history are listed in MissingSymbols and the run fails only when no
+// asset has data. Signals are executed in the order they are generated:
+// assets in the given order, strategies in their binding order.
+func (b *BrokerBacktester) Run(ctx context.Context, strategies []strategy.Strategy, assets []Asset, start, end time.Time) (*BrokerResult, error) {
+	result, err := b.run(ctx, strategies, assets, start, end, b.cfg.Filter, b.cfg.Gate)
+	if err != nil || (b.cfg.Filter == nil && b.cfg.Gate == nil) {
+		return result, err
+	}
+	// As in Backtester.Run, report what executing every signal would have
+	// given next to the routed run.
+	all, err := b.run(ctx, strategies, assets, start, end, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	result.Routing.Unfiltered = all.Stats
+	return result, nil
+}
+
+// run replays the execution chain once, routing signals through gate and a
+// fresh filter from newFilter when they are set.
+func (b *BrokerBacktester) run(ctx context.Context, strategies []strategy.Strategy, assets []Asset, start, end time.Time, newFilter func() SignalFilter, gate SignalGate) (*BrokerResult, error) {
+	if len(strategies) == 0 {
+		return nil, errors.New("no strategies to backtest")
+	}
+	if len(assets) == 0 {
+		return nil, errors.New("no assets to backtest")
+	}
+
+	byName := make(map[string]strategy.Strategy, len(strategies))
+	names := make([]string, 0, len(strategies))
+	for _, s := range strategies {
+		byName[s.Name()] = s
+		names = append(names, s.Name())
+	}
+
+	result := &BrokerResult{
+		Strategies:     names,
+		StartDate:      start,
+		EndDate:        end,
+		InitialCapital: b.cfg.InitialCapital,
+		Execution:      b.cfg.Execution,
+		Risk:           b.cfg.Risk,
+		RejectedBy:     make(map[string]int),
+	}
+
+	var runs []*assetRun
+	for _, a := range assets {
+		bars, err := b.provider.FetchHistory(a.Symbol, start, end, "1d")
+		if err != nil || len(bars) == 0 {
+			result.MissingSymbols = append(result.MissingSymbols, a.Symbol)
+			continue
+		}
+		run := &assetRun{symbol: a.Symbol, bars: bars}
+		if len(a.Strategies) == 0 {
+			run.strategies = strategies
+		} else {
+			for _, name := range a.Strategies {
+				if s, ok := byName[name]; ok {
+					run.strategies = append(run.strategies, s)
+				}
+			}
+		}
+		if len(run.strategies) == 0 {
+			continue
+		}
+		run.funds, err = fundamentalsFor(b.cfg.Fundamentals, a.Symbol, bars, run.strategies...)
+		if errors.Is(err, ErrNoFundamentals) {
+			return nil, err
+		}
+		if err != nil {
+			result.MissingSymbols = append(result.MissingSymbols, a.Symbol)
+			continue
+		}
+		runs = append(runs, run)
+		result.Symbols = append(result.Symbols, a.Symbol)
+	}
+	if len(runs) == 0 {
+		return nil, errors.New("no historical data available")
+	}
+
+	// The live chain of cmd/atlas buildExecution, on a simulated clock.
+	var clock time.Time
+	pb := paper.New(b.cfg.InitialCapital)
+	pb.SetClock(func() time.Time { return clock })
+	if err := pb.Connect(ctx); err != nil {
+		return nil, fmt.Errorf("connecting paper broker: %w", err)
+	}
+	defer pb.Disconnect()
+	tracker := broker.NewPositionTracker(pb)
+	em := broker.NewExecutionManager(b.cfg.Execution, pb, broker.NewRiskChecker(b.cfg.Risk, pb), tracker)
+
+	var filter SignalFilter
+	if newFilter != nil {
+		filter = newFilter()
+	}
+	if newFilter != nil || gate != nil {
+		result.Routing = newRouting()
+	}
+
+	open := make(map[string]*brokerTrade)
+	for _, day := range tradingDays(runs) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+		clock = day
+
+		var daySignals []core.Signal
+		assets, skipped := analyzeDay(runs, day, func(symbol string) *strategy.Position { return trackedPosition(tracker, symbol) })
+		result.SkippedBars += skipped
+		for _, a := range assets {
+			daySignals = append(daySignals, a.signals...)
+		}
+		prices := make(map[string]float64, len(runs))
+		for _, r := range runs {
+			if r.lastClose > 0 {
+				prices[r.symbol] = r.lastClose
+			}
+		}
+		result.Signals = append(result.Signals, daySignals...)
+
+		// Revalue at today's closes before any order, so sizing and the risk
+		// limits see the day's account value and P&L.
+		pb.MarkToMarket(prices)
+
+		for _, sig := range daySignals {
+			if result.Routing != nil {
+				routed, ok, err := result.Routing.route(gate, filter, sig)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					continue
+				}
+				sig = routed
+			}
+			if !isBuy(sig.Action) && !isSell(sig.Action) {
+				continue // the live signal adapter skips these too
+			}
+			attempt := b.execute(ctx, em, sig)
+			result.Orders = append(result.Orders, attempt)
+			switch attempt.Status {
+			case OrderFilled:
+				result.Filled++
+				recordFill(open, &result.Trades, sig, attempt)
+			case OrderRejected:
+				result.Rejected++
+				result.RejectedBy[rejectionKey(attempt.Reason)]++
+			case OrderFailed:
+				result.Failed++
+				result.RejectedBy[rejectionKey(attempt.Reason)]++
+			}
+		}
+
+		bal, err := pb.GetBalance(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("reading paper balance: %w", err)
+		}
+		result.Equity = append(result.Equity, EquityPoint{
+			Time:      day,
+			Cash:      bal.Cash,
+			Positions: bal.TotalValue - bal.Cash,
+			Equity:    bal.TotalValue,
+		})
+	}
+
+	// Positions still held at the end are marked at the last close.
+	for _, r := range runs {
+		bt, ok := open[r.symbol]
+		if !ok {
+			continue
+		}
+		t := bt.trade
+		t.Quantity = float64(bt.held + bt.soldQty)
+		t.EntryPrice = bt.bought / t.Quantity
+		t.ExitTime = r.bars[len(r.bars)-1].Time
+		t.ExitPrice = (bt.sold + float64(bt.held)*r.lastClose) / t.Quantity
+		settleTrade(&t.Trade)
+		result.Trades = append(result.Trades, t)
+	}
+
+	positions, err := pb.GetPositions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading paper positions: %w", err)
+	}
+	result.Positions = positions
+
+	result.FinalEquity = b.cfg.InitialCapital
+	if n := len(result.Equity); n > 0 {
+		result.FinalEquity = result.Equity[n-1].Equity
+	}
+	result.Stats = portfolioStats(result.Trades, result.Equity, b.cfg.InitialCapital)
+	if b.cfg.Benchmark != "" {
+		if bars, err := b.provider.FetchHistory(b.cfg.Benchmark, start, end, "1d"); err == nil {
+			applyBenchmarkStats(&result.Stats, b.cfg.Benchmark, result.Equity, bars)
+		}
+	}
+	return result, nil
+}
+
+// trackedPosition is the tracker's long position in symbol as strategies see
+// it, nil when none is held.
+func trackedPosition(tracker *broker.PositionTracker, symbol string) *strategy.Position {
+	pos := tracker.GetPosition(symbol)
+	if pos.Quantity <= 0 {
+		return nil
+	}
+	return &strategy.Position{
+		Quantity:    float64(pos.Quantity),
+		AverageCost: pos.AverageCost,
+		EntryDate:   pos.OpenedAt,
+	}
+}
+
+// execute sends one signal to the execution manager at its bar close,
+// confirming at once any order the configured mode queues.
+func (b *BrokerBacktester) execute(ctx context.Context, em *broker.ExecutionManager, sig core.Signal) OrderAttempt {
+	attempt := OrderAttempt{Time: sig.GeneratedAt, Signal: sig}
+	res, err := em.Execute(ctx, &sig, sig.Price)
+	if err == nil && res.Success && res.PendingID != "" {
+		res, err = em.Confirm(ctx, res.PendingID)
+	}
+	switch {
+	case err != nil:
+		attempt.Status = OrderFailed
+		attempt.Reason = err.Error()
+	case !res.Success:
+		attempt.Status = OrderRejected
+		attempt.Reason = res.Message
+	case res.Order == nil || !res.Order.IsFilled():
+		attempt.Status = OrderFailed
+		attempt.Reason = "order not filled"
+	default:
+		attempt.Status = OrderFilled
+		attempt.Side = res.Order.Side
+		attempt.Quantity = res.Order.FilledQuantity
+		attempt.Price = res.Order.AverageFillPrice
+	}
+	return attempt
+}
+
+// recordFill folds a fill into the symbol's open round trip, appending the
+// trade to trades once the position is flat again.
+func recordFill(open map[string]*brokerTrade, trades *[]PortfolioTrade, sig core.Signal, f OrderAttempt) {
+	notional := float64(f.Quantity) * f.Price
+	bt, ok := open[sig.Symbol]
+	if f.Side == broker.OrderSideBuy {
+		if !ok {
+			bt = &brokerTrade{trade: PortfolioTrade{
+				Trade:  Trade{EntrySignal: sig, EntryTime: f.Time},
+				Symbol: sig.Symbol,
+			}}
+			open[sig.Symbol] = bt
+		}
+		bt.held += f.Quantity
+		bt.bought += notional
+		return
+	}
+	if !ok {
+		return // the paper broker refuses to sell what is not held
+	}
+	exit := sig
+	bt.held -= f.Quantity
+	bt.sold += notional
+	bt.soldQty += f.Quantity
+	bt.lastExit = &exit
+	if bt.held > 0 {
+		return
+	}
+	t := bt.trade
+	t.Quantity = float64(bt.soldQty)
+	t.EntryPrice = bt.bought / t.Quantity
+	t.ExitSignal = bt.lastExit
+	t.ExitTime = f.Time
+	t.ExitPrice = bt.sold / t.Quantity
+	settleTrade(&t.Trade)
+	*trades = append(*trades, t)
+	delete(open, sig.Symbol)
+}
+
+// rejectionKey reduces an execution message to its reason, dropping the
+// "risk check failed" prefix and the per-order figures after the colon.
+func rejectionKey(msg string) string {
+	msg = strings.TrimPrefix(msg, "risk check failed: ")
+	msg = strings.TrimPrefix(msg, "execution: failed to place order: ")
+	msg = strings.TrimPrefix(msg, "broker: ")
+	if i := strings.Index(msg, ":"); i > 0 {
+		msg = msg[:i]
+	}
+	return msg
+}
@@ -0,0 +1,188 @@
+package backtest
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/broker"
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+func runBroker(t *testing.T, provider OHLCVProvider, cfg BrokerConfig, strat strategy.Strategy, symbols ...string) *BrokerResult {
+	t.Helper()
+	var assets []Asset
+	for _, s := range symbols {
+		assets = append(assets, Asset{Symbol: s})
+	}
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	res, err := NewBrokerBacktester(provider, cfg).Run(context.Background(), []strategy.Strategy{strat}, assets, start, start.AddDate(0, 0, 10))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	return res
+}
+
+func TestBrokerBacktester_SizingAndPartialExit(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	provider := symbolProvider{"AAPL": closesToBars("AAPL", day, 100, 110, 120)}
+	strat := scriptStrategy{
+		day:                  {Action: core.ActionBuy, Confidence: 0.9},
+		day.AddDate(0, 0, 2): {Action: core.ActionSell, Confidence: 0.9},
+	}
+	res := runBroker(t, provider, BrokerConfig{
+		InitialCapital: 10000,
+		Execution:      broker.ExecutionConfig{DefaultSizePct: 10},
+		Risk:           broker.DefaultRiskConfig(),
+	}, strat, "AAPL")
+
+	if res.Filled != 2 || len(res.Orders) != 2 {
+		t.Fatalf("orders = %+v", res.Orders)
+	}
+	// Buy 10% of 10000 at 100 = 10 shares. The sell is sized from equity too:
+	// 10% of 10200 at 120 = 8 shares, leaving 2 held.
+	if q := res.Orders[0].Quantity; q != 10 {
+		t.Errorf("buy quantity = %d, want 10", q)
+	}
+	if q := res.Orders[1].Quantity; q != 8 {
+		t.Errorf("sell quantity = %d, want 8", q)
+	}
+	if len(res.Positions) != 1 || res.Positions[0].Quantity != 2 {
+		t.Errorf("positions = %+v", res.Positions)
+	}
+	if len(res.Trades) != 1 || res.Trades[0].IsClosed() || math.Abs(res.Trades[0].PnL-200) > 1e-9 {
+		t.Errorf("trades = %+v", res.Trades)
+	}
+	if len(res.Equity) != 3 || res.Equity[1].Equity != 10100 || res.FinalEquity != 10200 {
+		t.Errorf("equity = %+v", res.Equity)
+	}
+	if !res.Orders[0].Time.Equal(day) {
+		t.Errorf("order time = %v, want bar time", res.Orders[0].Time)
+	}
+}
+
+func TestBrokerBacktester_RiskLimits(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	provider := symbolProvider{
+		"AAA": closesToBars("AAA", day, 100),
+		"BBB": closesToBars("BBB", day, 100),
+		"CCC": closesToBars("CCC", day, 100),
+	}
+	strat := scriptStrategy{day: {Action: core.ActionBuy, Confidence: 0.9}}
+
+	capped := runBroker(t, provider, BrokerConfig{
+		InitialCapital: 10000,
+		Execution:      broker.ExecutionConfig{DefaultSizePct: 10},
+		Risk:           broker.RiskConfig{MaxPositionPct: 20, MaxDailyLossPct: 5, MaxOpenPositions: 2},
+	}, strat, "AAA", "BBB", "CCC")
+	if capped.Filled != 2 || capped.Rejected != 1 || capped.RejectedBy["max open positions reached"] != 1 {
+		t.Errorf("MaxOpenPositions: filled=%d rejected=%v", capped.Filled, capped.RejectedBy)
+	}
+	if capped.Orders[2].Signal.Symbol != "CCC" {
+		t.Errorf("orders should follow generation order, got %+v", capped.Orders)
+	}
+
+	oversized := runBroker(t, provider, BrokerConfig{
+		InitialCapital: 10000,
+		Execution:      broker.ExecutionConfig{DefaultSizePct: 30},
+		Risk:           broker.RiskConfig{MaxPositionPct: 20, MaxDailyLossPct: 5, MaxOpenPositions: 20},
+	}, strat, "AAA", "BBB", "CCC")
+	if oversized.Filled != 0 || oversized.RejectedBy["position size too large"] != 3 {
+		t.Errorf("MaxPositionPct: filled=%d rejected=%v", oversized.Filled, oversized.RejectedBy)
+	}
+	if oversized.FinalEquity != 10000 {
+		t.Errorf("FinalEquity = %v, want untouched capital", oversized.FinalEquity)
+	}
+}
+
+func TestBrokerBacktester_DailyLossOnBarTime(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	provider := symbolProvider{
+		"AAA": closesToBars("AAA", day, 100, 80),
+		"BBB": closesToBars("BBB", day, 50, 50),
+	}
+	strat := scriptStrategy{
+		day:                  {Action: core.ActionBuy, Confidence: 0.9},
+		day.AddDate(0, 0, 1): {Action: core.ActionBuy, Confidence: 0.9},
+	}
+	// AAA is bought with half the account; its 20% drop the next day is a 10%
+	// daily loss, which halts the next day's buys.
+	res := runBroker(t, provider, BrokerConfig{
+		InitialCapital: 10000,
+		Execution:      broker.ExecutionConfig{DefaultSizePct: 50},
+		Risk:           broker.RiskConfig{MaxPositionPct: 60, MaxDailyLossPct: 5, MaxOpenPositions: 20},
+	}, strat, "AAA")
+	if res.Filled != 1 || res.RejectedBy["daily loss limit reached"] != 1 {
+		t.Errorf("filled=%d rejected=%v", res.Filled, res.RejectedBy)
+	}
+}
+
+func TestBrokerBacktester_BrokerErrorsAreReported(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	provider := symbolProvider{"AAPL": closesToBars("AAPL", day, 100, 100, 80)}
+	strat := scriptStrategy{
+		day:                  {Action: core.ActionSell, Confidence: 0.9}, // nothing held
+		day.AddDate(0, 0, 1): {Action: core.ActionBuy, Confidence: 0.9},
+		day.AddDate(0, 0, 2): {Action: core.ActionSell, Confidence: 0.9}, // sized above the holding
+	}
+	res := runBroker(t, provider, BrokerConfig{
+		InitialCapital: 10000,
+		Execution:      broker.ExecutionConfig{DefaultSizePct: 10},
+		Risk:           broker.DefaultRiskConfig(),
+	}, strat, "AAPL")
+	if res.Filled != 1 || res.Failed != 2 || res.RejectedBy["invalid quantity"] != 2 {
+		t.Errorf("filled=%d failed=%d rejected=%v", res.Filled, res.Failed, res.RejectedBy)
+	}
+	if len(res.Positions) != 1 || res.Positions[0].Quantity != 10 {
+		t.Errorf("positions = %+v", res.Positions)
+	}
+}
+
+func TestBrokerBacktester_ConfirmModeAndRouting(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	provider := symbolProvider{"AAPL": closesToBars("AAPL", day, 100, 100, 100)}
+	strat := scriptStrategy{
+		day:                  {Action: core.ActionBuy, Confidence: 0.9},
+		day.AddDate(0, 0, 1): {Action: core.ActionBuy, Confidence: 0.9}, // within the cooldown
+		day.AddDate(0, 0, 2): {Action: core.ActionBuy, Confidence: 0.9},
+	}
+	res := runBroker(t, provider, BrokerConfig{
+		InitialCapital: 10000,
+		Execution:      broker.ExecutionConfig{Mode: broker.ExecutionConfirm, DefaultSizePct: 10},
+		Risk:           broker.DefaultRiskConfig(),
+		Filter: func() SignalFilter {
+			return &cooldownFilter{cooldown: 48 * time.Hour, last: map[string]time.Time{}}
+		},
+	}, strat, "AAPL")
+	if res.Routing == nil || res.Routing.Notified != 2 || res.Routing.SuppressedBy["cooldown"] != 1 {
+		t.Fatalf("routing = %+v", res.Routing)
+	}
+	if len(res.Signals) != 3 || len(res.Orders) != 2 || res.Filled != 2 {
+		t.Errorf("signals=%d orders=%+v", len(res.Signals), res.Orders)
+	}
+	if len(res.Positions) != 1 || res.Positions[0].Quantity != 20 {
+		t.Errorf("positions = %+v", res.Positions)
+	}
+}
+
+func TestBrokerBacktester_SuppliesTrackedPosition(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	provider := symbolProvider{"AAPL": closesToBars("AAPL", day, 100, 101, 102)}
+	strat := &holdStrategy{hold: 5}
+	runBroker(t, provider, BrokerConfig{
+		InitialCapital: 10000,
+		Execution:      broker.ExecutionConfig{DefaultSizePct: 10},
+		Risk:           broker.DefaultRiskConfig(),
+	}, strat, "AAPL")
+
+	// 10% of 10000 at 100 = 10 shares, dated at the bar it filled on.
+	if strat.seen[0] != nil {
+		t.Errorf("position on day 0 = %+v, want none", strat.seen[0])
+	}
+	if pos := strat.seen[2]; pos == nil || pos.Quantity != 10 || pos.AverageCost != 100 ||
+		!pos.EntryDate.Equal(day) || pos.UnrealizedPL != 20 {
+		t.Errorf("position on day 2 = %+v", pos)
+	}
+}
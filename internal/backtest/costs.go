@@ -0,0 +1,118 @@
+package backtest
+
+import (
+	"math"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/indicator"
+)
+
+// Side is the direction of a simulated fill.
+type Side string
+
+const (
+	SideBuy  Side = "buy"
+	SideSell Side = "sell"
+)
+
+// CostModel prices the fees charged on one fill.
+type CostModel interface {
+	Fees(side Side, price, quantity float64) float64
+}
+
+// SlippageModel moves a fill price against the trader. bars[i] is the bar the
+// fill happens on; models may look back from it but never forward.
+type SlippageModel interface {
+	Apply(side Side, price float64, bars []core.OHLCV, i int) float64
+}
+
+// CommissionSchedule is a broker commission: Rate of notional per order,
+// never less than Minimum.
+type CommissionSchedule struct {
+	Rate    float64
+	Minimum float64
+}
+
+// MarketCosts is the standard CostModel: broker commission plus the
+// transaction taxes and exchange levies of a market. Rates are fractions of
+// notional (0.0005 = 5 bps).
+type MarketCosts struct {
+	Commission CommissionSchedule
+	BuyTax     float64 // Stamp duty charged on buys (HK)
+	SellTax    float64 // Stamp duty / regulatory fee charged on sells (CN_A, HK, US SEC fee)
+	Levy       float64 // Exchange and regulator levies on both sides
+	// RoundTaxUp rounds stamp duty up to a whole currency unit, as HK does.
+	RoundTaxUp bool
+}
+
+// Fees returns the total cost of a fill of quantity shares at price.
+func (c MarketCosts) Fees(side Side, price, quantity float64) float64 {
+	notional := price * quantity
+	if notional <= 0 {
+		return 0
+	}
+	commission := math.Max(notional*c.Commission.Rate, c.Commission.Minimum)
+
+	taxRate := c.SellTax
+	if side == SideBuy {
+		taxRate = c.BuyTax
+	}
+	tax := notional * taxRate
+	if c.RoundTaxUp {
+		tax = math.Ceil(tax)
+	}
+	return commission + tax + notional*c.Levy
+}
+
+// BpsSlippage moves the fill price a fixed number of basis points.
+type BpsSlippage struct {
+	Bps float64
+}
+
+func (s BpsSlippage) Apply(side Side, price float64, bars []core.OHLCV, i int) float64 {
+	return slip(side, price, price*s.Bps/10000)
+}
+
+// ATRSlippage moves the fill price by Multiple times the average true range
+// over the Period bars ending at the fill bar. With too little history it
+// falls back to the fill bar's own range.
+type ATRSlippage struct {
+	Period   int
+	Multiple float64
+}
+
+func (s ATRSlippage) Apply(side Side, price float64, bars []core.OHLCV, i int) float64 {
+	if i < 0 || i >= len(bars) {
+		return price
+	}
+	return slip(side, price, averageTrueRange(bars[:i+1], s.Period)*s.Multiple)
+}
+
+// slip moves price by amount against the trader: buys fill higher, sells lower.
+func slip(side Side, price, amount float64) float64 {
+	if side == SideBuy {
+		return price + amount
+	}
+	return math.Max(price-amount, 0)
+}
+
+// averageTrueRange is the simple mean true range of the last period bars.
+func averageTrueRange(bars []core.OHLCV, period int) float64 {
+	if len(bars) == 0 {
+		return 0
+	}
+	if period <= 0 {
+		period = 14
+	}
+	// One extra bar in front supplies the previous close of the first range.
+	start := max(0, len(bars)-period)
+	tr := indicator.TrueRange(bars[max(0, start-1):])
+	if start > 0 {
+		tr = tr[1:]
+	}
+	var sum float64
+	for _, v := range tr {
+		sum += v
+	}
+	return sum / float64(len(tr))
+}
@@ -0,0 +1,64 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/newthinker/atlas/internal/core"
+)
+
+func TestMarketCosts_Fees(t *testing.T) {
+	tests := []struct {
+		name   string
+		market core.Market
+		side   Side
+		price  float64
+		qty    float64
+		want   float64
+	}{
+		// 1000 notional: commission floor 5 + stamp duty 0.5 + transfer 0.01.
+		{"CN_A sell hits commission minimum", core.MarketCNA, SideSell, 10, 100, 5.51},
+		// Buys pay no stamp duty in A 股.
+		{"CN_A buy", core.MarketCNA, SideBuy, 10, 100, 5.01},
+		// 5001 notional: commission floor 3 + stamp duty ceil(5.001)=6 + levies.
+		{"HK stamp duty rounds up", core.MarketHK, SideBuy, 50.01, 100, 3 + 6 + 5001*0.000085},
+		{"US buy is free", core.MarketUS, SideBuy, 100, 10, 0},
+		{"US sell pays SEC fee", core.MarketUS, SideSell, 100, 10, 1000 * 0.0000278},
+		{"zero quantity", core.MarketCNA, SideBuy, 10, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ModelForMarket(tt.market).Costs.Fees(tt.side, tt.price, tt.qty)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Fees = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBpsSlippage(t *testing.T) {
+	s := BpsSlippage{Bps: 10}
+	if got := s.Apply(SideBuy, 100, nil, 0); math.Abs(got-100.1) > 1e-9 {
+		t.Errorf("buy fill = %v, want 100.1", got)
+	}
+	if got := s.Apply(SideSell, 100, nil, 0); math.Abs(got-99.9) > 1e-9 {
+		t.Errorf("sell fill = %v, want 99.9", got)
+	}
+}
+
+func TestATRSlippage(t *testing.T) {
+	bars := []core.OHLCV{
+		{High: 11, Low: 9, Close: 10},
+		{High: 12, Low: 10, Close: 11},
+		{High: 15, Low: 11, Close: 14}, // true range 4
+	}
+	// ATR over the last two bars = (2 + 4) / 2 = 3; half of it is 1.5.
+	s := ATRSlippage{Period: 2, Multiple: 0.5}
+	if got := s.Apply(SideBuy, 14, bars, 2); math.Abs(got-15.5) > 1e-9 {
+		t.Errorf("buy fill = %v, want 15.5", got)
+	}
+	// Only bars up to the fill bar are used.
+	if got := s.Apply(SideSell, 11, bars, 1); math.Abs(got-10) > 1e-9 {
+		t.Errorf("sell fill = %v, want 10", got)
+	}
+}
@@ -0,0 +1,340 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/newthinker/atlas/internal/collector"
+	"github.com/newthinker/atlas/internal/core"
+)
+
+// TradingRules are the exchange rules a fill must respect.
+type TradingRules struct {
+	// LotSize is the board lot; buys are rounded down to whole lots. 0 allows
+	// fractional quantities (crypto), 1 means whole shares.
+	LotSize float64
+	// LotApprox marks LotSize as a stand-in for board lots that vary by
+	// security (港股每手股数由发行人自定), so lot rounding is approximate.
+	LotApprox bool
+	// SettlementDays is the number of bars a bought position must be held
+	// before it can be sold (1 = A 股 T+1); 0 allows same-day round trips.
+	SettlementDays int
+	// PriceLimitPct is the daily price limit in percent of the previous close
+	// (A 股 10%, 创业板/科创板 20%). Buys at limit-up and sells at
+	// limit-down are rejected; 0 disables the check.
+	PriceLimitPct float64
+}
+
+// ExecutionModel bundles the costs, slippage and trading rules of one market.
+// A nil Costs or Slippage charges nothing.
+type ExecutionModel struct {
+	Market   core.Market
+	Costs    CostModel
+	Slippage SlippageModel
+	Rules    TradingRules
+}
+
+// ModelSelector picks the execution model of a symbol; a nil model means
+// frictionless fills at the signal price.
+type ModelSelector func(symbol string) *ExecutionModel
+
+// MarketModels is the ModelSelector that applies ModelForSymbol.
+func MarketModels(symbol string) *ExecutionModel {
+	m := ModelForSymbol(symbol)
+	return &m
+}
+
+// ModelForMarket returns the default execution model of a market: typical
+// retail commission, the statutory taxes and levies, a flat slippage
+// allowance and the exchange's lot and settlement rules.
+func ModelForMarket(market core.Market) ExecutionModel {
+	switch market {
+	case core.MarketCNA:
+		// 佣金万 2.5 最低 5 元，印花税卖出 0.05%，过户费 0.001%；T+1，涨跌停 10%。
+		return ExecutionModel{
+			Market: market,
+			Costs: MarketCosts{
+				Commission: CommissionSchedule{Rate: 0.00025, Minimum: 5},
+				SellTax:    0.0005,
+				Levy:       0.00001,
+			},
+			Slippage: BpsSlippage{Bps: 5},
+			Rules:    TradingRules{LotSize: 100, SettlementDays: 1, PriceLimitPct: 10},
+		}
+	case core.MarketHK:
+		// 佣金 0.03% 最低 3 港元，双边印花税 0.1%（不足 1 元按 1 元计），
+		// 交易征费 + 交易费 + 会财局征费合计约 0.0085%。
+		return ExecutionModel{
+			Market: market,
+			Costs: MarketCosts{
+				Commission: CommissionSchedule{Rate: 0.0003, Minimum: 3},
+				BuyTax:     0.001,
+				SellTax:    0.001,
+				Levy:       0.000085,
+				RoundTaxUp: true,
+			},
+			Slippage: BpsSlippage{Bps: 10},
+			// 港股每手股数因股而异（100 至数千股不等），离线无逐股数据，按 100 近似。
+			Rules: TradingRules{LotSize: 100, LotApprox: true},
+		}
+	case core.MarketUS:
+		// Commission-free broker; SEC Section 31 fee on sells only.
+		return ExecutionModel{
+			Market:   market,
+			Costs:    MarketCosts{SellTax: 0.0000278},
+			Slippage: BpsSlippage{Bps: 5},
+			Rules:    TradingRules{LotSize: 1},
+		}
+	case core.MarketCrypto:
+		return ExecutionModel{
+			Market:   market,
+			Costs:    MarketCosts{Commission: CommissionSchedule{Rate: 0.001}},
+			Slippage: BpsSlippage{Bps: 10},
+		}
+	case core.MarketEU:
+		return ExecutionModel{
+			Market:   market,
+			Costs:    MarketCosts{Commission: CommissionSchedule{Rate: 0.001}},
+			Slippage: BpsSlippage{Bps: 5},
+			Rules:    TradingRules{LotSize: 1},
+		}
+	}
+	return ExecutionModel{Market: market}
+}
+
+// ModelForSymbol returns the execution model of symbol's market, with the
+// 20% price limit for 创业板 (300/301.SZ) and 科创板 (688/689.SH) stocks.
+func ModelForSymbol(symbol string) ExecutionModel {
+	m := ModelForMarket(collector.MarketForSymbol(symbol))
+	if m.Market == core.MarketCNA && isWideLimitBoard(symbol) {
+		m.Rules.PriceLimitPct = 20
+	}
+	return m
+}
+
+func isWideLimitBoard(symbol string) bool {
+	s := strings.ToUpper(symbol)
+	switch {
+	case strings.HasSuffix(s, ".SZ"):
+		return strings.HasPrefix(s, "300") || strings.HasPrefix(s, "301")
+	case strings.HasSuffix(s, ".SH"):
+		return strings.HasPrefix(s, "688") || strings.HasPrefix(s, "689")
+	}
+	return false
+}
+
+// Describe summarises the model for reports, e.g.
+// "CN_A: lot 100, T+1, ±10% limit".
+func (m ExecutionModel) Describe() string {
+	parts := []string{}
+	if m.Rules.LotSize > 1 {
+		lot := fmt.Sprintf("lot %g", m.Rules.LotSize)
+		if m.Rules.LotApprox {
+			lot += " (approximate: board lots vary by stock)"
+		}
+		parts = append(parts, lot)
+	}
+	if m.Rules.SettlementDays > 0 {
+		parts = append(parts, fmt.Sprintf("T+%d", m.Rules.SettlementDays))
+	}
+	if m.Rules.PriceLimitPct > 0 {
+		parts = append(parts, fmt.Sprintf("±%g%% limit", m.Rules.PriceLimitPct))
+	}
+	if m.Costs == nil && m.Slippage == nil {
+		parts = append(parts, "no costs")
+	}
+	if len(parts) == 0 {
+		return string(m.Market)
+	}
+	return string(m.Market) + ": " + strings.Join(parts, ", ")
+}
+
+// limitTolerance treats a move within 0.1% of the limit as locked at the
+// limit: limit prices are rounded to the tick and adjusted history shifts them.
+const limitTolerance = 0.001
+
+// Rejection records an order that could not be filled: refused by the
+// trading rules, or left without a bar to fill on.
+type Rejection struct {
+	Time   time.Time
+	Symbol string
+	Side   Side
+	Reason string
+}
+
+// fill is one simulated execution.
+type fill struct {
+	bar      int // index of the bar filled on; -1 when the signal matches no bar
+	at       time.Time
+	price    float64
+	quantity float64
+	fees     float64
+}
+
+// cost is the cash a buy fill consumes.
+func (f fill) cost() float64 { return f.price*f.quantity + f.fees }
+
+// proceeds is the cash a sell fill returns.
+func (f fill) proceeds() float64 { return f.price*f.quantity - f.fees }
+
+// fillSimulator applies a FillModel and an ExecutionModel to the signals of
+// one symbol. A nil model fills every order at the fill price with no costs
+// or rules.
+type fillSimulator struct {
+	model  *ExecutionModel
+	timing FillModel
+	bars   []core.OHLCV
+	index  map[time.Time]int
+}
+
+func newFillSimulator(model *ExecutionModel, timing FillModel, bars []core.OHLCV) *fillSimulator {
+	index := make(map[time.Time]int, len(bars))
+	for i, b := range bars {
+		index[b.Time] = i
+	}
+	return &fillSimulator{model: model, timing: timing, bars: bars, index: index}
+}
+
+// barIndex locates the bar a signal was generated on, or -1.
+func (s *fillSimulator) barIndex(sig core.Signal) int {
+	if i, ok := s.index[sig.GeneratedAt]; ok {
+		return i
+	}
+	return -1
+}
+
+// fillBar returns the bar a signal fills on and the price before slippage.
+// ok is false when a next-bar model has no bar after the signal.
+func (s *fillSimulator) fillBar(sig core.Signal) (i int, price float64, ok bool) {
+	i = s.barIndex(sig)
+	if s.timing.sameBar() {
+		return i, sig.Price, true
+	}
+	if i < 0 || i+1 >= len(s.bars) {
+		return -1, 0, false
+	}
+	return i + 1, s.timing.price(s.bars[i+1]), true
+}
+
+// barTime is the time of bar i, or the signal time when it matches no bar.
+func (s *fillSimulator) barTime(i int, sig core.Signal) time.Time {
+	if i >= 0 && i < len(s.bars) {
+		return s.bars[i].Time
+	}
+	return sig.GeneratedAt
+}
+
+// buy fills a buy signal with at most budget cash.
+func (s *fillSimulator) buy(sig core.Signal, budget float64) (fill, string) {
+	i, ref, ok := s.fillBar(sig)
+	if !ok {
+		return fill{}, "no next bar to fill on"
+	}
+	at := s.barTime(i, sig)
+	if s.model == nil {
+		if ref <= 0 {
+			return fill{bar: i, at: at, price: ref}, ""
+		}
+		return fill{bar: i, at: at, price: ref, quantity: budget / ref}, ""
+	}
+	if ref <= 0 {
+		return fill{}, "no price"
+	}
+	if s.atLimit(SideBuy, ref, i) {
+		return fill{}, "limit-up: no sellers"
+	}
+
+	price := s.slipped(SideBuy, ref, i)
+	qty := s.roundLot(budget / price)
+	fees := s.fees(SideBuy, price, qty)
+	if qty > 0 && price*qty+fees > budget {
+		if lot := s.model.Rules.LotSize; lot > 0 {
+			// Fees push the order over budget: drop whole lots until it fits.
+			for qty > 0 && price*qty+fees > budget {
+				qty -= lot
+				fees = s.fees(SideBuy, price, qty)
+			}
+		} else {
+			qty = math.Max(budget-fees, 0) / price
+			fees = s.fees(SideBuy, price, qty)
+		}
+	}
+	if qty <= 0 {
+		return fill{}, "insufficient cash for one lot"
+	}
+	return fill{bar: i, at: at, price: price, quantity: qty, fees: fees}, ""
+}
+
+// sell fills a sell signal for qty of a position whose entry filled on bar
+// entry.
+func (s *fillSimulator) sell(sig core.Signal, entry int, qty float64) (fill, string) {
+	i, ref, ok := s.fillBar(sig)
+	if !ok {
+		return fill{}, "no next bar to fill on"
+	}
+	at := s.barTime(i, sig)
+	if s.model == nil {
+		return fill{bar: i, at: at, price: ref, quantity: qty}, ""
+	}
+	if days := s.model.Rules.SettlementDays; days > 0 && i >= 0 && entry >= 0 && i-entry < days {
+		return fill{}, fmt.Sprintf("T+%d: position not yet sellable", days)
+	}
+	if s.atLimit(SideSell, ref, i) {
+		return fill{}, "limit-down: no buyers"
+	}
+	price := s.slipped(SideSell, ref, i)
+	return fill{bar: i, at: at, price: price, quantity: qty, fees: s.fees(SideSell, price, qty)}, ""
+}
+
+// atLimit reports whether price on bar i sits at the daily limit that blocks
+// an order on side.
+func (s *fillSimulator) atLimit(side Side, price float64, i int) bool {
+	pct := s.model.Rules.PriceLimitPct
+	if pct <= 0 || i <= 0 || i >= len(s.bars) {
+		return false
+	}
+	prev := s.bars[i-1].Close
+	if prev <= 0 {
+		return false
+	}
+	move := price/prev - 1
+	limit := pct / 100 * (1 - limitTolerance)
+	if side == SideBuy {
+		return move >= limit
+	}
+	return move <= -limit
+}
+
+func (s *fillSimulator) slipped(side Side, price float64, i int) float64 {
+	if s.model.Slippage == nil {
+		return price
+	}
+	return s.model.Slippage.Apply(side, price, s.bars, i)
+}
+
+func (s *fillSimulator) fees(side Side, price, qty float64) float64 {
+	if s.model.Costs == nil || qty <= 0 {
+		return 0
+	}
+	return s.model.Costs.Fees(side, price, qty)
+}
+
+func (s *fillSimulator) roundLot(qty float64) float64 {
+	lot := s.model.Rules.LotSize
+	if lot <= 0 {
+		return qty
+	}
+	return math.Floor(qty/lot) * lot
+}
+
+// tradeReturn is a trade's return on entry notional net of all fees. With no
+// fees it is exactly (exit-entry)/entry.
+func tradeReturn(entry, exit, quantity, fees float64) float64 {
+	r := (exit - entry) / entry
+	if fees != 0 && quantity > 0 {
+		r -= fees / (entry * quantity)
+	}
+	return r
+}
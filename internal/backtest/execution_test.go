@@ -0,0 +1,177 @@
+package backtest
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+func TestModelForSymbol(t *testing.T) {
+	tests := []struct {
+		symbol string
+		market core.Market
+		lot    float64
+		limit  float64
+	}{
+		{"600519.SH", core.MarketCNA, 100, 10},
+		{"300750.SZ", core.MarketCNA, 100, 20},
+		{"688981.SH", core.MarketCNA, 100, 20},
+		{"0700.HK", core.MarketHK, 100, 0},
+		{"AAPL", core.MarketUS, 1, 0},
+		{"BTC-USD", core.MarketCrypto, 0, 0},
+	}
+	for _, tt := range tests {
+		m := ModelForSymbol(tt.symbol)
+		if m.Market != tt.market || m.Rules.LotSize != tt.lot || m.Rules.PriceLimitPct != tt.limit {
+			t.Errorf("%s: got %s lot %g limit %g, want %s lot %g limit %g",
+				tt.symbol, m.Market, m.Rules.LotSize, m.Rules.PriceLimitPct, tt.market, tt.lot, tt.limit)
+		}
+	}
+}
+
+func TestExecutionModel_Describe(t *testing.T) {
+	for symbol, want := range map[string]string{
+		"300750.SZ": "CN_A: lot 100, T+1, ±20% limit",
+		"0700.HK":   "HK: lot 100 (approximate: board lots vary by stock)",
+		"AAPL":      "US",
+	} {
+		if got := ModelForSymbol(symbol).Describe(); got != want {
+			t.Errorf("%s: Describe() = %q, want %q", symbol, got, want)
+		}
+	}
+}
+
+func TestSimulateTrades_LotsAndFees(t *testing.T) {
+	base := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	bars := closesToBars("600000.SH", base, 33, 33.5, 34)
+	signals := []core.Signal{
+		{Action: core.ActionBuy, Price: 33, GeneratedAt: bars[0].Time},
+		{Action: core.ActionSell, Price: 34, GeneratedAt: bars[2].Time},
+	}
+	model := ModelForSymbol("600000.SH")
+	sim := simulateTrades("600000.SH", signals, bars, &model, FillSameClose, 10000)
+	trades, rejections := sim.trades, sim.rejections
+	if len(rejections) != 0 || len(trades) != 1 {
+		t.Fatalf("trades=%d rejections=%v, want 1 trade", len(trades), rejections)
+	}
+	tr := trades[0]
+	// 10000 / 33.0165 ≈ 302.9 shares → 3 lots.
+	if tr.Quantity != 300 {
+		t.Errorf("Quantity = %v, want 300", tr.Quantity)
+	}
+	if tr.EntryPrice <= 33 || tr.ExitPrice >= 34 {
+		t.Errorf("slippage not applied: entry %v exit %v", tr.EntryPrice, tr.ExitPrice)
+	}
+	if tr.Fees < 10 {
+		t.Errorf("Fees = %v, want at least two 5-yuan commissions", tr.Fees)
+	}
+	gross := (tr.ExitPrice - tr.EntryPrice) * tr.Quantity
+	if math.Abs(tr.PnL-(gross-tr.Fees)) > 1e-9 {
+		t.Errorf("PnL = %v, want gross %v minus fees %v", tr.PnL, gross, tr.Fees)
+	}
+	if tr.Return >= (34.0-33.0)/33.0 {
+		t.Errorf("Return %v should be below the frictionless return", tr.Return)
+	}
+}
+
+func TestSimulateTrades_TPlusOneAndPriceLimits(t *testing.T) {
+	base := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	// Day 1 closes limit-up (+10%), day 3 closes limit-down.
+	bars := closesToBars("600000.SH", base, 10, 11, 11.2, 10.08, 10.5)
+	signals := []core.Signal{
+		{Action: core.ActionBuy, Price: 11, GeneratedAt: bars[1].Time},     // limit-up: rejected
+		{Action: core.ActionBuy, Price: 11.2, GeneratedAt: bars[2].Time},   // filled
+		{Action: core.ActionSell, Price: 11.2, GeneratedAt: bars[2].Time},  // T+1: rejected
+		{Action: core.ActionSell, Price: 10.08, GeneratedAt: bars[3].Time}, // limit-down: rejected
+		{Action: core.ActionSell, Price: 10.5, GeneratedAt: bars[4].Time},  // filled
+	}
+	model := ModelForSymbol("600000.SH")
+	sim := simulateTrades("600000.SH", signals, bars, &model, FillSameClose, 100000)
+	trades, rejections := sim.trades, sim.rejections
+
+	if len(trades) != 1 || !trades[0].IsClosed() {
+		t.Fatalf("trades = %+v, want one closed trade", trades)
+	}
+	if !trades[0].ExitSignal.GeneratedAt.Equal(bars[4].Time) {
+		t.Errorf("exit on %v, want %v", trades[0].ExitSignal.GeneratedAt, bars[4].Time)
+	}
+	wantReasons := []string{"limit-up", "T+1", "limit-down"}
+	if len(rejections) != len(wantReasons) {
+		t.Fatalf("rejections = %+v, want %d", rejections, len(wantReasons))
+	}
+	for i, want := range wantReasons {
+		if !strings.HasPrefix(rejections[i].Reason, want) || rejections[i].Symbol != "600000.SH" {
+			t.Errorf("rejection %d = %+v, want %s", i, rejections[i], want)
+		}
+	}
+}
+
+func TestSimulateTrades_InsufficientCashForLot(t *testing.T) {
+	base := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	bars := closesToBars("0700.HK", base, 400)
+	model := ModelForSymbol("0700.HK")
+	sim := simulateTrades("0700.HK",
+		[]core.Signal{{Action: core.ActionBuy, Price: 400, GeneratedAt: bars[0].Time}}, bars, &model, FillSameClose, 30000)
+	trades, rejections := sim.trades, sim.rejections
+	if len(trades) != 0 || len(rejections) != 1 {
+		t.Errorf("trades=%v rejections=%v, want the 40000 lot rejected", trades, rejections)
+	}
+}
+
+func TestBacktester_WithExecutionModels(t *testing.T) {
+	base := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	provider := &mockProvider{data: closesToBars("AAPL", base, 100, 110)}
+	strat := &thresholdStrategy{name: "thr", buyAt: 100, sellAt: 110}
+
+	plain, err := New(provider).Run(context.Background(), strat, "AAPL", base, base.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if plain.Execution != nil || plain.Trades[0].Fees != 0 {
+		t.Errorf("default backtester should be frictionless, got %+v", plain.Execution)
+	}
+
+	costed, err := New(provider, WithExecutionModels(MarketModels), WithCapital(5000)).
+		Run(context.Background(), strat, "AAPL", base, base.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if costed.Execution == nil || costed.Execution.Market != core.MarketUS {
+		t.Fatalf("Execution = %+v, want US model", costed.Execution)
+	}
+	tr := costed.Trades[0]
+	if tr.Quantity != 49 { // 5000 / 100.05 whole shares
+		t.Errorf("Quantity = %v, want 49", tr.Quantity)
+	}
+	if tr.Return >= plain.Trades[0].Return {
+		t.Errorf("costed return %v should trail frictionless %v", tr.Return, plain.Trades[0].Return)
+	}
+}
+
+func TestPortfolio_ExecutionModels(t *testing.T) {
+	base := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	provider := symbolProvider{"600000.SH": closesToBars("600000.SH", base, 10, 10.2, 10.5)}
+	strat := &thresholdStrategy{name: "thr", buyAt: 10, sellAt: 10.5, confidence: 1}
+
+	res, err := NewPortfolio(provider, PortfolioConfig{InitialCapital: 10000, Models: MarketModels}).
+		Run(context.Background(), []strategy.Strategy{strat}, []Asset{{Symbol: "600000.SH"}}, base, base.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res.Execution["600000.SH"].Market != core.MarketCNA {
+		t.Errorf("Execution = %+v, want CN_A model", res.Execution)
+	}
+	if len(res.Trades) != 1 || math.Mod(res.Trades[0].Quantity, 100) != 0 {
+		t.Fatalf("trades = %+v, want one whole-lot trade", res.Trades)
+	}
+	tr := res.Trades[0]
+	// Cash paid fees on both legs, so equity reflects PnL net of fees.
+	if math.Abs(res.FinalEquity-10000-tr.PnL) > 1e-6 {
+		t.Errorf("FinalEquity %v does not match PnL %v", res.FinalEquity, tr.PnL)
+	}
+}
@@ -0,0 +1,56 @@
+package backtest
+
+import (
+	"fmt"
+
+	"github.com/newthinker/atlas/internal/core"
+)
+
+// FillModel decides when and at what price a signal computed from bar i's
+// close is executed.
+type FillModel string
+
+const (
+	// FillSameClose fills at bar i's close, the price the signal was computed
+	// from. It is the default but carries same-bar lookahead.
+	FillSameClose FillModel = "same_close"
+	// FillNextOpen fills at bar i+1's open.
+	FillNextOpen FillModel = "next_open"
+	// FillNextClose fills at bar i+1's close.
+	FillNextClose FillModel = "next_close"
+	// FillNextVWAP fills at bar i+1's typical price (high+low+close)/3, a
+	// daily-bar approximation of the session VWAP.
+	FillNextVWAP FillModel = "next_vwap"
+)
+
+// ParseFillModel validates a fill model name; "" means FillSameClose.
+func ParseFillModel(s string) (FillModel, error) {
+	switch m := FillModel(s); m {
+	case "":
+		return FillSameClose, nil
+	case FillSameClose, FillNextOpen, FillNextClose, FillNextVWAP:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown fill model %q (want same_close, next_open, next_close or next_vwap)", s)
+}
+
+// sameBar reports whether the model fills on the signal's own bar.
+func (m FillModel) sameBar() bool {
+	return m == FillSameClose || m == ""
+}
+
+// price is the reference fill price on bar b for a next-bar model. Bars with
+// a missing open or range fall back to the close.
+func (m FillModel) price(b core.OHLCV) float64 {
+	switch m {
+	case FillNextOpen:
+		if b.Open > 0 {
+			return b.Open
+		}
+	case FillNextVWAP:
+		if b.High > 0 && b.Low > 0 {
+			return (b.High + b.Low + b.Close) / 3
+		}
+	}
+	return b.Close
+}
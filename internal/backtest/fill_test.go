@@ -0,0 +1,141 @@
+package backtest
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// fillBars has distinct open/high/low/close so every fill model picks a
+// different price.
+func fillBars(base time.Time) []core.OHLCV {
+	return []core.OHLCV{
+		{Open: 99, High: 101, Low: 98, Close: 100, Time: base},
+		{Open: 102, High: 106, Low: 101, Close: 104, Time: base.AddDate(0, 0, 1)},
+		{Open: 110, High: 114, Low: 109, Close: 112, Time: base.AddDate(0, 0, 2)},
+		{Open: 111, High: 113, Low: 108, Close: 109, Time: base.AddDate(0, 0, 3)},
+	}
+}
+
+func TestParseFillModel(t *testing.T) {
+	if m, err := ParseFillModel(""); err != nil || m != FillSameClose {
+		t.Errorf(`"" = %q, %v; want same_close`, m, err)
+	}
+	if m, err := ParseFillModel("next_open"); err != nil || m != FillNextOpen {
+		t.Errorf("next_open = %q, %v", m, err)
+	}
+	if _, err := ParseFillModel("next_tick"); err == nil {
+		t.Error("expected error for unknown fill model")
+	}
+}
+
+func TestSimulateTrades_FillModels(t *testing.T) {
+	base := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	bars := fillBars(base)
+	signals := []core.Signal{
+		{Action: core.ActionBuy, Price: 100, GeneratedAt: bars[0].Time},
+		{Action: core.ActionSell, Price: 112, GeneratedAt: bars[2].Time},
+	}
+
+	tests := []struct {
+		model       FillModel
+		entry, exit float64
+		exitTime    time.Time
+	}{
+		{FillSameClose, 100, 112, bars[2].Time},
+		{FillNextOpen, 102, 111, bars[3].Time},
+		{FillNextClose, 104, 109, bars[3].Time},
+		{FillNextVWAP, (106 + 101 + 104) / 3.0, (113 + 108 + 109) / 3.0, bars[3].Time},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.model), func(t *testing.T) {
+			sim := simulateTrades("AAPL", signals, bars, nil, tt.model, 10000)
+			trades, rejections := sim.trades, sim.rejections
+			if len(trades) != 1 || len(rejections) != 0 {
+				t.Fatalf("trades=%+v rejections=%+v", trades, rejections)
+			}
+			tr := trades[0]
+			if math.Abs(tr.EntryPrice-tt.entry) > 1e-9 || math.Abs(tr.ExitPrice-tt.exit) > 1e-9 {
+				t.Errorf("entry/exit = %v/%v, want %v/%v", tr.EntryPrice, tr.ExitPrice, tt.entry, tt.exit)
+			}
+			if !tr.ExitTime.Equal(tt.exitTime) {
+				t.Errorf("ExitTime = %v, want %v", tr.ExitTime, tt.exitTime)
+			}
+			// The signals themselves keep the close they were computed from.
+			if tr.EntrySignal.Price != 100 || tr.ExitSignal.Price != 112 {
+				t.Errorf("signal prices changed: %v/%v", tr.EntrySignal.Price, tr.ExitSignal.Price)
+			}
+		})
+	}
+}
+
+func TestSimulateTrades_NextBarOnLastBar(t *testing.T) {
+	base := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	bars := fillBars(base)
+	signals := []core.Signal{{Action: core.ActionBuy, Price: 109, GeneratedAt: bars[3].Time}}
+
+	sim := simulateTrades("AAPL", signals, bars, nil, FillNextOpen, 10000)
+	trades, rejections := sim.trades, sim.rejections
+	if len(trades) != 0 {
+		t.Errorf("trades = %+v, want none: the last bar has no next bar", trades)
+	}
+	if len(rejections) != 1 || rejections[0].Side != SideBuy {
+		t.Errorf("rejections = %+v, want one unfilled buy", rejections)
+	}
+}
+
+func TestBacktester_RecordsFillModel(t *testing.T) {
+	base := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	provider := &mockProvider{data: fillBars(base)}
+	strat := &thresholdStrategy{name: "thr", buyAt: 100, sellAt: 112}
+
+	res, err := New(provider).Run(context.Background(), strat, "AAPL", base, base.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res.FillModel != FillSameClose {
+		t.Errorf("default FillModel = %q, want same_close", res.FillModel)
+	}
+
+	res, err = New(provider, WithFillModel(FillNextOpen)).Run(context.Background(), strat, "AAPL", base, base.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res.FillModel != FillNextOpen || res.Trades[0].EntryPrice != 102 {
+		t.Errorf("FillModel = %q entry = %v, want next_open at 102", res.FillModel, res.Trades[0].EntryPrice)
+	}
+}
+
+func TestPortfolio_NextOpenFills(t *testing.T) {
+	base := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+	provider := symbolProvider{"AAPL": fillBars(base)}
+	strat := &thresholdStrategy{name: "thr", buyAt: 100, sellAt: 112, confidence: 1}
+
+	res, err := NewPortfolio(provider, PortfolioConfig{InitialCapital: 10200, Fill: FillNextOpen}).
+		Run(context.Background(), []strategy.Strategy{strat}, []Asset{{Symbol: "AAPL"}}, base, base.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res.FillModel != FillNextOpen {
+		t.Errorf("FillModel = %q", res.FillModel)
+	}
+	// Day 0 signal fills on day 1's open; the day 0 equity is still all cash.
+	if res.Equity[0].Cash != 10200 {
+		t.Errorf("day 0 cash = %v, want untouched 10200", res.Equity[0].Cash)
+	}
+	if len(res.Trades) != 1 {
+		t.Fatalf("trades = %+v, want 1", res.Trades)
+	}
+	tr := res.Trades[0]
+	if tr.EntryPrice != 102 || tr.ExitPrice != 111 || !tr.EntryTime.Equal(base.AddDate(0, 0, 1)) {
+		t.Errorf("trade = %+v, want 102 → 111 entered on day 1", tr.Trade)
+	}
+	// 100 shares: 10200 → 11100.
+	if math.Abs(res.FinalEquity-11100) > 1e-9 {
+		t.Errorf("FinalEquity = %v, want 11100", res.FinalEquity)
+	}
+}
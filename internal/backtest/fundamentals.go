@@ -0,0 +1,295 @@
+package backtest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/newthinker/atlas/internal/collector"
+	"github.com/newthinker/atlas/internal/core"
+	prismstore "github.com/newthinker/atlas/internal/storage/prism"
+	"github.com/newthinker/atlas/internal/strategy"
+	"github.com/newthinker/atlas/internal/valuation"
+)
+
+// ErrNoFundamentals is returned when a strategy declares a fundamental-data
+// requirement but the backtester has no fundamental feed.
+var ErrNoFundamentals = errors.New("strategy needs fundamentals but no fundamental feed is configured")
+
+// FundamentalProvider supplies the fundamentals that were public on each bar,
+// so valuation strategies (pe_band, pe_percentile, dividend_yield and the PB,
+// PS and dividend-yield percentiles) can be backtested without lookahead.
+type FundamentalProvider interface {
+	// FundamentalsAt returns one entry per bar, aligned with bars (ascending).
+	// A nil entry means nothing was known on that bar.
+	FundamentalsAt(symbol string, bars []core.OHLCV) ([]*core.Fundamental, error)
+}
+
+// EPSSource provides a trailing EPS history for a symbol. It is satisfied by
+// *yahoo.Yahoo and *qlibpit.Source.
+type EPSSource interface {
+	FetchEPSHistory(symbol string, start, end time.Time) ([]core.EPSPoint, error)
+}
+
+// ReportingLag is how long after its period end an EPS point without
+// a FilingDate is assumed to become public. Yahoo only reports the fiscal
+// period, and a 10-Q is due 40-45 days after quarter end.
+const ReportingLag = 45 * 24 * time.Hour
+
+// epsMinPercentilePoints is the minimum PE history (about one year of trading
+// days) before a percentile is reported, as prism's rolling percentiles use.
+const epsMinPercentilePoints = 252
+
+// EPSFundamentals rebuilds each bar's PE(TTM) and PE percentile from an EPS
+// history, the way app.buildFundamental does live but evaluated bar by bar:
+// a bar only sees EPS points effective by its date, and its percentile is
+// ranked against the PE of earlier bars only.
+type EPSFundamentals struct {
+	prices        OHLCVProvider
+	eps           EPSSource
+	lookbackYears int
+}
+
+// NewEPSFundamentals creates a feed reading EPS from eps. prices supplies the
+// lookbackYears of closes before the backtest window that the first bars'
+// percentiles are ranked against (0 = since inception).
+func NewEPSFundamentals(prices OHLCVProvider, eps EPSSource, lookbackYears int) *EPSFundamentals {
+	return &EPSFundamentals{prices: prices, eps: eps, lookbackYears: lookbackYears}
+}
+
+// FundamentalsAt implements FundamentalProvider. Errors from the EPS source,
+// including valuation.ErrInsufficientEPS, are returned as is.
+func (f *EPSFundamentals) FundamentalsAt(symbol string, bars []core.OHLCV) ([]*core.Fundamental, error) {
+	out := make([]*core.Fundamental, len(bars))
+	if len(bars) == 0 {
+		return out, nil
+	}
+	first, last := bars[0].Time, bars[len(bars)-1].Time
+	warmup := time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+	if f.lookbackYears > 0 {
+		warmup = first.AddDate(-f.lookbackYears, 0, 0)
+	}
+
+	// The percentile needs the PE history before the backtest window. A
+	// failed warmup fetch only shortens that history.
+	closes := bars
+	if f.prices != nil {
+		if history, err := f.prices.FetchHistory(symbol, warmup, last, "1d"); err == nil && len(history) > 0 {
+			closes = mergeBars(history, bars)
+		}
+	}
+
+	points, err := f.eps.FetchEPSHistory(symbol, warmup.AddDate(0, 0, -90), last)
+	if err != nil {
+		return nil, err
+	}
+	published := make([]core.EPSPoint, len(points))
+	for i, p := range points {
+		if p.FilingDate.IsZero() {
+			p.FilingDate = p.Date.Add(ReportingLag)
+		}
+		published[i] = p
+	}
+
+	pe, pct, err := valuation.PointInTimePE(closes, published, f.lookbackYears, epsMinPercentilePoints)
+	if err != nil {
+		return nil, err
+	}
+	byDay := make(map[time.Time]int, len(closes))
+	for i, c := range closes {
+		byDay[dayOf(c.Time)] = i
+	}
+	market := collector.MarketForSymbol(symbol)
+	for i, b := range bars {
+		k, ok := byDay[dayOf(b.Time)]
+		if !ok || math.IsNaN(pe[k]) {
+			continue
+		}
+		fd := &core.Fundamental{
+			Symbol: symbol, Market: market, Date: b.Time,
+			PE: pe[k], EPS: closes[k].Close / pe[k], PEPercentile: -1, Source: "reconstructed",
+		}
+		if !math.IsNaN(pct[k]) {
+			fd.PEPercentile = pct[k]
+		}
+		out[i] = fd
+	}
+	return out, nil
+}
+
+// mergeBars returns history with bars appended for any day history lacks,
+// sorted ascending. The backtest's own bars win on days both carry.
+func mergeBars(history, bars []core.OHLCV) []core.OHLCV {
+	byDay := make(map[time.Time]core.OHLCV, len(history)+len(bars))
+	for _, b := range history {
+		byDay[dayOf(b.Time)] = b
+	}
+	for _, b := range bars {
+		byDay[dayOf(b.Time)] = b
+	}
+	merged := make([]core.OHLCV, 0, len(byDay))
+	for _, b := range byDay {
+		merged = append(merged, b)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Time.Before(merged[j].Time) })
+	return merged
+}
+
+// ValuationStore is the read side of the prism valuation store; it is
+// satisfied by *prismstore.Store.
+type ValuationStore interface {
+	Series(symbol, from string) (*prismstore.SeriesData, error)
+}
+
+// PrismFundamentals serves the daily PE(TTM), PB and PS(TTM) that prism
+// refresh has stored, with the stored rolling PE percentiles and PB and PS
+// percentiles ranked over the same window. Each stored row is an end-of-day
+// value, so a bar sees the latest row dated on or before it. Prism stores no
+// dividend yield.
+type PrismFundamentals struct {
+	store         ValuationStore
+	lookbackYears int
+}
+
+// NewPrismFundamentals creates a feed over store. lookbackYears >= 10 reads
+// the 10-year percentile column, anything else the 5-year one.
+func NewPrismFundamentals(store ValuationStore, lookbackYears int) *PrismFundamentals {
+	return &PrismFundamentals{store: store, lookbackYears: lookbackYears}
+}
+
+// FundamentalsAt implements FundamentalProvider.
+func (f *PrismFundamentals) FundamentalsAt(symbol string, bars []core.OHLCV) ([]*core.Fundamental, error) {
+	out := make([]*core.Fundamental, len(bars))
+	if len(bars) == 0 {
+		return out, nil
+	}
+	series, err := f.store.Series(symbol, "")
+	if err != nil {
+		return nil, err
+	}
+	pctl, source, years := series.Pctl5Y, "prism_pctl5y", 5
+	if f.lookbackYears >= 10 {
+		pctl, source, years = series.Pctl10Y, "prism_pctl10y", 10
+	}
+	pbPctl, err := rollingByRow(series.Dates, series.PB, years)
+	if err != nil {
+		return nil, err
+	}
+	psPctl, err := rollingByRow(series.Dates, series.PSTTM, years)
+	if err != nil {
+		return nil, err
+	}
+
+	market := collector.MarketForSymbol(symbol)
+	k := -1
+	for i, b := range bars {
+		day := b.Time.Format("2006-01-02")
+		for k+1 < len(series.Dates) && series.Dates[k+1] <= day {
+			k++
+		}
+		if k < 0 {
+			continue
+		}
+		pe, pb, ps := valueAt(series.PETTM, k), valueAt(series.PB, k), valueAt(series.PSTTM, k)
+		if math.IsNaN(pe) && math.IsNaN(pb) && math.IsNaN(ps) {
+			continue
+		}
+		fd := &core.Fundamental{Symbol: symbol, Market: market, Date: b.Time, PEPercentile: -1, Source: source}
+		if !math.IsNaN(pe) {
+			fd.PE = pe
+			if !math.IsNaN(pctl[k]) {
+				fd.PEPercentile = pctl[k]
+			}
+		}
+		if !math.IsNaN(pb) {
+			fd.PB = pb
+			if !math.IsNaN(pbPctl[k]) {
+				fd.SetPercentile(core.MetricPB, pbPctl[k])
+			}
+		}
+		if !math.IsNaN(ps) {
+			fd.PS = ps
+			if !math.IsNaN(psPctl[k]) {
+				fd.SetPercentile(core.MetricPS, psPctl[k])
+			}
+		}
+		out[i] = fd
+	}
+	return out, nil
+}
+
+// valueAt reads values[k], NaN when the column is shorter than the dates.
+func valueAt(values []float64, k int) float64 {
+	if k >= len(values) {
+		return math.NaN()
+	}
+	return values[k]
+}
+
+// rollingByRow ranks each stored value within the preceding years of the
+// column, as prism ranks PE; NaN where the column has no value or too short a
+// history. A missing column yields all NaN.
+func rollingByRow(days []string, values []float64, years int) ([]float64, error) {
+	if len(values) != len(days) {
+		out := make([]float64, len(days))
+		for i := range out {
+			out[i] = math.NaN()
+		}
+		return out, nil
+	}
+	dates := make([]time.Time, len(days))
+	for i, ds := range days {
+		d, err := time.Parse("2006-01-02", ds)
+		if err != nil {
+			return nil, fmt.Errorf("bad stored date %q: %w", ds, err)
+		}
+		dates[i] = d
+	}
+	return valuation.RollingPercentile(dates, values, years, epsMinPercentilePoints), nil
+}
+
+// FundamentalChain tries each feed in turn and uses the first that succeeds,
+// mirroring the live primary/fallback valuation path.
+type FundamentalChain []FundamentalProvider
+
+// FundamentalsAt implements FundamentalProvider, returning the last error
+// when every feed fails.
+func (c FundamentalChain) FundamentalsAt(symbol string, bars []core.OHLCV) ([]*core.Fundamental, error) {
+	err := errors.New("no fundamental feed")
+	for _, feed := range c {
+		var out []*core.Fundamental
+		if out, err = feed.FundamentalsAt(symbol, bars); err == nil {
+			return out, nil
+		}
+	}
+	return nil, err
+}
+
+// needsFundamentals reports whether any of strategies declares a
+// fundamental-data requirement.
+func needsFundamentals(strategies ...strategy.Strategy) bool {
+	for _, s := range strategies {
+		if s.RequiredData().Fundamentals {
+			return true
+		}
+	}
+	return false
+}
+
+// fundamentalsFor loads the per-bar fundamentals of symbol when strategies
+// need them; nil otherwise.
+func fundamentalsFor(feed FundamentalProvider, symbol string, bars []core.OHLCV, strategies ...strategy.Strategy) ([]*core.Fundamental, error) {
+	if !needsFundamentals(strategies...) {
+		return nil, nil
+	}
+	if feed == nil {
+		return nil, ErrNoFundamentals
+	}
+	fds, err := feed.FundamentalsAt(symbol, bars)
+	if err != nil {
+		return nil, fmt.Errorf("fundamentals for %s: %w", symbol, err)
+	}
+	return fds, nil
+}
@@ -0,0 +1,285 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	prismstore "github.com/newthinker/atlas/internal/storage/prism"
+	"github.com/newthinker/atlas/internal/strategy"
+	"github.com/newthinker/atlas/internal/valuation"
+)
+
+type stubEPS struct {
+	points []core.EPSPoint
+	err    error
+}
+
+func (s stubEPS) FetchEPSHistory(symbol string, start, end time.Time) ([]core.EPSPoint, error) {
+	return s.points, s.err
+}
+
+type stubValuationStore struct{ data *prismstore.SeriesData }
+
+func (s stubValuationStore) Series(symbol, from string) (*prismstore.SeriesData, error) {
+	if s.data == nil {
+		return nil, prismstore.ErrNotFound
+	}
+	return s.data, nil
+}
+
+// staticFundamentals hands out a fixed PE per bar.
+type staticFundamentals struct{ pe []float64 }
+
+func (s staticFundamentals) FundamentalsAt(symbol string, bars []core.OHLCV) ([]*core.Fundamental, error) {
+	out := make([]*core.Fundamental, len(bars))
+	for i := range bars {
+		out[i] = &core.Fundamental{Symbol: symbol, PE: s.pe[i], PEPercentile: -1}
+	}
+	return out, nil
+}
+
+// peStrategy buys below buyPE and sells above sellPE, reading only the
+// fundamental, like pe_band.
+type peStrategy struct{ buyPE, sellPE float64 }
+
+func (s *peStrategy) Name() string                   { return "pe_test" }
+func (s *peStrategy) Description() string            { return "pe" }
+func (s *peStrategy) Init(cfg strategy.Config) error { return nil }
+func (s *peStrategy) RequiredData() strategy.DataRequirements {
+	return strategy.DataRequirements{PriceHistory: 1, Fundamentals: true}
+}
+func (s *peStrategy) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	if ctx.Fundamental == nil {
+		return nil, nil
+	}
+	switch {
+	case ctx.Fundamental.PE < s.buyPE:
+		return []core.Signal{{Action: core.ActionBuy, Confidence: 1}}, nil
+	case ctx.Fundamental.PE > s.sellPE:
+		return []core.Signal{{Action: core.ActionSell, Confidence: 1}}, nil
+	}
+	return nil, nil
+}
+
+func quarterlyEPS(n int, start time.Time, eps float64) []core.EPSPoint {
+	pts := make([]core.EPSPoint, n)
+	for i := range pts {
+		pts[i] = core.EPSPoint{Date: start.AddDate(0, 3*i, 0), EPS: eps}
+	}
+	return pts
+}
+
+func TestEPSFundamentals_ReportingLag(t *testing.T) {
+	eps := quarterlyEPS(8, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), 2)
+	eps[7].EPS = 4 // period end 2024-10-01, public 45 days later
+	public := eps[7].Date.Add(ReportingLag)
+	bars := []core.OHLCV{
+		{Time: eps[7].Date.AddDate(0, 0, 10), Close: 100},
+		{Time: public, Close: 100},
+	}
+
+	funds, err := NewEPSFundamentals(nil, stubEPS{points: eps}, 5).FundamentalsAt("AAPL", bars)
+	if err != nil {
+		t.Fatalf("FundamentalsAt: %v", err)
+	}
+	if funds[0] == nil || funds[0].PE != 50 {
+		t.Fatalf("before filing: %+v, want the old EPS (PE 50)", funds[0])
+	}
+	if funds[1] == nil || funds[1].PE != 25 || funds[1].EPS != 4 {
+		t.Errorf("after filing: %+v, want PE 25 on EPS 4", funds[1])
+	}
+	// Two bars of PE history is far short of a percentile window.
+	if funds[1].PEPercentile != -1 || funds[1].Source != "reconstructed" || funds[1].Market != core.MarketUS {
+		t.Errorf("fundamental = %+v", funds[1])
+	}
+
+	// An explicit filing date is honoured as is.
+	eps[7].FilingDate = eps[7].Date.AddDate(0, 0, 5)
+	funds, _ = NewEPSFundamentals(nil, stubEPS{points: eps}, 5).FundamentalsAt("AAPL", bars)
+	if funds[0].PE != 25 {
+		t.Errorf("filed on day 5: PE = %v, want 25", funds[0].PE)
+	}
+}
+
+func TestEPSFundamentals_PercentileUsesWarmupOnly(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	eps := quarterlyEPS(8, base.AddDate(-3, 0, 0), 1)
+	// A year of warmup at 10..(10+epsMinPercentilePoints-1), then two
+	// backtest bars at the bottom and top of that range.
+	history := make([]float64, epsMinPercentilePoints)
+	for i := range history {
+		history[i] = float64(10 + i)
+	}
+	warm := closesToBars("AAPL", base, history...)
+	bars := closesToBars("AAPL", base.AddDate(0, 0, len(history)), 5, 1000)
+	prices := &mockProvider{data: append(append([]core.OHLCV{}, warm...), bars...)}
+
+	funds, err := NewEPSFundamentals(prices, stubEPS{points: eps}, 5).FundamentalsAt("AAPL", bars)
+	if err != nil {
+		t.Fatalf("FundamentalsAt: %v", err)
+	}
+	if funds[0].PEPercentile != 0 {
+		t.Errorf("bottom bar percentile = %v, want 0", funds[0].PEPercentile)
+	}
+	// The first backtest bar joins the window; 1000 still tops it.
+	if funds[1].PEPercentile != 100 {
+		t.Errorf("top bar percentile = %v, want 100", funds[1].PEPercentile)
+	}
+}
+
+func TestEPSFundamentals_InsufficientEPS(t *testing.T) {
+	bars := closesToBars("AAPL", time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), 100)
+	_, err := NewEPSFundamentals(nil, stubEPS{points: quarterlyEPS(3, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 1)}, 5).
+		FundamentalsAt("AAPL", bars)
+	if !errors.Is(err, valuation.ErrInsufficientEPS) {
+		t.Errorf("err = %v, want ErrInsufficientEPS", err)
+	}
+}
+
+func TestPrismFundamentals(t *testing.T) {
+	store := stubValuationStore{data: &prismstore.SeriesData{
+		Dates:   []string{"2025-01-07", "2025-01-09"},
+		PETTM:   []float64{12, 15},
+		Pctl5Y:  []float64{30, math.NaN()},
+		Pctl10Y: []float64{20, 25},
+	}}
+	bars := closesToBars("600519.SH", time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), 1, 1, 1, 1)
+
+	funds, err := NewPrismFundamentals(store, 5).FundamentalsAt("600519.SH", bars)
+	if err != nil {
+		t.Fatalf("FundamentalsAt: %v", err)
+	}
+	if funds[0] != nil {
+		t.Errorf("bar before the first row = %+v, want nil", funds[0])
+	}
+	// 01-08 has no row and carries the 01-07 values forward.
+	if funds[2] == nil || funds[2].PE != 12 || funds[2].PEPercentile != 30 || funds[2].Source != "prism_pctl5y" {
+		t.Errorf("gap day = %+v", funds[2])
+	}
+	if funds[3].PE != 15 || funds[3].PEPercentile != -1 {
+		t.Errorf("NaN percentile = %+v, want PE 15 percentile -1", funds[3])
+	}
+
+	funds, _ = NewPrismFundamentals(store, 10).FundamentalsAt("600519.SH", bars)
+	if funds[3].PEPercentile != 25 || funds[3].Source != "prism_pctl10y" {
+		t.Errorf("10y = %+v", funds[3])
+	}
+
+	if _, err := NewPrismFundamentals(stubValuationStore{}, 5).FundamentalsAt("AAPL", bars); !errors.Is(err, prismstore.ErrNotFound) {
+		t.Errorf("unknown symbol err = %v", err)
+	}
+}
+
+func TestPrismFundamentals_PBAndPS(t *testing.T) {
+	// Two years of rows: PB falls to the bottom of its history on the last
+	// day, PS is missing there; PE is missing throughout (a loss-maker).
+	start := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	n := 2 * epsMinPercentilePoints
+	data := &prismstore.SeriesData{}
+	for i := range n {
+		data.Dates = append(data.Dates, start.AddDate(0, 0, i).Format("2006-01-02"))
+		data.PETTM = append(data.PETTM, math.NaN())
+		data.Pctl5Y = append(data.Pctl5Y, math.NaN())
+		data.Pctl10Y = append(data.Pctl10Y, math.NaN())
+		data.PB = append(data.PB, 2+float64(i%10)/10)
+		data.PSTTM = append(data.PSTTM, 5)
+	}
+	data.PB[n-1], data.PSTTM[n-1] = 1, math.NaN()
+	last := start.AddDate(0, 0, n-1)
+	bars := closesToBars("601398.SH", last, 1)
+
+	funds, err := NewPrismFundamentals(stubValuationStore{data: data}, 5).FundamentalsAt("601398.SH", bars)
+	if err != nil {
+		t.Fatalf("FundamentalsAt: %v", err)
+	}
+	fd := funds[0]
+	if fd == nil || fd.PE != 0 || fd.PEPercentile != -1 || fd.PB != 1 {
+		t.Fatalf("fundamental = %+v, want PB 1 and no PE", fd)
+	}
+	if pct, ok := fd.Percentile(core.MetricPB); !ok || pct != 0 {
+		t.Errorf("PB percentile = %v, %v, want 0", pct, ok)
+	}
+	if _, ok := fd.Percentile(core.MetricPS); ok || fd.PS != 0 {
+		t.Errorf("PS missing on the day: got %v, percentiles %v", fd.PS, fd.Percentiles)
+	}
+}
+
+func TestFundamentalChain(t *testing.T) {
+	bars := closesToBars("AAPL", time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), 100)
+	chain := FundamentalChain{NewPrismFundamentals(stubValuationStore{}, 5), staticFundamentals{pe: []float64{9}}}
+	funds, err := chain.FundamentalsAt("AAPL", bars)
+	if err != nil || funds[0].PE != 9 {
+		t.Errorf("fallback = %+v, %v", funds, err)
+	}
+	if _, err := (FundamentalChain{}).FundamentalsAt("AAPL", bars); err == nil {
+		t.Error("empty chain: expected error")
+	}
+}
+
+func TestBacktester_Fundamentals(t *testing.T) {
+	base := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	provider := &mockProvider{data: closesToBars("AAPL", base, 100, 100, 100, 100)}
+	strat := &peStrategy{buyPE: 10, sellPE: 20}
+
+	_, err := New(provider).Run(context.Background(), strat, "AAPL", base, base.AddDate(0, 0, 3))
+	if !errors.Is(err, ErrNoFundamentals) {
+		t.Fatalf("err = %v, want ErrNoFundamentals", err)
+	}
+
+	feed := staticFundamentals{pe: []float64{15, 8, 15, 25}}
+	res, err := New(provider, WithFundamentals(feed)).Run(context.Background(), strat, "AAPL", base, base.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(res.Trades) != 1 || !res.Trades[0].EntryTime.Equal(base.AddDate(0, 0, 1)) ||
+		!res.Trades[0].ExitTime.Equal(base.AddDate(0, 0, 3)) {
+		t.Errorf("trades = %+v, want one trade from day 1 to day 3", res.Trades)
+	}
+}
+
+func TestPortfolio_Fundamentals(t *testing.T) {
+	base := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	provider := symbolProvider{
+		"AAPL": closesToBars("AAPL", base, 100, 100, 100),
+		"MSFT": closesToBars("MSFT", base, 50, 50, 50),
+	}
+	strat := &peStrategy{buyPE: 10, sellPE: 20}
+	assets := []Asset{{Symbol: "AAPL"}, {Symbol: "MSFT"}}
+
+	_, err := NewPortfolio(provider, PortfolioConfig{}).
+		Run(context.Background(), []strategy.Strategy{strat}, assets, base, base.AddDate(0, 0, 2))
+	if !errors.Is(err, ErrNoFundamentals) {
+		t.Fatalf("err = %v, want ErrNoFundamentals", err)
+	}
+
+	// MSFT has no fundamentals and drops out like a symbol without history.
+	feed := onlySymbol{"AAPL", staticFundamentals{pe: []float64{8, 15, 25}}}
+	res, err := NewPortfolio(provider, PortfolioConfig{Fundamentals: feed}).
+		Run(context.Background(), []strategy.Strategy{strat}, assets, base, base.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(res.MissingSymbols) != 1 || res.MissingSymbols[0] != "MSFT" {
+		t.Errorf("MissingSymbols = %v, want [MSFT]", res.MissingSymbols)
+	}
+	if len(res.Trades) != 1 || res.Trades[0].Symbol != "AAPL" {
+		t.Errorf("trades = %+v, want one AAPL trade", res.Trades)
+	}
+}
+
+// onlySymbol serves fundamentals for one symbol and fails the rest.
+type onlySymbol struct {
+	symbol string
+	feed   FundamentalProvider
+}
+
+func (o onlySymbol) FundamentalsAt(symbol string, bars []core.OHLCV) ([]*core.Fundamental, error) {
+	if symbol != o.symbol {
+		return nil, errors.New("no fundamentals")
+	}
+	return o.feed.FundamentalsAt(symbol, bars)
+}
@@ -0,0 +1,174 @@
+package backtest
+
+import (
+	"math"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+)
+
+// tradingDaysPerYear annualizes daily statistics, as calculateSharpeRatio does.
+const tradingDaysPerYear = 252
+
+// BenchmarkSelector picks the benchmark symbol a backtest of symbol is
+// compared against; "" means no benchmark.
+type BenchmarkSelector func(symbol string) string
+
+// applyEquityStats fills the equity-curve statistics of stats and replaces
+// its trade-based TotalReturn, MaxDrawdown and SharpeRatio with values taken
+// from the daily equity curve: per-trade returns ignore time in the market
+// and the drawdown of positions that have not been closed yet.
+func applyEquityStats(stats *Stats, equity []EquityPoint, initial float64, trades []Trade) {
+	if len(equity) == 0 || initial <= 0 {
+		return
+	}
+	daily := dailyReturns(equity, initial)
+	final := equity[len(equity)-1].Equity
+	years := float64(len(daily)) / tradingDaysPerYear
+
+	stats.TotalReturn = (final/initial - 1) * 100
+	stats.MaxDrawdown = equityDrawdown(equity, initial) * 100
+	stats.SharpeRatio = calculateSharpeRatio(daily)
+	stats.Volatility = stdDev(daily) * math.Sqrt(tradingDaysPerYear) * 100
+	stats.SortinoRatio = sortinoRatio(daily)
+	if final > 0 && years > 0 {
+		stats.AnnualizedReturn = (math.Pow(final/initial, 1/years) - 1) * 100
+	} else if final <= 0 {
+		stats.AnnualizedReturn = -100
+	}
+	if stats.MaxDrawdown > 0 {
+		stats.CalmarRatio = stats.AnnualizedReturn / stats.MaxDrawdown
+	}
+
+	var invested int
+	var equitySum float64
+	for _, e := range equity {
+		if e.Positions > 0 {
+			invested++
+		}
+		equitySum += e.Equity
+	}
+	stats.Exposure = float64(invested) / float64(len(equity)) * 100
+
+	var traded, holdingDays float64
+	var closed int
+	for _, t := range trades {
+		traded += t.EntryPrice * t.Quantity
+		if !t.IsClosed() {
+			continue
+		}
+		traded += t.ExitPrice * t.Quantity
+		if !t.EntryTime.IsZero() && !t.ExitTime.IsZero() {
+			holdingDays += t.ExitTime.Sub(t.EntryTime).Hours() / 24
+			closed++
+		}
+	}
+	if avg := equitySum / float64(len(equity)); avg > 0 && years > 0 {
+		stats.Turnover = traded / avg / years
+	}
+	if closed > 0 {
+		stats.AvgHoldingDays = holdingDays / float64(closed)
+	}
+}
+
+// applyBenchmarkStats compares the equity curve with a benchmark's daily
+// closes, matched by calendar day. Days missing from either series are
+// skipped, so each pair of returns spans the same interval. Nothing is set
+// when fewer than two common return days exist.
+func applyBenchmarkStats(stats *Stats, symbol string, equity []EquityPoint, bench []core.OHLCV) {
+	closes := make(map[time.Time]float64, len(bench))
+	for _, b := range bench {
+		closes[dayOf(b.Time)] = b.Close
+	}
+
+	var strat, market []float64
+	var prevEquity, prevClose, firstClose float64
+	for _, e := range equity {
+		c, ok := closes[dayOf(e.Time)]
+		if !ok || c <= 0 {
+			continue
+		}
+		if firstClose == 0 {
+			firstClose = c
+		} else if prevEquity > 0 {
+			strat = append(strat, e.Equity/prevEquity-1)
+			market = append(market, c/prevClose-1)
+		}
+		prevEquity, prevClose = e.Equity, c
+	}
+	if len(market) < 2 {
+		return
+	}
+
+	stats.Benchmark = symbol
+	stats.BenchmarkReturn = (prevClose/firstClose - 1) * 100
+	meanS, meanM := mean(strat), mean(market)
+	var cov, varM float64
+	for i := range market {
+		cov += (strat[i] - meanS) * (market[i] - meanM)
+		varM += (market[i] - meanM) * (market[i] - meanM)
+	}
+	if varM > 0 {
+		stats.Beta = cov / varM
+	}
+	stats.Alpha = (meanS - stats.Beta*meanM) * tradingDaysPerYear * 100
+}
+
+// equityDrawdown is the largest peak-to-trough decline of the curve as a
+// fraction, with the initial capital as the first peak.
+func equityDrawdown(equity []EquityPoint, initial float64) float64 {
+	peak := initial
+	var maxDD float64
+	for _, e := range equity {
+		if e.Equity > peak {
+			peak = e.Equity
+		}
+		if peak > 0 {
+			maxDD = math.Max(maxDD, (peak-e.Equity)/peak)
+		}
+	}
+	return maxDD
+}
+
+// sortinoRatio annualizes the mean daily return over the downside deviation
+// (root mean square of the negative returns, target 0).
+func sortinoRatio(returns []float64) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+	var downside float64
+	for _, r := range returns {
+		if r < 0 {
+			downside += r * r
+		}
+	}
+	dd := math.Sqrt(downside / float64(len(returns)))
+	if dd == 0 {
+		return 0
+	}
+	return mean(returns) * tradingDaysPerYear / (dd * math.Sqrt(tradingDaysPerYear))
+}
+
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// stdDev is the sample standard deviation.
+func stdDev(xs []float64) float64 {
+	if len(xs) < 2 {
+		return 0
+	}
+	m := mean(xs)
+	var v float64
+	for _, x := range xs {
+		v += (x - m) * (x - m)
+	}
+	return math.Sqrt(v / float64(len(xs)-1))
+}
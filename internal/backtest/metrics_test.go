@@ -0,0 +1,147 @@
+package backtest
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+)
+
+func curve(base time.Time, positions []float64, equity ...float64) []EquityPoint {
+	points := make([]EquityPoint, len(equity))
+	for i, e := range equity {
+		points[i] = EquityPoint{Time: base.AddDate(0, 0, i), Equity: e, Positions: positions[i], Cash: e - positions[i]}
+	}
+	return points
+}
+
+func TestApplyEquityStats(t *testing.T) {
+	base := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	equity := curve(base, []float64{0, 110, 99, 0}, 100, 110, 99, 121)
+	trades := []Trade{{
+		EntryTime: base.AddDate(0, 0, 1), ExitTime: base.AddDate(0, 0, 3),
+		EntryPrice: 10, ExitPrice: 11, Quantity: 10, ExitSignal: &core.Signal{},
+	}}
+
+	var stats Stats
+	applyEquityStats(&stats, equity, 100, trades)
+
+	if math.Abs(stats.TotalReturn-21) > 1e-9 {
+		t.Errorf("TotalReturn = %v, want 21", stats.TotalReturn)
+	}
+	// Peak 110 → trough 99.
+	if math.Abs(stats.MaxDrawdown-10) > 1e-9 {
+		t.Errorf("MaxDrawdown = %v, want 10", stats.MaxDrawdown)
+	}
+	if stats.Exposure != 50 {
+		t.Errorf("Exposure = %v, want 50", stats.Exposure)
+	}
+	if stats.AvgHoldingDays != 2 {
+		t.Errorf("AvgHoldingDays = %v, want 2", stats.AvgHoldingDays)
+	}
+	// 4 days compounding to +21% annualizes to far more than 21%.
+	if stats.AnnualizedReturn <= 21 || stats.Volatility <= 0 {
+		t.Errorf("AnnualizedReturn = %v, Volatility = %v", stats.AnnualizedReturn, stats.Volatility)
+	}
+	if math.Abs(stats.CalmarRatio-stats.AnnualizedReturn/10) > 1e-9 {
+		t.Errorf("CalmarRatio = %v, want AnnualizedReturn / 10", stats.CalmarRatio)
+	}
+	if stats.SortinoRatio <= 0 {
+		t.Errorf("SortinoRatio = %v, want > 0", stats.SortinoRatio)
+	}
+	// 100 bought + 110 sold over an average equity of 107.5, per 4/252 years.
+	wantTurnover := 210 / 107.5 / (4.0 / 252)
+	if math.Abs(stats.Turnover-wantTurnover) > 1e-9 {
+		t.Errorf("Turnover = %v, want %v", stats.Turnover, wantTurnover)
+	}
+}
+
+func TestApplyEquityStats_DrawdownFromInitialCapital(t *testing.T) {
+	base := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	var stats Stats
+	applyEquityStats(&stats, curve(base, []float64{90, 95}, 90, 95), 100, nil)
+	if math.Abs(stats.MaxDrawdown-10) > 1e-9 {
+		t.Errorf("MaxDrawdown = %v, want 10 (first day below the starting capital)", stats.MaxDrawdown)
+	}
+}
+
+func TestApplyBenchmarkStats(t *testing.T) {
+	base := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	bench := closesToBars("^GSPC", base, 100, 101, 99, 102, 103)
+	// The strategy moves exactly twice the benchmark every day.
+	equity := []EquityPoint{{Time: base, Equity: 1000}}
+	for i := 1; i < len(bench); i++ {
+		r := bench[i].Close/bench[i-1].Close - 1
+		prev := equity[i-1].Equity
+		equity = append(equity, EquityPoint{Time: bench[i].Time, Equity: prev * (1 + 2*r)})
+	}
+
+	var stats Stats
+	applyBenchmarkStats(&stats, "^GSPC", equity, bench)
+	if stats.Benchmark != "^GSPC" {
+		t.Fatalf("Benchmark = %q, want ^GSPC", stats.Benchmark)
+	}
+	if math.Abs(stats.Beta-2) > 1e-9 {
+		t.Errorf("Beta = %v, want 2", stats.Beta)
+	}
+	if math.Abs(stats.Alpha) > 1e-9 {
+		t.Errorf("Alpha = %v, want 0", stats.Alpha)
+	}
+	if math.Abs(stats.BenchmarkReturn-3) > 1e-9 {
+		t.Errorf("BenchmarkReturn = %v, want 3", stats.BenchmarkReturn)
+	}
+}
+
+func TestApplyBenchmarkStats_NoOverlap(t *testing.T) {
+	base := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	var stats Stats
+	applyBenchmarkStats(&stats, "^HSI", curve(base, []float64{0, 0}, 100, 101),
+		closesToBars("^HSI", base.AddDate(1, 0, 0), 1, 2, 3))
+	if stats.Benchmark != "" || stats.Beta != 0 {
+		t.Errorf("stats = %+v, want no benchmark figures", stats)
+	}
+}
+
+func TestBacktester_EquityCurveAndBenchmark(t *testing.T) {
+	base := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	provider := symbolProvider{
+		"AAPL":  closesToBars("AAPL", base, 100, 90, 120, 110),
+		"^GSPC": closesToBars("^GSPC", base, 50, 49, 52, 51),
+	}
+	strat := &thresholdStrategy{name: "thr", buyAt: 100, sellAt: 120}
+
+	res, err := New(provider, WithCapital(1000), WithBenchmark(func(string) string { return "^GSPC" })).
+		Run(context.Background(), strat, "AAPL", base, base.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(res.Equity) != 4 || res.Capital != 1000 {
+		t.Fatalf("Equity = %+v Capital = %v", res.Equity, res.Capital)
+	}
+	// Bought 10 at 100, sold at 120 on day 2, flat on day 3.
+	want := []float64{1000, 900, 1200, 1200}
+	for i, w := range want {
+		if math.Abs(res.Equity[i].Equity-w) > 1e-9 {
+			t.Errorf("equity[%d] = %v, want %v", i, res.Equity[i].Equity, w)
+		}
+	}
+	// The open-trade drawdown on day 1 shows up even though the trade won.
+	if math.Abs(res.Stats.MaxDrawdown-10) > 1e-9 {
+		t.Errorf("MaxDrawdown = %v, want 10", res.Stats.MaxDrawdown)
+	}
+	if math.Abs(res.Stats.TotalReturn-20) > 1e-9 {
+		t.Errorf("TotalReturn = %v, want 20", res.Stats.TotalReturn)
+	}
+	if res.Stats.Benchmark != "^GSPC" || math.Abs(res.Stats.BenchmarkReturn-2) > 1e-9 {
+		t.Errorf("benchmark = %q %v, want ^GSPC 2%%", res.Stats.Benchmark, res.Stats.BenchmarkReturn)
+	}
+
+	// An unavailable benchmark is skipped, not fatal.
+	res, err = New(provider, WithBenchmark(func(string) string { return "^N225" })).
+		Run(context.Background(), strat, "AAPL", base, base.AddDate(0, 0, 3))
+	if err != nil || res.Stats.Benchmark != "" {
+		t.Errorf("missing benchmark: err=%v benchmark=%q", err, res.Stats.Benchmark)
+	}
+}
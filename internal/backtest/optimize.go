@@ -0,0 +1,601 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// MaxTrials caps how many parameter sets one search may run; a grid larger
+// than this must be narrowed or sampled with SearchRandom.
+const MaxTrials = 5000
+
+// DefaultSamples is the number of random draws when Samples is unset.
+const DefaultSamples = 50
+
+// DefaultMaxDrawdown is the drawdown ceiling (percent) of ObjectiveReturnDD
+// when MaxDrawdown is unset.
+const DefaultMaxDrawdown = 20.0
+
+// SearchMethod is how parameter sets are drawn from the declared ranges.
+type SearchMethod string
+
+const (
+	// SearchGrid runs every combination of the ranges.
+	SearchGrid SearchMethod = "grid"
+	// SearchRandom runs Samples sets drawn uniformly from the ranges.
+	SearchRandom SearchMethod = "random"
+)
+
+// ParseSearchMethod accepts "grid" and "random"; empty means grid.
+func ParseSearchMethod(s string) (SearchMethod, error) {
+	switch m := SearchMethod(strings.ToLower(strings.TrimSpace(s))); m {
+	case "":
+		return SearchGrid, nil
+	case SearchGrid, SearchRandom:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown search method %q (want grid or random)", s)
+}
+
+// Objective ranks the trials of a search.
+type Objective string
+
+const (
+	// ObjectiveSharpe maximises the annualized Sharpe ratio.
+	ObjectiveSharpe Objective = "sharpe"
+	// ObjectiveCAGR maximises the compound annual growth rate.
+	ObjectiveCAGR Objective = "cagr"
+	// ObjectiveReturnDD maximises total return among trials whose max
+	// drawdown stays within the request's MaxDrawdown.
+	ObjectiveReturnDD Objective = "return_dd"
+)
+
+// ParseObjective accepts sharpe, cagr and return_dd; empty means sharpe.
+func ParseObjective(s string) (Objective, error) {
+	switch o := Objective(strings.ToLower(strings.TrimSpace(s))); o {
+	case "":
+		return ObjectiveSharpe, nil
+	case ObjectiveSharpe, ObjectiveCAGR, ObjectiveReturnDD:
+		return o, nil
+	}
+	return "", fmt.Errorf("unknown objective %q (want sharpe, cagr or return_dd)", s)
+}
+
+// ParamRange declares the values one strategy param is searched over: the
+// explicit Values if given, otherwise Min to Max in Step increments (grid) or
+// uniformly (random). Integer params are rounded to whole numbers.
+type ParamRange struct {
+	Name    string  `json:"name"`
+	Values  []any   `json:"values,omitempty"`
+	Min     float64 `json:"min,omitempty"`
+	Max     float64 `json:"max,omitempty"`
+	Step    float64 `json:"step,omitempty"`
+	Integer bool    `json:"integer,omitempty"`
+}
+
+// validate checks the range can be enumerated.
+func (r ParamRange) validate() error {
+	if r.Name == "" {
+		return errors.New("param range without a name")
+	}
+	if len(r.Values) > 0 {
+		return nil
+	}
+	if r.Max < r.Min {
+		return fmt.Errorf("param %s: max %v is below min %v", r.Name, r.Max, r.Min)
+	}
+	if r.Step < 0 {
+		return fmt.Errorf("param %s: step must be positive", r.Name)
+	}
+	return nil
+}
+
+// grid returns the values a grid search tries. A zero Step tries Min alone.
+func (r ParamRange) grid() []any {
+	if len(r.Values) > 0 {
+		return r.Values
+	}
+	var out []any
+	seen := make(map[float64]bool)
+	for k := 0; ; k++ {
+		v := r.Min + float64(k)*r.Step
+		// Tolerate float drift on the last step.
+		if v > r.Max+1e-9*math.Max(1, math.Abs(r.Max)) {
+			break
+		}
+		v = math.Min(v, r.Max)
+		if r.Integer {
+			v = math.Round(v)
+		}
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, r.value(v))
+		}
+		if r.Step == 0 {
+			break
+		}
+	}
+	return out
+}
+
+// sample draws one value for a random search.
+func (r ParamRange) sample(rng *rand.Rand) any {
+	if len(r.Values) > 0 {
+		return r.Values[rng.Intn(len(r.Values))]
+	}
+	if r.Integer {
+		lo, hi := math.Ceil(r.Min), math.Floor(r.Max)
+		if hi < lo {
+			return r.value(math.Round(r.Min))
+		}
+		return r.value(lo + float64(rng.Intn(int(hi-lo)+1)))
+	}
+	return r.value(r.Min + rng.Float64()*(r.Max-r.Min))
+}
+
+// value types v the way a config file would: int for integer params.
+func (r ParamRange) value(v float64) any {
+	if r.Integer {
+		return int(v)
+	}
+	return v
+}
+
+// WalkForward splits the period into Folds consecutive windows. Each window
+// is searched over its first InSample fraction and the winning params are
+// then run, untouched, on the remainder.
+type WalkForward struct {
+	Folds    int     `json:"folds"`
+	InSample float64 `json:"in_sample"` // default 0.7
+}
+
+// OptimizeRequest describes a parameter search for one strategy and symbol.
+type OptimizeRequest struct {
+	Symbol string
+	Start  time.Time
+	End    time.Time
+	Params []ParamRange
+	// Base holds fixed params applied to every trial under the searched ones.
+	Base        map[string]any
+	Method      SearchMethod
+	Samples     int   // SearchRandom draws (default DefaultSamples)
+	Seed        int64 // SearchRandom seed; runs with the same seed draw the same sets
+	Objective   Objective
+	MaxDrawdown float64 // ObjectiveReturnDD ceiling, percent (default DefaultMaxDrawdown)
+	WalkForward *WalkForward
+}
+
+// Trial is one parameter set and how it scored.
+type Trial struct {
+	Params map[string]any
+	Score  float64
+	// Eligible is false when the run failed or the objective's constraint
+	// ruled it out; ineligible trials rank below every eligible one.
+	Eligible bool
+	Stats    Stats
+	Trades   int
+	Error    string `json:",omitempty"`
+}
+
+// Fold is one walk-forward window.
+type Fold struct {
+	InSampleStart  time.Time
+	InSampleEnd    time.Time
+	OutSampleStart time.Time
+	OutSampleEnd   time.Time
+	// InSample is the best trial of the in-sample search and OutOfSample its
+	// params replayed on the out-of-sample window.
+	InSample    Trial
+	OutOfSample Trial
+}
+
+// WalkForwardSummary averages the fold scores. Efficiency is the mean
+// out-of-sample score over the mean in-sample score: near 1 the params held
+// up, near 0 or negative they were fitted to noise.
+type WalkForwardSummary struct {
+	InSampleScore    float64
+	OutOfSampleScore float64
+	Efficiency       float64
+}
+
+// OptimizeResult holds a finished search, ready to be stored and compared
+// with later ones.
+type OptimizeResult struct {
+	ID          string
+	Strategy    string
+	Symbol      string
+	Start       time.Time
+	End         time.Time
+	Method      SearchMethod
+	Objective   Objective
+	MaxDrawdown float64 `json:",omitempty"`
+	Params      []ParamRange
+	// Trials covers the whole period, best first; Best is Trials[0] when it
+	// is eligible.
+	Trials      []Trial
+	Best        *Trial
+	Folds       []Fold
+	WalkForward *WalkForwardSummary `json:",omitempty"`
+	FillModel   FillModel
+	CreatedAt   time.Time
+}
+
+// Optimize searches strat's params over req.Params on req.Symbol and ranks
+// the trials by req.Objective. Each trial runs on a copy of strat made with
+// strategy.WithParams, so strat itself is left as it was. progress, if set,
+// is called after every trial with the number done and the total.
+func (b *Backtester) Optimize(ctx context.Context, strat strategy.Strategy, req OptimizeRequest, progress func(done, total int)) (*OptimizeResult, error) {
+	if req.Method == "" {
+		req.Method = SearchGrid
+	}
+	if req.Objective == "" {
+		req.Objective = ObjectiveSharpe
+	}
+	if req.Objective == ObjectiveReturnDD && req.MaxDrawdown <= 0 {
+		req.MaxDrawdown = DefaultMaxDrawdown
+	}
+	if req.Objective != ObjectiveReturnDD {
+		req.MaxDrawdown = 0
+	}
+	if !req.End.After(req.Start) {
+		return nil, errors.New("end must be after start")
+	}
+	if strategy.IsCrossSectional(strat) {
+		return nil, fmt.Errorf("%s: %w", strat.Name(), ErrCrossSectional)
+	}
+	req.Params = declaredIntegers(req.Params, strat)
+	sets, err := paramSets(req)
+	if err != nil {
+		return nil, err
+	}
+
+	folds := walkForwardFolds(req.Start, req.End, req.WalkForward)
+	// One search over the whole period, one per fold plus its out-of-sample
+	// replay.
+	total := len(sets)*(1+len(folds)) + len(folds)
+	done := 0
+	step := func() {
+		done++
+		if progress != nil {
+			progress(done, total)
+		}
+	}
+
+	// Every trial replays the same history, so fetch it once.
+	cached := b.cached(req.Start, req.End, strat.RequiredData().PriceHistory)
+
+	res := &OptimizeResult{
+		Strategy:    strat.Name(),
+		Symbol:      req.Symbol,
+		Start:       req.Start,
+		End:         req.End,
+		Method:      req.Method,
+		Objective:   req.Objective,
+		MaxDrawdown: req.MaxDrawdown,
+		Params:      req.Params,
+		FillModel:   b.fill,
+		CreatedAt:   time.Now().UTC(),
+	}
+	res.Trials, err = cached.search(ctx, strat, req, sets, req.Start, req.End, step)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Trials) > 0 && res.Trials[0].Eligible {
+		best := res.Trials[0]
+		res.Best = &best
+	}
+
+	var inSum, outSum float64
+	for _, f := range folds {
+		trials, err := cached.search(ctx, strat, req, sets, f.InSampleStart, f.InSampleEnd, step)
+		if err != nil {
+			return nil, err
+		}
+		f.InSample = trials[0]
+		f.OutOfSample = cached.trial(ctx, strat, req, f.InSample.Params, f.OutSampleStart, f.OutSampleEnd)
+		step()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		inSum += f.InSample.Score
+		outSum += f.OutOfSample.Score
+		res.Folds = append(res.Folds, f)
+	}
+	if n := float64(len(folds)); n > 0 {
+		res.WalkForward = &WalkForwardSummary{InSampleScore: inSum / n, OutOfSampleScore: outSum / n}
+		if res.WalkForward.InSampleScore != 0 {
+			res.WalkForward.Efficiency = res.WalkForward.OutOfSampleScore / res.WalkForward.InSampleScore
+		}
+	}
+	return res, nil
+}
+
+// search runs every set on [start, end] and returns the trials ranked.
+func (b *Backtester) search(ctx context.Context, strat strategy.Strategy, req OptimizeRequest, sets []map[string]any, start, end time.Time, step func()) ([]Trial, error) {
+	trials := make([]Trial, 0, len(sets))
+	for _, params := range sets {
+		trials = append(trials, b.trial(ctx, strat, req, params, start, end))
+		step()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	rankTrials(trials)
+	return trials, nil
+}
+
+// trial runs one parameter set. Failures are recorded on the trial rather
+// than ending the search: a set the strategy rejects is just a bad set.
+func (b *Backtester) trial(ctx context.Context, strat strategy.Strategy, req OptimizeRequest, params map[string]any, start, end time.Time) Trial {
+	t := Trial{Params: params}
+	s, err := strategy.WithParams(strat, mergeParams(req.Base, params))
+	if err == nil {
+		var res *Result
+		if res, err = b.Run(ctx, s, req.Symbol, start, end); err == nil {
+			t.Stats = res.Stats
+			t.Trades = len(res.Trades)
+			t.Score, t.Eligible = score(req.Objective, req.MaxDrawdown, res.Stats)
+		}
+	}
+	if err != nil {
+		t.Error = err.Error()
+	}
+	return t
+}
+
+// score evaluates stats under objective. Non-finite scores are ineligible and
+// zeroed so results stay JSON-encodable.
+func score(objective Objective, maxDD float64, s Stats) (float64, bool) {
+	var v float64
+	eligible := true
+	switch objective {
+	case ObjectiveCAGR:
+		v = s.AnnualizedReturn
+	case ObjectiveReturnDD:
+		v = s.TotalReturn
+		eligible = s.MaxDrawdown <= maxDD
+	default:
+		v = s.SharpeRatio
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false
+	}
+	return v, eligible
+}
+
+// rankTrials orders eligible trials before ineligible ones, each by score
+// descending. The sort is stable so ties keep their search order.
+func rankTrials(trials []Trial) {
+	sort.SliceStable(trials, func(i, j int) bool {
+		if trials[i].Eligible != trials[j].Eligible {
+			return trials[i].Eligible
+		}
+		return trials[i].Score > trials[j].Score
+	})
+}
+
+// declaredIntegers marks the ranges of params strat declares as integers, so
+// a search never tries a fraction its Init would reject.
+func declaredIntegers(ranges []ParamRange, strat strategy.Strategy) []ParamRange {
+	declared, ok := strategy.ParamsOf(strat)
+	if !ok {
+		return ranges
+	}
+	out := slices.Clone(ranges)
+	for i := range out {
+		for _, p := range declared {
+			if p.Name == out[i].Name && p.Type == strategy.ParamInt {
+				out[i].Integer = true
+			}
+		}
+	}
+	return out
+}
+
+// paramSets expands the request's ranges into the parameter sets to try.
+func paramSets(req OptimizeRequest) ([]map[string]any, error) {
+	if len(req.Params) == 0 {
+		return nil, errors.New("no params to optimize")
+	}
+	for _, r := range req.Params {
+		if err := r.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Method == SearchRandom {
+		n := req.Samples
+		if n <= 0 {
+			n = DefaultSamples
+		}
+		if n > MaxTrials {
+			return nil, fmt.Errorf("%d samples exceeds the limit of %d", n, MaxTrials)
+		}
+		rng := rand.New(rand.NewSource(req.Seed))
+		sets := make([]map[string]any, n)
+		for i := range sets {
+			sets[i] = make(map[string]any, len(req.Params))
+			for _, r := range req.Params {
+				sets[i][r.Name] = r.sample(rng)
+			}
+		}
+		return sets, nil
+	}
+
+	axes := make([][]any, len(req.Params))
+	size := 1
+	for i, r := range req.Params {
+		axes[i] = r.grid()
+		size *= len(axes[i])
+		if size > MaxTrials {
+			return nil, fmt.Errorf("grid exceeds %d combinations; narrow the ranges or use random search", MaxTrials)
+		}
+	}
+	sets := []map[string]any{{}}
+	for i, axis := range axes {
+		next := make([]map[string]any, 0, len(sets)*len(axis))
+		for _, set := range sets {
+			for _, v := range axis {
+				m := make(map[string]any, len(set)+1)
+				for k, x := range set {
+					m[k] = x
+				}
+				m[req.Params[i].Name] = v
+				next = append(next, m)
+			}
+		}
+		sets = next
+	}
+	return sets, nil
+}
+
+// mergeParams layers params over base.
+func mergeParams(base, params map[string]any) map[string]any {
+	out := make(map[string]any, len(base)+len(params))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range params {
+		out[k] = v
+	}
+	return out
+}
+
+// walkForwardFolds splits [start, end] into wf.Folds equal windows, each cut
+// into an in-sample and an out-of-sample part; nil when wf is unset.
+func walkForwardFolds(start, end time.Time, wf *WalkForward) []Fold {
+	if wf == nil || wf.Folds <= 0 {
+		return nil
+	}
+	in := wf.InSample
+	if in <= 0 || in >= 1 {
+		in = 0.7
+	}
+	span := end.Sub(start) / time.Duration(wf.Folds)
+	folds := make([]Fold, wf.Folds)
+	for k := range folds {
+		fs := start.Add(time.Duration(k) * span)
+		fe := fs.Add(span)
+		if k == wf.Folds-1 {
+			fe = end
+		}
+		cut := dayOf(fs.Add(time.Duration(float64(span) * in)))
+		folds[k] = Fold{
+			InSampleStart:  fs,
+			InSampleEnd:    cut,
+			OutSampleStart: cut.AddDate(0, 0, 1),
+			OutSampleEnd:   fe,
+		}
+	}
+	return folds
+}
+
+// cached returns a copy of b whose provider and fundamental feed serve
+// [start, end] from memory after the first fetch of each symbol. A window
+// inside the range also gets up to warmup bars before it, so later
+// walk-forward windows start with a primed lookback like live trading would.
+func (b *Backtester) cached(start, end time.Time, warmup int) *Backtester {
+	c := *b
+	prices := &rangeCache{provider: b.provider, start: start, end: end, warmup: warmup,
+		bars: make(map[string][]core.OHLCV), errs: make(map[string]error)}
+	c.provider = prices
+	if b.funds != nil {
+		c.funds = &fundamentalCache{feed: b.funds, prices: prices,
+			byDay: make(map[string]map[time.Time]*core.Fundamental), errs: make(map[string]error)}
+	}
+	return &c
+}
+
+// rangeCache is an OHLCVProvider over one fetch per symbol of a fixed range.
+type rangeCache struct {
+	provider   OHLCVProvider
+	start, end time.Time
+	warmup     int
+
+	mu   sync.Mutex
+	bars map[string][]core.OHLCV
+	errs map[string]error
+}
+
+// all returns the full cached range of symbol.
+func (c *rangeCache) all(symbol string) ([]core.OHLCV, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if bars, ok := c.bars[symbol]; ok {
+		return bars, c.errs[symbol]
+	}
+	bars, err := c.provider.FetchHistory(symbol, c.start, c.end, "1d")
+	c.bars[symbol], c.errs[symbol] = bars, err
+	return bars, err
+}
+
+// FetchHistory implements OHLCVProvider for windows inside the cached range.
+func (c *rangeCache) FetchHistory(symbol string, start, end time.Time, interval string) ([]core.OHLCV, error) {
+	bars, err := c.all(symbol)
+	if err != nil {
+		return nil, err
+	}
+	from := sort.Search(len(bars), func(i int) bool { return !bars[i].Time.Before(start) })
+	to := sort.Search(len(bars), func(i int) bool { return bars[i].Time.After(end) })
+	return bars[max(0, from-c.warmup):to], nil
+}
+
+// fundamentalCache computes each symbol's fundamentals once over the cached
+// range and serves any window from them by day.
+type fundamentalCache struct {
+	feed   FundamentalProvider
+	prices *rangeCache
+
+	mu    sync.Mutex
+	byDay map[string]map[time.Time]*core.Fundamental
+	errs  map[string]error
+}
+
+// FundamentalsAt implements FundamentalProvider.
+func (c *fundamentalCache) FundamentalsAt(symbol string, bars []core.OHLCV) ([]*core.Fundamental, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	days, ok := c.byDay[symbol]
+	if !ok {
+		var err error
+		days, err = c.load(symbol)
+		c.byDay[symbol], c.errs[symbol] = days, err
+	}
+	if err := c.errs[symbol]; err != nil {
+		return nil, err
+	}
+	out := make([]*core.Fundamental, len(bars))
+	for i, b := range bars {
+		out[i] = days[dayOf(b.Time)]
+	}
+	return out, nil
+}
+
+func (c *fundamentalCache) load(symbol string) (map[time.Time]*core.Fundamental, error) {
+	all, err := c.prices.all(symbol)
+	if err != nil {
+		return nil, err
+	}
+	fds, err := c.feed.FundamentalsAt(symbol, all)
+	if err != nil {
+		return nil, err
+	}
+	days := make(map[time.Time]*core.Fundamental, len(all))
+	for i, b := range all {
+		if i < len(fds) {
+			days[dayOf(b.Time)] = fds[i]
+		}
+	}
+	return days, nil
+}
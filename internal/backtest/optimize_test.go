@@ -0,0 +1,225 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+	"github.com/newthinker/atlas/internal/strategy/ma_crossover"
+)
+
+// tunableThreshold is a thresholdStrategy whose levels come from params.
+type tunableThreshold struct{ thresholdStrategy }
+
+func (s *tunableThreshold) Init(cfg strategy.Config) error {
+	if v, ok := strategy.NumParam(cfg.Params, "buy_at"); ok {
+		s.buyAt = v
+	}
+	if v, ok := strategy.NumParam(cfg.Params, "sell_at"); ok {
+		s.sellAt = v
+	}
+	if s.buyAt >= s.sellAt {
+		return fmt.Errorf("buy_at %v must be below sell_at %v", s.buyAt, s.sellAt)
+	}
+	return nil
+}
+
+// rangeProvider serves bars within the requested range and counts fetches.
+type rangeProvider struct {
+	bars  []core.OHLCV
+	calls int
+}
+
+func (p *rangeProvider) FetchHistory(symbol string, start, end time.Time, interval string) ([]core.OHLCV, error) {
+	p.calls++
+	var out []core.OHLCV
+	for _, b := range p.bars {
+		if !b.Time.Before(start) && !b.Time.After(end) {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+// waveCloses repeats 100, 90, 100, 110 n times.
+func waveCloses(n int) []float64 {
+	var out []float64
+	for i := 0; i < n; i++ {
+		out = append(out, 100, 90, 100, 110)
+	}
+	return out
+}
+
+func TestParamSets_Grid(t *testing.T) {
+	req := OptimizeRequest{Params: []ParamRange{
+		{Name: "fast_period", Min: 5, Max: 15, Step: 5, Integer: true},
+		{Name: "mode", Values: []any{"a", "b"}},
+	}}
+	sets, err := paramSets(req)
+	if err != nil {
+		t.Fatalf("paramSets: %v", err)
+	}
+	if len(sets) != 6 {
+		t.Fatalf("got %d sets, want 3x2", len(sets))
+	}
+	if sets[0]["fast_period"] != 5 || sets[0]["mode"] != "a" || sets[5]["fast_period"] != 15 {
+		t.Errorf("sets = %v", sets)
+	}
+
+	// Float steps that do not land exactly on Max still include it.
+	got := ParamRange{Name: "x", Min: 0.1, Max: 0.3, Step: 0.1}.grid()
+	if len(got) != 3 {
+		t.Errorf("float grid = %v, want 3 values", got)
+	}
+
+	req.Params = []ParamRange{{Name: "a", Min: 1, Max: 100, Step: 1}, {Name: "b", Min: 1, Max: 100, Step: 1}}
+	if _, err := paramSets(req); err == nil {
+		t.Error("10000-point grid: expected error")
+	}
+	req.Params = []ParamRange{{Name: "a", Min: 5, Max: 1, Step: 1}}
+	if _, err := paramSets(req); err == nil {
+		t.Error("max below min: expected error")
+	}
+}
+
+func TestParamSets_RandomIsSeeded(t *testing.T) {
+	req := OptimizeRequest{
+		Method:  SearchRandom,
+		Samples: 20,
+		Seed:    7,
+		Params:  []ParamRange{{Name: "n", Min: 2, Max: 4, Integer: true}, {Name: "x", Min: 0, Max: 1}},
+	}
+	a, err := paramSets(req)
+	if err != nil {
+		t.Fatalf("paramSets: %v", err)
+	}
+	b, _ := paramSets(req)
+	if len(a) != 20 {
+		t.Fatalf("got %d samples, want 20", len(a))
+	}
+	for i := range a {
+		if a[i]["n"] != b[i]["n"] || a[i]["x"] != b[i]["x"] {
+			t.Fatalf("same seed drew %v then %v", a[i], b[i])
+		}
+		n := a[i]["n"].(int)
+		x := a[i]["x"].(float64)
+		if n < 2 || n > 4 || x < 0 || x > 1 {
+			t.Errorf("sample %v out of range", a[i])
+		}
+	}
+}
+
+func TestOptimize_RanksTrials(t *testing.T) {
+	base := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	provider := &rangeProvider{bars: closesToBars("AAA", base, waveCloses(5)...)}
+	strat := &tunableThreshold{thresholdStrategy{name: "thr", buyAt: 50, sellAt: 200, confidence: 1}}
+
+	res, err := New(provider).Optimize(context.Background(), strat, OptimizeRequest{
+		Symbol: "AAA", Start: base, End: base.AddDate(0, 0, 19),
+		Params: []ParamRange{
+			{Name: "buy_at", Values: []any{80.0, 90.0, 120.0}},
+			{Name: "sell_at", Values: []any{110.0}},
+		},
+		Objective: ObjectiveReturnDD,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+	if res.Best == nil || res.Best.Params["buy_at"] != 90.0 || res.Best.Trades == 0 {
+		t.Fatalf("best = %+v, want buy_at 90", res.Best)
+	}
+	if res.MaxDrawdown != DefaultMaxDrawdown {
+		t.Errorf("MaxDrawdown = %v, want the default", res.MaxDrawdown)
+	}
+	// buy_at 120 >= sell_at is rejected by Init and ranks last.
+	last := res.Trials[len(res.Trials)-1]
+	if last.Eligible || last.Error == "" || last.Params["buy_at"] != 120.0 {
+		t.Errorf("last trial = %+v, want the rejected set", last)
+	}
+	if strat.buyAt != 50 || strat.sellAt != 200 {
+		t.Errorf("strategy mutated to %v/%v", strat.buyAt, strat.sellAt)
+	}
+	if provider.calls != 1 {
+		t.Errorf("provider fetched %d times, want once", provider.calls)
+	}
+}
+
+func TestOptimize_DrawdownConstraint(t *testing.T) {
+	if _, ok := score(ObjectiveReturnDD, 10, Stats{TotalReturn: 50, MaxDrawdown: 12}); ok {
+		t.Error("drawdown above the cap should be ineligible")
+	}
+	if v, ok := score(ObjectiveCAGR, 0, Stats{AnnualizedReturn: 8}); !ok || v != 8 {
+		t.Errorf("cagr score = %v, %v", v, ok)
+	}
+	trials := []Trial{{Score: 9}, {Score: 5, Eligible: true}, {Score: 7, Eligible: true}}
+	rankTrials(trials)
+	if trials[0].Score != 7 || trials[2].Score != 9 {
+		t.Errorf("ranked = %+v", trials)
+	}
+}
+
+func TestOptimize_WalkForward(t *testing.T) {
+	base := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	provider := &rangeProvider{bars: closesToBars("AAA", base, waveCloses(10)...)}
+	strat := &tunableThreshold{thresholdStrategy{name: "thr", buyAt: 90, sellAt: 110, confidence: 1}}
+	end := base.AddDate(0, 0, 39)
+
+	var done, total int
+	res, err := New(provider).Optimize(context.Background(), strat, OptimizeRequest{
+		Symbol: "AAA", Start: base, End: end,
+		Params:      []ParamRange{{Name: "buy_at", Min: 80, Max: 90, Step: 10}},
+		WalkForward: &WalkForward{Folds: 2, InSample: 0.5},
+	}, func(d, n int) { done, total = d, n })
+	if err != nil {
+		t.Fatalf("Optimize: %v", err)
+	}
+	if len(res.Folds) != 2 || res.WalkForward == nil {
+		t.Fatalf("folds = %+v", res.Folds)
+	}
+	// 2 sets over the whole period, 2 per fold, one replay per fold.
+	if total != 8 || done != total {
+		t.Errorf("progress = %d/%d, want 8/8", done, total)
+	}
+	for _, f := range res.Folds {
+		if !f.OutSampleStart.After(f.InSampleEnd) || f.OutSampleEnd.After(end) {
+			t.Errorf("fold windows overlap: %+v", f)
+		}
+		if f.OutOfSample.Params["buy_at"] != f.InSample.Params["buy_at"] {
+			t.Errorf("out-of-sample ran %v, in-sample picked %v", f.OutOfSample.Params, f.InSample.Params)
+		}
+	}
+	if provider.calls != 1 {
+		t.Errorf("provider fetched %d times, want once", provider.calls)
+	}
+}
+
+func TestRun_WarmupBarsOnlyPrime(t *testing.T) {
+	base := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	// The provider returns two bars before start; the dip on day 1 must not
+	// open a trade.
+	provider := &mockProvider{data: closesToBars("AAA", base, 100, 80, 100, 100)}
+	strat := &thresholdStrategy{name: "thr", buyAt: 90, sellAt: 200, confidence: 1}
+
+	res, err := New(provider).Run(context.Background(), strat, "AAA", base.AddDate(0, 0, 2), base.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(res.Trades) != 0 || len(res.Equity) != 2 {
+		t.Errorf("trades = %d, equity points = %d; want 0 and 2", len(res.Trades), len(res.Equity))
+	}
+}
+
+func TestDeclaredIntegers(t *testing.T) {
+	strat := ma_crossover.New(10, 30)
+	ranges := []ParamRange{{Name: "fast_period", Min: 5, Max: 15}, {Name: "x", Min: 0, Max: 1}}
+	got := declaredIntegers(ranges, strat)
+	if !got[0].Integer || got[1].Integer {
+		t.Errorf("declaredIntegers = %+v, want fast_period integer only", got)
+	}
+	if ranges[0].Integer {
+		t.Error("declaredIntegers modified the request's ranges")
+	}
+}
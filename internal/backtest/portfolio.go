@@ -0,0 +1,661 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/newthinker/atlas/internal/config"
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/indicator"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// DefaultInitialCapital is the starting cash of a portfolio backtest when
+// PortfolioConfig.InitialCapital is unset.
+const DefaultInitialCapital = 100000.0
+
+// SizingPolicy decides how much cash a new position receives.
+type SizingPolicy interface {
+	// Allocate returns the cash to commit to a new position, given the current
+	// portfolio equity, the cash still available and the entry signal. The
+	// caller caps the amount at the available cash.
+	Allocate(equity, cash float64, sig core.Signal) float64
+}
+
+// FixedFractionSizing commits Pct percent of current equity to every new
+// position (the same convention as broker ExecutionConfig.DefaultSizePct).
+type FixedFractionSizing struct {
+	Pct float64
+}
+
+func (s FixedFractionSizing) Allocate(equity, cash float64, sig core.Signal) float64 {
+	return equity * s.Pct / 100
+}
+
+// EqualWeightSizing splits equity into Slots equal slices, one per position.
+type EqualWeightSizing struct {
+	Slots int
+}
+
+func (s EqualWeightSizing) Allocate(equity, cash float64, sig core.Signal) float64 {
+	if s.Slots <= 0 {
+		return cash
+	}
+	return equity / float64(s.Slots)
+}
+
+// ConfidenceSizing scales a Pct-of-equity allocation by the entry signal's
+// confidence, so a 0.9-confidence buy gets more capital than a 0.6 one.
+type ConfidenceSizing struct {
+	Pct float64
+}
+
+func (s ConfidenceSizing) Allocate(equity, cash float64, sig core.Signal) float64 {
+	return equity * s.Pct / 100 * sig.Confidence
+}
+
+// NewSizingPolicy builds a sizing policy by name: "equal" (param = slots, 0
+// means one slot per asset, resolved by the caller), "fixed" (param = percent
+// of equity) or "confidence" (param = percent of equity at confidence 1.0).
+func NewSizingPolicy(kind string, param float64) (SizingPolicy, error) {
+	switch kind {
+	case "equal", "":
+		return EqualWeightSizing{Slots: int(param)}, nil
+	case "fixed":
+		if param <= 0 || param > 100 {
+			return nil, fmt.Errorf("fixed sizing percent must be in (0, 100], got %g", param)
+		}
+		return FixedFractionSizing{Pct: param}, nil
+	case "confidence":
+		if param <= 0 || param > 100 {
+			return nil, fmt.Errorf("confidence sizing percent must be in (0, 100], got %g", param)
+		}
+		return ConfidenceSizing{Pct: param}, nil
+	}
+	return nil, fmt.Errorf("unknown sizing policy %q (want equal, fixed or confidence)", kind)
+}
+
+// PortfolioConfig configures a portfolio backtest.
+type PortfolioConfig struct {
+	// InitialCapital is the starting cash; <=0 uses DefaultInitialCapital.
+	InitialCapital float64
+	// Sizing decides the cash committed per new position; nil splits equity
+	// equally across the assets (EqualWeightSizing with one slot per asset).
+	Sizing SizingPolicy
+	// MaxPositions caps the number of concurrently open positions; 0 = no cap.
+	MaxPositions int
+	// Models selects each symbol's costs, slippage and trading rules; nil
+	// fills frictionlessly at the signal price.
+	Models ModelSelector
+	// Fill decides when signals execute; "" means FillSameClose.
+	Fill FillModel
+	// Benchmark is the symbol the equity curve is compared against; "" means
+	// none.
+	Benchmark string
+	// Fundamentals feeds strategies that need fundamental data; nil fails
+	// runs that include such a strategy with ErrNoFundamentals.
+	Fundamentals FundamentalProvider
+}
+
+// Asset is one symbol in a portfolio backtest together with the strategies
+// bound to it. An empty Strategies list runs every strategy passed to Run,
+// mirroring the watchlist binding semantics in app.analyzeSymbol.
+type Asset struct {
+	Symbol     string
+	Strategies []string
+}
+
+// AssetsFromWatchlist converts config watchlist items into portfolio assets,
+// keeping each item's strategy binding.
+func AssetsFromWatchlist(items []config.WatchlistItem) []Asset {
+	assets := make([]Asset, 0, len(items))
+	for _, item := range items {
+		assets = append(assets, Asset{Symbol: item.Symbol, Strategies: item.Strategies})
+	}
+	return assets
+}
+
+// PortfolioBacktester runs one or more strategies over many symbols against a
+// single cash pool, holding concurrent positions sized by a SizingPolicy.
+type PortfolioBacktester struct {
+	provider OHLCVProvider
+	cfg      PortfolioConfig
+}
+
+// NewPortfolio creates a portfolio backtester with the given OHLCV provider
+// and configuration.
+func NewPortfolio(provider OHLCVProvider, cfg PortfolioConfig) *PortfolioBacktester {
+	if cfg.InitialCapital <= 0 {
+		cfg.InitialCapital = DefaultInitialCapital
+	}
+	return &PortfolioBacktester{provider: provider, cfg: cfg}
+}
+
+// portfolioPosition is an open position inside a portfolio run.
+type portfolioPosition struct {
+	trade    PortfolioTrade
+	quantity float64
+	entryBar int // bar index of the entry fill, for settlement rules
+}
+
+// position is the open position as strategies see it; nil when p is nil.
+func (p *portfolioPosition) position() *strategy.Position {
+	if p == nil {
+		return nil
+	}
+	return &strategy.Position{
+		Quantity:    p.quantity,
+		AverageCost: p.trade.EntryPrice,
+		EntryDate:   p.trade.EntryTime,
+	}
+}
+
+// assetRun holds the per-asset state of a portfolio run.
+type assetRun struct {
+	symbol     string
+	bars       []core.OHLCV
+	strategies []strategy.Strategy
+	next       int // index of the next unprocessed bar
+	lastClose  float64
+	sim        *fillSimulator
+	pending    []core.Signal // signals of the previous bar awaiting a next-bar fill
+	funds      []*core.Fundamental
+}
+
+// Run executes the portfolio backtest. Symbols whose history cannot be fetched
+// or is empty, or whose fundamentals a bound strategy needs but cannot get,
+// are listed in Result.MissingSymbols; the run fails only when no asset has
+// data at all.
+func (p *PortfolioBacktester) Run(ctx context.Context, strategies []strategy.Strategy, assets []Asset, start, end time.Time) (*PortfolioResult, error) {
+	if len(strategies) == 0 {
+		return nil, errors.New("no strategies to backtest")
+	}
+	if len(assets) == 0 {
+		return nil, errors.New("no assets to backtest")
+	}
+
+	byName := make(map[string]strategy.Strategy, len(strategies))
+	names := make([]string, 0, len(strategies))
+	for _, s := range strategies {
+		byName[s.Name()] = s
+		names = append(names, s.Name())
+	}
+
+	result := &PortfolioResult{
+		Strategies:     names,
+		StartDate:      start,
+		EndDate:        end,
+		InitialCapital: p.cfg.InitialCapital,
+	}
+
+	timing := p.cfg.Fill
+	if timing == "" {
+		timing = FillSameClose
+	}
+	result.FillModel = timing
+
+	var runs []*assetRun
+	for _, a := range assets {
+		bars, err := p.provider.FetchHistory(a.Symbol, start, end, "1d")
+		if err != nil || len(bars) == 0 {
+			result.MissingSymbols = append(result.MissingSymbols, a.Symbol)
+			continue
+		}
+		run := &assetRun{symbol: a.Symbol, bars: bars}
+		var model *ExecutionModel
+		if p.cfg.Models != nil {
+			model = p.cfg.Models(a.Symbol)
+		}
+		run.sim = newFillSimulator(model, timing, bars)
+		if len(a.Strategies) == 0 {
+			run.strategies = strategies
+		} else {
+			for _, name := range a.Strategies {
+				if s, ok := byName[name]; ok {
+					run.strategies = append(run.strategies, s)
+				}
+			}
+		}
+		if len(run.strategies) == 0 {
+			continue // bound only to strategies outside this run
+		}
+		run.funds, err = fundamentalsFor(p.cfg.Fundamentals, a.Symbol, bars, run.strategies...)
+		if errors.Is(err, ErrNoFundamentals) {
+			return nil, err
+		}
+		if err != nil {
+			// Treated like missing history: the asset's valuation strategies
+			// would see nothing.
+			result.MissingSymbols = append(result.MissingSymbols, a.Symbol)
+			continue
+		}
+		runs = append(runs, run)
+		result.Symbols = append(result.Symbols, a.Symbol)
+		if model != nil {
+			if result.Execution == nil {
+				result.Execution = make(map[string]ExecutionModel)
+			}
+			result.Execution[a.Symbol] = *model
+		}
+	}
+	if len(runs) == 0 {
+		return nil, errors.New("no historical data available")
+	}
+
+	sizing := p.cfg.Sizing
+	if sizing == nil {
+		sizing = EqualWeightSizing{Slots: len(runs)}
+	}
+	if eq, ok := sizing.(EqualWeightSizing); ok && eq.Slots <= 0 {
+		sizing = EqualWeightSizing{Slots: len(runs)}
+	}
+
+	cash := p.cfg.InitialCapital
+	open := make(map[string]*portfolioPosition)
+	contrib := make(map[string]*SymbolContribution, len(runs))
+	bySymbol := make(map[string]*assetRun, len(runs))
+	for _, r := range runs {
+		contrib[r.symbol] = &SymbolContribution{Symbol: r.symbol}
+		bySymbol[r.symbol] = r
+	}
+	reject := func(sig core.Signal, side Side, reason string) {
+		result.Rejections = append(result.Rejections, Rejection{Time: sig.GeneratedAt, Symbol: sig.Symbol, Side: side, Reason: reason})
+	}
+
+	for _, day := range tradingDays(runs) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		// Collect every signal generated on this day across all assets, and
+		// the orders that fill today: today's signals under a same-bar fill
+		// model, otherwise those queued on each asset's previous bar.
+		var daySignals, orders []core.Signal
+		assets, skipped := analyzeDay(runs, day, func(symbol string) *strategy.Position { return open[symbol].position() })
+		result.SkippedBars += skipped
+		for _, a := range assets {
+			daySignals = append(daySignals, a.signals...)
+			if timing.sameBar() {
+				orders = append(orders, a.signals...)
+			} else {
+				orders = append(orders, a.run.pending...)
+				a.run.pending = a.signals
+			}
+		}
+		result.Signals = append(result.Signals, daySignals...)
+
+		// Exits first so the freed cash is available to same-day entries;
+		// entries are then taken highest-confidence first.
+		for _, sig := range orders {
+			if !isSell(sig.Action) {
+				continue
+			}
+			pos, ok := open[sig.Symbol]
+			if !ok {
+				continue
+			}
+			sim := bySymbol[sig.Symbol].sim
+			f, reason := sim.sell(sig, pos.entryBar, pos.quantity)
+			if reason != "" {
+				reject(sig, SideSell, reason)
+				continue
+			}
+			cash += f.proceeds()
+			closed := closeTrade(pos, sig, f)
+			result.Trades = append(result.Trades, closed)
+			contrib[sig.Symbol].Trades++
+			contrib[sig.Symbol].RealizedPnL += closed.PnL
+			delete(open, sig.Symbol)
+		}
+
+		equity := cash + marketValue(open, runs)
+		for _, sig := range entriesByConfidence(orders) {
+			if _, held := open[sig.Symbol]; held {
+				continue
+			}
+			if p.cfg.MaxPositions > 0 && len(open) >= p.cfg.MaxPositions {
+				break
+			}
+			if sig.Price <= 0 {
+				continue
+			}
+			amount := min(sizing.Allocate(equity, cash, sig), cash)
+			if amount <= 0 {
+				continue
+			}
+			f, reason := bySymbol[sig.Symbol].sim.buy(sig, amount)
+			if reason != "" {
+				reject(sig, SideBuy, reason)
+				continue
+			}
+			cash -= f.cost()
+			open[sig.Symbol] = &portfolioPosition{
+				quantity: f.quantity,
+				entryBar: f.bar,
+				trade: PortfolioTrade{
+					Trade: Trade{
+						EntrySignal: sig,
+						EntryTime:   f.at,
+						EntryPrice:  f.price,
+						Quantity:    f.quantity,
+						Fees:        f.fees,
+					},
+					Symbol: sig.Symbol,
+				},
+			}
+		}
+
+		positions := marketValue(open, runs)
+		result.Equity = append(result.Equity, EquityPoint{
+			Time:      day,
+			Cash:      cash,
+			Positions: positions,
+			Equity:    cash + positions,
+		})
+	}
+
+	// Orders still queued after an asset's last bar have nothing to fill on.
+	for _, r := range runs {
+		for _, sig := range r.pending {
+			_, held := open[sig.Symbol]
+			if isSell(sig.Action) && held {
+				reject(sig, SideSell, "no next bar to fill on")
+			} else if isBuy(sig.Action) && !held {
+				reject(sig, SideBuy, "no next bar to fill on")
+			}
+		}
+	}
+
+	// Positions still open at the end are marked at the last close, like the
+	// single-symbol signalsToTrades.
+	for _, r := range runs {
+		pos, ok := open[r.symbol]
+		if !ok {
+			continue
+		}
+		t := pos.trade
+		t.ExitTime = r.bars[len(r.bars)-1].Time
+		t.ExitPrice = r.lastClose
+		settleTrade(&t.Trade)
+		result.Trades = append(result.Trades, t)
+		contrib[r.symbol].Trades++
+		contrib[r.symbol].UnrealizedPnL += t.PnL
+	}
+
+	result.FinalEquity = p.cfg.InitialCapital
+	if n := len(result.Equity); n > 0 {
+		result.FinalEquity = result.Equity[n-1].Equity
+	}
+	for _, r := range runs {
+		c := contrib[r.symbol]
+		c.PnL = c.RealizedPnL + c.UnrealizedPnL
+		c.Contribution = c.PnL / p.cfg.InitialCapital * 100
+		result.Contributions = append(result.Contributions, *c)
+	}
+	result.Stats = portfolioStats(result.Trades, result.Equity, p.cfg.InitialCapital)
+	if p.cfg.Benchmark != "" {
+		// As in Backtester.Run, a missing benchmark is not fatal.
+		if bars, err := p.provider.FetchHistory(p.cfg.Benchmark, start, end, "1d"); err == nil {
+			applyBenchmarkStats(&result.Stats, p.cfg.Benchmark, result.Equity, bars)
+		}
+	}
+	return result, nil
+}
+
+// indicatorRegistry computes the indicators strategies declare, as the app
+// does for live analysis.
+var indicatorRegistry = indicator.NewRegistry()
+
+// analyzeBar runs strat over the rolling window ending at bars[i] and stamps
+// the resulting signals the way Run does: priced at the bar close, attributed
+// to the strategy and timed at the bar, never the wall clock. fund is the
+// point-in-time fundamental of bars[i], nil when none is known, and pos the
+// simulated position held at the bar, nil when flat; it is marked at the bar
+// close.
+func analyzeBar(strat strategy.Strategy, symbol string, bars []core.OHLCV, i int, fund *core.Fundamental, pos *strategy.Position) ([]core.Signal, error) {
+	signals, err := strat.Analyze(barContext(strat, symbol, bars, i, fund, pos))
+	if err != nil {
+		return nil, err
+	}
+	stampSignals(signals, strat, bars[i])
+	return signals, nil
+}
+
+// barContext is the analysis context strat sees at bars[i]. The declared
+// indicators are computed over the same window the strategy sees, and the
+// declared weekly and monthly bars resampled from it; intraday timeframes
+// have no history to replay and are left out.
+func barContext(strat strategy.Strategy, symbol string, bars []core.OHLCV, i int, fund *core.Fundamental, pos *strategy.Position) strategy.AnalysisContext {
+	req := strat.RequiredData()
+	windowSize := req.PriceHistory
+	if windowSize <= 0 {
+		windowSize = 1
+	}
+	window := bars[max(0, i-windowSize+1) : i+1]
+
+	actx := strategy.AnalysisContext{
+		Symbol:        symbol,
+		OHLCV:         window,
+		Fundamental:   fund,
+		Now:           bars[i].Time,
+		PositionKnown: true, // every backtester simulates its positions
+	}
+	if pos != nil {
+		marked := pos.MarkedAt(bars[i].Close)
+		actx.Position = &marked
+	}
+	if len(req.Indicators) > 0 {
+		// Like the app, unknown specs are left out rather than failing the bar.
+		actx.Indicators, _ = indicatorRegistry.Compute(window, req.Indicators)
+	}
+	if len(req.Timeframes) > 0 {
+		actx.Bars = strategy.ResampleAll(window, req.Timeframes)
+	}
+	return actx
+}
+
+// stampSignals prices signals at bar's close, attributes them to strat and
+// times them at the bar.
+func stampSignals(signals []core.Signal, strat strategy.Strategy, bar core.OHLCV) {
+	for k := range signals {
+		signals[k].Price = bar.Close
+		signals[k].Strategy = strat.Name()
+		signals[k].GeneratedAt = bar.Time
+	}
+}
+
+// ErrCrossSectional is returned when a strategy that ranks a group of symbols
+// is backtested on a single symbol.
+var ErrCrossSectional = errors.New("strategy ranks a group of symbols and needs a portfolio or broker backtest")
+
+// assetSignals are the signals of one asset on one trading day.
+type assetSignals struct {
+	run     *assetRun
+	signals []core.Signal
+}
+
+// analyzeDay advances every asset with a bar on day and analyzes it: each
+// bound strategy on the asset's own bars, then each bound cross-sectional
+// strategy over the group of assets bound to it that trade that day. It
+// returns the signals of the assets that traded, in runs order, and the
+// number of analyses that failed. position is the position held in a symbol
+// going into the day.
+func analyzeDay(runs []*assetRun, day time.Time, position func(symbol string) *strategy.Position) (days []assetSignals, skipped int) {
+	type member struct {
+		day int // index into days
+		bar int
+	}
+	groups := make(map[string][]member)
+	var ranked []strategy.Strategy
+
+	for _, r := range runs {
+		if r.next >= len(r.bars) || !sameDay(r.bars[r.next].Time, day) {
+			continue
+		}
+		i := r.next
+		r.next++
+		r.lastClose = r.bars[i].Close
+		ds := assetSignals{run: r}
+		for _, s := range r.strategies {
+			if strategy.IsCrossSectional(s) {
+				if _, seen := groups[s.Name()]; !seen {
+					ranked = append(ranked, s)
+				}
+				groups[s.Name()] = append(groups[s.Name()], member{day: len(days), bar: i})
+				continue
+			}
+			sigs, err := analyzeBar(s, r.symbol, r.bars, i, fundamentalAt(r.funds, i), position(r.symbol))
+			if err != nil {
+				skipped++
+				continue
+			}
+			for k := range sigs {
+				sigs[k].Symbol = r.symbol // positions are keyed by the asset
+			}
+			ds.signals = append(ds.signals, sigs...)
+		}
+		days = append(days, ds)
+	}
+
+	for _, s := range ranked {
+		members := groups[s.Name()]
+		group := make([]strategy.AnalysisContext, len(members))
+		bySymbol := make(map[string]member, len(members))
+		for k, m := range members {
+			r := days[m.day].run
+			group[k] = barContext(s, r.symbol, r.bars, m.bar, fundamentalAt(r.funds, m.bar), position(r.symbol))
+			bySymbol[r.symbol] = m
+		}
+		sigs, err := s.(strategy.CrossSectional).AnalyzeGroup(group)
+		if err != nil {
+			skipped += len(members)
+			continue
+		}
+		for _, sig := range sigs {
+			m, ok := bySymbol[sig.Symbol]
+			if !ok {
+				continue // only the group's own assets can be signalled
+			}
+			one := []core.Signal{sig}
+			stampSignals(one, s, days[m.day].run.bars[m.bar])
+			days[m.day].signals = append(days[m.day].signals, one...)
+		}
+	}
+	return days, skipped
+}
+
+// fundamentalAt returns the i-th fundamental of funds, nil when the run has
+// none.
+func fundamentalAt(funds []*core.Fundamental, i int) *core.Fundamental {
+	if i < len(funds) {
+		return funds[i]
+	}
+	return nil
+}
+
+// tradingDays returns the sorted union of calendar days on which any asset has
+// a bar. Markets close on different holidays, so no single series is the clock.
+func tradingDays(runs []*assetRun) []time.Time {
+	seen := make(map[time.Time]struct{})
+	var days []time.Time
+	for _, r := range runs {
+		for _, b := range r.bars {
+			d := dayOf(b.Time)
+			if _, ok := seen[d]; ok {
+				continue
+			}
+			seen[d] = struct{}{}
+			days = append(days, d)
+		}
+	}
+	slices.SortFunc(days, func(a, b time.Time) int { return a.Compare(b) })
+	return days
+}
+
+// dayOf truncates t to its calendar day in its own location, expressed in UTC
+// so bars from different exchanges share one key per date.
+func dayOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func sameDay(t, day time.Time) bool {
+	return dayOf(t).Equal(day)
+}
+
+func isBuy(a core.Action) bool  { return a == core.ActionBuy || a == core.ActionStrongBuy }
+func isSell(a core.Action) bool { return a == core.ActionSell || a == core.ActionStrongSell }
+
+// entriesByConfidence returns the buy signals of a day ordered by descending
+// confidence, ties broken by symbol so runs are deterministic.
+func entriesByConfidence(signals []core.Signal) []core.Signal {
+	var buys []core.Signal
+	for _, s := range signals {
+		if isBuy(s.Action) {
+			buys = append(buys, s)
+		}
+	}
+	sort.SliceStable(buys, func(i, j int) bool {
+		if buys[i].Confidence != buys[j].Confidence {
+			return buys[i].Confidence > buys[j].Confidence
+		}
+		return buys[i].Symbol < buys[j].Symbol
+	})
+	return buys
+}
+
+// closeTrade completes an open position's trade with the exit fill.
+func closeTrade(pos *portfolioPosition, exit core.Signal, f fill) PortfolioTrade {
+	t := pos.trade
+	exitCopy := exit
+	t.ExitSignal = &exitCopy
+	t.ExitTime = f.at
+	t.ExitPrice = f.price
+	t.Fees += f.fees
+	settleTrade(&t.Trade)
+	return t
+}
+
+// marketValue marks every open position at its asset's latest known close.
+func marketValue(open map[string]*portfolioPosition, runs []*assetRun) float64 {
+	var v float64
+	for _, r := range runs {
+		if pos, ok := open[r.symbol]; ok {
+			v += pos.quantity * r.lastClose
+		}
+	}
+	return v
+}
+
+// portfolioStats computes trade counts from the trades but takes the return
+// and risk figures from the daily equity curve: per-trade returns of
+// concurrently held, differently sized positions cannot be summed.
+func portfolioStats(trades []PortfolioTrade, equity []EquityPoint, initial float64) Stats {
+	plain := make([]Trade, len(trades))
+	for i, t := range trades {
+		plain[i] = t.Trade
+	}
+	stats := CalculateStats(plain)
+	applyEquityStats(&stats, equity, initial, plain)
+	return stats
+}
+
+// dailyReturns converts an equity curve into simple day-over-day returns, the
+// first measured against the initial capital.
+func dailyReturns(equity []EquityPoint, initial float64) []float64 {
+	returns := make([]float64, 0, len(equity))
+	prev := initial
+	for _, e := range equity {
+		if prev > 0 {
+			returns = append(returns, e.Equity/prev-1)
+		}
+		prev = e.Equity
+	}
+	return returns
+}
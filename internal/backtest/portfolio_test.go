@@ -0,0 +1,241 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/config"
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+	"github.com/newthinker/atlas/internal/strategy/momentum_rotation"
+)
+
+// symbolProvider serves a fixed bar series per symbol.
+type symbolProvider map[string][]core.OHLCV
+
+func (p symbolProvider) FetchHistory(symbol string, start, end time.Time, interval string) ([]core.OHLCV, error) {
+	bars, ok := p[symbol]
+	if !ok {
+		return nil, errors.New("unknown symbol")
+	}
+	return bars, nil
+}
+
+// thresholdStrategy buys when the close is at or below buyAt and sells when it
+// is at or above sellAt.
+type thresholdStrategy struct {
+	name          string
+	buyAt, sellAt float64
+	confidence    float64
+}
+
+func (s *thresholdStrategy) Name() string                   { return s.name }
+func (s *thresholdStrategy) Description() string            { return "threshold" }
+func (s *thresholdStrategy) Init(cfg strategy.Config) error { return nil }
+func (s *thresholdStrategy) RequiredData() strategy.DataRequirements {
+	return strategy.DataRequirements{PriceHistory: 1}
+}
+func (s *thresholdStrategy) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	last := ctx.OHLCV[len(ctx.OHLCV)-1]
+	switch {
+	case last.Close <= s.buyAt:
+		return []core.Signal{{Symbol: ctx.Symbol, Action: core.ActionBuy, Confidence: s.confidence}}, nil
+	case last.Close >= s.sellAt:
+		return []core.Signal{{Symbol: ctx.Symbol, Action: core.ActionSell, Confidence: s.confidence}}, nil
+	}
+	return nil, nil
+}
+
+func closesToBars(symbol string, base time.Time, closes ...float64) []core.OHLCV {
+	bars := make([]core.OHLCV, len(closes))
+	for i, c := range closes {
+		bars[i] = core.OHLCV{Symbol: symbol, Interval: "1d", Open: c, High: c, Low: c, Close: c, Time: base.AddDate(0, 0, i)}
+	}
+	return bars
+}
+
+func TestPortfolio_SharedCapitalAndContribution(t *testing.T) {
+	base := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	provider := symbolProvider{
+		"AAA": closesToBars("AAA", base, 100, 105, 110, 120),
+		"BBB": closesToBars("BBB", base, 50, 45, 40, 40),
+	}
+	strat := &thresholdStrategy{name: "thr", buyAt: 100, sellAt: 120, confidence: 0.8}
+	bbb := &thresholdStrategy{name: "thr_b", buyAt: 50, sellAt: 1000, confidence: 0.7}
+
+	bt := NewPortfolio(provider, PortfolioConfig{InitialCapital: 10000})
+	res, err := bt.Run(context.Background(), []strategy.Strategy{strat, bbb},
+		[]Asset{{Symbol: "AAA", Strategies: []string{"thr"}}, {Symbol: "BBB", Strategies: []string{"thr_b"}}},
+		base, base.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if len(res.Equity) != 4 {
+		t.Fatalf("equity points = %d, want 4", len(res.Equity))
+	}
+	// Equal weight across two assets: 5000 each. AAA 50 shares 100→120 = +1000;
+	// BBB 100 shares 50→40 (still open) = -1000.
+	if len(res.Trades) != 2 {
+		t.Fatalf("trades = %d, want 2", len(res.Trades))
+	}
+	byStock := map[string]SymbolContribution{}
+	for _, c := range res.Contributions {
+		byStock[c.Symbol] = c
+	}
+	if got := byStock["AAA"].RealizedPnL; math.Abs(got-1000) > 1e-9 {
+		t.Errorf("AAA realized = %v, want 1000", got)
+	}
+	if got := byStock["BBB"].UnrealizedPnL; math.Abs(got+1000) > 1e-9 {
+		t.Errorf("BBB unrealized = %v, want -1000", got)
+	}
+	total := byStock["AAA"].PnL + byStock["BBB"].PnL
+	if math.Abs(res.FinalEquity-10000-total) > 1e-9 {
+		t.Errorf("contributions %v do not explain equity change %v", total, res.FinalEquity-10000)
+	}
+	if math.Abs(res.Stats.TotalReturn) > 1e-9 {
+		t.Errorf("TotalReturn = %v, want 0 (gains and losses cancel)", res.Stats.TotalReturn)
+	}
+	if res.Stats.MaxDrawdown <= 0 {
+		t.Errorf("MaxDrawdown = %v, want > 0 from BBB's slide", res.Stats.MaxDrawdown)
+	}
+}
+
+func TestPortfolio_MaxPositions(t *testing.T) {
+	base := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	provider := symbolProvider{
+		"AAA": closesToBars("AAA", base, 10, 11),
+		"BBB": closesToBars("BBB", base, 10, 11),
+	}
+	strat := &thresholdStrategy{name: "thr", buyAt: 10, sellAt: 1000, confidence: 0.6}
+
+	bt := NewPortfolio(provider, PortfolioConfig{InitialCapital: 1000, MaxPositions: 1, Sizing: FixedFractionSizing{Pct: 50}})
+	res, err := bt.Run(context.Background(), []strategy.Strategy{strat},
+		[]Asset{{Symbol: "BBB"}, {Symbol: "AAA"}}, base, base.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(res.Trades) != 1 {
+		t.Fatalf("trades = %d, want 1 (MaxPositions=1)", len(res.Trades))
+	}
+	// Equal confidence: ties go to the alphabetically first symbol.
+	if res.Trades[0].Symbol != "AAA" {
+		t.Errorf("entered %s, want AAA", res.Trades[0].Symbol)
+	}
+	if got := res.Equity[0].Cash; math.Abs(got-500) > 1e-9 {
+		t.Errorf("cash after 50%% entry = %v, want 500", got)
+	}
+}
+
+func TestPortfolio_MissingSymbols(t *testing.T) {
+	base := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	provider := symbolProvider{"AAA": closesToBars("AAA", base, 10)}
+	strat := &thresholdStrategy{name: "thr", buyAt: 0, sellAt: 1000}
+
+	res, err := NewPortfolio(provider, PortfolioConfig{}).Run(context.Background(),
+		[]strategy.Strategy{strat}, []Asset{{Symbol: "AAA"}, {Symbol: "ZZZ"}}, base, base)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(res.MissingSymbols) != 1 || res.MissingSymbols[0] != "ZZZ" {
+		t.Errorf("MissingSymbols = %v, want [ZZZ]", res.MissingSymbols)
+	}
+	if res.InitialCapital != DefaultInitialCapital {
+		t.Errorf("InitialCapital = %v, want default", res.InitialCapital)
+	}
+
+	_, err = NewPortfolio(provider, PortfolioConfig{}).Run(context.Background(),
+		[]strategy.Strategy{strat}, []Asset{{Symbol: "ZZZ"}}, base, base)
+	if err == nil {
+		t.Error("expected error when no asset has data")
+	}
+}
+
+func TestNewSizingPolicy(t *testing.T) {
+	if _, err := NewSizingPolicy("fixed", 0); err == nil {
+		t.Error("fixed 0% should be rejected")
+	}
+	if _, err := NewSizingPolicy("kelly", 10); err == nil {
+		t.Error("unknown policy should be rejected")
+	}
+	p, err := NewSizingPolicy("confidence", 20)
+	if err != nil {
+		t.Fatalf("confidence: %v", err)
+	}
+	if got := p.Allocate(1000, 1000, core.Signal{Confidence: 0.5}); got != 100 {
+		t.Errorf("confidence allocation = %v, want 100", got)
+	}
+}
+
+func TestAssetsFromWatchlist(t *testing.T) {
+	assets := AssetsFromWatchlist([]config.WatchlistItem{
+		{Symbol: "AAPL", Strategies: []string{"ma_crossover"}},
+		{Symbol: "600519.SH"},
+	})
+	if len(assets) != 2 || assets[0].Strategies[0] != "ma_crossover" || assets[1].Strategies != nil {
+		t.Errorf("unexpected assets: %+v", assets)
+	}
+}
+
+func TestPortfolio_SuppliesSimulatedPosition(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	provider := symbolProvider{"AAPL": closesToBars("AAPL", day, 100, 101, 102, 103)}
+	strat := &holdStrategy{hold: 2}
+	res, err := NewPortfolio(provider, PortfolioConfig{InitialCapital: 10000}).Run(
+		context.Background(), []strategy.Strategy{strat}, []Asset{{Symbol: "AAPL"}}, day, day.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(res.Trades) != 2 || !res.Trades[0].IsClosed() || !res.Trades[0].ExitTime.Equal(day.AddDate(0, 0, 2)) {
+		t.Fatalf("trades = %+v", res.Trades)
+	}
+	if pos := strat.seen[1]; pos == nil || pos.Quantity != 100 || !pos.EntryDate.Equal(day) || pos.UnrealizedPL != 100 {
+		t.Errorf("position on day 1 = %+v", pos)
+	}
+}
+
+func TestPortfolio_RanksCrossSectionalGroup(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	provider := symbolProvider{
+		"AAA": closesToBars("AAA", day, 100, 110, 120, 121, 122, 123),
+		"BBB": closesToBars("BBB", day, 100, 100, 100, 112, 125, 140),
+		"CCC": closesToBars("CCC", day, 100, 99, 98, 97, 96, 95),
+		"DDD": closesToBars("DDD", day, 100, 100, 200, 400, 800, 1600),
+	}
+	rotation := momentum_rotation.New(2, 1)
+	threshold := &thresholdStrategy{name: "threshold", buyAt: 0, sellAt: math.Inf(1)}
+	assets := []Asset{{Symbol: "AAA"}, {Symbol: "BBB"}, {Symbol: "CCC"}, {Symbol: "DDD", Strategies: []string{"threshold"}}}
+	res, err := NewPortfolio(provider, PortfolioConfig{InitialCapital: 30000}).Run(
+		context.Background(), []strategy.Strategy{rotation, threshold}, assets, day, day.AddDate(0, 0, 5))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// DDD is not in the group. AAA leads on day 2; BBB overtakes it on day 3.
+	if len(res.Trades) != 2 {
+		t.Fatalf("trades = %+v", res.Trades)
+	}
+	aaa, bbb := res.Trades[0], res.Trades[1]
+	if aaa.Symbol != "AAA" || !aaa.EntryTime.Equal(day.AddDate(0, 0, 2)) || !aaa.ExitTime.Equal(day.AddDate(0, 0, 3)) || aaa.ExitPrice != 121 {
+		t.Errorf("AAA trade = %+v", aaa)
+	}
+	if bbb.Symbol != "BBB" || !bbb.EntryTime.Equal(day.AddDate(0, 0, 3)) || bbb.EntryPrice != 112 {
+		t.Errorf("BBB trade = %+v", bbb)
+	}
+	for _, sig := range res.Signals {
+		if sig.Strategy != "momentum_rotation" || sig.GeneratedAt.IsZero() || sig.Price == 0 {
+			t.Errorf("unstamped signal %+v", sig)
+		}
+	}
+
+	_, err = New(provider).Run(context.Background(), rotation, "AAA", day, day.AddDate(0, 0, 5))
+	if !errors.Is(err, ErrCrossSectional) {
+		t.Errorf("single-symbol Run = %v, want ErrCrossSectional", err)
+	}
+	_, err = New(provider).Optimize(context.Background(), rotation, OptimizeRequest{Symbol: "AAA", Start: day, End: day.AddDate(0, 0, 5)}, nil)
+	if !errors.Is(err, ErrCrossSectional) {
+		t.Errorf("Optimize = %v, want ErrCrossSectional", err)
+	}
+}
@@ -0,0 +1,389 @@
+package backtest
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"strings"
+	"time"
+)
+
+// reportHTMLData is the view model of the HTML tearsheet.
+type reportHTMLData struct {
+	Strategy, Symbol string
+	From, To         string
+	GeneratedAt      string
+	Fills, Costs     string
+	Stats            [][2]string
+	Equity           template.HTML
+	Drawdown         template.HTML
+	Price            template.HTML // empty for portfolio runs, which have no single price
+	Months           []string
+	Years            []yearRow
+	Trades           []tradeRow
+	Routing          *Routing
+}
+
+type yearRow struct {
+	Year  int
+	Cells [12]heatCell
+	Total heatCell
+}
+
+// heatCell is one monthly return; an empty Text is a month outside the run.
+type heatCell struct {
+	Text  string
+	Color template.CSS
+}
+
+type tradeRow struct {
+	Entry, Exit              string
+	EntryPrice, ExitPrice    string
+	Quantity, Fees           string
+	Return, PnL, HoldingDays string
+	Win                      bool
+}
+
+// RenderReportHTML renders r as a self-contained tearsheet: one HTML file
+// with inline SVG charts and no external assets, readable offline.
+func RenderReportHTML(r *Result) (string, error) {
+	if r == nil || len(r.Equity) == 0 {
+		return "", errors.New("backtest result has no equity curve to report")
+	}
+	first, last := r.Equity[0].Time, r.Equity[len(r.Equity)-1].Time
+	data := reportHTMLData{
+		Strategy:    r.Strategy,
+		Symbol:      r.Symbol,
+		From:        first.Format("2006-01-02"),
+		To:          last.Format("2006-01-02"),
+		GeneratedAt: time.Now().Format("2006-01-02 15:04"),
+		Fills:       string(r.FillModel),
+		Stats:       statRows(r),
+		Months:      []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		Years:       monthlyReturns(r.Equity, r.Capital),
+		Trades:      tradeRows(r.Trades),
+		Routing:     r.Routing,
+	}
+	if data.Fills == "" {
+		data.Fills = string(FillSameClose)
+	}
+	if r.Execution != nil {
+		data.Costs = r.Execution.Describe()
+	}
+
+	equity := make([]float64, len(r.Equity))
+	drawdown := make([]float64, len(r.Equity))
+	peak := 0.0
+	hasPrice := false
+	for i, p := range r.Equity {
+		equity[i] = p.Equity
+		peak = math.Max(peak, p.Equity)
+		if peak > 0 {
+			drawdown[i] = (p.Equity/peak - 1) * 100
+		}
+		hasPrice = hasPrice || p.Close != 0
+	}
+	money := func(v float64) string { return fmt.Sprintf("%.0f", v) }
+	pct := func(v float64) string { return fmt.Sprintf("%.1f%%", v) }
+	data.Equity = template.HTML(lineSVG("equity", r.Equity, equity, money, "#4f46e5", false))
+	data.Drawdown = template.HTML(lineSVG("drawdown", r.Equity, drawdown, pct, "#dc2626", true))
+	if hasPrice {
+		data.Price = template.HTML(priceSVG(r.Equity, r.Trades))
+	}
+
+	var b strings.Builder
+	if err := reportTmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// statRows lists the stats table in display order.
+func statRows(r *Result) [][2]string {
+	s := r.Stats
+	rows := [][2]string{
+		{"Total Return", fmt.Sprintf("%.2f%%", s.TotalReturn)},
+		{"Annualized Return", fmt.Sprintf("%.2f%%", s.AnnualizedReturn)},
+		{"Volatility", fmt.Sprintf("%.2f%%", s.Volatility)},
+		{"Max Drawdown", fmt.Sprintf("%.2f%%", s.MaxDrawdown)},
+		{"Sharpe Ratio", fmt.Sprintf("%.2f", s.SharpeRatio)},
+		{"Sortino Ratio", fmt.Sprintf("%.2f", s.SortinoRatio)},
+		{"Calmar Ratio", fmt.Sprintf("%.2f", s.CalmarRatio)},
+		{"Trades", fmt.Sprintf("%d (%d won, %d lost)", s.TotalTrades, s.WinningTrades, s.LosingTrades)},
+		{"Win Rate", fmt.Sprintf("%.1f%%", s.WinRate)},
+		{"Exposure", fmt.Sprintf("%.1f%%", s.Exposure)},
+		{"Turnover", fmt.Sprintf("%.2fx/yr", s.Turnover)},
+		{"Avg Holding", fmt.Sprintf("%.1f days", s.AvgHoldingDays)},
+	}
+	if s.Benchmark != "" {
+		rows = append(rows,
+			[2]string{"Benchmark", fmt.Sprintf("%s (%.2f%%)", s.Benchmark, s.BenchmarkReturn)},
+			[2]string{"Alpha", fmt.Sprintf("%.2f%%", s.Alpha)},
+			[2]string{"Beta", fmt.Sprintf("%.2f", s.Beta)},
+		)
+	}
+	if r.Capital > 0 {
+		rows = append(rows, [2]string{"Starting Capital", fmt.Sprintf("%.2f", r.Capital)})
+	}
+	if r.Execution != nil {
+		var fees float64
+		for _, t := range r.Trades {
+			fees += t.Fees
+		}
+		rows = append(rows,
+			[2]string{"Fees Paid", fmt.Sprintf("%.2f", fees)},
+			[2]string{"Rejected Orders", fmt.Sprintf("%d", len(r.Rejections))},
+		)
+	}
+	return rows
+}
+
+// Chart geometry shared by every chart so their x axes line up.
+const chartW, chartH, chartPad = 720, 180, 40
+
+// chartX maps bar index i of n to the x coordinate.
+func chartX(i, n int) float64 {
+	if n == 1 {
+		return chartPad
+	}
+	return chartPad + float64(i)*float64(chartW-2*chartPad)/float64(n-1)
+}
+
+// chartScale returns the y mapping for values between lo and hi.
+func chartScale(lo, hi float64) func(float64) float64 {
+	if hi == lo {
+		hi = lo + 1
+	}
+	return func(v float64) float64 { return chartPad/2 + (hi-v)*float64(chartH-chartPad)/(hi-lo) }
+}
+
+// openSVG writes the svg element with its y-range labels and a year tick on
+// each January (and on the first bar).
+func openSVG(b *strings.Builder, label string, points []EquityPoint, lo, hi string, y func(float64) float64, vlo, vhi float64) {
+	fmt.Fprintf(b, `<svg class="chart" viewBox="0 0 %d %d" role="img" aria-label="%s">`, chartW, chartH, label)
+	fmt.Fprintf(b, `<text x="2" y="%.1f" class="lbl">%s</text>`, y(vhi)+4, hi)
+	fmt.Fprintf(b, `<text x="2" y="%.1f" class="lbl">%s</text>`, y(vlo), lo)
+	prevYear := 0
+	for i, p := range points {
+		if p.Time.Year() == prevYear {
+			continue
+		}
+		x := chartX(i, len(points))
+		fmt.Fprintf(b, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="currentColor" stroke-width="0.5" opacity="0.3"/>`,
+			x, chartPad/2, x, chartH-chartPad/2)
+		fmt.Fprintf(b, `<text x="%.1f" y="%d" class="lbl">%s</text>`, x+2, chartH-4, p.Time.Format("2006-01"))
+		prevYear = p.Time.Year()
+	}
+}
+
+// lineSVG draws one value per bar; fill shades the area between the line and
+// zero (the drawdown chart).
+func lineSVG(label string, points []EquityPoint, values []float64, format func(float64) string, color string, fill bool) string {
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	if fill {
+		lo, hi = math.Min(lo, 0), math.Max(hi, 0)
+	}
+	y := chartScale(lo, hi)
+
+	var b strings.Builder
+	openSVG(&b, label, points, format(lo), format(hi), y, lo, hi)
+	pts := make([]string, len(values))
+	for i, v := range values {
+		pts[i] = fmt.Sprintf("%.1f,%.1f", chartX(i, len(values)), y(v))
+	}
+	if fill {
+		fmt.Fprintf(&b, `<polygon fill="%s" fill-opacity="0.25" points="%.1f,%.1f %s %.1f,%.1f"/>`,
+			color, chartX(0, len(values)), y(0), strings.Join(pts, " "), chartX(len(values)-1, len(values)), y(0))
+	}
+	fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="1.2" points="%s"/>`, color, strings.Join(pts, " "))
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// priceSVG draws the symbol's closes with a green triangle at each entry fill
+// and a red one at each exit fill.
+func priceSVG(points []EquityPoint, trades []Trade) string {
+	lo, hi := points[0].Close, points[0].Close
+	idx := make(map[int64]int, len(points)) // by Unix time: stored runs come back in UTC
+	for i, p := range points {
+		lo, hi = math.Min(lo, p.Close), math.Max(hi, p.Close)
+		idx[p.Time.Unix()] = i
+	}
+	for _, t := range trades {
+		lo, hi = math.Min(lo, math.Min(t.EntryPrice, t.ExitPrice)), math.Max(hi, math.Max(t.EntryPrice, t.ExitPrice))
+	}
+	y := chartScale(lo, hi)
+	price := func(v float64) string { return fmt.Sprintf("%.2f", v) }
+
+	var b strings.Builder
+	openSVG(&b, "price", points, price(lo), price(hi), y, lo, hi)
+	pts := make([]string, len(points))
+	for i, p := range points {
+		pts[i] = fmt.Sprintf("%.1f,%.1f", chartX(i, len(points)), y(p.Close))
+	}
+	fmt.Fprintf(&b, `<polyline fill="none" stroke="currentColor" stroke-width="1" points="%s"/>`, strings.Join(pts, " "))
+	marker := func(at time.Time, v float64, up bool, what string) {
+		i, ok := idx[at.Unix()]
+		if !ok {
+			return
+		}
+		x, yy := chartX(i, len(points)), y(v)
+		if up {
+			fmt.Fprintf(&b, `<path d="M%.1f %.1f l-4 7 h8 z" fill="#16a34a"><title>%s %s @ %.2f</title></path>`,
+				x, yy, what, at.Format("2006-01-02"), v)
+		} else {
+			fmt.Fprintf(&b, `<path d="M%.1f %.1f l-4 -7 h8 z" fill="#dc2626"><title>%s %s @ %.2f</title></path>`,
+				x, yy, what, at.Format("2006-01-02"), v)
+		}
+	}
+	for _, t := range trades {
+		marker(t.EntryTime, t.EntryPrice, true, "buy")
+		if t.IsClosed() {
+			marker(t.ExitTime, t.ExitPrice, false, "sell")
+		}
+	}
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// monthlyReturns compounds the equity curve into per-month returns, one row
+// per calendar year. The first month is measured from capital (or the first
+// point when capital is unknown).
+func monthlyReturns(points []EquityPoint, capital float64) []yearRow {
+	base := capital
+	if base <= 0 {
+		base = points[0].Equity
+	}
+	var rows []yearRow
+	yearStart := base
+	for i, p := range points {
+		if i+1 < len(points) && points[i+1].Time.Month() == p.Time.Month() && points[i+1].Time.Year() == p.Time.Year() {
+			continue // not the month's last point
+		}
+		if len(rows) == 0 || rows[len(rows)-1].Year != p.Time.Year() {
+			rows = append(rows, yearRow{Year: p.Time.Year()})
+			yearStart = base
+		}
+		row := &rows[len(rows)-1]
+		if base > 0 {
+			row.Cells[p.Time.Month()-1] = heat((p.Equity/base - 1) * 100)
+		}
+		if yearStart > 0 {
+			row.Total = heat((p.Equity/yearStart - 1) * 100)
+		}
+		base = p.Equity
+	}
+	return rows
+}
+
+// heat colours a percentage return: green for gains, red for losses, deeper
+// as it nears ±10%.
+func heat(ret float64) heatCell {
+	alpha := math.Min(math.Abs(ret)/10, 1)*0.6 + 0.05
+	color := "22,163,74"
+	if ret < 0 {
+		color = "220,38,38"
+	}
+	return heatCell{
+		Text:  fmt.Sprintf("%.1f", ret),
+		Color: template.CSS(fmt.Sprintf("background: rgba(%s,%.2f)", color, alpha)),
+	}
+}
+
+func tradeRows(trades []Trade) []tradeRow {
+	rows := make([]tradeRow, len(trades))
+	for i, t := range trades {
+		row := tradeRow{
+			Entry:       t.EntryTime.Format("2006-01-02"),
+			Exit:        t.ExitTime.Format("2006-01-02"),
+			EntryPrice:  fmt.Sprintf("%.2f", t.EntryPrice),
+			ExitPrice:   fmt.Sprintf("%.2f", t.ExitPrice),
+			Quantity:    fmt.Sprintf("%g", t.Quantity),
+			Fees:        fmt.Sprintf("%.2f", t.Fees),
+			Return:      fmt.Sprintf("%.2f%%", t.ReturnPct()),
+			PnL:         fmt.Sprintf("%.2f", t.PnL),
+			HoldingDays: fmt.Sprintf("%.0f", t.ExitTime.Sub(t.EntryTime).Hours()/24),
+			Win:         t.IsWin(),
+		}
+		if !t.IsClosed() {
+			row.Exit = "open"
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+var reportTmpl = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Backtest {{.Strategy}} on {{.Symbol}} {{.From}} ~ {{.To}}</title>
+<style>
+:root { color-scheme: light dark; }
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 24px auto; max-width: 960px; padding: 0 16px; background: #fff; color: #1f2937; }
+h1 { font-size: 1.3rem; } h2 { font-size: 1.05rem; margin-top: 2rem; }
+table { border-collapse: collapse; font-size: 0.78rem; white-space: nowrap; }
+th, td { border: 1px solid #6b728066; padding: 4px 8px; text-align: right; }
+th { background: #f3f4f6; }
+td:first-child, th:first-child { text-align: left; }
+.scroll { overflow-x: auto; }
+svg.chart { width: 100%; height: auto; display: block; }
+.lbl { font-size: 8px; fill: currentColor; }
+.meta { color: #6b7280; font-size: 0.85rem; }
+.win { color: #16a34a; } .loss { color: #dc2626; }
+footer { margin-top: 2rem; color: #6b7280; font-size: 0.8rem; border-top: 1px solid #6b728066; padding-top: 8px; }
+@media (prefers-color-scheme: dark) {
+  body { background: #111827; color: #e5e7eb; }
+  th { background: #1f2937; }
+}
+</style>
+</head>
+<body>
+<h1>Backtest: {{.Strategy}} on {{.Symbol}} · {{.From}} ~ {{.To}}</h1>
+<p class="meta">Generated {{.GeneratedAt}} · fills {{.Fills}}{{if .Costs}} · costs {{.Costs}}{{end}}</p>
+
+<h2>Statistics</h2>
+<table>
+{{range .Stats}}<tr><td>{{index . 0}}</td><td>{{index . 1}}</td></tr>
+{{end}}</table>
+
+<h2>Equity Curve</h2>
+{{.Equity}}
+
+<h2>Drawdown</h2>
+{{.Drawdown}}
+
+{{if .Price}}<h2>Price and Trades</h2>
+<p class="meta">Green ▲ entry fills · red ▼ exit fills (hover for date and price)</p>
+{{.Price}}
+{{end}}
+
+<h2>Monthly Returns (%)</h2>
+<div class="scroll"><table>
+<tr><th>Year</th>{{range .Months}}<th>{{.}}</th>{{end}}<th>Year</th></tr>
+{{range .Years}}<tr><td>{{.Year}}</td>{{range .Cells}}<td{{if .Text}} style="{{.Color}}"{{end}}>{{.Text}}</td>{{end}}<td style="{{.Total.Color}}">{{.Total.Text}}</td></tr>
+{{end}}</table></div>
+
+<h2>Trades</h2>
+{{if .Trades}}<div class="scroll"><table>
+<tr><th>Entry</th><th>Exit</th><th>Entry Price</th><th>Exit Price</th><th>Quantity</th><th>Fees</th><th>Days</th><th>Return</th><th>P&amp;L</th></tr>
+{{range .Trades}}<tr><td>{{.Entry}}</td><td>{{.Exit}}</td><td>{{.EntryPrice}}</td><td>{{.ExitPrice}}</td><td>{{.Quantity}}</td><td>{{.Fees}}</td><td>{{.HoldingDays}}</td><td class="{{if .Win}}win{{else}}loss{{end}}">{{.Return}}</td><td>{{.PnL}}</td></tr>
+{{end}}</table></div>
+{{else}}<p class="meta">No trades.</p>{{end}}
+
+{{with .Routing}}<h2>Router Replay</h2>
+<p class="meta">Trades above act only on notified signals. {{.Notified}} of {{len .Signals}} signals notified{{range $reason, $n := .SuppressedBy}} · {{$n}} suppressed by {{$reason}}{{end}}. Trading every signal returns {{printf "%.2f" .Unfiltered.TotalReturn}}% over {{.Unfiltered.TotalTrades}} trades.</p>
+<div class="scroll"><table>
+<tr><th>Date</th><th>Action</th><th>Confidence</th><th style="text-align:left">Decision</th></tr>
+{{range .Signals}}<tr><td>{{.Signal.GeneratedAt.Format "2006-01-02"}}</td><td>{{.Signal.Action}}</td><td>{{printf "%.2f" .Signal.Confidence}}</td><td style="text-align:left">{{if .Notified}}notified{{else}}suppressed: {{.Reason}}{{end}}</td></tr>
+{{end}}</table></div>
+{{end}}
+<footer>Simulated results on historical data; past performance does not predict future returns.</footer>
+</body>
+</html>
+`))
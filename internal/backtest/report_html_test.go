@@ -0,0 +1,120 @@
+package backtest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+)
+
+// reportFixture spans Dec 2024 to Feb 2025 with one closed and one open trade.
+func reportFixture() *Result {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	exit := core.Signal{Action: core.ActionSell}
+	return &Result{
+		Strategy: "ma_crossover",
+		Symbol:   "AAPL",
+		Capital:  1000,
+		Stats:    Stats{TotalTrades: 2, TotalReturn: 21, Benchmark: "SPY"},
+		Equity: []EquityPoint{
+			{Time: day(2024, 12, 30), Equity: 1000, Close: 100},
+			{Time: day(2024, 12, 31), Equity: 1100, Close: 110},
+			{Time: day(2025, 1, 2), Equity: 1100, Close: 105},
+			{Time: day(2025, 2, 3), Equity: 1210, Close: 121},
+		},
+		Trades: []Trade{
+			{EntryTime: day(2024, 12, 30), ExitTime: day(2024, 12, 31), EntryPrice: 100, ExitPrice: 110, ExitSignal: &exit, Return: 0.10},
+			{EntryTime: day(2025, 1, 2), ExitTime: day(2025, 2, 3), EntryPrice: 105, ExitPrice: 121, Return: 16.0 / 105},
+		},
+	}
+}
+
+func TestRenderReportHTML(t *testing.T) {
+	html, err := RenderReportHTML(reportFixture())
+	if err != nil {
+		t.Fatalf("RenderReportHTML: %v", err)
+	}
+	for _, want := range []string{
+		"Backtest: ma_crossover on AAPL · 2024-12-30 ~ 2025-02-03",
+		`aria-label="equity"`, `aria-label="drawdown"`, `aria-label="price"`,
+		"SPY (0.00%)",
+		"<td>2024</td>", "<td>2025</td>",
+		"<td>open</td>",
+		`<td class="win">10.00%</td>`, `<td class="win">15.24%</td>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+	// Two entry markers, one exit marker: the second trade is still open.
+	if n := strings.Count(html, `fill="#16a34a"><title>buy`); n != 2 {
+		t.Errorf("entry markers = %d, want 2", n)
+	}
+	if n := strings.Count(html, `fill="#dc2626"><title>sell`); n != 1 {
+		t.Errorf("exit markers = %d, want 1", n)
+	}
+	// Self-contained: nothing is loaded from the network.
+	if strings.Contains(html, "http://") || strings.Contains(html, "https://") || strings.Contains(html, "<script") {
+		t.Error("report must not reference external resources")
+	}
+}
+
+func TestRenderReportHTML_Routing(t *testing.T) {
+	r := reportFixture()
+	r.Routing = &Routing{
+		Signals: []RoutedSignal{
+			{Signal: core.Signal{Action: core.ActionBuy, Confidence: 0.8}, Notified: true},
+			{Signal: core.Signal{Action: core.ActionSell, Confidence: 0.8}, Reason: "cooldown"},
+		},
+		Notified: 1, Suppressed: 1, SuppressedBy: map[string]int{"cooldown": 1},
+	}
+	html, err := RenderReportHTML(r)
+	if err != nil {
+		t.Fatalf("RenderReportHTML: %v", err)
+	}
+	for _, want := range []string{"Router Replay", "1 of 2 signals notified", "1 suppressed by cooldown", "suppressed: cooldown"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+}
+
+func TestRenderReportHTML_NoPriceForPortfolio(t *testing.T) {
+	r := reportFixture()
+	for i := range r.Equity {
+		r.Equity[i].Close = 0
+	}
+	html, err := RenderReportHTML(r)
+	if err != nil {
+		t.Fatalf("RenderReportHTML: %v", err)
+	}
+	if strings.Contains(html, `aria-label="price"`) {
+		t.Error("portfolio report should omit the price chart")
+	}
+}
+
+func TestRenderReportHTML_Empty(t *testing.T) {
+	if _, err := RenderReportHTML(&Result{}); err == nil {
+		t.Error("expected an error for a result without an equity curve")
+	}
+}
+
+func TestMonthlyReturns(t *testing.T) {
+	rows := monthlyReturns(reportFixture().Equity, 1000)
+	if len(rows) != 2 || rows[0].Year != 2024 || rows[1].Year != 2025 {
+		t.Fatalf("rows = %+v", rows)
+	}
+	if got := rows[0].Cells[11].Text; got != "10.0" {
+		t.Errorf("Dec 2024 = %q, want 10.0", got)
+	}
+	if got := rows[1].Cells[0].Text; got != "0.0" {
+		t.Errorf("Jan 2025 = %q, want 0.0", got)
+	}
+	if got := rows[1].Cells[1].Text; got != "10.0" {
+		t.Errorf("Feb 2025 = %q, want 10.0", got)
+	}
+	if rows[1].Cells[5].Text != "" || rows[1].Total.Text != "10.0" {
+		t.Errorf("2025 row = %+v", rows[1])
+	}
+}
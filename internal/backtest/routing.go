@@ -0,0 +1,93 @@
+package backtest
+
+import (
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+)
+
+// SignalFilter decides whether the live router would have notified a signal.
+// Admit sees each signal at its bar time and returns "" to notify it or the
+// reason it is suppressed. *router.Router satisfies it.
+type SignalFilter interface {
+	Admit(sig core.Signal, now time.Time) string
+}
+
+// WithSignalFilter replays notification routing: each run takes a fresh
+// filter from newFilter, feeds it every generated signal in bar order at the
+// signal's bar time, and trades only the signals it admits. Result.Routing
+// reports the decisions next to the statistics of trading every signal.
+func WithSignalFilter(newFilter func() SignalFilter) Option {
+	return func(b *Backtester) {
+		b.filter = newFilter
+	}
+}
+
+// SignalGate adjusts or suppresses a signal before the notification filter
+// sees it, as the live app's gate does. Apply sees each signal at its bar time
+// and returns the signal as it leaves it, or the reason it is suppressed.
+// *crisisgate.Gate satisfies it.
+type SignalGate interface {
+	Apply(sig core.Signal, now time.Time) (core.Signal, string, error)
+}
+
+// WithSignalGate replays the live signal gate: every generated signal passes
+// through gate before the filter of WithSignalFilter, and only the signals
+// that pass both are traded, as the gate left them. Result.Routing records
+// the gate's suppressions next to the filter's. Unlike filters, a gate keeps
+// no state between signals, so one gate serves every run.
+func WithSignalGate(gate SignalGate) Option {
+	return func(b *Backtester) {
+		b.gate = gate
+	}
+}
+
+// RoutedSignal is one generated signal and the routing decision on it.
+type RoutedSignal struct {
+	Signal   core.Signal
+	Notified bool
+	Reason   string // why it was suppressed; "" when notified
+}
+
+// Routing reports a run replayed through the signal gate and notification
+// filters.
+type Routing struct {
+	Signals    []RoutedSignal // every generated signal as the gate left it, in bar order
+	Notified   int
+	Suppressed int
+	// SuppressedBy counts suppressed signals per reason.
+	SuppressedBy map[string]int
+	// Unfiltered are the statistics of trading every generated signal, for
+	// comparison with the run's own Stats, which trade notified signals only.
+	Unfiltered Stats
+}
+
+func newRouting() *Routing {
+	return &Routing{SuppressedBy: make(map[string]int)}
+}
+
+// route passes sig through gate, then filter, at its bar time; either may be
+// nil. It records the decision and returns the signal as the gate left it and
+// whether it was notified. A gate error fails the run: a replay that cannot
+// read its state would misreport what the gate did.
+func (r *Routing) route(gate SignalGate, filter SignalFilter, sig core.Signal) (core.Signal, bool, error) {
+	var reason string
+	if gate != nil {
+		var err error
+		sig, reason, err = gate.Apply(sig, sig.GeneratedAt)
+		if err != nil {
+			return sig, false, err
+		}
+	}
+	if reason == "" && filter != nil {
+		reason = filter.Admit(sig, sig.GeneratedAt)
+	}
+	r.Signals = append(r.Signals, RoutedSignal{Signal: sig, Notified: reason == "", Reason: reason})
+	if reason != "" {
+		r.Suppressed++
+		r.SuppressedBy[reason]++
+		return sig, false, nil
+	}
+	r.Notified++
+	return sig, true, nil
+}
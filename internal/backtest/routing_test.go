@@ -0,0 +1,195 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// scriptStrategy emits the scripted signal for each bar's time.
+type scriptStrategy map[time.Time]core.Signal
+
+func (s scriptStrategy) Name() string        { return "script" }
+func (s scriptStrategy) Description() string { return "scripted signals" }
+func (s scriptStrategy) RequiredData() strategy.DataRequirements {
+	return strategy.DataRequirements{PriceHistory: 1}
+}
+func (s scriptStrategy) Init(cfg strategy.Config) error { return nil }
+func (s scriptStrategy) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	if sig, ok := s[ctx.Now]; ok {
+		sig.Symbol = ctx.Symbol
+		return []core.Signal{sig}, nil
+	}
+	return nil, nil
+}
+
+// cooldownFilter mimics the router: a confidence floor and a per-symbol
+// cooldown measured on the time it is given.
+type cooldownFilter struct {
+	cooldown time.Duration
+	last     map[string]time.Time
+}
+
+func (f *cooldownFilter) Admit(sig core.Signal, now time.Time) string {
+	if sig.Confidence < 0.5 {
+		return "min_confidence"
+	}
+	if last, ok := f.last[sig.Symbol]; ok && now.Sub(last) < f.cooldown {
+		return "cooldown"
+	}
+	f.last[sig.Symbol] = now
+	return ""
+}
+
+func TestRun_SignalFilter(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	var bars []core.OHLCV
+	for i, c := range []float64{100, 110, 90, 120} {
+		bars = append(bars, core.OHLCV{Symbol: "AAPL", Open: c, High: c, Low: c, Close: c, Time: day.AddDate(0, 0, i)})
+	}
+	strat := scriptStrategy{
+		day:                  {Action: core.ActionBuy, Confidence: 0.9},
+		day.AddDate(0, 0, 1): {Action: core.ActionSell, Confidence: 0.9}, // within the 2-day cooldown
+		day.AddDate(0, 0, 2): {Action: core.ActionSell, Confidence: 0.3}, // below the confidence floor
+		day.AddDate(0, 0, 3): {Action: core.ActionSell, Confidence: 0.9},
+	}
+	bt := New(&mockProvider{data: bars}, WithSignalFilter(func() SignalFilter {
+		return &cooldownFilter{cooldown: 48 * time.Hour, last: map[string]time.Time{}}
+	}))
+
+	for run := 0; run < 2; run++ { // every run starts from a fresh filter
+		result, err := bt.Run(context.Background(), strat, "AAPL", day, day.AddDate(0, 0, 3))
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		r := result.Routing
+		if r == nil || r.Notified != 2 || r.Suppressed != 2 || r.SuppressedBy["cooldown"] != 1 || r.SuppressedBy["min_confidence"] != 1 {
+			t.Fatalf("run %d: routing = %+v", run, r)
+		}
+		if len(r.Signals) != 4 || !r.Signals[0].Notified || r.Signals[1].Reason != "cooldown" {
+			t.Errorf("run %d: decisions = %+v", run, r.Signals)
+		}
+		if len(result.Signals) != 4 {
+			t.Errorf("run %d: Result.Signals should keep every generated signal, got %d", run, len(result.Signals))
+		}
+		// Notified only: buy at 100, sell at 120. Every signal: sell at 110.
+		if len(result.Trades) != 1 || result.Trades[0].ExitPrice != 120 {
+			t.Errorf("run %d: trades = %+v", run, result.Trades)
+		}
+		if math.Abs(result.Stats.TotalReturn-20) > 1e-9 || math.Abs(r.Unfiltered.TotalReturn-10) > 1e-9 {
+			t.Errorf("run %d: return = %v, unfiltered = %v", run, result.Stats.TotalReturn, r.Unfiltered.TotalReturn)
+		}
+	}
+}
+
+func TestRun_NoSignalFilter(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	bars := []core.OHLCV{{Symbol: "AAPL", Close: 100, Time: day}}
+	result, err := New(&mockProvider{data: bars}).Run(context.Background(), scriptStrategy{}, "AAPL", day, day)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Routing != nil {
+		t.Errorf("Routing = %+v, want nil without a filter", result.Routing)
+	}
+}
+
+// dayGate suppresses buys on the days it lists and marks what it passes.
+type dayGate struct {
+	crisis map[time.Time]bool
+	err    error
+}
+
+func (g dayGate) Apply(sig core.Signal, now time.Time) (core.Signal, string, error) {
+	if g.err != nil {
+		return sig, "", g.err
+	}
+	if g.crisis[now] && sig.Action == core.ActionBuy {
+		return sig, "crisis_state", nil
+	}
+	sig.Metadata = map[string]any{"gated": true}
+	return sig, "", nil
+}
+
+func TestRun_SignalGate(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	var bars []core.OHLCV
+	for i, c := range []float64{100, 110, 90, 120} {
+		bars = append(bars, core.OHLCV{Symbol: "AAPL", Open: c, High: c, Low: c, Close: c, Time: day.AddDate(0, 0, i)})
+	}
+	strat := scriptStrategy{
+		day:                  {Action: core.ActionBuy, Confidence: 0.9}, // suppressed by the gate
+		day.AddDate(0, 0, 2): {Action: core.ActionBuy, Confidence: 0.9},
+		day.AddDate(0, 0, 3): {Action: core.ActionSell, Confidence: 0.9},
+	}
+	gate := dayGate{crisis: map[time.Time]bool{day: true}}
+	// The gate runs before the filter, which never sees the suppressed buy.
+	bt := New(&mockProvider{data: bars}, WithSignalGate(gate), WithSignalFilter(func() SignalFilter {
+		return &cooldownFilter{cooldown: 24 * time.Hour, last: map[string]time.Time{}}
+	}))
+	result, err := bt.Run(context.Background(), strat, "AAPL", day, day.AddDate(0, 0, 3))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	r := result.Routing
+	if r == nil || r.Notified != 2 || r.SuppressedBy["crisis_state"] != 1 || r.Suppressed != 1 {
+		t.Fatalf("routing = %+v", r)
+	}
+	if r.Signals[1].Signal.Metadata["gated"] != true {
+		t.Errorf("routing should record the signal as the gate left it: %+v", r.Signals[1])
+	}
+	// Gated: buy at 90, sell at 120. Every signal: buy at 100, sell at 120.
+	if len(result.Trades) != 1 || result.Trades[0].EntryPrice != 90 {
+		t.Errorf("trades = %+v", result.Trades)
+	}
+	if result.Trades[0].EntrySignal.Metadata["gated"] != true {
+		t.Errorf("the traded signal should be the gated one: %+v", result.Trades[0].EntrySignal)
+	}
+	if math.Abs(r.Unfiltered.TotalReturn-20) > 1e-9 {
+		t.Errorf("unfiltered return = %v", r.Unfiltered.TotalReturn)
+	}
+
+	// A gate that cannot read its state fails the run.
+	failing := New(&mockProvider{data: bars}, WithSignalGate(dayGate{err: errors.New("no replay")}))
+	if _, err := failing.Run(context.Background(), strat, "AAPL", day, day.AddDate(0, 0, 3)); err == nil || !strings.Contains(err.Error(), "no replay") {
+		t.Errorf("failing gate: err = %v", err)
+	}
+}
+
+// dayFilter holds back the signals of the days it lists.
+type dayFilter map[time.Time]bool
+
+func (f dayFilter) Admit(sig core.Signal, now time.Time) string {
+	if f[now] {
+		return "held_back"
+	}
+	return ""
+}
+
+func TestRun_UnfilteredIsItsOwnPass(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	bars := closesToBars("AAPL", day, 100, 101, 102, 103, 104, 105)
+	// The filter holds back the day-0 buy, so the routed run enters a day
+	// late and its strategy sees a different position from then on.
+	bt := New(&mockProvider{data: bars}, WithSignalFilter(func() SignalFilter { return dayFilter{day: true} }))
+	result, err := bt.Run(context.Background(), &holdStrategy{hold: 2}, "AAPL", day, day.AddDate(0, 0, 5))
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	// Routed: buy at 101, sell at 103, buy at 104 still open.
+	if len(result.Trades) != 2 || result.Trades[0].EntryPrice != 101 || result.Trades[1].IsClosed() {
+		t.Errorf("trades = %+v", result.Trades)
+	}
+	// Every signal, with positions of its own: buy at 100 and 103, each sold
+	// two days later, as in TestRun_SuppliesSimulatedPosition.
+	want := (102.0/100*105/103 - 1) * 100
+	if u := result.Routing.Unfiltered; u.WinningTrades != 2 || math.Abs(u.TotalReturn-want) > 1e-9 {
+		t.Errorf("unfiltered = %+v, want 2 wins returning %.4f%%", u, want)
+	}
+}
@@ -18,15 +18,36 @@ type Result struct {
 	// SkippedBars counts bars whose strategy analysis returned an error and
 	// were skipped during the backtest run.
 	SkippedBars int
+	// FillModel is when signals were executed relative to their bar.
+	FillModel FillModel
+	// Execution is the cost and trading-rule model applied to fills; nil
+	// means frictionless fills at the signal price.
+	Execution *ExecutionModel
+	// Rejections lists orders the trading rules refused (lot size, T+1,
+	// limit-up/limit-down).
+	Rejections []Rejection
+	// Capital is the starting cash and Equity the daily mark-to-market
+	// curve of the all-in strategy, one point per bar.
+	Capital float64
+	Equity  []EquityPoint
+	// Routing is set when the run replayed notification filters
+	// (WithSignalFilter) or the signal gate (WithSignalGate); Trades and Stats
+	// then cover notified signals only.
+	Routing *Routing
 }
 
 // Trade represents a simulated trade from entry to exit
 type Trade struct {
 	EntrySignal core.Signal
 	ExitSignal  *core.Signal // nil if position still open
-	EntryPrice  float64
+	EntryTime   time.Time    // Time of the entry fill bar
+	ExitTime    time.Time    // Time of the exit fill bar, or the last bar if open
+	EntryPrice  float64      // Fill price after slippage
 	ExitPrice   float64
-	Return      float64 // Percentage return
+	Return      float64 // Percentage return, net of Fees
+	Quantity    float64
+	Fees        float64 // Commission, taxes and levies of both legs
+	PnL         float64 // Money profit/loss; open trades are marked at the last close
 }
 
 // Stats holds performance statistics
@@ -38,6 +59,22 @@ type Stats struct {
 	TotalReturn   float64 // Net return percentage
 	MaxDrawdown   float64 // Largest peak-to-trough decline
 	SharpeRatio   float64 // Risk-adjusted return (annualized)
+
+	// Equity-curve statistics. When a run has an equity curve, TotalReturn,
+	// MaxDrawdown and SharpeRatio above are also taken from it.
+	AnnualizedReturn float64 // Compound annual growth rate, percent
+	Volatility       float64 // Annualized standard deviation of daily returns, percent
+	SortinoRatio     float64 // Annualized mean daily return over downside deviation
+	CalmarRatio      float64 // AnnualizedReturn / MaxDrawdown
+	Exposure         float64 // Percentage of days with an open position
+	Turnover         float64 // Traded notional per year as a multiple of average equity
+	AvgHoldingDays   float64 // Mean calendar days from entry to exit of closed trades
+
+	// Benchmark comparison; empty Benchmark means none was available.
+	Benchmark       string
+	BenchmarkReturn float64 // Buy-and-hold return of the benchmark, percent
+	Alpha           float64 // Annualized excess return over Beta × benchmark, percent
+	Beta            float64
 }
 
 // IsWin returns true if the trade was profitable
@@ -45,7 +82,61 @@ func (t Trade) IsWin() bool {
 	return t.Return > 0
 }
 
+// ReturnPct returns the trade's return in percent (Return is a fraction).
+func (t Trade) ReturnPct() float64 {
+	return t.Return * 100
+}
+
 // IsClosed returns true if the trade has an exit
 func (t Trade) IsClosed() bool {
 	return t.ExitSignal != nil
 }
+
+// EquityPoint is one day of a mark-to-market equity curve.
+type EquityPoint struct {
+	Time      time.Time
+	Cash      float64
+	Positions float64 // Market value of open positions at the day's close
+	Equity    float64 // Cash + Positions
+	Close     float64 // The symbol's close; 0 in portfolio runs
+}
+
+// PortfolioTrade is a Trade taken inside a shared-capital portfolio run.
+type PortfolioTrade struct {
+	Trade
+	Symbol string
+}
+
+// SymbolContribution is one symbol's share of a portfolio run's P&L.
+type SymbolContribution struct {
+	Symbol        string
+	Trades        int
+	RealizedPnL   float64
+	UnrealizedPnL float64 // P&L of the position still open at the end
+	PnL           float64
+	Contribution  float64 // PnL as a percentage of initial capital
+}
+
+// PortfolioResult holds the output of a portfolio backtest.
+type PortfolioResult struct {
+	Strategies     []string
+	Symbols        []string // Symbols that had data and took part in the run
+	MissingSymbols []string // Symbols skipped because no history was available
+	StartDate      time.Time
+	EndDate        time.Time
+	InitialCapital float64
+	FinalEquity    float64
+	Signals        []core.Signal
+	Trades         []PortfolioTrade
+	Equity         []EquityPoint
+	Contributions  []SymbolContribution
+	// Stats counts trades from Trades; TotalReturn, MaxDrawdown and
+	// SharpeRatio are taken from the daily equity curve.
+	Stats       Stats
+	SkippedBars int
+	FillModel   FillModel
+	// Execution holds the execution model applied to each symbol; empty for
+	// frictionless runs.
+	Execution  map[string]ExecutionModel
+	Rejections []Rejection
+}
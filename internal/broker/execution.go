@@ -300,3 +300,8 @@ func (em *ExecutionManager) GetPendingOrders() []PendingOrder {
 	}
 	return orders
 }
+
+// Tracker returns the position tracker fills are recorded in.
+func (em *ExecutionManager) Tracker() *PositionTracker {
+	return em.tracker
+}
@@ -2,6 +2,9 @@
 // broker.Broker. Market orders fill immediately and fully at the price
 // carried by the order request, while cash, positions, and orders are tracked
 // entirely in memory. See design-spec D1.1.
+//
+// For simulated-time runs (backtests) the broker can be given a clock with
+// SetClock and revalued at bar closes with MarkToMarket.
 package paper
 
 import (
@@ -26,6 +29,11 @@ type PaperBroker struct {
 	orders    map[string]broker.Order
 	counter   int64
 	handler   broker.OrderUpdateHandler
+	clock     func() time.Time
+
+	// Daily P&L bookkeeping, maintained by MarkToMarket.
+	markDay   time.Time // calendar day of the latest mark; zero before the first
+	dayOpenTV float64   // account value carried into markDay
 }
 
 // New creates a PaperBroker with the given initial cash. A non-positive value
@@ -38,6 +46,68 @@ func New(initialCash float64) *PaperBroker {
 		cash:      initialCash,
 		positions: make(map[string]broker.Position),
 		orders:    make(map[string]broker.Order),
+		clock:     time.Now,
+	}
+}
+
+// SetClock replaces the wall clock used to stamp orders, positions and
+// balances, so a backtest can run the broker on bar time. nil restores
+// time.Now.
+func (p *PaperBroker) SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clock = now
+}
+
+// MarkToMarket revalues held positions at the given prices; symbols without a
+// price keep their last mark. The first mark of each calendar day (by the
+// broker clock) also snapshots the account value carried into the day, from
+// which GetBalance reports DailyPL. Without marks, positions stay valued at
+// their last fill and DailyPL is zero.
+func (p *PaperBroker) MarkToMarket(prices map[string]float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.clock()
+	y, m, d := now.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if !day.Equal(p.markDay) {
+		p.markDay = day
+		p.dayOpenTV = p.totalValue()
+	}
+	for symbol, pos := range p.positions {
+		price, ok := prices[symbol]
+		if !ok || price <= 0 {
+			continue
+		}
+		pos.CurrentPrice = price
+		revalue(&pos)
+		pos.UpdatedAt = now
+		p.positions[symbol] = pos
+	}
+}
+
+// totalValue is cash plus the marked value of all positions. Caller must hold
+// the lock.
+func (p *PaperBroker) totalValue() float64 {
+	total := p.cash
+	for _, pos := range p.positions {
+		total += pos.MarketValue
+	}
+	return total
+}
+
+// revalue derives a position's value and unrealized P&L from its quantity,
+// average cost and current price.
+func revalue(pos *broker.Position) {
+	pos.CostBasis = pos.AverageCost * float64(pos.Quantity)
+	pos.MarketValue = pos.CurrentPrice * float64(pos.Quantity)
+	pos.UnrealizedPL = pos.MarketValue - pos.CostBasis
+	pos.UnrealizedPLPercent = 0
+	if pos.CostBasis != 0 {
+		pos.UnrealizedPLPercent = pos.UnrealizedPL / pos.CostBasis * 100
 	}
 }
 
@@ -119,6 +189,9 @@ func (p *PaperBroker) applyFill(req broker.OrderRequest) error {
 			return broker.ErrInsufficientFunds
 		}
 		p.cash -= notional
+		if pos.Quantity == 0 {
+			pos.OpenedAt = p.clock()
+		}
 		totalCost := pos.AverageCost*float64(pos.Quantity) + notional
 		pos.Quantity += req.Quantity
 		pos.AverageCost = totalCost / float64(pos.Quantity)
@@ -139,13 +212,8 @@ func (p *PaperBroker) applyFill(req broker.OrderRequest) error {
 	pos.Symbol = req.Symbol
 	pos.Market = req.Market
 	pos.CurrentPrice = req.Price
-	pos.CostBasis = pos.AverageCost * float64(pos.Quantity)
-	pos.MarketValue = pos.CurrentPrice * float64(pos.Quantity)
-	pos.UnrealizedPL = pos.MarketValue - pos.CostBasis
-	if pos.CostBasis != 0 {
-		pos.UnrealizedPLPercent = pos.UnrealizedPL / pos.CostBasis * 100
-	}
-	pos.UpdatedAt = time.Now()
+	revalue(&pos)
+	pos.UpdatedAt = p.clock()
 	p.positions[req.Symbol] = pos
 	return nil
 }
@@ -153,7 +221,7 @@ func (p *PaperBroker) applyFill(req broker.OrderRequest) error {
 // buildFilledOrder constructs a fully filled order. Caller must hold the lock.
 func (p *PaperBroker) buildFilledOrder(req broker.OrderRequest) broker.Order {
 	p.counter++
-	now := time.Now()
+	now := p.clock()
 	filledAt := now
 	return broker.Order{
 		OrderID:          fmt.Sprintf("PAPER-%d", p.counter),
@@ -257,15 +325,17 @@ func (p *PaperBroker) GetBalance(ctx context.Context) (*broker.Balance, error) {
 	if !p.connected {
 		return nil, broker.ErrNotConnected
 	}
-	total := p.cash
-	for _, pos := range p.positions {
-		total += pos.MarketValue
+	total := p.totalValue()
+	var dailyPL float64
+	if !p.markDay.IsZero() {
+		dailyPL = total - p.dayOpenTV
 	}
 	return &broker.Balance{
 		Cash:        p.cash,
 		BuyingPower: p.cash,
 		TotalValue:  total,
-		UpdatedAt:   time.Now(),
+		DailyPL:     dailyPL,
+		UpdatedAt:   p.clock(),
 	}, nil
 }
 
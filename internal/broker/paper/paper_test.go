@@ -14,6 +14,7 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/newthinker/atlas/internal/broker"
 	"github.com/newthinker/atlas/internal/core"
@@ -370,3 +371,69 @@ func TestReadOpsNotConnected(t *testing.T) {
 		t.Errorf("CancelOrder err = %v", err)
 	}
 }
+
+func TestSetClockStampsOrders(t *testing.T) {
+	pb := connectedBroker(t, 10000)
+	at := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	pb.SetClock(func() time.Time { return at })
+	order, err := pb.PlaceOrder(context.Background(), buyReq("AAPL", 1, 100))
+	if err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	if !order.CreatedAt.Equal(at) || !order.FilledAt.Equal(at) {
+		t.Errorf("order times = %v / %v, want %v", order.CreatedAt, *order.FilledAt, at)
+	}
+	pos, _ := pb.GetPosition(context.Background(), "AAPL")
+	if !pos.UpdatedAt.Equal(at) || !pos.OpenedAt.Equal(at) {
+		t.Errorf("position UpdatedAt/OpenedAt = %v / %v, want %v", pos.UpdatedAt, pos.OpenedAt, at)
+	}
+
+	// Adding to the position keeps the day it was opened.
+	opened := at
+	at = at.AddDate(0, 0, 1)
+	if _, err := pb.PlaceOrder(context.Background(), buyReq("AAPL", 1, 100)); err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	pos, _ = pb.GetPosition(context.Background(), "AAPL")
+	if !pos.OpenedAt.Equal(opened) {
+		t.Errorf("position OpenedAt after adding = %v, want %v", pos.OpenedAt, opened)
+	}
+}
+
+func TestMarkToMarketDailyPL(t *testing.T) {
+	pb := connectedBroker(t, 10000)
+	ctx := context.Background()
+	at := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	pb.SetClock(func() time.Time { return at })
+
+	if _, err := pb.PlaceOrder(ctx, buyReq("AAPL", 10, 100)); err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	if bal, _ := pb.GetBalance(ctx); bal.DailyPL != 0 {
+		t.Errorf("DailyPL before any mark = %v, want 0", bal.DailyPL)
+	}
+
+	pb.MarkToMarket(map[string]float64{"AAPL": 110})
+	bal, _ := pb.GetBalance(ctx)
+	if bal.TotalValue != 10100 || bal.DailyPL != 100 {
+		t.Errorf("day 1: total=%v dailyPL=%v, want 10100 / 100", bal.TotalValue, bal.DailyPL)
+	}
+
+	// A new day measures P&L from the previous day's last mark.
+	at = at.AddDate(0, 0, 1)
+	pb.MarkToMarket(map[string]float64{"AAPL": 95})
+	bal, _ = pb.GetBalance(ctx)
+	if bal.TotalValue != 9950 || bal.DailyPL != -150 {
+		t.Errorf("day 2: total=%v dailyPL=%v, want 9950 / -150", bal.TotalValue, bal.DailyPL)
+	}
+	pos, _ := pb.GetPosition(ctx, "AAPL")
+	if pos.CurrentPrice != 95 || pos.UnrealizedPL != -50 {
+		t.Errorf("position = %+v", pos)
+	}
+
+	// Symbols without a price keep their last mark.
+	pb.MarkToMarket(map[string]float64{"MSFT": 300})
+	if pos, _ := pb.GetPosition(ctx, "AAPL"); pos.CurrentPrice != 95 {
+		t.Errorf("unpriced position re-marked to %v", pos.CurrentPrice)
+	}
+}
@@ -36,10 +36,15 @@ func (pt *PositionTracker) Sync(ctx context.Context) error {
 	pt.mu.Lock()
 	defer pt.mu.Unlock()
 
-	// Clear existing positions and replace with broker data
+	// Clear existing positions and replace with broker data, keeping the
+	// opening time of positions the broker does not date
+	previous := pt.positions
 	pt.positions = make(map[string]*Position)
 	for i := range positions {
 		pos := positions[i]
+		if old, ok := previous[pos.Symbol]; ok && pos.OpenedAt.IsZero() {
+			pos.OpenedAt = old.OpenedAt
+		}
 		pt.positions[pos.Symbol] = &pos
 	}
 	pt.lastSync = time.Now()
@@ -82,7 +87,8 @@ func (pt *PositionTracker) GetAllPositions() []Position {
 }
 
 // UpdateOnFill updates a position based on an order fill.
-// For BUY orders: adds to quantity and calculates weighted average cost.
+// For BUY orders: adds to quantity and calculates weighted average cost; a buy
+// into a flat position dates it at the fill time.
 // For SELL orders: reduces quantity and calculates realized P&L.
 func (pt *PositionTracker) UpdateOnFill(order *Order) {
 	if order == nil || order.FilledQuantity == 0 {
@@ -116,6 +122,12 @@ func (pt *PositionTracker) UpdateOnFill(order *Order) {
 		newQty := oldQty + filledQty
 
 		pos.Quantity = newQty
+		if oldQty == 0 {
+			pos.OpenedAt = time.Now()
+			if order.FilledAt != nil {
+				pos.OpenedAt = *order.FilledAt
+			}
+		}
 		if newQty > 0 {
 			pos.AverageCost = totalCost / float64(newQty)
 		}
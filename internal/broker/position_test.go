@@ -225,6 +225,43 @@ func TestPositionTracker_UpdateOnFill_MultipleBuys(t *testing.T) {
 	}
 }
 
+func TestPositionTracker_UpdateOnFill_OpenedAt(t *testing.T) {
+	ctx := context.Background()
+	mockBroker := mocks.New()
+	if err := mockBroker.Connect(ctx); err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer mockBroker.Disconnect()
+	pt := broker.NewPositionTracker(mockBroker)
+
+	opened := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+	added := opened.AddDate(0, 0, 5)
+	for _, at := range []time.Time{opened, added} {
+		filledAt := at
+		pt.UpdateOnFill(&broker.Order{
+			Symbol:           "AAPL",
+			Side:             broker.OrderSideBuy,
+			FilledQuantity:   100,
+			AverageFillPrice: 150.00,
+			FilledAt:         &filledAt,
+		})
+	}
+
+	// Adding to a held position keeps its opening time
+	if pos := pt.GetPosition("AAPL"); !pos.OpenedAt.Equal(opened) {
+		t.Errorf("expected OpenedAt %v, got: %v", opened, pos.OpenedAt)
+	}
+
+	// A sync from a broker that does not date positions keeps it too
+	mockBroker.AddPosition(broker.Position{Symbol: "AAPL", Quantity: 200, AverageCost: 150.00})
+	if err := pt.Sync(ctx); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if pos := pt.GetPosition("AAPL"); !pos.OpenedAt.Equal(opened) {
+		t.Errorf("expected OpenedAt %v after sync, got: %v", opened, pos.OpenedAt)
+	}
+}
+
 func TestPositionTracker_UpdateOnFill_Sell(t *testing.T) {
 	mockBroker := mocks.New()
 	pt := broker.NewPositionTracker(mockBroker)
@@ -198,6 +198,8 @@ type Position struct {
 	RealizedPL float64 `json:"realized_pl"`
 	// CostBasis is the total cost basis.
 	CostBasis float64 `json:"cost_basis"`
+	// OpenedAt is when the position was opened from flat.
+	OpenedAt time.Time `json:"opened_at"`
 	// UpdatedAt is when the position was last updated.
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -0,0 +1,57 @@
+package akshare
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// YieldPoint 是一天的国债收益率(%)。
+type YieldPoint struct {
+	Date  time.Time
+	Value float64
+}
+
+// cgbColumn 是期限 → bond_zh_us_rate 的中国国债收益率列名。
+// ⚠ live 校验点: 列名须与 aktools 实际响应一致。
+var cgbColumn = map[string]string{
+	"2y":  "中国国债收益率2年",
+	"5y":  "中国国债收益率5年",
+	"10y": "中国国债收益率10年",
+	"30y": "中国国债收益率30年",
+}
+
+// FetchCGBYields 返回 [start,end] 中国国债 tenor(2y/5y/10y/30y)期收益率,升序;
+// 该期限缺值的日子(接口与美债合表,休市日不同)跳过。
+// ⚠ live 校验点: 接口名 bond_zh_us_rate 与其 start_date 参数。
+func (c *Client) FetchCGBYields(tenor string, start, end time.Time) ([]YieldPoint, error) {
+	col, ok := cgbColumn[tenor]
+	if !ok {
+		return nil, fmt.Errorf("akshare: unknown CGB tenor %q (want 2y, 5y, 10y or 30y)", tenor)
+	}
+	rows, err := c.get("bond_zh_us_rate", url.Values{"start_date": {start.Format("20060102")}})
+	if err != nil {
+		return nil, err
+	}
+	s, e := start.Format("2006-01-02"), end.Format("2006-01-02")
+	pts := make([]YieldPoint, 0, len(rows))
+	for _, row := range rows {
+		d, err := fdate(row, "日期", "date")
+		if err != nil {
+			return nil, fmt.Errorf("%w (bond_zh_us_rate)", err)
+		}
+		v := fnum(row, col)
+		if ds := d.Format("2006-01-02"); math.IsNaN(v) || ds < s || ds > e {
+			continue
+		}
+		pts = append(pts, YieldPoint{Date: d, Value: v})
+	}
+	// 与 guardSchemaDrift 同理: 有行却一个收益率都没解析出,多半是列名变了。
+	if len(rows) > 0 && len(pts) == 0 {
+		return nil, fmt.Errorf("akshare: bond_zh_us_rate: fetched %d rows but no %s (probable schema drift)", len(rows), col)
+	}
+	sort.Slice(pts, func(i, j int) bool { return pts[i].Date.Before(pts[j].Date) })
+	return pts, nil
+}
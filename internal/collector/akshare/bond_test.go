@@ -0,0 +1,42 @@
+package akshare
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchCGBYields(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/public/bond_zh_us_rate", r.URL.Path)
+		assert.Equal(t, "20260701", r.URL.Query().Get("start_date"))
+		json.NewEncoder(w).Encode([]map[string]any{
+			{"日期": "2026-07-23", "中国国债收益率10年": 1.71, "美国国债收益率10年": 4.4},
+			{"日期": "2026-07-22", "中国国债收益率10年": "1.69"}, // 字符串数值,乱序
+			{"日期": "2026-07-04", "美国国债收益率10年": 4.3},    // 仅美债开市,跳过
+			{"日期": "2026-08-01", "中国国债收益率10年": 1.8},    // 窗口外
+		})
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	pts, err := c.FetchCGBYields("10y", day(2026, 7, 1), day(2026, 7, 23))
+	require.NoError(t, err)
+	require.Len(t, pts, 2)
+	assert.Equal(t, "2026-07-22", pts[0].Date.Format("2006-01-02"))
+	assert.Equal(t, 1.69, pts[0].Value)
+	assert.Equal(t, 1.71, pts[1].Value)
+
+	_, err = c.FetchCGBYields("30y", day(2026, 7, 1), day(2026, 7, 23))
+	assert.ErrorContains(t, err, "probable schema drift", "有行却无 30 年收益率")
+}
+
+func TestFetchCGBYieldsUnknownTenor(t *testing.T) {
+	c := New("http://127.0.0.1:1")
+	_, err := c.FetchCGBYields("7y", day(2026, 1, 1), day(2026, 7, 23))
+	assert.ErrorContains(t, err, "unknown CGB tenor")
+}
@@ -468,15 +468,20 @@ func mapPolicyError(symbol string, err error) error {
 }
 
 func (e *Eastmoney) fetchHistory(symbol string, start, end time.Time, interval string) ([]core.OHLCV, error) {
-	// Funds use different API for historical NAV
+	intraday := interval != "" && interval != "1d"
+
+	// Funds use different API for historical NAV, which is daily only
 	if e.isFund(symbol) {
+		if intraday {
+			return nil, fmt.Errorf("eastmoney: no %s bars for fund %s", interval, symbol)
+		}
 		return e.fetchFundHistory(symbol, start, end)
 	}
 
 	data, err := e.fetchStockHistory(symbol, start, end, interval)
 	if err != nil {
-		// Try Lixinger fallback if available
-		if e.lixingerFallback != nil && e.lixingerFallback.HasAPIKey() {
+		// Try Lixinger fallback if available; it has daily bars only
+		if !intraday && e.lixingerFallback != nil && e.lixingerFallback.HasAPIKey() {
 			log.Printf("eastmoney: FetchHistory failed for %s, trying lixinger fallback: %v", symbol, err)
 			return e.lixingerFallback.FetchHistory(symbol, start, end, interval)
 		}
@@ -516,15 +521,16 @@ func (e *Eastmoney) fetchStockHistory(symbol string, start, end time.Time, inter
 	}
 
 	data := make([]core.OHLCV, 0, len(result.Data.Klines))
-	re := regexp.MustCompile(`(\d{4}-\d{2}-\d{2}),([^,]+),([^,]+),([^,]+),([^,]+),([^,]+)`)
-
 	for _, line := range result.Data.Klines {
-		matches := re.FindStringSubmatch(line)
+		matches := klineRe.FindStringSubmatch(line)
 		if len(matches) < 7 {
 			continue
 		}
 
-		t, _ := time.Parse("2006-01-02", matches[1])
+		t, err := parseKlineTime(matches[1])
+		if err != nil {
+			continue
+		}
 		open, _ := strconv.ParseFloat(matches[2], 64)
 		closePrice, _ := strconv.ParseFloat(matches[3], 64)
 		high, _ := strconv.ParseFloat(matches[4], 64)
@@ -542,10 +548,39 @@ func (e *Eastmoney) fetchStockHistory(symbol string, start, end time.Time, inter
 			Time:     t,
 		})
 	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("eastmoney: no parsable %s klines for symbol: %s", klt, symbol)
+	}
 
 	return data, nil
 }
 
+// klineRe matches a kline row: "2024-01-02,open,close,high,low,volume,..."
+// for daily bars, "2024-01-02 09:35,..." for intraday ones.
+var klineRe = regexp.MustCompile(`(\d{4}-\d{2}-\d{2}(?: \d{2}:\d{2})?),([^,]+),([^,]+),([^,]+),([^,]+),([^,]+)`)
+
+// klineLoc is the exchange time zone of intraday kline times (bar end, e.g.
+// 09:35 closes the 09:31–09:35 bar). Daily bars stay at UTC midnight, as
+// before intraday support.
+var klineLoc = func() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Shanghai"); err == nil {
+		return loc
+	}
+	return time.FixedZone("CST", 8*3600)
+}()
+
+func parseKlineTime(s string) (time.Time, error) {
+	if len(s) > len("2006-01-02") {
+		return time.ParseInLocation("2006-01-02 15:04", s, klineLoc)
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+// Intervals lists the intervals FetchHistory honours; see toKlineType.
+func (e *Eastmoney) Intervals() []string {
+	return []string{"1m", "5m", "15m", "30m", "1h", "1d"}
+}
+
 func (e *Eastmoney) toKlineType(interval string) string {
 	switch interval {
 	case "1m":
@@ -223,6 +223,37 @@ func TestFetchHistory_Stock(t *testing.T) {
 	}
 }
 
+// Intraday klines carry the bar's end time; each bar keeps it, in exchange time.
+func TestFetchHistory_Intraday(t *testing.T) {
+	body := `{"data":{"code":"600519","name":"X","klines":["2024-01-02 09:35,10.0,11.0,12.0,9.0,1000","2024-01-02 09:40,11.0,12.5,13.0,10.5,2000"]}}`
+	e, _ := newStockServer(t, http.StatusOK, body)
+
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	bars, err := e.FetchHistory("600519.SH", start, start, "5m")
+	if err != nil {
+		t.Fatalf("FetchHistory: %v", err)
+	}
+	if len(bars) != 2 {
+		t.Fatalf("bars = %d, want 2", len(bars))
+	}
+	want := time.Date(2024, 1, 2, 1, 35, 0, 0, time.UTC) // 09:35 +08:00
+	if !bars[0].Time.Equal(want) || !bars[1].Time.Equal(want.Add(5*time.Minute)) {
+		t.Errorf("bar times = %v, %v; want %v and 5m later", bars[0].Time, bars[1].Time, want)
+	}
+	if bars[1].Close != 12.5 || bars[1].Interval != "5m" {
+		t.Errorf("bar1 unexpected: %+v", bars[1])
+	}
+}
+
+// Rows that match no kline layout are an error, not an empty success.
+func TestFetchHistory_UnparsableKlines(t *testing.T) {
+	e, _ := newStockServer(t, http.StatusOK, `{"data":{"code":"600519","name":"X","klines":["20240102T0935,1,2,3,4,5"]}}`)
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	if bars, err := e.FetchHistory("600519.SH", start, start, "5m"); err == nil {
+		t.Fatalf("FetchHistory = %d bars, nil error; want an error", len(bars))
+	}
+}
+
 // boundary[0] + error[2]: HTTP 200 with null data returns error, no panic.
 func TestFetchQuote_NullData(t *testing.T) {
 	e, _ := newStockServer(t, http.StatusOK, `{"data":null}`)
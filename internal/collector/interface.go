@@ -2,6 +2,7 @@ package collector
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/newthinker/atlas/internal/core"
@@ -31,3 +32,19 @@ type Collector interface {
 	FetchQuote(symbol string) (*core.Quote, error)
 	FetchHistory(symbol string, start, end time.Time, interval string) ([]core.OHLCV, error)
 }
+
+// IntervalCollector is implemented by collectors whose FetchHistory honours
+// intervals other than "1d". Other collectors return daily bars whatever
+// interval they are asked for.
+type IntervalCollector interface {
+	Intervals() []string
+}
+
+// SupportsInterval reports whether c fetches bars of the given interval.
+func SupportsInterval(c Collector, interval string) bool {
+	if interval == "1d" {
+		return true
+	}
+	ic, ok := c.(IntervalCollector)
+	return ok && slices.Contains(ic.Intervals(), interval)
+}
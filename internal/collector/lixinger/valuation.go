@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/newthinker/atlas/internal/collector"
+	"github.com/newthinker/atlas/internal/core"
 )
 
 // usHKIndexCodes maps supported international index symbols to Lixinger codes.
@@ -59,28 +60,47 @@ func lookbackGranularity(lookbackYears int) string {
 	}
 }
 
+// metricKeys maps the valuation metrics to Lixinger's metric names.
+// ⚠ live 校验点:pb/ps_ttm/dyr 的 cvpos 按 pe_ttm 的命名规则外推,未经 live 验证。
+var metricKeys = map[string]string{
+	core.MetricPE:            "pe_ttm",
+	core.MetricPB:            "pb",
+	core.MetricPS:            "ps_ttm",
+	core.MetricDividendYield: "dyr",
+}
+
 // FetchValuationPercentile returns the PE-TTM historical percentile (0-100) for
 // a stock or index via Lixinger's cvpos metric. Index endpoints require the
 // market-cap-weighted (.mcw) variant. The metric string doubles as the flat
 // response key (e.g. "pe_ttm.y5.cvpos"). Returns (-1, error) for unsupported
 // symbols or any failure — callers degrade to "percentile unavailable".
 func (l *Lixinger) FetchValuationPercentile(symbol string, lookbackYears int) (float64, error) {
+	return l.FetchMetricPercentile(symbol, core.MetricPE, lookbackYears)
+}
+
+// FetchMetricPercentile is FetchValuationPercentile for any of the valuation
+// metrics core.MetricPE, MetricPB, MetricPS and MetricDividendYield.
+func (l *Lixinger) FetchMetricPercentile(symbol, metric string, lookbackYears int) (float64, error) {
+	key, ok := metricKeys[metric]
+	if !ok {
+		return -1, fmt.Errorf("lixinger: unknown valuation metric %q", metric)
+	}
 	endpoint, code := endpointFor(symbol)
 	if endpoint == "" {
 		return -1, fmt.Errorf("lixinger: valuation percentile unsupported for %s", symbol)
 	}
 	gran := lookbackGranularity(lookbackYears)
 
-	metric := fmt.Sprintf("pe_ttm.%s.cvpos", gran)
+	cvpos := fmt.Sprintf("%s.%s.cvpos", key, gran)
 	if strings.Contains(endpoint, "/index/") {
-		metric = fmt.Sprintf("pe_ttm.%s.mcw.cvpos", gran) // 指数为市值加权
+		cvpos = fmt.Sprintf("%s.%s.mcw.cvpos", key, gran) // 指数为市值加权
 	}
 
 	payload := map[string]any{
 		"token":       l.apiKey,
 		"date":        "latest",
 		"stockCodes":  []string{code},
-		"metricsList": []string{metric},
+		"metricsList": []string{cvpos},
 	}
 	raw, err := l.request(endpoint, payload)
 	if err != nil {
@@ -97,9 +117,9 @@ func (l *Lixinger) FetchValuationPercentile(symbol string, lookbackYears int) (f
 		return -1, fmt.Errorf("lixinger: no valuation data for %s", symbol)
 	}
 
-	v, ok := result.Data[0][metric].(float64) // 扁平 dotted key
+	v, ok := result.Data[0][cvpos].(float64) // 扁平 dotted key
 	if !ok {
-		return -1, fmt.Errorf("lixinger: metric %s missing for %s", metric, symbol)
+		return -1, fmt.Errorf("lixinger: metric %s missing for %s", cvpos, symbol)
 	}
 	return v * 100, nil
 }
@@ -6,6 +6,8 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/newthinker/atlas/internal/core"
 )
 
 // Context Checkpoint: done_criteria → test mapping (TASK-002, plan Task 2)
@@ -164,3 +166,32 @@ func TestFetchValuationPercentile_MissingMetric(t *testing.T) {
 		t.Error("expected error when metric key missing")
 	}
 }
+
+func TestFetchMetricPercentile(t *testing.T) {
+	cases := []struct {
+		symbol, metric, want string
+	}{
+		{"601398.SH", core.MetricPB, "pb.y10.cvpos"},
+		{"600519.SH", core.MetricPS, "ps_ttm.y10.cvpos"},
+		{"000300.SH", core.MetricDividendYield, "dyr.y10.mcw.cvpos"},
+	}
+	for _, c := range cases {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(raw), `"`+c.want+`"`) {
+				t.Errorf("%s: request must ask for %s, got: %s", c.metric, c.want, raw)
+			}
+			_, _ = w.Write([]byte(`{"code":1,"message":"success","data":[{"` + c.want + `":0.125}]}`))
+		}))
+		pct, err := NewWithBaseURL("test-key", srv.URL).FetchMetricPercentile(c.symbol, c.metric, 10)
+		srv.Close()
+		if err != nil || pct != 12.5 {
+			t.Errorf("FetchMetricPercentile(%s, %s) = %v, %v, want 12.5", c.symbol, c.metric, pct, err)
+		}
+	}
+
+	lx := NewWithBaseURL("test-key", "http://unused.invalid")
+	if _, err := lx.FetchMetricPercentile("600519.SH", "roe", 5); err == nil {
+		t.Error("unknown metric should fail before any request")
+	}
+}
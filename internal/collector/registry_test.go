@@ -51,3 +51,19 @@ func TestRegistry_GetAll(t *testing.T) {
 		t.Errorf("expected 2 collectors, got %d", len(all))
 	}
 }
+
+// intervalCollector is a mockCollector that fetches hourly bars.
+type intervalCollector struct{ mockCollector }
+
+func (c *intervalCollector) Intervals() []string { return []string{"1h", "1d"} }
+
+func TestSupportsInterval(t *testing.T) {
+	plain := &mockCollector{name: "plain"}
+	hourly := &intervalCollector{mockCollector{name: "hourly"}}
+	if !SupportsInterval(plain, "1d") || SupportsInterval(plain, "1h") {
+		t.Error("a plain collector fetches daily bars only")
+	}
+	if !SupportsInterval(hourly, "1h") || SupportsInterval(hourly, "5m") {
+		t.Error("an IntervalCollector fetches the intervals it lists")
+	}
+}
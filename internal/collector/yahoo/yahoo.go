@@ -392,6 +392,11 @@ func (y *Yahoo) fetchHistory(symbol string, start, end time.Time, interval strin
 	return data, nil
 }
 
+// Intervals lists the intervals FetchHistory honours; see toYahooInterval.
+func (y *Yahoo) Intervals() []string {
+	return []string{"1m", "5m", "1h", "1d"}
+}
+
 func (y *Yahoo) toYahooInterval(interval string) string {
 	switch interval {
 	case "1m":
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -28,6 +29,8 @@ type Config struct {
 	Qlib       QlibConfig                 `mapstructure:"qlib"`
 	Valuation  ValuationConfig            `mapstructure:"valuation"`
 	Prism      PrismConfig                `mapstructure:"prism"`
+	CrisisGate CrisisGateConfig           `mapstructure:"crisis_gate"`
+	CrisisWeb  CrisisWebConfig            `mapstructure:"crisis_web"`
 }
 
 // ValuationConfig configures the app-side PE-percentile lookback used for EPS
@@ -49,6 +52,41 @@ type QlibConfig struct {
 	ConnMaxLifetime  time.Duration `mapstructure:"conn_max_lifetime"`
 }
 
+// CrisisGateConfig gates routed signals on the crisis monitor's system state.
+// Policies are keyed by state (normal, watch, brewing, crisis); a state
+// without a policy passes signals unchanged.
+type CrisisGateConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// CrisisConfig is the crisis monitor config whose store the gate reads
+	// (default configs/crisis-monitor.yaml).
+	CrisisConfig string `mapstructure:"crisis_config"`
+	// MaxAgeDays is how old the latest evaluation may be before it is
+	// ignored; 0 means 7.
+	MaxAgeDays int                           `mapstructure:"max_age_days"`
+	Policies   map[string]CrisisPolicyConfig `mapstructure:"policies"`
+}
+
+// CrisisPolicyConfig is what the gate does while one system state holds.
+type CrisisPolicyConfig struct {
+	SuppressBuys bool `mapstructure:"suppress_buys"`
+	// BuyConfidenceScale multiplies buy confidence, in (0, 1]; 0 leaves it
+	// unchanged.
+	BuyConfidenceScale float64 `mapstructure:"buy_confidence_scale"`
+	Warn               bool    `mapstructure:"warn"`
+}
+
+// CrisisWebConfig serves the crisis monitor dashboard (/crisis) and its REST
+// API (/api/crisis/...) from the store the monitor writes.
+type CrisisWebConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// CrisisConfig is the crisis monitor config whose store the dashboard
+	// reads (default configs/crisis-monitor.yaml).
+	CrisisConfig string `mapstructure:"crisis_config"`
+}
+
+// defaultCrisisConfigPath matches the crisis CLI's --config default.
+const defaultCrisisConfigPath = "configs/crisis-monitor.yaml"
+
 // AnalysisConfig holds analysis pipeline settings.
 type AnalysisConfig struct {
 	// Workers is the number of parallel analysis workers; <=1 means serial.
@@ -102,11 +140,21 @@ type ServerConfig struct {
 }
 
 type StorageConfig struct {
-	Hot     HotStorageConfig    `mapstructure:"hot"`
-	Cold    ColdStorageConfig   `mapstructure:"cold"`
-	Signals SignalStorageConfig `mapstructure:"signals"`
+	Hot       HotStorageConfig      `mapstructure:"hot"`
+	Cold      ColdStorageConfig     `mapstructure:"cold"`
+	Signals   SignalStorageConfig   `mapstructure:"signals"`
+	Backtests BacktestStorageConfig `mapstructure:"backtests"`
+}
+
+// BacktestStorageConfig is the sqlite database that keeps finished API
+// backtest runs for listing, comparison and re-runs, and saved parameter
+// optimizations.
+type BacktestStorageConfig struct {
+	Path string `mapstructure:"path"` // sqlite db path (default data/backtests.db)
 }
 
+const defaultBacktestsPath = "data/backtests.db"
+
 // SignalStorageConfig selects where generated signals are persisted. Backend
 // defaults to "sqlite" (persistent, data/signals.db) — a deliberate change from
 // the former in-memory-only behaviour, applied in Load/Defaults.
@@ -144,8 +192,12 @@ type CollectorConfig struct {
 }
 
 type StrategyConfig struct {
-	Enabled bool           `mapstructure:"enabled"`
-	Params  map[string]any `mapstructure:"params"`
+	Enabled bool `mapstructure:"enabled"`
+	// Type is empty for a built-in strategy, whose name is the entry key,
+	// "composite" for a strategy combining other strategies' signals, or
+	// "rule" for a strategy whose conditions are expressions.
+	Type   string         `mapstructure:"type"`
+	Params map[string]any `mapstructure:"params"`
 }
 
 type NotifierConfig struct {
@@ -348,6 +400,15 @@ func Load(path string) (*Config, error) {
 	if cfg.Storage.Signals.Path == "" {
 		cfg.Storage.Signals.Path = "data/signals.db"
 	}
+	if cfg.Storage.Backtests.Path == "" {
+		cfg.Storage.Backtests.Path = defaultBacktestsPath
+	}
+	if cfg.CrisisGate.CrisisConfig == "" {
+		cfg.CrisisGate.CrisisConfig = defaultCrisisConfigPath
+	}
+	if cfg.CrisisWeb.CrisisConfig == "" {
+		cfg.CrisisWeb.CrisisConfig = defaultCrisisConfigPath
+	}
 
 	return &cfg, nil
 }
@@ -373,6 +434,9 @@ func Defaults() *Config {
 				Backend: "sqlite",
 				Path:    "data/signals.db",
 			},
+			Backtests: BacktestStorageConfig{
+				Path: defaultBacktestsPath,
+			},
 		},
 		Router: RouterConfig{
 			CooldownHours: 4,
@@ -418,11 +482,22 @@ func Defaults() *Config {
 		Valuation: ValuationConfig{
 			LookbackYears: 5,
 		},
+		CrisisGate: CrisisGateConfig{
+			CrisisConfig: defaultCrisisConfigPath,
+		},
+		CrisisWeb: CrisisWebConfig{
+			CrisisConfig: defaultCrisisConfigPath,
+		},
 	}
 }
 
-// Validate checks the configuration for errors.
-func (c *Config) Validate() error {
+// StrategyValidator checks one strategies entry. Config knows no strategies,
+// so the caller that builds them supplies it.
+type StrategyValidator func(name string, entry StrategyConfig) error
+
+// Validate checks the configuration for errors, the strategies entries with
+// validateStrategy when it is set (see ValidateStrategies).
+func (c *Config) Validate(validateStrategy StrategyValidator) error {
 	// Server validation
 	if c.Server.Port < 1 || c.Server.Port > 65535 {
 		return core.WrapError(core.ErrConfigInvalid,
@@ -474,6 +549,30 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if validateStrategy != nil {
+		if err := c.ValidateStrategies(validateStrategy); err != nil {
+			return err
+		}
+	}
+
+	// Crisis gate validation: policies must name a system state.
+	if c.CrisisGate.MaxAgeDays < 0 {
+		return core.WrapError(core.ErrConfigInvalid,
+			fmt.Errorf("crisis_gate.max_age_days cannot be negative, got %d", c.CrisisGate.MaxAgeDays))
+	}
+	for state, p := range c.CrisisGate.Policies {
+		switch strings.ToLower(state) {
+		case "normal", "watch", "brewing", "crisis":
+		default:
+			return core.WrapError(core.ErrConfigInvalid,
+				fmt.Errorf("crisis_gate.policies: unknown state %q (want normal, watch, brewing or crisis)", state))
+		}
+		if p.BuyConfidenceScale < 0 || p.BuyConfidenceScale > 1 {
+			return core.WrapError(core.ErrConfigInvalid,
+				fmt.Errorf("crisis_gate.policies.%s.buy_confidence_scale must be between 0 and 1, got %f", state, p.BuyConfidenceScale))
+		}
+	}
+
 	// Broker validation
 	if c.Broker.Enabled {
 		// Live trading was withdrawn (FutuBroker not implemented, 2026-07-02
@@ -494,6 +593,23 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// ValidateStrategies checks every strategies entry, enabled or not, in name
+// order with validate, so a typo in a param or a rule expression fails the
+// command instead of silently falling back to defaults.
+func (c *Config) ValidateStrategies(validate StrategyValidator) error {
+	names := make([]string, 0, len(c.Strategies))
+	for name := range c.Strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := validate(name, c.Strategies[name]); err != nil {
+			return core.WrapError(core.ErrConfigInvalid, fmt.Errorf("strategies.%s: %w", name, err))
+		}
+	}
+	return nil
+}
+
 // PrismConfig configures the Prism valuation board module (M1).
 type PrismConfig struct {
 	Enabled            bool              `mapstructure:"enabled"`
@@ -51,7 +51,7 @@ func TestDefaults_RouterPercentileStep_Zero(t *testing.T) {
 func TestConfig_Validate_PercentileStepNegative(t *testing.T) {
 	c := validConfig()
 	c.Router.PercentileStep = -1
-	err := c.Validate()
+	err := c.Validate(nil)
 	if err == nil {
 		t.Fatal("expected error for negative percentile_step, got nil")
 	}
@@ -61,7 +61,7 @@ func TestConfig_Validate_PercentileStepNegative(t *testing.T) {
 	// zero and positive must pass.
 	for _, v := range []float64{0, 5} {
 		c.Router.PercentileStep = v
-		if err := c.Validate(); err != nil {
+		if err := c.Validate(nil); err != nil {
 			t.Errorf("percentile_step=%v: unexpected error %v", v, err)
 		}
 	}
@@ -160,7 +160,7 @@ func TestConfig_Validate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tt.cfg.Validate()
+			err := tt.cfg.Validate(nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -330,7 +330,7 @@ func TestConfig_Validate_Branches(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			c := validConfig()
 			tt.mutate(&c)
-			if err := c.Validate(); (err != nil) != tt.wantErr {
+			if err := c.Validate(nil); (err != nil) != tt.wantErr {
 				t.Errorf("Validate() err=%v, wantErr=%v", err, tt.wantErr)
 			}
 		})
@@ -345,7 +345,7 @@ func TestConfig_Validate_LiveNotSupported(t *testing.T) {
 	c.Broker.Enabled = true
 	c.Broker.Mode = "live"
 
-	err := c.Validate()
+	err := c.Validate(nil)
 	if err == nil {
 		t.Fatal("expected error for live mode")
 	}
@@ -425,7 +425,7 @@ broker:
 	if cfg.Broker.Execution.Mode != "confirm" {
 		t.Errorf("execution.mode = %q, want default %q", cfg.Broker.Execution.Mode, "confirm")
 	}
-	if err := cfg.Validate(); err != nil {
+	if err := cfg.Validate(nil); err != nil {
 		t.Errorf("Validate() with defaulted execution.mode returned error: %v", err)
 	}
 }
@@ -613,12 +613,30 @@ func TestDefaults_SignalStorage(t *testing.T) {
 	}
 }
 
+func TestBacktestStorage_Defaults(t *testing.T) {
+	cfgPath := writeTempConfig(t, `
+storage:
+  backtests:
+    path: /tmp/runs.db
+`)
+	cfg, err := Load(cfgPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Storage.Backtests.Path != "/tmp/runs.db" {
+		t.Errorf("Load Path = %q, want /tmp/runs.db", cfg.Storage.Backtests.Path)
+	}
+	if got := Defaults().Storage.Backtests.Path; got != "data/backtests.db" {
+		t.Errorf("Defaults Path = %q, want data/backtests.db", got)
+	}
+}
+
 // error_handling[1]: an invalid backend is rejected and the error names the
 // offending value.
 func TestConfig_Validate_SignalBackendInvalid(t *testing.T) {
 	cfg := Defaults()
 	cfg.Storage.Signals.Backend = "postgres"
-	err := cfg.Validate()
+	err := cfg.Validate(nil)
 	if err == nil {
 		t.Fatal("expected error for invalid backend, got nil")
 	}
@@ -635,7 +653,7 @@ func TestConfig_Validate_SignalBackendValid(t *testing.T) {
 	for _, backend := range []string{"memory", "sqlite"} {
 		cfg := Defaults()
 		cfg.Storage.Signals.Backend = backend
-		if err := cfg.Validate(); err != nil {
+		if err := cfg.Validate(nil); err != nil {
 			t.Errorf("backend %q must be valid, got %v", backend, err)
 		}
 	}
@@ -726,3 +744,118 @@ func TestLoad_TopicConfigPointerSemantics(t *testing.T) {
 		t.Errorf("未设置的 ttl 应为 nil, got %v", *eps.TTL)
 	}
 }
+
+func TestLoad_RuleStrategies(t *testing.T) {
+	cfgPath := writeTempConfig(t, `
+strategies:
+  dip_buyer:
+    type: rule
+    enabled: true
+    params:
+      entry: "close > sma(200) and rsi(14) < 30 and pe_percentile < 20"
+      exit: "rsi(14) > 70"
+`)
+	cfg, err := Load(cfgPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := cfg.Strategies["dip_buyer"]; got.Type != "rule" || got.Params["exit"] != "rsi(14) > 70" {
+		t.Errorf("dip_buyer = %+v", got)
+	}
+}
+
+func TestLoad_StrategyParams(t *testing.T) {
+	cfg, err := Load(writeTempConfig(t, `
+strategies:
+  ma_crossover:
+    enabled: true
+    params: {fast_period: 20, slow_period: 100}
+  bollinger:
+    params: {period: 20, k: 2.5, mode: breakout}
+  my_strategy:
+    params: {anything: 1}
+`))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := cfg.Strategies["bollinger"].Params["mode"]; got != "breakout" {
+		t.Errorf("bollinger mode = %v", got)
+	}
+}
+
+func TestValidate_StrategyValidator(t *testing.T) {
+	cfg := Defaults()
+	cfg.Strategies = map[string]StrategyConfig{
+		"rsi":          {Enabled: true},
+		"ma_crossover": {Params: map[string]any{"fast_period": 20}},
+	}
+	if err := cfg.Validate(nil); err != nil {
+		t.Errorf("Validate(nil) = %v, want strategies left unchecked", err)
+	}
+
+	var seen []string
+	err := cfg.Validate(func(name string, entry StrategyConfig) error {
+		seen = append(seen, name)
+		if entry.Enabled {
+			return errors.New("bad param")
+		}
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "strategies.rsi: bad param") || !errors.Is(err, core.ErrConfigInvalid) {
+		t.Errorf("Validate() = %v, want the rsi entry rejected", err)
+	}
+	if strings.Join(seen, ",") != "ma_crossover,rsi" {
+		t.Errorf("validator saw %v, want every entry in name order", seen)
+	}
+}
+
+func TestLoad_CrisisGate(t *testing.T) {
+	cfgPath := writeTempConfig(t, `
+crisis_gate:
+  enabled: true
+  max_age_days: 3
+  policies:
+    BREWING:
+      buy_confidence_scale: 0.5
+      warn: true
+    crisis:
+      suppress_buys: true
+`)
+	cfg, err := Load(cfgPath)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	g := cfg.CrisisGate
+	if !g.Enabled || g.MaxAgeDays != 3 || g.CrisisConfig != "configs/crisis-monitor.yaml" {
+		t.Errorf("CrisisGate = %+v", g)
+	}
+	// viper lowercases map keys.
+	if p := g.Policies["brewing"]; p.BuyConfidenceScale != 0.5 || !p.Warn || p.SuppressBuys {
+		t.Errorf("brewing policy = %+v", p)
+	}
+	if p := g.Policies["crisis"]; !p.SuppressBuys {
+		t.Errorf("crisis policy = %+v", p)
+	}
+	if w := cfg.CrisisWeb; w.Enabled || w.CrisisConfig != "configs/crisis-monitor.yaml" {
+		t.Errorf("CrisisWeb = %+v", w)
+	}
+}
+
+func TestConfig_Validate_CrisisGate(t *testing.T) {
+	for name, gate := range map[string]CrisisGateConfig{
+		"unknown state": {Policies: map[string]CrisisPolicyConfig{"panic": {SuppressBuys: true}}},
+		"scale above 1": {Policies: map[string]CrisisPolicyConfig{"watch": {BuyConfidenceScale: 1.5}}},
+		"negative age":  {MaxAgeDays: -1},
+	} {
+		c := validConfig()
+		c.CrisisGate = gate
+		if err := c.Validate(nil); !errors.Is(err, core.ErrConfigInvalid) {
+			t.Errorf("%s: err = %v, want ErrConfigInvalid", name, err)
+		}
+	}
+	c := validConfig()
+	c.CrisisGate.Policies = map[string]CrisisPolicyConfig{"brewing": {BuyConfidenceScale: 0.5}, "crisis": {SuppressBuys: true}}
+	if err := c.Validate(nil); err != nil {
+		t.Errorf("valid policies: %v", err)
+	}
+}
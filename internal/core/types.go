@@ -120,14 +120,49 @@ type Fundamental struct {
 	// 0-100. Negative means unavailable. Source encodes how it was obtained:
 	// "lixinger_cvpos", "reconstructed", or "method:fallback_reason".
 	PEPercentile float64
-	Source       string // Data source
+	// Percentiles holds the historical percentiles (0-100) of the other
+	// valuation metrics, keyed by MetricPB, MetricPS or MetricDividendYield.
+	// A missing key means unavailable.
+	Percentiles map[string]float64
+	Source      string // Data source
 }
 
+// Valuation metrics whose position in their own history percentile
+// strategies rank.
+const (
+	MetricPE            = "pe"
+	MetricPB            = "pb"
+	MetricPS            = "ps"
+	MetricDividendYield = "dividend_yield"
+)
+
 // IsValid checks if fundamental data has required fields
 func (f Fundamental) IsValid() bool {
 	return f.Symbol != "" && !f.Date.IsZero()
 }
 
+// Percentile returns the historical percentile of metric; ok is false when it
+// is unavailable. MetricPE reads PEPercentile.
+func (f Fundamental) Percentile(metric string) (pct float64, ok bool) {
+	if metric == MetricPE {
+		return f.PEPercentile, f.PEPercentile >= 0
+	}
+	pct, ok = f.Percentiles[metric]
+	return pct, ok
+}
+
+// SetPercentile records the historical percentile of metric.
+func (f *Fundamental) SetPercentile(metric string, pct float64) {
+	if metric == MetricPE {
+		f.PEPercentile = pct
+		return
+	}
+	if f.Percentiles == nil {
+		f.Percentiles = make(map[string]float64)
+	}
+	f.Percentiles[metric] = pct
+}
+
 // Action represents a trading signal action
 type Action string
 
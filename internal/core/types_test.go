@@ -64,3 +64,24 @@ func TestFundamental_IsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestFundamental_Percentile(t *testing.T) {
+	f := Fundamental{PEPercentile: -1}
+	if _, ok := f.Percentile(MetricPE); ok {
+		t.Error("negative PE percentile should be unavailable")
+	}
+	if _, ok := f.Percentile(MetricPB); ok {
+		t.Error("unset PB percentile should be unavailable")
+	}
+	f.SetPercentile(MetricPE, 12)
+	f.SetPercentile(MetricPB, 34)
+	if f.PEPercentile != 12 {
+		t.Errorf("PEPercentile = %v, want 12", f.PEPercentile)
+	}
+	if got, ok := f.Percentile(MetricPB); !ok || got != 34 {
+		t.Errorf("Percentile(pb) = %v, %v, want 34", got, ok)
+	}
+	if _, ok := f.Percentile(MetricPS); ok {
+		t.Error("PS percentile was never set")
+	}
+}
@@ -6,16 +6,18 @@ import (
 	"github.com/spf13/viper"
 )
 
-// Config mirrors configs/crisis-monitor.yaml. Typed per indicator rather than
-// a generic threshold DSL because the seven rule shapes differ (persistence,
-// two-sided amber, momentum, steepening); every number stays in YAML so
-// tuning never needs a release (design §4.1).
+// Config mirrors configs/crisis-monitor.yaml. The seven built-in indicators
+// keep their typed sections (Indicators) for tuning; Custom declares further
+// indicators, or replaces a built-in of the same name, with the generic rule
+// primitives of IndicatorDef. Every number stays in YAML so tuning never
+// needs a release (design §4.1).
 type Config struct {
 	Storage      StorageCfg      `mapstructure:"storage"`
 	FRED         FREDCfg         `mapstructure:"fred"`
 	Freshness    FreshnessCfg    `mapstructure:"freshness"`
 	Percentile   PercentileCfg   `mapstructure:"percentile"`
 	Indicators   IndicatorsCfg   `mapstructure:"indicators"`
+	Custom       []IndicatorDef  `mapstructure:"custom_indicators"`
 	StateMachine StateMachineCfg `mapstructure:"state_machine"`
 }
 
@@ -145,5 +147,5 @@ func (c *Config) validate() error {
 	case c.StateMachine.CrisisExitDays < 1 || c.StateMachine.WatchExitDays < 1 || c.StateMachine.BrewingExitDays < 1:
 		return fmt.Errorf("state_machine exit days must be >= 1")
 	}
-	return nil
+	return c.validateCustom()
 }
@@ -0,0 +1,323 @@
+package crisis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"time"
+)
+
+// dashboardWindowYears 未指定区间时的默认回看：最新评估日往前一年。
+const dashboardWindowYears = 1
+
+var (
+	// ErrUnknownIndicator is returned for a name outside Config.IndicatorNames.
+	ErrUnknownIndicator = errors.New("unknown indicator")
+	// ErrInvalidRange is returned for a from/to that is not YYYY-MM-DD or is
+	// reversed.
+	ErrInvalidRange = errors.New("invalid date range")
+)
+
+// Dashboard is the read model behind the crisis web page and REST API. It
+// only reads what the daily eval persisted — evaluations are never re-run,
+// so the page shows exactly what the notifications said (sqlite stays the
+// single source of truth, design §4.3).
+type Dashboard struct {
+	cfg   *Config
+	store *Store
+}
+
+func NewDashboard(cfg *Config, st *Store) *Dashboard {
+	return &Dashboard{cfg: cfg, store: st}
+}
+
+// IndicatorStatus is one indicator evaluation as the dashboard shows it.
+type IndicatorStatus struct {
+	Name    string  `json:"name"`
+	Layer   string  `json:"layer"`
+	Date    string  `json:"date"`
+	Status  Status  `json:"status"`
+	Tag     Tag     `json:"tag,omitempty"`
+	Value   float64 `json:"value"`
+	Reading string  `json:"reading"`
+	// Pct5y 为 nil：分位窗口为空，或该指标不展示分位（补充决策 4）。
+	Pct5y *float64 `json:"pct_5y"`
+}
+
+// StatusView is the latest system evaluation with each indicator's latest row.
+// Date is empty when nothing has been evaluated yet.
+type StatusView struct {
+	Date       string            `json:"date"`
+	State      SystemState       `json:"state"`
+	StateDays  int               `json:"state_days"`
+	AmberCount int               `json:"amber_count"`
+	Indicators []IndicatorStatus `json:"indicators"`
+}
+
+// HistoryDay is one evaluated day of the state timeline.
+type HistoryDay struct {
+	Date       string            `json:"date"`
+	State      SystemState       `json:"state"`
+	AmberCount int               `json:"amber_count"`
+	Statuses   map[string]Status `json:"statuses"`
+}
+
+// Transition is one system state change.
+type Transition struct {
+	Date string      `json:"date"`
+	From SystemState `json:"from"`
+	To   SystemState `json:"to"`
+}
+
+// HistoryView is the evaluated days of [From, To] and the transitions among them.
+type HistoryView struct {
+	From        string       `json:"from"`
+	To          string       `json:"to"`
+	Days        []HistoryDay `json:"days"`
+	Transitions []Transition `json:"transitions"`
+}
+
+// Point is one dated observation.
+type Point struct {
+	Date  string  `json:"date"`
+	Value float64 `json:"value"`
+}
+
+// IndicatorHistory is one indicator's observations and evaluations over
+// [From, To], with its rules and chart threshold lines.
+type IndicatorHistory struct {
+	Name         string            `json:"name"`
+	Layer        string            `json:"layer"`
+	From         string            `json:"from"`
+	To           string            `json:"to"`
+	Rules        string            `json:"rules"`
+	Thresholds   []Threshold       `json:"thresholds"`
+	Observations []Point           `json:"observations"`
+	Evaluations  []IndicatorStatus `json:"evaluations"`
+}
+
+// Chart is one indicator line chart; Note non-empty means no observations in
+// the window and no SVG.
+type Chart struct {
+	Name string
+	Note string
+	SVG  template.HTML
+}
+
+// ChartsView holds the state timeline and per-indicator charts of [From, To],
+// drawn by the same SVG builders as the replay HTML report.
+type ChartsView struct {
+	From, To string
+	Timeline template.HTML
+	Charts   []Chart
+}
+
+// Status returns the latest system state and indicator readings.
+func (d *Dashboard) Status(ctx context.Context) (*StatusView, error) {
+	sys, err := d.store.LatestSystemEval(ctx)
+	if err != nil || sys == nil {
+		return &StatusView{Indicators: []IndicatorStatus{}}, err
+	}
+	v := &StatusView{Date: sys.TS, State: sys.SystemState, AmberCount: sysDetail(*sys).AmberCount,
+		Indicators: []IndicatorStatus{}}
+	recent, err := d.store.RecentSystemEvals(ctx, 500)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range recent {
+		if e.SystemState != sys.SystemState {
+			break
+		}
+		v.StateDays++
+	}
+	for _, ind := range d.cfg.IndicatorNames() {
+		evals, err := d.store.RecentIndicatorEvals(ctx, ind, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(evals) > 0 {
+			v.Indicators = append(v.Indicators, d.indicatorStatus(evals[0]))
+		}
+	}
+	return v, nil
+}
+
+// History returns the evaluated days and state transitions of [from, to];
+// empty bounds default to the year up to the latest evaluation.
+func (d *Dashboard) History(ctx context.Context, from, to string) (*HistoryView, error) {
+	from, to, err := d.window(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	days, err := d.days(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	v := &HistoryView{From: from, To: to, Days: []HistoryDay{}, Transitions: []Transition{}}
+	for _, day := range days {
+		hd := HistoryDay{Date: day.Date, State: day.Res.State, AmberCount: day.Res.Detail.AmberCount,
+			Statuses: map[string]Status{}}
+		for _, ind := range d.cfg.IndicatorNames() {
+			if r, ok := day.Res.Results[ind]; ok {
+				hd.Statuses[ind] = r.Status
+			}
+		}
+		v.Days = append(v.Days, hd)
+		if day.Res.Transitioned() {
+			v.Transitions = append(v.Transitions, Transition{Date: day.Date, From: day.Res.PrevState, To: day.Res.State})
+		}
+	}
+	return v, nil
+}
+
+// Indicator returns one indicator's observations and evaluations over
+// [from, to] (defaults as History).
+func (d *Dashboard) Indicator(ctx context.Context, name, from, to string) (*IndicatorHistory, error) {
+	def, ok := d.cfg.indicatorDef(name)
+	if !ok || def.InputOnly {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownIndicator, name)
+	}
+	from, to, err := d.window(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	obs, err := d.store.SeriesSince(ctx, name, from, to)
+	if err != nil {
+		return nil, err
+	}
+	evals, err := d.store.EvaluationsBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	v := &IndicatorHistory{
+		Name: name, Layer: layerName(d.cfg, name), From: from, To: to,
+		Rules: ruleSummary(def), Thresholds: d.cfg.Thresholds(name),
+		Observations: make([]Point, 0, len(obs)), Evaluations: []IndicatorStatus{},
+	}
+	if v.Thresholds == nil {
+		v.Thresholds = []Threshold{}
+	}
+	for _, o := range obs {
+		v.Observations = append(v.Observations, Point{Date: o.Date, Value: o.Value})
+	}
+	for _, e := range evals {
+		if e.Indicator == name {
+			v.Evaluations = append(v.Evaluations, d.indicatorStatus(e))
+		}
+	}
+	return v, nil
+}
+
+// Charts draws the state timeline and every indicator's line chart with its
+// threshold lines over [from, to] (defaults as History).
+func (d *Dashboard) Charts(ctx context.Context, from, to string) (*ChartsView, error) {
+	from, to, err := d.window(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	days, err := d.days(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	v := &ChartsView{From: from, To: to}
+	if len(days) == 0 {
+		return v, nil
+	}
+	v.Timeline = template.HTML(timelineSVG(days))
+	for _, ind := range d.cfg.IndicatorNames() {
+		obs, err := d.store.SeriesSince(ctx, ind, from, to)
+		if err != nil {
+			return nil, err
+		}
+		c := Chart{Name: ind}
+		if len(obs) == 0 {
+			c.Note = "区间内无观测数据"
+		} else {
+			c.SVG = template.HTML(lineChartSVG(d.cfg, ind, days, obs))
+		}
+		v.Charts = append(v.Charts, c)
+	}
+	return v, nil
+}
+
+// window validates from/to and fills empty bounds: to defaults to the latest
+// evaluated day (today before any evaluation), from to a year before to.
+func (d *Dashboard) window(ctx context.Context, from, to string) (string, string, error) {
+	for _, s := range []string{from, to} {
+		if _, err := time.Parse(dateLayout, s); s != "" && err != nil {
+			return "", "", fmt.Errorf("%w: %q is not YYYY-MM-DD", ErrInvalidRange, s)
+		}
+	}
+	if to == "" {
+		sys, err := d.store.LatestSystemEval(ctx)
+		if err != nil {
+			return "", "", err
+		}
+		to = time.Now().Format(dateLayout)
+		if sys != nil {
+			to = sys.TS
+		}
+	}
+	if from == "" {
+		from = addYears(to, -dashboardWindowYears)
+	}
+	if from > to {
+		return "", "", fmt.Errorf("%w: from %s is after to %s", ErrInvalidRange, from, to)
+	}
+	return from, to, nil
+}
+
+// days rebuilds the persisted evaluations of [from, to] as ReplayDays so the
+// replay report's SVG builders draw them unchanged. Days without a system
+// row (e.g. only the intraday JPY alert) are skipped; StateDays counts from
+// the window start.
+func (d *Dashboard) days(ctx context.Context, from, to string) ([]ReplayDay, error) {
+	evals, err := d.store.EvaluationsBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	var out []ReplayDay
+	results := map[string]IndicatorResult{}
+	for _, e := range evals {
+		if e.Indicator != "" {
+			results[e.Indicator] = IndicatorResult{Indicator: e.Indicator, Status: e.Status,
+				RawStatus: rawFromDetail(e), Tag: e.Tag, Value: e.Value, Pct5y: e.Pct5y}
+			continue
+		}
+		if len(out) > 0 && out[len(out)-1].Date == e.TS {
+			out = out[:len(out)-1] // 同日重复评估：取最后一次
+		}
+		det := sysDetail(e)
+		res := &DayResult{Date: e.TS, Results: results, PrevState: det.Prev, State: e.SystemState, Detail: det}
+		if res.PrevState == "" {
+			res.PrevState = res.State
+		}
+		day := ReplayDay{Date: e.TS, Res: res, StateDays: 1}
+		if n := len(out); n > 0 && !res.Transitioned() {
+			day.StateDays = out[n-1].StateDays + 1
+		}
+		out = append(out, day)
+		results = map[string]IndicatorResult{}
+	}
+	return out, nil
+}
+
+func (d *Dashboard) indicatorStatus(e Evaluation) IndicatorStatus {
+	s := IndicatorStatus{
+		Name: e.Indicator, Layer: layerName(d.cfg, e.Indicator), Date: e.TS,
+		Status: e.Status, Tag: e.Tag, Value: e.Value, Reading: formatReading(d.cfg, e.Indicator, e.Value),
+	}
+	if p := e.Pct5y; p >= 0 && showPct5y(d.cfg, e.Indicator) {
+		s.Pct5y = &p
+	}
+	return s
+}
+
+// sysDetail decodes a system row's detail; a malformed one reads as zero.
+func sysDetail(e Evaluation) SysDetail {
+	var det SysDetail
+	_ = json.Unmarshal([]byte(e.Detail), &det)
+	return det
+}
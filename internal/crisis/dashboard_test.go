@@ -0,0 +1,121 @@
+package crisis
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dashboardFixture 把 replaySeries 的观测与逐日评估落库（末 4 日情绪双红 →
+// 2026-07-07 起 CRISIS），返回库与回放快照供对照。
+func dashboardFixture(t *testing.T) (*Dashboard, []ReplayDay) {
+	t.Helper()
+	const end = "2026-07-10"
+	ctx := context.Background()
+	cfg, sr := testConfig(), replaySeries(end, 12, 4)
+	st := newTestStore(t)
+	for _, obs := range sr {
+		require.NoError(t, st.UpsertObservations(ctx, obs))
+	}
+	days, err := ReplayRange(cfg, sr, "2026-06-29", end)
+	require.NoError(t, err)
+	for _, d := range days {
+		require.NoError(t, st.AppendEvaluations(ctx, d.Res.Evaluations))
+	}
+	return NewDashboard(cfg, st), days
+}
+
+func TestDashboardStatus(t *testing.T) {
+	ctx := context.Background()
+	empty, err := NewDashboard(testConfig(), newTestStore(t)).Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "", empty.Date) // 尚无评估
+	assert.Empty(t, empty.Indicators)
+
+	db, _ := dashboardFixture(t)
+	v, err := db.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-07-10", v.Date)
+	assert.Equal(t, StateCrisis, v.State)
+	assert.Equal(t, 4, v.StateDays) // 07-07..07-10
+	require.Len(t, v.Indicators, len(AllIndicators))
+
+	vix := v.Indicators[0]
+	assert.Equal(t, IndVIX, vix.Name)
+	assert.Equal(t, "情绪", vix.Layer)
+	assert.Equal(t, StatusRed, vix.Status)
+	assert.Equal(t, "35.0", vix.Reading)
+	require.NotNil(t, vix.Pct5y)
+	assert.Nil(t, v.Indicators[2].Pct5y) // sofr_effr 不展示分位（补充决策 4）
+}
+
+func TestDashboardHistory(t *testing.T) {
+	ctx := context.Background()
+	db, _ := dashboardFixture(t)
+
+	v, err := db.History(ctx, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-07-10", v.From) // 默认：最新评估日往前一年
+	assert.Equal(t, "2026-07-10", v.To)
+	require.Len(t, v.Days, 12)
+	assert.Equal(t, StatusRed, v.Days[11].Statuses[IndMOVE])
+	assert.Equal(t, []Transition{{Date: "2026-07-07", From: StateNormal, To: StateCrisis}}, v.Transitions)
+
+	v, err = db.History(ctx, "2026-07-08", "2026-07-10")
+	require.NoError(t, err)
+	assert.Len(t, v.Days, 3)
+	assert.Empty(t, v.Transitions) // 窗口内无迁移
+
+	for _, bad := range [][2]string{{"2026-7-1", ""}, {"", "yesterday"}, {"2026-07-10", "2026-07-01"}} {
+		_, err = db.History(ctx, bad[0], bad[1])
+		assert.True(t, errors.Is(err, ErrInvalidRange), bad)
+	}
+}
+
+func TestDashboardIndicator(t *testing.T) {
+	ctx := context.Background()
+	db, _ := dashboardFixture(t)
+
+	v, err := db.Indicator(ctx, IndVIX, "2026-07-06", "2026-07-10")
+	require.NoError(t, err)
+	assert.Equal(t, "value>30 RED / value≥25 AMBER · wow>0.5 AMBER · 5y分位轨", v.Rules)
+	assert.Equal(t, []Threshold{{25, StatusAmber}, {30, StatusRed}}, v.Thresholds)
+	require.Len(t, v.Observations, 5)
+	assert.Equal(t, Point{Date: "2026-07-07", Value: 35}, v.Observations[1])
+	require.Len(t, v.Evaluations, 5)
+	assert.Equal(t, StatusGreen, v.Evaluations[0].Status)
+	assert.Equal(t, StatusRed, v.Evaluations[1].Status)
+
+	v, err = db.Indicator(ctx, IndUSDJPY, "", "")
+	require.NoError(t, err)
+	assert.NotNil(t, v.Thresholds) // 无阈值线 → 空数组而非 null
+
+	for _, name := range []string{"absent", indSOFR} { // 输入腿不对外
+		_, err = db.Indicator(ctx, name, "", "")
+		assert.True(t, errors.Is(err, ErrUnknownIndicator), name)
+	}
+}
+
+// 页面图形与回放 HTML 报告出自同一组 SVG 构造器：由落库评估重建的快照画出的
+// 时间线与折线，与直接回放的逐字节一致。
+func TestDashboardChartsReuseReplayBuilders(t *testing.T) {
+	ctx := context.Background()
+	db, days := dashboardFixture(t)
+
+	v, err := db.Charts(ctx, "2026-06-29", "2026-07-10")
+	require.NoError(t, err)
+	assert.Equal(t, template.HTML(timelineSVG(days)), v.Timeline)
+	require.Len(t, v.Charts, len(AllIndicators))
+	obs, err := db.store.SeriesSince(ctx, IndHYOAS, "2026-06-29", "2026-07-10")
+	require.NoError(t, err)
+	assert.Equal(t, template.HTML(lineChartSVG(db.cfg, IndHYOAS, days, obs)), v.Charts[3].SVG)
+
+	v, err = db.Charts(ctx, "2027-01-01", "2027-02-01")
+	require.NoError(t, err)
+	assert.Empty(t, v.Timeline)
+	assert.Empty(t, v.Charts)
+}
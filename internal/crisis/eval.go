@@ -6,8 +6,8 @@ import (
 )
 
 // DayResult is one day's full evaluation: per-indicator results, the state
-// transition and the ready-to-persist audit rows (7 indicator rows + 1
-// system row, in AllIndicators order then system last).
+// transition and the ready-to-persist audit rows (one row per evaluated
+// indicator in Config.IndicatorNames order, then the system row last).
 type DayResult struct {
 	Date        string
 	Results     map[string]IndicatorResult
@@ -23,14 +23,18 @@ func (r *DayResult) Transitioned() bool { return r.State != r.PrevState }
 // suppression → hysteresis → state machine → audit rows. It is pure over
 // (SeriesReader, EvalHistory), so live eval and replay share it.
 func EvalDay(cfg *Config, date string, sr SeriesReader, hist EvalHistory, evalAt time.Time) (*DayResult, error) {
-	results := make(map[string]IndicatorResult, len(AllIndicators))
-	for _, ind := range AllIndicators {
-		r, err := EvaluateIndicator(cfg, ind, date, sr)
+	defs := cfg.evaluatedDefs()
+	results := make(map[string]IndicatorResult, len(defs))
+	names := make([]string, len(defs))
+	for i, def := range defs {
+		ind := def.Name
+		names[i] = ind
+		r, err := evaluateDef(cfg, def, date, sr)
 		if err != nil {
 			return nil, err
 		}
 		switch {
-		case ind == IndSOFREFFR && cfg.Indicators.SOFREFFR.SuppressQuarterEnd &&
+		case def.SuppressQuarterEnd &&
 			severity(r.RawStatus) >= severity(StatusAmber) && InQuarterEndWindow(date):
 			r.Status = StatusSuppressed // 设计 §3.2 条 1：仅记录不告警、退出共振
 		case isColor(r.RawStatus):
@@ -57,16 +61,16 @@ func EvalDay(cfg *Config, date string, sr SeriesReader, hist EvalHistory, evalAt
 	det.Date = date
 
 	res := &DayResult{Date: date, Results: results, PrevState: prevState, State: next, Detail: det}
-	if res.Evaluations, err = buildEvaluations(res, evalAt); err != nil {
+	if res.Evaluations, err = buildEvaluations(res, names, evalAt); err != nil {
 		return nil, err
 	}
 	return res, nil
 }
 
-func buildEvaluations(r *DayResult, evalAt time.Time) ([]Evaluation, error) {
+func buildEvaluations(r *DayResult, names []string, evalAt time.Time) ([]Evaluation, error) {
 	stamp := NowStamp(evalAt)
-	out := make([]Evaluation, 0, len(AllIndicators)+1)
-	for _, ind := range AllIndicators {
+	out := make([]Evaluation, 0, len(names)+1)
+	for _, ind := range names {
 		ir := r.Results[ind]
 		d, err := json.Marshal(indDetail{Raw: ir.RawStatus, WindowActualObs: ir.WindowActualObs,
 			PersistDays: ir.PersistDays, Wow: ir.Wow, WowOK: ir.WowOK})
@@ -166,7 +166,7 @@ func TestBuildEvaluationsCarriesPersistAndWow(t *testing.T) {
 	r.Results[IndUSDJPY] = IndicatorResult{Indicator: IndUSDJPY,
 		Status: StatusRed, RawStatus: StatusRed, Wow: -0.031, WowOK: true}
 
-	evals, err := buildEvaluations(r, time.Date(2026, 7, 11, 0, 0, 0, 0, time.UTC))
+	evals, err := buildEvaluations(r, AllIndicators, time.Date(2026, 7, 11, 0, 0, 0, 0, time.UTC))
 	require.NoError(t, err)
 	byInd := map[string]Evaluation{}
 	for _, e := range evals {
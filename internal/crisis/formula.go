@@ -0,0 +1,195 @@
+package crisis
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"unicode"
+)
+
+// formula is a compiled source.expr: + - * / and parentheses over numbers
+// and indicator names, evaluated per observation date.
+type formula struct {
+	root formulaNode
+	refs []string // 引用的指标，按首次出现序
+}
+
+type formulaNode interface {
+	eval(vals map[string]float64) (float64, bool)
+}
+
+type numNode float64
+
+func (n numNode) eval(map[string]float64) (float64, bool) { return float64(n), true }
+
+type refNode string
+
+func (r refNode) eval(vals map[string]float64) (float64, bool) {
+	v, ok := vals[string(r)]
+	return v, ok
+}
+
+type negNode struct{ x formulaNode }
+
+func (n negNode) eval(vals map[string]float64) (float64, bool) {
+	v, ok := n.x.eval(vals)
+	return -v, ok
+}
+
+type binNode struct {
+	op   byte
+	l, r formulaNode
+}
+
+// eval 除数为 0 视作当日无值（跳过该日），不写入 Inf。
+func (b binNode) eval(vals map[string]float64) (float64, bool) {
+	l, ok := b.l.eval(vals)
+	if !ok {
+		return 0, false
+	}
+	r, ok := b.r.eval(vals)
+	if !ok {
+		return 0, false
+	}
+	switch b.op {
+	case '+':
+		return l + r, true
+	case '-':
+		return l - r, true
+	case '*':
+		return l * r, true
+	}
+	if r == 0 {
+		return 0, false
+	}
+	return l / r, true
+}
+
+// eval returns the formula's value for one date; ok=false when a referenced
+// indicator has no value that day or a division by zero occurs.
+func (f *formula) eval(vals map[string]float64) (float64, bool) { return f.root.eval(vals) }
+
+// parseFormula compiles src by recursive descent (expr := term {+|- term},
+// term := factor {*|/ factor}, factor := number | name | -factor | (expr)).
+func parseFormula(src string) (*formula, error) {
+	p := &formulaParser{src: []rune(src)}
+	f := &formula{}
+	p.refs = &f.refs
+	root, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(p.src) {
+		return nil, fmt.Errorf("unexpected %q at column %d", p.src[p.pos], p.pos+1)
+	}
+	f.root = root
+	return f, nil
+}
+
+type formulaParser struct {
+	src  []rune
+	pos  int
+	refs *[]string
+}
+
+func (p *formulaParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+// peekOp returns the next non-space rune if it is one of ops.
+func (p *formulaParser) peekOp(ops string) (rune, bool) {
+	p.skipSpace()
+	if p.pos < len(p.src) && slices.Contains([]rune(ops), p.src[p.pos]) {
+		return p.src[p.pos], true
+	}
+	return 0, false
+}
+
+func (p *formulaParser) expr() (formulaNode, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.peekOp("+-")
+		if !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left = binNode{op: byte(op), l: left, r: right}
+	}
+}
+
+func (p *formulaParser) term() (formulaNode, error) {
+	left, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.peekOp("*/")
+		if !ok {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		left = binNode{op: byte(op), l: left, r: right}
+	}
+}
+
+func (p *formulaParser) factor() (formulaNode, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	r := p.src[p.pos]
+	switch {
+	case r == '-':
+		p.pos++
+		x, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return negNode{x}, nil
+	case r == '(':
+		p.pos++
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.peekOp(")"); !ok {
+			return nil, fmt.Errorf("missing ) at column %d", p.pos+1)
+		}
+		p.pos++
+		return x, nil
+	case unicode.IsDigit(r) || r == '.':
+		start := p.pos
+		for p.pos < len(p.src) && (unicode.IsDigit(p.src[p.pos]) || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		v, err := strconv.ParseFloat(string(p.src[start:p.pos]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at column %d", string(p.src[start:p.pos]), start+1)
+		}
+		return numNode(v), nil
+	case unicode.IsLetter(r) || r == '_':
+		start := p.pos
+		for p.pos < len(p.src) && (unicode.IsLetter(p.src[p.pos]) || unicode.IsDigit(p.src[p.pos]) || p.src[p.pos] == '_') {
+			p.pos++
+		}
+		name := string(p.src[start:p.pos])
+		if !slices.Contains(*p.refs, name) {
+			*p.refs = append(*p.refs, name)
+		}
+		return refNode(name), nil
+	}
+	return nil, fmt.Errorf("unexpected %q at column %d", r, p.pos+1)
+}
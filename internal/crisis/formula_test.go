@@ -0,0 +1,46 @@
+package crisis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormulaEval(t *testing.T) {
+	vals := map[string]float64{"sofr": 4.30, "effr": 4.40, "dgs10": 4.2, "dgs2": 3.9}
+	tests := []struct {
+		src  string
+		want float64
+		refs []string
+	}{
+		{"(sofr - effr) * 100", -10, []string{"sofr", "effr"}},
+		{"sofr - effr * 100", 4.30 - 440, []string{"sofr", "effr"}}, // 乘除优先
+		{"-dgs2 + dgs10", 0.3, []string{"dgs2", "dgs10"}},
+		{"dgs10 / dgs2 / 2", 4.2 / 3.9 / 2, []string{"dgs10", "dgs2"}}, // 左结合
+		{"2.5", 2.5, nil},
+		{" dgs10-dgs10 ", 0, []string{"dgs10"}}, // 重复引用只记一次
+	}
+	for _, tt := range tests {
+		f, err := parseFormula(tt.src)
+		require.NoError(t, err, tt.src)
+		assert.Equal(t, tt.refs, f.refs, tt.src)
+		v, ok := f.eval(vals)
+		require.True(t, ok, tt.src)
+		assert.InDelta(t, tt.want, v, 1e-9, tt.src)
+	}
+
+	f, err := parseFormula("sofr / (dgs10 - 4.2)")
+	require.NoError(t, err)
+	_, ok := f.eval(vals)
+	assert.False(t, ok) // 除零 → 当日无值
+	_, ok = f.eval(map[string]float64{"sofr": 1})
+	assert.False(t, ok) // 缺引用 → 当日无值
+}
+
+func TestParseFormulaErrors(t *testing.T) {
+	for _, src := range []string{"", "sofr -", "(sofr - effr", "sofr effr", "1.2.3", "sofr % 2", "()"} {
+		_, err := parseFormula(src)
+		assert.Error(t, err, src)
+	}
+}
@@ -0,0 +1,467 @@
+package crisis
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// IndicatorDef declares one indicator generically: where its series comes
+// from, the rules that color it and how notifications show it. The built-in
+// indicators are IndicatorDefs too (builtinDefs, fed by the typed
+// IndicatorsCfg); a custom_indicators entry of the same name replaces one, so
+// a new stress indicator is configuration rather than code.
+type IndicatorDef struct {
+	Name   string    `mapstructure:"name"`
+	Source SourceDef `mapstructure:"source"`
+	// Layer 冰山层：credit / liquidity / sentiment / leading / corroborating
+	//（通知设计 §6.2，异常区同级按此排序）。
+	Layer string `mapstructure:"layer"`
+	// Frequency daily（默认）或 weekly，决定 STALE 用哪个时效阈值。
+	Frequency string `mapstructure:"frequency"`
+	// InputOnly 的指标只采集入库、供 expr 引用，不评估、不展示、不计共振。
+	InputOnly          bool       `mapstructure:"input_only"`
+	PercentileTrack    bool       `mapstructure:"percentile_track"`
+	SuppressQuarterEnd bool       `mapstructure:"suppress_quarter_end"`
+	Rules              []RuleDef  `mapstructure:"rules"`
+	Display            DisplayDef `mapstructure:"display"`
+}
+
+// SourceDef names exactly one of a FRED series, a Yahoo symbol or an
+// expression over other indicators (derived per date where every referenced
+// indicator has an observation).
+type SourceDef struct {
+	FRED  string  `mapstructure:"fred"`
+	Scale float64 `mapstructure:"scale"` // FRED 读数乘数（百分数→bp 用 100），0 视作 1
+	Yahoo string  `mapstructure:"yahoo"`
+	Expr  string  `mapstructure:"expr"`
+}
+
+// RuleDef is one independent check: a measure of the series and its levels,
+// tried in order with the first match winning. Checks only escalate the
+// status (maxStatus); the first matching level that carries a tag sets it.
+type RuleDef struct {
+	Measure     string     `mapstructure:"measure"`      // value（默认）/ wow / change / percentile / rebound
+	Obs         int        `mapstructure:"obs"`          // change / rebound 的回看观测数
+	WindowYears int        `mapstructure:"window_years"` // percentile 的窗口年数
+	MinObs      int        `mapstructure:"min_obs"`      // percentile 的最少观测数，0 取 60
+	TroughBelow float64    `mapstructure:"trough_below"` // rebound：回看低点须低于此值才算反弹
+	Levels      []LevelDef `mapstructure:"levels"`
+}
+
+// LevelDef is one threshold: exactly one direction (above >, at_least ≥,
+// below <, at_most ≤). Days > 0 asks the last Days observations to hold the
+// condition consecutively (value measure only). Status empty = tag only.
+type LevelDef struct {
+	Status  Status   `mapstructure:"status"`
+	Tag     Tag      `mapstructure:"tag"`
+	Above   *float64 `mapstructure:"above"`
+	AtLeast *float64 `mapstructure:"at_least"`
+	Below   *float64 `mapstructure:"below"`
+	AtMost  *float64 `mapstructure:"at_most"`
+	Days    int      `mapstructure:"days"`
+}
+
+// DisplayDef drives the reading/delta formats of notifications and replay
+// reports: Decimals digits plus Unit, Signed readings carry +/-; deltas are
+// always signed.
+type DisplayDef struct {
+	Decimals  int    `mapstructure:"decimals"`
+	Unit      string `mapstructure:"unit"`
+	Signed    bool   `mapstructure:"signed"`
+	HidePct5y bool   `mapstructure:"hide_pct5y"`
+}
+
+const (
+	MeasureValue      = "value"
+	MeasureWow        = "wow"
+	MeasureChange     = "change"
+	MeasurePercentile = "percentile"
+	MeasureRebound    = "rebound"
+)
+
+const (
+	FrequencyDaily  = "daily"
+	FrequencyWeekly = "weekly"
+)
+
+// layers 冰山层，按异常区同级排序（深层优先看）：信用→流动性→情绪→领先→旁证。
+var layers = []struct{ key, name string }{
+	{"credit", "信用"},
+	{"liquidity", "流动性"},
+	{"sentiment", "情绪"},
+	{"leading", "领先"},
+	{"corroborating", "旁证"},
+}
+
+// SOFR/EFFR 两腿：sofr_effr 的 expr 引用的纯输入指标。
+const (
+	indSOFR = "sofr"
+	indEFFR = "effr"
+)
+
+func ptr(v float64) *float64 { return &v }
+
+// builtinDefs expresses the seven typed indicators (plus the SOFR/EFFR legs)
+// as IndicatorDefs. Thresholds come from cfg.Indicators; sources and display
+// are fixed, as they were before indicators were declarable.
+func (c *Config) builtinDefs() []IndicatorDef {
+	ic := c.Indicators
+	return []IndicatorDef{
+		{
+			Name: IndVIX, Source: SourceDef{FRED: "VIXCLS"}, Layer: "sentiment",
+			PercentileTrack: ic.VIX.PercentileTrack,
+			Rules: []RuleDef{
+				{Levels: []LevelDef{{Status: StatusRed, Above: ptr(ic.VIX.Red)}, {Status: StatusAmber, AtLeast: ptr(ic.VIX.Amber)}}},
+				{Measure: MeasureWow, Levels: []LevelDef{{Status: StatusAmber, Above: ptr(ic.VIX.WeeklySpikePct)}}},
+			},
+			Display: DisplayDef{Decimals: 1},
+		},
+		{
+			Name: IndMOVE, Source: SourceDef{Yahoo: "^MOVE"}, Layer: "sentiment",
+			PercentileTrack: ic.MOVE.PercentileTrack,
+			Rules: []RuleDef{
+				{Levels: []LevelDef{{Status: StatusRed, Above: ptr(ic.MOVE.Red)}, {Status: StatusAmber, AtLeast: ptr(ic.MOVE.Amber)}}},
+			},
+			Display: DisplayDef{Decimals: 1},
+		},
+		{Name: indSOFR, Source: SourceDef{FRED: "SOFR"}, InputOnly: true},
+		{Name: indEFFR, Source: SourceDef{FRED: "EFFR"}, InputOnly: true},
+		{
+			// 持续性是核心降噪（设计 §3.1 注 3）：红/黄各自要求连续 N 个观测超阈。
+			Name: IndSOFREFFR, Source: SourceDef{Expr: "(sofr - effr) * 100"}, Layer: "liquidity",
+			PercentileTrack: ic.SOFREFFR.PercentileTrack, SuppressQuarterEnd: ic.SOFREFFR.SuppressQuarterEnd,
+			Rules: []RuleDef{
+				{Levels: []LevelDef{
+					{Status: StatusRed, Above: ptr(ic.SOFREFFR.RedBp), Days: ic.SOFREFFR.RedPersistDays},
+					{Status: StatusAmber, Above: ptr(ic.SOFREFFR.AmberBp), Days: ic.SOFREFFR.AmberPersistDays},
+				}},
+			},
+			Display: DisplayDef{Unit: "bp", Signed: true, HidePct5y: true},
+		},
+		{
+			// 双向黄灯（设计 §3.1 注 1）：过紧是自满、中度走阔是压力；月动量先于水平捕捉急速走阔。
+			Name: IndHYOAS, Source: SourceDef{FRED: "BAMLH0A0HYM2", Scale: 100}, Layer: "credit",
+			PercentileTrack: ic.HYOAS.PercentileTrack,
+			Rules: []RuleDef{
+				{Levels: []LevelDef{
+					{Status: StatusRed, Above: ptr(ic.HYOAS.RedBp)},
+					{Status: StatusAmber, Tag: TagComplacency, Below: ptr(ic.HYOAS.AmberLowBp)},
+					{Status: StatusAmber, Tag: TagStress, AtLeast: ptr(ic.HYOAS.AmberHighBp)},
+				}},
+				{Measure: MeasureChange, Obs: ic.HYOAS.MomentumWindowObs, Levels: []LevelDef{
+					{Status: StatusAmber, Tag: TagStress, Above: ptr(ic.HYOAS.MomentumBp)},
+				}},
+			},
+			Display: DisplayDef{Unit: "bp"},
+		},
+		{
+			// 倒挂为红；STEEPENING 标记倒挂后快速复陡这一历史上最危险的窗口（设计 §3.1 注 2），只打标不改色。
+			Name: IndT10Y2Y, Source: SourceDef{FRED: "T10Y2Y", Scale: 100}, Layer: "leading",
+			PercentileTrack: ic.T10Y2Y.PercentileTrack,
+			Rules: []RuleDef{
+				{Levels: []LevelDef{{Status: StatusRed, Below: ptr(0)}, {Status: StatusAmber, AtMost: ptr(ic.T10Y2Y.AmberBp)}}},
+				{Measure: MeasureRebound, Obs: ic.T10Y2Y.SteepeningLookbackObs, Levels: []LevelDef{
+					{Tag: TagSteepening, Above: ptr(ic.T10Y2Y.SteepeningBp)},
+				}},
+			},
+			Display: DisplayDef{Unit: "bp", Signed: true},
+		},
+		{
+			Name: IndNFCI, Source: SourceDef{FRED: "NFCI"}, Layer: "leading", Frequency: FrequencyWeekly,
+			PercentileTrack: ic.NFCI.PercentileTrack,
+			Rules: []RuleDef{
+				{Levels: []LevelDef{{Status: StatusRed, Above: ptr(ic.NFCI.RedAbove)}, {Status: StatusAmber, AtLeast: ptr(ic.NFCI.GreenBelow)}}},
+			},
+			Display: DisplayDef{Decimals: 2, Signed: true},
+		},
+		{
+			// JPY 急升值 = USDJPY 下跌，故周环比阈值为负、≤ 比较（carry trade 急平仓方向）；
+			// CROWDED = USDJPY 处 52 周高分位（日元极端弱势 = 空头拥挤）。
+			Name: IndUSDJPY, Source: SourceDef{Yahoo: "JPY=X"}, Layer: "corroborating",
+			PercentileTrack: ic.USDJPY.PercentileTrack,
+			Rules: []RuleDef{
+				{Measure: MeasureWow, Levels: []LevelDef{
+					{Status: StatusRed, AtMost: ptr(ic.USDJPY.RedWowPct)},
+					{Status: StatusAmber, AtMost: ptr(ic.USDJPY.AmberWowPct)},
+				}},
+				{Measure: MeasurePercentile, WindowYears: 1, Levels: []LevelDef{
+					{Status: StatusAmber, Tag: TagCrowded, AtLeast: ptr(ic.USDJPY.Crowded52wPct)},
+				}},
+			},
+			Display: DisplayDef{Decimals: 1, HidePct5y: true},
+		},
+	}
+}
+
+// IndicatorDefs returns every indicator the monitor ingests: the built-ins
+// with custom_indicators of the same name in their place, then the other
+// custom indicators in declaration order.
+func (c *Config) IndicatorDefs() []IndicatorDef {
+	custom := make(map[string]IndicatorDef, len(c.Custom))
+	for _, d := range c.Custom {
+		custom[d.Name] = d
+	}
+	defs := c.builtinDefs()
+	builtin := make(map[string]bool, len(defs))
+	for i, d := range defs {
+		builtin[d.Name] = true
+		if cd, ok := custom[d.Name]; ok {
+			defs[i] = cd
+		}
+	}
+	for _, d := range c.Custom {
+		if !builtin[d.Name] {
+			defs = append(defs, d)
+		}
+	}
+	return defs
+}
+
+// evaluatedDefs are the IndicatorDefs that get a status each day, in
+// evaluation and display order.
+func (c *Config) evaluatedDefs() []IndicatorDef {
+	var out []IndicatorDef
+	for _, d := range c.IndicatorDefs() {
+		if !d.InputOnly {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// IndicatorNames returns the evaluated indicators in display order: the
+// built-ins in AllIndicators order, then the added custom ones.
+func (c *Config) IndicatorNames() []string {
+	defs := c.evaluatedDefs()
+	out := make([]string, len(defs))
+	for i, d := range defs {
+		out[i] = d.Name
+	}
+	return out
+}
+
+// indicatorDef looks up ind; an unknown name yields a bare def (default
+// display, no layer) and ok=false.
+func (c *Config) indicatorDef(ind string) (IndicatorDef, bool) {
+	for _, d := range c.IndicatorDefs() {
+		if d.Name == ind {
+			return d, true
+		}
+	}
+	return IndicatorDef{Name: ind}, false
+}
+
+// isCustom reports whether ind is declared in custom_indicators (including a
+// replaced built-in).
+func (c *Config) isCustom(ind string) bool {
+	return slices.ContainsFunc(c.Custom, func(d IndicatorDef) bool { return d.Name == ind })
+}
+
+// maxLagDays 该指标的 STALE 时效阈值（周频用周阈值）。
+func (c *Config) maxLagDays(ind string) int {
+	if d, _ := c.indicatorDef(ind); d.Frequency == FrequencyWeekly {
+		return c.Freshness.WeeklyMaxLagDays
+	}
+	return c.Freshness.DailyMaxLagDays
+}
+
+// op returns the level's comparison and threshold; ok=false when no
+// direction is set.
+func (l LevelDef) op() (string, float64, bool) {
+	switch {
+	case l.Above != nil:
+		return "above", *l.Above, true
+	case l.AtLeast != nil:
+		return "at_least", *l.AtLeast, true
+	case l.Below != nil:
+		return "below", *l.Below, true
+	case l.AtMost != nil:
+		return "at_most", *l.AtMost, true
+	}
+	return "", 0, false
+}
+
+func (l LevelDef) holds(v float64) bool {
+	op, th, _ := l.op()
+	switch op {
+	case "above":
+		return v > th
+	case "at_least":
+		return v >= th
+	case "below":
+		return v < th
+	case "at_most":
+		return v <= th
+	}
+	return false
+}
+
+// falling reports whether the level fires on low values.
+func (l LevelDef) falling() bool {
+	op, _, _ := l.op()
+	return op == "below" || op == "at_most"
+}
+
+// worstIsMin "最差"方向与红灯方向一致：首个 RED 档位向下比较（t10y2y 倒挂、
+// usdjpy 急跌）的指标取期间最小值，其余取最大值（回放设计 §4）。
+func (d IndicatorDef) worstIsMin() bool {
+	for _, r := range d.Rules {
+		for _, l := range r.Levels {
+			if l.Status == StatusRed {
+				return l.falling()
+			}
+		}
+	}
+	return false
+}
+
+// wowDropHit reports whether a falling week-over-week level with a color
+// matches wow — the "周跌" fragment of notifications (usdjpy).
+func (d IndicatorDef) wowDropHit(wow float64) bool {
+	for _, r := range d.Rules {
+		if r.Measure != MeasureWow {
+			continue
+		}
+		for _, l := range r.Levels {
+			if isColor(l.Status) && l.falling() && l.holds(wow) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// channel 数据通道名（运维速报用）；expr 指标取其输入的通道。
+func (c *Config) channel(ind string) string {
+	d, _ := c.indicatorDef(ind)
+	switch {
+	case d.Source.Yahoo != "":
+		return "Yahoo"
+	case d.Source.Expr != "":
+		f, err := parseFormula(d.Source.Expr)
+		if err != nil {
+			return "FRED"
+		}
+		var chans []string
+		for _, in := range f.refs {
+			if ch := c.channel(in); !slices.Contains(chans, ch) {
+				chans = append(chans, ch)
+			}
+		}
+		if len(chans) == 1 {
+			return chans[0]
+		}
+		return "FRED/Yahoo"
+	}
+	return "FRED"
+}
+
+var indicatorNameRe = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// validateCustom checks custom_indicators against the generic rule grammar;
+// expressions may only reference FRED- or Yahoo-sourced indicators.
+func (c *Config) validateCustom() error {
+	seen := map[string]bool{}
+	defs := c.IndicatorDefs()
+	for _, d := range c.Custom {
+		if !indicatorNameRe.MatchString(d.Name) {
+			return fmt.Errorf("custom_indicators: invalid name %q (lowercase letters, digits, _)", d.Name)
+		}
+		if seen[d.Name] {
+			return fmt.Errorf("custom_indicators: duplicate %s", d.Name)
+		}
+		seen[d.Name] = true
+		if err := d.validate(defs); err != nil {
+			return fmt.Errorf("custom_indicators %s: %w", d.Name, err)
+		}
+	}
+	return nil
+}
+
+func (d IndicatorDef) validate(defs []IndicatorDef) error {
+	s := d.Source
+	n := 0
+	for _, set := range []bool{s.FRED != "", s.Yahoo != "", s.Expr != ""} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return fmt.Errorf("source needs exactly one of fred, yahoo, expr")
+	}
+	if s.Expr != "" {
+		f, err := parseFormula(s.Expr)
+		if err != nil {
+			return fmt.Errorf("source.expr: %w", err)
+		}
+		for _, ref := range f.refs {
+			i := slices.IndexFunc(defs, func(x IndicatorDef) bool { return x.Name == ref })
+			if i < 0 {
+				return fmt.Errorf("source.expr: unknown indicator %q", ref)
+			}
+			if defs[i].Source.Expr != "" {
+				return fmt.Errorf("source.expr: %s is itself derived", ref)
+			}
+		}
+	}
+	if d.Frequency != "" && d.Frequency != FrequencyDaily && d.Frequency != FrequencyWeekly {
+		return fmt.Errorf("frequency must be daily or weekly")
+	}
+	if d.InputOnly {
+		if len(d.Rules) > 0 || d.PercentileTrack {
+			return fmt.Errorf("input_only indicators have no rules")
+		}
+		return nil
+	}
+	if !slices.ContainsFunc(layers, func(l struct{ key, name string }) bool { return l.key == d.Layer }) {
+		return fmt.Errorf("layer must be one of credit, liquidity, sentiment, leading, corroborating")
+	}
+	if d.Display.Decimals < 0 || d.Display.Decimals > 6 {
+		return fmt.Errorf("display.decimals must be 0–6")
+	}
+	for i, r := range d.Rules {
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("rules[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (r RuleDef) validate() error {
+	switch r.Measure {
+	case "", MeasureValue, MeasureWow:
+	case MeasureChange, MeasureRebound:
+		if r.Obs < 1 {
+			return fmt.Errorf("%s needs obs >= 1", r.Measure)
+		}
+	case MeasurePercentile:
+		if r.WindowYears < 1 {
+			return fmt.Errorf("percentile needs window_years >= 1")
+		}
+	default:
+		return fmt.Errorf("unknown measure %q", r.Measure)
+	}
+	if len(r.Levels) == 0 {
+		return fmt.Errorf("no levels")
+	}
+	for i, l := range r.Levels {
+		dirs := 0
+		for _, p := range []*float64{l.Above, l.AtLeast, l.Below, l.AtMost} {
+			if p != nil {
+				dirs++
+			}
+		}
+		switch {
+		case dirs != 1:
+			return fmt.Errorf("levels[%d] needs exactly one of above, at_least, below, at_most", i)
+		case l.Status != "" && l.Status != StatusAmber && l.Status != StatusRed:
+			return fmt.Errorf("levels[%d] status must be AMBER or RED", i)
+		case l.Status == "" && l.Tag == "":
+			return fmt.Errorf("levels[%d] needs a status or a tag", i)
+		case l.Days < 0 || (l.Days > 0 && r.Measure != "" && r.Measure != MeasureValue):
+			return fmt.Errorf("levels[%d] days applies to the value measure only", i)
+		}
+	}
+	return nil
+}
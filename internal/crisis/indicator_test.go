@@ -0,0 +1,267 @@
+package crisis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stressSeries 三年逐工作日的合成 7 指标序列（固定种子，可复现），把每条规则
+// 路径都走一遍：2019 倒挂与 9 月回购冲击、2020-03 情绪双红与信用动量、季末
+// SOFR 尖峰、2021-09 信用+资金面共振（BREWING）、2021 日元急升/52 周拥挤与 HY 自满；move 晚起（NO_DATA）且 2021-05
+// 断更两周（STALE），nfci 仅逢周五发布（周频时效）。
+func stressSeries() memSeries {
+	rng := rand.New(rand.NewPCG(7, 11))
+	noise := func(scale float64) float64 { return (rng.Float64()*2 - 1) * scale }
+	round := func(v float64) float64 { return math.Round(v*100) / 100 }
+	in := func(d, from, to string) bool { return d >= from && d <= to }
+
+	m := memSeries{}
+	add := func(ind, d string, v float64) {
+		m[ind] = append(m[ind], Observation{Date: d, Indicator: ind, Value: round(v)})
+	}
+	vix, move, hy, curve, jpy := 16.0, 75.0, 420.0, 20.0, 110.0
+	for t := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC); t.Year() < 2022; t = t.AddDate(0, 0, 1) {
+		if isWeekend(t) {
+			continue
+		}
+		d := t.Format(dateLayout)
+
+		vixTarget, moveTarget := 16.0, 75.0
+		switch {
+		case in(d, "2020-02-24", "2020-04-30"):
+			vixTarget, moveTarget = 48, 135
+		case in(d, "2019-08-05", "2019-08-16"):
+			vixTarget, moveTarget = 26, 95
+		case in(d, "2021-01-25", "2021-02-05"):
+			vixTarget, moveTarget = 31, 104
+		}
+		vix += (vixTarget-vix)*0.35 + noise(1.2)
+		move += (moveTarget-move)*0.3 + noise(3)
+		add(IndVIX, d, vix)
+		if d >= "2019-03-01" && !in(d, "2021-05-03", "2021-05-14") {
+			add(IndMOVE, d, move)
+		}
+
+		spread := 2 + noise(2)
+		switch {
+		case in(d, "2019-09-16", "2019-09-24"):
+			spread = 40 + noise(15)
+		case in(d, "2020-03-09", "2020-03-13"):
+			spread = 14 + noise(3)
+		case in(d, "2021-09-20", "2021-10-15"):
+			spread = 32 + noise(4)
+		case InQuarterEndWindow(d):
+			spread = 18 + noise(10)
+		}
+		add(IndSOFREFFR, d, spread)
+
+		hyTarget := 420.0
+		switch {
+		case in(d, "2020-02-24", "2020-05-15"):
+			hyTarget = 820
+		case in(d, "2020-05-18", "2020-09-30"):
+			hyTarget = 520
+		case in(d, "2021-09-01", "2021-10-22"):
+			hyTarget = 720
+		case d >= "2021-03-01":
+			hyTarget = 320
+		}
+		hy += (hyTarget-hy)*0.08 + noise(6)
+		add(IndHYOAS, d, hy)
+
+		curveTarget := 60.0
+		switch {
+		case d < "2019-10-01":
+			curveTarget = -8
+		case d < "2020-03-01":
+			curveTarget = 15
+		}
+		curve += (curveTarget-curve)*0.05 + noise(2)
+		add(IndT10Y2Y, d, curve)
+
+		if t.Weekday() == time.Friday {
+			nfci := -0.5 + noise(0.05)
+			if in(d, "2020-03-06", "2020-05-29") {
+				nfci = 0.15 + noise(0.1)
+			} else if in(d, "2020-06-05", "2020-07-31") {
+				nfci = -0.2 + noise(0.05)
+			}
+			add(IndNFCI, d, nfci)
+		}
+
+		jpyDrift := 0.0
+		switch {
+		case in(d, "2019-08-01", "2019-08-07"), in(d, "2020-03-02", "2020-03-06"):
+			jpyDrift = -0.9
+		case d >= "2021-06-01":
+			jpyDrift = 0.06
+		}
+		jpy += jpyDrift + noise(0.25)
+		add(IndUSDJPY, d, jpy)
+	}
+	return m
+}
+
+// replayDigest 把回放快照（去掉 eval_at 时间戳）逐行渲染；等价性比较与黄金摘要共用。
+func replayDigest(t *testing.T, cfg *Config, sr SeriesReader) (string, string) {
+	t.Helper()
+	days, err := ReplayRange(cfg, sr, "2019-01-01", "2021-12-31")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	for _, day := range days {
+		fmt.Fprintf(&b, "%s days=%d\n", day.Date, day.StateDays)
+		for _, e := range day.Res.Evaluations {
+			fmt.Fprintf(&b, "  %s %s %s %s %.6f %.6f %s %s\n",
+				e.TS, e.Indicator, e.Status, e.Tag, e.Value, e.Pct5y, e.SystemState, e.Detail)
+		}
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return b.String(), hex.EncodeToString(sum[:])
+}
+
+// stressGoldenDigest 内置 7 指标在 stressSeries 上三年回放的摘要，锁定通用规则
+// 引擎与改造前逐指标硬编码实现的逐日输出一致；规则语义若有意改动须同步更新。
+const stressGoldenDigest = "488ad70bb0ac693625fc09eea6f16c38e9da20b3538e1e561b367dc24d758ae2"
+
+func TestStressSeriesGolden(t *testing.T) {
+	out, digest := replayDigest(t, testConfig(), stressSeries())
+	assert.Equal(t, stressGoldenDigest, digest)
+	// 夹具须覆盖四种系统状态与 STALE/NO_DATA，否则摘要锁不住相应分支
+	for _, want := range []string{"NORMAL", "WATCH", "BREWING", "CRISIS", "STALE", "NO_DATA", "SUPPRESSED_SEASONAL", "STEEPENING", "CROWDED", "COMPLACENCY"} {
+		assert.Contains(t, out, want)
+	}
+}
+
+// 全部以 custom_indicators 声明的 7 指标（testdata，无 indicators 段）须与内置
+// 定义逐日输出完全一致。
+func TestGenericIndicatorsMatchBuiltins(t *testing.T) {
+	cfg, err := LoadConfig(filepath.Join("testdata", "generic-indicators.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, AllIndicators, cfg.IndicatorNames())
+
+	sr := stressSeries()
+	want, _ := replayDigest(t, testConfig(), sr)
+	got, digest := replayDigest(t, cfg, sr)
+	assert.Equal(t, want, got)
+	assert.Equal(t, stressGoldenDigest, digest)
+}
+
+func TestIndicatorDefsCustomOrder(t *testing.T) {
+	cfg := testConfig()
+	cfg.Custom = []IndicatorDef{
+		{Name: "ted", Source: SourceDef{FRED: "TEDRATE", Scale: 100}, Layer: "liquidity"},
+		{Name: IndMOVE, Source: SourceDef{Yahoo: "^MOVE"}, Layer: "sentiment"},
+	}
+	want := append(slices.Clone(AllIndicators), "ted")
+	assert.Equal(t, want, cfg.IndicatorNames())
+
+	move, ok := cfg.indicatorDef(IndMOVE)
+	require.True(t, ok)
+	assert.Empty(t, move.Rules) // 同名自定义整体替换内置定义
+	assert.True(t, cfg.isCustom(IndMOVE))
+	assert.False(t, cfg.isCustom(IndVIX))
+
+	_, ok = cfg.indicatorDef("absent")
+	assert.False(t, ok)
+	_, err := EvaluateIndicator(cfg, "absent", "2026-07-10", memSeries{})
+	assert.ErrorContains(t, err, "unknown indicator")
+}
+
+// 自定义指标走同一管线：持续档、change、percentile 各规则与 tag-only 档。
+func TestEvaluateCustomIndicator(t *testing.T) {
+	const d = "2026-07-10"
+	cfg := testConfig()
+	cfg.Custom = []IndicatorDef{{
+		Name: "ted", Source: SourceDef{FRED: "TEDRATE"}, Layer: "liquidity",
+		Rules: []RuleDef{
+			{Levels: []LevelDef{
+				{Status: StatusRed, Above: ptr(100), Days: 40},
+				{Status: StatusAmber, Above: ptr(50), Days: 3},
+			}},
+			{Measure: MeasureChange, Obs: 5, Levels: []LevelDef{{Tag: "SURGE", Above: ptr(30)}}},
+		},
+	}}
+
+	sr := memSeries{"ted": append(seriesEnding(addDays(d, -3), 60, 40, 40), seriesEnding(d, 3, 60, 110)...)}
+	res, err := EvaluateIndicator(cfg, "ted", d, sr)
+	require.NoError(t, err)
+	assert.Equal(t, StatusAmber, res.RawStatus) // 近 3 日 >50；>100 仅当日
+	assert.Equal(t, 3, res.PersistDays)
+	assert.Equal(t, Tag("SURGE"), res.Tag) // 5 观测变化 110−40
+
+	sr = memSeries{"ted": seriesEnding(d, 60, 120, 120)}
+	res, err = EvaluateIndicator(cfg, "ted", d, sr)
+	require.NoError(t, err)
+	assert.Equal(t, StatusRed, res.RawStatus) // days: 40 超过通知回看 30 仍可判
+	assert.Equal(t, 40, res.PersistDays)
+
+	sr = memSeries{"ted": append(seriesEnding(addDays(d, -10), 30, 10, 10), seriesEnding(d, 5, 20, 45)...)}
+	res, err = EvaluateIndicator(cfg, "ted", d, sr)
+	require.NoError(t, err)
+	assert.Equal(t, StatusGreen, res.RawStatus)
+	assert.Equal(t, Tag("SURGE"), res.Tag)
+}
+
+// validateCustom：从合法定义出发每次只破坏一处，断言命中对应错误。
+func TestValidateCustomIndicators(t *testing.T) {
+	valid := func() IndicatorDef {
+		return IndicatorDef{
+			Name: "ted", Source: SourceDef{FRED: "TEDRATE"}, Layer: "liquidity",
+			Rules: []RuleDef{{Levels: []LevelDef{{Status: StatusAmber, Above: ptr(50)}}}},
+		}
+	}
+	cfg := testConfig()
+	cfg.Custom = []IndicatorDef{valid()}
+	require.NoError(t, cfg.validateCustom())
+
+	tests := []struct {
+		name   string
+		mutate func(*IndicatorDef)
+		want   string
+	}{
+		{"bad name", func(d *IndicatorDef) { d.Name = "TED-1" }, "invalid name"},
+		{"no source", func(d *IndicatorDef) { d.Source = SourceDef{} }, "exactly one of fred"},
+		{"two sources", func(d *IndicatorDef) { d.Source.Yahoo = "^TED" }, "exactly one of fred"},
+		{"bad expr", func(d *IndicatorDef) { d.Source = SourceDef{Expr: "sofr -"} }, "source.expr"},
+		{"unknown ref", func(d *IndicatorDef) { d.Source = SourceDef{Expr: "sofr - libor"} }, `unknown indicator "libor"`},
+		{"derived ref", func(d *IndicatorDef) { d.Source = SourceDef{Expr: "sofr_effr * 2"} }, "itself derived"},
+		{"frequency", func(d *IndicatorDef) { d.Frequency = "monthly" }, "frequency"},
+		{"input with rules", func(d *IndicatorDef) { d.InputOnly = true }, "input_only"},
+		{"layer", func(d *IndicatorDef) { d.Layer = "macro" }, "layer"},
+		{"decimals", func(d *IndicatorDef) { d.Display.Decimals = 7 }, "decimals"},
+		{"measure", func(d *IndicatorDef) { d.Rules[0].Measure = "zscore" }, "unknown measure"},
+		{"change obs", func(d *IndicatorDef) { d.Rules[0].Measure = MeasureChange }, "obs >= 1"},
+		{"percentile window", func(d *IndicatorDef) { d.Rules[0].Measure = MeasurePercentile }, "window_years"},
+		{"no levels", func(d *IndicatorDef) { d.Rules[0].Levels = nil }, "no levels"},
+		{"two directions", func(d *IndicatorDef) { d.Rules[0].Levels[0].Below = ptr(10) }, "exactly one of above"},
+		{"status", func(d *IndicatorDef) { d.Rules[0].Levels[0].Status = StatusStale }, "AMBER or RED"},
+		{"empty level", func(d *IndicatorDef) { d.Rules[0].Levels[0].Status = "" }, "status or a tag"},
+		{"days on wow", func(d *IndicatorDef) {
+			d.Rules[0].Measure, d.Rules[0].Levels[0].Days = MeasureWow, 3
+		}, "days applies"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := valid()
+			tt.mutate(&d)
+			cfg.Custom = []IndicatorDef{d}
+			assert.ErrorContains(t, cfg.validateCustom(), tt.want)
+		})
+	}
+
+	cfg.Custom = []IndicatorDef{valid(), valid()}
+	assert.ErrorContains(t, cfg.validateCustom(), "duplicate ted")
+}
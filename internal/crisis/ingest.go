@@ -3,6 +3,7 @@ package crisis
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/newthinker/atlas/internal/collector/fred"
@@ -31,77 +32,112 @@ type Ingestor struct {
 	fred  FREDFetcher
 	yahoo HistoryFetcher
 	store *Store
+	defs  []IndicatorDef
 	now   func() time.Time
 }
 
-func NewIngestor(f FREDFetcher, y HistoryFetcher, s *Store) *Ingestor {
-	return &Ingestor{fred: f, yahoo: y, store: s, now: time.Now}
-}
+// IngestOption configures an Ingestor.
+type IngestOption func(*Ingestor)
 
-// fredDirect are the FRED series stored as-is (scale converts percent→bp per
-// the canonical-unit table in the implementation plan).
-var fredDirect = []struct {
-	indicator string
-	series    string
-	scale     float64
-}{
-	{IndVIX, "VIXCLS", 1},
-	{IndHYOAS, "BAMLH0A0HYM2", 100},
-	{IndT10Y2Y, "T10Y2Y", 100},
-	{IndNFCI, "NFCI", 1},
+// WithIndicators sets the indicators to ingest, normally Config.IndicatorDefs
+// so custom_indicators are fetched too. The default is the built-ins.
+func WithIndicators(defs []IndicatorDef) IngestOption {
+	return func(ig *Ingestor) { ig.defs = defs }
 }
 
-var yahooSymbols = map[string]string{IndMOVE: "^MOVE", IndUSDJPY: "JPY=X"}
+func NewIngestor(f FREDFetcher, y HistoryFetcher, s *Store, opts ...IngestOption) *Ingestor {
+	ig := &Ingestor{fred: f, yahoo: y, store: s, defs: (&Config{}).builtinDefs(), now: time.Now}
+	for _, opt := range opts {
+		opt(ig)
+	}
+	return ig
+}
 
-// IngestAll fetches every indicator for [from, to]: FRED errors abort (the
-// daily eval retries next wakeup), yahoo errors are collected in the report.
+// IngestAll fetches every indicator for [from, to]: FRED series first (errors
+// abort — the daily eval retries next wakeup), then Yahoo symbols (errors are
+// collected in the report), then the expr indicators, derived from the rows
+// just fetched. Input-only legs (sofr/effr) are stored too.
 func (ig *Ingestor) IngestAll(ctx context.Context, from, to string) (*IngestReport, error) {
 	rep := &IngestReport{Counts: map[string]int{}, YahooErrs: map[string]error{}}
 	stamp := NowStamp(ig.now())
+	fetched := map[string][]Observation{}
 
-	for _, fs := range fredDirect {
-		n, err := ig.ingestFredSeries(ctx, fs.series, fs.indicator, fs.scale, from, to, stamp)
+	for _, d := range ig.defs {
+		if d.Source.FRED == "" {
+			continue
+		}
+		rows, err := ig.fetchFred(ctx, d, from, to, stamp)
 		if err != nil {
 			return nil, err
 		}
-		rep.Counts[fs.indicator] = n
+		if rep.Counts[d.Name], err = ig.upsert(ctx, rows); err != nil {
+			return nil, err
+		}
+		fetched[d.Name] = rows
 	}
 
-	n, err := ig.ingestSpread(ctx, from, to, stamp)
-	if err != nil {
-		return nil, err
+	for _, d := range ig.defs {
+		if d.Source.Yahoo == "" {
+			continue
+		}
+		rows, err := ig.fetchYahoo(d.Name, d.Source.Yahoo, from, to, stamp)
+		if err != nil {
+			rep.YahooErrs[d.Name] = err
+			continue
+		}
+		if rep.Counts[d.Name], err = ig.upsert(ctx, rows); err != nil {
+			return nil, err
+		}
+		fetched[d.Name] = rows
 	}
-	rep.Counts[IndSOFREFFR] = n
 
-	for ind, sym := range yahooSymbols {
-		n, err := ig.ingestYahoo(ctx, ind, sym, from, to, stamp)
-		if err != nil {
-			rep.YahooErrs[ind] = err
+	for _, d := range ig.defs {
+		if d.Source.Expr == "" {
 			continue
 		}
-		rep.Counts[ind] = n
+		rows, err := derive(d, fetched, stamp)
+		if err != nil {
+			return nil, err
+		}
+		if rep.Counts[d.Name], err = ig.upsert(ctx, rows); err != nil {
+			return nil, err
+		}
 	}
 	return rep, nil
 }
 
 // IngestNFCI refreshes only the weekly NFCI series (Wednesday plist, design §4.3).
 func (ig *Ingestor) IngestNFCI(ctx context.Context, from, to string) (int, error) {
-	return ig.ingestFredSeries(ctx, "NFCI", IndNFCI, 1, from, to, NowStamp(ig.now()))
+	i := slices.IndexFunc(ig.defs, func(d IndicatorDef) bool { return d.Name == IndNFCI })
+	if i < 0 || ig.defs[i].Source.FRED == "" {
+		return 0, fmt.Errorf("nfci is not a FRED series")
+	}
+	rows, err := ig.fetchFred(ctx, ig.defs[i], from, to, NowStamp(ig.now()))
+	if err != nil {
+		return 0, err
+	}
+	return ig.upsert(ctx, rows)
 }
 
-func (ig *Ingestor) ingestFredSeries(ctx context.Context, seriesID, indicator string, scale float64, from, to, stamp string) (int, error) {
-	obs, err := ig.fred.FetchSeries(ctx, seriesID, from, to)
+// fetchFred reads one FRED series, scaled to the indicator's canonical unit
+// (percent→bp per the unit table in the implementation plan).
+func (ig *Ingestor) fetchFred(ctx context.Context, d IndicatorDef, from, to, stamp string) ([]Observation, error) {
+	obs, err := ig.fred.FetchSeries(ctx, d.Source.FRED, from, to)
 	if err != nil {
-		return 0, fmt.Errorf("fetching %s: %w", seriesID, err)
+		return nil, fmt.Errorf("fetching %s: %w", d.Source.FRED, err)
+	}
+	scale := d.Source.Scale
+	if scale == 0 {
+		scale = 1
 	}
 	rows := make([]Observation, 0, len(obs))
 	for _, o := range obs {
 		rows = append(rows, Observation{
-			Date: o.Date, Indicator: indicator, Value: o.Value * scale,
+			Date: o.Date, Indicator: d.Name, Value: o.Value * scale,
 			Source: "fred", FetchedAt: stamp,
 		})
 	}
-	return ig.upsert(ctx, rows)
+	return rows, nil
 }
 
 // upsert stores rows and returns the row count, the shared tail of every
@@ -113,48 +149,53 @@ func (ig *Ingestor) upsert(ctx context.Context, rows []Observation) (int, error)
 	return len(rows), nil
 }
 
-// ingestSpread joins SOFR and EFFR by date and stores the spread in bp
-// (design §2.2; days missing either leg are skipped).
-func (ig *Ingestor) ingestSpread(ctx context.Context, from, to, stamp string) (int, error) {
-	sofr, err := ig.fred.FetchSeries(ctx, "SOFR", from, to)
+// derive evaluates an expr indicator on each date where every referenced
+// indicator was fetched (design §2.2: sofr_effr skips days missing either
+// leg); a division by zero also skips the day.
+func derive(d IndicatorDef, fetched map[string][]Observation, stamp string) ([]Observation, error) {
+	f, err := parseFormula(d.Source.Expr)
 	if err != nil {
-		return 0, fmt.Errorf("fetching SOFR: %w", err)
+		return nil, fmt.Errorf("%s expr: %w", d.Name, err)
 	}
-	effr, err := ig.fred.FetchSeries(ctx, "EFFR", from, to)
-	if err != nil {
-		return 0, fmt.Errorf("fetching EFFR: %w", err)
+	if len(f.refs) == 0 {
+		return nil, nil
 	}
-	effrByDate := make(map[string]float64, len(effr))
-	for _, o := range effr {
-		effrByDate[o.Date] = o.Value
+	byDate := map[string]map[string]float64{}
+	for _, ref := range f.refs {
+		for _, o := range fetched[ref] {
+			if byDate[o.Date] == nil {
+				byDate[o.Date] = map[string]float64{}
+			}
+			byDate[o.Date][ref] = o.Value
+		}
 	}
-	rows := make([]Observation, 0, len(sofr))
-	for _, o := range sofr {
-		e, ok := effrByDate[o.Date]
+	var rows []Observation
+	for _, o := range fetched[f.refs[0]] {
+		v, ok := f.eval(byDate[o.Date])
 		if !ok {
 			continue
 		}
 		rows = append(rows, Observation{
-			Date: o.Date, Indicator: IndSOFREFFR, Value: SpreadBp(o.Value, e),
+			Date: o.Date, Indicator: d.Name, Value: v,
 			Source: "derived", FetchedAt: stamp,
 		})
 	}
-	return ig.upsert(ctx, rows)
+	return rows, nil
 }
 
-func (ig *Ingestor) ingestYahoo(ctx context.Context, indicator, symbol, from, to, stamp string) (int, error) {
+func (ig *Ingestor) fetchYahoo(indicator, symbol, from, to, stamp string) ([]Observation, error) {
 	start, err := time.Parse(dateLayout, from)
 	if err != nil {
-		return 0, fmt.Errorf("parsing from %q: %w", from, err)
+		return nil, fmt.Errorf("parsing from %q: %w", from, err)
 	}
 	end, err := time.Parse(dateLayout, to)
 	if err != nil {
-		return 0, fmt.Errorf("parsing to %q: %w", to, err)
+		return nil, fmt.Errorf("parsing to %q: %w", to, err)
 	}
 	// end+1d：yahoo chart 区间对当日收盘的包含性不稳定，多取一天由 upsert 幂等兜底
 	bars, err := ig.yahoo.FetchHistory(symbol, start, end.AddDate(0, 0, 1), "1d")
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	rows := make([]Observation, 0, len(bars))
 	for _, b := range bars {
@@ -163,5 +204,5 @@ func (ig *Ingestor) ingestYahoo(ctx context.Context, indicator, symbol, from, to
 			Source: "yahoo", FetchedAt: stamp,
 		})
 	}
-	return ig.upsert(ctx, rows)
+	return rows, nil
 }
@@ -81,7 +81,8 @@ func TestIngestAllScalesJoinsAndDegrades(t *testing.T) {
 	assert.Equal(t, "derived", spread.Source)
 	missing, err := st.Observation(ctx, IndSOFREFFR, "2026-07-02")
 	require.NoError(t, err)
-	assert.Nil(t, missing) // 缺腿日跳过
+	assert.Nil(t, missing)                 // 缺腿日跳过
+	assert.Equal(t, 2, rep.Counts["sofr"]) // 输入腿照常入库
 
 	assert.Len(t, rep.YahooErrs, 2) // move 与 usdjpy 都失败
 	assert.Equal(t, 1, rep.Counts[IndVIX])
@@ -126,8 +127,8 @@ func TestIngestAllSurfacesFREDFailure(t *testing.T) {
 		name string
 		drop string // 从全序列 map 中删除的 FRED id
 	}{
-		{"direct series failure", "VIXCLS"}, // fredDirect 首个 → ingestFredSeries 失败
-		{"spread leg failure", "SOFR"},      // ingestSpread 的 SOFR 腿失败
+		{"direct series failure", "VIXCLS"}, // 首个 FRED 指标 → fetchFred 失败
+		{"spread leg failure", "SOFR"},      // sofr_effr 的 SOFR 输入腿失败
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -141,3 +142,34 @@ func TestIngestAllSurfacesFREDFailure(t *testing.T) {
 		})
 	}
 }
+
+// custom_indicators 经 WithIndicators 接入：FRED 换算 + expr 派生（缺值/除零日跳过）。
+func TestIngestAllCustomIndicators(t *testing.T) {
+	st := newTestStore(t)
+	ctx := context.Background()
+	ff := fakeFRED{
+		"DGS10":  {{Date: "2026-07-01", Value: 4.2}, {Date: "2026-07-02", Value: 4.3}},
+		"DGS3MO": {{Date: "2026-07-01", Value: 4.5}, {Date: "2026-07-02", Value: 4.3}},
+	}
+	defs := []IndicatorDef{
+		{Name: "dgs10", Source: SourceDef{FRED: "DGS10", Scale: 100}, InputOnly: true},
+		{Name: "dgs3mo", Source: SourceDef{FRED: "DGS3MO", Scale: 100}, InputOnly: true},
+		{Name: "ratio", Source: SourceDef{Expr: "dgs3mo / (dgs10 - dgs3mo)"}},
+	}
+	ig := NewIngestor(ff, fakeYahoo{}, st, WithIndicators(defs))
+	rep, err := ig.IngestAll(ctx, "2026-07-01", "2026-07-02")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"dgs10": 2, "dgs3mo": 2, "ratio": 1}, rep.Counts)
+
+	r, err := st.Observation(ctx, "ratio", "2026-07-01")
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	assert.InDelta(t, -15.0, r.Value, 1e-9)
+	assert.Equal(t, "derived", r.Source)
+	zero, err := st.Observation(ctx, "ratio", "2026-07-02")
+	require.NoError(t, err)
+	assert.Nil(t, zero) // 除数为 0 → 该日不入库
+
+	_, err = ig.IngestNFCI(ctx, "2026-07-01", "2026-07-02")
+	assert.Error(t, err) // 未声明 nfci
+}
@@ -0,0 +1,358 @@
+package crisis
+
+import (
+	"fmt"
+	"sort"
+)
+
+// LeadTimeOptions configures EvaluateLeadTime.
+type LeadTimeOptions struct {
+	// Threshold is the peak-to-trough decline, as a fraction, that makes a
+	// benchmark drawdown an episode (0.10 = 10%).
+	Threshold float64
+	// WindowDays is how many calendar days before an episode's breach a
+	// warning must still be in force to count as a hit; it is also how long
+	// after an alarm ends a breach may come before the alarm counts as false.
+	WindowDays int
+}
+
+// DrawdownEpisode is one benchmark decline of at least the threshold: from
+// the running peak, through the first close at the threshold (Breach), to the
+// lowest close and the first close back at the peak (Recovery; empty while
+// still under water).
+type DrawdownEpisode struct {
+	Peak        string  `json:"peak"`
+	PeakValue   float64 `json:"peak_value"`
+	Breach      string  `json:"breach"`
+	Trough      string  `json:"trough"`
+	TroughValue float64 `json:"trough_value"`
+	Depth       float64 `json:"depth"`
+	Recovery    string  `json:"recovery,omitempty"`
+	// Warnings holds, per alarm level, the alarm that covered the episode.
+	Warnings []Warning `json:"warnings"`
+}
+
+// Warning is the alarm of one level that covered an episode. LeadDays counts
+// calendar days from the alarm's onset to the breach; negative means the
+// alarm came late (after the breach, by the trough).
+type Warning struct {
+	Level    SystemState `json:"level"`
+	Onset    string      `json:"onset"`
+	LeadDays int         `json:"lead_days"`
+}
+
+// LevelStats scores one alarm level: an alarm is a run of days at that state
+// or worse, so WATCH counts every run of WATCH, BREWING or CRISIS.
+type LevelStats struct {
+	Level SystemState `json:"level"`
+	// Episodes 中 Hits 提前预警、Late 破阈后才预警、Missed 未预警。
+	Hits    int     `json:"hits"`
+	Late    int     `json:"late"`
+	Missed  int     `json:"missed"`
+	HitRate float64 `json:"hit_rate"`
+	// AvgLeadDays 只对命中取平均；无命中为 0。
+	AvgLeadDays float64 `json:"avg_lead_days"`
+	// Alarms = 被回撤确认的 + FalseAlarms + Pending；Pending 为窗口尚未走完的预警。
+	Alarms      int     `json:"alarms"`
+	FalseAlarms int     `json:"false_alarms"`
+	Pending     int     `json:"pending"`
+	Precision   float64 `json:"precision"`
+}
+
+// StateStats is how long the system spent in one state and how the
+// benchmark did meanwhile.
+type StateStats struct {
+	State        SystemState `json:"state"`
+	Days         int         `json:"days"`
+	Share        float64     `json:"share"`
+	Spells       int         `json:"spells"`
+	AvgSpellDays float64     `json:"avg_spell_days"`
+	// BenchReturn compounds the benchmark's close-to-close returns over the
+	// intervals that started in this state.
+	BenchReturn float64 `json:"bench_return"`
+}
+
+// LeadTimeReport is EvaluateLeadTime's result. Benchmark is left for the
+// caller to name.
+type LeadTimeReport struct {
+	From       string            `json:"from"`
+	To         string            `json:"to"`
+	Benchmark  string            `json:"benchmark"`
+	Threshold  float64           `json:"threshold"`
+	WindowDays int               `json:"window_days"`
+	Episodes   []DrawdownEpisode `json:"episodes"`
+	Levels     []LevelStats      `json:"levels"`
+	States     []StateStats      `json:"states"`
+}
+
+// alarmLevels are the levels scored, mildest first.
+var alarmLevels = []SystemState{StateWatch, StateBrewing, StateCrisis}
+
+// alarmRun 某级别及以上的连续评估日：[Onset, End]。
+type alarmRun struct{ Onset, End string }
+
+// EvaluateLeadTime measures whether the replayed system states precede
+// benchmark drawdowns. prices are daily closes in date order; only episodes
+// that breach inside the replay window are scored, since earlier ones could
+// not have been warned of.
+func EvaluateLeadTime(days []ReplayDay, prices []Point, opt LeadTimeOptions) (*LeadTimeReport, error) {
+	if len(days) == 0 {
+		return nil, fmt.Errorf("no replay days to evaluate")
+	}
+	if len(prices) < 2 {
+		return nil, fmt.Errorf("benchmark has %d closes, need at least 2", len(prices))
+	}
+	if opt.Threshold <= 0 || opt.Threshold >= 1 {
+		return nil, fmt.Errorf("drawdown threshold must be in (0, 1), got %g", opt.Threshold)
+	}
+	if opt.WindowDays <= 0 {
+		return nil, fmt.Errorf("lead window must be positive, got %d days", opt.WindowDays)
+	}
+	from, to := days[0].Date, days[len(days)-1].Date
+	rep := &LeadTimeReport{From: from, To: to, Threshold: opt.Threshold, WindowDays: opt.WindowDays,
+		Episodes: []DrawdownEpisode{}}
+	for _, ep := range drawdownEpisodes(prices, opt.Threshold) {
+		if ep.Breach >= from && ep.Breach <= to {
+			rep.Episodes = append(rep.Episodes, ep)
+		}
+	}
+	lastPrice := prices[len(prices)-1].Date
+	for _, level := range alarmLevels {
+		rep.Levels = append(rep.Levels, scoreLevel(rep.Episodes, alarmRuns(days, level), level, opt.WindowDays, lastPrice))
+	}
+	rep.States = stateStats(days, prices)
+	return rep, nil
+}
+
+// drawdownEpisodes 运行峰值法：回撤达阈值开启事件，收盘回到峰值结束。
+func drawdownEpisodes(prices []Point, threshold float64) []DrawdownEpisode {
+	var out []DrawdownEpisode
+	peak := prices[0]
+	var ep *DrawdownEpisode
+	for _, p := range prices[1:] {
+		if p.Value >= peak.Value {
+			if ep != nil {
+				ep.Recovery = p.Date
+				out = append(out, *ep)
+				ep = nil
+			}
+			peak = p
+			continue
+		}
+		dd := 1 - p.Value/peak.Value
+		if ep == nil && dd >= threshold {
+			ep = &DrawdownEpisode{Peak: peak.Date, PeakValue: peak.Value, Breach: p.Date, Warnings: []Warning{}}
+		}
+		if ep != nil && dd > ep.Depth {
+			ep.Trough, ep.TroughValue, ep.Depth = p.Date, p.Value, dd
+		}
+	}
+	if ep != nil {
+		out = append(out, *ep)
+	}
+	return out
+}
+
+// alarmRuns 按级别切分预警段；窗口首日已在该级别及以上时以首日为 onset。
+func alarmRuns(days []ReplayDay, level SystemState) []alarmRun {
+	var runs []alarmRun
+	open := false
+	for _, d := range days {
+		if stateRank(d.Res.State) < stateRank(level) {
+			open = false
+			continue
+		}
+		if !open {
+			runs = append(runs, alarmRun{Onset: d.Date})
+			open = true
+		}
+		runs[len(runs)-1].End = d.Date
+	}
+	return runs
+}
+
+// scoreLevel 命中：预警在 [breach-window, breach] 内处于激活（onset 可更早，
+// 领先天数按 onset 计）；迟到：onset 落在 (breach, trough]。预警的真伪与命中
+// 同口径：[onset, end+window] 内有破阈，或 onset 落在某事件的 [peak, trough] 内。
+func scoreLevel(eps []DrawdownEpisode, runs []alarmRun, level SystemState, window int, lastPrice string) LevelStats {
+	st := LevelStats{Level: level, Alarms: len(runs)}
+	leadSum := 0
+	for i := range eps {
+		ep := &eps[i]
+		start := addDays(ep.Breach, -window)
+		w, found := Warning{Level: level}, false
+		for _, r := range runs {
+			if r.Onset <= ep.Breach && r.End >= start {
+				w.Onset, w.LeadDays, found = r.Onset, daysBetween(r.Onset, ep.Breach), true
+				break
+			}
+			if r.Onset > ep.Breach && r.Onset <= ep.Trough {
+				w.Onset, w.LeadDays, found = r.Onset, -daysBetween(ep.Breach, r.Onset), true
+				break
+			}
+		}
+		switch {
+		case !found:
+			st.Missed++
+		case w.LeadDays < 0:
+			st.Late++
+			ep.Warnings = append(ep.Warnings, w)
+		default:
+			st.Hits++
+			leadSum += w.LeadDays
+			ep.Warnings = append(ep.Warnings, w)
+		}
+	}
+	if len(eps) > 0 {
+		st.HitRate = float64(st.Hits) / float64(len(eps))
+	}
+	if st.Hits > 0 {
+		st.AvgLeadDays = float64(leadSum) / float64(st.Hits)
+	}
+	confirmed := 0
+	for _, r := range runs {
+		deadline := addDays(r.End, window)
+		switch {
+		case alarmConfirmed(eps, r.Onset, deadline):
+			confirmed++
+		case deadline > lastPrice:
+			st.Pending++
+		default:
+			st.FalseAlarms++
+		}
+	}
+	if n := confirmed + st.FalseAlarms; n > 0 {
+		st.Precision = float64(confirmed) / float64(n)
+	}
+	return st
+}
+
+func alarmConfirmed(eps []DrawdownEpisode, onset, deadline string) bool {
+	for _, ep := range eps {
+		if (ep.Breach >= onset && ep.Breach <= deadline) || (onset >= ep.Peak && onset <= ep.Trough) {
+			return true
+		}
+	}
+	return false
+}
+
+// stateStats 每个状态的评估日数、段数与期间基准收益。价格区间 [p(i-1), p(i)]
+// 归属 p(i-1) 当日（或之前最近评估日）的状态；首个评估日之前的区间不计。
+func stateStats(days []ReplayDay, prices []Point) []StateStats {
+	states := []SystemState{StateNormal, StateWatch, StateBrewing, StateCrisis}
+	byState := map[SystemState]*StateStats{}
+	out := make([]StateStats, len(states))
+	for i, s := range states {
+		out[i] = StateStats{State: s, BenchReturn: 1}
+		byState[s] = &out[i]
+	}
+	var prev SystemState
+	for _, d := range days {
+		st := byState[d.Res.State]
+		if st == nil {
+			continue
+		}
+		st.Days++
+		if d.Res.State != prev {
+			st.Spells++
+		}
+		prev = d.Res.State
+	}
+	for i := 1; i < len(prices); i++ {
+		p0, p1 := prices[i-1], prices[i]
+		if p0.Date > days[len(days)-1].Date || p0.Value == 0 {
+			continue
+		}
+		j := sort.Search(len(days), func(k int) bool { return days[k].Date > p0.Date }) - 1
+		if j < 0 {
+			continue
+		}
+		if st := byState[days[j].Res.State]; st != nil {
+			st.BenchReturn *= p1.Value / p0.Value
+		}
+	}
+	for i := range out {
+		out[i].BenchReturn--
+		out[i].Share = float64(out[i].Days) / float64(len(days))
+		if out[i].Spells > 0 {
+			out[i].AvgSpellDays = float64(out[i].Days) / float64(out[i].Spells)
+		}
+	}
+	return out
+}
+
+// ---------- HTML 视图模型 ----------
+
+type leadTimeView struct {
+	Benchmark, Threshold string
+	WindowDays           int
+	LevelNames           []SystemState
+	Levels               []levelRow
+	States               []stateRow
+	Episodes             []episodeRow
+}
+
+type levelRow struct {
+	Level                        SystemState
+	Episodes, Hits, Late, Missed int
+	Alarms, FalseAlarms, Pending int
+	HitRate, AvgLead, Precision  string
+}
+
+type stateRow struct {
+	State                        SystemState
+	Days, Spells                 int
+	Share, AvgSpell, BenchReturn string
+}
+
+type episodeRow struct {
+	Peak, Breach, Trough, Depth, Recovery string
+	Leads                                 []string // 按 LevelNames 序；— = 漏报
+}
+
+func newLeadTimeView(rep *LeadTimeReport) *leadTimeView {
+	pct := func(v float64) string { return fmt.Sprintf("%.0f%%", v*100) }
+	v := &leadTimeView{Benchmark: rep.Benchmark, Threshold: pct(rep.Threshold), WindowDays: rep.WindowDays,
+		LevelNames: alarmLevels}
+	for _, l := range rep.Levels {
+		row := levelRow{Level: l.Level, Episodes: len(rep.Episodes), Hits: l.Hits, Late: l.Late, Missed: l.Missed,
+			Alarms: l.Alarms, FalseAlarms: l.FalseAlarms, Pending: l.Pending,
+			HitRate: "—", AvgLead: "—", Precision: "—"}
+		if len(rep.Episodes) > 0 {
+			row.HitRate = pct(l.HitRate)
+		}
+		if l.Hits > 0 {
+			row.AvgLead = fmt.Sprintf("%.0f", l.AvgLeadDays)
+		}
+		if l.Alarms > l.Pending {
+			row.Precision = pct(l.Precision)
+		}
+		v.Levels = append(v.Levels, row)
+	}
+	for _, s := range rep.States {
+		row := stateRow{State: s.State, Days: s.Days, Spells: s.Spells, Share: pct(s.Share),
+			AvgSpell: "—", BenchReturn: fmt.Sprintf("%+.1f%%", s.BenchReturn*100)}
+		if s.Spells > 0 {
+			row.AvgSpell = fmt.Sprintf("%.1f", s.AvgSpellDays)
+		}
+		v.States = append(v.States, row)
+	}
+	for _, ep := range rep.Episodes {
+		row := episodeRow{Peak: ep.Peak, Breach: ep.Breach, Trough: ep.Trough,
+			Depth: fmt.Sprintf("-%.1f%%", ep.Depth*100), Recovery: ep.Recovery}
+		if row.Recovery == "" {
+			row.Recovery = "未修复"
+		}
+		for _, level := range alarmLevels {
+			cell := "—"
+			for _, w := range ep.Warnings {
+				if w.Level == level {
+					cell = fmt.Sprintf("%d（%s）", w.LeadDays, w.Onset)
+				}
+			}
+			row.Leads = append(row.Leads, cell)
+		}
+		v.Episodes = append(v.Episodes, row)
+	}
+	return v
+}
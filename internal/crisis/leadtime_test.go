@@ -0,0 +1,153 @@
+package crisis
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// leadTimeFixture 60 个连续日历日（2026-01-01 起），状态与收盘逐日给定：
+//
+//	事件 1：峰 01-13(110) → 破阈 01-17(95) → 谷 01-19(90) → 修复 01-23(111)
+//	事件 2：峰 02-11(111) → 破阈 02-12(95) → 谷 02-14(90)，未修复
+//	WATCH 01-11..01-15（事件 1 领先 6 日）；BREWING 01-31..02-04（事件 2 领先 12 日）；
+//	CRISIS 02-13（事件 2 破阈后 1 日，迟到）；WATCH 02-18..02-19（误报）；
+//	WATCH 02-25..03-01（窗口未走完，待定）。
+func leadTimeFixture() ([]ReplayDay, []Point) {
+	state := func(i int) SystemState {
+		switch {
+		case i >= 10 && i <= 14, i >= 48 && i <= 49, i >= 55:
+			return StateWatch
+		case i >= 30 && i <= 34:
+			return StateBrewing
+		case i == 43:
+			return StateCrisis
+		}
+		return StateNormal
+	}
+	price := func(i int) float64 {
+		switch {
+		case i <= 11:
+			return 100
+		case i == 12:
+			return 110
+		case i <= 15:
+			return 105
+		case i == 16:
+			return 95
+		case i == 17:
+			return 92
+		case i == 18:
+			return 90
+		case i <= 21:
+			return 100
+		case i <= 41:
+			return 111
+		case i == 42:
+			return 95
+		case i == 43:
+			return 93
+		case i == 44:
+			return 90
+		}
+		return 92
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var days []ReplayDay
+	var prices []Point
+	prev := StateNormal
+	for i := 0; i < 60; i++ {
+		date := start.AddDate(0, 0, i).Format(dateLayout)
+		days = append(days, ReplayDay{Date: date, Res: &DayResult{Date: date, PrevState: prev, State: state(i)}})
+		prices = append(prices, Point{Date: date, Value: price(i)})
+		prev = state(i)
+	}
+	return days, prices
+}
+
+func TestEvaluateLeadTime(t *testing.T) {
+	days, prices := leadTimeFixture()
+	rep, err := EvaluateLeadTime(days, prices, LeadTimeOptions{Threshold: 0.10, WindowDays: 10})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-01-01", rep.From)
+	assert.Equal(t, "2026-03-01", rep.To)
+
+	require.Len(t, rep.Episodes, 2)
+	ep := rep.Episodes[0]
+	assert.Equal(t, []string{"2026-01-13", "2026-01-17", "2026-01-19", "2026-01-23"},
+		[]string{ep.Peak, ep.Breach, ep.Trough, ep.Recovery})
+	assert.InDelta(t, 1-90.0/110, ep.Depth, 1e-9)
+	assert.Equal(t, []Warning{{Level: StateWatch, Onset: "2026-01-11", LeadDays: 6}}, ep.Warnings)
+	ep = rep.Episodes[1]
+	assert.Equal(t, []string{"2026-02-11", "2026-02-12", "2026-02-14", ""},
+		[]string{ep.Peak, ep.Breach, ep.Trough, ep.Recovery})
+	assert.Equal(t, []Warning{
+		{Level: StateWatch, Onset: "2026-01-31", LeadDays: 12},
+		{Level: StateBrewing, Onset: "2026-01-31", LeadDays: 12},
+		{Level: StateCrisis, Onset: "2026-02-13", LeadDays: -1},
+	}, ep.Warnings)
+
+	require.Len(t, rep.Levels, 3)
+	assert.Equal(t, LevelStats{Level: StateWatch, Hits: 2, HitRate: 1, AvgLeadDays: 9,
+		Alarms: 5, FalseAlarms: 1, Pending: 1, Precision: 0.75}, rep.Levels[0])
+	assert.Equal(t, LevelStats{Level: StateBrewing, Hits: 1, Missed: 1, HitRate: 0.5, AvgLeadDays: 12,
+		Alarms: 2, Precision: 1}, rep.Levels[1])
+	assert.Equal(t, LevelStats{Level: StateCrisis, Late: 1, Missed: 1,
+		Alarms: 1, Precision: 1}, rep.Levels[2])
+
+	require.Len(t, rep.States, 4)
+	normal, watch, brewing, crisis := rep.States[0], rep.States[1], rep.States[2], rep.States[3]
+	assert.Equal(t, []int{42, 5}, []int{normal.Days, normal.Spells})
+	assert.Equal(t, []int{12, 3}, []int{watch.Days, watch.Spells})
+	assert.InDelta(t, 4.0, watch.AvgSpellDays, 1e-9)
+	assert.InDelta(t, 0.2, watch.Share, 1e-9)
+	assert.Equal(t, []int{5, 1}, []int{brewing.Days, brewing.Spells})
+	assert.InDelta(t, 0, brewing.BenchReturn, 1e-9)
+	assert.InDelta(t, 90.0/93-1, crisis.BenchReturn, 1e-9) // 02-13 → 02-14
+}
+
+// 早于回放窗口破阈的事件无从预警，不计分。
+func TestEvaluateLeadTimeSkipsEpisodesBeforeWindow(t *testing.T) {
+	days, prices := leadTimeFixture()
+	rep, err := EvaluateLeadTime(days[20:], prices, LeadTimeOptions{Threshold: 0.10, WindowDays: 10})
+	require.NoError(t, err)
+	require.Len(t, rep.Episodes, 1)
+	assert.Equal(t, "2026-02-12", rep.Episodes[0].Breach)
+}
+
+func TestEvaluateLeadTimeErrors(t *testing.T) {
+	days, prices := leadTimeFixture()
+	for name, call := range map[string]func() error{
+		"no days":   func() error { _, err := EvaluateLeadTime(nil, prices, LeadTimeOptions{0.1, 10}); return err },
+		"one close": func() error { _, err := EvaluateLeadTime(days, prices[:1], LeadTimeOptions{0.1, 10}); return err },
+		"threshold": func() error { _, err := EvaluateLeadTime(days, prices, LeadTimeOptions{1, 10}); return err },
+		"window":    func() error { _, err := EvaluateLeadTime(days, prices, LeadTimeOptions{0.1, 0}); return err },
+	} {
+		assert.Error(t, call(), name)
+	}
+}
+
+func TestRenderEvaluationHTML(t *testing.T) {
+	days, sr := htmlFixture(t)
+	plain, err := RenderReplayHTML(testConfig(), days, sr)
+	require.NoError(t, err)
+	assert.NotContains(t, plain, "信号领先性评估")
+
+	ldays, prices := leadTimeFixture()
+	rep, err := EvaluateLeadTime(ldays, prices, LeadTimeOptions{Threshold: 0.10, WindowDays: 10})
+	require.NoError(t, err)
+	rep.Benchmark = "^GSPC"
+	html, err := RenderEvaluationHTML(testConfig(), days, sr, rep)
+	require.NoError(t, err)
+	replay := strings.TrimRight(plain[:strings.Index(plain, "<footer>")], "\n")
+	assert.True(t, strings.HasPrefix(html, replay), "回放部分不变，评估一节追加在页脚前")
+	assert.Less(t, strings.Index(html, "信号领先性评估"), strings.Index(html, "<footer>"))
+	assert.Contains(t, html, "基准 ^GSPC · 回撤阈值 10% · 预警窗口 10 日")
+	assert.Contains(t, html, "<td>WATCH</td><td>2</td><td>2</td><td>0</td><td>0</td><td>100%</td><td>9</td><td>5</td><td>1</td><td>1</td><td>75%</td>")
+	assert.Contains(t, html, "<td>-1（2026-02-13）</td>") // CRISIS 迟到
+	assert.Contains(t, html, "未修复")
+	assert.Contains(t, html, "<td>CRISIS</td><td>1</td><td>2%</td><td>1</td><td>1.0</td><td>-3.2%</td>")
+}
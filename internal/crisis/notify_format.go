@@ -2,40 +2,31 @@ package crisis
 
 import (
 	"fmt"
+	"math"
 	"strings"
 )
 
-// layerName 冰山层固定映射（通知设计 §6.2）。
-func layerName(ind string) string {
-	switch ind {
-	case IndVIX, IndMOVE:
-		return "情绪"
-	case IndSOFREFFR:
-		return "流动性"
-	case IndHYOAS:
-		return "信用"
-	case IndT10Y2Y, IndNFCI:
-		return "领先"
-	case IndUSDJPY:
-		return "旁证"
+// layerName 冰山层名（通知设计 §6.2），取自指标定义的 layer；未知指标原样返回。
+func layerName(cfg *Config, ind string) string {
+	d, _ := cfg.indicatorDef(ind)
+	for _, l := range layers {
+		if l.key == d.Layer {
+			return l.name
+		}
 	}
 	return ind
 }
 
 // icebergRank 异常区同级排序的冰山层序：信用→流动性→情绪→领先→旁证
-// （深层异常优先看，通知设计 §6.2）。
-func icebergRank(ind string) int {
-	switch layerName(ind) {
-	case "信用":
-		return 0
-	case "流动性":
-		return 1
-	case "情绪":
-		return 2
-	case "领先":
-		return 3
+// （深层异常优先看，通知设计 §6.2）；无层的指标殿后。
+func icebergRank(cfg *Config, ind string) int {
+	d, _ := cfg.indicatorDef(ind)
+	for i, l := range layers {
+		if l.key == d.Layer {
+			return i
+		}
 	}
-	return 4 // 旁证
+	return len(layers) - 1
 }
 
 func statusEmoji(s Status) string {
@@ -63,7 +54,7 @@ func nonColorNote(s Status) string {
 	return ""
 }
 
-// tagText 统一 中文(英文)（通知设计 §6.3）。
+// tagText 统一 中文(英文)（通知设计 §6.3）；自定义指标的 tag 原样显示。
 func tagText(t Tag) string {
 	switch t {
 	case TagStress:
@@ -75,46 +66,33 @@ func tagText(t Tag) string {
 	case TagSteepening:
 		return "倒挂后复陡(STEEPENING)"
 	}
-	return ""
+	return string(t)
 }
 
-// formatReading 每指标读数格式（通知设计 §6.3 写死一条）。
-func formatReading(ind string, v float64) string {
-	switch ind {
-	case IndVIX, IndMOVE, IndUSDJPY:
-		return fmt.Sprintf("%.1f", v)
-	case IndHYOAS:
-		return fmt.Sprintf("%.0fbp", v)
-	case IndSOFREFFR, IndT10Y2Y:
-		return fmt.Sprintf("%+.0fbp", v)
+// formatReading 每指标读数格式（通知设计 §6.3），由指标定义的 display 决定：
+// decimals 位小数 + unit，signed 带正负号。
+func formatReading(cfg *Config, ind string, v float64) string {
+	d, _ := cfg.indicatorDef(ind)
+	if d.Display.Signed {
+		return fmt.Sprintf("%+.*f%s", d.Display.Decimals, v, d.Display.Unit)
 	}
-	return fmt.Sprintf("%+.2f", v) // nfci
+	return fmt.Sprintf("%.*f%s", d.Display.Decimals, v, d.Display.Unit)
 }
 
-// formatDelta 变化量格式（月报月变化与日报"较昨日"共用，通知设计 §6.3）。
-func formatDelta(ind string, d float64) string {
-	switch ind {
-	case IndVIX, IndMOVE, IndUSDJPY:
-		return fmt.Sprintf("%+.1f", d)
-	case IndHYOAS, IndSOFREFFR, IndT10Y2Y:
-		return fmt.Sprintf("%+.0fbp", d)
-	}
-	return fmt.Sprintf("%+.2f", d) // nfci
+// formatDelta 变化量格式（月报月变化与日报"较昨日"共用，通知设计 §6.3），恒带符号。
+func formatDelta(cfg *Config, ind string, delta float64) string {
+	d, _ := cfg.indicatorDef(ind)
+	return fmt.Sprintf("%+.*f%s", d.Display.Decimals, delta, d.Display.Unit)
 }
 
 // deltaEpsilon 趋势箭头的"横盘"判定 = 该指标显示精度一个单位（通知设计 §6.4）。
-func deltaEpsilon(ind string) float64 {
-	switch ind {
-	case IndVIX, IndMOVE, IndUSDJPY:
-		return 0.1
-	case IndNFCI:
-		return 0.01
-	}
-	return 1 // bp 类
+func deltaEpsilon(cfg *Config, ind string) float64 {
+	d, _ := cfg.indicatorDef(ind)
+	return math.Pow10(-d.Display.Decimals)
 }
 
-func trendArrow(ind string, delta float64) string {
-	eps := deltaEpsilon(ind)
+func trendArrow(cfg *Config, ind string, delta float64) string {
+	eps := deltaEpsilon(cfg, ind)
 	switch {
 	case delta >= eps:
 		return "↗"
@@ -124,10 +102,12 @@ func trendArrow(ind string, delta float64) string {
 	return "→"
 }
 
-// showPct5y：sofr_effr（利差水平 5y 分位无解读价值）与 usdjpy（用 52 周拥挤
-// 分位）不显示 5y 分位片段（补充决策 4，设计 §5 示例一致省略）。
-func showPct5y(ind string) bool {
-	return ind != IndSOFREFFR && ind != IndUSDJPY
+// showPct5y：display.hide_pct5y 的指标不显示 5y 分位片段——内置的 sofr_effr
+// （利差水平 5y 分位无解读价值）与 usdjpy（用 52 周拥挤分位）即如此（补充决策 4，
+// 设计 §5 示例一致省略）。
+func showPct5y(cfg *Config, ind string) bool {
+	d, _ := cfg.indicatorDef(ind)
+	return !d.Display.HidePct5y
 }
 
 func formatPct5y(p float64) string { return fmt.Sprintf("%.0f%%", p*100) }
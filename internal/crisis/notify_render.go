@@ -51,23 +51,25 @@ func stateRank(s SystemState) int {
 // 🔴 信用 hy_oas 612bp · 5y分位 98% · 压力(STRESS)
 func indicatorLine(cfg *Config, r IndicatorResult) string {
 	if note := nonColorNote(r.Status); note != "" {
-		head := fmt.Sprintf("⚪ %s %s", layerName(r.Indicator), r.Indicator)
+		head := fmt.Sprintf("⚪ %s %s", layerName(cfg, r.Indicator), r.Indicator)
 		if r.Status == StatusNoData { // 无读数：直接空格接说明（boundary[0]）
 			return head + " " + note
 		}
-		return head + " " + formatReading(r.Indicator, r.Value) + " · " + note
+		return head + " " + formatReading(cfg, r.Indicator, r.Value) + " · " + note
 	}
-	head := fmt.Sprintf("%s %s %s %s", statusEmoji(r.Status), layerName(r.Indicator),
-		r.Indicator, formatReading(r.Indicator, r.Value))
+	head := fmt.Sprintf("%s %s %s %s", statusEmoji(r.Status), layerName(cfg, r.Indicator),
+		r.Indicator, formatReading(cfg, r.Indicator, r.Value))
 	var parts []string
-	if showPct5y(r.Indicator) && r.Pct5y >= 0 {
+	if showPct5y(cfg, r.Indicator) && r.Pct5y >= 0 {
 		parts = append(parts, "5y分位 "+formatPct5y(r.Pct5y))
 	}
-	if r.Indicator == IndSOFREFFR && severity(r.Status) >= severity(StatusAmber) && r.PersistDays > 0 {
+	// 持续天数只由持续性档位（days）写入，sofr_effr 即此类。
+	if severity(r.Status) >= severity(StatusAmber) && r.PersistDays > 0 {
 		parts = append(parts, fmt.Sprintf("持续 %d 个交易日", r.PersistDays))
 	}
-	if r.Indicator == IndUSDJPY && severity(r.Status) >= severity(StatusAmber) &&
-		r.WowOK && r.Wow <= cfg.Indicators.USDJPY.AmberWowPct {
+	// 周跌片段：向下的周环比档位命中时显示（usdjpy 日元急升值）。
+	if def, _ := cfg.indicatorDef(r.Indicator); severity(r.Status) >= severity(StatusAmber) &&
+		r.WowOK && def.wowDropHit(r.Wow) {
 		parts = append(parts, fmt.Sprintf("周跌 %.1f%%", -r.Wow*100))
 	}
 	if t := tagText(r.Tag); t != "" {
@@ -80,10 +82,11 @@ func indicatorLine(cfg *Config, r IndicatorResult) string {
 }
 
 // splitZones 通知设计 §6.2：异常区 = 🔴🟡，严重度降序、同级按冰山层序、再按
-// AllIndicators 序；其余区 = 🟢 后接 ⚪（已退出共振，视觉最弱、殿后）。
-func splitZones(res *DayResult) (abnormal, rest []IndicatorResult) {
+// 指标序（cfg.IndicatorNames）；其余区 = 🟢 后接 ⚪（已退出共振，视觉最弱、殿后）。
+func splitZones(cfg *Config, res *DayResult) (abnormal, rest []IndicatorResult) {
 	var noncolor []IndicatorResult
-	for _, ind := range AllIndicators {
+	names := cfg.IndicatorNames()
+	for _, ind := range names {
 		r := res.Results[ind]
 		switch {
 		case severity(r.Status) >= severity(StatusAmber):
@@ -99,30 +102,28 @@ func splitZones(res *DayResult) (abnormal, rest []IndicatorResult) {
 		if severity(a.Status) != severity(b.Status) {
 			return severity(a.Status) > severity(b.Status) // 一级：严重度降序
 		}
-		if icebergRank(a.Indicator) != icebergRank(b.Indicator) {
-			return icebergRank(a.Indicator) < icebergRank(b.Indicator) // 二级：冰山层序
+		if ra, rb := icebergRank(cfg, a.Indicator), icebergRank(cfg, b.Indicator); ra != rb {
+			return ra < rb // 二级：冰山层序
 		}
-		return indicatorIndex(a.Indicator) < indicatorIndex(b.Indicator) // 三级：AllIndicators 序（显式，不靠排序稳定性）
+		return indicatorIndex(names, a.Indicator) < indicatorIndex(names, b.Indicator) // 三级：指标序（显式，不靠排序稳定性）
 	})
 	return abnormal, append(rest, noncolor...)
 }
 
-// indicatorIndex is the position of ind in AllIndicators — the third-level
+// indicatorIndex is the position of ind in names — the third-level
 // abnormal-zone tiebreak (通知设计 §6.2). Made explicit so the ordering is a
 // total comparator rather than an artifact of sort stability (which n≤7 slices
 // can't distinguish stable-vs-unstable and so can't lock via mutation).
-func indicatorIndex(ind string) int {
-	for i, x := range AllIndicators {
-		if x == ind {
-			return i
-		}
+func indicatorIndex(names []string, ind string) int {
+	if i := slices.Index(names, ind); i >= 0 {
+		return i
 	}
-	return len(AllIndicators)
+	return len(names)
 }
 
 // bodyZones 渲染异常区 + 其余区（通知设计 §4 骨架第三段）。
 func bodyZones(cfg *Config, res *DayResult, abnormalTitle string) string {
-	abnormal, rest := splitZones(res)
+	abnormal, rest := splitZones(cfg, res)
 	lines := func(rs []IndicatorResult) string {
 		out := make([]string, len(rs))
 		for i, r := range rs {
@@ -205,14 +206,14 @@ func renderTransition(cfg *Config, nc NotifyContext) string {
 			res.PrevState, nc.StateDays, res.State)
 	} else {
 		glyphAndVerb := "✅ 状态解除" // R2：仅异常区为空时用 ✅（设计 v1.1 原则 2）
-		if abnormal, _ := splitZones(res); len(abnormal) > 0 {
+		if abnormal, _ := splitZones(cfg, res); len(abnormal) > 0 {
 			glyphAndVerb = "🔽 状态回落"
 			residualClause = "其余层面仍有异常，见下。" // R7（v1.2）：与 🔽 共用同一判定
 		}
 		first = fmt.Sprintf("[P1] %s %s → %s · %s", glyphAndVerb, res.PrevState, res.State, monthDay(res.Date))
 		title = "仍异常："
 		tail = fmt.Sprintf("%s 共持续 %d 个评估日 · 下一评估：下一交易日", res.PrevState, nc.StateDays)
-		if w := staleDowngradeWarning(nc); w != "" { // R1a：断更溯源警示置于尾注行前
+		if w := staleDowngradeWarning(cfg, nc); w != "" { // R1a：断更溯源警示置于尾注行前
 			tail = w + "\n" + tail
 		}
 	}
@@ -241,14 +242,14 @@ func colorWord(s Status) string {
 // 读数变化仅列当日异常区指标（hy_oas +6bp）；完全无变化 → 无变化。
 // 读数无变化用浮点直等判断（d != 0）——PrevDay.Value 与当日 Value 同出一个 store 的
 // float 读写、无精度损失，故"完全相等=无变化"成立，刻意不引入 epsilon（补充决策）。
-func diffLine(nc NotifyContext) string {
-	abnormal, _ := splitZones(nc.Res)
+func diffLine(cfg *Config, nc NotifyContext) string {
+	abnormal, _ := splitZones(cfg, nc.Res)
 	inAbnormal := map[string]bool{}
 	for _, r := range abnormal {
 		inAbnormal[r.Indicator] = true
 	}
 	var parts []string
-	for _, ind := range AllIndicators {
+	for _, ind := range cfg.IndicatorNames() {
 		prev, ok := nc.PrevDay[ind]
 		if !ok {
 			continue
@@ -265,7 +266,7 @@ func diffLine(nc NotifyContext) string {
 			continue
 		}
 		if d := cur.Value - prev.Value; inAbnormal[ind] && d != 0 {
-			parts = append(parts, ind+" "+formatDelta(ind, d))
+			parts = append(parts, ind+" "+formatDelta(cfg, ind, d))
 		}
 	}
 	if len(parts) == 0 {
@@ -278,7 +279,7 @@ func diffLine(nc NotifyContext) string {
 func renderDaily(cfg *Config, nc NotifyContext) string {
 	res := nc.Res
 	first := fmt.Sprintf("[P1] 📍 %s 日报 第 %d 日 · %s", res.State, nc.StateDays, monthDay(res.Date))
-	tail := diffLine(nc) + "\n盘中 JPY 监测运行中（每 30 分钟）· 下一评估：下一交易日"
+	tail := diffLine(cfg, nc) + "\n盘中 JPY 监测运行中（每 30 分钟）· 下一评估：下一交易日"
 	return strings.Join([]string{first, bodyZones(cfg, res, "异常指标："), tail}, "\n\n") + notifyFooter
 }
 
@@ -298,13 +299,13 @@ func renderWeekly(cfg *Config, nc NotifyContext) string {
 
 // trendLine 月报趋势行（通知设计 §5.4/§6.4）：
 // 🟡 信用 hy_oas 267bp ▃▂▂▁▁▁▁ ↘-18bp · 3% · 自满(COMPLACENCY)
-func trendLine(r IndicatorResult, tr Trend) string {
+func trendLine(cfg *Config, r IndicatorResult, tr Trend) string {
 	head := fmt.Sprintf("%s %s %s %s %s %s%s",
-		statusEmoji(r.Status), layerName(r.Indicator), r.Indicator,
-		formatReading(r.Indicator, r.Value), sparkline(tr.Window),
-		trendArrow(r.Indicator, tr.Delta), formatDelta(r.Indicator, tr.Delta))
+		statusEmoji(r.Status), layerName(cfg, r.Indicator), r.Indicator,
+		formatReading(cfg, r.Indicator, r.Value), sparkline(tr.Window),
+		trendArrow(cfg, r.Indicator, tr.Delta), formatDelta(cfg, r.Indicator, tr.Delta))
 	var parts []string
-	if showPct5y(r.Indicator) && r.Pct5y >= 0 {
+	if showPct5y(cfg, r.Indicator) && r.Pct5y >= 0 {
 		parts = append(parts, formatPct5y(r.Pct5y))
 	}
 	if t := tagText(r.Tag); t != "" {
@@ -328,7 +329,7 @@ func nextMonthlyDue(date string) string {
 }
 
 // renderMonthly 消息 4：NORMAL 月报（通知设计 §5.4）。月报特例：不做异常/
-// 正常分区，单一趋势区按 AllIndicators 顺序；空趋势窗口省略该行。
+// 正常分区，单一趋势区按指标序；空趋势窗口省略该行。
 func renderMonthly(cfg *Config, nc NotifyContext) string {
 	res := nc.Res
 	month := res.Date
@@ -338,12 +339,12 @@ func renderMonthly(cfg *Config, nc NotifyContext) string {
 	first := fmt.Sprintf("[P1] 📅 Cassandra 月报 · %s · %s 已持续 %d 个评估日",
 		month, res.State, nc.StateDays)
 	lines := []string{"近 21 个交易日趋势（走势 · 月变化 · 5y分位）："}
-	for _, ind := range AllIndicators {
+	for _, ind := range cfg.IndicatorNames() {
 		tr, ok := nc.Trends[ind]
 		if !ok || len(tr.Window) == 0 {
 			continue
 		}
-		lines = append(lines, trendLine(res.Results[ind], tr))
+		lines = append(lines, trendLine(cfg, res.Results[ind], tr))
 	}
 	tail := fmt.Sprintf("AMBER 计数 %d（触发 WATCH 需 ≥%d）· 下次月报：%s",
 		res.Detail.AmberCount, cfg.StateMachine.WatchAmberCount, nextMonthlyDue(res.Date))
@@ -354,16 +355,10 @@ func renderMonthly(cfg *Config, nc NotifyContext) string {
 // 无页脚。去重（仅新进入 STALE 当日发）由 cmd 组装 NewStale 时完成。
 func renderOpsAlert(cfg *Config, nc NotifyContext, ind string) string {
 	first := fmt.Sprintf("[P2] 🔧 %s 数据源断更 · %s", ind, monthDay(nc.Res.Date))
-	channel := "FRED"
-	if ind == IndMOVE || ind == IndUSDJPY {
-		channel = "Yahoo"
-	}
+	channel := cfg.channel(ind)
 	var body string
 	if lastObs, ok := nc.StaleLastObs[ind]; ok && lastObs != "" {
-		maxLag := cfg.Freshness.DailyMaxLagDays
-		if ind == IndNFCI {
-			maxLag = cfg.Freshness.WeeklyMaxLagDays
-		}
+		maxLag := cfg.maxLagDays(ind)
 		body = fmt.Sprintf("最后观测 %s（滞后 %d 日 > 阈值 %d 日），已标记 STALE、不再计入触发判定；数据恢复后自动重新计入。持续超一周需检查 %s 通道。",
 			monthDay(lastObs), daysBetween(lastObs, nc.Res.Date), maxLag, channel)
 	} else {
@@ -378,11 +373,11 @@ func renderOpsAlert(cfg *Config, nc NotifyContext, ind string) string {
 
 // staleDowngradeWarning R1a（设计 v1.1）：状态降级当日有指标新进入 STALE 且
 // 断更前为 RED/AMBER 时，生成溯源警示——触发条件可能被动解除而非真实缓解。
-// 断更前状态取 PrevDay（昨日行）；指标按 AllIndicators 序，颜色列表同序对应；
+// 断更前状态取 PrevDay（昨日行）；指标按 cfg.IndicatorNames 序，颜色列表同序对应；
 // 无符合条件指标返回空串（升级路径由调用方保证不调用本函数）。
-func staleDowngradeWarning(nc NotifyContext) string {
+func staleDowngradeWarning(cfg *Config, nc NotifyContext) string {
 	var inds, colors []string
-	for _, ind := range AllIndicators {
+	for _, ind := range cfg.IndicatorNames() {
 		if !slices.Contains(nc.NewStale, ind) {
 			continue
 		}
@@ -57,7 +57,7 @@ func TestSplitZonesOrdering(t *testing.T) {
 	set(IndHYOAS, StatusAmber)
 	set(IndMOVE, StatusStale)
 
-	abnormal, rest := splitZones(res)
+	abnormal, rest := splitZones(testConfig(), res)
 	var got []string
 	for _, r := range abnormal {
 		got = append(got, r.Indicator)
@@ -78,12 +78,12 @@ func TestSplitZonesOrdering(t *testing.T) {
 		r.Status = StatusAmber
 		res2.Results[ind] = r
 	}
-	ab2, _ := splitZones(res2)
+	ab2, _ := splitZones(testConfig(), res2)
 	assert.Equal(t, []string{IndVIX, IndMOVE}, []string{ab2[0].Indicator, ab2[1].Indicator})
 
-	// indicatorIndex 兜底：未知指标排在 AllIndicators 之后
-	assert.Equal(t, 0, indicatorIndex(IndVIX))
-	assert.Equal(t, len(AllIndicators), indicatorIndex("unknown"))
+	// indicatorIndex 兜底：未知指标排在名单之后
+	assert.Equal(t, 0, indicatorIndex(AllIndicators, IndVIX))
+	assert.Equal(t, len(AllIndicators), indicatorIndex(AllIndicators, "unknown"))
 }
 
 // 区块标题（设计 §4 + 补充决策 5）。
@@ -387,7 +387,7 @@ func TestDiffLineLevels(t *testing.T) {
 		IndVIX:   {Indicator: IndVIX, Status: StatusGreen, Value: 5},     // 非异常，仅变值(-4) → 不出现
 		IndMOVE:  {Indicator: IndMOVE, Status: StatusGreen, Value: 1},    // 绿→STALE → 转白
 	}}
-	line := diffLine(nc)
+	line := diffLine(testConfig(), nc)
 	// 顺序按 AllIndicators：move(idx1) 先于 hy_oas(idx3)；迁移句互斥（hy_oas 无 +118bp）
 	assert.Equal(t, "较昨日：move 转白（原绿） · hy_oas 转红（原黄）", line)
 	assert.NotContains(t, line, "+118bp") // 迁移优先：读数变化被抑制（互斥）
@@ -405,7 +405,7 @@ func TestDiffLineLevels(t *testing.T) {
 	rn := ncMix.Res.Results[IndNFCI]
 	rn.Status = StatusStale
 	ncMix.Res.Results[IndNFCI] = rn
-	mixLine := diffLine(ncMix)
+	mixLine := diffLine(testConfig(), ncMix)
 	assert.Contains(t, mixLine, "sofr_effr 转季末抑制（原数据断更(STALE)）")
 	assert.Contains(t, mixLine, "nfci 转数据断更(STALE)（原无数据(NO_DATA)）")
 	assert.NotContains(t, mixLine, "转白（原白）")
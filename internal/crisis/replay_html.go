@@ -18,6 +18,7 @@ type replayHTMLData struct {
 	Charts         []indicatorChart
 	Months         []monthRow
 	Transitions    []transitionRow
+	LeadTime       *leadTimeView // nil = 纯回放报告，不含领先性评估
 }
 
 type indicatorChart struct {
@@ -28,7 +29,7 @@ type indicatorChart struct {
 
 type monthRow struct {
 	Month     string
-	Cells     []string // 按 AllIndicators 序：min / max / 月末值
+	Cells     []string // 按指标序（cfg.IndicatorNames）：min / max / 月末值
 	AmberDays int
 	StaleDays int
 	EndState  SystemState
@@ -55,6 +56,15 @@ func stateColor(s SystemState) string {
 // RenderReplayHTML 渲染自包含单文件详细报告（无外链、prefers-color-scheme
 // 亮暗兼容）。始终全量日粒度，与 --form 无关（设计 §5）。
 func RenderReplayHTML(cfg *Config, days []ReplayDay, sr SeriesReader) (string, error) {
+	return renderReplayHTML(cfg, days, sr, nil)
+}
+
+// RenderEvaluationHTML 同 RenderReplayHTML，末尾追加信号领先性评估一节。
+func RenderEvaluationHTML(cfg *Config, days []ReplayDay, sr SeriesReader, rep *LeadTimeReport) (string, error) {
+	return renderReplayHTML(cfg, days, sr, newLeadTimeView(rep))
+}
+
+func renderReplayHTML(cfg *Config, days []ReplayDay, sr SeriesReader, lt *leadTimeView) (string, error) {
 	if len(days) == 0 {
 		return "", fmt.Errorf("no replay days to render")
 	}
@@ -62,13 +72,14 @@ func RenderReplayHTML(cfg *Config, days []ReplayDay, sr SeriesReader) (string, e
 	data := replayHTMLData{
 		From: from, To: to,
 		GeneratedAt:    time.Now().Format("2006-01-02 15:04"),
-		IndicatorNames: AllIndicators,
+		IndicatorNames: cfg.IndicatorNames(),
 		Thresholds:     thresholdRows(cfg),
 		Timeline:       template.HTML(timelineSVG(days)),
-		Months:         monthRows(days),
-		Transitions:    transitionRows(days),
+		Months:         monthRows(cfg, days),
+		Transitions:    transitionRows(cfg, days),
+		LeadTime:       lt,
 	}
-	for _, ind := range AllIndicators {
+	for _, ind := range data.IndicatorNames {
 		obs, err := sr.WindowSince(ind, from, to)
 		if err != nil {
 			return "", err
@@ -92,9 +103,10 @@ func RenderReplayHTML(cfg *Config, days []ReplayDay, sr SeriesReader) (string, e
 }
 
 // thresholdRows 阈值摘要：key=value 原样陈列 cfg 数值，不做语义解读。
+// custom_indicators 声明的指标（含取代内置者）陈列其规则档位。
 func thresholdRows(cfg *Config) [][2]string {
 	ic := cfg.Indicators
-	return [][2]string{
+	rows := [][2]string{
 		{IndVIX, fmt.Sprintf("amber=%.0f red=%.0f weekly_spike=%.0f%%", ic.VIX.Amber, ic.VIX.Red, ic.VIX.WeeklySpikePct*100)},
 		{IndMOVE, fmt.Sprintf("amber=%.0f red=%.0f", ic.MOVE.Amber, ic.MOVE.Red)},
 		{IndSOFREFFR, fmt.Sprintf("amber_bp=%+.0f×%d日 red_bp=%+.0f×%d日", ic.SOFREFFR.AmberBp, ic.SOFREFFR.AmberPersistDays, ic.SOFREFFR.RedBp, ic.SOFREFFR.RedPersistDays)},
@@ -103,6 +115,56 @@ func thresholdRows(cfg *Config) [][2]string {
 		{IndNFCI, fmt.Sprintf("green_below=%+.2f red_above=%+.2f", ic.NFCI.GreenBelow, ic.NFCI.RedAbove)},
 		{IndUSDJPY, fmt.Sprintf("amber_wow=%.1f%% red_wow=%.1f%%（周环比，无水平阈值线）", ic.USDJPY.AmberWowPct*100, ic.USDJPY.RedWowPct*100)},
 	}
+	var out [][2]string
+	for _, row := range rows {
+		if !cfg.isCustom(row[0]) {
+			out = append(out, row)
+		}
+	}
+	for _, d := range cfg.evaluatedDefs() {
+		if cfg.isCustom(d.Name) {
+			out = append(out, [2]string{d.Name, ruleSummary(d)})
+		}
+	}
+	return out
+}
+
+// ruleSummary 通用指标的档位摘要：每条规则一段，档位按判定顺序以 / 分隔，如
+// "value>30 RED / value≥25 AMBER · wow>0.5 AMBER"。
+func ruleSummary(d IndicatorDef) string {
+	symbols := map[string]string{"above": ">", "at_least": "≥", "below": "<", "at_most": "≤"}
+	var rules []string
+	for _, r := range d.Rules {
+		measure := r.Measure
+		switch measure {
+		case "":
+			measure = MeasureValue
+		case MeasureChange, MeasureRebound:
+			measure = fmt.Sprintf("%s(%d观测)", measure, r.Obs)
+		case MeasurePercentile:
+			measure = fmt.Sprintf("%s(%d年)", measure, r.WindowYears)
+		}
+		var levels []string
+		for _, l := range r.Levels {
+			op, th, _ := l.op()
+			lv := fmt.Sprintf("%s%s%g", measure, symbols[op], th)
+			if l.Days > 0 {
+				lv += fmt.Sprintf("×%d日", l.Days)
+			}
+			if l.Status != "" {
+				lv += " " + string(l.Status)
+			}
+			if l.Tag != "" {
+				lv += "(" + string(l.Tag) + ")"
+			}
+			levels = append(levels, lv)
+		}
+		rules = append(rules, strings.Join(levels, " / "))
+	}
+	if d.PercentileTrack {
+		rules = append(rules, "5y分位轨")
+	}
+	return strings.Join(rules, " · ")
 }
 
 // timelineSVG 状态时间线点阵：x=交易日序、每日一个色块；月份变更画刻度线，
@@ -131,29 +193,54 @@ func timelineSVG(days []ReplayDay) string {
 	return b.String()
 }
 
-type thLine struct {
-	v     float64
-	color string
+// Threshold is one horizontal threshold line of an indicator chart: the level
+// and the status it marks (AMBER or RED).
+type Threshold struct {
+	Value  float64 `json:"value"`
+	Status Status  `json:"status"`
 }
 
-// thresholdLines 折线图阈值横线（usdjpy 周环比规则无水平阈值 → 无横线；
-// nfci 只画 red_above；t10y2y 只画 amber_bp）。
-func thresholdLines(cfg *Config, ind string) []thLine {
-	const amber, red = "#eab308", "#dc2626"
-	ic := cfg.Indicators
+// thresholdColor 阈值线色板，与 stateColor 的黄/红一致。
+func thresholdColor(s Status) string {
+	if s == StatusRed {
+		return "#dc2626"
+	}
+	return "#eab308"
+}
+
+// Thresholds returns the chart threshold lines of ind (usdjpy's week-over-week
+// rule has no level line → none; nfci draws only red_above, t10y2y only
+// amber_bp). Custom indicators draw every colored level of their value rules.
+func (c *Config) Thresholds(ind string) []Threshold {
+	if c.isCustom(ind) {
+		d, _ := c.indicatorDef(ind)
+		var out []Threshold
+		for _, r := range d.Rules {
+			if r.Measure != "" && r.Measure != MeasureValue {
+				continue
+			}
+			for _, l := range r.Levels {
+				if _, th, _ := l.op(); isColor(l.Status) {
+					out = append(out, Threshold{th, l.Status})
+				}
+			}
+		}
+		return out
+	}
+	ic := c.Indicators
 	switch ind {
 	case IndVIX:
-		return []thLine{{ic.VIX.Amber, amber}, {ic.VIX.Red, red}}
+		return []Threshold{{ic.VIX.Amber, StatusAmber}, {ic.VIX.Red, StatusRed}}
 	case IndMOVE:
-		return []thLine{{ic.MOVE.Amber, amber}, {ic.MOVE.Red, red}}
+		return []Threshold{{ic.MOVE.Amber, StatusAmber}, {ic.MOVE.Red, StatusRed}}
 	case IndSOFREFFR:
-		return []thLine{{ic.SOFREFFR.AmberBp, amber}, {ic.SOFREFFR.RedBp, red}}
+		return []Threshold{{ic.SOFREFFR.AmberBp, StatusAmber}, {ic.SOFREFFR.RedBp, StatusRed}}
 	case IndHYOAS:
-		return []thLine{{ic.HYOAS.AmberHighBp, amber}, {ic.HYOAS.RedBp, red}}
+		return []Threshold{{ic.HYOAS.AmberHighBp, StatusAmber}, {ic.HYOAS.RedBp, StatusRed}}
 	case IndNFCI:
-		return []thLine{{ic.NFCI.RedAbove, red}}
+		return []Threshold{{ic.NFCI.RedAbove, StatusRed}}
 	case IndT10Y2Y:
-		return []thLine{{ic.T10Y2Y.AmberBp, amber}}
+		return []Threshold{{ic.T10Y2Y.AmberBp, StatusAmber}}
 	}
 	return nil // usdjpy
 }
@@ -177,9 +264,9 @@ func lineChartSVG(cfg *Config, ind string, days []ReplayDay, obs []Observation)
 	for _, o := range obs {
 		lo, hi = min(lo, o.Value), max(hi, o.Value)
 	}
-	lines := thresholdLines(cfg, ind)
+	lines := cfg.Thresholds(ind)
 	for _, t := range lines {
-		lo, hi = min(lo, t.v), max(hi, t.v)
+		lo, hi = min(lo, t.Value), max(hi, t.Value)
 	}
 	if hi == lo {
 		hi = lo + 1
@@ -188,12 +275,12 @@ func lineChartSVG(cfg *Config, ind string, days []ReplayDay, obs []Observation)
 
 	var b strings.Builder
 	fmt.Fprintf(&b, `<svg class="chart" viewBox="0 0 %d %d" role="img" aria-label="%s">`, w, h, ind)
-	fmt.Fprintf(&b, `<text x="2" y="%.1f" class="lbl">%s</text>`, yOf(hi)+4, formatReading(ind, hi))
-	fmt.Fprintf(&b, `<text x="2" y="%.1f" class="lbl">%s</text>`, yOf(lo), formatReading(ind, lo))
+	fmt.Fprintf(&b, `<text x="2" y="%.1f" class="lbl">%s</text>`, yOf(hi)+4, formatReading(cfg, ind, hi))
+	fmt.Fprintf(&b, `<text x="2" y="%.1f" class="lbl">%s</text>`, yOf(lo), formatReading(cfg, ind, lo))
 	for _, t := range lines {
-		y := yOf(t.v)
-		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="%s" stroke-dasharray="4 3" stroke-width="1"/>`, pad, y, w-pad/2, y, t.color)
-		fmt.Fprintf(&b, `<text x="%d" y="%.1f" class="lbl" fill="%s">%s</text>`, w-pad/2+2, y+3, t.color, formatReading(ind, t.v))
+		y, color := yOf(t.Value), thresholdColor(t.Status)
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="%s" stroke-dasharray="4 3" stroke-width="1"/>`, pad, y, w-pad/2, y, color)
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" class="lbl" fill="%s">%s</text>`, w-pad/2+2, y+3, color, formatReading(cfg, ind, t.Value))
 	}
 	var pts []string
 	for _, o := range obs {
@@ -216,11 +303,12 @@ func lineChartSVG(cfg *Config, ind string, days []ReplayDay, obs []Observation)
 
 // monthRows 月度汇总：月份 × {各指标 min/max/月末值、AMBER 天数、STALE 天数、
 // 月末状态}。读数只取有新鲜观测的日（hasFreshReading，与总结极值同口径）。
-func monthRows(days []ReplayDay) []monthRow {
+func monthRows(cfg *Config, days []ReplayDay) []monthRow {
 	type agg struct {
 		lo, hi, end float64
 		seen        bool
 	}
+	names := cfg.IndicatorNames()
 	var rows []monthRow
 	var cur *monthRow
 	var aggs map[string]*agg
@@ -228,14 +316,14 @@ func monthRows(days []ReplayDay) []monthRow {
 		if cur == nil {
 			return
 		}
-		for i, ind := range AllIndicators {
+		for i, ind := range names {
 			a := aggs[ind]
 			if !a.seen {
 				cur.Cells[i] = "—"
 				continue
 			}
 			cur.Cells[i] = fmt.Sprintf("%s / %s / %s",
-				formatReading(ind, a.lo), formatReading(ind, a.hi), formatReading(ind, a.end))
+				formatReading(cfg, ind, a.lo), formatReading(cfg, ind, a.hi), formatReading(cfg, ind, a.end))
 		}
 		rows = append(rows, *cur)
 	}
@@ -243,13 +331,13 @@ func monthRows(days []ReplayDay) []monthRow {
 		m := d.Date[:7]
 		if cur == nil || cur.Month != m {
 			flush()
-			cur = &monthRow{Month: m, Cells: make([]string, len(AllIndicators))}
+			cur = &monthRow{Month: m, Cells: make([]string, len(names))}
 			aggs = map[string]*agg{}
-			for _, ind := range AllIndicators {
+			for _, ind := range names {
 				aggs[ind] = &agg{}
 			}
 		}
-		for _, ind := range AllIndicators {
+		for _, ind := range names {
 			r := d.Res.Results[ind]
 			if !hasFreshReading(r.Status) {
 				continue
@@ -265,7 +353,7 @@ func monthRows(days []ReplayDay) []monthRow {
 		if d.Res.Detail.AmberCount > 0 {
 			cur.AmberDays++
 		}
-		for _, ind := range AllIndicators {
+		for _, ind := range names {
 			if d.Res.Results[ind].Status == StatusStale {
 				cur.StaleDays++
 				break
@@ -279,14 +367,14 @@ func monthRows(days []ReplayDay) []monthRow {
 
 // transitionRows 状态转移明细：日期、FROM→TO、当日触发指标摘要（红/黄名单 +
 // amber 计数，detail 摘要口径）。
-func transitionRows(days []ReplayDay) []transitionRow {
+func transitionRows(cfg *Config, days []ReplayDay) []transitionRow {
 	var rows []transitionRow
 	for _, d := range days {
 		if !d.Res.Transitioned() {
 			continue
 		}
 		var reds, ambers []string
-		for _, ind := range AllIndicators {
+		for _, ind := range cfg.IndicatorNames() {
 			switch d.Res.Results[ind].Status {
 			case StatusRed:
 				reds = append(reds, ind)
@@ -367,6 +455,24 @@ footer { margin-top: 2rem; color: #6b7280; font-size: 0.8rem; border-top: 1px so
 <tr><th>日期</th><th>转移</th><th style="text-align:left">当日触发指标</th></tr>
 {{range .Transitions}}<tr><td>{{.Date}}</td><td>{{.From}} → {{.To}}</td><td style="text-align:left">{{.Detail}}</td></tr>
 {{end}}</table></div>
+{{with .LeadTime}}
+<h2>信号领先性评估</h2>
+<p class="meta">基准 {{.Benchmark}} · 回撤阈值 {{.Threshold}} · 预警窗口 {{.WindowDays}} 日 · 级别 X 的预警 = 连续处于 X 或更严重状态的一段；领先天数自该段起点计至破阈日</p>
+<div class="scroll"><table>
+<tr><th>级别（及以上）</th><th>回撤事件</th><th>提前命中</th><th>迟到</th><th>漏报</th><th>命中率</th><th>平均领先（日）</th><th>预警段</th><th>误报</th><th>待定</th><th>精确率</th></tr>
+{{range .Levels}}<tr><td>{{.Level}}</td><td>{{.Episodes}}</td><td>{{.Hits}}</td><td>{{.Late}}</td><td>{{.Missed}}</td><td>{{.HitRate}}</td><td>{{.AvgLead}}</td><td>{{.Alarms}}</td><td>{{.FalseAlarms}}</td><td>{{.Pending}}</td><td>{{.Precision}}</td></tr>
+{{end}}</table></div>
+<h3>各状态停留</h3>
+<div class="scroll"><table>
+<tr><th>状态</th><th>评估日</th><th>占比</th><th>段数</th><th>平均段长（日）</th><th>期间基准收益</th></tr>
+{{range .States}}<tr><td>{{.State}}</td><td>{{.Days}}</td><td>{{.Share}}</td><td>{{.Spells}}</td><td>{{.AvgSpell}}</td><td>{{.BenchReturn}}</td></tr>
+{{end}}</table></div>
+<h3>回撤事件</h3>
+{{if .Episodes}}<div class="scroll"><table>
+<tr><th>峰值日</th><th>破阈日</th><th>谷底日</th><th>回撤</th><th>修复日</th>{{range .LevelNames}}<th>{{.}} 领先</th>{{end}}</tr>
+{{range .Episodes}}<tr><td>{{.Peak}}</td><td>{{.Breach}}</td><td>{{.Trough}}</td><td>{{.Depth}}</td><td>{{.Recovery}}</td>{{range .Leads}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table></div>{{else}}<p class="meta">区间内无达阈值的回撤</p>{{end}}
+{{end}}
 
 <footer>历史回放，非实时告警；阈值为当前配置，非事后调参。风险状态提示（概率语言），非交易信号。</footer>
 </body>
@@ -121,22 +121,22 @@ func TestStateColorPalette(t *testing.T) {
 	assert.Equal(t, "#dc2626", stateColor(StateCrisis))
 }
 
-// thresholdLines 逐指标横线规则：5 指标各自阈值线 + nfci/t10y2y 单线；
+// Thresholds 逐指标横线规则：5 指标各自阈值线 + nfci/t10y2y 单线；
 // usdjpy 周环比规则无水平阈值线 → 返回 nil（原 nf review 项转直接断言）。
-func TestThresholdLines(t *testing.T) {
+func TestThresholds(t *testing.T) {
 	cfg := testConfig()
-	assert.Len(t, thresholdLines(cfg, IndVIX), 2)
-	assert.Len(t, thresholdLines(cfg, IndMOVE), 2)
-	assert.Len(t, thresholdLines(cfg, IndSOFREFFR), 2)
-	assert.Len(t, thresholdLines(cfg, IndHYOAS), 2)
-	assert.Len(t, thresholdLines(cfg, IndNFCI), 1)    // 只画 red_above
-	assert.Len(t, thresholdLines(cfg, IndT10Y2Y), 1)  // 只画 amber_bp
-	assert.Nil(t, thresholdLines(cfg, IndUSDJPY))     // 周环比规则无水平阈值线
+	assert.Len(t, cfg.Thresholds(IndVIX), 2)
+	assert.Len(t, cfg.Thresholds(IndMOVE), 2)
+	assert.Len(t, cfg.Thresholds(IndSOFREFFR), 2)
+	assert.Len(t, cfg.Thresholds(IndHYOAS), 2)
+	assert.Len(t, cfg.Thresholds(IndNFCI), 1)    // 只画 red_above
+	assert.Len(t, cfg.Thresholds(IndT10Y2Y), 1)  // 只画 amber_bp
+	assert.Nil(t, cfg.Thresholds(IndUSDJPY))     // 周环比规则无水平阈值线
 
 	// 值来自 cfg（判别性：vix amber/red = testConfig 的 25/30）
-	vix := thresholdLines(cfg, IndVIX)
-	assert.Equal(t, 25.0, vix[0].v)
-	assert.Equal(t, 30.0, vix[1].v)
+	vix := cfg.Thresholds(IndVIX)
+	assert.Equal(t, Threshold{25, StatusAmber}, vix[0])
+	assert.Equal(t, Threshold{30, StatusRed}, vix[1])
 }
 
 // non_functional: 自包含（无外链）、亮暗兼容、禁词、阈值来自 cfg。
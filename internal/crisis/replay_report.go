@@ -12,7 +12,7 @@ func ReplayReport(cfg *Config, form string, day ReplayDay, prev *ReplayDay, sr S
 		PrevDay:   map[string]Evaluation{},
 	}
 	if prev != nil {
-		for _, ind := range AllIndicators {
+		for _, ind := range cfg.IndicatorNames() {
 			r := prev.Res.Results[ind]
 			nc.PrevDay[ind] = Evaluation{Indicator: ind, Status: r.Status, Value: r.Value}
 		}
@@ -22,7 +22,7 @@ func ReplayReport(cfg *Config, form string, day ReplayDay, prev *ReplayDay, sr S
 		return renderDaily(cfg, nc), nil
 	case "monthly":
 		nc.Trends = map[string]Trend{}
-		for _, ind := range AllIndicators {
+		for _, ind := range cfg.IndicatorNames() {
 			win, err := sr.Window(ind, day.Date, 21)
 			if err != nil {
 				return "", err
@@ -8,10 +8,6 @@ import (
 // replayFooter 回放专用尾注（设计 §4：不复用 notifyFooter，含"历史回放"限定）。
 const replayFooter = "\n—\n历史回放，非实时告警；阈值为当前配置，非事后调参。"
 
-// worstIsMin "最差"方向与各指标红灯方向一致：t10y2y（倒挂向下）、usdjpy
-// （急跌向下）取期间最小值，其余取最大值（设计 §4）。
-func worstIsMin(ind string) bool { return ind == IndT10Y2Y || ind == IndUSDJPY }
-
 // hasFreshReading 极值只统计有新鲜读数的日：色彩态与季末抑制有当日观测，
 // STALE/NO_DATA 无（STALE 的 Value 是旧读数，其原日已计入）。
 func hasFreshReading(s Status) bool { return isColor(s) || s == StatusSuppressed }
@@ -55,8 +51,10 @@ func RenderReplaySummary(cfg *Config, days []ReplayDay) string {
 	}
 	b.WriteString("各态停留：" + strings.Join(stays, " · ") + "\n")
 
+	names := cfg.IndicatorNames()
 	var extremes []string
-	for _, ind := range AllIndicators {
+	for _, ind := range names {
+		def, _ := cfg.indicatorDef(ind) // "最差"方向与红灯方向一致（IndicatorDef.worstIsMin）
 		var v float64
 		var date string
 		for _, d := range days {
@@ -65,7 +63,7 @@ func RenderReplaySummary(cfg *Config, days []ReplayDay) string {
 				continue
 			}
 			worse := r.Value > v
-			if worstIsMin(ind) {
+			if def.worstIsMin() {
 				worse = r.Value < v
 			}
 			if date == "" || worse {
@@ -73,7 +71,7 @@ func RenderReplaySummary(cfg *Config, days []ReplayDay) string {
 			}
 		}
 		if date != "" {
-			extremes = append(extremes, fmt.Sprintf("%s %s（%s）", ind, formatReading(ind, v), date))
+			extremes = append(extremes, fmt.Sprintf("%s %s（%s）", ind, formatReading(cfg, ind, v), date))
 		}
 	}
 	if len(extremes) > 0 {
@@ -87,13 +85,13 @@ func RenderReplaySummary(cfg *Config, days []ReplayDay) string {
 		}
 	}
 	if peakDate == "" {
-		fmt.Fprintf(&b, "AMBER 峰值：0/%d\n", len(AllIndicators))
+		fmt.Fprintf(&b, "AMBER 峰值：0/%d\n", len(names))
 	} else {
-		fmt.Fprintf(&b, "AMBER 峰值：%d/%d（%s）\n", peak, len(AllIndicators), peakDate)
+		fmt.Fprintf(&b, "AMBER 峰值：%d/%d（%s）\n", peak, len(names), peakDate)
 	}
 
 	var stales []string
-	for _, ind := range AllIndicators {
+	for _, ind := range names {
 		n := 0
 		for _, d := range days {
 			if d.Res.Results[ind].Status == StatusStale {
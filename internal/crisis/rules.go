@@ -1,19 +1,29 @@
 package crisis
 
+import "fmt"
+
 // minPercentileObs is the minimum window size for the percentile track and
-// the CROWDED check (plan deviation 3: short windows are annotated in
+// the percentile measure (plan deviation 3: short windows are annotated in
 // WindowActualObs, but a rank over a handful of points is meaningless).
 const minPercentileObs = 60
 
 // EvaluateIndicator runs the shared pipeline for one indicator (design §3.1):
-// data presence → freshness → absolute-threshold track → percentile track,
+// data presence → freshness → the indicator's rules → percentile track,
 // either track escalating (maxStatus). NO_DATA and STALE short-circuit and
 // leave resonance counting; seasonal suppression and hysteresis are applied
 // later by EvalDay.
 func EvaluateIndicator(cfg *Config, indicator, date string, sr SeriesReader) (IndicatorResult, error) {
-	res := IndicatorResult{Indicator: indicator, RawStatus: StatusGreen, Pct5y: -1}
+	def, ok := cfg.indicatorDef(indicator)
+	if !ok {
+		return IndicatorResult{Indicator: indicator}, fmt.Errorf("unknown indicator %q", indicator)
+	}
+	return evaluateDef(cfg, def, date, sr)
+}
 
-	latest, err := sr.Window(indicator, date, 1)
+func evaluateDef(cfg *Config, def IndicatorDef, date string, sr SeriesReader) (IndicatorResult, error) {
+	res := IndicatorResult{Indicator: def.Name, RawStatus: StatusGreen, Pct5y: -1}
+
+	latest, err := sr.Window(def.Name, date, 1)
 	if err != nil {
 		return res, err
 	}
@@ -22,22 +32,24 @@ func EvaluateIndicator(cfg *Config, indicator, date string, sr SeriesReader) (In
 		return res, nil
 	}
 	res.Value = latest[0].Value
-	if staleFor(cfg, indicator, date, latest[0].Date) {
+	if staleFor(cfg, def.Name, date, latest[0].Date) {
 		res.Status, res.RawStatus = StatusStale, StatusStale
 		return res, nil
 	}
 
-	pctWin, err := sr.WindowSince(indicator, addYears(date, -cfg.Percentile.WindowYears), date)
+	pctWin, err := sr.WindowSince(def.Name, addYears(date, -cfg.Percentile.WindowYears), date)
 	if err != nil {
 		return res, err
 	}
 	res.Pct5y, res.WindowActualObs = Percentile(pctWin, res.Value)
 
-	if err := evalAbsolute(cfg, indicator, date, sr, &res); err != nil {
-		return res, err
+	for _, r := range def.Rules {
+		if err := r.apply(def.Name, date, sr, &res); err != nil {
+			return res, err
+		}
 	}
 
-	if percentileTrack(cfg, indicator) && res.WindowActualObs >= minPercentileObs {
+	if def.PercentileTrack && res.WindowActualObs >= minPercentileObs {
 		switch {
 		case res.Pct5y >= cfg.Percentile.Red:
 			res.RawStatus = maxStatus(res.RawStatus, StatusRed)
@@ -50,219 +62,112 @@ func EvaluateIndicator(cfg *Config, indicator, date string, sr SeriesReader) (In
 	return res, nil
 }
 
-func percentileTrack(cfg *Config, indicator string) bool {
-	switch indicator {
-	case IndVIX:
-		return cfg.Indicators.VIX.PercentileTrack
-	case IndMOVE:
-		return cfg.Indicators.MOVE.PercentileTrack
-	case IndSOFREFFR:
-		return cfg.Indicators.SOFREFFR.PercentileTrack
-	case IndHYOAS:
-		return cfg.Indicators.HYOAS.PercentileTrack
-	case IndT10Y2Y:
-		return cfg.Indicators.T10Y2Y.PercentileTrack
-	case IndNFCI:
-		return cfg.Indicators.NFCI.PercentileTrack
-	case IndUSDJPY:
-		return cfg.Indicators.USDJPY.PercentileTrack
-	}
-	return false
-}
-
-func evalAbsolute(cfg *Config, indicator, date string, sr SeriesReader, res *IndicatorResult) error {
-	switch indicator {
-	case IndVIX:
-		return evalVIX(cfg, date, sr, res)
-	case IndMOVE:
-		evalMOVE(cfg, res)
-	case IndSOFREFFR:
-		return evalSOFREFFR(cfg, date, sr, res)
-	case IndHYOAS:
-		return evalHYOAS(cfg, date, sr, res)
-	case IndT10Y2Y:
-		return evalT10Y2Y(cfg, date, sr, res)
-	case IndNFCI:
-		evalNFCI(cfg, res)
-	case IndUSDJPY:
-		return evalUSDJPY(cfg, date, sr, res)
-	}
-	return nil
-}
-
-func evalVIX(cfg *Config, date string, sr SeriesReader, res *IndicatorResult) error {
-	c := cfg.Indicators.VIX
-	switch {
-	case res.Value > c.Red:
-		res.RawStatus = StatusRed
-	case res.Value >= c.Amber:
-		res.RawStatus = StatusAmber
-	}
-	win, err := sr.Window(IndVIX, date, 6)
-	if err != nil {
-		return err
-	}
-	if wow, ok := WowPct(win); ok {
-		res.Wow, res.WowOK = wow, true
-		if wow > c.WeeklySpikePct {
-			res.RawStatus = maxStatus(res.RawStatus, StatusAmber)
-		}
-	}
-	return nil
-}
-
-func evalMOVE(cfg *Config, res *IndicatorResult) {
-	c := cfg.Indicators.MOVE
-	switch {
-	case res.Value > c.Red:
-		res.RawStatus = StatusRed
-	case res.Value >= c.Amber:
-		res.RawStatus = StatusAmber
-	}
-}
-
 // persistLookbackObs caps the "持续 N 个交易日" count in notifications. It is
 // a display bound, not a rule threshold (thresholds live in YAML); ~6 weeks
 // covers any persistence worth reporting.
 const persistLookbackObs = 30
 
-// evalSOFREFFR: persistence conditions are the core noise filter (design
-// §3.1 note 3) — red needs red_persist_days consecutive observations above
-// red_bp, amber likewise over its own window. The wider lookback only feeds
-// PersistDays for notifications; the rule windows are unchanged via lastN.
-func evalSOFREFFR(cfg *Config, date string, sr SeriesReader, res *IndicatorResult) error {
-	c := cfg.Indicators.SOFREFFR
-	win, err := sr.Window(IndSOFREFFR, date, persistLookbackObs)
-	if err != nil {
+// apply measures the series, then applies the first matching level: its
+// status escalates the result, its tag is kept unless an earlier check
+// already tagged it. A measure without enough history matches nothing.
+func (r RuleDef) apply(indicator, date string, sr SeriesReader, res *IndicatorResult) error {
+	v, ok, err := r.measure(indicator, date, sr, res)
+	if err != nil || !ok {
 		return err
 	}
-	if len(win) >= c.RedPersistDays && allAbove(lastN(win, c.RedPersistDays), c.RedBp) {
-		res.RawStatus = StatusRed
-		res.PersistDays = consecutiveAbove(win, c.RedBp)
-		return nil
-	}
-	if len(win) >= c.AmberPersistDays && allAbove(lastN(win, c.AmberPersistDays), c.AmberBp) {
-		res.RawStatus = StatusAmber
-		res.PersistDays = consecutiveAbove(win, c.AmberBp)
-	}
-	return nil
-}
-
-// consecutiveAbove counts trailing observations strictly above threshold.
-func consecutiveAbove(obs []Observation, threshold float64) int {
-	n := 0
-	for i := len(obs) - 1; i >= 0; i-- {
-		if obs[i].Value <= threshold {
-			break
+	for _, l := range r.Levels {
+		hit, err := r.matches(l, v, indicator, date, sr, res)
+		if err != nil {
+			return err
 		}
-		n++
-	}
-	return n
-}
-
-// evalHYOAS: two-sided amber (design §3.1 note 1) — too tight is complacency,
-// moderately wide is stress; the momentum condition catches fast widening
-// before the level does.
-func evalHYOAS(cfg *Config, date string, sr SeriesReader, res *IndicatorResult) error {
-	c := cfg.Indicators.HYOAS
-	switch {
-	case res.Value > c.RedBp:
-		res.RawStatus = StatusRed
-	case res.Value < c.AmberLowBp:
-		res.RawStatus, res.Tag = StatusAmber, TagComplacency
-	case res.Value >= c.AmberHighBp:
-		res.RawStatus, res.Tag = StatusAmber, TagStress
-	}
-	win, err := sr.Window(IndHYOAS, date, c.MomentumWindowObs+1)
-	if err != nil {
-		return err
-	}
-	if mom, ok := MomChange(win, c.MomentumWindowObs); ok && mom > c.MomentumBp {
-		res.RawStatus = maxStatus(res.RawStatus, StatusAmber)
+		if !hit {
+			continue
+		}
+		res.RawStatus = maxStatus(res.RawStatus, l.Status)
 		if res.Tag == "" {
-			res.Tag = TagStress
+			res.Tag = l.Tag
 		}
+		return nil
 	}
 	return nil
 }
 
-// evalT10Y2Y: inversion is red, but STEEPENING marks the historically most
-// dangerous window — fast re-steepening after an inversion (design §3.1
-// note 2). The tag never changes the color by itself.
-func evalT10Y2Y(cfg *Config, date string, sr SeriesReader, res *IndicatorResult) error {
-	c := cfg.Indicators.T10Y2Y
-	switch {
-	case res.Value < 0:
-		res.RawStatus = StatusRed
-	case res.Value <= c.AmberBp:
-		res.RawStatus = StatusAmber
+// matches tests one level. A persistence level (days > 0) needs its last
+// days observations all to hold the condition — the core noise filter of
+// sofr_effr (design §3.1 note 3) — and records the trailing run in
+// PersistDays for notifications; the wider lookback only feeds that count.
+func (r RuleDef) matches(l LevelDef, v float64, indicator, date string, sr SeriesReader, res *IndicatorResult) (bool, error) {
+	if l.Days <= 0 {
+		return l.holds(v), nil
 	}
-	win, err := sr.Window(IndT10Y2Y, date, c.SteepeningLookbackObs)
+	win, err := sr.Window(indicator, date, max(persistLookbackObs, l.Days))
 	if err != nil {
-		return err
-	}
-	lowest := res.Value
-	for _, o := range win {
-		if o.Value < lowest {
-			lowest = o.Value
-		}
-	}
-	if lowest < 0 && res.Value-lowest > c.SteepeningBp {
-		res.Tag = TagSteepening
+		return false, err
 	}
-	return nil
-}
-
-func evalNFCI(cfg *Config, res *IndicatorResult) {
-	c := cfg.Indicators.NFCI
-	switch {
-	case res.Value > c.RedAbove:
-		res.RawStatus = StatusRed
-	case res.Value >= c.GreenBelow:
-		res.RawStatus = StatusAmber
+	run := consecutive(win, l)
+	if run < l.Days {
+		return false, nil
 	}
+	res.PersistDays = run
+	return true, nil
 }
 
-// evalUSDJPY: JPY 急升值 = USDJPY 下跌，故 wow 阈值为负、≤ 比较（carry trade
-// 急平仓方向）；CROWDED = USDJPY 处 52 周高分位（日元极端弱势 = 空头拥挤）。
-func evalUSDJPY(cfg *Config, date string, sr SeriesReader, res *IndicatorResult) error {
-	c := cfg.Indicators.USDJPY
-	win, err := sr.Window(IndUSDJPY, date, 6)
-	if err != nil {
-		return err
-	}
-	if wow, ok := WowPct(win); ok {
-		res.Wow, res.WowOK = wow, true
-		switch {
-		case wow <= c.RedWowPct:
-			res.RawStatus = StatusRed
-		case wow <= c.AmberWowPct:
-			res.RawStatus = maxStatus(res.RawStatus, StatusAmber)
+// measure computes the rule's measure at date; ok=false when the history is
+// too short for it. The week-over-week change is also recorded on res for
+// notifications.
+func (r RuleDef) measure(indicator, date string, sr SeriesReader, res *IndicatorResult) (float64, bool, error) {
+	switch r.Measure {
+	case MeasureWow:
+		win, err := sr.Window(indicator, date, 6)
+		if err != nil {
+			return 0, false, err
 		}
-	}
-	yr, err := sr.WindowSince(IndUSDJPY, addYears(date, -1), date)
-	if err != nil {
-		return err
-	}
-	if p, n := Percentile(yr, res.Value); n >= minPercentileObs && p >= c.Crowded52wPct {
-		res.RawStatus = maxStatus(res.RawStatus, StatusAmber)
-		res.Tag = TagCrowded
-	}
-	return nil
-}
-
-func allAbove(obs []Observation, threshold float64) bool {
-	for _, o := range obs {
-		if o.Value <= threshold {
-			return false
+		wow, ok := WowPct(win)
+		if ok {
+			res.Wow, res.WowOK = wow, true
+		}
+		return wow, ok, nil
+	case MeasureChange:
+		win, err := sr.Window(indicator, date, r.Obs+1)
+		if err != nil {
+			return 0, false, err
+		}
+		v, ok := MomChange(win, r.Obs)
+		return v, ok, nil
+	case MeasurePercentile:
+		win, err := sr.WindowSince(indicator, addYears(date, -r.WindowYears), date)
+		if err != nil {
+			return 0, false, err
 		}
+		minObs := r.MinObs
+		if minObs <= 0 {
+			minObs = minPercentileObs
+		}
+		p, n := Percentile(win, res.Value)
+		return p, n >= minObs, nil
+	case MeasureRebound:
+		// 自回看窗口低点的反弹幅度；低点须低于 trough_below（t10y2y：曾倒挂）。
+		win, err := sr.Window(indicator, date, r.Obs)
+		if err != nil {
+			return 0, false, err
+		}
+		lowest := res.Value
+		for _, o := range win {
+			lowest = min(lowest, o.Value)
+		}
+		return res.Value - lowest, lowest < r.TroughBelow, nil
 	}
-	return true
+	return res.Value, true, nil
 }
 
-func lastN(obs []Observation, n int) []Observation {
-	if len(obs) <= n {
-		return obs
+// consecutive counts trailing observations that hold the level's condition.
+func consecutive(obs []Observation, l LevelDef) int {
+	n := 0
+	for i := len(obs) - 1; i >= 0; i-- {
+		if !l.holds(obs[i].Value) {
+			break
+		}
+		n++
 	}
-	return obs[len(obs)-n:]
+	return n
 }
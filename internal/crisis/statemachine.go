@@ -24,12 +24,12 @@ func coloredStatus(res map[string]IndicatorResult, ind string) Status {
 	return ""
 }
 
-// amberOrWorseCount counts indicators at AMBER or above — "全系统 AMBER ≥ 3"
-// read as at-least-amber so reds are not perversely excluded (plan's
-// state-machine semantics note).
+// amberOrWorseCount counts indicators at AMBER or above, custom ones
+// included — "全系统 AMBER ≥ 3" read as at-least-amber so reds are not
+// perversely excluded (plan's state-machine semantics note).
 func amberOrWorseCount(res map[string]IndicatorResult) int {
 	n := 0
-	for _, ind := range AllIndicators {
+	for ind := range res {
 		if severity(coloredStatus(res, ind)) >= severity(StatusAmber) {
 			n++
 		}
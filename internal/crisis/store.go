@@ -201,6 +201,17 @@ func (s *Store) RecentIndicatorEvals(ctx context.Context, indicator string, n in
 	return collectEvaluations(rows)
 }
 
+// EvaluationsBetween returns every evaluation row with from<=ts<=to, oldest
+// first; rows of one day keep their insertion order (system row last).
+func (s *Store) EvaluationsBetween(ctx context.Context, from, to string) ([]Evaluation, error) {
+	rows, err := s.db.QueryContext(ctx,
+		evalSelect+` WHERE ts >= ? AND ts <= ? ORDER BY ts ASC, rowid ASC`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("querying evaluations: %w", err)
+	}
+	return collectEvaluations(rows)
+}
+
 func (s *Store) LatestSystemEval(ctx context.Context) (*Evaluation, error) {
 	evals, err := s.RecentSystemEvals(ctx, 1)
 	if err != nil || len(evals) == 0 {
@@ -63,13 +63,10 @@ func nextTradingDay(t time.Time) time.Time {
 // staleFor implements design §3.2 rule 2 (≈48h beyond expected publication,
 // widened via config for weekends/holidays). It also covers the MOVE
 // "3 consecutive fetch failures" degradation: failed fetches leave the latest
-// observation aging past the window. NFCI uses the weekly allowance.
+// observation aging past the window. Weekly indicators (NFCI) use the weekly
+// allowance.
 func staleFor(cfg *Config, indicator, evalDate, latestObsDate string) bool {
-	maxLag := cfg.Freshness.DailyMaxLagDays
-	if indicator == IndNFCI {
-		maxLag = cfg.Freshness.WeeklyMaxLagDays
-	}
-	return daysBetween(latestObsDate, evalDate) > maxLag
+	return daysBetween(latestObsDate, evalDate) > cfg.maxLagDays(indicator)
 }
 
 // indDetail is the JSON persisted in indicator evaluation rows; Raw feeds the
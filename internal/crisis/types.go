@@ -1,7 +1,8 @@
 // Package crisis implements the macro crisis monitor (Cassandra): ingestion
-// of seven market-stress indicators, config-driven threshold rules with
-// suppression, and a system-level state machine. sqlite is the single source
-// of truth; the process itself is stateless (design §4.3).
+// of seven built-in market-stress indicators plus any declared under
+// custom_indicators, config-driven threshold rules with suppression, and a
+// system-level state machine. sqlite is the single source of truth; the
+// process itself is stateless (design §4.3).
 package crisis
 
 type Status string
@@ -43,6 +44,8 @@ const (
 	IndUSDJPY   = "usdjpy"
 )
 
+// AllIndicators are the built-in indicators in display order; the full
+// evaluated list, custom indicators included, is Config.IndicatorNames.
 var AllIndicators = []string{IndVIX, IndMOVE, IndSOFREFFR, IndHYOAS, IndT10Y2Y, IndNFCI, IndUSDJPY}
 
 // Observation is one dated indicator value in canonical units (see the unit
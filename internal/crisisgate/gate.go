@@ -0,0 +1,134 @@
+// Package crisisgate applies the crisis monitor's system state (NORMAL /
+// WATCH / BREWING / CRISIS) to trading signals between the strategy engine
+// and the router. Per state, a policy may suppress buys, scale their
+// confidence or attach a macro warning that notifiers print. Every signal the
+// gate passes records the state it saw in its metadata.
+//
+// The crisis monitor itself stays a risk monitor: it never sees a signal.
+package crisisgate
+
+import (
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/crisis"
+	"github.com/newthinker/atlas/internal/notifier"
+)
+
+// Metadata keys the gate stamps on the signals it passes.
+const (
+	MetaState         = "crisis_state"
+	MetaStateDate     = "crisis_state_date"
+	MetaRawConfidence = "crisis_raw_confidence"
+	// MetaWarning carries the line notifiers print with the signal.
+	MetaWarning = notifier.MetaMacroWarning
+)
+
+// ReasonCrisisState is the reason Apply gives for a suppressed signal.
+const ReasonCrisisState = "crisis_state"
+
+// DefaultMaxAge is how old the latest system evaluation may be before the
+// gate stops trusting it: a week covers a long weekend plus a missed run.
+const DefaultMaxAge = 7 * 24 * time.Hour
+
+// Policy is what the gate does to signals while one system state is in
+// force.
+type Policy struct {
+	// SuppressBuys drops buy and strong-buy signals.
+	SuppressBuys bool
+	// BuyConfidenceScale multiplies the confidence of buy and strong-buy
+	// signals; 0 leaves it unchanged. Sells are never scaled.
+	BuyConfidenceScale float64
+	// Warn attaches the macro warning line to every signal passed.
+	Warn bool
+}
+
+// Reading is a system state and the observation date it was evaluated for.
+type Reading struct {
+	State crisis.SystemState
+	Date  string // YYYY-MM-DD
+}
+
+// StateSource reports the system state known at a time; ok is false when no
+// evaluation is known yet.
+type StateSource interface {
+	StateAt(at time.Time) (r Reading, ok bool, err error)
+}
+
+// Gate applies the policy of the current system state to signals.
+type Gate struct {
+	source   StateSource
+	policies map[crisis.SystemState]Policy
+	maxAge   time.Duration
+}
+
+// Option configures a Gate.
+type Option func(*Gate)
+
+// WithMaxAge sets how old an evaluation may be and still gate signals
+// (default DefaultMaxAge). Older ones are treated as unknown.
+func WithMaxAge(d time.Duration) Option {
+	return func(g *Gate) {
+		if d > 0 {
+			g.maxAge = d
+		}
+	}
+}
+
+// New creates a gate reading the system state from source. States without a
+// policy pass signals unchanged, apart from the recorded state.
+func New(source StateSource, policies map[crisis.SystemState]Policy, opts ...Option) *Gate {
+	g := &Gate{source: source, policies: policies, maxAge: DefaultMaxAge}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Apply returns sig as the policy of the system state known at now leaves
+// it, or the reason it is suppressed. With no state known, or only a stale
+// one, sig passes unchanged. On a source error sig passes unchanged along
+// with the error, for the caller to report.
+func (g *Gate) Apply(sig core.Signal, now time.Time) (core.Signal, string, error) {
+	r, ok, err := g.source.StateAt(now)
+	if err != nil {
+		return sig, "", fmt.Errorf("crisis gate: reading system state: %w", err)
+	}
+	if !ok || g.stale(r, now) {
+		return sig, "", nil
+	}
+
+	p := g.policies[r.State]
+	buy := sig.Action == core.ActionBuy || sig.Action == core.ActionStrongBuy
+	if buy && p.SuppressBuys {
+		return sig, ReasonCrisisState, nil
+	}
+
+	// The metadata map may be shared with the strategy's other signals.
+	md := maps.Clone(sig.Metadata)
+	if md == nil {
+		md = make(map[string]any)
+	}
+	md[MetaState] = string(r.State)
+	md[MetaStateDate] = r.Date
+	if buy && p.BuyConfidenceScale > 0 && p.BuyConfidenceScale != 1 {
+		md[MetaRawConfidence] = sig.Confidence
+		sig.Confidence *= p.BuyConfidenceScale
+	}
+	if p.Warn {
+		md[MetaWarning] = fmt.Sprintf("Macro crisis state %s (as of %s)", r.State, r.Date)
+	}
+	sig.Metadata = md
+	return sig, "", nil
+}
+
+// stale reports whether r was evaluated more than maxAge before now.
+func (g *Gate) stale(r Reading, now time.Time) bool {
+	d, err := time.Parse("2006-01-02", r.Date)
+	if err != nil {
+		return true
+	}
+	return now.Sub(d) > g.maxAge
+}
@@ -0,0 +1,143 @@
+package crisisgate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/crisis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedSource struct {
+	r   Reading
+	ok  bool
+	err error
+}
+
+func (f fixedSource) StateAt(time.Time) (Reading, bool, error) { return f.r, f.ok, f.err }
+
+var now = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+func testPolicies() map[crisis.SystemState]Policy {
+	return map[crisis.SystemState]Policy{
+		crisis.StateWatch:   {Warn: true},
+		crisis.StateBrewing: {BuyConfidenceScale: 0.5, Warn: true},
+		crisis.StateCrisis:  {SuppressBuys: true, Warn: true},
+	}
+}
+
+func gateIn(state crisis.SystemState) *Gate {
+	return New(fixedSource{r: Reading{State: state, Date: "2026-10-15"}, ok: true}, testPolicies())
+}
+
+func TestApply_Policies(t *testing.T) {
+	buy := core.Signal{Symbol: "AAPL", Action: core.ActionBuy, Confidence: 0.8, Metadata: map[string]any{"name": "Apple"}}
+	sell := core.Signal{Symbol: "AAPL", Action: core.ActionSell, Confidence: 0.8}
+
+	// NORMAL has no policy: only the state is recorded.
+	got, reason, err := gateIn(crisis.StateNormal).Apply(buy, now)
+	require.NoError(t, err)
+	assert.Empty(t, reason)
+	assert.Equal(t, 0.8, got.Confidence)
+	assert.Equal(t, "NORMAL", got.Metadata[MetaState])
+	assert.Equal(t, "2026-10-15", got.Metadata[MetaStateDate])
+	assert.Equal(t, "Apple", got.Metadata["name"])
+	assert.NotContains(t, got.Metadata, MetaWarning)
+	assert.NotContains(t, buy.Metadata, MetaState, "the caller's metadata map is left alone")
+
+	got, _, _ = gateIn(crisis.StateWatch).Apply(buy, now)
+	assert.Equal(t, "Macro crisis state WATCH (as of 2026-10-15)", got.Metadata[MetaWarning])
+
+	got, _, _ = gateIn(crisis.StateBrewing).Apply(buy, now)
+	assert.InDelta(t, 0.4, got.Confidence, 1e-9)
+	assert.Equal(t, 0.8, got.Metadata[MetaRawConfidence])
+	got, _, _ = gateIn(crisis.StateBrewing).Apply(sell, now)
+	assert.Equal(t, 0.8, got.Confidence, "sells are not scaled")
+	assert.NotContains(t, got.Metadata, MetaRawConfidence)
+
+	_, reason, _ = gateIn(crisis.StateCrisis).Apply(buy, now)
+	assert.Equal(t, ReasonCrisisState, reason)
+	strong := buy
+	strong.Action = core.ActionStrongBuy
+	_, reason, _ = gateIn(crisis.StateCrisis).Apply(strong, now)
+	assert.Equal(t, ReasonCrisisState, reason)
+	got, reason, _ = gateIn(crisis.StateCrisis).Apply(sell, now)
+	assert.Empty(t, reason, "sells pass a crisis")
+	assert.Equal(t, "CRISIS", got.Metadata[MetaState])
+}
+
+func TestApply_UnknownOrStaleState(t *testing.T) {
+	buy := core.Signal{Action: core.ActionBuy, Confidence: 0.8}
+
+	got, reason, err := New(fixedSource{}, testPolicies()).Apply(buy, now)
+	require.NoError(t, err)
+	assert.Empty(t, reason)
+	assert.Nil(t, got.Metadata)
+
+	// A crisis evaluated three weeks ago no longer gates.
+	old := fixedSource{r: Reading{State: crisis.StateCrisis, Date: "2026-09-25"}, ok: true}
+	_, reason, _ = New(old, testPolicies()).Apply(buy, now)
+	assert.Empty(t, reason)
+	_, reason, _ = New(old, testPolicies(), WithMaxAge(30*24*time.Hour)).Apply(buy, now)
+	assert.Equal(t, ReasonCrisisState, reason)
+
+	got, reason, err = New(fixedSource{err: errors.New("db locked")}, testPolicies()).Apply(buy, now)
+	assert.ErrorContains(t, err, "db locked")
+	assert.Empty(t, reason)
+	assert.Equal(t, buy, got, "the signal passes unchanged on an error")
+}
+
+type latestStub struct {
+	e   *crisis.Evaluation
+	err error
+}
+
+func (l latestStub) LatestSystemEval(context.Context) (*crisis.Evaluation, error) { return l.e, l.err }
+
+func TestStoreSource(t *testing.T) {
+	r, ok, err := NewStoreSource(latestStub{e: &crisis.Evaluation{TS: "2026-10-15", SystemState: crisis.StateWatch}}).StateAt(now)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, Reading{State: crisis.StateWatch, Date: "2026-10-15"}, r)
+
+	_, ok, err = NewStoreSource(latestStub{}).StateAt(now)
+	assert.NoError(t, err)
+	assert.False(t, ok, "an empty store has no state")
+}
+
+func replayDay(date string, state crisis.SystemState) crisis.ReplayDay {
+	return crisis.ReplayDay{Date: date, Res: &crisis.DayResult{Date: date, State: state}}
+}
+
+func TestReplaySource(t *testing.T) {
+	loads := 0
+	src := NewReplaySource(func() ([]crisis.ReplayDay, error) {
+		loads++
+		return []crisis.ReplayDay{
+			replayDay("2020-03-02", crisis.StateWatch),
+			replayDay("2020-03-03", crisis.StateBrewing),
+			replayDay("2020-03-04", crisis.StateCrisis),
+		}, nil
+	})
+	at := func(date string) Reading {
+		d, _ := time.Parse("2006-01-02", date)
+		r, ok, err := src.StateAt(d.Add(16 * time.Hour))
+		require.NoError(t, err)
+		if !ok {
+			return Reading{}
+		}
+		return r
+	}
+	assert.Equal(t, Reading{}, at("2020-03-02"), "nothing is known before the first evaluated day")
+	assert.Equal(t, crisis.StateWatch, at("2020-03-03").State, "a bar sees the previous day's evaluation")
+	assert.Equal(t, Reading{State: crisis.StateCrisis, Date: "2020-03-04"}, at("2020-03-09"))
+	assert.Equal(t, 1, loads, "the replay is loaded once")
+
+	failing := NewReplaySource(func() ([]crisis.ReplayDay, error) { return nil, errors.New("no vix") })
+	_, _, err := failing.StateAt(now)
+	assert.ErrorContains(t, err, "no vix")
+}
@@ -0,0 +1,87 @@
+package crisisgate
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/newthinker/atlas/internal/crisis"
+)
+
+// LatestEvaluator is the part of *crisis.Store the live source reads.
+type LatestEvaluator interface {
+	LatestSystemEval(ctx context.Context) (*crisis.Evaluation, error)
+}
+
+// StoreSource is the live state source: the latest system evaluation in the
+// crisis store, whenever it is asked.
+type StoreSource struct {
+	store LatestEvaluator
+}
+
+// NewStoreSource creates a live source over the crisis store.
+func NewStoreSource(store LatestEvaluator) StoreSource {
+	return StoreSource{store: store}
+}
+
+// StateAt ignores at: the store holds only what has been evaluated so far.
+func (s StoreSource) StateAt(time.Time) (Reading, bool, error) {
+	e, err := s.store.LatestSystemEval(context.Background())
+	if err != nil || e == nil {
+		return Reading{}, false, err
+	}
+	return Reading{State: e.SystemState, Date: e.TS}, true, nil
+}
+
+// replayTTL is how long a loaded replay is reused before it is replayed
+// again to take in the days evaluated since.
+const replayTTL = 12 * time.Hour
+
+// ReplaySource is the backtest state source: the system states of a crisis
+// replay. A bar sees the state evaluated for the last trading day before it,
+// as the monitor evaluates each day's observations the next morning.
+type ReplaySource struct {
+	load func() ([]crisis.ReplayDay, error)
+
+	mu       sync.Mutex
+	days     []Reading // ascending by Date
+	loadedAt time.Time
+}
+
+// NewReplaySource creates a source over the replay load returns, typically
+// crisis.ReplayRange over the whole store. It is loaded on first use.
+func NewReplaySource(load func() ([]crisis.ReplayDay, error)) *ReplaySource {
+	return &ReplaySource{load: load}
+}
+
+func (s *ReplaySource) StateAt(at time.Time) (Reading, bool, error) {
+	days, err := s.readings()
+	if err != nil {
+		return Reading{}, false, err
+	}
+	date := at.Format("2006-01-02")
+	i := sort.Search(len(days), func(i int) bool { return days[i].Date >= date })
+	if i == 0 {
+		return Reading{}, false, nil
+	}
+	return days[i-1], true, nil
+}
+
+func (s *ReplaySource) readings() ([]Reading, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.days != nil && time.Since(s.loadedAt) < replayTTL {
+		return s.days, nil
+	}
+	replay, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	days := make([]Reading, 0, len(replay))
+	for _, d := range replay {
+		days = append(days, Reading{State: d.Res.State, Date: d.Date})
+	}
+	s.days, s.loadedAt = days, time.Now()
+	return days, nil
+}
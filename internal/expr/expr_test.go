@@ -0,0 +1,157 @@
+package expr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// mapEnv serves bar fields and indicators as series ending at the latest bar.
+type mapEnv struct {
+	series map[string][]float64
+	funds  map[string]float64
+}
+
+func at(series []float64, offset int) (float64, bool) {
+	i := len(series) - 1 - offset
+	if i < 0 {
+		return 0, false
+	}
+	return series[i], true
+}
+
+func (e mapEnv) Bar(field string, offset int) (float64, bool) { return at(e.series[field], offset) }
+func (e mapEnv) Indicator(key string, offset int) (float64, bool) {
+	return at(e.series[key], offset)
+}
+func (e mapEnv) Fundamental(field string) (float64, bool) {
+	v, ok := e.funds[field]
+	return v, ok
+}
+
+func mustCompile(t *testing.T, src string) *Program {
+	t.Helper()
+	p, err := Compile(src)
+	if err != nil {
+		t.Fatalf("Compile(%q): %v", src, err)
+	}
+	return p
+}
+
+func TestCompile_Metadata(t *testing.T) {
+	p := mustCompile(t, "close > sma(200) and RSI(14) < 30 and pe_percentile < 20 and bb.lower(20, 2.5) < close")
+	if p.Type() != Bool {
+		t.Errorf("Type = %v, want condition", p.Type())
+	}
+	if got := strings.Join(p.Indicators(), ","); got != "sma_200,rsi_14,bb_20_2.5" {
+		t.Errorf("Indicators = %q", got)
+	}
+	if !p.Fundamentals() {
+		t.Error("Fundamentals should be true for pe_percentile")
+	}
+	if p.Lookback() != 0 {
+		t.Errorf("Lookback = %d, want 0", p.Lookback())
+	}
+
+	p = mustCompile(t, "crosses_above(ema(12), prev(ema(26), 2)) or highest(high, 20) > 100")
+	if p.Lookback() != 19 || p.Fundamentals() {
+		t.Errorf("Lookback = %d Fundamentals = %v, want 19 false", p.Lookback(), p.Fundamentals())
+	}
+	if mustCompile(t, "(close - sma) / atr").Type() != Number {
+		t.Error("arithmetic should be a number")
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	cases := map[string]string{
+		"":                         "empty expression",
+		"close > ":                 "unexpected end of expression",
+		"close > smaa(200)":        `column 9: unknown function "smaa"`,
+		"closing > 1":              `unknown identifier "closing"`,
+		"rsi(0) < 30":              "invalid parameter",
+		"rsi(14, 3) < 30":          "at most 1 parameters",
+		"rsi(close) < 30":          "rsi parameters must be numbers",
+		"bb.middle(20) > close":    `bb has no output "middle"`,
+		"close and rsi < 30":       `"and" joins conditions, not numbers`,
+		"(close > 1) + 2":          `"+" needs numbers`,
+		"(close > 1) > 0":          "compares numbers",
+		"1 < close < 2":            "cannot be chained",
+		"not close":                "needs a condition",
+		"prev(close, 0) > 1":       "bar count must be a whole number of at least 1",
+		"highest(close, n) > 1":    "unknown identifier",
+		"abs(close, 1) > 1":        "abs takes 1 arguments, got 2",
+		"close > sma(200":          `expected ")" at end of expression`,
+		"close # 1":                `unexpected character '#'`,
+		"close > 1 close":          `column 11: unexpected "close"`,
+		"crosses_above(close > 1)": "crosses_above takes 2 arguments",
+	}
+	for src, want := range cases {
+		_, err := Compile(src)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Compile(%q) = %v, want error containing %q", src, err, want)
+		}
+		var e *Error
+		if err != nil && !errors.As(err, &e) {
+			t.Errorf("Compile(%q) error %T is not *Error", src, err)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	env := mapEnv{
+		series: map[string][]float64{
+			"close":   {90, 95, 110},
+			"high":    {92, 99, 111},
+			"sma_2":   {97, 102.5},
+			"rsi":     {25, 28, 35},
+			"sma_200": {100},
+		},
+		funds: map[string]float64{"pe_percentile": 15},
+	}
+	for src, want := range map[string]bool{
+		"close > sma(200) and pe_percentile < 20":            true,
+		"crosses_above(close, sma(2))":                       true,
+		"crosses_above(close, sma(2)) and rsi > 40":          false, // rsi is 35
+		"crosses_below(close, sma(2))":                       false,
+		"prev(rsi) < 30 and rsi >= 30":                       true,
+		"prev(close > 100)":                                  false,
+		"highest(high, 3) == 111 and lowest(close, 2) == 95": true,
+		"abs(close - 120) == 10 and max(close, 200) == 200":  true,
+		"not (close < 100) && !false":                        true,
+		"close / (close - close) > 1":                        false, // division by zero is unavailable
+	} {
+		p := mustCompile(t, src)
+		if got := p.True(env, 0); got != want {
+			t.Errorf("%q = %v, want %v", src, got, want)
+		}
+	}
+}
+
+func TestEval_UnavailableValues(t *testing.T) {
+	env := mapEnv{series: map[string][]float64{"close": {100}, "sma_200": {}}}
+	// Too little history: the comparison is unavailable, so neither it nor its
+	// negation holds.
+	for _, src := range []string{"close > sma(200)", "not (close > sma(200))", "pe < 10", "crosses_above(close, 50)"} {
+		if mustCompile(t, src).True(env, 0) {
+			t.Errorf("%q should not hold without data", src)
+		}
+	}
+	// A decided side of and/or does not need the other.
+	if !mustCompile(t, "close > 50 or sma(200) > 1").True(env, 0) {
+		t.Error("true or unavailable should hold")
+	}
+	if mustCompile(t, "close > 500 and sma(200) > 1").True(env, 0) {
+		t.Error("false and unavailable should not hold")
+	}
+}
+
+func TestTerms(t *testing.T) {
+	env := mapEnv{
+		series: map[string][]float64{"close": {100, 101}, "sma_200": {99}},
+		funds:  map[string]float64{},
+	}
+	terms := mustCompile(t, "close > sma(200) and close > 1 and pe < 20").Terms(env, 0)
+	if len(terms) != 2 || terms["close"] != 101 || terms["sma(200)"] != 99 {
+		t.Errorf("Terms = %v", terms)
+	}
+}
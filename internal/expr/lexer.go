@@ -0,0 +1,85 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp // operators and punctuation
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	pos  int // 1-based column
+}
+
+// lex splits src into tokens. Identifiers are case-insensitive and may
+// contain dots so indicator outputs read as bb.upper or macd.signal.
+func lex(src string) ([]token, error) {
+	var toks []token
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			text := string(runes[start:i])
+			v, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, errorAt(start+1, "invalid number %q", text)
+			}
+			toks = append(toks, token{kind: tokNumber, text: text, num: v, pos: start + 1})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			toks = append(toks, token{kind: tokIdent, text: strings.ToLower(string(runes[start:i])), pos: start + 1})
+		default:
+			op := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "<=", ">=", "==", "!=", "&&", "||":
+					op = two
+				}
+			}
+			switch op {
+			case "(", ")", ",", "+", "-", "*", "/", "<", ">", "<=", ">=", "==", "!=", "&&", "||", "!":
+			default:
+				return nil, errorAt(i+1, "unexpected character %q", r)
+			}
+			toks = append(toks, token{kind: tokOp, text: op, pos: i + 1})
+			i += len([]rune(op))
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(runes) + 1}), nil
+}
+
+// Error is a compile error at a column of the source.
+type Error struct {
+	Pos int // 1-based column
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Pos, e.Msg)
+}
+
+func errorAt(pos int, format string, args ...any) error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
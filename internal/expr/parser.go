@@ -0,0 +1,417 @@
+package expr
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/newthinker/atlas/internal/indicator"
+)
+
+// registry resolves indicator calls; expressions use the built-in indicators.
+var registry = indicator.NewRegistry()
+
+// barFields are the identifiers read from the OHLCV bars.
+var barFields = []string{"open", "high", "low", "close", "volume"}
+
+// fundamentalFields are the identifiers read from the symbol's fundamentals.
+var fundamentalFields = []string{
+	"pe", "pb", "ps", "roe", "roa", "eps", "dividend_yield", "market_cap", "pe_percentile",
+	"pb_percentile", "ps_percentile", "dividend_yield_percentile",
+}
+
+// builtins are the non-indicator functions with their minimum and maximum
+// argument counts.
+var builtins = map[string][2]int{
+	"prev":          {1, 2},
+	"crosses_above": {2, 2},
+	"crosses_below": {2, 2},
+	"highest":       {2, 2},
+	"lowest":        {2, 2},
+	"abs":           {1, 1},
+	"min":           {2, 2},
+	"max":           {2, 2},
+}
+
+// Compile parses and type-checks src. Errors name the offending column.
+func Compile(src string) (*Program, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, errorAt(1, "empty expression")
+	}
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, prog: &Program{src: src}}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, errorAt(t.pos, "unexpected %q", t.text)
+	}
+	p.prog.root = root
+	p.prog.lookback = root.lookback()
+	return p.prog, nil
+}
+
+type parser struct {
+	toks []token
+	i    int
+	prog *Program
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// accept consumes the next token when it is one of ops (operators or
+// keywords) and returns it.
+func (p *parser) accept(ops ...string) (token, bool) {
+	t := p.peek()
+	if (t.kind == tokOp || t.kind == tokIdent) && slices.Contains(ops, t.text) {
+		return p.next(), true
+	}
+	return token{}, false
+}
+
+func (p *parser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		t := p.peek()
+		if t.kind == tokEOF {
+			return errorAt(t.pos, "expected %q at end of expression", op)
+		}
+		return errorAt(t.pos, "expected %q, got %q", op, t.text)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("or", "||")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = logical(op, "or", left, right); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("and", "&&")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = logical(op, "and", left, right); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseNot() (node, error) {
+	if op, ok := p.accept("not", "!"); ok {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if x.typ() != Bool {
+			return nil, errorAt(op.pos, "%q needs a condition, got a number", op.text)
+		}
+		return &notNode{x: x}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("<", "<=", ">", ">=", "==", "!=")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if left.typ() != Number || right.typ() != Number {
+		return nil, errorAt(op.pos, "%q compares numbers, not conditions", op.text)
+	}
+	if next, ok := p.accept("<", "<=", ">", ">=", "==", "!="); ok {
+		return nil, errorAt(next.pos, "comparisons cannot be chained; join them with \"and\"")
+	}
+	return &compareNode{op: op.text, l: left, r: right}, nil
+}
+
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = arithmetic(op, left, right); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = arithmetic(op, left, right); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if op, ok := p.accept("-"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if x.typ() != Number {
+			return nil, errorAt(op.pos, "cannot negate a condition")
+		}
+		return &arithNode{op: "-", l: &numNode{}, r: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return &numNode{v: t.num}, nil
+	case tokEOF:
+		return nil, errorAt(t.pos, "unexpected end of expression")
+	case tokOp:
+		if t.text != "(" {
+			return nil, errorAt(t.pos, "unexpected %q", t.text)
+		}
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return x, nil
+	}
+
+	name := t.text
+	if p.peek().text == "(" && p.peek().kind == tokOp {
+		return p.parseCall(t, name)
+	}
+	switch {
+	case name == "true" || name == "false":
+		return &boolNode{v: name == "true"}, nil
+	case slices.Contains(barFields, name):
+		return p.prog.term(&barNode{field: name}), nil
+	case slices.Contains(fundamentalFields, name):
+		p.prog.fundamentals = true
+		return p.prog.term(&fundamentalNode{field: name}), nil
+	case slices.Contains(registry.Names(), indicatorName(name)):
+		return p.indicator(t, name, nil)
+	}
+	return nil, errorAt(t.pos, "unknown identifier %q (fields: %s; fundamentals: %s)",
+		t.text, strings.Join(barFields, ", "), strings.Join(fundamentalFields, ", "))
+}
+
+// parseCall parses the argument list of name and builds the call.
+func (p *parser) parseCall(t token, name string) (node, error) {
+	p.next() // "("
+	var args []node
+	var argPos []int
+	if _, ok := p.accept(")"); !ok {
+		for {
+			argPos = append(argPos, p.peek().pos)
+			a, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, a)
+			if _, ok := p.accept(","); ok {
+				continue
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	arity, isBuiltin := builtins[name]
+	if !isBuiltin {
+		if !slices.Contains(registry.Names(), indicatorName(name)) {
+			return nil, errorAt(t.pos, "unknown function %q (indicators: %s; functions: %s)",
+				t.text, strings.Join(registry.Names(), ", "), strings.Join(builtinNames(), ", "))
+		}
+		params := make([]float64, len(args))
+		for i, a := range args {
+			n, ok := a.(*numNode)
+			if !ok {
+				return nil, errorAt(argPos[i], "%s parameters must be numbers", name)
+			}
+			params[i] = n.v
+		}
+		return p.indicator(t, name, params)
+	}
+
+	if len(args) < arity[0] || len(args) > arity[1] {
+		want := strconv.Itoa(arity[0])
+		if arity[1] != arity[0] {
+			want += " or " + strconv.Itoa(arity[1])
+		}
+		return nil, errorAt(t.pos, "%s takes %s arguments, got %d", name, want, len(args))
+	}
+	for i, a := range args {
+		if a.typ() != Number && !(name == "prev" && i == 0) {
+			return nil, errorAt(argPos[i], "%s argument %d must be a number, not a condition", name, i+1)
+		}
+	}
+	// bars is the constant bar count of prev, highest and lowest.
+	bars := func(i, min int) (int, error) {
+		n, ok := args[i].(*numNode)
+		if !ok || n.v != float64(int(n.v)) || int(n.v) < min {
+			return 0, errorAt(argPos[i], "%s bar count must be a whole number of at least %d", name, min)
+		}
+		return int(n.v), nil
+	}
+
+	switch name {
+	case "prev":
+		n := 1
+		if len(args) == 2 {
+			var err error
+			if n, err = bars(1, 1); err != nil {
+				return nil, err
+			}
+		}
+		return &prevNode{x: args[0], n: n}, nil
+	case "crosses_above", "crosses_below":
+		return &crossNode{a: args[0], b: args[1], above: name == "crosses_above"}, nil
+	case "highest", "lowest":
+		n, err := bars(1, 1)
+		if err != nil {
+			return nil, err
+		}
+		return &extremeNode{x: args[0], n: n, highest: name == "highest"}, nil
+	default: // abs, min, max
+		return &mathNode{fn: name, args: args}, nil
+	}
+}
+
+// indicator resolves name(params) to a registry spec and output key.
+func (p *parser) indicator(t token, name string, params []float64) (node, error) {
+	base, part, _ := strings.Cut(name, ".")
+	spec := base
+	for _, v := range params {
+		spec += "_" + strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	parts, err := registry.Outputs(spec)
+	if err != nil {
+		return nil, errorAt(t.pos, "%v", err)
+	}
+	if !slices.Contains(parts, part) {
+		var named []string
+		for _, pt := range parts {
+			if pt != "" {
+				named = append(named, base+"."+pt)
+			}
+		}
+		if len(named) == 0 {
+			return nil, errorAt(t.pos, "%s has no output %q", base, part)
+		}
+		return nil, errorAt(t.pos, "%s has no output %q (outputs: %s, %s)", base, part, base, strings.Join(named, ", "))
+	}
+	key := spec
+	if part != "" {
+		key += "." + part
+	}
+	if !slices.Contains(p.prog.indicators, spec) {
+		p.prog.indicators = append(p.prog.indicators, spec)
+	}
+	text := name
+	if len(params) > 0 {
+		args := make([]string, len(params))
+		for i, v := range params {
+			args[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		text += "(" + strings.Join(args, ", ") + ")"
+	}
+	return p.prog.term(&indicatorNode{key: key, text: text}), nil
+}
+
+// indicatorName strips the output part from an indicator reference.
+func indicatorName(name string) string {
+	base, _, _ := strings.Cut(name, ".")
+	return base
+}
+
+func builtinNames() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func logical(op token, name string, l, r node) (node, error) {
+	if l.typ() != Bool || r.typ() != Bool {
+		return nil, errorAt(op.pos, "%q joins conditions, not numbers", op.text)
+	}
+	return &logicNode{and: name == "and", l: l, r: r}, nil
+}
+
+func arithmetic(op token, l, r node) (node, error) {
+	if l.typ() != Number || r.typ() != Number {
+		return nil, errorAt(op.pos, "%q needs numbers, not conditions", op.text)
+	}
+	return &arithNode{op: op.text, l: l, r: r}, nil
+}
@@ -0,0 +1,343 @@
+package expr
+
+import (
+	"math"
+)
+
+// Type is the type of an expression's value.
+type Type int
+
+const (
+	Number Type = iota
+	Bool
+)
+
+func (t Type) String() string {
+	if t == Bool {
+		return "condition"
+	}
+	return "number"
+}
+
+// Env supplies the values an expression reads. offset counts bars back from
+// the latest (0). ok is false when the value is unavailable, e.g. before an
+// indicator's warm-up or when fundamentals are missing.
+type Env interface {
+	Bar(field string, offset int) (v float64, ok bool)
+	Indicator(key string, offset int) (v float64, ok bool)
+	Fundamental(field string) (v float64, ok bool)
+}
+
+// Program is a compiled expression.
+type Program struct {
+	src          string
+	root         node
+	indicators   []string
+	fundamentals bool
+	lookback     int
+	terms        []term
+}
+
+// term is a leaf value reported alongside an evaluation.
+type term struct {
+	text string
+	node node
+}
+
+// Source returns the expression as written.
+func (p *Program) Source() string { return p.src }
+
+// Type returns the type of the expression's value.
+func (p *Program) Type() Type { return p.root.typ() }
+
+// Indicators returns the indicator specs the expression reads, in order of
+// first use.
+func (p *Program) Indicators() []string { return p.indicators }
+
+// Fundamentals reports whether the expression reads fundamental fields.
+func (p *Program) Fundamentals() bool { return p.fundamentals }
+
+// Lookback returns how many bars before the latest the expression reaches
+// back, e.g. 1 for crosses_above or prev.
+func (p *Program) Lookback() int { return p.lookback }
+
+// Eval evaluates the expression offset bars back. ok is false when a value
+// it depends on is unavailable; a condition then counts as not met.
+func (p *Program) Eval(env Env, offset int) (v float64, ok bool) {
+	return p.root.eval(env, offset)
+}
+
+// True reports whether a condition holds offset bars back. Unavailable values
+// make it false.
+func (p *Program) True(env Env, offset int) bool {
+	v, ok := p.root.eval(env, offset)
+	return ok && v != 0
+}
+
+// Terms returns the value of every field and indicator the expression reads,
+// keyed by their text, skipping unavailable ones.
+func (p *Program) Terms(env Env, offset int) map[string]float64 {
+	out := make(map[string]float64, len(p.terms))
+	for _, t := range p.terms {
+		if v, ok := t.node.eval(env, offset); ok {
+			out[t.text] = v
+		}
+	}
+	return out
+}
+
+// leaf is a node that reads a field or indicator.
+type leaf interface {
+	node
+	String() string
+}
+
+// term records n as a reported leaf and returns it.
+func (p *Program) term(n leaf) node {
+	text := n.String()
+	for _, t := range p.terms {
+		if t.text == text {
+			return n
+		}
+	}
+	p.terms = append(p.terms, term{text: text, node: n})
+	return n
+}
+
+type node interface {
+	typ() Type
+	eval(env Env, offset int) (float64, bool)
+	lookback() int
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type numNode struct{ v float64 }
+
+func (n *numNode) typ() Type                     { return Number }
+func (n *numNode) eval(Env, int) (float64, bool) { return n.v, true }
+func (n *numNode) lookback() int                 { return 0 }
+
+type boolNode struct{ v bool }
+
+func (n *boolNode) typ() Type                     { return Bool }
+func (n *boolNode) eval(Env, int) (float64, bool) { return boolValue(n.v), true }
+func (n *boolNode) lookback() int                 { return 0 }
+
+type barNode struct{ field string }
+
+func (n *barNode) typ() Type      { return Number }
+func (n *barNode) lookback() int  { return 0 }
+func (n *barNode) String() string { return n.field }
+func (n *barNode) eval(env Env, offset int) (float64, bool) {
+	return env.Bar(n.field, offset)
+}
+
+type fundamentalNode struct{ field string }
+
+func (n *fundamentalNode) typ() Type      { return Number }
+func (n *fundamentalNode) lookback() int  { return 0 }
+func (n *fundamentalNode) String() string { return n.field }
+
+// eval reads the latest fundamentals whatever the offset: they are not kept
+// per bar.
+func (n *fundamentalNode) eval(env Env, _ int) (float64, bool) {
+	return env.Fundamental(n.field)
+}
+
+type indicatorNode struct{ key, text string }
+
+func (n *indicatorNode) typ() Type      { return Number }
+func (n *indicatorNode) lookback() int  { return 0 }
+func (n *indicatorNode) String() string { return n.text }
+func (n *indicatorNode) eval(env Env, offset int) (float64, bool) {
+	return env.Indicator(n.key, offset)
+}
+
+type notNode struct{ x node }
+
+func (n *notNode) typ() Type     { return Bool }
+func (n *notNode) lookback() int { return n.x.lookback() }
+func (n *notNode) eval(env Env, offset int) (float64, bool) {
+	v, ok := n.x.eval(env, offset)
+	return boolValue(v == 0), ok
+}
+
+// logicNode is "and" / "or". An unavailable side only matters when the other
+// side does not decide the result on its own.
+type logicNode struct {
+	and  bool
+	l, r node
+}
+
+func (n *logicNode) typ() Type     { return Bool }
+func (n *logicNode) lookback() int { return max(n.l.lookback(), n.r.lookback()) }
+func (n *logicNode) eval(env Env, offset int) (float64, bool) {
+	l, lok := n.l.eval(env, offset)
+	if lok && (l != 0) != n.and {
+		return l, true // false and ..., true or ...
+	}
+	r, rok := n.r.eval(env, offset)
+	if rok && (r != 0) != n.and {
+		return r, true
+	}
+	return r, lok && rok
+}
+
+type compareNode struct {
+	op   string
+	l, r node
+}
+
+func (n *compareNode) typ() Type     { return Bool }
+func (n *compareNode) lookback() int { return max(n.l.lookback(), n.r.lookback()) }
+func (n *compareNode) eval(env Env, offset int) (float64, bool) {
+	l, lok := n.l.eval(env, offset)
+	r, rok := n.r.eval(env, offset)
+	if !lok || !rok {
+		return 0, false
+	}
+	switch n.op {
+	case "<":
+		return boolValue(l < r), true
+	case "<=":
+		return boolValue(l <= r), true
+	case ">":
+		return boolValue(l > r), true
+	case ">=":
+		return boolValue(l >= r), true
+	case "==":
+		return boolValue(l == r), true
+	default: // "!="
+		return boolValue(l != r), true
+	}
+}
+
+type arithNode struct {
+	op   string
+	l, r node
+}
+
+func (n *arithNode) typ() Type     { return Number }
+func (n *arithNode) lookback() int { return max(n.l.lookback(), n.r.lookback()) }
+func (n *arithNode) eval(env Env, offset int) (float64, bool) {
+	l, lok := n.l.eval(env, offset)
+	r, rok := n.r.eval(env, offset)
+	if !lok || !rok {
+		return 0, false
+	}
+	switch n.op {
+	case "+":
+		return l + r, true
+	case "-":
+		return l - r, true
+	case "*":
+		return l * r, true
+	default: // "/"
+		if r == 0 {
+			return 0, false
+		}
+		return l / r, true
+	}
+}
+
+// prevNode is prev(x, n): x as of n bars earlier.
+type prevNode struct {
+	x node
+	n int
+}
+
+func (n *prevNode) typ() Type     { return n.x.typ() }
+func (n *prevNode) lookback() int { return n.n + n.x.lookback() }
+func (n *prevNode) eval(env Env, offset int) (float64, bool) {
+	return n.x.eval(env, offset+n.n)
+}
+
+// crossNode is crosses_above(a, b) / crosses_below(a, b): a was on or below
+// (above) b on the previous bar and is above (below) it now.
+type crossNode struct {
+	a, b  node
+	above bool
+}
+
+func (n *crossNode) typ() Type     { return Bool }
+func (n *crossNode) lookback() int { return 1 + max(n.a.lookback(), n.b.lookback()) }
+func (n *crossNode) eval(env Env, offset int) (float64, bool) {
+	a, aok := n.a.eval(env, offset)
+	b, bok := n.b.eval(env, offset)
+	pa, paok := n.a.eval(env, offset+1)
+	pb, pbok := n.b.eval(env, offset+1)
+	if !aok || !bok || !paok || !pbok {
+		return 0, false
+	}
+	if n.above {
+		return boolValue(pa <= pb && a > b), true
+	}
+	return boolValue(pa >= pb && a < b), true
+}
+
+// extremeNode is highest(x, n) / lowest(x, n) over the last n bars.
+type extremeNode struct {
+	x       node
+	n       int
+	highest bool
+}
+
+func (n *extremeNode) typ() Type     { return Number }
+func (n *extremeNode) lookback() int { return n.n - 1 + n.x.lookback() }
+func (n *extremeNode) eval(env Env, offset int) (float64, bool) {
+	e := math.Inf(1)
+	if n.highest {
+		e = math.Inf(-1)
+	}
+	for i := 0; i < n.n; i++ {
+		v, ok := n.x.eval(env, offset+i)
+		if !ok {
+			return 0, false
+		}
+		if n.highest {
+			e = math.Max(e, v)
+		} else {
+			e = math.Min(e, v)
+		}
+	}
+	return e, true
+}
+
+// mathNode is abs, min or max.
+type mathNode struct {
+	fn   string
+	args []node
+}
+
+func (n *mathNode) typ() Type { return Number }
+func (n *mathNode) lookback() int {
+	lb := 0
+	for _, a := range n.args {
+		lb = max(lb, a.lookback())
+	}
+	return lb
+}
+func (n *mathNode) eval(env Env, offset int) (float64, bool) {
+	vals := make([]float64, len(n.args))
+	for i, a := range n.args {
+		v, ok := a.eval(env, offset)
+		if !ok {
+			return 0, false
+		}
+		vals[i] = v
+	}
+	switch n.fn {
+	case "abs":
+		return math.Abs(vals[0]), true
+	case "min":
+		return math.Min(vals[0], vals[1]), true
+	default: // "max"
+		return math.Max(vals[0], vals[1]), true
+	}
+}
@@ -370,6 +370,53 @@ func (s *Store) Preceding(ctx context.Context, period, periodType string, n int)
 	return out, nil
 }
 
+// PublishedBefore 返回 date 当天**之前**已发布的全部期次，每个业务键取那时的
+// 最新修订，按 (period, period_type) 升序。date 为 YYYY-MM-DD。
+//
+// 这是「我当时看到的」那一半（bitemporal.AsOfQuery），供回测与信号派生按时点
+// 取数：在 date 当天评估的信号只能用 date 之前发布的数据，之后才发布的修订对它
+// 不存在，而不是被「现在最好的估计」悄悄替换掉。
+//
+// # 为什么是严格早于，而 AsOfQuery 是 <=
+//
+// AsOfQuery 的 <= 回答的是「发布当天知道什么」，含当天发布的内容。信号评估不同：
+// 央行的报告常在交易时段后发布，当天的收盘价不可能已经反映它。这里把时点换成
+// date 的前一天再交给 AsOfQuery——published_at 是定宽 YYYY-MM-DD（periodRE 同族
+// 的 publishedAtRE 在 Save 里把关），所以「<= 前一天」与「< date」逐字等价，
+// 不必另写一份 as-of 查询。
+//
+// 不区分 period_type：月报与季报、半年报、年报一并返回，调用方按各自口径取用。
+func (s *Store) PublishedBefore(ctx context.Context, date string) ([]Observation, error) {
+	day, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return nil, fmt.Errorf("hestia store published before %s: %w", date, err)
+	}
+	asOf := day.AddDate(0, 0, -1).Format(time.DateOnly)
+
+	cols := slices.Concat(metaColumns, fieldOrder)
+	q := fmt.Sprintf("SELECT %s FROM (%s) ORDER BY period, period_type",
+		strings.Join(cols, ", "), bitemporal.AsOfQuery(s.spec))
+
+	rows, err := s.db.QueryContext(ctx, q, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("hestia store published before %s: %w", date, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var out []Observation
+	for rows.Next() {
+		obs, err := scanObservation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("hestia store published before %s: %w", date, err)
+		}
+		out = append(out, obs)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("hestia store published before %s: %w", date, err)
+	}
+	return out, nil
+}
+
 // scanObservation 把一行还原成 Observation。列顺序与 insertSQL 对称：
 // metaColumns 在前，fieldOrder 在后。
 //
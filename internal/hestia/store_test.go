@@ -381,7 +381,7 @@ func TestStoreExposesNoWriteMethods(t *testing.T) {
 	for i := range got {
 		got[i] = typ.Method(i).Name
 	}
-	want := []string{"Close", "DB", "HasArticle", "HasArticleInObservations", "HasPeriod", "Preceding", "RecentObservations", "RecentPending", "Save"}
+	want := []string{"Close", "DB", "HasArticle", "HasArticleInObservations", "HasPeriod", "Preceding", "PublishedBefore", "RecentObservations", "RecentPending", "Save"}
 	assert.Equalf(t, want, got,
 		"只应导出这 %d 个只读方法（%s）；出现 Insert/Upsert 等写口即违反单一写入口约束",
 		len(want), strings.Join(want, "、"))
@@ -434,7 +434,7 @@ func TestPackageExposesNoWriteFunctions(t *testing.T) {
 	// 同一事实的两个副本，改一处不会让另一处变红。它一度真的不一致：TASK-006 交付时
 	// 是「列表 16 项 vs 文案十七」，无人报警；后来加 "Ingest" 使列表变 17，**文案碰巧
 	// 变对了**。⇒ 「现在是对的」与「它被修好了」是两回事，而前者会让人停止追问。
-	want := []string{"DefaultThresholds", "Discover", "Ingest", "LoadConfig", "NewPBOCFetcher", "NewStore", "Parse", "RenderStatus", "Store.Close", "Store.DB", "Store.HasArticle", "Store.HasArticleInObservations", "Store.HasPeriod", "Store.Preceding", "Store.RecentObservations", "Store.RecentPending", "Store.Save", "Validate"}
+	want := []string{"DefaultThresholds", "Discover", "Ingest", "LoadConfig", "NewPBOCFetcher", "NewStore", "Parse", "RenderStatus", "Store.Close", "Store.DB", "Store.HasArticle", "Store.HasArticleInObservations", "Store.HasPeriod", "Store.Preceding", "Store.PublishedBefore", "Store.RecentObservations", "Store.RecentPending", "Store.Save", "Validate"}
 	// 用 Equalf 而不是 Equal + fmt.Sprintf：本文件不必为一句文案引入 fmt。
 	assert.Equalf(t, want, got,
 		"包的导出函数/方法必须恰好是这 %d 个——任何新增的包级写口（如 InsertRow）"+
@@ -590,6 +590,19 @@ func TestPackageExposesNoWriteFunctions(t *testing.T) {
 //
 // 排在 Discover 之后、LoadConfig 之前是字节序结果（"Di" < "In" < "Lo"）。
 //
+// —— 为什么名单里多了 Store.PublishedBefore（cn_liquidity 策略追加）——
+//
+// 同样是登记而不是放宽：切片十七→十八，assert.Equal 的全导出面精确集合相等一字未动。
+// PublishedBefore 是 Store 的又一个**读**方法：一条包在 bitemporal.AsOfQuery 外面的
+// SELECT，不碰任何写路径。它是派生序列（M1–M2 剪刀差、社融脉冲、信贷脉冲）按时点取数
+// 的入口——策略在某天评估时只能看见那天之前发布的数据。
+//
+// 它与 Store.Preceding/Store.HasArticle 同形：**是 *Store 的方法，所以同时打红本条
+// （AST 版）与 TestStoreExposesNoWriteMethods（reflect 版）**，两条都登记过才算数。
+//
+// 排在 Store.Preceding 之后、Store.RecentObservations 之前是字节序结果
+// （"Pre" < "Pu" < "R"）。
+//
 // ⚠️ **本次登记撞上了一次真实的并发冲突，记在这里免得后人重蹈**：TASK-005 与 TASK-006
 // 同在 wave2、同一棵工作树，而两者都必须改这同一行。本条的作用域是**全包**（go/parser
 // 扫全部非 _test.go 文件），所以任一方的新导出物一落盘，另一方的包测试立刻红 —— 双向、
@@ -1841,6 +1854,55 @@ func TestPrecedingWrapsQueryError(t *testing.T) {
 	assert.NotEqual(t, context.Canceled, err, "应是包裹后的错误而不是裸 sentinel")
 }
 
+// PublishedBefore 是时点读：date 当天及之后发布的行（含修订）一律不可见，
+// 每个业务键取那时的最新修订，各 period_type 一并返回。
+func TestPublishedBeforeSeesOnlyEarlierPublications(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+	saveMonthly(t, s, "2025-11", map[string]float64{FieldM2: 300}) // 2025-11-15 发布
+	saveMonthly(t, s, "2025-12", map[string]float64{FieldM2: 310}) // 2025-12-15 发布
+	_, err := s.Save(ctx, Observation{
+		Meta: Meta{
+			Period: "2025-12", PeriodType: "annual", PublishedAt: "2026-01-14",
+			ArticleID: "art-2025-annual", CaliberVersion: "2025-01", Extractor: extractorV2,
+		},
+		Values: map[string]float64{FieldTSFFlowYTD: 356000},
+	}, passing())
+	require.NoError(t, err)
+	_, err = s.Save(ctx, Observation{
+		Meta: Meta{
+			Period: "2025-11", PeriodType: "monthly", PublishedAt: "2026-01-20",
+			ArticleID: "art-2025-11-rev", CaliberVersion: "2025-01", Extractor: extractorV2,
+		},
+		Values: map[string]float64{FieldM2: 305},
+	}, passing())
+	require.NoError(t, err)
+
+	got, err := s.PublishedBefore(ctx, "2025-12-15")
+	require.NoError(t, err)
+	require.Len(t, got, 1, "当天发布的 2025-12 不可见：严格早于")
+	assert.Equal(t, "2025-11", got[0].Meta.Period)
+
+	got, err = s.PublishedBefore(ctx, "2026-01-20")
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, 300.0, got[0].Values[FieldM2], "修订当天仍只看得到原值")
+	assert.Equal(t, "2025-12", got[1].Meta.Period)
+	assert.Equal(t, "annual", got[1].Meta.PeriodType, "按 (period, period_type) 升序")
+	assert.Equal(t, "monthly", got[2].Meta.PeriodType)
+
+	got, err = s.PublishedBefore(ctx, "2026-01-21")
+	require.NoError(t, err)
+	require.Len(t, got, 3, "修订不产生第二期")
+	assert.Equal(t, 305.0, got[0].Values[FieldM2], "修订发布之后取修订值")
+}
+
+func TestPublishedBeforeRejectsMalformedDate(t *testing.T) {
+	s := newTestStore(t)
+	_, err := s.PublishedBefore(context.Background(), "2026-1-5")
+	assert.ErrorContains(t, err, "2026-1-5")
+}
+
 // Store 必须满足 History。签名一旦漂移，这行在编译期就红。
 var _ History = (*Store)(nil)
 
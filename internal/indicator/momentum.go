@@ -0,0 +1,128 @@
+package indicator
+
+import "github.com/newthinker/atlas/internal/core"
+
+// RSI calculates Wilder's Relative Strength Index (0-100).
+// Returns slice of length: len(prices) - period
+func RSI(prices []float64, period int) []float64 {
+	if period <= 0 || len(prices) <= period {
+		return []float64{}
+	}
+
+	result := make([]float64, 0, len(prices)-period)
+	p := float64(period)
+
+	// Seed with the simple average gain and loss of the first period changes
+	var gain, loss float64
+	for i := 1; i <= period; i++ {
+		up, down := change(prices[i-1], prices[i])
+		gain += up
+		loss += down
+	}
+	gain /= p
+	loss /= p
+	result = append(result, rsiValue(gain, loss))
+
+	// Wilder smoothing for the rest
+	for i := period + 1; i < len(prices); i++ {
+		up, down := change(prices[i-1], prices[i])
+		gain = (gain*(p-1) + up) / p
+		loss = (loss*(p-1) + down) / p
+		result = append(result, rsiValue(gain, loss))
+	}
+
+	return result
+}
+
+// change splits a price move into its gain and loss parts.
+func change(prev, cur float64) (up, down float64) {
+	d := cur - prev
+	if d > 0 {
+		return d, 0
+	}
+	return 0, -d
+}
+
+func rsiValue(gain, loss float64) float64 {
+	if loss == 0 {
+		if gain == 0 {
+			return 50 // flat market
+		}
+		return 100
+	}
+	return 100 - 100/(1+gain/loss)
+}
+
+// MACDResult holds the MACD line, its signal line and their difference, all
+// of the same length and ending at the last price.
+type MACDResult struct {
+	MACD      []float64
+	Signal    []float64
+	Histogram []float64
+}
+
+// MACD calculates Moving Average Convergence/Divergence: EMA(fast) - EMA(slow)
+// and an EMA(signal) of that line.
+// Returns series of length: len(prices) - slow - signal + 2
+func MACD(prices []float64, fast, slow, signal int) MACDResult {
+	if fast <= 0 || slow <= fast || signal <= 0 || len(prices) < slow+signal-1 {
+		return MACDResult{MACD: []float64{}, Signal: []float64{}, Histogram: []float64{}}
+	}
+
+	fastEMA := EMA(prices, fast)
+	slowEMA := EMA(prices, slow)
+	offset := slow - fast
+	line := make([]float64, len(slowEMA))
+	for i := range slowEMA {
+		line[i] = fastEMA[i+offset] - slowEMA[i]
+	}
+
+	sig := EMA(line, signal)
+	line = line[len(line)-len(sig):]
+	hist := make([]float64, len(sig))
+	for i := range sig {
+		hist[i] = line[i] - sig[i]
+	}
+
+	return MACDResult{MACD: line, Signal: sig, Histogram: hist}
+}
+
+// StochasticResult holds the %K and %D lines, of the same length and ending at
+// the last bar.
+type StochasticResult struct {
+	K []float64
+	D []float64
+}
+
+// Stochastic calculates the stochastic oscillator: %K is where the close sits
+// in the high-low range of the last kPeriod bars (0-100), %D the dPeriod SMA
+// of %K.
+// Returns series of length: len(bars) - kPeriod - dPeriod + 2
+func Stochastic(bars []core.OHLCV, kPeriod, dPeriod int) StochasticResult {
+	if kPeriod <= 0 || dPeriod <= 0 || len(bars) < kPeriod+dPeriod-1 {
+		return StochasticResult{K: []float64{}, D: []float64{}}
+	}
+
+	k := make([]float64, 0, len(bars)-kPeriod+1)
+	for i := kPeriod - 1; i < len(bars); i++ {
+		high, low := highLow(bars[i-kPeriod+1 : i+1])
+		if high == low {
+			k = append(k, 50)
+			continue
+		}
+		k = append(k, (bars[i].Close-low)/(high-low)*100)
+	}
+
+	d := SMA(k, dPeriod)
+	return StochasticResult{K: k[len(k)-len(d):], D: d}
+}
+
+// highLow returns the highest high and lowest low of bars.
+func highLow(bars []core.OHLCV) (high, low float64) {
+	high, low = bars[0].High, bars[0].Low
+	for _, b := range bars[1:] {
+		high = max(high, b.High)
+		low = min(low, b.Low)
+	}
+	return high, low
+}
@@ -0,0 +1,80 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/newthinker/atlas/internal/core"
+)
+
+// flatBars builds bars whose high, low and close all equal the given closes.
+func flatBars(closes ...float64) []core.OHLCV {
+	bars := make([]core.OHLCV, len(closes))
+	for i, c := range closes {
+		bars[i] = core.OHLCV{Open: c, High: c, Low: c, Close: c}
+	}
+	return bars
+}
+
+func TestRSI_Calculate(t *testing.T) {
+	// Changes +1,-1,+1,-1. Seed: gain 0.5, loss 0.5 → 50.
+	// Then gain 0.75 / loss 0.25 → 75; gain 0.375 / loss 0.625 → 37.5.
+	rsi := RSI([]float64{1, 2, 1, 2, 1}, 2)
+	expected := []float64{50, 75, 37.5}
+	if len(rsi) != len(expected) {
+		t.Fatalf("expected %d values, got %d", len(expected), len(rsi))
+	}
+	for i, v := range expected {
+		if !almostEqual(rsi[i], v, 1e-9) {
+			t.Errorf("rsi[%d] = %f, want %f", i, rsi[i], v)
+		}
+	}
+}
+
+func TestRSI_Extremes(t *testing.T) {
+	if rsi := RSI([]float64{1, 2, 3, 4}, 2); rsi[len(rsi)-1] != 100 {
+		t.Errorf("rising RSI = %v, want 100", rsi)
+	}
+	if rsi := RSI([]float64{4, 3, 2, 1}, 2); rsi[len(rsi)-1] != 0 {
+		t.Errorf("falling RSI = %v, want 0", rsi)
+	}
+	if rsi := RSI([]float64{1, 2}, 2); len(rsi) != 0 {
+		t.Errorf("expected empty slice, got %d values", len(rsi))
+	}
+}
+
+func TestMACD_Calculate(t *testing.T) {
+	prices := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	m := MACD(prices, 2, 3, 2)
+
+	// 10 - 3 - 2 + 2 = 7 aligned values
+	if len(m.MACD) != 7 || len(m.Signal) != 7 || len(m.Histogram) != 7 {
+		t.Fatalf("lengths = %d/%d/%d, want 7", len(m.MACD), len(m.Signal), len(m.Histogram))
+	}
+	for i := range m.MACD {
+		if m.MACD[i] <= 0 {
+			t.Errorf("MACD[%d] = %f, want positive in an uptrend", i, m.MACD[i])
+		}
+		if !almostEqual(m.Histogram[i], m.MACD[i]-m.Signal[i], 1e-12) {
+			t.Errorf("Histogram[%d] != MACD - Signal", i)
+		}
+	}
+
+	if m := MACD(prices[:3], 2, 3, 2); len(m.MACD) != 0 {
+		t.Errorf("expected empty result, got %d values", len(m.MACD))
+	}
+}
+
+func TestStochastic_Calculate(t *testing.T) {
+	// %K(3): [3,1,2]→50, [1,2,5]→100, [2,5,4]→66.67; %D(2): 75, 83.33
+	s := Stochastic(flatBars(3, 1, 2, 5, 4), 3, 2)
+	wantK := []float64{100, 200.0 / 3}
+	wantD := []float64{75, 250.0 / 3}
+	if len(s.K) != 2 || len(s.D) != 2 {
+		t.Fatalf("lengths = %d/%d, want 2", len(s.K), len(s.D))
+	}
+	for i := range wantK {
+		if !almostEqual(s.K[i], wantK[i], 1e-9) || !almostEqual(s.D[i], wantD[i], 1e-9) {
+			t.Errorf("[%d] K=%f D=%f, want %f / %f", i, s.K[i], s.D[i], wantK[i], wantD[i])
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package indicator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/newthinker/atlas/internal/core"
+)
+
+// Indicator specs are what strategies declare in
+// DataRequirements.Indicators: a registered name optionally followed by
+// underscore-separated parameters, e.g. "rsi", "rsi_14", "macd_12_26_9" or
+// "bb_20_2.5". Missing trailing parameters take the indicator's defaults.
+// Periods and lengths must be whole numbers; only parameters such as the
+// Bollinger width may be fractional.
+//
+// Compute keys each result by the spec exactly as declared. Indicators with
+// several outputs add one series per extra output under "<spec>.<part>", e.g.
+// "macd_12_26_9" (the MACD line), "macd_12_26_9.signal" and
+// "macd_12_26_9.hist". Every series ends at the last bar; a series is empty
+// when there are not enough bars to compute it.
+
+// Func computes an indicator over bars with its full parameter list. The
+// returned map holds the primary series under "" and any extra outputs under
+// their part names.
+type Func func(bars []core.OHLCV, params []float64) map[string][]float64
+
+type definition struct {
+	defaults   []float64
+	fractional []int // indexes of the parameters that need not be whole
+	compute    Func
+}
+
+// Registry maps indicator names to their implementations.
+type Registry struct {
+	mu   sync.RWMutex
+	defs map[string]definition
+}
+
+// NewRegistry creates a registry with the built-in indicators:
+//
+//	sma_<period=20>, ema_<period=20>, rsi_<period=14>,
+//	macd_<fast=12>_<slow=26>_<signal=9>  (.signal, .hist),
+//	bb_<period=20>_<k=2>                 (middle band; .upper, .lower),
+//	atr_<period=14>,
+//	stoch_<k=14>_<d=3>                   (%K; .d),
+//	obv,
+//	adx_<period=14>                      (.plus_di, .minus_di),
+//	donchian_<period=20>                 (middle; .upper, .lower),
+//	zscore_<period=20>                   (of the close)
+//
+// All price-based indicators use the close.
+func NewRegistry() *Registry {
+	r := &Registry{defs: make(map[string]definition)}
+	r.Register("sma", []float64{20}, func(bars []core.OHLCV, p []float64) map[string][]float64 {
+		return map[string][]float64{"": SMA(Closes(bars), int(p[0]))}
+	})
+	r.Register("ema", []float64{20}, func(bars []core.OHLCV, p []float64) map[string][]float64 {
+		return map[string][]float64{"": EMA(Closes(bars), int(p[0]))}
+	})
+	r.Register("rsi", []float64{14}, func(bars []core.OHLCV, p []float64) map[string][]float64 {
+		return map[string][]float64{"": RSI(Closes(bars), int(p[0]))}
+	})
+	r.Register("macd", []float64{12, 26, 9}, func(bars []core.OHLCV, p []float64) map[string][]float64 {
+		m := MACD(Closes(bars), int(p[0]), int(p[1]), int(p[2]))
+		return map[string][]float64{"": m.MACD, "signal": m.Signal, "hist": m.Histogram}
+	})
+	r.Register("bb", []float64{20, 2}, func(bars []core.OHLCV, p []float64) map[string][]float64 {
+		b := Bollinger(Closes(bars), int(p[0]), p[1])
+		return map[string][]float64{"": b.Middle, "upper": b.Upper, "lower": b.Lower}
+	}, 1)
+	r.Register("atr", []float64{14}, func(bars []core.OHLCV, p []float64) map[string][]float64 {
+		return map[string][]float64{"": ATR(bars, int(p[0]))}
+	})
+	r.Register("stoch", []float64{14, 3}, func(bars []core.OHLCV, p []float64) map[string][]float64 {
+		s := Stochastic(bars, int(p[0]), int(p[1]))
+		return map[string][]float64{"": s.K, "d": s.D}
+	})
+	r.Register("obv", nil, func(bars []core.OHLCV, p []float64) map[string][]float64 {
+		return map[string][]float64{"": OBV(bars)}
+	})
+	r.Register("adx", []float64{14}, func(bars []core.OHLCV, p []float64) map[string][]float64 {
+		a := ADX(bars, int(p[0]))
+		return map[string][]float64{"": a.ADX, "plus_di": a.PlusDI, "minus_di": a.MinusDI}
+	})
+	r.Register("donchian", []float64{20}, func(bars []core.OHLCV, p []float64) map[string][]float64 {
+		b := Donchian(bars, int(p[0]))
+		return map[string][]float64{"": b.Middle, "upper": b.Upper, "lower": b.Lower}
+	})
+	r.Register("zscore", []float64{20}, func(bars []core.OHLCV, p []float64) map[string][]float64 {
+		return map[string][]float64{"": ZScore(Closes(bars), int(p[0]))}
+	})
+	return r
+}
+
+// Register adds or replaces an indicator. name must not contain "_"; defaults
+// fixes the number of parameters a spec may give. Parameters must be whole
+// numbers unless their index is listed in fractional.
+func (r *Registry) Register(name string, defaults []float64, fn Func, fractional ...int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defs[name] = definition{defaults: defaults, fractional: fractional, compute: fn}
+}
+
+// Names returns the registered indicator names, sorted.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.defs))
+	for name := range r.defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate reports whether spec names a registered indicator with valid
+// parameters.
+func (r *Registry) Validate(spec string) error {
+	_, _, err := r.parse(spec)
+	return err
+}
+
+// Outputs returns the part names spec produces, sorted, with "" for the
+// primary series.
+func (r *Registry) Outputs(spec string) ([]string, error) {
+	def, params, err := r.parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	parts := make([]string, 0, 3)
+	for part := range def.compute(nil, params) {
+		parts = append(parts, part)
+	}
+	sort.Strings(parts)
+	return parts, nil
+}
+
+// Compute evaluates every spec once over bars; duplicate specs are computed
+// once. Specs that fail to parse are skipped and reported together in the
+// error, while the others are still returned.
+func (r *Registry) Compute(bars []core.OHLCV, specs []string) (map[string][]float64, error) {
+	out := make(map[string][]float64)
+	var errs []error
+	for _, spec := range specs {
+		if _, done := out[spec]; done {
+			continue
+		}
+		def, params, err := r.parse(spec)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for part, series := range def.compute(bars, params) {
+			key := spec
+			if part != "" {
+				key = spec + "." + part
+			}
+			out[key] = series
+		}
+	}
+	return out, errors.Join(errs...)
+}
+
+// parse resolves spec to its definition and full parameter list.
+func (r *Registry) parse(spec string) (definition, []float64, error) {
+	fields := strings.Split(spec, "_")
+	r.mu.RLock()
+	def, ok := r.defs[fields[0]]
+	r.mu.RUnlock()
+	if !ok {
+		return definition{}, nil, fmt.Errorf("unknown indicator %q", spec)
+	}
+	args := fields[1:]
+	if len(args) > len(def.defaults) {
+		return definition{}, nil, fmt.Errorf("indicator %q: %s takes at most %d parameters", spec, fields[0], len(def.defaults))
+	}
+	params := append([]float64(nil), def.defaults...)
+	for i, a := range args {
+		v, err := strconv.ParseFloat(a, 64)
+		if err != nil || v <= 0 {
+			return definition{}, nil, fmt.Errorf("indicator %q: invalid parameter %q", spec, a)
+		}
+		if v != math.Trunc(v) && !slices.Contains(def.fractional, i) {
+			return definition{}, nil, fmt.Errorf("indicator %q: parameter %q must be a whole number", spec, a)
+		}
+		params[i] = v
+	}
+	return def, params, nil
+}
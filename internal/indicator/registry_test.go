@@ -0,0 +1,68 @@
+package indicator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistry_Compute(t *testing.T) {
+	var closes []float64
+	for i := 0; i < 40; i++ {
+		closes = append(closes, float64(10+i%3+i/2)) // zig-zag uptrend
+	}
+	bars := flatBars(closes...)
+	r := NewRegistry()
+
+	got, err := r.Compute(bars, []string{"rsi_2", "rsi_2", "macd", "bb_5_1.5", "obv", "bogus", "rsi_x"})
+	if err == nil || !strings.Contains(err.Error(), `"bogus"`) || !strings.Contains(err.Error(), `"rsi_x"`) {
+		t.Errorf("expected errors for the invalid specs, got %v", err)
+	}
+
+	rsi := RSI(Closes(bars), 2)
+	if s := got["rsi_2"]; len(s) != len(rsi) || s[len(s)-1] != rsi[len(rsi)-1] {
+		t.Errorf("rsi_2 = %v, want %v", s, rsi)
+	}
+	for _, key := range []string{"macd", "macd.signal", "macd.hist", "bb_5_1.5", "bb_5_1.5.upper", "bb_5_1.5.lower", "obv"} {
+		if len(got[key]) == 0 {
+			t.Errorf("missing series %q", key)
+		}
+	}
+	// Defaults fill the missing parameters: macd = macd_12_26_9
+	if want := MACD(Closes(bars), 12, 26, 9).MACD; len(got["macd"]) != len(want) {
+		t.Errorf("macd length = %d, want %d", len(got["macd"]), len(want))
+	}
+	if _, ok := got["bogus"]; ok {
+		t.Error("invalid spec should not produce a series")
+	}
+}
+
+func TestRegistry_Validate(t *testing.T) {
+	r := NewRegistry()
+	for _, spec := range []string{"rsi", "rsi_14", "macd_12_26_9", "bb_20_2.5", "obv", "donchian_55"} {
+		if err := r.Validate(spec); err != nil {
+			t.Errorf("Validate(%q) = %v", spec, err)
+		}
+	}
+	for _, spec := range []string{"", "rsi_0", "rsi_14_3", "obv_5", "vwap", "sma_0.5", "rsi_14.5", "bb_20.5_2", "macd_12_26_9.5"} {
+		if err := r.Validate(spec); err == nil {
+			t.Errorf("Validate(%q) should fail", spec)
+		}
+	}
+	if err := r.Validate("sma_0.5"); err == nil || !strings.Contains(err.Error(), "whole number") {
+		t.Errorf("Validate(sma_0.5) = %v, want a whole-number error", err)
+	}
+}
+
+func TestRegistry_Outputs(t *testing.T) {
+	r := NewRegistry()
+	parts, err := r.Outputs("macd_12_26_9")
+	if err != nil || strings.Join(parts, ",") != ",hist,signal" {
+		t.Errorf("Outputs(macd) = %q, %v", parts, err)
+	}
+	if parts, _ := r.Outputs("rsi"); len(parts) != 1 || parts[0] != "" {
+		t.Errorf("Outputs(rsi) = %q", parts)
+	}
+	if _, err := r.Outputs("vwap"); err == nil {
+		t.Error("Outputs(vwap) should fail")
+	}
+}
@@ -0,0 +1,32 @@
+package indicator
+
+import "github.com/newthinker/atlas/internal/core"
+
+// ZScore calculates the rolling z-score: how many population standard
+// deviations each value lies from the mean of its trailing period window
+// (itself included). A flat window scores 0.
+// Returns slice of length: len(values) - period + 1
+func ZScore(values []float64, period int) []float64 {
+	if period <= 0 || len(values) < period {
+		return []float64{}
+	}
+
+	means := SMA(values, period)
+	result := make([]float64, len(means))
+	for i, m := range means {
+		if sd := stddev(values[i:i+period], m); sd > 0 {
+			result[i] = (values[i+period-1] - m) / sd
+		}
+	}
+
+	return result
+}
+
+// Closes extracts the close prices of bars.
+func Closes(bars []core.OHLCV) []float64 {
+	closes := make([]float64, len(bars))
+	for i, b := range bars {
+		closes[i] = b.Close
+	}
+	return closes
+}
@@ -0,0 +1,17 @@
+package indicator
+
+import "testing"
+
+func TestZScore_Calculate(t *testing.T) {
+	// Window [1,2,3]: mean 2, population sd sqrt(2/3)
+	z := ZScore([]float64{1, 2, 3, 3}, 3)
+	if len(z) != 2 {
+		t.Fatalf("expected 2 values, got %d", len(z))
+	}
+	if !almostEqual(z[0], 1.224744871, 1e-6) {
+		t.Errorf("z[0] = %f, want 1.2247", z[0])
+	}
+	if flat := ZScore([]float64{5, 5, 5}, 3); flat[0] != 0 {
+		t.Errorf("flat window z = %f, want 0", flat[0])
+	}
+}
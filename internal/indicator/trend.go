@@ -0,0 +1,62 @@
+package indicator
+
+import (
+	"math"
+
+	"github.com/newthinker/atlas/internal/core"
+)
+
+// ADXResult holds the Average Directional Index and the directional indicators
+// it is built from, all of the same length and ending at the last bar.
+type ADXResult struct {
+	ADX     []float64
+	PlusDI  []float64
+	MinusDI []float64
+}
+
+// ADX calculates Wilder's Average Directional Index (0-100) with the +DI and
+// -DI lines.
+// Returns series of length: len(bars) - 2*period + 1
+func ADX(bars []core.OHLCV, period int) ADXResult {
+	if period <= 0 || len(bars) < 2*period {
+		return ADXResult{ADX: []float64{}, PlusDI: []float64{}, MinusDI: []float64{}}
+	}
+
+	// Directional movement and true range of each bar against the previous one
+	n := len(bars) - 1
+	plusDM := make([]float64, n)
+	minusDM := make([]float64, n)
+	tr := TrueRange(bars)[1:]
+	for i := 1; i < len(bars); i++ {
+		up := bars[i].High - bars[i-1].High
+		down := bars[i-1].Low - bars[i].Low
+		if up > down && up > 0 {
+			plusDM[i-1] = up
+		}
+		if down > up && down > 0 {
+			minusDM[i-1] = down
+		}
+	}
+
+	// Wilder-smoothed averages; the ratios equal those of Wilder's running sums
+	sPlus := wilder(plusDM, period)
+	sMinus := wilder(minusDM, period)
+	sTR := wilder(tr, period)
+
+	plusDI := make([]float64, len(sTR))
+	minusDI := make([]float64, len(sTR))
+	dx := make([]float64, len(sTR))
+	for i := range sTR {
+		if sTR[i] > 0 {
+			plusDI[i] = sPlus[i] / sTR[i] * 100
+			minusDI[i] = sMinus[i] / sTR[i] * 100
+		}
+		if sum := plusDI[i] + minusDI[i]; sum > 0 {
+			dx[i] = math.Abs(plusDI[i]-minusDI[i]) / sum * 100
+		}
+	}
+
+	adx := wilder(dx, period)
+	skip := len(dx) - len(adx)
+	return ADXResult{ADX: adx, PlusDI: plusDI[skip:], MinusDI: minusDI[skip:]}
+}
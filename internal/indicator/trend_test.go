@@ -0,0 +1,37 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/newthinker/atlas/internal/core"
+)
+
+func TestADX_StrongUptrend(t *testing.T) {
+	// Every bar makes a higher high and a higher low: all movement is +DM.
+	var bars []core.OHLCV
+	for i := 0; i < 10; i++ {
+		f := float64(i)
+		bars = append(bars, core.OHLCV{High: f + 1, Low: f, Close: f + 0.5})
+	}
+	a := ADX(bars, 3)
+
+	// 10 - 2*3 + 1 = 5 aligned values
+	if len(a.ADX) != 5 || len(a.PlusDI) != 5 || len(a.MinusDI) != 5 {
+		t.Fatalf("lengths = %d/%d/%d, want 5", len(a.ADX), len(a.PlusDI), len(a.MinusDI))
+	}
+	for i := range a.ADX {
+		if !almostEqual(a.ADX[i], 100, 1e-9) || a.MinusDI[i] != 0 {
+			t.Errorf("[%d] ADX=%f -DI=%f, want 100 / 0", i, a.ADX[i], a.MinusDI[i])
+		}
+		// +DM 1 over a true range of 1.5
+		if !almostEqual(a.PlusDI[i], 200.0/3, 1e-9) {
+			t.Errorf("[%d] +DI = %f, want 66.67", i, a.PlusDI[i])
+		}
+	}
+}
+
+func TestADX_NotEnoughData(t *testing.T) {
+	if a := ADX(make([]core.OHLCV, 5), 3); len(a.ADX) != 0 {
+		t.Errorf("expected empty result, got %d values", len(a.ADX))
+	}
+}
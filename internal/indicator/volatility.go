@@ -0,0 +1,113 @@
+package indicator
+
+import (
+	"math"
+
+	"github.com/newthinker/atlas/internal/core"
+)
+
+// Bands holds an upper, middle and lower band, all of the same length and
+// ending at the last input.
+type Bands struct {
+	Upper  []float64
+	Middle []float64
+	Lower  []float64
+}
+
+// Bollinger calculates Bollinger Bands: the period SMA, plus and minus k
+// population standard deviations of the same window.
+// Returns bands of length: len(prices) - period + 1
+func Bollinger(prices []float64, period int, k float64) Bands {
+	if period <= 0 || len(prices) < period {
+		return Bands{Upper: []float64{}, Middle: []float64{}, Lower: []float64{}}
+	}
+
+	middle := SMA(prices, period)
+	upper := make([]float64, len(middle))
+	lower := make([]float64, len(middle))
+	for i, m := range middle {
+		sd := stddev(prices[i:i+period], m)
+		upper[i] = m + k*sd
+		lower[i] = m - k*sd
+	}
+
+	return Bands{Upper: upper, Middle: middle, Lower: lower}
+}
+
+// Donchian calculates the Donchian Channel: the highest high and lowest low of
+// the last period bars, and their midpoint.
+// Returns bands of length: len(bars) - period + 1
+func Donchian(bars []core.OHLCV, period int) Bands {
+	if period <= 0 || len(bars) < period {
+		return Bands{Upper: []float64{}, Middle: []float64{}, Lower: []float64{}}
+	}
+
+	n := len(bars) - period + 1
+	b := Bands{Upper: make([]float64, n), Middle: make([]float64, n), Lower: make([]float64, n)}
+	for i := 0; i < n; i++ {
+		high, low := highLow(bars[i : i+period])
+		b.Upper[i] = high
+		b.Lower[i] = low
+		b.Middle[i] = (high + low) / 2
+	}
+
+	return b
+}
+
+// TrueRange calculates each bar's true range: the high-low range widened to
+// the previous close. The first bar has no previous close and uses high-low.
+// Returns slice of length: len(bars)
+func TrueRange(bars []core.OHLCV) []float64 {
+	result := make([]float64, len(bars))
+	for i, b := range bars {
+		tr := b.High - b.Low
+		if i > 0 {
+			prev := bars[i-1].Close
+			tr = math.Max(tr, math.Max(math.Abs(b.High-prev), math.Abs(b.Low-prev)))
+		}
+		result[i] = tr
+	}
+	return result
+}
+
+// ATR calculates Wilder's Average True Range.
+// Returns slice of length: len(bars) - period + 1
+func ATR(bars []core.OHLCV, period int) []float64 {
+	if period <= 0 || len(bars) < period {
+		return []float64{}
+	}
+	return wilder(TrueRange(bars), period)
+}
+
+// wilder applies Wilder's smoothing: the first value is the simple mean of
+// the first period values, then avg = (avg*(period-1) + v) / period.
+// Returns slice of length: len(values) - period + 1
+func wilder(values []float64, period int) []float64 {
+	if period <= 0 || len(values) < period {
+		return []float64{}
+	}
+
+	result := make([]float64, 0, len(values)-period+1)
+	p := float64(period)
+	var sum float64
+	for _, v := range values[:period] {
+		sum += v
+	}
+	avg := sum / p
+	result = append(result, avg)
+	for _, v := range values[period:] {
+		avg = (avg*(p-1) + v) / p
+		result = append(result, avg)
+	}
+
+	return result
+}
+
+// stddev is the population standard deviation of values around mean.
+func stddev(values []float64, mean float64) float64 {
+	var ss float64
+	for _, v := range values {
+		ss += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(ss / float64(len(values)))
+}
@@ -0,0 +1,68 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/newthinker/atlas/internal/core"
+)
+
+func TestBollinger_Calculate(t *testing.T) {
+	// mean 5, population standard deviation 2
+	b := Bollinger([]float64{2, 4, 4, 4, 5, 5, 7, 9}, 8, 2)
+	if len(b.Middle) != 1 {
+		t.Fatalf("expected 1 value, got %d", len(b.Middle))
+	}
+	if b.Middle[0] != 5 || b.Upper[0] != 9 || b.Lower[0] != 1 {
+		t.Errorf("bands = %v/%v/%v, want 9/5/1", b.Upper[0], b.Middle[0], b.Lower[0])
+	}
+}
+
+func TestDonchian_Calculate(t *testing.T) {
+	bars := []core.OHLCV{
+		{High: 10, Low: 8}, {High: 12, Low: 9}, {High: 11, Low: 7}, {High: 9, Low: 8},
+	}
+	b := Donchian(bars, 3)
+	if len(b.Upper) != 2 {
+		t.Fatalf("expected 2 values, got %d", len(b.Upper))
+	}
+	if b.Upper[0] != 12 || b.Lower[0] != 7 || b.Middle[0] != 9.5 {
+		t.Errorf("first channel = %v/%v/%v", b.Upper[0], b.Middle[0], b.Lower[0])
+	}
+	if b.Upper[1] != 12 || b.Lower[1] != 7 {
+		t.Errorf("second channel = %v/%v", b.Upper[1], b.Lower[1])
+	}
+}
+
+func TestTrueRange_Gap(t *testing.T) {
+	bars := []core.OHLCV{
+		{High: 11, Low: 9, Close: 10},
+		{High: 15, Low: 13, Close: 14}, // gap up: range widens to the previous close
+	}
+	tr := TrueRange(bars)
+	if len(tr) != 2 || tr[0] != 2 || tr[1] != 5 {
+		t.Errorf("true range = %v, want [2 5]", tr)
+	}
+}
+
+func TestATR_Calculate(t *testing.T) {
+	// True ranges 2, 2, 8, 2: seed (2+2)/2 = 2, then (2+8)/2 = 5, (5+2)/2 = 3.5
+	bars := []core.OHLCV{
+		{High: 11, Low: 9, Close: 10},
+		{High: 11, Low: 9, Close: 10},
+		{High: 14, Low: 6, Close: 10},
+		{High: 11, Low: 9, Close: 10},
+	}
+	atr := ATR(bars, 2)
+	expected := []float64{2, 5, 3.5}
+	if len(atr) != len(expected) {
+		t.Fatalf("expected %d values, got %d", len(expected), len(atr))
+	}
+	for i, v := range expected {
+		if atr[i] != v {
+			t.Errorf("atr[%d] = %f, want %f", i, atr[i], v)
+		}
+	}
+	if atr := ATR(bars[:1], 2); len(atr) != 0 {
+		t.Errorf("expected empty slice, got %d values", len(atr))
+	}
+}
@@ -0,0 +1,21 @@
+package indicator
+
+import "github.com/newthinker/atlas/internal/core"
+
+// OBV calculates On-Balance Volume: a running total that adds the bar's volume
+// on an up close and subtracts it on a down close, starting at 0.
+// Returns slice of length: len(bars)
+func OBV(bars []core.OHLCV) []float64 {
+	result := make([]float64, len(bars))
+	var obv float64
+	for i := 1; i < len(bars); i++ {
+		switch {
+		case bars[i].Close > bars[i-1].Close:
+			obv += float64(bars[i].Volume)
+		case bars[i].Close < bars[i-1].Close:
+			obv -= float64(bars[i].Volume)
+		}
+		result[i] = obv
+	}
+	return result
+}
@@ -0,0 +1,27 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/newthinker/atlas/internal/core"
+)
+
+func TestOBV_Calculate(t *testing.T) {
+	bars := []core.OHLCV{
+		{Close: 10, Volume: 100},
+		{Close: 11, Volume: 200},
+		{Close: 10, Volume: 300},
+		{Close: 10, Volume: 400}, // unchanged close leaves OBV alone
+		{Close: 12, Volume: 500},
+	}
+	obv := OBV(bars)
+	expected := []float64{0, 200, -100, -100, 400}
+	if len(obv) != len(expected) {
+		t.Fatalf("expected %d values, got %d", len(expected), len(obv))
+	}
+	for i, v := range expected {
+		if obv[i] != v {
+			t.Errorf("obv[%d] = %f, want %f", i, obv[i], v)
+		}
+	}
+}
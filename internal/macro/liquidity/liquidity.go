@@ -0,0 +1,194 @@
+// Package liquidity 从 Hestia 的央行金融统计观测派生流动性序列：M1–M2 剪刀差、
+// 社融存量增速脉冲与信贷脉冲。
+//
+// 它只做纯计算：吃 []hestia.Observation，吐按期末月升序的 []Point，不碰数据库、
+// 不关心时点。时点语义由取数的一方负责——策略按评估日调 Store.PublishedBefore，
+// 再把结果交给这里，于是派生出来的每个点都只用到了评估日之前发布的数据。
+//
+// # 累计口径折成月度
+//
+// 社融增量（tsf_flow_ytd）是**年初起累计**：q1 / h1 / q1_q3 / annual 报告给出的
+// 分别是 1–3 / 1–6 / 1–9 / 1–12 月的合计，月报给出的是截至当月的合计。MonthlyFlows
+// 把它们折成逐月增量：同一年内相邻两期的累计差，摊到两期之间的那几个月上。一年的
+// 第一期没有前一期可减，就从 1 月摊起——于是一年只有季报时，除数恰好是 q1 3 /
+// h1 6 / q1_q3 9 / annual 12，也就是 types.go 里 validPeriodTypes 声明的那组除数；
+// 月报齐全时每期只摊一个月，除数为 1。
+//
+// 摊匀会抹平季内的月度波动，这是只有季报时唯一诚实的做法：那几个月各自是多少，
+// 报告本身没有披露。
+package liquidity
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/newthinker/atlas/internal/hestia"
+)
+
+// Point 是派生序列的一个点。Period 是期末月 YYYY-MM。
+type Point struct {
+	Period string
+	Value  float64
+}
+
+// tsfStockToFlow 把社融存量（万亿元）换成增量的单位（亿元）。
+const tsfStockToFlow = 10000
+
+// Scissors 返回 M1–M2 剪刀差：m1_yoy − m2_yoy，单位百分点。
+//
+// 两个同比都是央行按同一口径报出的，所以剪刀差本身跨 2025-01 的 M1 口径修订仍然
+// 有效；但拿修订前后两期的剪刀差做差，比较的是两个口径，调用方要自己当心。
+func Scissors(obs []hestia.Observation) []Point {
+	m2 := valuesByPeriod(obs, hestia.FieldM2YoY)
+	var out []Point
+	for _, p := range byPeriod(obs, hestia.FieldM1YoY) {
+		if v, ok := m2[p.Period]; ok {
+			out = append(out, Point{Period: p.Period, Value: p.Value - v})
+		}
+	}
+	return out
+}
+
+// TSFImpulse 返回社融存量增速脉冲：tsf_stock_yoy 相对 months 个月前那一期的变化，
+// 单位百分点。正值是社融在加速。
+//
+// 要求 months 个月前那一期恰好在库，而不是取「那之前最近的一期」：后者在序列有
+// 缺口时会拿一年前的值冒充半年前的值，而脉冲的符号正是策略的判据。只有季报时
+// months 应取 3 的倍数。
+func TSFImpulse(obs []hestia.Observation, months int) []Point {
+	if months <= 0 {
+		return nil
+	}
+	yoy := byPeriod(obs, hestia.FieldTSFStockYoY)
+	prev := make(map[string]float64, len(yoy))
+	for _, p := range yoy {
+		prev[p.Period] = p.Value
+	}
+	var out []Point
+	for _, p := range yoy {
+		if v, ok := prev[AddMonths(p.Period, -months)]; ok {
+			out = append(out, Point{Period: p.Period, Value: p.Value - v})
+		}
+	}
+	return out
+}
+
+// CreditImpulse 返回信贷脉冲：近 12 个月社融增量相对再往前 12 个月的变化，
+// 占一年前社融存量的百分比。
+//
+//	(Σflow[t-11..t] − Σflow[t-23..t-12]) / stock[t-12] × 100
+//
+// 需要连续 24 个月的月度增量（见 MonthlyFlows）和恰好 12 个月前那一期的存量；
+// 缺一样，那一期就没有值，不拿残缺的窗口凑数。
+func CreditImpulse(obs []hestia.Observation) []Point {
+	monthly := MonthlyFlows(obs)
+	flows := make(map[string]float64, len(monthly))
+	for _, p := range monthly {
+		flows[p.Period] = p.Value
+	}
+	stock := valuesByPeriod(obs, hestia.FieldTSFStock)
+
+	var out []Point
+	for _, p := range monthly {
+		base, ok := stock[AddMonths(p.Period, -12)]
+		if !ok || base <= 0 {
+			continue
+		}
+		recent, ok1 := trailingSum(flows, p.Period, 0)
+		earlier, ok2 := trailingSum(flows, p.Period, 12)
+		if !ok1 || !ok2 {
+			continue
+		}
+		out = append(out, Point{Period: p.Period, Value: (recent - earlier) / (base * tsfStockToFlow) * 100})
+	}
+	return out
+}
+
+// MonthlyFlows 把年初起累计的社融增量折成逐月增量，单位亿元，按月升序。
+// 折算规则见包注释。
+func MonthlyFlows(obs []hestia.Observation) []Point {
+	var out []Point
+	year, month, ytd := "", 0, 0.0
+	for _, p := range byPeriod(obs, hestia.FieldTSFFlowYTD) {
+		y, m := splitPeriod(p.Period)
+		if y != year {
+			year, month, ytd = y, 0, 0
+		}
+		if m <= month {
+			continue
+		}
+		per := (p.Value - ytd) / float64(m-month)
+		for k := month + 1; k <= m; k++ {
+			out = append(out, Point{Period: fmt.Sprintf("%s-%02d", y, k), Value: per})
+		}
+		month, ytd = m, p.Value
+	}
+	return out
+}
+
+// trailingSum 合计 period 往前 skip 个月起、再往前共 12 个月的增量；缺任何一个月
+// 就报 ok=false。
+func trailingSum(flows map[string]float64, period string, skip int) (float64, bool) {
+	sum := 0.0
+	for k := skip; k < skip+12; k++ {
+		v, ok := flows[AddMonths(period, -k)]
+		if !ok {
+			return 0, false
+		}
+		sum += v
+	}
+	return sum, true
+}
+
+// byPeriod 取出 field 的序列，按期末月升序，每个期末月一个点。
+//
+// 同一期末月可能有几种 period_type（12 月的月报与年报、6 月的月报与半年报），
+// 它们报的是同一时点的余额、同一段时间的累计，取先出现的那个即可。未披露的期次
+// （Values 里没有这个键）直接跳过，不当作 0。
+func byPeriod(obs []hestia.Observation, field string) []Point {
+	sorted := slices.Clone(obs)
+	slices.SortStableFunc(sorted, func(a, b hestia.Observation) int {
+		return strings.Compare(a.Meta.Period, b.Meta.Period)
+	})
+	var out []Point
+	for _, o := range sorted {
+		v, ok := o.Values[field]
+		if !ok {
+			continue
+		}
+		if n := len(out); n > 0 && out[n-1].Period == o.Meta.Period {
+			continue
+		}
+		out = append(out, Point{Period: o.Meta.Period, Value: v})
+	}
+	return out
+}
+
+// valuesByPeriod 是 byPeriod 的查表形态。
+func valuesByPeriod(obs []hestia.Observation, field string) map[string]float64 {
+	m := make(map[string]float64)
+	for _, p := range byPeriod(obs, field) {
+		m[p.Period] = p.Value
+	}
+	return m
+}
+
+// AddMonths 把期末月 YYYY-MM 平移 n 个月。形态不合法时返回空串，查表必然落空。
+func AddMonths(period string, n int) string {
+	t, err := time.Parse("2006-01", period)
+	if err != nil {
+		return ""
+	}
+	return t.AddDate(0, n, 0).Format("2006-01")
+}
+
+// splitPeriod 拆出年份与月份；period 的形态已由 hestia 的写入校验保证。
+func splitPeriod(period string) (string, int) {
+	t, err := time.Parse("2006-01", period)
+	if err != nil {
+		return "", 0
+	}
+	return period[:4], int(t.Month())
+}
@@ -0,0 +1,117 @@
+package liquidity
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newthinker/atlas/internal/hestia"
+)
+
+func obs(period, periodType string, values map[string]float64) hestia.Observation {
+	return hestia.Observation{
+		Meta:   hestia.Meta{Period: period, PeriodType: periodType, PublishedAt: period + "-15"},
+		Values: values,
+	}
+}
+
+func flowYTD(period, periodType string, v float64) hestia.Observation {
+	return obs(period, periodType, map[string]float64{hestia.FieldTSFFlowYTD: v})
+}
+
+func TestScissors(t *testing.T) {
+	got := Scissors([]hestia.Observation{
+		obs("2025-06", "monthly", map[string]float64{hestia.FieldM1YoY: 4.6, hestia.FieldM2YoY: 8.3}),
+		obs("2025-03", "q1", map[string]float64{hestia.FieldM1YoY: 1.6, hestia.FieldM2YoY: 7.0}),
+		obs("2025-04", "monthly", map[string]float64{hestia.FieldM2YoY: 8.0}), // M1 未披露
+	})
+	require.Len(t, got, 2, "缺一边的期次没有剪刀差，不当作 0")
+	assert.Equal(t, "2025-03", got[0].Period, "按期末月升序")
+	assert.InDelta(t, -5.4, got[0].Value, 1e-9)
+	assert.InDelta(t, -3.7, got[1].Value, 1e-9)
+}
+
+// 一年只有季报时，除数恰好是 q1 3 / h1 6 / q1_q3 9 / annual 12 的那组：每期的累计差
+// 摊到它与前一期之间的月份上，跨年从 1 月重新摊。
+func TestMonthlyFlowsNormalizesCumulativePeriods(t *testing.T) {
+	got := MonthlyFlows([]hestia.Observation{
+		flowYTD("2024-12", "annual", 120),
+		flowYTD("2025-09", "q1_q3", 300),
+		flowYTD("2025-03", "q1", 90),
+		flowYTD("2025-06", "h1", 210),
+	})
+	want := []float64{10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 30, 30, 30, 40, 40, 40, 30, 30, 30}
+	require.Len(t, got, len(want))
+	assert.Equal(t, "2024-01", got[0].Period, "一年的第一期从 1 月摊起")
+	assert.Equal(t, "2025-09", got[len(got)-1].Period)
+	for i, w := range want {
+		assert.InDeltaf(t, w, got[i].Value, 1e-9, "%s", got[i].Period)
+	}
+}
+
+// 月报与季报同在一个期末月时，报的是同一段累计，只算一次。
+func TestMonthlyFlowsDedupesSharedPeriodEnds(t *testing.T) {
+	got := MonthlyFlows([]hestia.Observation{
+		flowYTD("2025-01", "monthly", 50),
+		flowYTD("2025-02", "monthly", 70),
+		flowYTD("2025-03", "monthly", 100),
+		flowYTD("2025-03", "q1", 100),
+	})
+	require.Len(t, got, 3)
+	assert.Equal(t, []float64{50, 20, 30}, []float64{got[0].Value, got[1].Value, got[2].Value})
+}
+
+func TestTSFImpulse(t *testing.T) {
+	yoy := func(period string, v float64) hestia.Observation {
+		return obs(period, "q1", map[string]float64{hestia.FieldTSFStockYoY: v})
+	}
+	in := []hestia.Observation{yoy("2024-06", 8.1), yoy("2024-12", 8.0), yoy("2025-03", 8.4), yoy("2025-06", 8.9)}
+
+	got := TSFImpulse(in, 6)
+	require.Len(t, got, 2)
+	assert.Equal(t, "2024-12", got[0].Period)
+	assert.InDelta(t, -0.1, got[0].Value, 1e-9)
+	assert.InDelta(t, 0.9, got[1].Value, 1e-9)
+
+	// 2025-03 六个月前的 2024-09 不在库：不拿 2024-06 顶替。
+	for _, p := range got {
+		assert.NotEqual(t, "2025-03", p.Period)
+	}
+	assert.Nil(t, TSFImpulse(in, 0))
+}
+
+func TestCreditImpulse(t *testing.T) {
+	// 2023 每月增量 2 万亿元、2024 与 2025 每月 3 万亿元（月报给出年初起累计），2024-12 存量 400 万亿元。
+	var in []hestia.Observation
+	for _, year := range []int{2023, 2024, 2025} {
+		perMonth := map[int]float64{2023: 20000, 2024: 30000, 2025: 30000}[year]
+		for m := 1; m <= 12; m++ {
+			in = append(in, flowYTD(fmt.Sprintf("%d-%02d", year, m), "monthly", perMonth*float64(m)))
+		}
+	}
+	in = append(in, obs("2024-12", "annual", map[string]float64{hestia.FieldTSFStock: 400}))
+
+	got := CreditImpulse(in)
+	require.Len(t, got, 1, "只有存量恰好在 12 个月前的那一期有值")
+	assert.Equal(t, "2025-12", got[0].Period)
+	// (12×3 − 12×3) 万亿元 / 400 万亿元 = 0；2024-12 那期缺 2023-12 的存量。
+	assert.InDelta(t, 0, got[0].Value, 1e-9)
+
+	in = append(in, obs("2023-12", "annual", map[string]float64{hestia.FieldTSFStock: 360}))
+	got = CreditImpulse(in)
+	require.Len(t, got, 2)
+	assert.Equal(t, "2024-12", got[0].Period)
+	// (36 − 24) 万亿元 / 360 万亿元 × 100
+	assert.InDelta(t, 12.0/360*100, got[0].Value, 1e-9)
+}
+
+func TestCreditImpulseNeedsFullWindows(t *testing.T) {
+	in := []hestia.Observation{
+		flowYTD("2024-06", "h1", 60),
+		flowYTD("2025-12", "annual", 150),
+		obs("2024-12", "annual", map[string]float64{hestia.FieldTSFStock: 400}),
+	}
+	assert.Empty(t, CreditImpulse(in), "前一个 12 个月窗口缺 2024 下半年的增量时不凑数")
+}
@@ -88,11 +88,14 @@ type CombinationRule struct {
 	Evidence   string            `json:"evidence"`
 }
 
-// SignalCondition describes a condition on a signal.
+// SignalCondition describes a condition on a signal. Weight is the
+// condition's share when a composite strategy aggregates confidence; zero
+// counts as 1.
 type SignalCondition struct {
-	Strategy  string      `json:"strategy"`
-	Action    core.Action `json:"action"`
-	MinConf   float64     `json:"min_confidence,omitempty"`
+	Strategy string      `json:"strategy"`
+	Action   core.Action `json:"action"`
+	MinConf  float64     `json:"min_confidence,omitempty"`
+	Weight   float64     `json:"weight,omitempty"`
 }
 
 // Synthesize analyzes trading history and generates improvement suggestions.
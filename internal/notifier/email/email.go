@@ -78,6 +78,9 @@ func (e *Email) SendBatch(signals []core.Signal) error {
 	sb.WriteString("<html><body>")
 	sb.WriteString("<h2>ATLAS Trading Signals</h2>")
 	sb.WriteString(fmt.Sprintf("<p>Generated at: %s</p>", time.Now().Format("2006-01-02 15:04:05")))
+	if w := notifier.MacroWarning(signals...); w != "" {
+		sb.WriteString(fmt.Sprintf("<p><strong>⚠️ %s</strong></p>", w))
+	}
 	sb.WriteString("<hr>")
 
 	for _, signal := range signals {
@@ -91,7 +94,7 @@ func (e *Email) SendBatch(signals []core.Signal) error {
 }
 
 func (e *Email) formatSignal(signal core.Signal) string {
-	return fmt.Sprintf(`
+	body := fmt.Sprintf(`
 ATLAS Trading Signal
 
 Symbol: %s
@@ -108,6 +111,10 @@ Time: %s
 		signal.Reason,
 		signal.GeneratedAt.Format("2006-01-02 15:04:05"),
 	)
+	if w := notifier.MacroWarning(signal); w != "" {
+		body += fmt.Sprintf("Macro: %s\n", w)
+	}
+	return body
 }
 
 func (e *Email) formatSignalHTML(signal core.Signal) string {
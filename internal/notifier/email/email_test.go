@@ -115,3 +115,19 @@ func TestEmail_SendBatch_Empty(t *testing.T) {
 		t.Errorf("empty batch should not error: %v", err)
 	}
 }
+
+func TestEmail_FormatSignal_MacroWarning(t *testing.T) {
+	e := New("smtp.example.com", 587, "", "", "from@example.com", []string{"to@example.com"})
+	signal := core.Signal{
+		Symbol:   "AAPL",
+		Action:   core.ActionBuy,
+		Metadata: map[string]any{notifier.MetaMacroWarning: "Macro crisis state BREWING (as of 2026-10-15)"},
+	}
+	if got := e.formatSignal(signal); !strings.Contains(got, "Macro: Macro crisis state BREWING (as of 2026-10-15)") {
+		t.Errorf("formatted message should carry the macro warning, got:\n%s", got)
+	}
+	signal.Metadata = nil
+	if got := e.formatSignal(signal); strings.Contains(got, "Macro:") {
+		t.Errorf("no warning expected, got:\n%s", got)
+	}
+}
@@ -156,6 +156,9 @@ func formatBatch(signals []core.Signal) string {
 		sb.WriteString(fmt.Sprintf("📊 Atlas 信号汇总 · %s · %d 条\n",
 			latest.Format("2006-01-02 15:04"), len(signals)))
 	}
+	if w := notifier.MacroWarning(signals...); w != "" {
+		sb.WriteString(fmt.Sprintf("⚠️ %s\n", w))
+	}
 
 	for _, g := range digestGroups {
 		rows := make([]core.Signal, 0)
@@ -278,6 +281,10 @@ func (t *Telegram) formatSignal(signal core.Signal) string {
 		sb.WriteString(fmt.Sprintf("💰 Price: $%.2f\n", signal.Price))
 	}
 
+	if w := notifier.MacroWarning(signal); w != "" {
+		sb.WriteString(fmt.Sprintf("⚠️ Macro: %s\n", w))
+	}
+
 	sb.WriteString(fmt.Sprintf("⏰ Time: %s", signal.GeneratedAt.Format("2006-01-02 15:04:05")))
 
 	return sb.String()
@@ -668,3 +668,19 @@ func TestTelegram_SendDocumentErrors(t *testing.T) {
 		t.Errorf("want API error, got %v", err)
 	}
 }
+
+func TestMacroWarning_SignalAndDigest(t *testing.T) {
+	warn := map[string]any{notifier.MetaMacroWarning: "Macro crisis state WATCH (as of 2026-10-15)"}
+	sig := core.Signal{Symbol: "AAPL", Action: core.ActionBuy, Confidence: 0.9, Metadata: warn}
+	if out := New("token", "chat").formatSignal(sig); !strings.Contains(out, "⚠️ Macro: Macro crisis state WATCH (as of 2026-10-15)\n") {
+		t.Errorf("formatSignal must carry the macro warning, got:\n%s", out)
+	}
+
+	out := formatBatch([]core.Signal{sig, {Symbol: "MSFT", Action: core.ActionSell, Confidence: 0.8, Metadata: warn}})
+	if n := strings.Count(out, "Macro crisis state WATCH"); n != 1 {
+		t.Errorf("digest must print the warning once, got %d in:\n%s", n, out)
+	}
+	if out := formatBatch([]core.Signal{{Symbol: "AAPL", Action: core.ActionBuy}}); strings.Contains(out, "⚠️") {
+		t.Errorf("no warning expected, got:\n%s", out)
+	}
+}
@@ -0,0 +1,18 @@
+package notifier
+
+import "github.com/newthinker/atlas/internal/core"
+
+// MetaMacroWarning is the signal metadata key of a warning line notifiers
+// print with the signal, e.g. the crisis gate's macro state.
+const MetaMacroWarning = "macro_warning"
+
+// MacroWarning returns the first macro warning among signals, or "". A batch
+// shares one macro state, so digests print it once.
+func MacroWarning(signals ...core.Signal) string {
+	for _, s := range signals {
+		if w, ok := s.Metadata[MetaMacroWarning].(string); ok && w != "" {
+			return w
+		}
+	}
+	return ""
+}
@@ -0,0 +1,21 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/newthinker/atlas/internal/core"
+)
+
+func TestMacroWarning(t *testing.T) {
+	signals := []core.Signal{
+		{Symbol: "AAPL"},
+		{Symbol: "MSFT", Metadata: map[string]any{MetaMacroWarning: "Macro crisis state WATCH (as of 2026-10-15)"}},
+		{Symbol: "GOOG", Metadata: map[string]any{MetaMacroWarning: "later"}},
+	}
+	if got := MacroWarning(signals...); got != "Macro crisis state WATCH (as of 2026-10-15)" {
+		t.Errorf("MacroWarning = %q", got)
+	}
+	if got := MacroWarning(signals[0]); got != "" {
+		t.Errorf("no warning: got %q", got)
+	}
+}
@@ -614,10 +614,10 @@ func rpsPoints(facts []edgar.QuarterlyFact) []core.EPSPoint {
 	return pts
 }
 
-// seriesByDate 用 ReconstructPESeries 对齐并转 date→ratio 查找表;失败(科目缺失/
+// seriesByDate 用 ReconstructSeries(PriceTo)对齐并转 date→ratio 查找表;失败(科目缺失/
 // 门槛不满足)返回 nil,调用方 lookup 得 NaN(整列 NaN)。
 func seriesByDate(closes []core.OHLCV, pts []core.EPSPoint) map[string]float64 {
-	dates, ratio, err := valuation.ReconstructPESeries(closes, pts)
+	dates, ratio, err := valuation.ReconstructSeries(closes, pts, valuation.PriceTo)
 	if err != nil {
 		return nil
 	}
@@ -76,21 +76,16 @@ func New(cfg Config, registry *notifier.Registry, logger *zap.Logger) *Router {
 // as the per-symbol cooldown (false), so callers can avoid acting on a
 // suppressed signal (e.g. submitting it for execution).
 func (r *Router) Route(signal core.Signal) (routed bool, err error) {
-	// Static filters (confidence + action) are a common precondition for both
-	// the percentile-step gate and the cooldown path.
-	if !r.passesStaticFilters(signal) {
+	if reason := r.Admit(signal, time.Now()); reason != "" {
 		r.logger.Debug("signal filtered out",
 			zap.String("symbol", signal.Symbol),
 			zap.String("action", string(signal.Action)),
 			zap.Float64("confidence", signal.Confidence),
+			zap.String("reason", reason),
 		)
 		return false, nil
 	}
 
-	if !r.passesDispatchGate(signal) {
-		return false, nil
-	}
-
 	// Persist signal if store is configured
 	if r.signalStore != nil {
 		if err := r.signalStore.Save(context.Background(), signal); err != nil {
@@ -149,12 +144,9 @@ func (r *Router) RouteBatch(signals []core.Signal) error {
 	var filtered []core.Signal
 
 	for _, signal := range signals {
-		if !r.passesStaticFilters(signal) {
-			continue
-		}
-		// Same dispatch as Route. Evaluated in order so a later signal sees the
+		// Same filters as Route. Evaluated in order so a later signal sees the
 		// earlier one's gate/cooldown state.
-		if !r.passesDispatchGate(signal) {
+		if r.Admit(signal, time.Now()) != "" {
 			continue
 		}
 		filtered = append(filtered, signal)
@@ -189,20 +181,34 @@ func (r *Router) RouteBatch(signals []core.Signal) error {
 	return nil
 }
 
-// passesStaticFilters checks the confidence threshold and action whitelist —
-// the common precondition that applies regardless of percentile/cooldown path.
-func (r *Router) passesStaticFilters(signal core.Signal) bool {
-	// Check confidence threshold
+// Reasons Admit gives for suppressing a signal.
+const (
+	ReasonMinConfidence  = "min_confidence"
+	ReasonActionDisabled = "action_disabled"
+	ReasonCooldown       = "cooldown"
+	ReasonPercentileStep = "percentile_step"
+)
+
+// Admit runs signal through Route's filters as if it arrived at now: static
+// filters first (a common precondition for both dispatch paths), then the
+// percentile-step gate or the per-symbol cooldown, whose state it updates on
+// a pass. It neither persists nor notifies. The result is "" when the signal
+// would be routed, otherwise the Reason* it would be suppressed for.
+// Route passes wall time; a backtest passes bar time to replay routing.
+func (r *Router) Admit(signal core.Signal, now time.Time) string {
 	if signal.Confidence < r.cfg.MinConfidence {
-		return false
+		return ReasonMinConfidence
 	}
-
-	// Check action whitelist
 	if len(r.cfg.EnabledActions) > 0 && !slices.Contains(r.cfg.EnabledActions, signal.Action) {
-		return false
+		return ReasonActionDisabled
 	}
-
-	return true
+	if !r.passesDispatchGate(signal, now) {
+		if _, _, ok := r.percentileGate(signal); ok {
+			return ReasonPercentileStep
+		}
+		return ReasonCooldown
+	}
+	return ""
 }
 
 // passesDispatchGate applies the percentile-step gate or the per-symbol cooldown
@@ -212,31 +218,40 @@ func (r *Router) passesStaticFilters(signal core.Signal) bool {
 // other signals take the cooldown path and, on pass, stamp the cooldown; the
 // percentile branch must never touch it (otherwise it would suppress other
 // strategies' signals for the same symbol).
-func (r *Router) passesDispatchGate(signal core.Signal) bool {
-	if pct, ok := r.percentileOf(signal); ok {
-		if step := r.effectiveStep(signal); step > 0 {
-			return r.passPercentileGate(signal, pct, step)
-		}
+func (r *Router) passesDispatchGate(signal core.Signal, now time.Time) bool {
+	if pct, step, ok := r.percentileGate(signal); ok {
+		return r.passPercentileGate(signal, pct, step)
 	}
 
-	if !r.passesCooldown(signal) {
+	if !r.passesCooldown(signal, now) {
 		return false
 	}
 	r.mu.Lock()
-	r.cooldowns[signal.Symbol] = time.Now()
+	r.cooldowns[signal.Symbol] = now
 	r.mu.Unlock()
 	return true
 }
 
-// passesCooldown reports whether the per-symbol cooldown allows this signal.
-// CooldownDuration == 0 makes time.Since(last) < 0 always false → always passes
-// (cooldown disabled).
-func (r *Router) passesCooldown(signal core.Signal) bool {
+// percentileGate reports whether signal takes the percentile-step path:
+// percentile metadata present and an effective step > 0.
+func (r *Router) percentileGate(signal core.Signal) (pct, step float64, ok bool) {
+	if pct, ok := r.percentileOf(signal); ok {
+		if step := r.effectiveStep(signal); step > 0 {
+			return pct, step, true
+		}
+	}
+	return 0, 0, false
+}
+
+// passesCooldown reports whether the per-symbol cooldown allows this signal
+// at now. CooldownDuration == 0 makes now.Sub(last) < 0 false for any later
+// signal → always passes (cooldown disabled).
+func (r *Router) passesCooldown(signal core.Signal, now time.Time) bool {
 	r.mu.RLock()
 	lastSignal, exists := r.cooldowns[signal.Symbol]
 	r.mu.RUnlock()
 
-	if exists && time.Since(lastSignal) < r.cfg.CooldownDuration {
+	if exists && now.Sub(lastSignal) < r.cfg.CooldownDuration {
 		return false
 	}
 
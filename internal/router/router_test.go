@@ -685,3 +685,34 @@ func TestRouter_CleanupExpiredCooldowns(t *testing.T) {
 	}
 	r.mu.RUnlock()
 }
+
+func TestAdmit_ReasonsOnSimulatedTime(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CooldownDuration = 24 * time.Hour
+	cfg.PercentileStep = 5
+	cfg.EnabledActions = []core.Action{core.ActionBuy}
+	r := New(cfg, nil, nil)
+	day := time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC)
+	buy := core.Signal{Symbol: "AAPL", Action: core.ActionBuy, Confidence: 0.9}
+
+	cases := []struct {
+		name string
+		sig  core.Signal
+		at   time.Time
+		want string
+	}{
+		{"low confidence", core.Signal{Symbol: "AAPL", Action: core.ActionBuy, Confidence: 0.1}, day, ReasonMinConfidence},
+		{"disabled action", core.Signal{Symbol: "AAPL", Action: core.ActionSell, Confidence: 0.9}, day, ReasonActionDisabled},
+		{"first buy", buy, day, ""},
+		// Cooldown follows the given time, not the wall clock, which is years later.
+		{"next bar", buy, day.Add(12 * time.Hour), ReasonCooldown},
+		{"after cooldown", buy, day.Add(24 * time.Hour), ""},
+		{"first percentile", pctSignal("AAPL", "price_percentile", core.ActionBuy, 10), day, ""},
+		{"small percentile move", pctSignal("AAPL", "price_percentile", core.ActionBuy, 12), day, ReasonPercentileStep},
+	}
+	for _, c := range cases {
+		if got := r.Admit(c.sig, c.at); got != c.want {
+			t.Errorf("%s: Admit = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
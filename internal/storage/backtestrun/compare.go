@@ -0,0 +1,78 @@
+package backtestrun
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/newthinker/atlas/internal/backtest"
+)
+
+// MetricDiff is one statistic of two runs side by side.
+type MetricDiff struct {
+	Name  string
+	Unit  string // "%" for percentages, "" for ratios and counts
+	A, B  float64
+	Delta float64 // B - A
+}
+
+// ParamDiff is one strategy param that differs between two runs; a missing
+// param is nil.
+type ParamDiff struct {
+	Name string
+	A, B any
+}
+
+// Comparison lines two runs up: A is the baseline and B the candidate.
+type Comparison struct {
+	A, B    *Run
+	Metrics []MetricDiff
+	Params  []ParamDiff
+}
+
+// metrics are the statistics a comparison reports, in display order.
+var metrics = []struct {
+	name, unit string
+	get        func(backtest.Stats) float64
+}{
+	{"Total Return", "%", func(s backtest.Stats) float64 { return s.TotalReturn }},
+	{"Annualized Return", "%", func(s backtest.Stats) float64 { return s.AnnualizedReturn }},
+	{"Max Drawdown", "%", func(s backtest.Stats) float64 { return s.MaxDrawdown }},
+	{"Volatility", "%", func(s backtest.Stats) float64 { return s.Volatility }},
+	{"Sharpe Ratio", "", func(s backtest.Stats) float64 { return s.SharpeRatio }},
+	{"Sortino Ratio", "", func(s backtest.Stats) float64 { return s.SortinoRatio }},
+	{"Calmar Ratio", "", func(s backtest.Stats) float64 { return s.CalmarRatio }},
+	{"Trades", "", func(s backtest.Stats) float64 { return float64(s.TotalTrades) }},
+	{"Win Rate", "%", func(s backtest.Stats) float64 { return s.WinRate }},
+	{"Exposure", "%", func(s backtest.Stats) float64 { return s.Exposure }},
+	{"Turnover", "", func(s backtest.Stats) float64 { return s.Turnover }},
+	{"Avg Holding Days", "", func(s backtest.Stats) float64 { return s.AvgHoldingDays }},
+	{"Alpha", "%", func(s backtest.Stats) float64 { return s.Alpha }},
+	{"Beta", "", func(s backtest.Stats) float64 { return s.Beta }},
+}
+
+// Compare lines up the statistics and differing params of a and b.
+func Compare(a, b *Run) Comparison {
+	c := Comparison{A: a, B: b}
+	for _, m := range metrics {
+		va, vb := m.get(a.Result.Stats), m.get(b.Result.Stats)
+		c.Metrics = append(c.Metrics, MetricDiff{Name: m.name, Unit: m.unit, A: va, B: vb, Delta: vb - va})
+	}
+
+	names := make(map[string]bool)
+	for k := range a.Config.Params {
+		names[k] = true
+	}
+	for k := range b.Config.Params {
+		names[k] = true
+	}
+	for k := range names {
+		va, vb := a.Config.Params[k], b.Config.Params[k]
+		// JSON round trips turn every number into float64, so 5 and 5.0 match.
+		if fmt.Sprint(va) == fmt.Sprint(vb) {
+			continue
+		}
+		c.Params = append(c.Params, ParamDiff{Name: k, A: va, B: vb})
+	}
+	sort.Slice(c.Params, func(i, j int) bool { return c.Params[i].Name < c.Params[j].Name })
+	return c
+}
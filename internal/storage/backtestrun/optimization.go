@@ -0,0 +1,124 @@
+package backtestrun
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/newthinker/atlas/internal/backtest"
+)
+
+// ErrOptimizationNotFound is returned for an unknown optimization ID.
+var ErrOptimizationNotFound = errors.New("optimization not found")
+
+// OptimizationSummary is a stored optimization without its trials.
+type OptimizationSummary struct {
+	ID          string
+	Strategy    string
+	Symbol      string
+	Start       time.Time
+	End         time.Time
+	Method      backtest.SearchMethod
+	Objective   backtest.Objective
+	Trials      int
+	Best        *backtest.Trial
+	WalkForward *backtest.WalkForwardSummary `json:",omitempty"`
+	CreatedAt   time.Time
+}
+
+// SaveOptimization stores r, assigning it an opt_<unixnano>_<counter> ID
+// first when it has none. A result saved again under its ID replaces the
+// stored one.
+func (s *SQLiteStore) SaveOptimization(ctx context.Context, r *backtest.OptimizeResult) error {
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now().UTC()
+	}
+	if r.ID == "" {
+		r.ID = fmt.Sprintf("opt_%d_%d", r.CreatedAt.UnixNano(), s.counter.Add(1))
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("marshaling optimization %s: %w", r.ID, err)
+	}
+	best, err := json.Marshal(r.Best)
+	if err != nil {
+		return fmt.Errorf("marshaling optimization best trial: %w", err)
+	}
+	wf, err := json.Marshal(r.WalkForward)
+	if err != nil {
+		return fmt.Errorf("marshaling optimization walk-forward: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT OR REPLACE INTO backtest_optimizations
+		 (id, strategy, symbol, start_date, end_date, method, objective, trials, best, walk_forward, data, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		r.ID, r.Strategy, r.Symbol, r.Start.UTC().Format(timeLayout), r.End.UTC().Format(timeLayout),
+		string(r.Method), string(r.Objective), len(r.Trials), string(best), string(wf), string(data),
+		r.CreatedAt.UTC().Format(timeLayout),
+	); err != nil {
+		return fmt.Errorf("inserting optimization: %w", err)
+	}
+	return nil
+}
+
+// GetOptimization loads the optimization stored under id with all its
+// trials, returning ErrOptimizationNotFound when absent.
+func (s *SQLiteStore) GetOptimization(ctx context.Context, id string) (*backtest.OptimizeResult, error) {
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM backtest_optimizations WHERE id = ?`, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrOptimizationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading optimization: %w", err)
+	}
+	var r backtest.OptimizeResult
+	if err := json.Unmarshal([]byte(data), &r); err != nil {
+		return nil, fmt.Errorf("decoding optimization %s: %w", id, err)
+	}
+	return &r, nil
+}
+
+// ListOptimizations summarises every stored optimization, newest first.
+func (s *SQLiteStore) ListOptimizations(ctx context.Context) ([]OptimizationSummary, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, strategy, symbol, start_date, end_date, method, objective, trials, best, walk_forward, created_at
+		 FROM backtest_optimizations ORDER BY created_at DESC, id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("listing optimizations: %w", err)
+	}
+	defer rows.Close()
+	out := []OptimizationSummary{}
+	for rows.Next() {
+		var sum OptimizationSummary
+		var start, end, created string
+		var best, wf sql.NullString
+		if err := rows.Scan(&sum.ID, &sum.Strategy, &sum.Symbol, &start, &end, &sum.Method, &sum.Objective,
+			&sum.Trials, &best, &wf, &created); err != nil {
+			return nil, err
+		}
+		for _, t := range []struct {
+			src string
+			dst *time.Time
+		}{{start, &sum.Start}, {end, &sum.End}, {created, &sum.CreatedAt}} {
+			if *t.dst, err = time.Parse(timeLayout, t.src); err != nil {
+				return nil, fmt.Errorf("parsing optimization time %q: %w", t.src, err)
+			}
+		}
+		if best.Valid && best.String != "null" {
+			if err := json.Unmarshal([]byte(best.String), &sum.Best); err != nil {
+				return nil, fmt.Errorf("decoding optimization best trial: %w", err)
+			}
+		}
+		if wf.Valid && wf.String != "null" {
+			if err := json.Unmarshal([]byte(wf.String), &sum.WalkForward); err != nil {
+				return nil, fmt.Errorf("decoding optimization walk-forward: %w", err)
+			}
+		}
+		out = append(out, sum)
+	}
+	return out, rows.Err()
+}
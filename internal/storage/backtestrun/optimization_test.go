@@ -0,0 +1,57 @@
+package backtestrun
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/backtest"
+)
+
+func TestSQLiteStore_Optimizations(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	older := &backtest.OptimizeResult{Strategy: "ma_crossover", Symbol: "AAPL",
+		Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+		Method: backtest.SearchGrid, Objective: backtest.ObjectiveSharpe, CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Trials: []backtest.Trial{{Params: map[string]any{"fast_period": 5}, Score: 1.2, Eligible: true}}}
+	older.Best = &older.Trials[0]
+	newer := &backtest.OptimizeResult{Strategy: "pe_band", Symbol: "AAPL", CreatedAt: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)}
+	for _, r := range []*backtest.OptimizeResult{older, newer} {
+		if err := s.SaveOptimization(ctx, r); err != nil {
+			t.Fatalf("SaveOptimization: %v", err)
+		}
+	}
+	if older.ID == "" || older.ID == newer.ID {
+		t.Fatalf("ids %q, %q", older.ID, newer.ID)
+	}
+
+	got, err := s.GetOptimization(ctx, older.ID)
+	if err != nil {
+		t.Fatalf("GetOptimization: %v", err)
+	}
+	if got.Best == nil || got.Best.Params["fast_period"] != 5.0 || len(got.Trials) != 1 || !got.End.Equal(older.End) {
+		t.Errorf("round trip = %+v", got)
+	}
+
+	list, err := s.ListOptimizations(ctx)
+	if err != nil {
+		t.Fatalf("ListOptimizations: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != newer.ID || list[1].Trials != 1 || list[1].Best == nil || list[1].Best.Score != 1.2 ||
+		list[1].Method != backtest.SearchGrid || !list[1].Start.Equal(older.Start) {
+		t.Errorf("list = %+v, want newest first", list)
+	}
+	if list[0].Best != nil || list[0].WalkForward != nil {
+		t.Errorf("newer summary = %+v, want no best trial or walk-forward", list[0])
+	}
+
+	// Optimizations share the database with runs but not their listing.
+	if runs, err := s.List(ctx, ListFilter{}); err != nil || len(runs) != 0 {
+		t.Errorf("runs = %+v, %v; want none", runs, err)
+	}
+	if _, err := s.GetOptimization(ctx, "missing"); !errors.Is(err, ErrOptimizationNotFound) {
+		t.Errorf("GetOptimization(missing) err = %v", err)
+	}
+}
@@ -0,0 +1,433 @@
+// Package backtestrun persists finished backtest runs and parameter
+// optimizations so they survive a restart and can be listed, compared and
+// re-run.
+package backtestrun
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/newthinker/atlas/internal/backtest"
+	"github.com/newthinker/atlas/internal/core"
+
+	_ "modernc.org/sqlite"
+)
+
+// ErrNotFound is returned for an unknown run ID.
+var ErrNotFound = errors.New("backtest run not found")
+
+// timeLayout is the signal store's fixed-width UTC layout, so TEXT columns
+// sort chronologically.
+const timeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+const schema = `
+CREATE TABLE IF NOT EXISTS backtest_runs (
+	id          TEXT PRIMARY KEY,
+	strategy    TEXT NOT NULL,
+	symbol      TEXT NOT NULL,
+	start_date  TEXT NOT NULL,
+	end_date    TEXT NOT NULL,
+	params      TEXT,
+	stats       TEXT NOT NULL,
+	meta        TEXT,
+	created_at  TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_backtest_runs_created ON backtest_runs(created_at);
+CREATE TABLE IF NOT EXISTS backtest_trades (
+	run_id      TEXT NOT NULL,
+	seq         INTEGER NOT NULL,
+	entry_time  TEXT NOT NULL,
+	exit_time   TEXT,
+	entry_price REAL,
+	exit_price  REAL,
+	quantity    REAL,
+	fees        REAL,
+	return_pct  REAL,
+	pnl         REAL,
+	closed      INTEGER NOT NULL,
+	data        TEXT NOT NULL,
+	PRIMARY KEY (run_id, seq)
+);
+CREATE TABLE IF NOT EXISTS backtest_equity (
+	run_id    TEXT NOT NULL,
+	time      TEXT NOT NULL,
+	cash      REAL,
+	positions REAL,
+	equity    REAL,
+	close     REAL NOT NULL DEFAULT 0,
+	PRIMARY KEY (run_id, time)
+);
+CREATE TABLE IF NOT EXISTS backtest_optimizations (
+	id           TEXT PRIMARY KEY,
+	strategy     TEXT NOT NULL,
+	symbol       TEXT NOT NULL,
+	start_date   TEXT NOT NULL,
+	end_date     TEXT NOT NULL,
+	method       TEXT NOT NULL,
+	objective    TEXT NOT NULL,
+	trials       INTEGER NOT NULL,
+	best         TEXT,
+	walk_forward TEXT,
+	data         TEXT NOT NULL,
+	created_at   TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_backtest_optimizations_created ON backtest_optimizations(created_at);`
+
+// Config is what a run was asked to do; re-running it repeats the backtest.
+type Config struct {
+	Strategy string         `json:"strategy"`
+	Symbol   string         `json:"symbol"`
+	Start    string         `json:"start"` // YYYY-MM-DD
+	End      string         `json:"end"`   // YYYY-MM-DD
+	Params   map[string]any `json:"params,omitempty"`
+}
+
+// Run is one stored backtest. Result carries the stats, trades and equity
+// curve; its signals are not kept.
+type Run struct {
+	ID        string
+	Config    Config
+	Result    *backtest.Result
+	CreatedAt time.Time
+}
+
+// Summary is a run without its trades and equity curve.
+type Summary struct {
+	ID        string
+	Config    Config
+	Stats     backtest.Stats
+	CreatedAt time.Time
+}
+
+// ListFilter narrows List; zero fields match everything.
+type ListFilter struct {
+	Strategy string
+	Symbol   string
+	Limit    int
+}
+
+// meta holds the Result fields outside stats, trades and equity.
+type meta struct {
+	SkippedBars int
+	FillModel   backtest.FillModel
+	Execution   *executionMeta
+	Rejections  []backtest.Rejection
+	Capital     float64
+}
+
+// executionMeta is the stored form of a run's execution model. The model's
+// cost and slippage fields are interfaces JSON cannot decode, so only the
+// market and its description are kept and the model is rebuilt on load.
+type executionMeta struct {
+	Market      core.Market
+	Description string `json:",omitempty"`
+}
+
+func newExecutionMeta(m *backtest.ExecutionModel) *executionMeta {
+	if m == nil {
+		return nil
+	}
+	return &executionMeta{Market: m.Market, Description: m.Describe()}
+}
+
+// model rebuilds the execution model: the symbol's own model (which carries
+// per-symbol rules such as the 20% board limit) when the market matches,
+// else the market default.
+func (e *executionMeta) model(symbol string) *backtest.ExecutionModel {
+	if e == nil {
+		return nil
+	}
+	if m := backtest.ModelForSymbol(symbol); m.Market == e.Market {
+		return &m
+	}
+	m := backtest.ModelForMarket(e.Market)
+	return &m
+}
+
+// SQLiteStore is a backtest run and optimization store backed by
+// modernc.org/sqlite.
+type SQLiteStore struct {
+	db      *sql.DB
+	counter atomic.Int64
+}
+
+// NewSQLiteStore opens (creating parent dirs and the schema if needed) a run
+// store at path, configured like the signal store.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	if dir := filepath.Dir(path); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("creating backtest db dir: %w", err)
+		}
+	}
+
+	dsn := "file:" + path + "?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening backtest db: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connecting backtest db: %w", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating backtest schema: %w", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// Close releases the underlying database handle.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// Save stores result under a new run_<unixnano>_<counter> ID and returns it.
+func (s *SQLiteStore) Save(ctx context.Context, cfg Config, result *backtest.Result) (string, error) {
+	now := time.Now().UTC()
+	id := fmt.Sprintf("run_%d_%d", now.UnixNano(), s.counter.Add(1))
+
+	params, err := json.Marshal(cfg.Params)
+	if err != nil {
+		return "", fmt.Errorf("marshaling run params: %w", err)
+	}
+	stats, err := json.Marshal(result.Stats)
+	if err != nil {
+		return "", fmt.Errorf("marshaling run stats: %w", err)
+	}
+	m, err := json.Marshal(meta{
+		SkippedBars: result.SkippedBars, FillModel: result.FillModel, Execution: newExecutionMeta(result.Execution),
+		Rejections: result.Rejections, Capital: result.Capital,
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshaling run meta: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("saving run: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO backtest_runs (id, strategy, symbol, start_date, end_date, params, stats, meta, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, cfg.Strategy, cfg.Symbol, cfg.Start, cfg.End, string(params), string(stats), string(m), now.Format(timeLayout),
+	); err != nil {
+		return "", fmt.Errorf("inserting run: %w", err)
+	}
+	for i, t := range result.Trades {
+		data, err := json.Marshal(t)
+		if err != nil {
+			return "", fmt.Errorf("marshaling trade: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO backtest_trades (run_id, seq, entry_time, exit_time, entry_price, exit_price, quantity, fees, return_pct, pnl, closed, data)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, i, t.EntryTime.UTC().Format(timeLayout), t.ExitTime.UTC().Format(timeLayout),
+			t.EntryPrice, t.ExitPrice, t.Quantity, t.Fees, t.Return, t.PnL, t.IsClosed(), string(data),
+		); err != nil {
+			return "", fmt.Errorf("inserting trade: %w", err)
+		}
+	}
+	for _, p := range result.Equity {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO backtest_equity (run_id, time, cash, positions, equity, close) VALUES (?, ?, ?, ?, ?, ?)`,
+			id, p.Time.UTC().Format(timeLayout), p.Cash, p.Positions, p.Equity, p.Close,
+		); err != nil {
+			return "", fmt.Errorf("inserting equity point: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("saving run: %w", err)
+	}
+	return id, nil
+}
+
+// Get loads a run with its trades and equity curve, returning ErrNotFound
+// when absent.
+func (s *SQLiteStore) Get(ctx context.Context, id string) (*Run, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT id, strategy, symbol, start_date, end_date, params, stats, created_at, meta
+		 FROM backtest_runs WHERE id = ?`, id)
+	var metaJSON sql.NullString
+	sum, err := scanSummary(row, &metaJSON)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var m meta
+	if metaJSON.Valid && metaJSON.String != "" {
+		if err := json.Unmarshal([]byte(metaJSON.String), &m); err != nil {
+			return nil, fmt.Errorf("decoding run meta: %w", err)
+		}
+	}
+	start, _ := time.Parse("2006-01-02", sum.Config.Start)
+	end, _ := time.Parse("2006-01-02", sum.Config.End)
+	result := &backtest.Result{
+		Strategy:    sum.Config.Strategy,
+		Symbol:      sum.Config.Symbol,
+		StartDate:   start,
+		EndDate:     end,
+		Stats:       sum.Stats,
+		SkippedBars: m.SkippedBars,
+		FillModel:   m.FillModel,
+		Execution:   m.Execution.model(sum.Config.Symbol),
+		Rejections:  m.Rejections,
+		Capital:     m.Capital,
+	}
+
+	if result.Trades, err = s.trades(ctx, id); err != nil {
+		return nil, err
+	}
+	if result.Equity, err = s.equity(ctx, id); err != nil {
+		return nil, err
+	}
+	return &Run{ID: sum.ID, Config: sum.Config, Result: result, CreatedAt: sum.CreatedAt}, nil
+}
+
+func (s *SQLiteStore) trades(ctx context.Context, id string) ([]backtest.Trade, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM backtest_trades WHERE run_id = ? ORDER BY seq`, id)
+	if err != nil {
+		return nil, fmt.Errorf("loading trades: %w", err)
+	}
+	defer rows.Close()
+	var out []backtest.Trade
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var t backtest.Trade
+		if err := json.Unmarshal([]byte(data), &t); err != nil {
+			return nil, fmt.Errorf("decoding trade: %w", err)
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLiteStore) equity(ctx context.Context, id string) ([]backtest.EquityPoint, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT time, cash, positions, equity, close FROM backtest_equity WHERE run_id = ? ORDER BY time`, id)
+	if err != nil {
+		return nil, fmt.Errorf("loading equity: %w", err)
+	}
+	defer rows.Close()
+	var out []backtest.EquityPoint
+	for rows.Next() {
+		var ts string
+		var p backtest.EquityPoint
+		if err := rows.Scan(&ts, &p.Cash, &p.Positions, &p.Equity, &p.Close); err != nil {
+			return nil, err
+		}
+		if p.Time, err = time.Parse(timeLayout, ts); err != nil {
+			return nil, fmt.Errorf("parsing equity time %q: %w", ts, err)
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// List returns run summaries matching filter, newest first.
+func (s *SQLiteStore) List(ctx context.Context, filter ListFilter) ([]Summary, error) {
+	var conds []string
+	var args []any
+	if filter.Strategy != "" {
+		conds = append(conds, "strategy = ?")
+		args = append(args, filter.Strategy)
+	}
+	if filter.Symbol != "" {
+		conds = append(conds, "symbol = ?")
+		args = append(args, filter.Symbol)
+	}
+	query := `SELECT id, strategy, symbol, start_date, end_date, params, stats, created_at FROM backtest_runs`
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing runs: %w", err)
+	}
+	defer rows.Close()
+	out := []Summary{}
+	for rows.Next() {
+		sum, err := scanSummary(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, sum)
+	}
+	return out, rows.Err()
+}
+
+// Delete removes a run with its trades and equity curve.
+func (s *SQLiteStore) Delete(ctx context.Context, id string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("deleting run: %w", err)
+	}
+	defer tx.Rollback()
+	res, err := tx.ExecContext(ctx, `DELETE FROM backtest_runs WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("deleting run: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	for _, table := range []string{"backtest_trades", "backtest_equity"} {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE run_id = ?`, id); err != nil {
+			return fmt.Errorf("deleting run: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// scanner is satisfied by *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanSummary reads the summary columns, plus any extra destinations the
+// query selects after created_at.
+func scanSummary(sc scanner, extra ...any) (Summary, error) {
+	var sum Summary
+	var params, stats sql.NullString
+	var created string
+	dest := append([]any{&sum.ID, &sum.Config.Strategy, &sum.Config.Symbol, &sum.Config.Start, &sum.Config.End,
+		&params, &stats, &created}, extra...)
+	if err := sc.Scan(dest...); err != nil {
+		return Summary{}, err
+	}
+	if params.Valid && params.String != "" && params.String != "null" {
+		if err := json.Unmarshal([]byte(params.String), &sum.Config.Params); err != nil {
+			return Summary{}, fmt.Errorf("decoding run params: %w", err)
+		}
+	}
+	if stats.Valid {
+		if err := json.Unmarshal([]byte(stats.String), &sum.Stats); err != nil {
+			return Summary{}, fmt.Errorf("decoding run stats: %w", err)
+		}
+	}
+	var err error
+	if sum.CreatedAt, err = time.Parse(timeLayout, created); err != nil {
+		return Summary{}, fmt.Errorf("parsing created_at %q: %w", created, err)
+	}
+	return sum, nil
+}
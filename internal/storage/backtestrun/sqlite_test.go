@@ -0,0 +1,165 @@
+package backtestrun
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/backtest"
+	"github.com/newthinker/atlas/internal/core"
+)
+
+func newTestStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	s, err := NewSQLiteStore(filepath.Join(t.TempDir(), "sub", "backtests.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func sampleResult() *backtest.Result {
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	exit := core.Signal{Action: core.ActionSell, GeneratedAt: day.AddDate(0, 0, 2)}
+	return &backtest.Result{
+		Strategy: "ma_crossover",
+		Symbol:   "AAPL",
+		Stats:    backtest.Stats{TotalTrades: 1, TotalReturn: 12.5, SharpeRatio: 1.4, Benchmark: "SPY"},
+		Trades: []backtest.Trade{{
+			EntrySignal: core.Signal{Action: core.ActionBuy, Strategy: "ma_crossover", GeneratedAt: day},
+			ExitSignal:  &exit,
+			EntryTime:   day, ExitTime: day.AddDate(0, 0, 2),
+			EntryPrice: 100, ExitPrice: 112.5, Quantity: 10, Return: 12.5, PnL: 125,
+		}},
+		FillModel:  backtest.FillNextOpen,
+		Capital:    1000,
+		Rejections: []backtest.Rejection{{Time: day, Symbol: "AAPL", Side: backtest.SideBuy, Reason: "lot size"}},
+		Equity: []backtest.EquityPoint{
+			{Time: day, Cash: 0, Positions: 1000, Equity: 1000},
+			{Time: day.AddDate(0, 0, 1), Cash: 0, Positions: 1050, Equity: 1050, Close: 105},
+			{Time: day.AddDate(0, 0, 2), Cash: 1125, Equity: 1125},
+		},
+	}
+}
+
+func TestSQLiteStore_RoundTrip(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	cfg := Config{Strategy: "ma_crossover", Symbol: "AAPL", Start: "2025-01-06", End: "2025-01-08",
+		Params: map[string]any{"fast_period": 5}}
+
+	id, err := s.Save(ctx, cfg, sampleResult())
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	run, err := s.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if run.Config.Strategy != "ma_crossover" || run.Config.Params["fast_period"] != 5.0 {
+		t.Errorf("config = %+v", run.Config)
+	}
+	r := run.Result
+	if r.Stats.TotalReturn != 12.5 || r.Stats.Benchmark != "SPY" || r.FillModel != backtest.FillNextOpen || r.Capital != 1000 {
+		t.Errorf("result = %+v", r)
+	}
+	if !r.StartDate.Equal(time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("StartDate = %v", r.StartDate)
+	}
+	if len(r.Trades) != 1 || !r.Trades[0].IsClosed() || r.Trades[0].PnL != 125 {
+		t.Errorf("trades = %+v", r.Trades)
+	}
+	if len(r.Equity) != 3 || r.Equity[2].Equity != 1125 || r.Equity[1].Close != 105 || !r.Equity[1].Time.Equal(time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("equity = %+v", r.Equity)
+	}
+	if len(r.Rejections) != 1 || r.Rejections[0].Reason != "lot size" {
+		t.Errorf("rejections = %+v", r.Rejections)
+	}
+
+	if _, err := s.Get(ctx, "run_missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing run err = %v", err)
+	}
+}
+
+// The execution model's cost and slippage fields are interfaces; a run saved
+// with one (as serve always does) must still load, with the model rebuilt.
+func TestSQLiteStore_RoundTripExecutionModel(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	cfg := Config{Strategy: "ma_crossover", Symbol: "300750.SZ", Start: "2025-01-06", End: "2025-01-08"}
+	result := sampleResult()
+	result.Symbol = cfg.Symbol
+	result.Execution = backtest.MarketModels(cfg.Symbol)
+
+	id, err := s.Save(ctx, cfg, result)
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	run, err := s.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got := run.Result.Execution
+	if got == nil || got.Costs == nil || got.Slippage == nil {
+		t.Fatalf("Execution = %+v, want the rebuilt market model", got)
+	}
+	if got.Describe() != result.Execution.Describe() || got.Rules.PriceLimitPct != 20 {
+		t.Errorf("Execution = %q, want %q", got.Describe(), result.Execution.Describe())
+	}
+}
+
+func TestSQLiteStore_ListAndDelete(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+	var ids []string
+	for _, c := range []Config{
+		{Strategy: "ma_crossover", Symbol: "AAPL", Start: "2024-01-01", End: "2024-12-31"},
+		{Strategy: "pe_band", Symbol: "AAPL", Start: "2024-01-01", End: "2024-12-31"},
+		{Strategy: "ma_crossover", Symbol: "MSFT", Start: "2024-01-01", End: "2024-12-31"},
+	} {
+		id, err := s.Save(ctx, c, sampleResult())
+		if err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	all, err := s.List(ctx, ListFilter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(all) != 3 || all[0].ID != ids[2] || all[0].Stats.SharpeRatio != 1.4 {
+		t.Errorf("list = %+v, want newest first", all)
+	}
+	ma, _ := s.List(ctx, ListFilter{Strategy: "ma_crossover", Limit: 1})
+	if len(ma) != 1 || ma[0].Config.Symbol != "MSFT" {
+		t.Errorf("filtered = %+v", ma)
+	}
+
+	if err := s.Delete(ctx, ids[0]); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get(ctx, ids[0]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleted run err = %v", err)
+	}
+	if err := s.Delete(ctx, ids[0]); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second delete err = %v", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	a := &Run{Config: Config{Params: map[string]any{"fast_period": 5.0, "slow_period": 20}}, Result: sampleResult()}
+	b := &Run{Config: Config{Params: map[string]any{"fast_period": 5, "slow_period": 50}}, Result: sampleResult()}
+	b.Result.Stats.TotalReturn = 20
+
+	c := Compare(a, b)
+	if c.Metrics[0].Name != "Total Return" || c.Metrics[0].Delta != 7.5 {
+		t.Errorf("total return diff = %+v", c.Metrics[0])
+	}
+	if len(c.Params) != 1 || c.Params[0].Name != "slow_period" {
+		t.Errorf("param diffs = %+v, want only slow_period", c.Params)
+	}
+}
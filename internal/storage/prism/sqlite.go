@@ -214,6 +214,7 @@ type SeriesData struct {
 	Symbol, Name, Source   string
 	Dates                  []string
 	PETTM, Pctl5Y, Pctl10Y []float64
+	PB, PSTTM              []float64
 }
 
 // UpsertInstrument inserts or updates by (symbol,type) and returns the row id.
@@ -514,7 +515,7 @@ func (s *Store) Series(symbol, from string) (*SeriesData, error) {
 		return nil, fmt.Errorf("prism: query symbol %s: %w", symbol, err)
 	}
 	rows, err := s.db.Query(`
-		SELECT v.d, v.pe_ttm, v.pctl_5y, v.pctl_10y
+		SELECT v.d, v.pe_ttm, v.pctl_5y, v.pctl_10y, v.pb, v.ps_ttm
 		FROM valuation_daily v JOIN instrument i ON i.id = v.instrument_id
 		WHERE i.symbol=? AND (?='' OR v.d>=?) ORDER BY v.d`, symbol, from, from)
 	if err != nil {
@@ -523,14 +524,16 @@ func (s *Store) Series(symbol, from string) (*SeriesData, error) {
 	defer rows.Close()
 	for rows.Next() {
 		var d string
-		var pe, p5, p10 sql.NullFloat64
-		if err := rows.Scan(&d, &pe, &p5, &p10); err != nil {
+		var pe, p5, p10, pb, ps sql.NullFloat64
+		if err := rows.Scan(&d, &pe, &p5, &p10, &pb, &ps); err != nil {
 			return nil, err
 		}
 		sd.Dates = append(sd.Dates, d)
 		sd.PETTM = append(sd.PETTM, fromNull(pe))
 		sd.Pctl5Y = append(sd.Pctl5Y, fromNull(p5))
 		sd.Pctl10Y = append(sd.Pctl10Y, fromNull(p10))
+		sd.PB = append(sd.PB, fromNull(pb))
+		sd.PSTTM = append(sd.PSTTM, fromNull(ps))
 	}
 	return sd, rows.Err()
 }
@@ -46,6 +46,11 @@ func TestLatestDateEmptyThenAdvances(t *testing.T) {
 	d, err = s.LatestDate(id)
 	require.NoError(t, err)
 	assert.Equal(t, "2026-07-22", d)
+
+	got, err := s.Series("000300.SH", "")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1.3, 1.3}, got.PB)
+	assert.Equal(t, []float64{1.1, 1.1}, got.PSTTM)
 }
 
 func TestUpsertValuationsNaNRoundtrip(t *testing.T) {
@@ -60,6 +65,8 @@ func TestUpsertValuationsNaNRoundtrip(t *testing.T) {
 	assert.Equal(t, 45.5, got.PETTM[0])
 	assert.True(t, math.IsNaN(got.Pctl10Y[0]))
 	assert.Equal(t, 88.0, got.Pctl5Y[0])
+	assert.True(t, math.IsNaN(got.PB[0]))
+	assert.True(t, math.IsNaN(got.PSTTM[0]))
 }
 
 func TestBoardReturnsLatestPerInstrument(t *testing.T) {
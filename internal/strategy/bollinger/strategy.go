@@ -0,0 +1,170 @@
+package bollinger
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/indicator"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// Modes of the Bollinger strategy.
+const (
+	// ModeReversion buys when the close drops below the lower band and sells
+	// when it rises above the upper band, expecting a return to the mean.
+	ModeReversion = "reversion"
+	// ModeBreakout buys when the close breaks above the upper band and sells
+	// when it breaks below the lower band, following the move.
+	ModeBreakout = "breakout"
+)
+
+// Bollinger implements a Bollinger Band strategy in reversion or breakout
+// mode. Signals fire on the bar the close crosses a band.
+type Bollinger struct {
+	period int
+	k      float64
+	mode   string
+}
+
+// New creates a new Bollinger strategy
+func New(period int, k float64, mode string) *Bollinger {
+	return &Bollinger{period: period, k: k, mode: mode}
+}
+
+func (b *Bollinger) Name() string {
+	return "bollinger"
+}
+
+func (b *Bollinger) Description() string {
+	return fmt.Sprintf("Bollinger Bands %s (%d, %.1f)", b.mode, b.period, b.k)
+}
+
+func (b *Bollinger) RequiredData() strategy.DataRequirements {
+	return strategy.DataRequirements{
+		PriceHistory: b.period + 10, // Extra buffer
+		Indicators:   []string{b.spec()},
+		AssetTypes: []core.AssetType{
+			core.AssetStock, core.AssetIndex, core.AssetETF,
+			core.AssetFund, core.AssetCommodity, core.AssetCrypto,
+		},
+	}
+}
+
+// Params declares the params Init reads; defaults are the current values.
+func (b *Bollinger) Params() []strategy.Param {
+	return []strategy.Param{
+		{Name: "period", Type: strategy.ParamInt, Default: b.period, Min: strategy.Bound(2),
+			Description: "Moving average period in bars"},
+		{Name: "k", Type: strategy.ParamNumber, Default: b.k, Min: strategy.Bound(0),
+			Description: "Band width in standard deviations"},
+		{Name: "mode", Type: strategy.ParamString, Default: b.mode, Options: []string{ModeReversion, ModeBreakout},
+			Description: "reversion trades back inside the bands, breakout trades closes outside them"},
+	}
+}
+
+func (b *Bollinger) Init(cfg strategy.Config) error {
+	if err := strategy.ValidateParams(b.Params(), cfg.Params); err != nil {
+		return fmt.Errorf("bollinger: %w", err)
+	}
+	if v, ok := strategy.IntParam(cfg.Params, "period"); ok {
+		b.period = v
+	}
+	if v, ok := strategy.NumParam(cfg.Params, "k"); ok {
+		b.k = v
+	}
+	if v, ok := cfg.Params["mode"].(string); ok {
+		b.mode = v
+	}
+	if b.period < 2 {
+		return fmt.Errorf("bollinger: period must be at least 2, got %d", b.period)
+	}
+	if b.k <= 0 {
+		return fmt.Errorf("bollinger: k must be positive, got %.2f", b.k)
+	}
+	if b.mode != ModeReversion && b.mode != ModeBreakout {
+		return fmt.Errorf("bollinger: mode must be %q or %q, got %q", ModeReversion, ModeBreakout, b.mode)
+	}
+	return nil
+}
+
+func (b *Bollinger) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	if len(ctx.OHLCV) <= b.period {
+		return nil, nil // Not enough data
+	}
+
+	// Use the bands the caller computed, else calculate them
+	upper, uok := ctx.Indicators[b.spec()+".upper"]
+	middle, mok := ctx.Indicators[b.spec()]
+	lower, lok := ctx.Indicators[b.spec()+".lower"]
+	if !uok || !mok || !lok {
+		bands := indicator.Bollinger(indicator.Closes(ctx.OHLCV), b.period, b.k)
+		upper, middle, lower = bands.Upper, bands.Middle, bands.Lower
+	}
+	if len(upper) < 2 || len(lower) < 2 || len(middle) < 1 {
+		return nil, nil
+	}
+
+	n := len(ctx.OHLCV)
+	curr, prev := ctx.OHLCV[n-1].Close, ctx.OHLCV[n-2].Close
+	currUp, prevUp := upper[len(upper)-1], upper[len(upper)-2]
+	currLow, prevLow := lower[len(lower)-1], lower[len(lower)-2]
+	width := currUp - currLow
+
+	meta := map[string]any{
+		"upper":  currUp,
+		"middle": middle[len(middle)-1],
+		"lower":  currLow,
+		"mode":   b.mode,
+	}
+	signal := func(action core.Action, typ, reason string, beyond float64) []core.Signal {
+		meta["type"] = typ
+		return []core.Signal{{
+			Symbol:      ctx.Symbol,
+			Action:      action,
+			Price:       curr,
+			Confidence:  b.calculateConfidence(beyond, width),
+			Reason:      reason,
+			GeneratedAt: ctx.Now,
+			Metadata:    meta,
+		}}
+	}
+
+	crossedBelow := prev >= prevLow && curr < currLow
+	crossedAbove := prev <= prevUp && curr > currUp
+	switch {
+	case crossedBelow && b.mode == ModeReversion:
+		return signal(core.ActionBuy, "bb_lower_reversion",
+			fmt.Sprintf("Close (%.2f) fell below lower band (%.2f)", curr, currLow), currLow-curr), nil
+	case crossedAbove && b.mode == ModeReversion:
+		return signal(core.ActionSell, "bb_upper_reversion",
+			fmt.Sprintf("Close (%.2f) rose above upper band (%.2f)", curr, currUp), curr-currUp), nil
+	case crossedAbove && b.mode == ModeBreakout:
+		return signal(core.ActionBuy, "bb_upper_breakout",
+			fmt.Sprintf("Close (%.2f) broke out above upper band (%.2f)", curr, currUp), curr-currUp), nil
+	case crossedBelow && b.mode == ModeBreakout:
+		return signal(core.ActionSell, "bb_lower_breakdown",
+			fmt.Sprintf("Close (%.2f) broke down below lower band (%.2f)", curr, currLow), currLow-curr), nil
+	}
+	return nil, nil
+}
+
+// spec is the indicator spec of the strategy's bands.
+func (b *Bollinger) spec() string {
+	return fmt.Sprintf("bb_%d_%s", b.period, strconv.FormatFloat(b.k, 'f', -1, 64))
+}
+
+// calculateConfidence returns higher confidence the further the close lies
+// beyond the band, measured in band widths.
+func (b *Bollinger) calculateConfidence(beyond, width float64) float64 {
+	if width <= 0 {
+		return 0.5
+	}
+
+	// Scale to 0.5-0.9 range based on the overshoot
+	confidence := 0.5 + beyond/width*2
+	if confidence > 0.9 {
+		confidence = 0.9
+	}
+	return confidence
+}
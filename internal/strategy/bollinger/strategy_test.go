@@ -0,0 +1,134 @@
+package bollinger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+func TestBollinger_ImplementsStrategy(t *testing.T) {
+	var _ strategy.Strategy = (*Bollinger)(nil)
+}
+
+// barsFromCloses builds OHLCV bars from a sequence of closing prices with a
+// fixed arbitrary increasing timestamp per bar (time value is irrelevant here).
+func barsFromCloses(closes ...float64) []core.OHLCV {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := make([]core.OHLCV, len(closes))
+	for i, c := range closes {
+		bars[i] = core.OHLCV{
+			Symbol: "T",
+			Close:  c,
+			Time:   base.Add(time.Duration(i) * 24 * time.Hour),
+		}
+	}
+	return bars
+}
+
+// rangeThen returns closes alternating between 100 and 101, followed by last.
+func rangeThen(n int, last float64) []float64 {
+	closes := make([]float64, 0, n+1)
+	for i := 0; i < n; i++ {
+		closes = append(closes, 100+float64(i%2))
+	}
+	return append(closes, last)
+}
+
+func TestBollinger_NameAndDescription(t *testing.T) {
+	s := New(20, 2, ModeReversion)
+	if s.Name() != "bollinger" {
+		t.Errorf("Name() = %q, want %q", s.Name(), "bollinger")
+	}
+	if got := s.Description(); got != "Bollinger Bands reversion (20, 2.0)" {
+		t.Errorf("Description() = %q", got)
+	}
+	if got := New(20, 2.5, ModeBreakout).RequiredData().Indicators; len(got) != 1 || got[0] != "bb_20_2.5" {
+		t.Errorf("Indicators = %v, want [bb_20_2.5]", got)
+	}
+}
+
+func TestBollinger_Init(t *testing.T) {
+	s := New(20, 2, ModeReversion)
+	if err := s.Init(strategy.Config{Params: map[string]any{
+		"period": 10.0,
+		"k":      1.5,
+		"mode":   "breakout",
+	}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.period != 10 || s.k != 1.5 || s.mode != ModeBreakout {
+		t.Errorf("after Init = %d/%v/%s, want 10/1.5/breakout", s.period, s.k, s.mode)
+	}
+
+	for _, params := range []map[string]any{
+		{"period": 1},
+		{"k": 0},
+		{"mode": "momentum"},
+	} {
+		if err := New(20, 2, ModeReversion).Init(strategy.Config{Params: params}); err == nil {
+			t.Errorf("Init(%v): expected error", params)
+		}
+	}
+}
+
+func TestBollinger_Reversion(t *testing.T) {
+	past := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	s := New(10, 2, ModeReversion)
+
+	sigs, err := s.Analyze(strategy.AnalysisContext{Symbol: "T", Now: past, OHLCV: barsFromCloses(rangeThen(12, 90)...)})
+	if err != nil || len(sigs) != 1 {
+		t.Fatalf("expected one signal, got %v err=%v", sigs, err)
+	}
+	sig := sigs[0]
+	if sig.Action != core.ActionBuy || sig.Price != 90 || !sig.GeneratedAt.Equal(past) {
+		t.Errorf("signal = %+v", sig)
+	}
+	if sig.Metadata["type"] != "bb_lower_reversion" || sig.Confidence < 0.5 || sig.Confidence > 0.9 {
+		t.Errorf("signal = %+v", sig)
+	}
+
+	sigs, _ = s.Analyze(strategy.AnalysisContext{Symbol: "T", OHLCV: barsFromCloses(rangeThen(12, 110)...)})
+	if len(sigs) != 1 || sigs[0].Action != core.ActionSell || sigs[0].Metadata["type"] != "bb_upper_reversion" {
+		t.Errorf("expected a sell above the upper band, got %v", sigs)
+	}
+}
+
+func TestBollinger_Breakout(t *testing.T) {
+	s := New(10, 2, ModeBreakout)
+
+	sigs, _ := s.Analyze(strategy.AnalysisContext{Symbol: "T", OHLCV: barsFromCloses(rangeThen(12, 110)...)})
+	if len(sigs) != 1 || sigs[0].Action != core.ActionBuy || sigs[0].Metadata["type"] != "bb_upper_breakout" {
+		t.Errorf("expected a buy on the upside breakout, got %v", sigs)
+	}
+	sigs, _ = s.Analyze(strategy.AnalysisContext{Symbol: "T", OHLCV: barsFromCloses(rangeThen(12, 90)...)})
+	if len(sigs) != 1 || sigs[0].Action != core.ActionSell || sigs[0].Metadata["type"] != "bb_lower_breakdown" {
+		t.Errorf("expected a sell on the downside breakdown, got %v", sigs)
+	}
+}
+
+func TestBollinger_NoSignalInsideBands(t *testing.T) {
+	s := New(10, 2, ModeReversion)
+	if sigs, _ := s.Analyze(strategy.AnalysisContext{Symbol: "T",
+		OHLCV: barsFromCloses(rangeThen(12, 100)...)}); len(sigs) != 0 {
+		t.Errorf("expected no signal, got %v", sigs)
+	}
+	if sigs, _ := s.Analyze(strategy.AnalysisContext{Symbol: "T",
+		OHLCV: barsFromCloses(rangeThen(5, 90)...)}); len(sigs) != 0 {
+		t.Errorf("expected no signal with too few bars, got %v", sigs)
+	}
+}
+
+func TestAnalyze_UsesProvidedIndicators(t *testing.T) {
+	s := New(10, 2, ModeReversion)
+	bars := barsFromCloses(rangeThen(12, 100)...) // inside its own bands
+	sigs, err := s.Analyze(strategy.AnalysisContext{Symbol: "T", OHLCV: bars, Indicators: map[string][]float64{
+		"bb_10_2":       {100, 105},
+		"bb_10_2.upper": {102, 108},
+		"bb_10_2.lower": {98, 102},
+	}})
+	if err != nil || len(sigs) != 1 || sigs[0].Action != core.ActionBuy {
+		t.Fatalf("expected a reversion buy from the provided bands, got %v err=%v", sigs, err)
+	}
+}
@@ -0,0 +1,59 @@
+// Package builtin lists the strategies compiled into atlas, for the callers
+// that need all of them: the CLI engines and config validation.
+package builtin
+
+import (
+	"github.com/newthinker/atlas/internal/strategy"
+	"github.com/newthinker/atlas/internal/strategy/bollinger"
+	"github.com/newthinker/atlas/internal/strategy/cn_liquidity"
+	"github.com/newthinker/atlas/internal/strategy/dividend_yield"
+	"github.com/newthinker/atlas/internal/strategy/erp"
+	"github.com/newthinker/atlas/internal/strategy/exit"
+	"github.com/newthinker/atlas/internal/strategy/ma_crossover"
+	"github.com/newthinker/atlas/internal/strategy/macd"
+	"github.com/newthinker/atlas/internal/strategy/momentum_rotation"
+	"github.com/newthinker/atlas/internal/strategy/pe_band"
+	"github.com/newthinker/atlas/internal/strategy/pe_percentile"
+	"github.com/newthinker/atlas/internal/strategy/price_percentile"
+	"github.com/newthinker/atlas/internal/strategy/rsi"
+	"github.com/newthinker/atlas/internal/strategy/valuation_percentile"
+)
+
+// New returns a fresh instance of every built-in strategy with its default
+// params.
+func New() []strategy.Strategy {
+	return []strategy.Strategy{
+		ma_crossover.New(50, 200),
+		price_percentile.New(),
+		rsi.New(14, 30, 70),
+		macd.New(12, 26, 9),
+		bollinger.New(20, 2, bollinger.ModeReversion),
+		pe_band.New(15, 30),
+		dividend_yield.New(3.0),
+		pe_percentile.New(),
+		valuation_percentile.NewPB(),
+		valuation_percentile.NewPS(),
+		valuation_percentile.NewDividendYield(),
+		// Without PE history or yield sources erp emits nothing; serve wires
+		// them in.
+		erp.New(nil, nil),
+		// Without the Hestia statistics store cn_liquidity fails to analyze;
+		// serve and the backtest commands wire it in.
+		cn_liquidity.New(nil),
+		exit.NewStopLoss(8),
+		exit.NewTakeProfit(20),
+		exit.NewTrailingStop(22, 3),
+		exit.NewTimeStop(60),
+		momentum_rotation.New(126, 3),
+	}
+}
+
+// Lookup returns a fresh instance of the built-in strategy called name.
+func Lookup(name string) (strategy.Strategy, bool) {
+	for _, s := range New() {
+		if s.Name() == name {
+			return s, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,43 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+func TestNew_DeclaresParams(t *testing.T) {
+	seen := map[string]bool{}
+	for _, s := range New() {
+		if seen[s.Name()] {
+			t.Errorf("%s listed twice", s.Name())
+		}
+		seen[s.Name()] = true
+		params, ok := strategy.ParamsOf(s)
+		if !ok || len(params) == 0 {
+			t.Errorf("%s declares no params", s.Name())
+			continue
+		}
+		// The declared defaults must themselves be valid params.
+		defaults := make(map[string]any, len(params))
+		for _, p := range params {
+			defaults[p.Name] = p.Default
+		}
+		if err := s.Init(strategy.Config{Params: defaults}); err != nil {
+			t.Errorf("%s: Init(defaults) = %v", s.Name(), err)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	a, ok := Lookup("rsi")
+	if !ok || a.Name() != "rsi" {
+		t.Fatalf("Lookup(rsi) = %v, %v", a, ok)
+	}
+	if b, _ := Lookup("rsi"); a == b {
+		t.Error("Lookup should return a fresh instance")
+	}
+	if _, ok := Lookup("nope"); ok {
+		t.Error("Lookup(nope) should fail")
+	}
+}
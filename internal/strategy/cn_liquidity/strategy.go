@@ -0,0 +1,343 @@
+// Package cn_liquidity signals the China liquidity regime for CN A-share
+// indexes from the PBOC monthly financial statistics Hestia ingests. Three
+// derived series each vote: the trend of the M1–M2 scissors, the TSF stock
+// growth impulse and the credit impulse. When at least two agree and none
+// dissents the regime is easing (buy) or tightening (sell); unanimity makes
+// it strong.
+//
+// Every value is read point in time: only reports published before the date
+// of the last bar count, so a backtest never sees a report, or a revision,
+// before it was out.
+package cn_liquidity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/newthinker/atlas/internal/collector"
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/macro/liquidity"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// DefaultHestiaConfig is the Hestia config naming the statistics store when
+// the hestia_config param is unset; it matches `atlas hestia`'s default.
+const DefaultHestiaConfig = "configs/hestia.yaml"
+
+// errNoSource is returned for CN A-share symbols when no statistics store
+// was wired: emitting nothing would read as a neutral regime.
+var errNoSource = errors.New("cn_liquidity: no PBOC statistics source (run `atlas hestia ingest`)")
+
+// cacheTTL is how long the series known before a date are reused. It spares
+// a cycle one store read per symbol while a live run still picks up a report
+// ingested later the same day.
+const cacheTTL = 6 * time.Hour
+
+// Series are the derived liquidity series, each ascending by period. A
+// point's Period is the YYYY-MM month its report ends.
+type Series struct {
+	// Scissors is M1 YoY less M2 YoY, in percentage points.
+	Scissors []liquidity.Point
+	// TSFImpulse is the change in TSF stock YoY over the impulse window, in
+	// percentage points.
+	TSFImpulse []liquidity.Point
+	// CreditImpulse is the change in trailing 12-month TSF flow as a percent
+	// of the TSF stock a year earlier.
+	CreditImpulse []liquidity.Point
+	// PublishedAt is the latest publication date (YYYY-MM-DD) among the
+	// reports read.
+	PublishedAt string
+}
+
+// Source supplies the series derived from the reports published strictly
+// before a date, the TSF impulse taken over impulseMonths.
+type Source interface {
+	Liquidity(before time.Time, impulseMonths int) (Series, error)
+}
+
+// Strategy emits regime signals for CN A-share indexes from the derived
+// liquidity series.
+type Strategy struct {
+	source Source
+
+	scissorsMonths int
+	impulseMonths  int
+	maxLagMonths   int
+	hestiaConfig   string
+
+	// cache is shared by the copies strategy.WithParams makes.
+	cache *seriesCache
+}
+
+// New creates the cn_liquidity strategy reading its series from source.
+// Without a source Analyze fails on CN A-share symbols.
+func New(source Source) *Strategy {
+	return &Strategy{
+		source:         source,
+		scissorsMonths: 3, impulseMonths: 6, maxLagMonths: 6,
+		hestiaConfig: DefaultHestiaConfig,
+		cache:        &seriesCache{entries: map[string]cacheEntry{}},
+	}
+}
+
+func (s *Strategy) Name() string { return "cn_liquidity" }
+
+func (s *Strategy) Description() string {
+	return "China liquidity regime from the M1-M2 scissors, TSF impulse and credit impulse"
+}
+
+func (s *Strategy) RequiredData() strategy.DataRequirements {
+	// Only the last bar is read: it dates the evaluation and prices the signal.
+	return strategy.DataRequirements{
+		Markets:      []core.Market{core.MarketCNA},
+		AssetTypes:   []core.AssetType{core.AssetIndex},
+		PriceHistory: 1,
+	}
+}
+
+// Params declares the params Init reads; defaults are the current values.
+func (s *Strategy) Params() []strategy.Param {
+	return []strategy.Param{
+		{Name: "scissors_months", Type: strategy.ParamInt, Default: s.scissorsMonths, Min: strategy.Bound(1), Max: strategy.Bound(24),
+			Description: "Months over which the M1-M2 scissors trend is measured"},
+		{Name: "impulse_months", Type: strategy.ParamInt, Default: s.impulseMonths, Min: strategy.Bound(1), Max: strategy.Bound(24),
+			Description: "Months over which the TSF stock growth impulse is measured"},
+		{Name: "max_lag_months", Type: strategy.ParamInt, Default: s.maxLagMonths, Min: strategy.Bound(1), Max: strategy.Bound(24),
+			Description: "Oldest report period, in months before the evaluation date, a series may end on and still vote"},
+		{Name: "hestia_config", Type: strategy.ParamString, Default: s.hestiaConfig,
+			Description: "Hestia config naming the PBOC statistics store; read when serve starts"},
+	}
+}
+
+func (s *Strategy) Init(cfg strategy.Config) error {
+	if err := strategy.ValidateParams(s.Params(), cfg.Params); err != nil {
+		return fmt.Errorf("cn_liquidity: %w", err)
+	}
+	if v, ok := strategy.IntParam(cfg.Params, "scissors_months"); ok {
+		s.scissorsMonths = v
+	}
+	if v, ok := strategy.IntParam(cfg.Params, "impulse_months"); ok {
+		s.impulseMonths = v
+	}
+	if v, ok := strategy.IntParam(cfg.Params, "max_lag_months"); ok {
+		s.maxLagMonths = v
+	}
+	if v, ok := cfg.Params["hestia_config"].(string); ok {
+		s.hestiaConfig = v
+	}
+	if s.hestiaConfig == "" {
+		return fmt.Errorf("cn_liquidity: hestia_config must not be empty")
+	}
+	return nil
+}
+
+func (s *Strategy) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	n := len(ctx.OHLCV)
+	if n == 0 {
+		return nil, nil
+	}
+	market := ctx.Market
+	if market == "" {
+		market = collector.MarketForSymbol(ctx.Symbol)
+	}
+	if market != core.MarketCNA {
+		return nil, nil
+	}
+	if s.source == nil {
+		return nil, errNoSource
+	}
+
+	asOf := ctx.OHLCV[n-1].Time
+	series, err := s.cache.load(s.source, asOf, s.impulseMonths)
+	if err != nil {
+		return nil, fmt.Errorf("cn_liquidity: %w", err)
+	}
+	r := s.read(series, asOf)
+	action, conf := classify(r.score)
+	if action == "" {
+		return nil, nil
+	}
+
+	md := map[string]any{
+		"regime": r.regime(), "liquidity_score": r.score,
+		"liquidity_period": r.period, "liquidity_published_at": series.PublishedAt,
+	}
+	if r.hasScissors {
+		md["scissors"], md["scissors_change"] = r.scissors, r.scissorsChange
+	}
+	if r.hasTSF {
+		md["tsf_impulse"] = r.tsfImpulse
+	}
+	if r.hasCredit {
+		md["credit_impulse"] = r.creditImpulse
+	}
+	return []core.Signal{{
+		Symbol: ctx.Symbol, Action: action, Confidence: conf, Price: ctx.OHLCV[n-1].Close,
+		Reason:   s.reasonText(r),
+		Strategy: s.Name(), GeneratedAt: ctx.Now, Metadata: md,
+	}}, nil
+}
+
+// reading is the latest vote of each series at an evaluation date.
+type reading struct {
+	scissors, scissorsChange float64
+	tsfImpulse               float64
+	creditImpulse            float64
+
+	hasScissors, hasTSF, hasCredit bool
+
+	score  int    // sum of the votes, -3..3
+	period string // latest period among the voting series
+}
+
+// read takes each series' latest point, dropping a series that ends more
+// than maxLagMonths before asOf, and sums the signs of the votes: a rising
+// scissors, a positive TSF impulse and a positive credit impulse each ease.
+func (s *Strategy) read(series Series, asOf time.Time) reading {
+	var r reading
+	fresh := func(p liquidity.Point) bool {
+		lag, ok := monthsBetween(p.Period, asOf)
+		return ok && lag <= s.maxLagMonths
+	}
+	vote := func(p liquidity.Point, v float64) {
+		r.score += sign(v)
+		r.period = max(r.period, p.Period)
+	}
+
+	if last, ok := latest(series.Scissors); ok && fresh(last) {
+		if prev, ok := at(series.Scissors, liquidity.AddMonths(last.Period, -s.scissorsMonths)); ok {
+			r.scissors, r.scissorsChange, r.hasScissors = last.Value, last.Value-prev.Value, true
+			vote(last, r.scissorsChange)
+		}
+	}
+	if last, ok := latest(series.TSFImpulse); ok && fresh(last) {
+		r.tsfImpulse, r.hasTSF = last.Value, true
+		vote(last, last.Value)
+	}
+	if last, ok := latest(series.CreditImpulse); ok && fresh(last) {
+		r.creditImpulse, r.hasCredit = last.Value, true
+		vote(last, last.Value)
+	}
+	return r
+}
+
+func (r reading) regime() string {
+	switch {
+	case r.score >= 2:
+		return "easing"
+	case r.score <= -2:
+		return "tightening"
+	}
+	return "neutral"
+}
+
+func (s *Strategy) reasonText(r reading) string {
+	var parts []string
+	if r.hasScissors {
+		parts = append(parts, fmt.Sprintf("M1-M2 scissors %.2fpp (%+.2fpp over %dm)", r.scissors, r.scissorsChange, s.scissorsMonths))
+	}
+	if r.hasTSF {
+		parts = append(parts, fmt.Sprintf("TSF impulse %+.2fpp over %dm", r.tsfImpulse, s.impulseMonths))
+	}
+	if r.hasCredit {
+		parts = append(parts, fmt.Sprintf("credit impulse %+.2f%%", r.creditImpulse))
+	}
+	return fmt.Sprintf("China liquidity %s (score %+d, as of %s): %s",
+		r.regime(), r.score, r.period, strings.Join(parts, ", "))
+}
+
+// classify maps a liquidity score to (action, confidence); "" means no
+// signal. Three agreeing series are strong, two are a plain signal.
+func classify(score int) (core.Action, float64) {
+	switch {
+	case score >= 3:
+		return core.ActionStrongBuy, 0.8
+	case score == 2:
+		return core.ActionBuy, 0.65
+	case score <= -3:
+		return core.ActionStrongSell, 0.8
+	case score == -2:
+		return core.ActionSell, 0.65
+	}
+	return "", 0
+}
+
+func sign(v float64) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+func latest(pts []liquidity.Point) (liquidity.Point, bool) {
+	if len(pts) == 0 {
+		return liquidity.Point{}, false
+	}
+	return pts[len(pts)-1], true
+}
+
+// at returns the point of period exactly: a gap is no vote rather than a
+// comparison against some older period.
+func at(pts []liquidity.Point, period string) (liquidity.Point, bool) {
+	for i := len(pts) - 1; i >= 0; i-- {
+		if pts[i].Period == period {
+			return pts[i], true
+		}
+	}
+	return liquidity.Point{}, false
+}
+
+// monthsBetween counts the months from a YYYY-MM period to asOf's month.
+func monthsBetween(period string, asOf time.Time) (int, bool) {
+	t, err := time.Parse("2006-01", period)
+	if err != nil {
+		return 0, false
+	}
+	return (asOf.Year()-t.Year())*12 + int(asOf.Month()-t.Month()), true
+}
+
+// seriesCache keeps the series known before each date for cacheTTL. Expired
+// entries are dropped whenever a new one is stored, so a backtest stepping
+// through years of dates does not keep them all.
+type seriesCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	fetchedAt time.Time
+	series    Series
+}
+
+// load returns the series source derives from the reports published before
+// asOf's date, cached per date and impulse window. Failures are not cached.
+func (c *seriesCache) load(source Source, asOf time.Time, impulseMonths int) (Series, error) {
+	before := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	key := fmt.Sprintf("%s/%d", before.Format("2006-01-02"), impulseMonths)
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Since(e.fetchedAt) < cacheTTL {
+		return e.series, nil
+	}
+	series, err := source.Liquidity(before, impulseMonths)
+	if err != nil {
+		return Series{}, err
+	}
+	now := time.Now()
+	c.mu.Lock()
+	for k, e := range c.entries {
+		if now.Sub(e.fetchedAt) >= cacheTTL {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cacheEntry{fetchedAt: now, series: series}
+	c.mu.Unlock()
+	return series, nil
+}
@@ -0,0 +1,201 @@
+package cn_liquidity
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/macro/liquidity"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+func TestStrategy_ImplementsStrategy(t *testing.T) {
+	var _ strategy.Strategy = (*Strategy)(nil)
+}
+
+type sourceStub struct {
+	series  Series
+	err     error
+	befores []time.Time
+	months  []int
+}
+
+func (s *sourceStub) Liquidity(before time.Time, impulseMonths int) (Series, error) {
+	s.befores = append(s.befores, before)
+	s.months = append(s.months, impulseMonths)
+	return s.series, s.err
+}
+
+var evalDay = time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+
+func ctxFor(symbol string, market core.Market) strategy.AnalysisContext {
+	return strategy.AnalysisContext{
+		Symbol: symbol, Market: market, Now: evalDay,
+		OHLCV: []core.OHLCV{{Time: evalDay, Close: 3900}},
+	}
+}
+
+// easing has all three series pointing to looser liquidity as of 2026-09.
+func easing() Series {
+	return Series{
+		Scissors:      []liquidity.Point{{Period: "2026-06", Value: -3.0}, {Period: "2026-09", Value: -1.5}},
+		TSFImpulse:    []liquidity.Point{{Period: "2026-09", Value: 0.4}},
+		CreditImpulse: []liquidity.Point{{Period: "2026-09", Value: 1.2}},
+		PublishedAt:   "2026-10-14",
+	}
+}
+
+func TestAnalyze_Regimes(t *testing.T) {
+	tests := []struct {
+		name   string
+		series func() Series
+		want   core.Action
+	}{
+		{"all easing", easing, core.ActionStrongBuy},
+		{"two easing, one missing", func() Series { s := easing(); s.CreditImpulse = nil; return s }, core.ActionBuy},
+		{"split vote", func() Series { s := easing(); s.TSFImpulse[0].Value = -0.4; return s }, ""},
+		{"all tightening", func() Series {
+			return Series{
+				Scissors:      []liquidity.Point{{Period: "2026-06", Value: -1.0}, {Period: "2026-09", Value: -2.5}},
+				TSFImpulse:    []liquidity.Point{{Period: "2026-09", Value: -0.3}},
+				CreditImpulse: []liquidity.Point{{Period: "2026-09", Value: -0.8}},
+			}
+		}, core.ActionStrongSell},
+		{"two tightening", func() Series {
+			return Series{TSFImpulse: []liquidity.Point{{Period: "2026-09", Value: -0.3}}, CreditImpulse: []liquidity.Point{{Period: "2026-09", Value: -0.8}}}
+		}, core.ActionSell},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(&sourceStub{series: tt.series()})
+			sigs, err := s.Analyze(ctxFor("000300.SH", core.MarketCNA))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == "" {
+				if len(sigs) != 0 {
+					t.Fatalf("want no signal, got %+v", sigs)
+				}
+				return
+			}
+			if len(sigs) != 1 || sigs[0].Action != tt.want {
+				t.Fatalf("want %s, got %+v", tt.want, sigs)
+			}
+		})
+	}
+}
+
+func TestAnalyze_SignalContents(t *testing.T) {
+	src := &sourceStub{series: easing()}
+	sigs, err := New(src).Analyze(ctxFor("000300.SH", core.MarketCNA))
+	if err != nil || len(sigs) != 1 {
+		t.Fatalf("Analyze = %+v, %v", sigs, err)
+	}
+	sig := sigs[0]
+	if sig.Confidence != 0.8 || sig.Price != 3900 || sig.Strategy != "cn_liquidity" {
+		t.Errorf("signal = %+v", sig)
+	}
+	md := sig.Metadata
+	if md["regime"] != "easing" || md["liquidity_score"] != 3 || md["liquidity_period"] != "2026-09" ||
+		md["liquidity_published_at"] != "2026-10-14" {
+		t.Errorf("metadata = %+v", md)
+	}
+	if md["scissors"] != -1.5 || md["scissors_change"] != 1.5 || md["tsf_impulse"] != 0.4 || md["credit_impulse"] != 1.2 {
+		t.Errorf("series metadata = %+v", md)
+	}
+	if !strings.Contains(sig.Reason, "scissors -1.50pp (+1.50pp over 3m)") {
+		t.Errorf("reason = %q", sig.Reason)
+	}
+
+	// The source is asked for what was published before the bar's date.
+	if want := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC); !src.befores[0].Equal(want) || src.months[0] != 6 {
+		t.Errorf("source asked for %v / %d", src.befores[0], src.months[0])
+	}
+}
+
+func TestAnalyze_StaleAndGappedSeriesDoNotVote(t *testing.T) {
+	s := easing()
+	s.TSFImpulse = []liquidity.Point{{Period: "2026-03", Value: 0.4}}                                  // seven months old
+	s.Scissors = []liquidity.Point{{Period: "2026-05", Value: -3.0}, {Period: "2026-09", Value: -1.5}} // no 2026-06 to compare with
+	sigs, err := New(&sourceStub{series: s}).Analyze(ctxFor("000300.SH", core.MarketCNA))
+	if err != nil || len(sigs) != 0 {
+		t.Fatalf("want no signal from a single vote, got %+v, %v", sigs, err)
+	}
+
+	st := New(&sourceStub{series: s})
+	if err := st.Init(strategy.Config{Params: map[string]any{"max_lag_months": 8, "scissors_months": 4}}); err != nil {
+		t.Fatal(err)
+	}
+	sigs, _ = st.Analyze(ctxFor("000300.SH", core.MarketCNA))
+	if len(sigs) != 1 || sigs[0].Action != core.ActionStrongBuy {
+		t.Fatalf("with a longer lag and scissors window: %+v", sigs)
+	}
+}
+
+func TestAnalyze_SkipsOtherMarketsAndRejectsMissingSource(t *testing.T) {
+	src := &sourceStub{series: easing()}
+	if sigs, _ := New(src).Analyze(ctxFor("^GSPC", core.MarketUS)); len(sigs) != 0 {
+		t.Errorf("US index got %+v", sigs)
+	}
+	if len(src.befores) != 0 {
+		t.Error("the source is not read for other markets")
+	}
+	if sigs, _ := New(src).Analyze(ctxFor("000300.SH", "")); len(sigs) != 1 {
+		t.Error("the market falls back to the symbol's")
+	}
+	if _, err := New(nil).Analyze(ctxFor("000300.SH", core.MarketCNA)); !errors.Is(err, errNoSource) {
+		t.Errorf("without a source: err = %v", err)
+	}
+	if sigs, err := New(nil).Analyze(ctxFor("^GSPC", core.MarketUS)); err != nil || len(sigs) != 0 {
+		t.Errorf("without a source, US index: %+v, %v", sigs, err)
+	}
+}
+
+func TestAnalyze_CachesPerDateAndReportsErrors(t *testing.T) {
+	src := &sourceStub{series: easing()}
+	s := New(src)
+	for _, sym := range []string{"000300.SH", "000905.SH"} {
+		if _, err := s.Analyze(ctxFor(sym, core.MarketCNA)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(src.befores) != 1 {
+		t.Errorf("source read %d times for one date", len(src.befores))
+	}
+
+	_, err := New(&sourceStub{err: errors.New("db locked")}).Analyze(ctxFor("000300.SH", core.MarketCNA))
+	if err == nil || !strings.Contains(err.Error(), "cn_liquidity: db locked") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSeriesCache_EvictsExpiredEntries(t *testing.T) {
+	c := &seriesCache{entries: map[string]cacheEntry{
+		"2026-10-14/6": {fetchedAt: time.Now().Add(-cacheTTL)},
+		"2026-10-15/6": {fetchedAt: time.Now()},
+	}}
+	if _, err := c.load(&sourceStub{series: easing()}, evalDay, 6); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.entries["2026-10-14/6"]; ok || len(c.entries) != 2 {
+		t.Errorf("entries = %v, want the expired one dropped", c.entries)
+	}
+}
+
+func TestInit_Params(t *testing.T) {
+	s := New(nil)
+	if err := s.Init(strategy.Config{Params: map[string]any{"hestia_config": "/etc/hestia.yaml", "impulse_months": 3}}); err != nil {
+		t.Fatal(err)
+	}
+	if s.hestiaConfig != "/etc/hestia.yaml" || s.impulseMonths != 3 {
+		t.Errorf("params not applied: %+v", s)
+	}
+	if err := New(nil).Init(strategy.Config{Params: map[string]any{"impulse_months": 0}}); err == nil {
+		t.Error("impulse_months 0 accepted")
+	}
+	if err := New(nil).Init(strategy.Config{Params: map[string]any{"hestia_config": ""}}); err == nil {
+		t.Error("empty hestia_config accepted")
+	}
+}
@@ -0,0 +1,505 @@
+package composite
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/meta"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// Type is the strategies.<name>.type value that declares a composite.
+const Type = "composite"
+
+// Voting modes.
+const (
+	// ModeAll requires every condition to be met.
+	ModeAll = "all"
+	// ModeAny requires at least one condition to be met.
+	ModeAny = "any"
+	// ModeVote requires at least min_votes conditions to be met.
+	ModeVote = "vote"
+)
+
+// Confidence aggregations over the contributing signals.
+const (
+	AggregateWeightedMean = "weighted_mean"
+	AggregateMin          = "min"
+	AggregateMax          = "max"
+)
+
+// Resolver returns the configured child strategy registered under name.
+type Resolver func(name string) (strategy.Strategy, error)
+
+// Composite combines the signals of named child strategies into one signal.
+// Its rule is a meta.CombinationRule: each condition names a child strategy,
+// optionally the action it must signal and a minimum confidence. A condition
+// with no action matches a buy or a sell, so "2 of 3 agree" is three
+// action-less conditions voted with min_votes: 2.
+//
+// The child signals are gathered over the last window bars, so "ma_crossover
+// crossed up within the last 20 bars" can be combined with a signal today.
+type Composite struct {
+	name      string
+	resolve   Resolver
+	rule      meta.CombinationRule
+	mode      string
+	minVotes  int
+	aggregate string
+	window    int
+
+	children []strategy.Strategy
+}
+
+// New creates a composite strategy named name whose children are looked up
+// with resolve. The rule itself comes from Init.
+func New(name string, resolve Resolver) *Composite {
+	return &Composite{
+		name:      name,
+		resolve:   resolve,
+		mode:      ModeAll,
+		aggregate: AggregateWeightedMean,
+		window:    1,
+	}
+}
+
+func (c *Composite) Name() string {
+	return c.name
+}
+
+func (c *Composite) Description() string {
+	parts := make([]string, len(c.rule.Conditions))
+	for i, cond := range c.rule.Conditions {
+		parts[i] = cond.Strategy
+		if cond.Action != "" {
+			parts[i] += " " + string(cond.Action)
+		}
+	}
+	mode := c.mode
+	if mode == ModeVote {
+		mode = fmt.Sprintf("%d of %d", c.minVotes, len(parts))
+	}
+	return fmt.Sprintf("Composite %s (%s)", mode, strings.Join(parts, ", "))
+}
+
+// RequiredData is the union of the children's needs. Asset types and markets
+// are narrowed to those every declaring child supports; Init rejects
+// children that have none in common.
+func (c *Composite) RequiredData() strategy.DataRequirements {
+	var req strategy.DataRequirements
+	for _, child := range c.children {
+		d := child.RequiredData()
+		req.PriceHistory = max(req.PriceHistory, d.PriceHistory)
+		req.Fundamentals = req.Fundamentals || d.Fundamentals
+		for _, spec := range d.Indicators {
+			if !slices.Contains(req.Indicators, spec) {
+				req.Indicators = append(req.Indicators, spec)
+			}
+		}
+		for _, tf := range d.Timeframes {
+			if !slices.Contains(req.Timeframes, tf) {
+				req.Timeframes = append(req.Timeframes, tf)
+			}
+		}
+		for _, m := range d.Valuations {
+			if !slices.Contains(req.Valuations, m) {
+				req.Valuations = append(req.Valuations, m)
+			}
+		}
+		req.AssetTypes = intersect(req.AssetTypes, d.AssetTypes)
+		req.Markets = intersect(req.Markets, d.Markets)
+	}
+	if req.PriceHistory > 0 {
+		req.PriceHistory += c.window - 1
+	}
+	return req
+}
+
+// Params declares the params Init reads; defaults are the current values.
+func (c *Composite) Params() []strategy.Param {
+	return []strategy.Param{
+		{Name: "conditions", Type: strategy.ParamList, Default: c.rule.Conditions,
+			Description: "Child strategies: [{strategy, action, min_confidence, weight}, ...]"},
+		{Name: "mode", Type: strategy.ParamString, Default: c.mode, Options: []string{ModeAll, ModeAny, ModeVote},
+			Description: "all, any or at least min_votes conditions must be met"},
+		{Name: "min_votes", Type: strategy.ParamInt, Default: c.minVotes, Min: strategy.Bound(1),
+			Description: "Conditions needed in vote mode; default a majority"},
+		{Name: "aggregate", Type: strategy.ParamString, Default: c.aggregate,
+			Options:     []string{AggregateWeightedMean, AggregateMin, AggregateMax},
+			Description: "How the contributing confidences combine"},
+		{Name: "window", Type: strategy.ParamInt, Default: c.window, Min: strategy.Bound(1),
+			Description: "Bars a child signal stays valid"},
+		{Name: "action", Type: strategy.ParamString, Default: string(c.rule.Action),
+			Options:     []string{string(core.ActionBuy), string(core.ActionSell), string(core.ActionStrongBuy), string(core.ActionStrongSell)},
+			Description: "Action to emit instead of the agreed one"},
+		{Name: "confidence", Type: strategy.ParamNumber, Default: c.rule.Confidence, Min: strategy.Bound(0), Max: strategy.Bound(1),
+			Description: "Fixed confidence to emit instead of the aggregate; 0 = aggregate"},
+	}
+}
+
+// conditionParams are the keys of one conditions entry.
+var conditionParams = []strategy.Param{
+	{Name: "strategy", Type: strategy.ParamString},
+	{Name: "action", Type: strategy.ParamString},
+	{Name: "min_confidence", Type: strategy.ParamNumber, Min: strategy.Bound(0), Max: strategy.Bound(1)},
+	{Name: "weight", Type: strategy.ParamNumber, Min: strategy.Bound(0)},
+}
+
+// Init reads the rule from params:
+//
+//	conditions: [{strategy, action, min_confidence, weight}, ...]
+//	mode:       all | any | vote (default all)
+//	min_votes:  conditions needed in vote mode (default a majority)
+//	aggregate:  weighted_mean | min | max (default weighted_mean)
+//	window:     bars a child signal stays valid (default 1, the current bar)
+//	action:     action to emit instead of the agreed one
+//	confidence: fixed confidence to emit instead of the aggregate
+func (c *Composite) Init(cfg strategy.Config) error {
+	if err := strategy.ValidateParams(c.Params(), cfg.Params); err != nil {
+		return fmt.Errorf("%s: %w", c.name, err)
+	}
+	conds, err := parseConditions(cfg.Params["conditions"])
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.name, err)
+	}
+	if conds != nil {
+		c.rule.Conditions = conds
+	}
+	if len(c.rule.Conditions) == 0 {
+		return fmt.Errorf("%s: composite needs at least one condition", c.name)
+	}
+	if v, ok := cfg.Params["mode"].(string); ok {
+		c.mode = strings.ToLower(v)
+	}
+	if v, ok := cfg.Params["aggregate"].(string); ok {
+		c.aggregate = strings.ToLower(v)
+	}
+	if v, ok := cfg.Params["action"].(string); ok {
+		c.rule.Action = core.Action(strings.ToLower(v))
+	}
+	if v, ok := strategy.NumParam(cfg.Params, "confidence"); ok {
+		c.rule.Confidence = v
+	}
+	if v, ok := strategy.IntParam(cfg.Params, "window"); ok {
+		c.window = v
+	}
+	if v, ok := strategy.IntParam(cfg.Params, "min_votes"); ok {
+		c.minVotes = v
+	}
+	if c.mode == ModeVote && c.minVotes == 0 {
+		c.minVotes = len(c.rule.Conditions)/2 + 1
+	}
+
+	switch {
+	case c.mode != ModeAll && c.mode != ModeAny && c.mode != ModeVote:
+		return fmt.Errorf("%s: mode must be all, any or vote, got %q", c.name, c.mode)
+	case c.mode == ModeVote && (c.minVotes < 1 || c.minVotes > len(c.rule.Conditions)):
+		return fmt.Errorf("%s: min_votes must be between 1 and %d, got %d", c.name, len(c.rule.Conditions), c.minVotes)
+	case c.aggregate != AggregateWeightedMean && c.aggregate != AggregateMin && c.aggregate != AggregateMax:
+		return fmt.Errorf("%s: aggregate must be weighted_mean, min or max, got %q", c.name, c.aggregate)
+	case c.window < 1:
+		return fmt.Errorf("%s: window must be at least 1, got %d", c.name, c.window)
+	case c.rule.Confidence < 0 || c.rule.Confidence > 1:
+		return fmt.Errorf("%s: confidence must be within [0, 1], got %g", c.name, c.rule.Confidence)
+	}
+
+	children := make([]strategy.Strategy, len(c.rule.Conditions))
+	for i, cond := range c.rule.Conditions {
+		if cond.Strategy == c.name {
+			return fmt.Errorf("%s: composite cannot include itself", c.name)
+		}
+		if c.resolve == nil {
+			return fmt.Errorf("%s: no strategies to resolve %q from", c.name, cond.Strategy)
+		}
+		child, err := c.resolve(cond.Strategy)
+		if err != nil {
+			return fmt.Errorf("%s: condition %d: %w", c.name, i+1, err)
+		}
+		if strategy.IsCrossSectional(child) {
+			// Its signals come from ranking a group, never from one symbol.
+			return fmt.Errorf("%s: condition %d: %s ranks a group of symbols and cannot be combined", c.name, i+1, cond.Strategy)
+		}
+		children[i] = child
+	}
+	c.children = children
+
+	// An empty, non-nil list means no asset suits every child, unlike the
+	// nil "any" a composite without declaring children gets.
+	req := c.RequiredData()
+	if req.AssetTypes != nil && len(req.AssetTypes) == 0 {
+		c.children = nil
+		return fmt.Errorf("%s: conditions share no asset type", c.name)
+	}
+	if req.Markets != nil && len(req.Markets) == 0 {
+		c.children = nil
+		return fmt.Errorf("%s: conditions share no market", c.name)
+	}
+	return nil
+}
+
+// vote is a condition matched by a child signal.
+type vote struct {
+	cond   meta.SignalCondition
+	signal core.Signal
+}
+
+func (c *Composite) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	if len(ctx.OHLCV) == 0 || len(c.children) == 0 {
+		return nil, nil
+	}
+
+	// Each child's most recent signal within the window.
+	latest := make([]*core.Signal, len(c.children))
+	for i, child := range c.children {
+		for offset := 0; offset < c.window && offset < len(ctx.OHLCV); offset++ {
+			sigs, err := child.Analyze(shift(ctx, offset))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", c.name, child.Name(), err)
+			}
+			if s := directional(sigs); s != nil {
+				latest[i] = s
+				break
+			}
+		}
+	}
+
+	var best []vote
+	var bestWeight float64
+	tie := false
+	for _, dir := range []core.Action{core.ActionBuy, core.ActionSell} {
+		votes := c.votes(latest, dir)
+		if !c.passes(len(votes)) {
+			continue
+		}
+		w := weightOf(votes)
+		switch {
+		case best == nil || w > bestWeight:
+			best, bestWeight, tie = votes, w, false
+		case w == bestWeight:
+			tie = true
+		}
+	}
+	if best == nil || tie {
+		return nil, nil // nothing agreed, or buy and sell carry equal weight
+	}
+
+	action := direction(best[0].signal.Action)
+	if c.rule.Action != "" {
+		action = c.rule.Action
+	}
+	confidence := c.rule.Confidence
+	if confidence == 0 {
+		confidence = c.aggregateConfidence(best)
+	}
+
+	reasons := make([]string, len(best))
+	contributors := make([]map[string]any, len(best))
+	for i, v := range best {
+		reasons[i] = fmt.Sprintf("%s %s (%.2f)", v.cond.Strategy, v.signal.Action, v.signal.Confidence)
+		contributors[i] = map[string]any{
+			"strategy":     v.cond.Strategy,
+			"action":       string(v.signal.Action),
+			"confidence":   v.signal.Confidence,
+			"weight":       weight(v.cond),
+			"reason":       v.signal.Reason,
+			"generated_at": v.signal.GeneratedAt,
+		}
+	}
+
+	return []core.Signal{{
+		Symbol:      ctx.Symbol,
+		Action:      action,
+		Price:       ctx.OHLCV[len(ctx.OHLCV)-1].Close,
+		Confidence:  confidence,
+		Reason:      fmt.Sprintf("%s: %s", c.Description(), strings.Join(reasons, " + ")),
+		GeneratedAt: ctx.Now,
+		Metadata: map[string]any{
+			"type":         "composite",
+			"mode":         c.mode,
+			"votes":        len(best),
+			"conditions":   len(c.rule.Conditions),
+			"contributors": contributors,
+		},
+	}}, nil
+}
+
+// votes returns the conditions met in direction dir.
+func (c *Composite) votes(latest []*core.Signal, dir core.Action) []vote {
+	var out []vote
+	for i, cond := range c.rule.Conditions {
+		s := latest[i]
+		if s == nil || direction(s.Action) != dir || s.Confidence < cond.MinConf {
+			continue
+		}
+		if cond.Action != "" && cond.Action != s.Action && cond.Action != dir {
+			continue // e.g. the condition asks for strong_buy and got buy
+		}
+		out = append(out, vote{cond: cond, signal: *s})
+	}
+	return out
+}
+
+// passes applies the voting mode to n met conditions.
+func (c *Composite) passes(n int) bool {
+	switch c.mode {
+	case ModeAny:
+		return n >= 1
+	case ModeVote:
+		return n >= c.minVotes
+	default:
+		return n == len(c.rule.Conditions)
+	}
+}
+
+// aggregateConfidence combines the contributing confidences.
+func (c *Composite) aggregateConfidence(votes []vote) float64 {
+	switch c.aggregate {
+	case AggregateMin, AggregateMax:
+		conf := votes[0].signal.Confidence
+		for _, v := range votes[1:] {
+			if c.aggregate == AggregateMin {
+				conf = min(conf, v.signal.Confidence)
+			} else {
+				conf = max(conf, v.signal.Confidence)
+			}
+		}
+		return conf
+	default:
+		var sum float64
+		for _, v := range votes {
+			sum += weight(v.cond) * v.signal.Confidence
+		}
+		return sum / weightOf(votes)
+	}
+}
+
+// shift returns ctx as it looked offset bars ago.
+func shift(ctx strategy.AnalysisContext, offset int) strategy.AnalysisContext {
+	if offset == 0 {
+		return ctx
+	}
+	n := len(ctx.OHLCV) - offset
+	past := ctx
+	past.OHLCV = ctx.OHLCV[:n]
+	past.Now = ctx.OHLCV[n-1].Time
+	past.LatestQuote = nil
+	if ctx.Indicators != nil {
+		past.Indicators = make(map[string][]float64, len(ctx.Indicators))
+		for k, series := range ctx.Indicators {
+			past.Indicators[k] = series[:max(len(series)-offset, 0)]
+		}
+	}
+	if ctx.Bars != nil {
+		// Resampled bars are rebuilt from the shifted daily bars, so the
+		// current week or month ends at the shifted day; intraday bars are
+		// cut after it.
+		past.Bars = make(map[string][]core.OHLCV, len(ctx.Bars))
+		for tf, bars := range ctx.Bars {
+			if strategy.Resampled(tf) {
+				past.Bars[tf] = strategy.Resample(past.OHLCV, tf)
+				continue
+			}
+			y, m, d := past.Now.Date()
+			dayEnd := time.Date(y, m, d+1, 0, 0, 0, 0, past.Now.Location())
+			n := sort.Search(len(bars), func(i int) bool { return !bars[i].Time.Before(dayEnd) })
+			past.Bars[tf] = bars[:n]
+		}
+	}
+	return past
+}
+
+// directional returns the first buy- or sell-side signal in sigs.
+func directional(sigs []core.Signal) *core.Signal {
+	for i := range sigs {
+		if direction(sigs[i].Action) != "" {
+			return &sigs[i]
+		}
+	}
+	return nil
+}
+
+// direction folds strong actions into buy or sell; hold has none.
+func direction(a core.Action) core.Action {
+	switch a {
+	case core.ActionBuy, core.ActionStrongBuy:
+		return core.ActionBuy
+	case core.ActionSell, core.ActionStrongSell:
+		return core.ActionSell
+	}
+	return ""
+}
+
+func weight(cond meta.SignalCondition) float64 {
+	if cond.Weight > 0 {
+		return cond.Weight
+	}
+	return 1
+}
+
+func weightOf(votes []vote) float64 {
+	var w float64
+	for _, v := range votes {
+		w += weight(v.cond)
+	}
+	return w
+}
+
+// intersect narrows acc to the values in next. An empty next means "any" and
+// leaves acc unchanged; a nil acc has not been narrowed yet and takes next.
+// Disjoint lists give a non-nil empty slice, which Init rejects.
+func intersect[T comparable](acc, next []T) []T {
+	if len(next) == 0 {
+		return acc
+	}
+	if acc == nil {
+		return slices.Clone(next)
+	}
+	out := make([]T, 0, len(acc))
+	for _, v := range acc {
+		if slices.Contains(next, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// parseConditions decodes the conditions param as YAML (via viper) or JSON
+// hands it over: a list of maps. A nil result means the param is absent.
+func parseConditions(raw any) ([]meta.SignalCondition, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	list, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("conditions must be a list")
+	}
+	conds := make([]meta.SignalCondition, 0, len(list))
+	for i, item := range list {
+		m, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("condition %d must be a map", i+1)
+		}
+		if err := strategy.ValidateParams(conditionParams, m); err != nil {
+			return nil, fmt.Errorf("condition %d: %w", i+1, err)
+		}
+		var cond meta.SignalCondition
+		cond.Strategy, _ = m["strategy"].(string)
+		if cond.Strategy == "" {
+			return nil, fmt.Errorf("condition %d: strategy is required", i+1)
+		}
+		if a, ok := m["action"].(string); ok {
+			cond.Action = core.Action(strings.ToLower(a))
+			if direction(cond.Action) == "" {
+				return nil, fmt.Errorf("condition %d: action must be buy, sell, strong_buy or strong_sell, got %q", i+1, a)
+			}
+		}
+		cond.MinConf, _ = strategy.NumParam(m, "min_confidence")
+		cond.Weight, _ = strategy.NumParam(m, "weight")
+		conds = append(conds, cond)
+	}
+	return conds, nil
+}
@@ -0,0 +1,338 @@
+package composite
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+func TestComposite_ImplementsStrategy(t *testing.T) {
+	var _ strategy.Strategy = (*Composite)(nil)
+}
+
+// stubStrategy emits a fixed signal on the bar closing at fireOn, or on
+// every bar when fireOn is zero.
+type stubStrategy struct {
+	name       string
+	action     core.Action
+	confidence float64
+	fireOn     float64
+	req        strategy.DataRequirements
+}
+
+func (s *stubStrategy) Name() string                            { return s.name }
+func (s *stubStrategy) Description() string                     { return s.name }
+func (s *stubStrategy) RequiredData() strategy.DataRequirements { return s.req }
+func (s *stubStrategy) Init(cfg strategy.Config) error          { return nil }
+func (s *stubStrategy) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	if s.action == "" {
+		return nil, nil
+	}
+	if last := ctx.OHLCV[len(ctx.OHLCV)-1].Close; s.fireOn != 0 && last != s.fireOn {
+		return nil, nil
+	}
+	return []core.Signal{{Symbol: ctx.Symbol, Action: s.action, Confidence: s.confidence, Reason: s.name + " fired"}}, nil
+}
+
+func resolverOf(children ...*stubStrategy) Resolver {
+	return func(name string) (strategy.Strategy, error) {
+		for _, c := range children {
+			if c.name == name {
+				return c, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown strategy %q", name)
+	}
+}
+
+func barsFromCloses(closes ...float64) []core.OHLCV {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := make([]core.OHLCV, len(closes))
+	for i, c := range closes {
+		bars[i] = core.OHLCV{Symbol: "T", Close: c, Time: base.AddDate(0, 0, i)}
+	}
+	return bars
+}
+
+func cond(name string, extra ...any) map[string]any {
+	m := map[string]any{"strategy": name}
+	for i := 0; i+1 < len(extra); i += 2 {
+		m[extra[i].(string)] = extra[i+1]
+	}
+	return m
+}
+
+func newComposite(t *testing.T, params map[string]any, children ...*stubStrategy) *Composite {
+	t.Helper()
+	c := New("combo", resolverOf(children...))
+	if err := c.Init(strategy.Config{Params: params}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	return c
+}
+
+func analyze(t *testing.T, c *Composite, closes ...float64) []core.Signal {
+	t.Helper()
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	sigs, err := c.Analyze(strategy.AnalysisContext{Symbol: "T", Now: now, OHLCV: barsFromCloses(closes...)})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	return sigs
+}
+
+func TestComposite_AllRequiresEveryCondition(t *testing.T) {
+	value := &stubStrategy{name: "value", action: core.ActionBuy, confidence: 0.8}
+	trend := &stubStrategy{name: "trend", action: core.ActionBuy, confidence: 0.6}
+	c := newComposite(t, map[string]any{"conditions": []any{
+		cond("value", "action", "buy"),
+		cond("trend", "action", "buy"),
+	}}, value, trend)
+
+	sigs := analyze(t, c, 100, 101)
+	if len(sigs) != 1 {
+		t.Fatalf("expected one signal, got %v", sigs)
+	}
+	sig := sigs[0]
+	if sig.Action != core.ActionBuy || sig.Price != 101 || sig.Confidence != 0.7 {
+		t.Errorf("signal = %+v", sig)
+	}
+	if !sig.GeneratedAt.Equal(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("GeneratedAt = %v, want ctx.Now", sig.GeneratedAt)
+	}
+	contributors, _ := sig.Metadata["contributors"].([]map[string]any)
+	if sig.Metadata["type"] != "composite" || len(contributors) != 2 || contributors[0]["strategy"] != "value" {
+		t.Errorf("metadata = %v", sig.Metadata)
+	}
+
+	trend.action = core.ActionSell
+	if sigs := analyze(t, c, 100, 101); len(sigs) != 0 {
+		t.Errorf("expected no signal when a condition fails, got %v", sigs)
+	}
+}
+
+func TestComposite_MinConfidence(t *testing.T) {
+	value := &stubStrategy{name: "value", action: core.ActionBuy, confidence: 0.55}
+	c := newComposite(t, map[string]any{"conditions": []any{
+		cond("value", "min_confidence", 0.6),
+	}}, value)
+	if sigs := analyze(t, c, 100); len(sigs) != 0 {
+		t.Errorf("expected a signal below min_confidence to be ignored, got %v", sigs)
+	}
+}
+
+func TestComposite_VoteWithWeights(t *testing.T) {
+	a := &stubStrategy{name: "a", action: core.ActionSell, confidence: 0.9}
+	b := &stubStrategy{name: "b", action: core.ActionSell, confidence: 0.5}
+	c3 := &stubStrategy{name: "c", action: core.ActionBuy, confidence: 0.9}
+	c := newComposite(t, map[string]any{
+		"mode": "vote",
+		"conditions": []any{
+			cond("a", "weight", 3),
+			cond("b"),
+			cond("c"),
+		},
+	}, a, b, c3)
+	if c.minVotes != 2 {
+		t.Errorf("default min_votes = %d, want a majority of 2", c.minVotes)
+	}
+
+	sigs := analyze(t, c, 100)
+	if len(sigs) != 1 || sigs[0].Action != core.ActionSell {
+		t.Fatalf("expected the 2-of-3 sell, got %v", sigs)
+	}
+	// (3*0.9 + 1*0.5) / 4
+	if got := sigs[0].Confidence; got < 0.7999 || got > 0.8001 {
+		t.Errorf("weighted confidence = %v, want 0.8", got)
+	}
+	if sigs[0].Metadata["votes"] != 2 {
+		t.Errorf("votes = %v, want 2", sigs[0].Metadata["votes"])
+	}
+}
+
+func TestComposite_AnyPicksHeavierSideAndSkipsTies(t *testing.T) {
+	up := &stubStrategy{name: "up", action: core.ActionBuy, confidence: 0.7}
+	down := &stubStrategy{name: "down", action: core.ActionStrongSell, confidence: 0.8}
+	tied := newComposite(t, map[string]any{"mode": "any", "conditions": []any{cond("up"), cond("down")}}, up, down)
+	if sigs := analyze(t, tied, 100); len(sigs) != 0 {
+		t.Errorf("expected no signal on an equal-weight conflict, got %v", sigs)
+	}
+
+	heavier := newComposite(t, map[string]any{"mode": "any", "conditions": []any{cond("up"), cond("down", "weight", 2)}}, up, down)
+	sigs := analyze(t, heavier, 100)
+	if len(sigs) != 1 || sigs[0].Action != core.ActionSell {
+		t.Errorf("expected the heavier sell side, got %v", sigs)
+	}
+}
+
+func TestComposite_ActionAndConfidenceOverride(t *testing.T) {
+	a := &stubStrategy{name: "a", action: core.ActionBuy, confidence: 0.6}
+	b := &stubStrategy{name: "b", action: core.ActionBuy, confidence: 0.9}
+	c := newComposite(t, map[string]any{
+		"conditions": []any{cond("a", "action", "BUY"), cond("b", "action", "buy")},
+		"action":     "strong_buy",
+		"aggregate":  "min",
+	}, a, b)
+	sigs := analyze(t, c, 100)
+	if len(sigs) != 1 || sigs[0].Action != core.ActionStrongBuy || sigs[0].Confidence != 0.6 {
+		t.Errorf("signal = %v", sigs)
+	}
+
+	fixed := newComposite(t, map[string]any{
+		"conditions": []any{cond("a"), cond("b")},
+		"confidence": 0.75,
+	}, a, b)
+	if sigs := analyze(t, fixed, 100); len(sigs) != 1 || sigs[0].Confidence != 0.75 {
+		t.Errorf("signal = %v", sigs)
+	}
+}
+
+func TestComposite_WindowKeepsRecentChildSignals(t *testing.T) {
+	cross := &stubStrategy{name: "cross", action: core.ActionBuy, confidence: 0.8, fireOn: 102} // fired two bars ago
+	value := &stubStrategy{name: "value", action: core.ActionBuy, confidence: 0.6}
+	params := map[string]any{"conditions": []any{cond("cross"), cond("value")}}
+
+	if sigs := analyze(t, newComposite(t, params, cross, value), 100, 101, 102, 103, 104); len(sigs) != 0 {
+		t.Errorf("window 1: expected no signal, got %v", sigs)
+	}
+	params["window"] = 3
+	c := newComposite(t, params, cross, value)
+	sigs := analyze(t, c, 100, 101, 102, 103, 104)
+	if len(sigs) != 1 {
+		t.Fatalf("window 3: expected one signal, got %v", sigs)
+	}
+	contributors := sigs[0].Metadata["contributors"].([]map[string]any)
+	if contributors[0]["strategy"] != "cross" {
+		t.Errorf("contributors = %v", contributors)
+	}
+}
+
+func TestComposite_RequiredDataMergesChildren(t *testing.T) {
+	a := &stubStrategy{name: "a", req: strategy.DataRequirements{
+		PriceHistory: 210, Indicators: []string{"sma_50", "sma_200"}, Timeframes: []string{"1w"},
+		AssetTypes: []core.AssetType{core.AssetStock, core.AssetETF},
+	}}
+	b := &stubStrategy{name: "b", req: strategy.DataRequirements{
+		PriceHistory: 140, Fundamentals: true, Indicators: []string{"rsi_14", "sma_50"}, Timeframes: []string{"1w", "1h"},
+		Valuations: []string{core.MetricPB}, AssetTypes: []core.AssetType{core.AssetStock},
+	}}
+	c := newComposite(t, map[string]any{"window": 5, "conditions": []any{cond("a"), cond("b")}}, a, b)
+
+	req := c.RequiredData()
+	if req.PriceHistory != 214 || !req.Fundamentals {
+		t.Errorf("PriceHistory=%d Fundamentals=%v, want 214 true", req.PriceHistory, req.Fundamentals)
+	}
+	if len(req.Indicators) != 3 {
+		t.Errorf("Indicators = %v, want the 3 distinct specs", req.Indicators)
+	}
+	if strings.Join(req.Timeframes, ",") != "1w,1h" {
+		t.Errorf("Timeframes = %v, want [1w 1h]", req.Timeframes)
+	}
+	if strings.Join(req.Valuations, ",") != core.MetricPB {
+		t.Errorf("Valuations = %v, want [pb]", req.Valuations)
+	}
+	if len(req.AssetTypes) != 1 || req.AssetTypes[0] != core.AssetStock {
+		t.Errorf("AssetTypes = %v, want [stock]", req.AssetTypes)
+	}
+}
+
+func TestComposite_RejectsDisjointChildren(t *testing.T) {
+	stocks := &stubStrategy{name: "stocks", req: strategy.DataRequirements{AssetTypes: []core.AssetType{core.AssetStock}}}
+	etfs := &stubStrategy{name: "etfs", req: strategy.DataRequirements{AssetTypes: []core.AssetType{core.AssetETF}}}
+	us := &stubStrategy{name: "us", req: strategy.DataRequirements{Markets: []core.Market{core.MarketUS}}}
+	hk := &stubStrategy{name: "hk", req: strategy.DataRequirements{Markets: []core.Market{core.MarketHK, core.MarketCNA}}}
+	resolve := resolverOf(stocks, etfs, us, hk)
+	for names, want := range map[[2]string]string{
+		{"stocks", "etfs"}: "share no asset type",
+		{"us", "hk"}:       "share no market",
+	} {
+		err := New("combo", resolve).Init(strategy.Config{Params: map[string]any{
+			"conditions": []any{cond(names[0]), cond(names[1])}}})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%v: Init = %v, want %q", names, err, want)
+		}
+	}
+
+	// A child without markets applies anywhere and leaves the other's alone.
+	c := newComposite(t, map[string]any{"conditions": []any{cond("stocks"), cond("hk")}}, stocks, hk)
+	if req := c.RequiredData(); len(req.Markets) != 2 || len(req.AssetTypes) != 1 {
+		t.Errorf("RequiredData = %+v, want hk's markets and stocks", req)
+	}
+}
+
+func TestShift_Timeframes(t *testing.T) {
+	daily := barsFromCloses(100, 101, 102, 103, 104, 105, 106, 107) // Wed 1 Jan to Wed 8 Jan 2020
+	var hourly []core.OHLCV
+	for _, b := range daily {
+		hourly = append(hourly, core.OHLCV{Close: b.Close, Time: b.Time.Add(10 * time.Hour)})
+	}
+	ctx := strategy.AnalysisContext{OHLCV: daily, Bars: map[string][]core.OHLCV{
+		"1w": strategy.Resample(daily, "1w"),
+		"1h": hourly,
+	}}
+
+	past := shift(ctx, 3) // as of Sun 5 Jan
+	weekly := past.Bars["1w"]
+	if len(weekly) != 1 || weekly[0].Close != 104 {
+		t.Errorf("weekly bars = %+v, want one week closing at 104", weekly)
+	}
+	if got := past.Bars["1h"]; len(got) != 5 || got[4].Close != 104 {
+		t.Errorf("hourly bars = %+v, want the five up to 5 Jan", got)
+	}
+	if len(ctx.Bars["1w"]) != 2 {
+		t.Error("shift must not modify the current bars")
+	}
+}
+
+func TestComposite_InitErrors(t *testing.T) {
+	a := &stubStrategy{name: "a"}
+	for _, params := range []map[string]any{
+		{},
+		{"conditions": "a"},
+		{"conditions": []any{cond("missing")}},
+		{"conditions": []any{cond("combo")}},
+		{"conditions": []any{map[string]any{"action": "buy"}}},
+		{"conditions": []any{cond("a", "action", "hold")}},
+		{"conditions": []any{cond("a")}, "mode": "majority"},
+		{"conditions": []any{cond("a")}, "mode": "vote", "min_votes": 2},
+		{"conditions": []any{cond("a")}, "aggregate": "median"},
+		{"conditions": []any{cond("a")}, "window": 0},
+		{"conditions": []any{cond("a", "min_conf", 0.5)}},
+		{"conditions": []any{cond("a")}, "windwo": 5},
+	} {
+		if err := New("combo", resolverOf(a)).Init(strategy.Config{Params: params}); err == nil {
+			t.Errorf("Init(%v): expected error", params)
+		}
+	}
+}
+
+// rankStub is a cross-sectional stub.
+type rankStub struct{ stubStrategy }
+
+func (r *rankStub) AnalyzeGroup(ctxs []strategy.AnalysisContext) ([]core.Signal, error) {
+	return nil, nil
+}
+
+func TestComposite_RejectsCrossSectionalChildren(t *testing.T) {
+	resolve := func(name string) (strategy.Strategy, error) { return &rankStub{stubStrategy{name: name}}, nil }
+	err := New("combo", resolve).Init(strategy.Config{Params: map[string]any{"conditions": []any{cond("rotation")}}})
+	if err == nil || !strings.Contains(err.Error(), "rotation ranks a group of symbols") {
+		t.Errorf("Init = %v, want a cross-sectional child rejected", err)
+	}
+}
+
+func TestComposite_Description(t *testing.T) {
+	c := newComposite(t, map[string]any{"mode": "vote", "min_votes": 2, "conditions": []any{
+		cond("a", "action", "buy"), cond("b"), cond("c"),
+	}}, &stubStrategy{name: "a"}, &stubStrategy{name: "b"}, &stubStrategy{name: "c"})
+	if got, want := c.Description(), "Composite 2 of 3 (a buy, b, c)"; got != want {
+		t.Errorf("Description() = %q, want %q", got, want)
+	}
+	if c.Name() != "combo" {
+		t.Errorf("Name() = %q, want combo", c.Name())
+	}
+}
@@ -0,0 +1,21 @@
+package strategy
+
+import (
+	"github.com/newthinker/atlas/internal/core"
+)
+
+// CrossSectional is implemented by strategies that rank a group of symbols
+// against each other, such as a rotation into the strongest few. AnalyzeGroup
+// receives one context per symbol of the group, all as of the same time, and
+// returns signals for any of them. Analyze, which sees a single symbol, has
+// nothing to rank and returns no signals.
+type CrossSectional interface {
+	Strategy
+	AnalyzeGroup(ctxs []AnalysisContext) ([]core.Signal, error)
+}
+
+// IsCrossSectional reports whether s ranks groups of symbols.
+func IsCrossSectional(s Strategy) bool {
+	_, ok := s.(CrossSectional)
+	return ok
+}
@@ -36,8 +36,19 @@ func (d *DividendYield) RequiredData() strategy.DataRequirements {
 	}
 }
 
+// Params declares the params Init reads; defaults are the current values.
+func (d *DividendYield) Params() []strategy.Param {
+	return []strategy.Param{
+		{Name: "min_yield", Type: strategy.ParamNumber, Default: d.minYield, Min: strategy.Bound(0),
+			Description: "Buy when the dividend yield (%) is at or above this"},
+	}
+}
+
 func (d *DividendYield) Init(cfg strategy.Config) error {
-	if yield, ok := cfg.Params["min_yield"].(float64); ok {
+	if err := strategy.ValidateParams(d.Params(), cfg.Params); err != nil {
+		return fmt.Errorf("dividend_yield: %w", err)
+	}
+	if yield, ok := strategy.NumParam(cfg.Params, "min_yield"); ok {
 		d.minYield = yield
 	}
 	return nil
@@ -140,3 +140,27 @@ func (e *Engine) AnalyzeWithStrategies(ctx context.Context, analysisCtx Analysis
 
 	return allSignals, nil
 }
+
+// AnalyzeGroup runs the cross-sectional strategy called name over a group of
+// symbols. It returns nothing when no such strategy is registered.
+func (e *Engine) AnalyzeGroup(ctx context.Context, name string, group []AnalysisContext) ([]core.Signal, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s, ok := e.Get(name)
+	if !ok {
+		return nil, nil
+	}
+	cs, ok := s.(CrossSectional)
+	if !ok {
+		return nil, nil
+	}
+	signals, err := cs.AnalyzeGroup(group)
+	if err != nil {
+		return nil, err
+	}
+	for i := range signals {
+		signals[i].Strategy = s.Name()
+	}
+	return signals, nil
+}
@@ -95,3 +95,30 @@ func TestEngine_AnalyzeWithStrategies(t *testing.T) {
 		t.Errorf("expected strategy s1, got %s", signals[0].Strategy)
 	}
 }
+
+// groupStrategy buys the first symbol of every group it is given.
+type groupStrategy struct{ mockStrategy }
+
+func (g *groupStrategy) AnalyzeGroup(ctxs []AnalysisContext) ([]core.Signal, error) {
+	return []core.Signal{{Symbol: ctxs[0].Symbol, Action: core.ActionBuy}}, nil
+}
+
+func TestEngine_AnalyzeGroup(t *testing.T) {
+	engine := NewEngine()
+	engine.Register(&groupStrategy{mockStrategy{name: "rotation"}})
+	engine.Register(&mockStrategy{name: "plain"})
+	group := []AnalysisContext{{Symbol: "SPY"}, {Symbol: "QQQ"}}
+
+	signals, err := engine.AnalyzeGroup(context.Background(), "rotation", group)
+	if err != nil || len(signals) != 1 || signals[0].Symbol != "SPY" || signals[0].Strategy != "rotation" {
+		t.Errorf("AnalyzeGroup(rotation) = %v, %v", signals, err)
+	}
+	for _, name := range []string{"plain", "missing"} {
+		if signals, err := engine.AnalyzeGroup(context.Background(), name, group); err != nil || signals != nil {
+			t.Errorf("AnalyzeGroup(%s) = %v, %v, want nothing", name, signals, err)
+		}
+	}
+	if !IsCrossSectional(&groupStrategy{}) || IsCrossSectional(&mockStrategy{}) {
+		t.Error("IsCrossSectional misreports")
+	}
+}
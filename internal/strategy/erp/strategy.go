@@ -0,0 +1,364 @@
+// Package erp signals when an index's equity risk premium — its earnings
+// yield (1/PE) less the 10-year government bond yield — sits at an extreme of
+// its own multi-year history. A high premium means equities are cheap against
+// bonds and is a buy; a low one is a sell. US indexes take their yield from
+// FRED, CN indexes from a configurable CGB yield source.
+package erp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/newthinker/atlas/internal/collector"
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// Yield source names, the keys of the sources map given to New.
+const (
+	SourceFRED    = "fred"
+	SourceAkshare = "akshare"
+)
+
+// minPoints is the fewest premium days a percentile is ranked against: one
+// year of trading days, as for the rebuilt PE percentiles.
+const minPoints = 252
+
+// cacheTTL is how long fetched PE and yield series are reused. It spares a
+// backtest one fetch per bar while a live run still sees each day's data.
+const cacheTTL = 6 * time.Hour
+
+// Point is one dated value of a PE or yield series.
+type Point struct {
+	Date  time.Time
+	Value float64
+}
+
+// PEHistory supplies an index's daily PE(TTM) from a date on; a symbol it
+// does not track yields no points and no error.
+type PEHistory interface {
+	PEHistory(symbol string, from time.Time) ([]Point, error)
+}
+
+// YieldSource supplies a government bond yield series in percent.
+type YieldSource interface {
+	Yields(series string, start, end time.Time) ([]Point, error)
+}
+
+// Strategy emits buy/sell signals from the percentile of an index's equity
+// risk premium within its own history, computed point in time at the last
+// bar of the analysis context.
+type Strategy struct {
+	pe      PEHistory
+	sources map[string]YieldSource
+
+	lookbackYears int
+	low, high     float64
+	extremeLow    float64
+	extremeHigh   float64
+	// percentileStep is the per-strategy re-alert step carried to the router via
+	// Signal.Metadata["percentile_step"]. <= 0 means unconfigured: the router
+	// falls back to its global router.percentile_step.
+	percentileStep float64
+
+	usSource, usSeries string
+	cnSource, cnSeries string
+
+	// cache is shared by the copies strategy.WithParams makes.
+	cache *seriesCache
+}
+
+// New creates the erp strategy reading PE history from pe and bond yields
+// from sources, keyed by SourceFRED or SourceAkshare. Without pe, or without
+// the source a market is configured to use, it emits nothing for that market.
+func New(pe PEHistory, sources map[string]YieldSource) *Strategy {
+	return &Strategy{
+		pe: pe, sources: sources,
+		lookbackYears: 10, low: 20, high: 80, extremeLow: 10, extremeHigh: 90,
+		usSource: SourceFRED, usSeries: "DGS10",
+		cnSource: SourceAkshare, cnSeries: "10y",
+		cache: &seriesCache{entries: map[string]cacheEntry{}},
+	}
+}
+
+func (s *Strategy) Name() string { return "erp" }
+
+func (s *Strategy) Description() string {
+	return "Equity risk premium (earnings yield less bond yield) position in its own multi-year history"
+}
+
+func (s *Strategy) RequiredData() strategy.DataRequirements {
+	// Only the last bar is read: the PE and yield histories come from the
+	// strategy's own sources.
+	return strategy.DataRequirements{
+		Markets:      []core.Market{core.MarketUS, core.MarketCNA},
+		AssetTypes:   []core.AssetType{core.AssetIndex},
+		PriceHistory: 1,
+	}
+}
+
+// Params declares the params Init reads; defaults are the current values.
+func (s *Strategy) Params() []strategy.Param {
+	sources := []string{SourceFRED, SourceAkshare}
+	return []strategy.Param{
+		{Name: "lookback_years", Type: strategy.ParamInt, Default: s.lookbackYears, Min: strategy.Bound(0),
+			Description: "Years of premium history to rank against; 0 = all stored history"},
+		{Name: "extreme_low", Type: strategy.ParamNumber, Default: s.extremeLow, Min: strategy.Bound(0), Max: strategy.Bound(100),
+			Description: "Strong sell below this premium percentile"},
+		{Name: "low", Type: strategy.ParamNumber, Default: s.low, Min: strategy.Bound(0), Max: strategy.Bound(100),
+			Description: "Sell below this premium percentile"},
+		{Name: "high", Type: strategy.ParamNumber, Default: s.high, Min: strategy.Bound(0), Max: strategy.Bound(100),
+			Description: "Buy above this premium percentile"},
+		{Name: "extreme_high", Type: strategy.ParamNumber, Default: s.extremeHigh, Min: strategy.Bound(0), Max: strategy.Bound(100),
+			Description: "Strong buy above this premium percentile"},
+		{Name: "percentile_step", Type: strategy.ParamNumber, Default: s.percentileStep,
+			Description: "Percentile points the premium must move before re-alerting; <= 0 = router.percentile_step"},
+		{Name: "us_yield_source", Type: strategy.ParamString, Default: s.usSource, Options: sources,
+			Description: "Bond yield source for US indexes"},
+		{Name: "us_yield_series", Type: strategy.ParamString, Default: s.usSeries,
+			Description: "US yield series: a FRED series ID, or a CGB tenor (2y, 5y, 10y, 30y) for akshare"},
+		{Name: "cn_yield_source", Type: strategy.ParamString, Default: s.cnSource, Options: sources,
+			Description: "Bond yield source for CN indexes"},
+		{Name: "cn_yield_series", Type: strategy.ParamString, Default: s.cnSeries,
+			Description: "CN yield series: a CGB tenor (2y, 5y, 10y, 30y) for akshare, or a FRED series ID"},
+	}
+}
+
+func (s *Strategy) Init(cfg strategy.Config) error {
+	if err := strategy.ValidateParams(s.Params(), cfg.Params); err != nil {
+		return fmt.Errorf("erp: %w", err)
+	}
+	if v, ok := strategy.IntParam(cfg.Params, "lookback_years"); ok {
+		s.lookbackYears = v
+	}
+	if v, ok := strategy.NumParam(cfg.Params, "low"); ok {
+		s.low = v
+	}
+	if v, ok := strategy.NumParam(cfg.Params, "high"); ok {
+		s.high = v
+	}
+	if v, ok := strategy.NumParam(cfg.Params, "extreme_low"); ok {
+		s.extremeLow = v
+	}
+	if v, ok := strategy.NumParam(cfg.Params, "extreme_high"); ok {
+		s.extremeHigh = v
+	}
+	s.percentileStep = 0
+	if v, ok := strategy.NumParam(cfg.Params, "percentile_step"); ok {
+		s.percentileStep = v
+	}
+	if v, ok := cfg.Params["us_yield_source"].(string); ok {
+		s.usSource = strings.ToLower(v)
+	}
+	if v, ok := cfg.Params["us_yield_series"].(string); ok {
+		s.usSeries = v
+	}
+	if v, ok := cfg.Params["cn_yield_source"].(string); ok {
+		s.cnSource = strings.ToLower(v)
+	}
+	if v, ok := cfg.Params["cn_yield_series"].(string); ok {
+		s.cnSeries = v
+	}
+	if !(s.extremeLow < s.low && s.low < s.high && s.high < s.extremeHigh) {
+		return fmt.Errorf("erp: thresholds must satisfy extreme_low < low < high < extreme_high, got %.1f/%.1f/%.1f/%.1f",
+			s.extremeLow, s.low, s.high, s.extremeHigh)
+	}
+	if s.lookbackYears < 0 {
+		return fmt.Errorf("erp: lookback_years must be non-negative, got %d", s.lookbackYears)
+	}
+	if s.usSeries == "" || s.cnSeries == "" {
+		return fmt.Errorf("erp: us_yield_series and cn_yield_series must not be empty")
+	}
+	return nil
+}
+
+func (s *Strategy) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	n := len(ctx.OHLCV)
+	if n == 0 || s.pe == nil {
+		return nil, nil
+	}
+	market := ctx.Market
+	if market == "" {
+		market = collector.MarketForSymbol(ctx.Symbol)
+	}
+	sourceName, series := s.yieldSeries(market)
+	src := s.sources[sourceName]
+	if src == nil {
+		return nil, nil
+	}
+
+	asOf := ctx.OHLCV[n-1].Time
+	start := time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+	if s.lookbackYears > 0 {
+		start = asOf.AddDate(-s.lookbackYears, 0, 0)
+	}
+	pe, yields, err := s.cache.load(s.pe, src, ctx.Symbol, sourceName+":"+series, series, start)
+	if err != nil {
+		return nil, fmt.Errorf("erp: %s: %w", ctx.Symbol, err)
+	}
+	prem := Premiums(pe, yields, asOf)
+	if len(prem) == 0 {
+		return nil, nil
+	}
+	last := prem[len(prem)-1]
+	p, ok := percentile(prem, start)
+	if !ok {
+		return nil, nil
+	}
+	action, conf := s.classify(p)
+	if action == "" {
+		return nil, nil
+	}
+
+	// "percentile" is the key the router's percentile-step gate reads.
+	md := map[string]any{
+		"percentile": p, "erp_percentile": p, "erp": last.ERP,
+		"earnings_yield": last.EarningsYield, "bond_yield": last.BondYield,
+		"yield_source": sourceName, "yield_series": series, "lookback_years": s.lookbackYears,
+	}
+	if s.percentileStep > 0 {
+		md["percentile_step"] = s.percentileStep
+	}
+	return []core.Signal{{
+		Symbol: ctx.Symbol, Action: action, Confidence: conf, Price: ctx.OHLCV[n-1].Close,
+		Reason:   s.reasonText(last, p, len(prem)),
+		Strategy: s.Name(), GeneratedAt: ctx.Now, Metadata: md,
+	}}, nil
+}
+
+// yieldSeries returns the configured yield source and series for market;
+// markets other than US and CN_A have none.
+func (s *Strategy) yieldSeries(market core.Market) (source, series string) {
+	if market == core.MarketCNA {
+		return s.cnSource, s.cnSeries
+	}
+	if market != core.MarketUS {
+		return "", ""
+	}
+	return s.usSource, s.usSeries
+}
+
+func (s *Strategy) reasonText(last Premium, p float64, days int) string {
+	span := fmt.Sprintf("its %d-year history", s.lookbackYears)
+	if s.lookbackYears == 0 {
+		span = fmt.Sprintf("full history (%d days)", days)
+	}
+	return fmt.Sprintf("ERP %.2f%% (earnings yield %.2f%% - bond yield %.2f%%) at %.1f%% of %s",
+		last.ERP, last.EarningsYield, last.BondYield, p, span)
+}
+
+// classify maps a percentile to (action, confidence); "" means no signal.
+// Bands: extreme→0.8+linear(capped 0.95), normal→0.6-0.8 linear, as in the
+// valuation percentiles, but a high premium buys.
+func (s *Strategy) classify(p float64) (core.Action, float64) {
+	switch {
+	case p > s.extremeHigh:
+		return core.ActionStrongBuy, min(0.95, 0.8+0.15*(p-s.extremeHigh)/(100-s.extremeHigh))
+	case p > s.high:
+		return core.ActionBuy, 0.6 + 0.2*(p-s.high)/(s.extremeHigh-s.high)
+	case p < s.extremeLow:
+		return core.ActionStrongSell, min(0.95, 0.8+0.15*(s.extremeLow-p)/s.extremeLow)
+	case p < s.low:
+		return core.ActionSell, 0.6 + 0.2*(s.low-p)/(s.low-s.extremeLow)
+	}
+	return "", 0
+}
+
+// Premium is the equity risk premium of one day, in percent.
+type Premium struct {
+	Date          time.Time
+	EarningsYield float64 // 100 / PE
+	BondYield     float64
+	ERP           float64 // EarningsYield - BondYield
+}
+
+// Premiums returns the daily premium series up to asOf: each day with a
+// positive PE less the latest bond yield known that day. Days before the
+// first yield are skipped. pe and yields must be ascending.
+func Premiums(pe, yields []Point, asOf time.Time) []Premium {
+	var out []Premium
+	j := -1
+	for _, p := range pe {
+		if p.Date.After(asOf) {
+			break
+		}
+		for j+1 < len(yields) && !yields[j+1].Date.After(p.Date) {
+			j++
+		}
+		if j < 0 || !(p.Value > 0) {
+			continue
+		}
+		ey := 100 / p.Value
+		y := yields[j].Value
+		out = append(out, Premium{Date: p.Date, EarningsYield: ey, BondYield: y, ERP: ey - y})
+	}
+	return out
+}
+
+// percentile ranks the last premium among the earlier ones dated after
+// start (0-100); ok is false with fewer than minPoints of them.
+func percentile(prem []Premium, start time.Time) (float64, bool) {
+	last := prem[len(prem)-1]
+	from := sort.Search(len(prem), func(i int) bool { return prem[i].Date.After(start) })
+	window := prem[from : len(prem)-1]
+	if len(window) < minPoints {
+		return 0, false
+	}
+	less := 0
+	for _, p := range window {
+		if p.ERP < last.ERP {
+			less++
+		}
+	}
+	return float64(less) / float64(len(window)) * 100, true
+}
+
+// seriesCache keeps each symbol's PE history and each yield series for
+// cacheTTL, fetched from the earliest start asked for.
+type seriesCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	start     time.Time
+	fetchedAt time.Time
+	points    []Point
+}
+
+func (c *seriesCache) load(pe PEHistory, src YieldSource, symbol, yieldKey, series string, start time.Time) ([]Point, []Point, error) {
+	pts, err := c.get("pe:"+symbol, start, func() ([]Point, error) { return pe.PEHistory(symbol, start) })
+	if err != nil {
+		return nil, nil, fmt.Errorf("PE history: %w", err)
+	}
+	// A month's margin gives the first PE day a yield to step-align with.
+	ystart := start.AddDate(0, -1, 0)
+	ys, err := c.get("yield:"+yieldKey, ystart, func() ([]Point, error) { return src.Yields(series, ystart, time.Now()) })
+	if err != nil {
+		return nil, nil, fmt.Errorf("yield %s: %w", series, err)
+	}
+	return pts, ys, nil
+}
+
+// get returns the cached points of key when fetched within cacheTTL from no
+// later than start, else fetches and caches them. Failures are not cached.
+func (c *seriesCache) get(key string, start time.Time, fetch func() ([]Point, error)) ([]Point, error) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && !e.start.After(start) && time.Since(e.fetchedAt) < cacheTTL {
+		return e.points, nil
+	}
+	pts, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pts, func(i, j int) bool { return pts[i].Date.Before(pts[j].Date) })
+	c.mu.Lock()
+	c.entries[key] = cacheEntry{start: start, fetchedAt: time.Now(), points: pts}
+	c.mu.Unlock()
+	return pts, nil
+}
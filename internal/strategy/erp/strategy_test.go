@@ -0,0 +1,215 @@
+package erp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+func TestStrategy_ImplementsStrategy(t *testing.T) {
+	var _ strategy.Strategy = (*Strategy)(nil)
+}
+
+type peStub struct {
+	pts   []Point
+	calls int
+}
+
+func (p *peStub) PEHistory(symbol string, from time.Time) ([]Point, error) {
+	p.calls++
+	var out []Point
+	for _, pt := range p.pts {
+		if !pt.Date.Before(from) {
+			out = append(out, pt)
+		}
+	}
+	return out, nil
+}
+
+type yieldStub struct {
+	pts    []Point
+	err    error
+	series []string
+}
+
+func (y *yieldStub) Yields(series string, start, end time.Time) ([]Point, error) {
+	y.series = append(y.series, series)
+	return y.pts, y.err
+}
+
+var day0 = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// history returns n daily PE points whose earnings yield rises from 5% by
+// step a day, and a flat 2% bond yield.
+func history(n int, step float64) (pe, yields []Point) {
+	for i := 0; i < n; i++ {
+		pe = append(pe, Point{Date: day0.AddDate(0, 0, i), Value: 100 / (5 + step*float64(i))})
+	}
+	return pe, []Point{{Date: day0.AddDate(0, 0, -3), Value: 2}}
+}
+
+func ctxAt(symbol string, market core.Market, n int) strategy.AnalysisContext {
+	return strategy.AnalysisContext{
+		Symbol: symbol, Market: market, Now: day0.AddDate(0, 0, n-1),
+		OHLCV: []core.OHLCV{{Time: day0.AddDate(0, 0, n-1), Close: 4000}},
+	}
+}
+
+func TestPremiums(t *testing.T) {
+	pe := []Point{
+		{Date: day0, Value: 20},
+		{Date: day0.AddDate(0, 0, 1), Value: -5}, // loss: no earnings yield
+		{Date: day0.AddDate(0, 0, 2), Value: 25},
+		{Date: day0.AddDate(0, 0, 3), Value: 10},
+	}
+	yields := []Point{
+		{Date: day0.AddDate(0, 0, 1), Value: 3},
+		{Date: day0.AddDate(0, 0, 3), Value: 1},
+	}
+	got := Premiums(pe, yields, day0.AddDate(0, 0, 2))
+	// day0 has no yield yet; the loss day is skipped; day 3 is after asOf.
+	if len(got) != 1 {
+		t.Fatalf("Premiums = %+v", got)
+	}
+	if p := got[0]; p.EarningsYield != 4 || p.BondYield != 3 || p.ERP != 1 {
+		t.Errorf("premium = %+v", p)
+	}
+}
+
+func TestAnalyze_Bands(t *testing.T) {
+	cases := []struct {
+		step float64
+		want core.Action
+	}{
+		{0.001, core.ActionStrongBuy},   // premium at its high
+		{-0.001, core.ActionStrongSell}, // premium at its low
+	}
+	for _, c := range cases {
+		pe, ys := history(400, c.step)
+		s := New(&peStub{pts: pe}, map[string]YieldSource{SourceFRED: &yieldStub{pts: ys}})
+		sigs, err := s.Analyze(ctxAt("^GSPC", core.MarketUS, 400))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sigs) != 1 || sigs[0].Action != c.want {
+			t.Fatalf("step %v: want %s, got %+v", c.step, c.want, sigs)
+		}
+		if sigs[0].Confidence != 0.95 || sigs[0].Price != 4000 || sigs[0].Strategy != "erp" {
+			t.Errorf("signal = %+v", sigs[0])
+		}
+	}
+
+	// A flat premium ranks at 0%: a sell, not a buy.
+	pe, ys := history(400, 0)
+	s := New(&peStub{pts: pe}, map[string]YieldSource{SourceFRED: &yieldStub{pts: ys}})
+	if sigs, _ := s.Analyze(ctxAt("^GSPC", core.MarketUS, 400)); len(sigs) != 1 || sigs[0].Action != core.ActionStrongSell {
+		t.Errorf("flat premium: got %+v", sigs)
+	}
+}
+
+func TestAnalyze_PointInTime(t *testing.T) {
+	// The premium rises for 400 days and then falls; on day 400 it is still
+	// at its high, whatever the later days hold.
+	pe, ys := history(800, 0.001)
+	for i := 400; i < 800; i++ {
+		pe[i].Value = 100 / (5 + 0.001*float64(800-i))
+	}
+	s := New(&peStub{pts: pe}, map[string]YieldSource{SourceFRED: &yieldStub{pts: ys}})
+	sigs, _ := s.Analyze(ctxAt("^GSPC", core.MarketUS, 400))
+	if len(sigs) != 1 || sigs[0].Action != core.ActionStrongBuy {
+		t.Errorf("got %+v", sigs)
+	}
+}
+
+func TestAnalyze_Metadata(t *testing.T) {
+	pe, ys := history(400, 0.001)
+	cgb := &yieldStub{pts: ys}
+	s := New(&peStub{pts: pe}, map[string]YieldSource{SourceAkshare: cgb})
+	if err := s.Init(strategy.Config{Params: map[string]any{"percentile_step": 3, "lookback_years": 0}}); err != nil {
+		t.Fatal(err)
+	}
+	sigs, err := s.Analyze(ctxAt("000300.SH", core.MarketCNA, 400))
+	if err != nil || len(sigs) != 1 {
+		t.Fatalf("Analyze = %+v, %v", sigs, err)
+	}
+	md := sigs[0].Metadata
+	if md["percentile"] != 100.0 || md["erp_percentile"] != 100.0 || md["bond_yield"] != 2.0 ||
+		md["yield_source"] != "akshare" || md["yield_series"] != "10y" || md["percentile_step"] != 3.0 {
+		t.Errorf("metadata = %+v", md)
+	}
+	if ey := md["earnings_yield"].(float64); ey < 5.398 || ey > 5.4 {
+		t.Errorf("earnings_yield = %v", ey)
+	}
+	if r := sigs[0].Reason; !strings.HasPrefix(r, "ERP 3.40% (earnings yield 5.40% - bond yield 2.00%) at 100.0%") ||
+		!strings.HasSuffix(r, "full history (400 days)") {
+		t.Errorf("Reason = %q", r)
+	}
+	if len(cgb.series) != 1 || cgb.series[0] != "10y" {
+		t.Errorf("yield series requested = %v", cgb.series)
+	}
+}
+
+func TestAnalyze_CachesSeries(t *testing.T) {
+	pe, ys := history(400, 0.001)
+	peSrc, yieldSrc := &peStub{pts: pe}, &yieldStub{pts: ys}
+	s := New(peSrc, map[string]YieldSource{SourceFRED: yieldSrc})
+	for n := 300; n <= 400; n++ {
+		if _, err := s.Analyze(ctxAt("^GSPC", core.MarketUS, n)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if peSrc.calls != 1 || len(yieldSrc.series) != 1 {
+		t.Errorf("fetches: PE %d, yield %d; want 1 each", peSrc.calls, len(yieldSrc.series))
+	}
+}
+
+func TestAnalyze_Unavailable(t *testing.T) {
+	pe, ys := history(400, 0.001)
+	fred := &yieldStub{pts: ys}
+	s := New(&peStub{pts: pe}, map[string]YieldSource{SourceFRED: fred})
+
+	// No CGB source configured, and no yields for HK indexes.
+	for _, market := range []core.Market{core.MarketCNA, core.MarketHK} {
+		if sigs, err := s.Analyze(ctxAt("X", market, 400)); sigs != nil || err != nil {
+			t.Errorf("%s: got %+v, %v", market, sigs, err)
+		}
+	}
+	// Too little history to rank against.
+	if sigs, _ := s.Analyze(ctxAt("^GSPC", core.MarketUS, 200)); sigs != nil {
+		t.Errorf("short history: got %+v", sigs)
+	}
+	// No sources at all, as listed in builtin.
+	if sigs, _ := New(nil, nil).Analyze(ctxAt("^GSPC", core.MarketUS, 400)); sigs != nil {
+		t.Errorf("no sources: got %+v", sigs)
+	}
+
+	failing := New(&peStub{pts: pe}, map[string]YieldSource{SourceFRED: &yieldStub{err: errors.New("http 500")}})
+	if _, err := failing.Analyze(ctxAt("^GSPC", core.MarketUS, 400)); err == nil || !strings.Contains(err.Error(), "http 500") {
+		t.Errorf("fetch failure: err = %v", err)
+	}
+}
+
+func TestInit(t *testing.T) {
+	s := New(nil, nil)
+	if err := s.Init(strategy.Config{Params: map[string]any{"low": 30, "cn_yield_source": "fred", "cn_yield_series": "IRLTLT01CNM156N"}}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if s.low != 30 || s.extremeLow != 10 || s.cnSource != SourceFRED || s.cnSeries != "IRLTLT01CNM156N" || s.usSeries != "DGS10" {
+		t.Errorf("after Init = %+v", s)
+	}
+	for _, params := range []map[string]any{
+		{"low": 95},
+		{"lookback_years": -1},
+		{"cn_yield_source": "wind"},
+		{"us_yield_series": ""},
+	} {
+		err := New(nil, nil).Init(strategy.Config{Params: params})
+		if err == nil || !strings.HasPrefix(err.Error(), "erp: ") {
+			t.Errorf("Init(%v) = %v, want an erp error", params, err)
+		}
+	}
+}
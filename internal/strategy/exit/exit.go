@@ -0,0 +1,47 @@
+// Package exit implements exit rules for held positions: a fixed stop-loss,
+// a take-profit, an ATR trailing stop and a time stop. They read the held
+// position from AnalysisContext.Position and emit nothing for a symbol that
+// is not held, so each is bound next to the entry strategy it closes.
+package exit
+
+import (
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// assetTypes are the asset types the exits apply to: anything with a price.
+var assetTypes = []core.AssetType{
+	core.AssetStock, core.AssetIndex, core.AssetETF,
+	core.AssetFund, core.AssetCommodity, core.AssetCrypto,
+}
+
+// held returns the long position of ctx and its last bar; ok is false when
+// nothing is held or there is no bar to judge it by.
+func held(ctx strategy.AnalysisContext) (pos strategy.Position, last core.OHLCV, ok bool) {
+	if ctx.Position == nil || ctx.Position.Quantity <= 0 || ctx.Position.AverageCost <= 0 || len(ctx.OHLCV) == 0 {
+		return strategy.Position{}, core.OHLCV{}, false
+	}
+	return *ctx.Position, ctx.OHLCV[len(ctx.OHLCV)-1], true
+}
+
+// sell builds the exit signal of a held position, priced at the last close.
+// Metadata gets the exit type and the position's return in percent.
+func sell(ctx strategy.AnalysisContext, pos strategy.Position, last core.OHLCV, kind string, confidence float64, reason string, metadata map[string]any) core.Signal {
+	metadata["type"] = kind
+	metadata["average_cost"] = pos.AverageCost
+	metadata["return_pct"] = returnPct(pos, last.Close)
+	return core.Signal{
+		Symbol:      ctx.Symbol,
+		Action:      core.ActionSell,
+		Price:       last.Close,
+		Confidence:  confidence,
+		Reason:      reason,
+		GeneratedAt: ctx.Now,
+		Metadata:    metadata,
+	}
+}
+
+// returnPct is the position's return at price, in percent of its cost.
+func returnPct(pos strategy.Position, price float64) float64 {
+	return (price - pos.AverageCost) / pos.AverageCost * 100
+}
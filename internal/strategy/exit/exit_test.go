@@ -0,0 +1,173 @@
+package exit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+var (
+	_ strategy.Strategy = (*StopLoss)(nil)
+	_ strategy.Strategy = (*TakeProfit)(nil)
+	_ strategy.Strategy = (*TrailingStop)(nil)
+	_ strategy.Strategy = (*TimeStop)(nil)
+)
+
+var day0 = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+// barsFromCloses builds daily bars with a high one above and a low one below
+// each close, starting at day0.
+func barsFromCloses(closes ...float64) []core.OHLCV {
+	bars := make([]core.OHLCV, len(closes))
+	for i, c := range closes {
+		bars[i] = core.OHLCV{Symbol: "T", Open: c, High: c + 1, Low: c - 1, Close: c, Time: day0.AddDate(0, 0, i)}
+	}
+	return bars
+}
+
+// heldAt returns a context holding 100 shares bought at cost on day0, at the
+// last of bars.
+func heldAt(cost float64, bars []core.OHLCV) strategy.AnalysisContext {
+	return strategy.AnalysisContext{
+		Symbol:   "T",
+		OHLCV:    bars,
+		Position: &strategy.Position{Quantity: 100, AverageCost: cost, EntryDate: day0},
+		Now:      bars[len(bars)-1].Time,
+	}
+}
+
+func analyze(t *testing.T, s strategy.Strategy, ctx strategy.AnalysisContext) []core.Signal {
+	t.Helper()
+	sigs, err := s.Analyze(ctx)
+	if err != nil {
+		t.Fatalf("%s: Analyze: %v", s.Name(), err)
+	}
+	return sigs
+}
+
+func TestExits_SilentWithoutPosition(t *testing.T) {
+	bars := barsFromCloses(100, 50)
+	for _, s := range []strategy.Strategy{NewStopLoss(8), NewTakeProfit(20), NewTrailingStop(1, 1), NewTimeStop(1)} {
+		if sigs := analyze(t, s, strategy.AnalysisContext{Symbol: "T", OHLCV: bars, Now: bars[1].Time}); len(sigs) != 0 {
+			t.Errorf("%s: not held, got %v", s.Name(), sigs)
+		}
+	}
+}
+
+func TestStopLoss(t *testing.T) {
+	s := NewStopLoss(8)
+	if sigs := analyze(t, s, heldAt(100, barsFromCloses(100, 93))); len(sigs) != 0 {
+		t.Errorf("7%% down is above the stop, got %v", sigs)
+	}
+	sigs := analyze(t, s, heldAt(100, barsFromCloses(100, 92)))
+	if len(sigs) != 1 {
+		t.Fatalf("expected a stop-loss, got %v", sigs)
+	}
+	sig := sigs[0]
+	if sig.Action != core.ActionSell || sig.Price != 92 || !sig.GeneratedAt.Equal(day0.AddDate(0, 0, 1)) {
+		t.Errorf("signal = %+v", sig)
+	}
+	if sig.Metadata["type"] != "stop_loss" || sig.Metadata["stop"] != 92.0 || sig.Metadata["return_pct"] != -8.0 {
+		t.Errorf("metadata = %v", sig.Metadata)
+	}
+}
+
+func TestTakeProfit(t *testing.T) {
+	s := NewTakeProfit(20)
+	if sigs := analyze(t, s, heldAt(100, barsFromCloses(100, 119))); len(sigs) != 0 {
+		t.Errorf("19%% up is below the target, got %v", sigs)
+	}
+	sigs := analyze(t, s, heldAt(100, barsFromCloses(100, 121)))
+	if len(sigs) != 1 || sigs[0].Action != core.ActionSell || sigs[0].Metadata["type"] != "take_profit" {
+		t.Errorf("expected a take-profit, got %v", sigs)
+	}
+}
+
+func TestTrailingStop(t *testing.T) {
+	// True ranges are 2 on flat bars and 5 to 11 on the gaps, so a 1x ATR(3)
+	// stop trails 2 to 5 below the highest high since entry.
+	s := NewTrailingStop(3, 1)
+	bars := barsFromCloses(100, 100, 100, 110, 110, 110)
+	if sigs := analyze(t, s, heldAt(100, bars)); len(sigs) != 0 {
+		t.Errorf("at the high, got %v", sigs)
+	}
+
+	bars = barsFromCloses(100, 100, 100, 104, 104, 104, 104, 100)
+	sigs := analyze(t, s, heldAt(100, bars))
+	if len(sigs) != 1 {
+		t.Fatalf("expected a trailing stop, got %v", sigs)
+	}
+	if high := sigs[0].Metadata["high"]; high != 105.0 {
+		t.Errorf("high = %v, want 105", high)
+	}
+	if stop := sigs[0].Metadata["stop"].(float64); stop <= 100 || stop >= 105 {
+		t.Errorf("stop = %v, want between the close and the high", stop)
+	}
+
+	// Highs before the entry day do not count.
+	ctx := heldAt(100, barsFromCloses(130, 100, 100, 100, 100))
+	ctx.Position.EntryDate = day0.AddDate(0, 0, 1).Add(15 * time.Hour)
+	if sigs := analyze(t, s, ctx); len(sigs) != 0 {
+		t.Errorf("trailing from a pre-entry high, got %v", sigs)
+	}
+}
+
+func TestTrailingStop_UsesProvidedATR(t *testing.T) {
+	s := NewTrailingStop(14, 3)
+	ctx := heldAt(100, barsFromCloses(100, 100, 95)) // too short to compute ATR(14) itself
+	ctx.Indicators = map[string][]float64{"atr_14": {1.5, 1.5}}
+	sigs := analyze(t, s, ctx)
+	if len(sigs) != 1 || sigs[0].Metadata["stop"] != 96.5 {
+		t.Errorf("expected a stop at 101 - 3 x 1.5, got %v", sigs)
+	}
+}
+
+func TestTimeStop(t *testing.T) {
+	s := NewTimeStop(30)
+	ctx := heldAt(100, barsFromCloses(100, 101))
+	ctx.Now = day0.AddDate(0, 0, 29)
+	if sigs := analyze(t, s, ctx); len(sigs) != 0 {
+		t.Errorf("held 29 days, got %v", sigs)
+	}
+	ctx.Now = day0.AddDate(0, 0, 30)
+	sigs := analyze(t, s, ctx)
+	if len(sigs) != 1 || sigs[0].Metadata["type"] != "time_stop" || sigs[0].Metadata["days_held"] != 30 {
+		t.Errorf("expected a time stop, got %v", sigs)
+	}
+
+	ctx.Position.EntryDate = time.Time{}
+	if sigs := analyze(t, s, ctx); len(sigs) != 0 {
+		t.Errorf("undated position, got %v", sigs)
+	}
+}
+
+func TestExits_Init(t *testing.T) {
+	for want, tc := range map[string]struct {
+		s      strategy.Strategy
+		params map[string]any
+	}{
+		"stop_loss: loss_pct must be within (0, 100)":      {NewStopLoss(8), map[string]any{"loss_pct": 0}},
+		"stop_loss: loss_pct must be at most 100":          {NewStopLoss(8), map[string]any{"loss_pct": 150}},
+		"take_profit: gain_pct must be positive":           {NewTakeProfit(20), map[string]any{"gain_pct": 0}},
+		"atr_trailing_stop: multiplier must be positive":   {NewTrailingStop(22, 3), map[string]any{"multiplier": 0}},
+		"atr_trailing_stop: period must be a whole number": {NewTrailingStop(22, 3), map[string]any{"period": 2.5}},
+		"time_stop: max_days must be at least 1":           {NewTimeStop(60), map[string]any{"max_days": 0}},
+		`time_stop: unknown param "days"`:                  {NewTimeStop(60), map[string]any{"days": 5}},
+	} {
+		err := tc.s.Init(strategy.Config{Params: tc.params})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s.Init(%v) = %v, want %q", tc.s.Name(), tc.params, err, want)
+		}
+	}
+
+	s := NewTrailingStop(22, 3)
+	if err := s.Init(strategy.Config{Params: map[string]any{"period": 10, "multiplier": 2.5}}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if req := s.RequiredData(); req.Indicators[0] != "atr_10" || req.PriceHistory != trailWindow {
+		t.Errorf("RequiredData = %+v", req)
+	}
+}
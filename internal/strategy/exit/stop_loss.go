@@ -0,0 +1,65 @@
+package exit
+
+import (
+	"fmt"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// StopLoss sells a position once the close has fallen a fixed percentage
+// below its average cost.
+type StopLoss struct {
+	lossPct float64
+}
+
+// NewStopLoss creates a stop-loss at lossPct percent below cost.
+func NewStopLoss(lossPct float64) *StopLoss {
+	return &StopLoss{lossPct: lossPct}
+}
+
+func (s *StopLoss) Name() string { return "stop_loss" }
+
+func (s *StopLoss) Description() string {
+	return fmt.Sprintf("Stop-loss %.1f%% below cost", s.lossPct)
+}
+
+func (s *StopLoss) RequiredData() strategy.DataRequirements {
+	return strategy.DataRequirements{PriceHistory: 1, AssetTypes: assetTypes}
+}
+
+// Params declares the params Init reads; defaults are the current values.
+func (s *StopLoss) Params() []strategy.Param {
+	return []strategy.Param{
+		{Name: "loss_pct", Type: strategy.ParamNumber, Default: s.lossPct, Min: strategy.Bound(0), Max: strategy.Bound(100),
+			Description: "Sell when the close is this many percent below the average cost"},
+	}
+}
+
+func (s *StopLoss) Init(cfg strategy.Config) error {
+	if err := strategy.ValidateParams(s.Params(), cfg.Params); err != nil {
+		return fmt.Errorf("stop_loss: %w", err)
+	}
+	if v, ok := strategy.NumParam(cfg.Params, "loss_pct"); ok {
+		s.lossPct = v
+	}
+	if s.lossPct <= 0 || s.lossPct >= 100 {
+		return fmt.Errorf("stop_loss: loss_pct must be within (0, 100), got %g", s.lossPct)
+	}
+	return nil
+}
+
+func (s *StopLoss) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	pos, last, ok := held(ctx)
+	if !ok {
+		return nil, nil
+	}
+	stop := pos.AverageCost * (1 - s.lossPct/100)
+	if last.Close > stop {
+		return nil, nil
+	}
+	return []core.Signal{sell(ctx, pos, last, "stop_loss", 0.9,
+		fmt.Sprintf("Close %.2f hit the %.1f%% stop-loss at %.2f (cost %.2f)", last.Close, s.lossPct, stop, pos.AverageCost),
+		map[string]any{"stop": stop},
+	)}, nil
+}
@@ -0,0 +1,65 @@
+package exit
+
+import (
+	"fmt"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// TakeProfit sells a position once the close has risen a fixed percentage
+// above its average cost.
+type TakeProfit struct {
+	gainPct float64
+}
+
+// NewTakeProfit creates a take-profit at gainPct percent above cost.
+func NewTakeProfit(gainPct float64) *TakeProfit {
+	return &TakeProfit{gainPct: gainPct}
+}
+
+func (t *TakeProfit) Name() string { return "take_profit" }
+
+func (t *TakeProfit) Description() string {
+	return fmt.Sprintf("Take-profit %.1f%% above cost", t.gainPct)
+}
+
+func (t *TakeProfit) RequiredData() strategy.DataRequirements {
+	return strategy.DataRequirements{PriceHistory: 1, AssetTypes: assetTypes}
+}
+
+// Params declares the params Init reads; defaults are the current values.
+func (t *TakeProfit) Params() []strategy.Param {
+	return []strategy.Param{
+		{Name: "gain_pct", Type: strategy.ParamNumber, Default: t.gainPct, Min: strategy.Bound(0),
+			Description: "Sell when the close is this many percent above the average cost"},
+	}
+}
+
+func (t *TakeProfit) Init(cfg strategy.Config) error {
+	if err := strategy.ValidateParams(t.Params(), cfg.Params); err != nil {
+		return fmt.Errorf("take_profit: %w", err)
+	}
+	if v, ok := strategy.NumParam(cfg.Params, "gain_pct"); ok {
+		t.gainPct = v
+	}
+	if t.gainPct <= 0 {
+		return fmt.Errorf("take_profit: gain_pct must be positive, got %g", t.gainPct)
+	}
+	return nil
+}
+
+func (t *TakeProfit) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	pos, last, ok := held(ctx)
+	if !ok {
+		return nil, nil
+	}
+	target := pos.AverageCost * (1 + t.gainPct/100)
+	if last.Close < target {
+		return nil, nil
+	}
+	return []core.Signal{sell(ctx, pos, last, "take_profit", 0.8,
+		fmt.Sprintf("Close %.2f reached the %.1f%% take-profit at %.2f (cost %.2f)", last.Close, t.gainPct, target, pos.AverageCost),
+		map[string]any{"target": target},
+	)}, nil
+}
@@ -0,0 +1,66 @@
+package exit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// TimeStop sells a position that has been held for a fixed number of
+// calendar days, whatever its return.
+type TimeStop struct {
+	maxDays int
+}
+
+// NewTimeStop creates a time stop after maxDays calendar days.
+func NewTimeStop(maxDays int) *TimeStop {
+	return &TimeStop{maxDays: maxDays}
+}
+
+func (t *TimeStop) Name() string { return "time_stop" }
+
+func (t *TimeStop) Description() string {
+	return fmt.Sprintf("Time stop after %d days", t.maxDays)
+}
+
+func (t *TimeStop) RequiredData() strategy.DataRequirements {
+	return strategy.DataRequirements{PriceHistory: 1, AssetTypes: assetTypes}
+}
+
+// Params declares the params Init reads; defaults are the current values.
+func (t *TimeStop) Params() []strategy.Param {
+	return []strategy.Param{
+		{Name: "max_days", Type: strategy.ParamInt, Default: t.maxDays, Min: strategy.Bound(1),
+			Description: "Sell once the position has been held this many calendar days"},
+	}
+}
+
+func (t *TimeStop) Init(cfg strategy.Config) error {
+	if err := strategy.ValidateParams(t.Params(), cfg.Params); err != nil {
+		return fmt.Errorf("time_stop: %w", err)
+	}
+	if v, ok := strategy.IntParam(cfg.Params, "max_days"); ok {
+		t.maxDays = v
+	}
+	if t.maxDays <= 0 {
+		return fmt.Errorf("time_stop: max_days must be positive, got %d", t.maxDays)
+	}
+	return nil
+}
+
+func (t *TimeStop) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	pos, last, ok := held(ctx)
+	if !ok || pos.EntryDate.IsZero() {
+		return nil, nil // an undated position cannot age
+	}
+	days := int(ctx.Now.Sub(pos.EntryDate) / (24 * time.Hour))
+	if days < t.maxDays {
+		return nil, nil
+	}
+	return []core.Signal{sell(ctx, pos, last, "time_stop", 0.7,
+		fmt.Sprintf("Held %d days since %s, time stop at %d", days, pos.EntryDate.Format("2006-01-02"), t.maxDays),
+		map[string]any{"days_held": days},
+	)}, nil
+}
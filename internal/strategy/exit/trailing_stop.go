@@ -0,0 +1,117 @@
+package exit
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/indicator"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// trailWindow is the price history the trailing stop asks for: about a year
+// of trading days. A position held longer trails from the highest high within
+// the window.
+const trailWindow = 250
+
+// TrailingStop is a chandelier exit: it sells a position once the close falls
+// multiplier ATRs below the highest high since entry (or the entry price, when
+// higher).
+type TrailingStop struct {
+	period     int
+	multiplier float64
+}
+
+// NewTrailingStop creates an ATR trailing stop over period bars.
+func NewTrailingStop(period int, multiplier float64) *TrailingStop {
+	return &TrailingStop{period: period, multiplier: multiplier}
+}
+
+func (t *TrailingStop) Name() string { return "atr_trailing_stop" }
+
+func (t *TrailingStop) Description() string {
+	return fmt.Sprintf("ATR%d trailing stop (%.1fx)", t.period, t.multiplier)
+}
+
+func (t *TrailingStop) RequiredData() strategy.DataRequirements {
+	return strategy.DataRequirements{
+		PriceHistory: max(trailWindow, t.period*10),
+		Indicators:   []string{t.spec()},
+		AssetTypes:   assetTypes,
+	}
+}
+
+// Params declares the params Init reads; defaults are the current values.
+func (t *TrailingStop) Params() []strategy.Param {
+	return []strategy.Param{
+		{Name: "period", Type: strategy.ParamInt, Default: t.period, Min: strategy.Bound(1),
+			Description: "ATR period in bars"},
+		{Name: "multiplier", Type: strategy.ParamNumber, Default: t.multiplier, Min: strategy.Bound(0),
+			Description: "Stop distance below the highest high since entry, in ATRs"},
+	}
+}
+
+func (t *TrailingStop) Init(cfg strategy.Config) error {
+	if err := strategy.ValidateParams(t.Params(), cfg.Params); err != nil {
+		return fmt.Errorf("atr_trailing_stop: %w", err)
+	}
+	if v, ok := strategy.IntParam(cfg.Params, "period"); ok {
+		t.period = v
+	}
+	if v, ok := strategy.NumParam(cfg.Params, "multiplier"); ok {
+		t.multiplier = v
+	}
+	if t.period <= 0 {
+		return fmt.Errorf("atr_trailing_stop: period must be positive, got %d", t.period)
+	}
+	if t.multiplier <= 0 {
+		return fmt.Errorf("atr_trailing_stop: multiplier must be positive, got %g", t.multiplier)
+	}
+	return nil
+}
+
+func (t *TrailingStop) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	pos, last, ok := held(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	// Use the ATR the caller computed, else calculate it
+	values, ok := ctx.Indicators[t.spec()]
+	if !ok {
+		values = indicator.ATR(ctx.OHLCV, t.period)
+	}
+	if len(values) == 0 {
+		return nil, nil // Not enough data
+	}
+	atr := values[len(values)-1]
+
+	high := pos.AverageCost
+	for _, b := range sinceEntry(ctx.OHLCV, pos.EntryDate) {
+		high = max(high, b.High)
+	}
+	stop := high - t.multiplier*atr
+	if last.Close > stop {
+		return nil, nil
+	}
+	return []core.Signal{sell(ctx, pos, last, "atr_trailing_stop", 0.85,
+		fmt.Sprintf("Close %.2f fell below the trailing stop %.2f (high %.2f - %.1f x ATR%d %.2f)",
+			last.Close, stop, high, t.multiplier, t.period, atr),
+		map[string]any{"stop": stop, "high": high, "atr": atr},
+	)}, nil
+}
+
+// spec is the indicator spec of the strategy's ATR.
+func (t *TrailingStop) spec() string {
+	return fmt.Sprintf("atr_%d", t.period)
+}
+
+// sinceEntry returns the bars from the day of entry on. An undated entry, or
+// one before the first bar, keeps all of them.
+func sinceEntry(bars []core.OHLCV, entry time.Time) []core.OHLCV {
+	y, m, d := entry.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, entry.Location())
+	i := sort.Search(len(bars), func(i int) bool { return !bars[i].Time.Before(day) })
+	return bars[i:]
+}
@@ -28,6 +28,13 @@ type DataRequirements struct {
 	PriceHistory int  // Days of history needed
 	Fundamentals bool // Needs fundamental data
 	Indicators   []string
+	// Timeframes lists the bar series needed besides the daily OHLCV:
+	// TimeframeWeekly, TimeframeMonthly or one of IntradayTimeframes.
+	Timeframes []string
+	// Valuations lists the valuation metrics besides PE whose historical
+	// percentiles are needed in Fundamental.Percentiles: core.MetricPB,
+	// core.MetricPS or core.MetricDividendYield. It goes with Fundamentals.
+	Valuations []string
 }
 
 // AnalysisContext provides data to strategies
@@ -39,7 +46,32 @@ type AnalysisContext struct {
 	Fundamental  *core.Fundamental
 	Fundamentals map[string]float64
 	Indicators   map[string][]float64
-	Now          time.Time
+	// Bars holds the declared Timeframes, keyed by timeframe. Weekly and
+	// monthly bars cover the same days as OHLCV; intraday bars are missing
+	// when no collector supports the interval.
+	Bars map[string][]core.OHLCV
+	// Position is the holding of Symbol; nil when it is not held or the
+	// caller tracks no positions.
+	Position *Position
+	// PositionKnown reports that the caller tracks positions, so a nil
+	// Position means Symbol is not held.
+	PositionKnown bool
+	Now           time.Time
+}
+
+// Position is what is held of the analysed symbol: live, the broker's
+// position tracker; in a backtest, the simulated trade.
+type Position struct {
+	Quantity     float64
+	AverageCost  float64
+	EntryDate    time.Time
+	UnrealizedPL float64
+}
+
+// MarkedAt returns p with UnrealizedPL marked at price.
+func (p Position) MarkedAt(price float64) Position {
+	p.UnrealizedPL = (price - p.AverageCost) * p.Quantity
+	return p
 }
 
 // Strategy defines the interface for trading strategies
@@ -11,3 +11,10 @@ func TestSinceInceptionBarsIsLarge(t *testing.T) {
 		t.Errorf("SinceInceptionBars = %d, want >= %d", SinceInceptionBars, want)
 	}
 }
+
+func TestPosition_MarkedAt(t *testing.T) {
+	p := Position{Quantity: 100, AverageCost: 10}.MarkedAt(12.5)
+	if p.UnrealizedPL != 250 {
+		t.Errorf("UnrealizedPL = %v, want 250", p.UnrealizedPL)
+	}
+}
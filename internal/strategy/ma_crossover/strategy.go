@@ -33,7 +33,7 @@ func (m *MACrossover) Description() string {
 func (m *MACrossover) RequiredData() strategy.DataRequirements {
 	return strategy.DataRequirements{
 		PriceHistory: m.slowPeriod + 10, // Extra buffer
-		Indicators:   []string{"SMA"},
+		Indicators:   []string{m.smaSpec(m.fastPeriod), m.smaSpec(m.slowPeriod)},
 		AssetTypes: []core.AssetType{
 			core.AssetStock, core.AssetIndex, core.AssetETF,
 			core.AssetFund, core.AssetCommodity, core.AssetCrypto,
@@ -41,13 +41,29 @@ func (m *MACrossover) RequiredData() strategy.DataRequirements {
 	}
 }
 
+// Params declares the params Init reads; defaults are the current values.
+func (m *MACrossover) Params() []strategy.Param {
+	return []strategy.Param{
+		{Name: "fast_period", Type: strategy.ParamInt, Default: m.fastPeriod, Min: strategy.Bound(1),
+			Description: "Fast SMA period in bars; must be below slow_period"},
+		{Name: "slow_period", Type: strategy.ParamInt, Default: m.slowPeriod, Min: strategy.Bound(2),
+			Description: "Slow SMA period in bars"},
+	}
+}
+
 func (m *MACrossover) Init(cfg strategy.Config) error {
-	if fast, ok := cfg.Params["fast_period"].(int); ok {
+	if err := strategy.ValidateParams(m.Params(), cfg.Params); err != nil {
+		return fmt.Errorf("ma_crossover: %w", err)
+	}
+	if fast, ok := strategy.IntParam(cfg.Params, "fast_period"); ok {
 		m.fastPeriod = fast
 	}
-	if slow, ok := cfg.Params["slow_period"].(int); ok {
+	if slow, ok := strategy.IntParam(cfg.Params, "slow_period"); ok {
 		m.slowPeriod = slow
 	}
+	if m.fastPeriod <= 0 || m.fastPeriod >= m.slowPeriod {
+		return fmt.Errorf("ma_crossover: periods must satisfy 0 < fast_period < slow_period, got %d/%d", m.fastPeriod, m.slowPeriod)
+	}
 	return nil
 }
 
@@ -62,9 +78,15 @@ func (m *MACrossover) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, erro
 		prices[i] = bar.Close
 	}
 
-	// Calculate moving averages
-	fastMA := indicator.SMA(prices, m.fastPeriod)
-	slowMA := indicator.SMA(prices, m.slowPeriod)
+	// Use the moving averages the caller computed, else calculate them
+	fastMA, ok := ctx.Indicators[m.smaSpec(m.fastPeriod)]
+	if !ok {
+		fastMA = indicator.SMA(prices, m.fastPeriod)
+	}
+	slowMA, ok := ctx.Indicators[m.smaSpec(m.slowPeriod)]
+	if !ok {
+		slowMA = indicator.SMA(prices, m.slowPeriod)
+	}
 
 	if len(fastMA) < 2 || len(slowMA) < 2 {
 		return nil, nil
@@ -125,6 +147,11 @@ func (m *MACrossover) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, erro
 	return signals, nil
 }
 
+// smaSpec is the indicator spec of a period SMA of the close.
+func (m *MACrossover) smaSpec(period int) string {
+	return fmt.Sprintf("sma_%d", period)
+}
+
 // calculateConfidence returns higher confidence for larger divergence
 func (m *MACrossover) calculateConfidence(fast, slow float64) float64 {
 	diff := (fast - slow) / slow
@@ -106,6 +106,14 @@ func TestMACrossover_Init(t *testing.T) {
 	if s.fastPeriod != 8 || s.slowPeriod != 21 {
 		t.Errorf("after Init fast/slow = %d/%d, want 8/21", s.fastPeriod, s.slowPeriod)
 	}
+
+	// JSON-decoded params arrive as float64.
+	if err := s.Init(strategy.Config{Params: map[string]any{"fast_period": 10.0}}); err != nil || s.fastPeriod != 10 {
+		t.Errorf("float64 fast_period: err=%v fast=%d, want 10", err, s.fastPeriod)
+	}
+	if err := s.Init(strategy.Config{Params: map[string]any{"fast_period": 30}}); err == nil {
+		t.Error("expected error for fast_period >= slow_period")
+	}
 }
 
 func TestMACrossover_GoldenCross(t *testing.T) {
@@ -241,3 +249,40 @@ func TestMACrossover_DeathCross(t *testing.T) {
 		t.Errorf("expected Sell action for death cross, got %s", signals[0].Action)
 	}
 }
+
+func TestMACrossover_DeclaresRegistrySpecs(t *testing.T) {
+	got := New(50, 200).RequiredData().Indicators
+	if len(got) != 2 || got[0] != "sma_50" || got[1] != "sma_200" {
+		t.Errorf("Indicators = %v, want [sma_50 sma_200]", got)
+	}
+}
+
+func TestAnalyze_UsesProvidedIndicators(t *testing.T) {
+	s := New(2, 4)
+	bars := barsFromCloses(100, 100, 100, 100, 100, 100) // flat: no cross on its own
+	sigs, err := s.Analyze(strategy.AnalysisContext{Symbol: "T", OHLCV: bars, Indicators: map[string][]float64{
+		"sma_2": {99, 101},
+		"sma_4": {100, 100},
+	}})
+	if err != nil || len(sigs) != 1 || sigs[0].Action != core.ActionBuy {
+		t.Fatalf("expected a golden cross from the provided SMAs, got %v err=%v", sigs, err)
+	}
+}
+
+func TestMACrossover_InitRejectsBadParams(t *testing.T) {
+	for _, params := range []map[string]any{
+		{"fast_perod": 10},    // typo
+		{"fast_period": "10"}, // wrong type
+		{"fast_period": 10.5}, // not a whole number
+		{"slow_period": 0},    // out of range
+		{"ma_type": "ema"},    // never supported
+	} {
+		s := New(50, 200)
+		if err := s.Init(strategy.Config{Params: params}); err == nil {
+			t.Errorf("Init(%v): expected error", params)
+		}
+		if s.fastPeriod != 50 || s.slowPeriod != 200 {
+			t.Errorf("Init(%v) changed the periods to %d/%d", params, s.fastPeriod, s.slowPeriod)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package macd
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/indicator"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// MACD implements a MACD signal-line crossover trend strategy: buy when the
+// MACD line crosses above its signal line, sell when it crosses below.
+type MACD struct {
+	fastPeriod   int
+	slowPeriod   int
+	signalPeriod int
+}
+
+// New creates a new MACD strategy
+func New(fastPeriod, slowPeriod, signalPeriod int) *MACD {
+	return &MACD{fastPeriod: fastPeriod, slowPeriod: slowPeriod, signalPeriod: signalPeriod}
+}
+
+func (m *MACD) Name() string {
+	return "macd"
+}
+
+func (m *MACD) Description() string {
+	return fmt.Sprintf("MACD Crossover (%d/%d/%d)", m.fastPeriod, m.slowPeriod, m.signalPeriod)
+}
+
+func (m *MACD) RequiredData() strategy.DataRequirements {
+	return strategy.DataRequirements{
+		PriceHistory: (m.slowPeriod + m.signalPeriod) * 3, // EMA warm-up
+		Indicators:   []string{m.spec()},
+		AssetTypes: []core.AssetType{
+			core.AssetStock, core.AssetIndex, core.AssetETF,
+			core.AssetFund, core.AssetCommodity, core.AssetCrypto,
+		},
+	}
+}
+
+// Params declares the params Init reads; defaults are the current values.
+func (m *MACD) Params() []strategy.Param {
+	return []strategy.Param{
+		{Name: "fast_period", Type: strategy.ParamInt, Default: m.fastPeriod, Min: strategy.Bound(1),
+			Description: "Fast EMA period in bars; must be below slow_period"},
+		{Name: "slow_period", Type: strategy.ParamInt, Default: m.slowPeriod, Min: strategy.Bound(2),
+			Description: "Slow EMA period in bars"},
+		{Name: "signal_period", Type: strategy.ParamInt, Default: m.signalPeriod, Min: strategy.Bound(1),
+			Description: "Signal line EMA period in bars"},
+	}
+}
+
+func (m *MACD) Init(cfg strategy.Config) error {
+	if err := strategy.ValidateParams(m.Params(), cfg.Params); err != nil {
+		return fmt.Errorf("macd: %w", err)
+	}
+	if v, ok := strategy.IntParam(cfg.Params, "fast_period"); ok {
+		m.fastPeriod = v
+	}
+	if v, ok := strategy.IntParam(cfg.Params, "slow_period"); ok {
+		m.slowPeriod = v
+	}
+	if v, ok := strategy.IntParam(cfg.Params, "signal_period"); ok {
+		m.signalPeriod = v
+	}
+	if m.fastPeriod <= 0 || m.fastPeriod >= m.slowPeriod {
+		return fmt.Errorf("macd: periods must satisfy 0 < fast_period < slow_period, got %d/%d", m.fastPeriod, m.slowPeriod)
+	}
+	if m.signalPeriod <= 0 {
+		return fmt.Errorf("macd: signal_period must be positive, got %d", m.signalPeriod)
+	}
+	return nil
+}
+
+func (m *MACD) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	if len(ctx.OHLCV) < m.slowPeriod+m.signalPeriod {
+		return nil, nil // Not enough data
+	}
+
+	// Use the MACD the caller computed, else calculate it
+	line, ok := ctx.Indicators[m.spec()]
+	signal, sok := ctx.Indicators[m.spec()+".signal"]
+	if !ok || !sok {
+		r := indicator.MACD(indicator.Closes(ctx.OHLCV), m.fastPeriod, m.slowPeriod, m.signalPeriod)
+		line, signal = r.MACD, r.Signal
+	}
+	if len(line) < 2 || len(signal) < 2 {
+		return nil, nil
+	}
+
+	currMACD, prevMACD := line[len(line)-1], line[len(line)-2]
+	currSig, prevSig := signal[len(signal)-1], signal[len(signal)-2]
+	lastClose := ctx.OHLCV[len(ctx.OHLCV)-1].Close
+
+	meta := map[string]any{
+		"macd":      currMACD,
+		"signal":    currSig,
+		"histogram": currMACD - currSig,
+	}
+
+	// Bullish crossover: MACD crosses above its signal line
+	if prevMACD <= prevSig && currMACD > currSig {
+		meta["type"] = "macd_bullish_cross"
+		return []core.Signal{{
+			Symbol:      ctx.Symbol,
+			Action:      core.ActionBuy,
+			Price:       lastClose,
+			Confidence:  m.calculateConfidence(currMACD, currSig, lastClose, currMACD < 0),
+			Reason:      fmt.Sprintf("MACD (%.3f) crossed above signal (%.3f)", currMACD, currSig),
+			GeneratedAt: ctx.Now,
+			Metadata:    meta,
+		}}, nil
+	}
+
+	// Bearish crossover: MACD crosses below its signal line
+	if prevMACD >= prevSig && currMACD < currSig {
+		meta["type"] = "macd_bearish_cross"
+		return []core.Signal{{
+			Symbol:      ctx.Symbol,
+			Action:      core.ActionSell,
+			Price:       lastClose,
+			Confidence:  m.calculateConfidence(currMACD, currSig, lastClose, currMACD > 0),
+			Reason:      fmt.Sprintf("MACD (%.3f) crossed below signal (%.3f)", currMACD, currSig),
+			GeneratedAt: ctx.Now,
+			Metadata:    meta,
+		}}, nil
+	}
+
+	return nil, nil
+}
+
+// spec is the indicator spec of the strategy's MACD.
+func (m *MACD) spec() string {
+	return fmt.Sprintf("macd_%d_%d_%d", m.fastPeriod, m.slowPeriod, m.signalPeriod)
+}
+
+// calculateConfidence returns higher confidence for a wider gap between the
+// lines relative to price, plus a bonus when the cross happens on the far
+// side of zero (a bullish cross below zero, a bearish one above it).
+func (m *MACD) calculateConfidence(macd, signal, price float64, farSide bool) float64 {
+	if price <= 0 {
+		return 0.5
+	}
+	gap := math.Abs(macd-signal) / price
+
+	// Scale to 0.5-0.9 range based on the gap
+	confidence := 0.5 + gap*50
+	if farSide {
+		confidence += 0.1
+	}
+	if confidence > 0.9 {
+		confidence = 0.9
+	}
+	return confidence
+}
@@ -0,0 +1,139 @@
+package macd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+func TestMACD_ImplementsStrategy(t *testing.T) {
+	var _ strategy.Strategy = (*MACD)(nil)
+}
+
+// barsFromCloses builds OHLCV bars from a sequence of closing prices with a
+// fixed arbitrary increasing timestamp per bar (time value is irrelevant here).
+func barsFromCloses(closes ...float64) []core.OHLCV {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := make([]core.OHLCV, len(closes))
+	for i, c := range closes {
+		bars[i] = core.OHLCV{
+			Symbol: "T",
+			Close:  c,
+			Time:   base.Add(time.Duration(i) * 24 * time.Hour),
+		}
+	}
+	return bars
+}
+
+// trend returns n closes moving by step from start.
+func trend(start, step float64, n int) []float64 {
+	closes := make([]float64, n)
+	for i := range closes {
+		closes[i] = start + step*float64(i)
+	}
+	return closes
+}
+
+func TestMACD_NameAndDescription(t *testing.T) {
+	s := New(12, 26, 9)
+	if s.Name() != "macd" {
+		t.Errorf("Name() = %q, want %q", s.Name(), "macd")
+	}
+	if got := s.Description(); got != "MACD Crossover (12/26/9)" {
+		t.Errorf("Description() = %q, want %q", got, "MACD Crossover (12/26/9)")
+	}
+	if got := s.RequiredData().Indicators; len(got) != 1 || got[0] != "macd_12_26_9" {
+		t.Errorf("Indicators = %v, want [macd_12_26_9]", got)
+	}
+}
+
+func TestMACD_Init(t *testing.T) {
+	s := New(12, 26, 9)
+	if err := s.Init(strategy.Config{Params: map[string]any{
+		"fast_period":   8,
+		"slow_period":   17.0,
+		"signal_period": 5,
+	}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.fastPeriod != 8 || s.slowPeriod != 17 || s.signalPeriod != 5 {
+		t.Errorf("after Init = %d/%d/%d, want 8/17/5", s.fastPeriod, s.slowPeriod, s.signalPeriod)
+	}
+
+	for _, params := range []map[string]any{
+		{"fast_period": 30},
+		{"fast_period": 0},
+		{"signal_period": 0},
+	} {
+		if err := New(12, 26, 9).Init(strategy.Config{Params: params}); err == nil {
+			t.Errorf("Init(%v): expected error", params)
+		}
+	}
+}
+
+func TestMACD_BullishCross(t *testing.T) {
+	past := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	s := New(3, 6, 3)
+	closes := append(trend(100, -1, 20), 95)
+	sigs, err := s.Analyze(strategy.AnalysisContext{Symbol: "T", Now: past, OHLCV: barsFromCloses(closes...)})
+	if err != nil || len(sigs) != 1 {
+		t.Fatalf("expected one signal, got %v err=%v", sigs, err)
+	}
+	sig := sigs[0]
+	if sig.Action != core.ActionBuy || sig.Price != 95 || !sig.GeneratedAt.Equal(past) {
+		t.Errorf("signal = %+v", sig)
+	}
+	if sig.Metadata["type"] != "macd_bullish_cross" || sig.Confidence < 0.5 || sig.Confidence > 0.9 {
+		t.Errorf("signal = %+v", sig)
+	}
+}
+
+func TestMACD_BearishCross(t *testing.T) {
+	s := New(3, 6, 3)
+	closes := append(trend(100, 1, 20), 105)
+	sigs, err := s.Analyze(strategy.AnalysisContext{Symbol: "T", OHLCV: barsFromCloses(closes...)})
+	if err != nil || len(sigs) != 1 {
+		t.Fatalf("expected one signal, got %v err=%v", sigs, err)
+	}
+	if sigs[0].Action != core.ActionSell || sigs[0].Metadata["type"] != "macd_bearish_cross" {
+		t.Errorf("signal = %+v", sigs[0])
+	}
+}
+
+func TestMACD_NoSignal(t *testing.T) {
+	s := New(3, 6, 3)
+	if sigs, _ := s.Analyze(strategy.AnalysisContext{Symbol: "T",
+		OHLCV: barsFromCloses(trend(100, 1, 20)...)}); len(sigs) != 0 {
+		t.Errorf("expected no signal in a steady trend, got %v", sigs)
+	}
+	if sigs, _ := s.Analyze(strategy.AnalysisContext{Symbol: "T",
+		OHLCV: barsFromCloses(trend(100, 1, 5)...)}); len(sigs) != 0 {
+		t.Errorf("expected no signal with too few bars, got %v", sigs)
+	}
+}
+
+func TestMACD_ConfidenceRewardsFarSideCross(t *testing.T) {
+	s := New(12, 26, 9)
+	near := s.calculateConfidence(0.2, 0.1, 100, false)
+	far := s.calculateConfidence(0.2, 0.1, 100, true)
+	if near < 0.5 || far <= near || far > 0.9 {
+		t.Errorf("confidence near=%v far=%v", near, far)
+	}
+	if got := s.calculateConfidence(50, 0, 100, true); got != 0.9 {
+		t.Errorf("confidence = %v, want capped at 0.9", got)
+	}
+}
+
+func TestAnalyze_UsesProvidedIndicators(t *testing.T) {
+	s := New(3, 6, 3)
+	bars := barsFromCloses(trend(100, 0, 12)...) // flat: no cross on its own
+	sigs, err := s.Analyze(strategy.AnalysisContext{Symbol: "T", OHLCV: bars, Indicators: map[string][]float64{
+		"macd_3_6_3":        {0.5, -0.2},
+		"macd_3_6_3.signal": {0.1, 0.0},
+	}})
+	if err != nil || len(sigs) != 1 || sigs[0].Action != core.ActionSell {
+		t.Fatalf("expected a bearish cross from the provided MACD, got %v err=%v", sigs, err)
+	}
+}
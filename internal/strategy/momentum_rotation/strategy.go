@@ -0,0 +1,200 @@
+package momentum_rotation
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// Rotation is a cross-sectional relative-strength strategy: it ranks the
+// symbols of its group by their return over the last lookback bars, leaving
+// out the most recent skip bars (12-1 momentum skips a month), and holds the
+// top N. A top-N symbol that is not held is bought (without known positions,
+// only when it enters the top N) and sold when it drops out of it; a held
+// symbol outside the top N is sold on every run until the position is closed.
+// Symbols whose return does not exceed min_return are never held, so an
+// all-negative group rotates into cash.
+type Rotation struct {
+	lookback  int
+	skip      int
+	topN      int
+	minReturn float64
+}
+
+// New creates a momentum rotation into the topN symbols by lookback-bar
+// return.
+func New(lookback, topN int) *Rotation {
+	return &Rotation{lookback: lookback, topN: topN}
+}
+
+func (r *Rotation) Name() string {
+	return "momentum_rotation"
+}
+
+func (r *Rotation) Description() string {
+	return fmt.Sprintf("Momentum Rotation (top %d by %d-bar return)", r.topN, r.lookback)
+}
+
+func (r *Rotation) RequiredData() strategy.DataRequirements {
+	return strategy.DataRequirements{
+		// The current and the previous bar's ranking.
+		PriceHistory: r.lookback + r.skip + 2,
+		AssetTypes: []core.AssetType{
+			core.AssetStock, core.AssetIndex, core.AssetETF,
+			core.AssetFund, core.AssetCommodity, core.AssetCrypto,
+		},
+	}
+}
+
+// Params declares the params Init reads; defaults are the current values.
+func (r *Rotation) Params() []strategy.Param {
+	return []strategy.Param{
+		{Name: "lookback", Type: strategy.ParamInt, Default: r.lookback, Min: strategy.Bound(1),
+			Description: "Bars over which momentum is measured"},
+		{Name: "skip", Type: strategy.ParamInt, Default: r.skip, Min: strategy.Bound(0),
+			Description: "Most recent bars left out of the momentum, e.g. 21 for 12-1 momentum"},
+		{Name: "top_n", Type: strategy.ParamInt, Default: r.topN, Min: strategy.Bound(1),
+			Description: "Number of symbols held"},
+		{Name: "min_return", Type: strategy.ParamNumber, Default: r.minReturn,
+			Description: "A symbol is held only while its momentum exceeds this return in percent"},
+	}
+}
+
+func (r *Rotation) Init(cfg strategy.Config) error {
+	if err := strategy.ValidateParams(r.Params(), cfg.Params); err != nil {
+		return fmt.Errorf("momentum_rotation: %w", err)
+	}
+	if v, ok := strategy.IntParam(cfg.Params, "lookback"); ok {
+		r.lookback = v
+	}
+	if v, ok := strategy.IntParam(cfg.Params, "skip"); ok {
+		r.skip = v
+	}
+	if v, ok := strategy.IntParam(cfg.Params, "top_n"); ok {
+		r.topN = v
+	}
+	if v, ok := strategy.NumParam(cfg.Params, "min_return"); ok {
+		r.minReturn = v
+	}
+	if r.lookback <= 0 {
+		return fmt.Errorf("momentum_rotation: lookback must be positive, got %d", r.lookback)
+	}
+	if r.skip < 0 {
+		return fmt.Errorf("momentum_rotation: skip must not be negative, got %d", r.skip)
+	}
+	if r.topN <= 0 {
+		return fmt.Errorf("momentum_rotation: top_n must be positive, got %d", r.topN)
+	}
+	return nil
+}
+
+// Analyze sees a single symbol, which has nothing to be ranked against.
+func (r *Rotation) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	return nil, nil
+}
+
+// AnalyzeGroup ranks the group on the last bar and on the bar before it, and
+// signals buys of the top N symbols not held and sells of those that left
+// it, best-ranked first.
+func (r *Rotation) AnalyzeGroup(ctxs []strategy.AnalysisContext) ([]core.Signal, error) {
+	top, momentum := r.rank(ctxs, 0)
+	wasTop, _ := r.rank(ctxs, 1)
+
+	var entries, exits []core.Signal
+	for _, ctx := range ctxs {
+		mom, ok := momentum[ctx.Symbol]
+		if !ok {
+			continue // too short a history to judge
+		}
+		held := ctx.Position != nil && ctx.Position.Quantity > 0
+		rank, inTop := top[ctx.Symbol]
+		_, wasIn := wasTop[ctx.Symbol]
+		last := ctx.OHLCV[len(ctx.OHLCV)-1]
+		metadata := map[string]any{
+			"momentum_pct": mom,
+			"group_size":   len(ctxs),
+			"top_n":        r.topN,
+		}
+		// A top-N symbol that is not held is bought, whether it just entered
+		// the top or its entry was refused or closed earlier. Without
+		// positions only a new entry to the top N is signalled.
+		enter := inTop && !held
+		if !ctx.PositionKnown && ctx.Position == nil {
+			enter = inTop && !wasIn
+		}
+		verb := "in"
+		if !wasIn {
+			verb = "entering"
+		}
+		switch {
+		case enter:
+			metadata["type"] = "rotation_entry"
+			metadata["rank"] = rank
+			entries = append(entries, core.Signal{
+				Symbol:      ctx.Symbol,
+				Action:      core.ActionBuy,
+				Price:       last.Close,
+				Confidence:  r.confidence(rank),
+				Reason:      fmt.Sprintf("Ranked #%d of %d by %d-bar momentum (%+.1f%%), %s the top %d", rank, len(ctxs), r.lookback, mom, verb, r.topN),
+				GeneratedAt: ctx.Now,
+				Metadata:    metadata,
+			})
+		case !inTop && (wasIn || held):
+			metadata["type"] = "rotation_exit"
+			exits = append(exits, core.Signal{
+				Symbol:      ctx.Symbol,
+				Action:      core.ActionSell,
+				Price:       last.Close,
+				Confidence:  0.8,
+				Reason:      fmt.Sprintf("%d-bar momentum (%+.1f%%) no longer ranks in the top %d of %d", r.lookback, mom, r.topN, len(ctxs)),
+				GeneratedAt: ctx.Now,
+				Metadata:    metadata,
+			})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return top[entries[i].Symbol] < top[entries[j].Symbol]
+	})
+	return append(entries, exits...), nil
+}
+
+// rank measures each symbol's momentum as of offset bars ago and returns the
+// 1-based ranks of the top N symbols above min_return, together with every
+// momentum that could be measured, in percent.
+func (r *Rotation) rank(ctxs []strategy.AnalysisContext, offset int) (top map[string]int, momentum map[string]float64) {
+	momentum = make(map[string]float64, len(ctxs))
+	var eligible []string
+	for _, ctx := range ctxs {
+		end := len(ctx.OHLCV) - 1 - offset - r.skip
+		start := end - r.lookback
+		if start < 0 || ctx.OHLCV[start].Close <= 0 {
+			continue
+		}
+		mom := (ctx.OHLCV[end].Close - ctx.OHLCV[start].Close) / ctx.OHLCV[start].Close * 100
+		momentum[ctx.Symbol] = mom
+		if mom > r.minReturn {
+			eligible = append(eligible, ctx.Symbol)
+		}
+	}
+	sort.SliceStable(eligible, func(i, j int) bool {
+		if momentum[eligible[i]] != momentum[eligible[j]] {
+			return momentum[eligible[i]] > momentum[eligible[j]]
+		}
+		return eligible[i] < eligible[j]
+	})
+	top = make(map[string]int, r.topN)
+	for i, symbol := range eligible[:min(r.topN, len(eligible))] {
+		top[symbol] = i + 1
+	}
+	return top, momentum
+}
+
+// confidence falls from 0.9 for the best-ranked symbol to 0.7 for the N-th.
+func (r *Rotation) confidence(rank int) float64 {
+	if r.topN == 1 {
+		return 0.9
+	}
+	return 0.9 - 0.2*float64(rank-1)/float64(r.topN-1)
+}
@@ -0,0 +1,155 @@
+package momentum_rotation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+func TestRotation_ImplementsCrossSectional(t *testing.T) {
+	var _ strategy.CrossSectional = (*Rotation)(nil)
+}
+
+var now = time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+
+// ctxOf builds the analysis context of symbol from its closing prices.
+func ctxOf(symbol string, closes ...float64) strategy.AnalysisContext {
+	bars := make([]core.OHLCV, len(closes))
+	for i, c := range closes {
+		bars[i] = core.OHLCV{Symbol: symbol, Close: c, Time: now.AddDate(0, 0, i-len(closes)+1)}
+	}
+	return strategy.AnalysisContext{Symbol: symbol, OHLCV: bars, Now: now}
+}
+
+func signalsBySymbol(sigs []core.Signal) map[string]core.Signal {
+	out := make(map[string]core.Signal, len(sigs))
+	for _, s := range sigs {
+		out[s.Symbol] = s
+	}
+	return out
+}
+
+func TestRotation_Init(t *testing.T) {
+	r := New(126, 3)
+	if err := r.Init(strategy.Config{Params: map[string]any{"lookback": 60, "skip": 5, "top_n": 2.0, "min_return": -5}}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if r.lookback != 60 || r.skip != 5 || r.topN != 2 || r.minReturn != -5 {
+		t.Errorf("after Init = %+v", r)
+	}
+	if got := r.RequiredData().PriceHistory; got != 67 {
+		t.Errorf("PriceHistory = %d, want 67", got)
+	}
+	for _, params := range []map[string]any{
+		{"lookback": 0},
+		{"top_n": 0},
+		{"skip": -1},
+		{"top": 3},
+	} {
+		if err := New(126, 3).Init(strategy.Config{Params: params}); err == nil {
+			t.Errorf("Init(%v): expected error", params)
+		}
+	}
+}
+
+func TestRotation_EntersAndLeavesTopN(t *testing.T) {
+	r := New(2, 2)
+	// Momentum over the last 2 bars: AAA +20%, BBB +10%, CCC +5% (it led a
+	// bar earlier, ahead of BBB), DDD -10%.
+	group := []strategy.AnalysisContext{
+		ctxOf("AAA", 100, 100, 100, 120),
+		ctxOf("BBB", 100, 100, 100, 110),
+		ctxOf("CCC", 100, 100, 112, 105),
+		ctxOf("DDD", 100, 100, 100, 90),
+	}
+	sigs, err := r.AnalyzeGroup(group)
+	if err != nil {
+		t.Fatalf("AnalyzeGroup: %v", err)
+	}
+	if len(sigs) != 3 {
+		t.Fatalf("signals = %+v, want AAA and BBB in, CCC out", sigs)
+	}
+	if sigs[0].Symbol != "AAA" || sigs[1].Symbol != "BBB" {
+		t.Errorf("entries should come best-ranked first, got %s, %s", sigs[0].Symbol, sigs[1].Symbol)
+	}
+	by := signalsBySymbol(sigs)
+	aaa := by["AAA"]
+	if aaa.Action != core.ActionBuy || aaa.Confidence != 0.9 || aaa.Price != 120 || !aaa.GeneratedAt.Equal(now) {
+		t.Errorf("AAA = %+v", aaa)
+	}
+	if aaa.Metadata["rank"] != 1 || aaa.Metadata["momentum_pct"] != 20.0 || aaa.Metadata["group_size"] != 4 {
+		t.Errorf("AAA metadata = %v", aaa.Metadata)
+	}
+	if bbb := by["BBB"]; bbb.Confidence != 0.7 || bbb.Metadata["type"] != "rotation_entry" {
+		t.Errorf("BBB = %+v", bbb)
+	}
+	if ccc := by["CCC"]; ccc.Action != core.ActionSell || ccc.Metadata["type"] != "rotation_exit" {
+		t.Errorf("CCC = %+v", ccc)
+	}
+}
+
+func TestRotation_HeldPositions(t *testing.T) {
+	r := New(2, 1)
+	held := &strategy.Position{Quantity: 10, AverageCost: 100}
+	leader := ctxOf("AAA", 100, 100, 100, 120)
+	leader.Position = held
+	laggard := ctxOf("BBB", 100, 100, 100, 101)
+	laggard.Position = held
+
+	// AAA just entered the top but is already held: no repeat buy. BBB never
+	// ranked, but is held outside the top: sold.
+	sigs, _ := r.AnalyzeGroup([]strategy.AnalysisContext{leader, laggard})
+	if len(sigs) != 1 || sigs[0].Symbol != "BBB" || sigs[0].Action != core.ActionSell {
+		t.Errorf("signals = %+v, want only a BBB sell", sigs)
+	}
+}
+
+func TestRotation_BuysTopNotHeld(t *testing.T) {
+	r := New(2, 1)
+	// AAA led on both bars, so it did not just enter the top. Held elsewhere
+	// it gets no repeat buy; known not to be held, as after a refused entry
+	// or an exit rule's sale, it is bought.
+	leader := ctxOf("AAA", 100, 100, 110, 120)
+	if sigs, _ := r.AnalyzeGroup([]strategy.AnalysisContext{leader}); len(sigs) != 0 {
+		t.Errorf("positions unknown: signals = %+v, want none", sigs)
+	}
+	leader.PositionKnown = true
+	sigs, _ := r.AnalyzeGroup([]strategy.AnalysisContext{leader})
+	if len(sigs) != 1 || sigs[0].Action != core.ActionBuy || sigs[0].Metadata["rank"] != 1 {
+		t.Fatalf("not held: signals = %+v, want an AAA buy", sigs)
+	}
+	if sigs[0].Reason != "Ranked #1 of 1 by 2-bar momentum (+20.0%), in the top 1" {
+		t.Errorf("reason = %q", sigs[0].Reason)
+	}
+	leader.Position = &strategy.Position{Quantity: 10, AverageCost: 100}
+	if sigs, _ := r.AnalyzeGroup([]strategy.AnalysisContext{leader}); len(sigs) != 0 {
+		t.Errorf("held: signals = %+v, want none", sigs)
+	}
+}
+
+func TestRotation_MinReturnRotatesIntoCash(t *testing.T) {
+	r := New(2, 2)
+	// Just long enough to rank on the last bar, not on the one before.
+	group := []strategy.AnalysisContext{
+		ctxOf("AAA", 100, 100, 95),
+		ctxOf("BBB", 100, 100, 90),
+		ctxOf("NEW", 100), // too short to rank
+	}
+	if sigs, _ := r.AnalyzeGroup(group); len(sigs) != 0 {
+		t.Errorf("no positive momentum: expected no entries, got %+v", sigs)
+	}
+	if err := r.Init(strategy.Config{Params: map[string]any{"min_return": -100}}); err != nil {
+		t.Fatal(err)
+	}
+	if sigs, _ := r.AnalyzeGroup(group); len(sigs) != 2 {
+		t.Errorf("min_return -100: expected both to enter, got %+v", sigs)
+	}
+}
+
+func TestRotation_AnalyzeAloneIsSilent(t *testing.T) {
+	if sigs, err := New(2, 1).Analyze(ctxOf("AAA", 100, 100, 100, 120)); err != nil || sigs != nil {
+		t.Errorf("Analyze = %v, %v, want nothing", sigs, err)
+	}
+}
@@ -0,0 +1,50 @@
+package strategy
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// WithParams returns a copy of s initialised with params, leaving s itself —
+// typically the instance shared through an Engine — untouched. The copy is a
+// shallow copy of the strategy struct, which is enough for strategies whose
+// only state is their parameters; params not named keep s's values.
+func WithParams(s Strategy, params map[string]any) (Strategy, error) {
+	v := reflect.ValueOf(s)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s: strategy cannot be copied", s.Name())
+	}
+	clone := reflect.New(v.Elem().Type())
+	clone.Elem().Set(v.Elem())
+	c := clone.Interface().(Strategy)
+	if err := c.Init(Config{Enabled: true, Params: params}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NumParam reads a numeric param tolerating int, int64 and float64, since
+// viper decodes YAML numbers as int and encoding/json decodes them as
+// float64. ok is false when key is absent or not a number.
+func NumParam(params map[string]any, key string) (v float64, ok bool) {
+	return number(params[key])
+}
+
+// number converts a decoded numeric value to float64.
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// IntParam is NumParam for integer params; a float64 is truncated.
+func IntParam(params map[string]any, key string) (int, bool) {
+	v, ok := NumParam(params, key)
+	return int(v), ok
+}
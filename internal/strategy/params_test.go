@@ -0,0 +1,63 @@
+package strategy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/newthinker/atlas/internal/core"
+)
+
+type paramStrategy struct {
+	period int
+	band   float64
+}
+
+func (p *paramStrategy) Name() string                                   { return "param" }
+func (p *paramStrategy) Description() string                            { return "param" }
+func (p *paramStrategy) RequiredData() DataRequirements                 { return DataRequirements{} }
+func (p *paramStrategy) Analyze(AnalysisContext) ([]core.Signal, error) { return nil, nil }
+func (p *paramStrategy) Init(cfg Config) error {
+	if v, ok := IntParam(cfg.Params, "period"); ok {
+		p.period = v
+	}
+	if v, ok := NumParam(cfg.Params, "band"); ok {
+		p.band = v
+	}
+	if p.period <= 0 {
+		return errors.New("period must be positive")
+	}
+	return nil
+}
+
+func TestWithParams(t *testing.T) {
+	shared := &paramStrategy{period: 20, band: 2}
+	got, err := WithParams(shared, map[string]any{"period": 50.0})
+	if err != nil {
+		t.Fatalf("WithParams: %v", err)
+	}
+	c := got.(*paramStrategy)
+	if c.period != 50 || c.band != 2 {
+		t.Errorf("copy = %+v, want period 50 and the shared band 2", c)
+	}
+	if shared.period != 20 {
+		t.Errorf("shared strategy changed: %+v", shared)
+	}
+	if _, err := WithParams(shared, map[string]any{"period": 0}); err == nil {
+		t.Error("expected Init error to be returned")
+	}
+}
+
+func TestNumParam(t *testing.T) {
+	params := map[string]any{"i": 3, "i64": int64(4), "f": 2.5, "s": "x"}
+	for key, want := range map[string]float64{"i": 3, "i64": 4, "f": 2.5} {
+		if v, ok := NumParam(params, key); !ok || v != want {
+			t.Errorf("NumParam(%q) = %v, %v; want %v", key, v, ok, want)
+		}
+	}
+	if _, ok := NumParam(params, "s"); ok {
+		t.Error("string param should not read as a number")
+	}
+	if v, ok := IntParam(params, "f"); !ok || v != 2 {
+		t.Errorf("IntParam(f) = %v, %v; want 2", v, ok)
+	}
+}
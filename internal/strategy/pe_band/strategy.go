@@ -32,11 +32,24 @@ func (p *PEBand) RequiredData() strategy.DataRequirements {
 	}
 }
 
+// Params declares the params Init reads; defaults are the current values.
+func (p *PEBand) Params() []strategy.Param {
+	return []strategy.Param{
+		{Name: "low_threshold", Type: strategy.ParamNumber, Default: p.lowThreshold, Min: strategy.Bound(0),
+			Description: "Buy when PE is below this"},
+		{Name: "high_threshold", Type: strategy.ParamNumber, Default: p.highThreshold, Min: strategy.Bound(0),
+			Description: "Sell when PE is above this"},
+	}
+}
+
 func (p *PEBand) Init(cfg strategy.Config) error {
-	if low, ok := cfg.Params["low_threshold"].(float64); ok {
+	if err := strategy.ValidateParams(p.Params(), cfg.Params); err != nil {
+		return fmt.Errorf("pe_band: %w", err)
+	}
+	if low, ok := strategy.NumParam(cfg.Params, "low_threshold"); ok {
 		p.lowThreshold = low
 	}
-	if high, ok := cfg.Params["high_threshold"].(float64); ok {
+	if high, ok := strategy.NumParam(cfg.Params, "high_threshold"); ok {
 		p.highThreshold = high
 	}
 	return nil
@@ -51,7 +51,28 @@ func (s *Strategy) RequiredData() strategy.DataRequirements {
 	}
 }
 
+// Params declares the params Init reads; defaults are the current values.
+func (s *Strategy) Params() []strategy.Param {
+	return []strategy.Param{
+		{Name: "lookback_years", Type: strategy.ParamInt, Default: s.lookbackYears, Min: strategy.Bound(0),
+			Description: "Years of history to rank against; 0 = since inception"},
+		{Name: "extreme_low", Type: strategy.ParamNumber, Default: s.extremeLow, Min: strategy.Bound(0), Max: strategy.Bound(100),
+			Description: "Strong buy below this PE percentile"},
+		{Name: "low", Type: strategy.ParamNumber, Default: s.low, Min: strategy.Bound(0), Max: strategy.Bound(100),
+			Description: "Buy below this PE percentile"},
+		{Name: "high", Type: strategy.ParamNumber, Default: s.high, Min: strategy.Bound(0), Max: strategy.Bound(100),
+			Description: "Sell above this PE percentile"},
+		{Name: "extreme_high", Type: strategy.ParamNumber, Default: s.extremeHigh, Min: strategy.Bound(0), Max: strategy.Bound(100),
+			Description: "Strong sell above this PE percentile"},
+		{Name: "percentile_step", Type: strategy.ParamNumber, Default: s.percentileStep,
+			Description: "Percentile points the PE must move before re-alerting; <= 0 = router.percentile_step"},
+	}
+}
+
 func (s *Strategy) Init(cfg strategy.Config) error {
+	if err := strategy.ValidateParams(s.Params(), cfg.Params); err != nil {
+		return fmt.Errorf("pe_percentile: %w", err)
+	}
 	s.lookbackYears = int(numParam(cfg.Params, "lookback_years", float64(s.lookbackYears)))
 	s.low = numParam(cfg.Params, "low", s.low)
 	s.high = numParam(cfg.Params, "high", s.high)
@@ -0,0 +1,160 @@
+package rsi
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/indicator"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// RSI implements an RSI overbought/oversold mean-reversion strategy. It buys
+// when the RSI climbs back above the oversold level and sells when it falls
+// back below the overbought level, i.e. once the extreme starts to revert.
+type RSI struct {
+	period     int
+	oversold   float64
+	overbought float64
+}
+
+// New creates a new RSI strategy
+func New(period int, oversold, overbought float64) *RSI {
+	return &RSI{period: period, oversold: oversold, overbought: overbought}
+}
+
+func (r *RSI) Name() string {
+	return "rsi"
+}
+
+func (r *RSI) Description() string {
+	return fmt.Sprintf("RSI%d Reversal (%.0f/%.0f)", r.period, r.oversold, r.overbought)
+}
+
+func (r *RSI) RequiredData() strategy.DataRequirements {
+	return strategy.DataRequirements{
+		PriceHistory: r.period * 10, // Wilder smoothing needs a long warm-up
+		Indicators:   []string{r.spec()},
+		AssetTypes: []core.AssetType{
+			core.AssetStock, core.AssetIndex, core.AssetETF,
+			core.AssetFund, core.AssetCommodity, core.AssetCrypto,
+		},
+	}
+}
+
+// Params declares the params Init reads; defaults are the current values.
+func (r *RSI) Params() []strategy.Param {
+	return []strategy.Param{
+		{Name: "period", Type: strategy.ParamInt, Default: r.period, Min: strategy.Bound(1),
+			Description: "RSI period in bars"},
+		{Name: "oversold", Type: strategy.ParamNumber, Default: r.oversold, Min: strategy.Bound(0), Max: strategy.Bound(100),
+			Description: "Buy when RSI climbs back above this level"},
+		{Name: "overbought", Type: strategy.ParamNumber, Default: r.overbought, Min: strategy.Bound(0), Max: strategy.Bound(100),
+			Description: "Sell when RSI falls back below this level"},
+	}
+}
+
+func (r *RSI) Init(cfg strategy.Config) error {
+	if err := strategy.ValidateParams(r.Params(), cfg.Params); err != nil {
+		return fmt.Errorf("rsi: %w", err)
+	}
+	if period, ok := strategy.IntParam(cfg.Params, "period"); ok {
+		r.period = period
+	}
+	if v, ok := strategy.NumParam(cfg.Params, "oversold"); ok {
+		r.oversold = v
+	}
+	if v, ok := strategy.NumParam(cfg.Params, "overbought"); ok {
+		r.overbought = v
+	}
+	if r.period <= 0 {
+		return fmt.Errorf("rsi: period must be positive, got %d", r.period)
+	}
+	if r.oversold <= 0 || r.oversold >= r.overbought || r.overbought >= 100 {
+		return fmt.Errorf("rsi: levels must satisfy 0 < oversold < overbought < 100, got %.1f/%.1f", r.oversold, r.overbought)
+	}
+	return nil
+}
+
+func (r *RSI) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	if len(ctx.OHLCV) <= r.period+1 {
+		return nil, nil // Not enough data
+	}
+
+	// Use the RSI the caller computed, else calculate it
+	values, ok := ctx.Indicators[r.spec()]
+	if !ok {
+		values = indicator.RSI(indicator.Closes(ctx.OHLCV), r.period)
+	}
+	if len(values) < 2 {
+		return nil, nil
+	}
+
+	curr := values[len(values)-1]
+	prev := values[len(values)-2]
+	lastClose := ctx.OHLCV[len(ctx.OHLCV)-1].Close
+
+	// Leaving oversold: RSI crosses back above the oversold level
+	if prev < r.oversold && curr >= r.oversold {
+		low := extreme(values[:len(values)-1], func(v float64) bool { return v < r.oversold }, math.Min)
+		return []core.Signal{{
+			Symbol:      ctx.Symbol,
+			Action:      core.ActionBuy,
+			Price:       lastClose,
+			Confidence:  r.calculateConfidence(r.oversold-low, r.oversold),
+			Reason:      fmt.Sprintf("RSI%d (%.1f) recovered above oversold %.0f (low %.1f)", r.period, curr, r.oversold, low),
+			GeneratedAt: ctx.Now,
+			Metadata: map[string]any{
+				"rsi":     curr,
+				"extreme": low,
+				"type":    "rsi_oversold_exit",
+			},
+		}}, nil
+	}
+
+	// Leaving overbought: RSI crosses back below the overbought level
+	if prev > r.overbought && curr <= r.overbought {
+		high := extreme(values[:len(values)-1], func(v float64) bool { return v > r.overbought }, math.Max)
+		return []core.Signal{{
+			Symbol:      ctx.Symbol,
+			Action:      core.ActionSell,
+			Price:       lastClose,
+			Confidence:  r.calculateConfidence(high-r.overbought, 100-r.overbought),
+			Reason:      fmt.Sprintf("RSI%d (%.1f) fell back below overbought %.0f (high %.1f)", r.period, curr, r.overbought, high),
+			GeneratedAt: ctx.Now,
+			Metadata: map[string]any{
+				"rsi":     curr,
+				"extreme": high,
+				"type":    "rsi_overbought_exit",
+			},
+		}}, nil
+	}
+
+	return nil, nil
+}
+
+// spec is the indicator spec of the strategy's RSI.
+func (r *RSI) spec() string {
+	return fmt.Sprintf("rsi_%d", r.period)
+}
+
+// extreme folds pick over the trailing run of values inside the zone, i.e.
+// the most extreme RSI of the excursion that just ended.
+func extreme(values []float64, inZone func(float64) bool, pick func(a, b float64) float64) float64 {
+	e := values[len(values)-1]
+	for i := len(values) - 2; i >= 0 && inZone(values[i]); i-- {
+		e = pick(e, values[i])
+	}
+	return e
+}
+
+// calculateConfidence returns higher confidence for a deeper excursion past
+// the level, as a fraction of the room beyond it.
+func (r *RSI) calculateConfidence(depth, room float64) float64 {
+	// Scale to 0.5-0.9 range based on depth
+	confidence := 0.5 + depth/room
+	if confidence > 0.9 {
+		confidence = 0.9
+	}
+	return confidence
+}
@@ -0,0 +1,135 @@
+package rsi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+func TestRSI_ImplementsStrategy(t *testing.T) {
+	var _ strategy.Strategy = (*RSI)(nil)
+}
+
+// barsFromCloses builds OHLCV bars from a sequence of closing prices with a
+// fixed arbitrary increasing timestamp per bar (time value is irrelevant here).
+func barsFromCloses(closes ...float64) []core.OHLCV {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := make([]core.OHLCV, len(closes))
+	for i, c := range closes {
+		bars[i] = core.OHLCV{
+			Symbol: "T",
+			Close:  c,
+			Time:   base.Add(time.Duration(i) * 24 * time.Hour),
+		}
+	}
+	return bars
+}
+
+func TestRSI_NameAndDescription(t *testing.T) {
+	s := New(14, 30, 70)
+	if s.Name() != "rsi" {
+		t.Errorf("Name() = %q, want %q", s.Name(), "rsi")
+	}
+	if got := s.Description(); got != "RSI14 Reversal (30/70)" {
+		t.Errorf("Description() = %q, want %q", got, "RSI14 Reversal (30/70)")
+	}
+	if got := s.RequiredData().Indicators; len(got) != 1 || got[0] != "rsi_14" {
+		t.Errorf("Indicators = %v, want [rsi_14]", got)
+	}
+}
+
+func TestRSI_Init(t *testing.T) {
+	s := New(14, 30, 70)
+	if err := s.Init(strategy.Config{Params: map[string]any{
+		"period":     9.0, // JSON-decoded params arrive as float64
+		"oversold":   25,
+		"overbought": 75.0,
+	}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.period != 9 || s.oversold != 25 || s.overbought != 75 {
+		t.Errorf("after Init = %d/%v/%v, want 9/25/75", s.period, s.oversold, s.overbought)
+	}
+
+	for _, params := range []map[string]any{
+		{"period": 0},
+		{"oversold": 80},
+		{"overbought": 100},
+		{"oversold": -5},
+	} {
+		if err := New(14, 30, 70).Init(strategy.Config{Params: params}); err == nil {
+			t.Errorf("Init(%v): expected error", params)
+		}
+	}
+}
+
+func TestRSI_OversoldExit(t *testing.T) {
+	past := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	s := New(3, 30, 70)
+	sigs, err := s.Analyze(strategy.AnalysisContext{Symbol: "T", Now: past,
+		OHLCV: barsFromCloses(100, 98, 96, 94, 92, 90, 100)})
+	if err != nil || len(sigs) != 1 {
+		t.Fatalf("expected one signal, got %v err=%v", sigs, err)
+	}
+	sig := sigs[0]
+	if sig.Action != core.ActionBuy || sig.Price != 100 || !sig.GeneratedAt.Equal(past) {
+		t.Errorf("signal = %+v", sig)
+	}
+	if sig.Metadata["type"] != "rsi_oversold_exit" || sig.Metadata["extreme"] != 0.0 {
+		t.Errorf("metadata = %v", sig.Metadata)
+	}
+	// An RSI that bottomed at 0 is the deepest possible excursion.
+	if sig.Confidence != 0.9 {
+		t.Errorf("confidence = %v, want 0.9", sig.Confidence)
+	}
+}
+
+func TestRSI_OverboughtExit(t *testing.T) {
+	s := New(3, 30, 70)
+	sigs, err := s.Analyze(strategy.AnalysisContext{Symbol: "T",
+		OHLCV: barsFromCloses(100, 102, 104, 106, 108, 110, 100)})
+	if err != nil || len(sigs) != 1 {
+		t.Fatalf("expected one signal, got %v err=%v", sigs, err)
+	}
+	if sigs[0].Action != core.ActionSell || sigs[0].Metadata["type"] != "rsi_overbought_exit" {
+		t.Errorf("signal = %+v", sigs[0])
+	}
+}
+
+func TestRSI_NoSignalInsideZone(t *testing.T) {
+	s := New(3, 30, 70)
+	// Still falling: RSI stays oversold, no exit yet.
+	if sigs, _ := s.Analyze(strategy.AnalysisContext{Symbol: "T",
+		OHLCV: barsFromCloses(100, 98, 96, 94, 92, 90, 88)}); len(sigs) != 0 {
+		t.Errorf("expected no signal, got %v", sigs)
+	}
+	if sigs, _ := s.Analyze(strategy.AnalysisContext{Symbol: "T",
+		OHLCV: barsFromCloses(100, 98, 96, 94)}); len(sigs) != 0 {
+		t.Errorf("expected no signal with too few bars, got %v", sigs)
+	}
+}
+
+func TestRSI_ConfidenceScalesWithDepth(t *testing.T) {
+	s := New(14, 30, 70)
+	shallow := s.calculateConfidence(30-27, 30)
+	deep := s.calculateConfidence(30-20, 30)
+	if shallow >= deep || shallow < 0.5 || deep > 0.9 {
+		t.Errorf("confidence shallow=%v deep=%v", shallow, deep)
+	}
+}
+
+func TestAnalyze_UsesProvidedIndicators(t *testing.T) {
+	s := New(3, 30, 70)
+	bars := barsFromCloses(100, 100, 100, 100, 100, 100) // flat: no cross on its own
+	sigs, err := s.Analyze(strategy.AnalysisContext{Symbol: "T", OHLCV: bars, Indicators: map[string][]float64{
+		"rsi_3": {25, 20, 35},
+	}})
+	if err != nil || len(sigs) != 1 || sigs[0].Action != core.ActionBuy {
+		t.Fatalf("expected an oversold exit from the provided RSI, got %v err=%v", sigs, err)
+	}
+	if sigs[0].Metadata["extreme"] != 20.0 {
+		t.Errorf("extreme = %v, want 20", sigs[0].Metadata["extreme"])
+	}
+}
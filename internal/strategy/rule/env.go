@@ -0,0 +1,83 @@
+package rule
+
+import (
+	"github.com/newthinker/atlas/internal/core"
+)
+
+// contextEnv exposes an AnalysisContext to expressions.
+type contextEnv struct {
+	bars         []core.OHLCV
+	indicators   map[string][]float64
+	fundamental  *core.Fundamental
+	fundamentals map[string]float64
+}
+
+func (e contextEnv) Bar(field string, offset int) (float64, bool) {
+	i := len(e.bars) - 1 - offset
+	if i < 0 {
+		return 0, false
+	}
+	b := e.bars[i]
+	switch field {
+	case "open":
+		return b.Open, true
+	case "high":
+		return b.High, true
+	case "low":
+		return b.Low, true
+	case "volume":
+		return float64(b.Volume), true
+	default:
+		return b.Close, true
+	}
+}
+
+func (e contextEnv) Indicator(key string, offset int) (float64, bool) {
+	series := e.indicators[key]
+	i := len(series) - 1 - offset
+	if i < 0 {
+		return 0, false
+	}
+	return series[i], true
+}
+
+// Fundamental reads ctx.Fundamentals first, then the Fundamental record. A
+// zero PE/PB/PS, a negative PE percentile and a missing metric percentile
+// count as unavailable, as the collectors leave them so when a source has no
+// value.
+func (e contextEnv) Fundamental(field string) (float64, bool) {
+	if v, ok := e.fundamentals[field]; ok {
+		return v, true
+	}
+	f := e.fundamental
+	if f == nil {
+		return 0, false
+	}
+	switch field {
+	case "pe":
+		return f.PE, f.PE != 0
+	case "pb":
+		return f.PB, f.PB != 0
+	case "ps":
+		return f.PS, f.PS != 0
+	case "roe":
+		return f.ROE, true
+	case "roa":
+		return f.ROA, true
+	case "eps":
+		return f.EPS, true
+	case "dividend_yield":
+		return f.DividendYield, true
+	case "market_cap":
+		return f.MarketCap, f.MarketCap != 0
+	case "pe_percentile":
+		return f.PEPercentile, f.PEPercentile >= 0
+	case "pb_percentile":
+		return f.Percentile(core.MetricPB)
+	case "ps_percentile":
+		return f.Percentile(core.MetricPS)
+	case "dividend_yield_percentile":
+		return f.Percentile(core.MetricDividendYield)
+	}
+	return 0, false
+}
@@ -0,0 +1,267 @@
+package rule
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/expr"
+	"github.com/newthinker/atlas/internal/indicator"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// Type is the strategies.<name>.type value that declares a rule strategy.
+const Type = "rule"
+
+// Triggers decide on which bars a rule that holds emits a signal.
+const (
+	// TriggerCross emits on the bar the rule starts to hold.
+	TriggerCross = "cross"
+	// TriggerLevel emits on every bar the rule holds.
+	TriggerLevel = "level"
+)
+
+// registry computes indicators the caller did not supply, the same way the
+// app and the backtester do.
+var registry = indicator.NewRegistry()
+
+// Rule is a strategy whose entry and exit conditions are expressions, e.g.
+// "close > sma(200) and rsi(14) < 30 and pe_percentile < 20". The entry rule
+// emits buys and the exit rule sells; see package expr for the language.
+type Rule struct {
+	name       string
+	entry      *expr.Program
+	exit       *expr.Program
+	trigger    string
+	confidence float64
+}
+
+// New creates a rule strategy named name. The rules come from Init.
+func New(name string) *Rule {
+	return &Rule{name: name, trigger: TriggerCross, confidence: 0.7}
+}
+
+// Compile parses and type-checks a rule's source, as Init does, so config
+// loading can reject bad rules early.
+func Compile(src string) (*expr.Program, error) {
+	p, err := expr.Compile(src)
+	if err != nil {
+		return nil, err
+	}
+	if p.Type() != expr.Bool {
+		return nil, fmt.Errorf("rule must be a condition, got a %s", p.Type())
+	}
+	return p, nil
+}
+
+// ValidateParams checks the params of a rule strategy entry without building
+// it. Errors name the offending param.
+func ValidateParams(params map[string]any) error {
+	_, err := New("").parse(params)
+	return err
+}
+
+func (r *Rule) Name() string {
+	return r.name
+}
+
+func (r *Rule) Description() string {
+	var parts []string
+	if r.entry != nil {
+		parts = append(parts, "buy when "+r.entry.Source())
+	}
+	if r.exit != nil {
+		parts = append(parts, "sell when "+r.exit.Source())
+	}
+	return "Rule: " + strings.Join(parts, "; ")
+}
+
+func (r *Rule) RequiredData() strategy.DataRequirements {
+	req := strategy.DataRequirements{
+		AssetTypes: []core.AssetType{
+			core.AssetStock, core.AssetIndex, core.AssetETF,
+			core.AssetFund, core.AssetCommodity, core.AssetCrypto,
+		},
+	}
+	lookback := 0
+	for _, p := range r.programs() {
+		for _, spec := range p.Indicators() {
+			if !slices.Contains(req.Indicators, spec) {
+				req.Indicators = append(req.Indicators, spec)
+			}
+		}
+		req.Fundamentals = req.Fundamentals || p.Fundamentals()
+		lookback = max(lookback, p.Lookback())
+	}
+	req.PriceHistory = warmup(req.Indicators) + lookback + 1 // +1: the cross trigger reads the previous bar
+	return req
+}
+
+// Params declares the params Init reads; defaults are the current values.
+func (r *Rule) Params() []strategy.Param {
+	source := func(p *expr.Program) string {
+		if p == nil {
+			return ""
+		}
+		return p.Source()
+	}
+	return []strategy.Param{
+		{Name: "entry", Type: strategy.ParamString, Default: source(r.entry),
+			Description: "Condition that emits a buy, e.g. close > sma(200) and rsi(14) < 30"},
+		{Name: "exit", Type: strategy.ParamString, Default: source(r.exit),
+			Description: "Condition that emits a sell"},
+		{Name: "trigger", Type: strategy.ParamString, Default: r.trigger, Options: []string{TriggerCross, TriggerLevel},
+			Description: "cross emits on the bar a rule starts to hold, level on every bar it holds"},
+		{Name: "confidence", Type: strategy.ParamNumber, Default: r.confidence, Min: strategy.Bound(0), Max: strategy.Bound(1),
+			Description: "Confidence of the emitted signals"},
+	}
+}
+
+// Init reads the rules from params:
+//
+//	entry:      condition that emits a buy
+//	exit:       condition that emits a sell
+//	trigger:    cross (default, on the bar the rule starts to hold) | level
+//	confidence: confidence of the emitted signals (default 0.7)
+//
+// At least one of entry and exit is required.
+func (r *Rule) Init(cfg strategy.Config) error {
+	parsed, err := r.parse(cfg.Params)
+	if err != nil {
+		return fmt.Errorf("%s: %w", r.name, err)
+	}
+	*r = *parsed
+	return nil
+}
+
+// parse returns a copy of r with params applied.
+func (r *Rule) parse(params map[string]any) (*Rule, error) {
+	if err := strategy.ValidateParams(r.Params(), params); err != nil {
+		return nil, err
+	}
+	out := *r
+	for _, key := range []string{"entry", "exit"} {
+		raw, present := params[key]
+		if !present {
+			continue
+		}
+		src, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", key)
+		}
+		p, err := Compile(src)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		if key == "entry" {
+			out.entry = p
+		} else {
+			out.exit = p
+		}
+	}
+	if v, ok := params["trigger"].(string); ok {
+		out.trigger = strings.ToLower(v)
+	}
+	if v, ok := strategy.NumParam(params, "confidence"); ok {
+		out.confidence = v
+	}
+
+	switch {
+	case out.entry == nil && out.exit == nil:
+		return nil, fmt.Errorf("an entry or exit rule is required")
+	case out.trigger != TriggerCross && out.trigger != TriggerLevel:
+		return nil, fmt.Errorf("trigger must be cross or level, got %q", out.trigger)
+	case out.confidence <= 0 || out.confidence > 1:
+		return nil, fmt.Errorf("confidence must be within (0, 1], got %g", out.confidence)
+	}
+	return &out, nil
+}
+
+func (r *Rule) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	if len(ctx.OHLCV) == 0 {
+		return nil, nil
+	}
+	env := r.env(ctx)
+
+	buy := r.fires(r.entry, env)
+	sell := r.fires(r.exit, env)
+	if buy == sell {
+		return nil, nil // neither, or both at once
+	}
+	action, p, typ := core.ActionBuy, r.entry, "rule_entry"
+	if sell {
+		action, p, typ = core.ActionSell, r.exit, "rule_exit"
+	}
+
+	return []core.Signal{{
+		Symbol:      ctx.Symbol,
+		Action:      action,
+		Price:       ctx.OHLCV[len(ctx.OHLCV)-1].Close,
+		Confidence:  r.confidence,
+		Reason:      fmt.Sprintf("%s rule met: %s", strings.TrimPrefix(typ, "rule_"), p.Source()),
+		GeneratedAt: ctx.Now,
+		Metadata: map[string]any{
+			"type":       typ,
+			"expression": p.Source(),
+			"trigger":    r.trigger,
+			"values":     p.Terms(env, 0),
+		},
+	}}, nil
+}
+
+// fires reports whether p emits on the latest bar.
+func (r *Rule) fires(p *expr.Program, env contextEnv) bool {
+	if p == nil || !p.True(env, 0) {
+		return false
+	}
+	return r.trigger == TriggerLevel || !p.True(env, 1)
+}
+
+func (r *Rule) programs() []*expr.Program {
+	var out []*expr.Program
+	for _, p := range []*expr.Program{r.entry, r.exit} {
+		if p != nil {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// env adapts ctx, computing any declared indicator the caller did not supply.
+func (r *Rule) env(ctx strategy.AnalysisContext) contextEnv {
+	specs := r.RequiredData().Indicators
+	var missing []string
+	for _, spec := range specs {
+		if _, ok := ctx.Indicators[spec]; !ok {
+			missing = append(missing, spec)
+		}
+	}
+	indicators := ctx.Indicators
+	if len(missing) > 0 {
+		computed, _ := registry.Compute(ctx.OHLCV, missing)
+		indicators = make(map[string][]float64, len(ctx.Indicators)+len(computed))
+		for k, v := range ctx.Indicators {
+			indicators[k] = v
+		}
+		for k, v := range computed {
+			indicators[k] = v
+		}
+	}
+	return contextEnv{bars: ctx.OHLCV, indicators: indicators, fundamental: ctx.Fundamental, fundamentals: ctx.Fundamentals}
+}
+
+// warmup estimates the bars the indicators need before they settle: three
+// times their largest parameter, or 30 bars.
+func warmup(specs []string) int {
+	bars := 30
+	for _, spec := range specs {
+		for _, arg := range strings.Split(spec, "_")[1:] {
+			var v float64
+			if _, err := fmt.Sscan(arg, &v); err == nil {
+				bars = max(bars, int(v)*3)
+			}
+		}
+	}
+	return bars
+}
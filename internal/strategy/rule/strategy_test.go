@@ -0,0 +1,157 @@
+package rule
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+func TestRule_ImplementsStrategy(t *testing.T) {
+	var _ strategy.Strategy = (*Rule)(nil)
+}
+
+// barsFromCloses builds OHLCV bars from a sequence of closing prices with a
+// fixed arbitrary increasing timestamp per bar (time value is irrelevant here).
+func barsFromCloses(closes ...float64) []core.OHLCV {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := make([]core.OHLCV, len(closes))
+	for i, c := range closes {
+		bars[i] = core.OHLCV{
+			Symbol: "T",
+			Open:   c,
+			High:   c + 1,
+			Low:    c - 1,
+			Close:  c,
+			Time:   base.Add(time.Duration(i) * 24 * time.Hour),
+		}
+	}
+	return bars
+}
+
+func newRule(t *testing.T, params map[string]any) *Rule {
+	t.Helper()
+	r := New("dip")
+	if err := r.Init(strategy.Config{Params: params}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	return r
+}
+
+func TestRule_Init(t *testing.T) {
+	r := newRule(t, map[string]any{
+		"entry":      "close > sma(3) and pe_percentile < 20",
+		"exit":       "rsi(14) > 70",
+		"trigger":    "level",
+		"confidence": 0.8,
+	})
+	if r.Name() != "dip" || r.trigger != TriggerLevel || r.confidence != 0.8 {
+		t.Errorf("after Init = %+v", r)
+	}
+	req := r.RequiredData()
+	if strings.Join(req.Indicators, ",") != "sma_3,rsi_14" || !req.Fundamentals || req.PriceHistory != 43 {
+		t.Errorf("RequiredData = %+v", req)
+	}
+	if got := r.Description(); got != "Rule: buy when close > sma(3) and pe_percentile < 20; sell when rsi(14) > 70" {
+		t.Errorf("Description() = %q", got)
+	}
+
+	for params, want := range map[string]map[string]any{
+		"an entry or exit rule is required":   {},
+		"entry: column 9: unknown function":   {"entry": "close > smaa(3)"},
+		"exit: rule must be a condition":      {"exit": "close - sma(3)"},
+		"entry must be a string":              {"entry": 5},
+		"trigger must be one of cross, level": {"entry": "close > 1", "trigger": "edge"},
+		"confidence must be at most 1":        {"entry": "close > 1", "confidence": 1.5},
+		"confidence must be within (0, 1]":    {"entry": "close > 1", "confidence": 0},
+		`unknown param "entyr"`:               {"entyr": "close > 1"},
+	} {
+		err := New("dip").Init(strategy.Config{Params: want})
+		if err == nil || !strings.Contains(err.Error(), "dip: "+params) {
+			t.Errorf("Init(%v) = %v, want %q", want, err, params)
+		}
+	}
+}
+
+func TestRule_CrossTrigger(t *testing.T) {
+	past := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	r := newRule(t, map[string]any{"entry": "close > sma(3)", "exit": "close < sma(3)"})
+
+	sigs, err := r.Analyze(strategy.AnalysisContext{Symbol: "T", Now: past, OHLCV: barsFromCloses(100, 99, 98, 97, 101)})
+	if err != nil || len(sigs) != 1 {
+		t.Fatalf("expected one signal, got %v err=%v", sigs, err)
+	}
+	sig := sigs[0]
+	if sig.Action != core.ActionBuy || sig.Price != 101 || sig.Confidence != 0.7 || !sig.GeneratedAt.Equal(past) {
+		t.Errorf("signal = %+v", sig)
+	}
+	values, _ := sig.Metadata["values"].(map[string]float64)
+	if sig.Metadata["type"] != "rule_entry" || values["close"] != 101 || values["sma(3)"] != 98.66666666666667 {
+		t.Errorf("metadata = %v", sig.Metadata)
+	}
+
+	// Still above the SMA on the next bar: the rule already held, no new entry.
+	if sigs, _ := r.Analyze(strategy.AnalysisContext{Symbol: "T", OHLCV: barsFromCloses(100, 99, 98, 97, 101, 103)}); len(sigs) != 0 {
+		t.Errorf("cross trigger should not repeat, got %v", sigs)
+	}
+
+	sigs, _ = r.Analyze(strategy.AnalysisContext{Symbol: "T", OHLCV: barsFromCloses(100, 101, 102, 103, 99)})
+	if len(sigs) != 1 || sigs[0].Action != core.ActionSell || sigs[0].Metadata["type"] != "rule_exit" {
+		t.Errorf("expected an exit, got %v", sigs)
+	}
+}
+
+func TestRule_LevelTrigger(t *testing.T) {
+	r := newRule(t, map[string]any{"entry": "close > sma(3)", "trigger": "level"})
+	if sigs, _ := r.Analyze(strategy.AnalysisContext{Symbol: "T", OHLCV: barsFromCloses(100, 99, 98, 97, 101, 103)}); len(sigs) != 1 {
+		t.Errorf("level trigger should fire while the rule holds, got %v", sigs)
+	}
+}
+
+func TestRule_Fundamentals(t *testing.T) {
+	r := newRule(t, map[string]any{"entry": "pe_percentile < 20 and close > 50", "trigger": "level"})
+	bars := barsFromCloses(100, 101)
+
+	if sigs, _ := r.Analyze(strategy.AnalysisContext{Symbol: "T", OHLCV: bars}); len(sigs) != 0 {
+		t.Errorf("no fundamentals: expected no signal, got %v", sigs)
+	}
+	missing := &core.Fundamental{PEPercentile: -1}
+	if sigs, _ := r.Analyze(strategy.AnalysisContext{Symbol: "T", OHLCV: bars, Fundamental: missing}); len(sigs) != 0 {
+		t.Errorf("negative percentile is unavailable, got %v", sigs)
+	}
+	cheap := &core.Fundamental{PEPercentile: 12}
+	if sigs, _ := r.Analyze(strategy.AnalysisContext{Symbol: "T", OHLCV: bars, Fundamental: cheap}); len(sigs) != 1 {
+		t.Errorf("expected a buy at the 12th percentile, got %v", sigs)
+	}
+
+	bank := newRule(t, map[string]any{"entry": "pb_percentile < 10", "trigger": "level"})
+	if sigs, _ := bank.Analyze(strategy.AnalysisContext{Symbol: "T", OHLCV: bars, Fundamental: cheap}); len(sigs) != 0 {
+		t.Errorf("no PB percentile: expected no signal, got %v", sigs)
+	}
+	cheap.SetPercentile(core.MetricPB, 4)
+	if sigs, _ := bank.Analyze(strategy.AnalysisContext{Symbol: "T", OHLCV: bars, Fundamental: cheap}); len(sigs) != 1 {
+		t.Errorf("expected a buy at the 4th PB percentile, got %v", sigs)
+	}
+}
+
+func TestRule_UsesProvidedIndicators(t *testing.T) {
+	r := newRule(t, map[string]any{"entry": "crosses_above(rsi(14), 30)"})
+	bars := barsFromCloses(100, 100, 100) // too short to compute rsi(14) itself
+	sigs, err := r.Analyze(strategy.AnalysisContext{Symbol: "T", OHLCV: bars, Indicators: map[string][]float64{
+		"rsi_14": {22, 31},
+	}})
+	if err != nil || len(sigs) != 1 || sigs[0].Action != core.ActionBuy {
+		t.Fatalf("expected a buy from the provided RSI, got %v err=%v", sigs, err)
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	if err := ValidateParams(map[string]any{"entry": "close > sma(200) and rsi(14) < 30"}); err != nil {
+		t.Errorf("ValidateParams: %v", err)
+	}
+	if err := ValidateParams(map[string]any{"entry": "close >"}); err == nil {
+		t.Error("ValidateParams should reject an incomplete rule")
+	}
+}
@@ -0,0 +1,123 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ParamType is the kind of value a strategy param takes.
+type ParamType string
+
+const (
+	ParamInt    ParamType = "int"
+	ParamNumber ParamType = "number"
+	ParamString ParamType = "string"
+	ParamBool   ParamType = "bool"
+	ParamList   ParamType = "list"
+)
+
+// Param describes one strategy param. Default is the value the strategy uses
+// when the param is not given — for a configured instance, its configured
+// value. Min and Max bound numeric params (nil = unbounded) and Options lists
+// the accepted values of a string param, matched case-insensitively.
+type Param struct {
+	Name        string    `json:"name"`
+	Type        ParamType `json:"type"`
+	Default     any       `json:"default"`
+	Min         *float64  `json:"min,omitempty"`
+	Max         *float64  `json:"max,omitempty"`
+	Options     []string  `json:"options,omitempty"`
+	Description string    `json:"description"`
+}
+
+// Parameterized is implemented by strategies that declare their params.
+type Parameterized interface {
+	Params() []Param
+}
+
+// ParamsOf returns the params s declares; ok is false when it declares none.
+func ParamsOf(s Strategy) (params []Param, ok bool) {
+	p, ok := s.(Parameterized)
+	if !ok {
+		return nil, false
+	}
+	return p.Params(), true
+}
+
+// Bound returns a pointer to v, for Param.Min and Param.Max.
+func Bound(v float64) *float64 {
+	return &v
+}
+
+// ValidateParams checks params against schema: every param must be declared,
+// of its declared type, within its range and one of its options. Relations
+// between params (e.g. fast < slow) are left to the strategy's Init.
+func ValidateParams(schema []Param, params map[string]any) error {
+	byName := make(map[string]Param, len(schema))
+	for _, p := range schema {
+		byName[p.Name] = p
+	}
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p, ok := byName[k]
+		if !ok {
+			names := make([]string, len(schema))
+			for i, p := range schema {
+				names[i] = p.Name
+			}
+			if len(names) == 0 {
+				return fmt.Errorf("unknown param %q (the strategy takes none)", k)
+			}
+			return fmt.Errorf("unknown param %q (want one of %s)", k, strings.Join(names, ", "))
+		}
+		if err := p.check(params[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check validates a single value against p.
+func (p Param) check(v any) error {
+	switch p.Type {
+	case ParamInt, ParamNumber:
+		n, ok := number(v)
+		if !ok {
+			return fmt.Errorf("%s must be a number, got %T", p.Name, v)
+		}
+		if p.Type == ParamInt && n != math.Trunc(n) {
+			return fmt.Errorf("%s must be a whole number, got %g", p.Name, n)
+		}
+		if p.Min != nil && n < *p.Min {
+			return fmt.Errorf("%s must be at least %g, got %g", p.Name, *p.Min, n)
+		}
+		if p.Max != nil && n > *p.Max {
+			return fmt.Errorf("%s must be at most %g, got %g", p.Name, *p.Max, n)
+		}
+	case ParamString:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string, got %T", p.Name, v)
+		}
+		if len(p.Options) > 0 && !slices.ContainsFunc(p.Options, func(o string) bool { return strings.EqualFold(o, s) }) {
+			return fmt.Errorf("%s must be one of %s, got %q", p.Name, strings.Join(p.Options, ", "), s)
+		}
+	case ParamBool:
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s must be true or false, got %T", p.Name, v)
+		}
+	case ParamList:
+		if v == nil || reflect.TypeOf(v).Kind() != reflect.Slice {
+			return fmt.Errorf("%s must be a list, got %T", p.Name, v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package strategy
+
+import (
+	"strings"
+	"testing"
+)
+
+var testSchema = []Param{
+	{Name: "period", Type: ParamInt, Default: 14, Min: Bound(2)},
+	{Name: "level", Type: ParamNumber, Default: 30.0, Min: Bound(0), Max: Bound(100)},
+	{Name: "mode", Type: ParamString, Default: "reversion", Options: []string{"reversion", "breakout"}},
+	{Name: "strict", Type: ParamBool, Default: false},
+	{Name: "conditions", Type: ParamList},
+}
+
+func TestValidateParams(t *testing.T) {
+	valid := map[string]any{
+		"period": 20, "level": 25.5, "mode": "breakout", "strict": true,
+		"conditions": []any{map[string]any{"strategy": "rsi"}},
+	}
+	if err := ValidateParams(testSchema, valid); err != nil {
+		t.Errorf("ValidateParams(valid) = %v", err)
+	}
+	if err := ValidateParams(testSchema, map[string]any{"period": 20.0}); err != nil {
+		t.Errorf("a whole float64 (JSON) should pass as an int: %v", err)
+	}
+
+	for want, params := range map[string]map[string]any{
+		`unknown param "perod" (want one of period, level, mode, strict, conditions)`: {"perod": 20},
+		"period must be a number, got string":                                         {"period": "20"},
+		"period must be a whole number, got 2.5":                                      {"period": 2.5},
+		"period must be at least 2, got 1":                                            {"period": 1},
+		"level must be at most 100, got 120":                                          {"level": 120},
+		`mode must be one of reversion, breakout, got "trend"`:                        {"mode": "trend"},
+		"strict must be true or false, got string":                                    {"strict": "yes"},
+		"conditions must be a list, got string":                                       {"conditions": "rsi"},
+	} {
+		err := ValidateParams(testSchema, params)
+		if err == nil || err.Error() != want {
+			t.Errorf("ValidateParams(%v) = %v, want %q", params, err, want)
+		}
+	}
+
+	err := ValidateParams(nil, map[string]any{"x": 1})
+	if err == nil || !strings.Contains(err.Error(), "takes none") {
+		t.Errorf("ValidateParams(nil schema) = %v", err)
+	}
+}
+
+func TestParamsOf(t *testing.T) {
+	if _, ok := ParamsOf(&paramStrategy{}); ok {
+		t.Error("paramStrategy declares no params")
+	}
+	if params, ok := ParamsOf(&schemaStrategy{}); !ok || len(params) != len(testSchema) {
+		t.Errorf("ParamsOf = %v, %v", params, ok)
+	}
+}
+
+type schemaStrategy struct{ paramStrategy }
+
+func (s *schemaStrategy) Params() []Param { return testSchema }
@@ -0,0 +1,79 @@
+package strategy
+
+import (
+	"slices"
+
+	"github.com/newthinker/atlas/internal/core"
+)
+
+// Timeframes a strategy can request in DataRequirements.Timeframes on top of
+// the daily OHLCV. Weekly and monthly bars are resampled from the daily bars;
+// the intraday timeframes are fetched from a collector that supports them.
+const (
+	TimeframeWeekly  = "1w"
+	TimeframeMonthly = "1mo"
+)
+
+// IntradayTimeframes are the intraday intervals a strategy can request.
+var IntradayTimeframes = []string{"1m", "5m", "15m", "30m", "1h"}
+
+// Resampled reports whether tf is built from the daily bars.
+func Resampled(tf string) bool {
+	return tf == TimeframeWeekly || tf == TimeframeMonthly
+}
+
+// ValidTimeframe reports whether tf can be requested.
+func ValidTimeframe(tf string) bool {
+	return Resampled(tf) || slices.Contains(IntradayTimeframes, tf)
+}
+
+// Resample aggregates daily bars into weekly (ISO week) or monthly bars: the
+// period's first open, extreme high and low, last close and total volume. A
+// bar is stamped with the time of its last daily bar, so a period still in
+// progress is a partial bar as of the latest day and no bar looks ahead.
+// Other timeframes return nil.
+func Resample(daily []core.OHLCV, tf string) []core.OHLCV {
+	if !Resampled(tf) {
+		return nil
+	}
+	var out []core.OHLCV
+	prev := -1
+	for _, b := range daily {
+		period := periodOf(b, tf)
+		if period != prev || len(out) == 0 {
+			prev = period
+			b.Interval = tf
+			out = append(out, b)
+			continue
+		}
+		cur := &out[len(out)-1]
+		cur.High = max(cur.High, b.High)
+		cur.Low = min(cur.Low, b.Low)
+		cur.Close = b.Close
+		cur.Volume += b.Volume
+		cur.Time = b.Time
+	}
+	return out
+}
+
+// ResampleAll returns the resampled bars of every resampled timeframe in
+// timeframes, keyed by timeframe; intraday timeframes are left out.
+func ResampleAll(daily []core.OHLCV, timeframes []string) map[string][]core.OHLCV {
+	bars := make(map[string][]core.OHLCV, len(timeframes))
+	for _, tf := range timeframes {
+		if Resampled(tf) {
+			bars[tf] = Resample(daily, tf)
+		}
+	}
+	return bars
+}
+
+// periodOf numbers the week or month of b so that consecutive bars of one
+// period share a number.
+func periodOf(b core.OHLCV, tf string) int {
+	if tf == TimeframeWeekly {
+		year, week := b.Time.ISOWeek()
+		return year*100 + week
+	}
+	return b.Time.Year()*100 + int(b.Time.Month())
+}
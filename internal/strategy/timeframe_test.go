@@ -0,0 +1,62 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+)
+
+// dailyBars returns one bar per weekday from 2024-01-29 (a Monday) through
+// 2024-02-09, closing at 1, 2, 3, ...
+func dailyBars() []core.OHLCV {
+	var bars []core.OHLCV
+	day := time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC)
+	for c := 1.0; len(bars) < 10; day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		bars = append(bars, core.OHLCV{Symbol: "T", Interval: "1d", Open: c - 0.5, High: c + 1, Low: c - 1, Close: c, Volume: 10, Time: day})
+		c++
+	}
+	return bars
+}
+
+func TestResample_Weekly(t *testing.T) {
+	daily := dailyBars()
+	weekly := Resample(daily, TimeframeWeekly)
+	if len(weekly) != 2 {
+		t.Fatalf("weekly = %+v, want 2 bars", weekly)
+	}
+	w := weekly[0]
+	if w.Open != 0.5 || w.High != 6 || w.Low != 0 || w.Close != 5 || w.Volume != 50 || w.Interval != "1w" || !w.Time.Equal(daily[4].Time) {
+		t.Errorf("first week = %+v", w)
+	}
+
+	// A week in progress is a partial bar as of its latest day.
+	partial := Resample(daily[:7], TimeframeWeekly)
+	if last := partial[len(partial)-1]; last.Close != 7 || last.Volume != 20 || !last.Time.Equal(daily[6].Time) {
+		t.Errorf("partial week = %+v", last)
+	}
+}
+
+func TestResample_Monthly(t *testing.T) {
+	monthly := Resample(dailyBars(), TimeframeMonthly)
+	// January ends on Wednesday the 31st: three days, then seven in February.
+	if len(monthly) != 2 || monthly[0].Close != 3 || monthly[1].Open != 3.5 || monthly[1].Volume != 70 {
+		t.Errorf("monthly = %+v", monthly)
+	}
+	if Resample(dailyBars(), "1h") != nil {
+		t.Error("intraday timeframes are not resampled")
+	}
+}
+
+func TestResampleAll(t *testing.T) {
+	bars := ResampleAll(dailyBars(), []string{TimeframeWeekly, "1h"})
+	if len(bars) != 1 || len(bars[TimeframeWeekly]) != 2 {
+		t.Errorf("ResampleAll = %v", bars)
+	}
+	if !ValidTimeframe("1mo") || !ValidTimeframe("15m") || ValidTimeframe("1y") {
+		t.Error("ValidTimeframe")
+	}
+}
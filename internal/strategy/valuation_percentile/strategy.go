@@ -0,0 +1,200 @@
+// Package valuation_percentile signals when a security's PB, PS or dividend
+// yield sits at an extreme of its own multi-year history, for the stocks PE
+// cannot value: banks (PB), loss-makers (PS) and income holdings (dividend
+// yield). Applies to stocks and indexes.
+//
+// The three strategies share this package because they differ only in the
+// metric they rank; pe_percentile stays separate, as it also reports how its
+// percentile was obtained.
+package valuation_percentile
+
+import (
+	"fmt"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+// Strategy emits buy/sell signals from the precomputed percentile of one
+// valuation metric carried on the analysis context's Fundamental
+// (Fundamental.Percentiles, 0-100).
+type Strategy struct {
+	metric string
+	label  string
+	// inverted marks a metric that is high when the price is low (dividend
+	// yield): a yield at the top of its history is a buy.
+	inverted      bool
+	lookbackYears int
+	low, high     float64
+	extremeLow    float64
+	extremeHigh   float64
+	// percentileStep is the per-strategy re-alert step carried to the router via
+	// Signal.Metadata["percentile_step"]. <= 0 means unconfigured: the router
+	// falls back to its global router.percentile_step.
+	percentileStep float64
+}
+
+func newStrategy(metric, label string, inverted bool) *Strategy {
+	return &Strategy{metric: metric, label: label, inverted: inverted,
+		lookbackYears: 5, low: 20, high: 80, extremeLow: 10, extremeHigh: 90}
+}
+
+// NewPB creates the pb_percentile strategy.
+func NewPB() *Strategy { return newStrategy(core.MetricPB, "PB", false) }
+
+// NewPS creates the ps_percentile strategy.
+func NewPS() *Strategy { return newStrategy(core.MetricPS, "PS", false) }
+
+// NewDividendYield creates the dividend_yield_percentile strategy. It buys a
+// high yield percentile and sells a low one.
+func NewDividendYield() *Strategy {
+	return newStrategy(core.MetricDividendYield, "Dividend yield", true)
+}
+
+func (s *Strategy) Name() string { return s.metric + "_percentile" }
+
+func (s *Strategy) Description() string {
+	return s.label + " position in its own multi-year history"
+}
+
+func (s *Strategy) RequiredData() strategy.DataRequirements {
+	ph := s.lookbackYears * 252
+	if s.lookbackYears == 0 {
+		ph = strategy.SinceInceptionBars
+	}
+	return strategy.DataRequirements{
+		// As in pe_percentile, the lookback is declared as price history so
+		// the item's bars cover the years the reason text names.
+		PriceHistory: ph,
+		Fundamentals: true,
+		Valuations:   []string{s.metric},
+		AssetTypes:   []core.AssetType{core.AssetStock, core.AssetIndex},
+	}
+}
+
+// Params declares the params Init reads; defaults are the current values.
+func (s *Strategy) Params() []strategy.Param {
+	verbs := [4]string{"Strong buy below", "Buy below", "Sell above", "Strong sell above"}
+	if s.inverted {
+		verbs = [4]string{"Strong sell below", "Sell below", "Buy above", "Strong buy above"}
+	}
+	return []strategy.Param{
+		{Name: "lookback_years", Type: strategy.ParamInt, Default: s.lookbackYears, Min: strategy.Bound(0),
+			Description: "Years of history to rank against; 0 = since inception"},
+		{Name: "extreme_low", Type: strategy.ParamNumber, Default: s.extremeLow, Min: strategy.Bound(0), Max: strategy.Bound(100),
+			Description: fmt.Sprintf("%s this %s percentile", verbs[0], s.label)},
+		{Name: "low", Type: strategy.ParamNumber, Default: s.low, Min: strategy.Bound(0), Max: strategy.Bound(100),
+			Description: fmt.Sprintf("%s this %s percentile", verbs[1], s.label)},
+		{Name: "high", Type: strategy.ParamNumber, Default: s.high, Min: strategy.Bound(0), Max: strategy.Bound(100),
+			Description: fmt.Sprintf("%s this %s percentile", verbs[2], s.label)},
+		{Name: "extreme_high", Type: strategy.ParamNumber, Default: s.extremeHigh, Min: strategy.Bound(0), Max: strategy.Bound(100),
+			Description: fmt.Sprintf("%s this %s percentile", verbs[3], s.label)},
+		{Name: "percentile_step", Type: strategy.ParamNumber, Default: s.percentileStep,
+			Description: fmt.Sprintf("Percentile points the %s must move before re-alerting; <= 0 = router.percentile_step", s.label)},
+	}
+}
+
+func (s *Strategy) Init(cfg strategy.Config) error {
+	if err := strategy.ValidateParams(s.Params(), cfg.Params); err != nil {
+		return fmt.Errorf("%s: %w", s.Name(), err)
+	}
+	if v, ok := strategy.IntParam(cfg.Params, "lookback_years"); ok {
+		s.lookbackYears = v
+	}
+	if v, ok := strategy.NumParam(cfg.Params, "low"); ok {
+		s.low = v
+	}
+	if v, ok := strategy.NumParam(cfg.Params, "high"); ok {
+		s.high = v
+	}
+	if v, ok := strategy.NumParam(cfg.Params, "extreme_low"); ok {
+		s.extremeLow = v
+	}
+	if v, ok := strategy.NumParam(cfg.Params, "extreme_high"); ok {
+		s.extremeHigh = v
+	}
+	s.percentileStep = 0
+	if v, ok := strategy.NumParam(cfg.Params, "percentile_step"); ok {
+		s.percentileStep = v
+	}
+	if !(s.extremeLow < s.low && s.low < s.high && s.high < s.extremeHigh) {
+		return fmt.Errorf("%s: thresholds must satisfy extreme_low < low < high < extreme_high, got %.1f/%.1f/%.1f/%.1f",
+			s.Name(), s.extremeLow, s.low, s.high, s.extremeHigh)
+	}
+	if s.lookbackYears < 0 {
+		return fmt.Errorf("%s: lookback_years must be non-negative, got %d", s.Name(), s.lookbackYears)
+	}
+	return nil
+}
+
+func (s *Strategy) Analyze(ctx strategy.AnalysisContext) ([]core.Signal, error) {
+	if ctx.Fundamental == nil {
+		return nil, nil
+	}
+	p, ok := ctx.Fundamental.Percentile(s.metric)
+	if !ok || p < 0 {
+		return nil, nil
+	}
+	action, conf := s.classify(p)
+	if action == "" {
+		return nil, nil
+	}
+
+	// "percentile" is the key the router's percentile-step gate reads.
+	md := map[string]any{
+		"metric": s.metric, "percentile": p, s.Name(): p, "lookback_years": s.lookbackYears,
+	}
+	if s.percentileStep > 0 {
+		md["percentile_step"] = s.percentileStep
+	}
+
+	price := 0.0
+	if n := len(ctx.OHLCV); n > 0 {
+		price = ctx.OHLCV[n-1].Close
+	}
+	return []core.Signal{{
+		Symbol: ctx.Symbol, Action: action, Confidence: conf, Price: price,
+		Reason:   s.reasonText(p, len(ctx.OHLCV)),
+		Strategy: s.Name(), GeneratedAt: ctx.Now, Metadata: md,
+	}}, nil
+}
+
+// reasonText names the year span, or the full history with its bar count
+// when lookbackYears is 0, as pe_percentile does.
+func (s *Strategy) reasonText(p float64, bars int) string {
+	if s.lookbackYears == 0 {
+		return fmt.Sprintf("%s at %.1f%% of full history (%d bars)", s.label, p, bars)
+	}
+	return fmt.Sprintf("%s at %.1f%% of its %d-year history", s.label, p, s.lookbackYears)
+}
+
+// classify maps a percentile to (action, confidence); "" means no signal.
+// Bands: extreme→0.8+linear(capped 0.95), normal→0.6-0.8 linear. An
+// inverted metric swaps the buy and sell sides.
+func (s *Strategy) classify(p float64) (core.Action, float64) {
+	var action core.Action
+	var conf float64
+	switch {
+	case p < s.extremeLow:
+		action, conf = core.ActionStrongBuy, min(0.95, 0.8+0.15*(s.extremeLow-p)/s.extremeLow)
+	case p < s.low:
+		action, conf = core.ActionBuy, 0.6+0.2*(s.low-p)/(s.low-s.extremeLow)
+	case p > s.extremeHigh:
+		action, conf = core.ActionStrongSell, min(0.95, 0.8+0.15*(p-s.extremeHigh)/(100-s.extremeHigh))
+	case p > s.high:
+		action, conf = core.ActionSell, 0.6+0.2*(p-s.high)/(s.extremeHigh-s.high)
+	default:
+		return "", 0
+	}
+	if s.inverted {
+		action = opposite[action]
+	}
+	return action, conf
+}
+
+var opposite = map[core.Action]core.Action{
+	core.ActionStrongBuy:  core.ActionStrongSell,
+	core.ActionBuy:        core.ActionSell,
+	core.ActionSell:       core.ActionBuy,
+	core.ActionStrongSell: core.ActionStrongBuy,
+}
@@ -0,0 +1,139 @@
+package valuation_percentile
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newthinker/atlas/internal/core"
+	"github.com/newthinker/atlas/internal/strategy"
+)
+
+func TestStrategy_ImplementsStrategy(t *testing.T) {
+	var _ strategy.Strategy = (*Strategy)(nil)
+}
+
+func ctxWith(metric string, pct float64) strategy.AnalysisContext {
+	f := &core.Fundamental{Symbol: "TEST", PEPercentile: -1}
+	f.SetPercentile(metric, pct)
+	return strategy.AnalysisContext{
+		Symbol: "TEST", Now: time.Now(), Fundamental: f,
+		OHLCV: []core.OHLCV{{Close: 10}, {Close: 12}},
+	}
+}
+
+func TestNames(t *testing.T) {
+	for s, want := range map[*Strategy]string{
+		NewPB(): "pb_percentile", NewPS(): "ps_percentile", NewDividendYield(): "dividend_yield_percentile",
+	} {
+		if s.Name() != want {
+			t.Errorf("Name() = %q, want %q", s.Name(), want)
+		}
+		if v := s.RequiredData().Valuations; len(v) != 1 || v[0]+"_percentile" != want {
+			t.Errorf("%s: Valuations = %v", want, v)
+		}
+	}
+}
+
+func TestAnalyze_Bands(t *testing.T) {
+	cases := []struct {
+		pct       float64
+		pb, yield core.Action // "" = no signal
+		wantConf  float64
+	}{
+		{5, core.ActionStrongBuy, core.ActionStrongSell, 0.875},
+		{15, core.ActionBuy, core.ActionSell, 0.7},
+		{50, "", "", 0},
+		{85, core.ActionSell, core.ActionBuy, 0.7},
+		{95, core.ActionStrongSell, core.ActionStrongBuy, 0.875},
+	}
+	for _, c := range cases {
+		for s, want := range map[*Strategy]core.Action{NewPB(): c.pb, NewDividendYield(): c.yield} {
+			sigs, err := s.Analyze(ctxWith(s.metric, c.pct))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want == "" {
+				if len(sigs) != 0 {
+					t.Errorf("%s pct=%v: want no signal, got %+v", s.Name(), c.pct, sigs)
+				}
+				continue
+			}
+			if len(sigs) != 1 || sigs[0].Action != want {
+				t.Fatalf("%s pct=%v: want %s, got %+v", s.Name(), c.pct, want, sigs)
+			}
+			if conf := sigs[0].Confidence; conf < c.wantConf-1e-9 || conf > c.wantConf+1e-9 {
+				t.Errorf("%s pct=%v: confidence = %v, want %v", s.Name(), c.pct, conf, c.wantConf)
+			}
+		}
+	}
+}
+
+func TestAnalyze_Metadata(t *testing.T) {
+	s := NewPS()
+	if err := s.Init(strategy.Config{Params: map[string]any{"percentile_step": 3, "lookback_years": 10}}); err != nil {
+		t.Fatal(err)
+	}
+	sigs, _ := s.Analyze(ctxWith(core.MetricPS, 4))
+	if len(sigs) != 1 {
+		t.Fatal("expected one signal")
+	}
+	sig := sigs[0]
+	md := sig.Metadata
+	if md["percentile"] != 4.0 || md["ps_percentile"] != 4.0 || md["metric"] != "ps" ||
+		md["lookback_years"] != 10 || md["percentile_step"] != 3.0 {
+		t.Errorf("metadata = %+v", md)
+	}
+	if sig.Price != 12 || sig.Strategy != "ps_percentile" || sig.Reason != "PS at 4.0% of its 10-year history" {
+		t.Errorf("signal = %+v", sig)
+	}
+
+	// Unconfigured step: the router falls back to its global step.
+	s = NewPS()
+	_ = s.Init(strategy.Config{Params: map[string]any{"lookback_years": 0}})
+	sigs, _ = s.Analyze(ctxWith(core.MetricPS, 4))
+	if _, ok := sigs[0].Metadata["percentile_step"]; ok {
+		t.Error("percentile_step should be absent when unconfigured")
+	}
+	if !strings.Contains(sigs[0].Reason, "full history (2 bars)") {
+		t.Errorf("Reason = %q", sigs[0].Reason)
+	}
+}
+
+func TestAnalyze_Unavailable(t *testing.T) {
+	s := NewPB()
+	if sigs, _ := s.Analyze(strategy.AnalysisContext{Symbol: "TEST"}); sigs != nil {
+		t.Errorf("no fundamental: got %+v", sigs)
+	}
+	// Only the PE percentile is known.
+	ctx := strategy.AnalysisContext{Symbol: "TEST", Fundamental: &core.Fundamental{PEPercentile: 3}}
+	if sigs, _ := s.Analyze(ctx); sigs != nil {
+		t.Errorf("no PB percentile: got %+v", sigs)
+	}
+}
+
+func TestInit(t *testing.T) {
+	s := NewDividendYield()
+	if err := s.Init(strategy.Config{Params: map[string]any{"low": 30, "high": 70.0}}); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if s.low != 30 || s.high != 70 || s.extremeLow != 10 || s.extremeHigh != 90 {
+		t.Errorf("after Init = %+v", s)
+	}
+	if got := s.RequiredData().PriceHistory; got != 5*252 {
+		t.Errorf("PriceHistory = %d, want %d", got, 5*252)
+	}
+	for _, params := range []map[string]any{
+		{"low": 95},
+		{"lookback_years": -1},
+		{"lookback": 5},
+	} {
+		err := NewDividendYield().Init(strategy.Config{Params: params})
+		if err == nil || !strings.HasPrefix(err.Error(), "dividend_yield_percentile: ") {
+			t.Errorf("Init(%v) = %v, want a dividend_yield_percentile error", params, err)
+		}
+	}
+	if got := NewPB().RequiredData(); !got.Fundamentals || len(got.AssetTypes) != 2 {
+		t.Errorf("RequiredData = %+v", got)
+	}
+}
@@ -1,5 +1,5 @@
 // Package valuation provides pure functions for historical-percentile
-// computations used by the price_percentile and pe_percentile strategies.
+// computations used by the price_percentile and valuation percentile strategies.
 package valuation
 
 // PercentileRank returns the percentage (0-100) of series values strictly
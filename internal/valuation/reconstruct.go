@@ -19,35 +19,52 @@ var (
 // to reconstruct a meaningful PE series.
 const MinEPSPoints = 8
 
+// Ratio turns a close and the per-share fundamental in effect on its day into
+// a valuation metric.
+type Ratio func(close, perShare float64) float64
+
+// PriceTo is a price-to-fundamental ratio: PE over EPS, PB over book value
+// per share, PS over revenue per share.
+func PriceTo(close, perShare float64) float64 { return close / perShare }
+
+// YieldOn is a yield in percent: dividend yield over dividends per share.
+func YieldOn(close, perShare float64) float64 { return perShare / close * 100 }
+
 // ReconstructPEPercentile rebuilds the historical PE series by aligning each
 // daily close with the latest EPS(TTM) point at or before it (step function),
 // drops days whose aligned EPS <= 0, and returns the percentile of the current
 // PE within that series.
 func ReconstructPEPercentile(closes []core.OHLCV, eps []core.EPSPoint) (float64, error) {
-	// 1-2. Sort the EPS points and require enough positive quarterly points.
-	pts, err := sortedEPSWithGate(eps)
+	return ReconstructPercentile(closes, eps, PriceTo)
+}
+
+// ReconstructPercentile is ReconstructPEPercentile for any metric rebuilt from
+// per-share points (core.EPSPoint.EPS holds the per-share value) by ratio. The
+// errors keep their EPS names whatever the per-share value is.
+func ReconstructPercentile(closes []core.OHLCV, pts []core.EPSPoint, ratio Ratio) (float64, error) {
+	// 1-2. Sort the points and require enough positive quarterly points.
+	pts, err := sortedEPSWithGate(pts)
 	if err != nil {
 		return -1, err
 	}
 
-	// 3. Current EPS(TTM) is the latest point; non-positive means a real loss,
-	//    so the PE percentile is meaningless and the caller must skip (no fallback).
-	currentEPS := pts[len(pts)-1].EPS
-	if currentEPS <= 0 {
+	// 3. The current value is the latest point; non-positive means a real loss,
+	//    so the percentile is meaningless and the caller must skip (no fallback).
+	current := pts[len(pts)-1].EPS
+	if current <= 0 {
 		return -1, ErrNonPositiveEPS
 	}
 
-	// 4. Step-align each close with the latest EPS at or before its time; drop
-	//    days whose aligned EPS <= 0 (loss quarters).
-	_, peSeries := alignPE(closes, pts)
-	// load-bearing: an empty PE series is a data-availability failure, not a
+	// 4. Step-align each close with the latest point at or before its time;
+	//    drop days whose aligned value <= 0 (loss quarters).
+	_, series := align(closes, pts, ratio)
+	// load-bearing: an empty series is a data-availability failure, not a
 	// success — never let PercentileRank's -1 ride out with a nil error.
-	if len(peSeries) == 0 || len(closes) == 0 {
+	if len(series) == 0 || len(closes) == 0 {
 		return -1, ErrInsufficientEPS
 	}
 
-	currentPE := closes[len(closes)-1].Close / currentEPS
-	return PercentileRank(peSeries, currentPE), nil
+	return PercentileRank(series, ratio(closes[len(closes)-1].Close, current)), nil
 }
 
 // effectiveDate is the date an EPS point becomes publicly known.
@@ -88,10 +105,11 @@ func latestEPSAtOrBefore(pts []core.EPSPoint, t time.Time) (eps float64, ok bool
 	return pts[i-1].EPS, true
 }
 
-// alignPE step-aligns each close with the latest EPS point at or before it,
-// skipping days whose aligned EPS <= 0 or whose close <= 0. pts must be sorted
-// ascending by date. Returned dates/pe are ascending and equal length.
-func alignPE(closes []core.OHLCV, pts []core.EPSPoint) (dates []time.Time, pe []float64) {
+// align step-aligns each close with the latest per-share point at or before
+// it, skipping days whose aligned value <= 0 or whose close <= 0, and applies
+// ratio. pts must be sorted ascending by date. Returned dates/values are
+// ascending and equal length.
+func align(closes []core.OHLCV, pts []core.EPSPoint, ratio Ratio) (dates []time.Time, values []float64) {
 	sorted := make([]core.OHLCV, len(closes))
 	copy(sorted, closes)
 	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
@@ -101,7 +119,7 @@ func alignPE(closes []core.OHLCV, pts []core.EPSPoint) (dates []time.Time, pe []
 			continue
 		}
 		dates = append(dates, c.Time)
-		pe = append(pe, c.Close/e)
+		values = append(values, ratio(c.Close, e))
 	}
-	return dates, pe
+	return dates, values
 }
@@ -118,3 +118,20 @@ func TestReconstructPEPercentile_EmptyAfterDrop(t *testing.T) {
 		t.Errorf("empty PE series must return ErrInsufficientEPS, got (%v, %v)", got, err)
 	}
 }
+
+func TestReconstructPercentile_Yield(t *testing.T) {
+	// 分红恒定、价格下跌 → 当前股息率为历史最高
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	dps := quarterlyEPS(start, repeat(1, 8)...)
+	closes := bars(start.AddDate(2, 0, 0), 40, 30, 20, 10)
+	pct, err := ReconstructPercentile(closes, dps, YieldOn)
+	if err != nil {
+		t.Fatalf("ReconstructPercentile: %v", err)
+	}
+	if pct != 75 {
+		t.Errorf("yield percentile = %v, want 75", pct)
+	}
+	if _, err := ReconstructPercentile(closes, dps[:MinEPSPoints-1], YieldOn); !errors.Is(err, ErrInsufficientEPS) {
+		t.Errorf("err = %v, want ErrInsufficientEPS", err)
+	}
+}
@@ -11,31 +11,93 @@ import (
 // closes with the EPS(TTM) points (yahoo 路径:EPSPoint.Date 为报告期;M2 接入
 // EDGAR 后升级为 filing date 生效,见设计文档 §5.1).
 func ReconstructPESeries(closes []core.OHLCV, eps []core.EPSPoint) ([]time.Time, []float64, error) {
-	pts, err := sortedEPSWithGate(eps)
+	return ReconstructSeries(closes, eps, PriceTo)
+}
+
+// ReconstructSeries is ReconstructPESeries for any metric rebuilt from
+// per-share points by ratio, e.g. PB from book value per share.
+func ReconstructSeries(closes []core.OHLCV, pts []core.EPSPoint, ratio Ratio) ([]time.Time, []float64, error) {
+	pts, err := sortedEPSWithGate(pts)
 	if err != nil {
 		return nil, nil, err
 	}
-	dates, pe := alignPE(closes, pts)
-	return dates, pe, nil
+	dates, values := align(closes, pts, ratio)
+	return dates, values, nil
 }
 
 // RollingPercentile computes, for each i, the percentile (0-100) of values[i]
 // within the lookback window (dates[i]-years, dates[i]). Windows with fewer
-// than minPoints samples yield NaN. dates must be ascending.
+// than minPoints samples yield NaN. NaN values, such as the days a stored
+// series lacks the metric, yield NaN and are left out of every window. dates
+// must be ascending.
 func RollingPercentile(dates []time.Time, values []float64, years, minPoints int) []float64 {
 	out := make([]float64, len(values))
-	lo := 0
-	for i := range values {
+	var known []int // indices of the non-NaN values before i
+	lo := 0         // first of known inside the window
+	for i, v := range values {
+		if math.IsNaN(v) {
+			out[i] = math.NaN()
+			continue
+		}
 		cutoff := dates[i].AddDate(-years, 0, 0)
-		for lo < i && !dates[lo].After(cutoff) {
+		for lo < len(known) && !dates[known[lo]].After(cutoff) {
 			lo++
 		}
-		window := values[lo:i]
+		window := known[lo:]
+		known = append(known, i)
 		if len(window) < minPoints {
 			out[i] = math.NaN()
 			continue
 		}
-		out[i] = PercentileRank(window, values[i])
+		less := 0
+		for _, k := range window {
+			if values[k] < v {
+				less++
+			}
+		}
+		out[i] = float64(less) / float64(len(window)) * 100
 	}
 	return out
 }
+
+// PointInTimePE returns, aligned with closes, the PE(TTM) known on each day —
+// close over the latest EPS point already effective (FilingDate when set) —
+// and that PE's percentile within the preceding years of PE history (0 = since
+// inception; the day itself is excluded, as in RollingPercentile). Days
+// without a positive PE, or whose window holds fewer than minPoints samples,
+// are NaN. closes must be ascending. 回测逐 bar 取值用,任何一天都看不到之后的数据。
+func PointInTimePE(closes []core.OHLCV, eps []core.EPSPoint, years, minPoints int) (pe, pct []float64, err error) {
+	return PointInTime(closes, eps, PriceTo, years, minPoints)
+}
+
+// PointInTime is PointInTimePE for any metric rebuilt from per-share points
+// by ratio.
+func PointInTime(closes []core.OHLCV, pts []core.EPSPoint, ratio Ratio, years, minPoints int) (values, pct []float64, err error) {
+	pts, err = sortedEPSWithGate(pts)
+	if err != nil {
+		return nil, nil, err
+	}
+	dates, series := align(closes, pts, ratio)
+
+	values = make([]float64, len(closes))
+	pct = make([]float64, len(closes))
+	for i := range closes {
+		values[i], pct[i] = math.NaN(), math.NaN()
+	}
+	if len(series) == 0 {
+		return values, pct, nil
+	}
+	if years <= 0 {
+		years = dates[len(dates)-1].Year() - dates[0].Year() + 1
+	}
+	rolling := RollingPercentile(dates, series, years, minPoints)
+
+	j := 0
+	for i, c := range closes {
+		if j < len(dates) && c.Time.Equal(dates[j]) {
+			values[i], pct[i] = series[j], rolling[j]
+			j++
+		}
+	}
+	return values, pct, nil
+}
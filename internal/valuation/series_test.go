@@ -17,6 +17,7 @@ import (
 //   boundary[0]   窗口样本 < minPoints → NaN                          → TestRollingPercentile
 //   boundary[1]   对齐 EPS<=0 或 close<=0 的交易日被跳过               → TestReconstructPESeriesSkipsNonPositive
 //   error[0]      正 EPS 点 < MinEPSPoints → ErrInsufficientEPS       → TestReconstructPESeriesInsufficient
+//   functional[2] PointInTimePE 按 closes 对齐、只用当日已生效 EPS      → TestPointInTimePE
 
 func day(y int, m time.Month, d int) time.Time {
 	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
@@ -100,3 +101,56 @@ func TestRollingPercentile(t *testing.T) {
 	assert.InDelta(t, 100.0, got[2], 1e-9) // 窗口 {10,20},当前 30 → 100
 	assert.InDelta(t, 100.0, got[3], 1e-9)
 }
+
+func TestPointInTimePE(t *testing.T) {
+	eps := makeEPS(8, day(2024, 1, 1), 2.0)
+	eps[7].EPS = 4.0
+	eps[7].FilingDate = eps[7].Date.AddDate(0, 0, 45)
+	filed := eps[7].FilingDate
+	closes := []core.OHLCV{
+		{Time: day(2023, 6, 1), Close: 100},         // 首个 EPS 点之前 → NaN
+		{Time: filed.AddDate(0, 0, -3), Close: 100}, // PE 50,窗口为空 → 分位 NaN
+		{Time: filed.AddDate(0, 0, -2), Close: 80},  // PE 40 < 50 → 0 分位
+		{Time: filed.AddDate(0, 0, -1), Close: 120}, // PE 60 → 100 分位
+		{Time: filed, Close: 100},                   // filing 当日生效:PE 25
+	}
+	pe, pct, err := PointInTimePE(closes, eps, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, pe, len(closes))
+	assert.True(t, math.IsNaN(pe[0]))
+	assert.True(t, math.IsNaN(pct[1]))
+	assert.InDelta(t, 40.0, pe[2], 1e-9)
+	assert.InDelta(t, 0.0, pct[2], 1e-9)
+	assert.InDelta(t, 100.0, pct[3], 1e-9)
+	assert.InDelta(t, 25.0, pe[4], 1e-9)
+	assert.InDelta(t, 0.0, pct[4], 1e-9)
+
+	_, _, err = PointInTimePE(closes, eps[:MinEPSPoints-1], 5, 1)
+	assert.ErrorIs(t, err, ErrInsufficientEPS)
+}
+
+func TestRollingPercentileSkipsNaN(t *testing.T) {
+	// PB 缺失的交易日输出 NaN,也不进入之后的窗口
+	dates := []time.Time{day(2025, 1, 1), day(2025, 2, 1), day(2025, 3, 1), day(2025, 4, 1)}
+	values := []float64{10, math.NaN(), 30, 20}
+	got := RollingPercentile(dates, values, 1, 1)
+	assert.True(t, math.IsNaN(got[0]))
+	assert.True(t, math.IsNaN(got[1]))
+	assert.InDelta(t, 100.0, got[2], 1e-9) // 窗口 {10}
+	assert.InDelta(t, 50.0, got[3], 1e-9)  // 窗口 {10,30}
+}
+
+func TestPointInTimeYield(t *testing.T) {
+	dps := makeEPS(8, day(2024, 1, 1), 2.0) // 每股分红 2
+	closes := []core.OHLCV{
+		{Time: day(2026, 1, 5), Close: 100}, // 股息率 2%
+		{Time: day(2026, 1, 6), Close: 50},  // 4% → 100 分位
+		{Time: day(2026, 1, 7), Close: 200}, // 1% → 0 分位
+	}
+	yield, pct, err := PointInTime(closes, dps, YieldOn, 0, 1)
+	require.NoError(t, err)
+	assert.InDelta(t, 2.0, yield[0], 1e-9)
+	assert.InDelta(t, 4.0, yield[1], 1e-9)
+	assert.InDelta(t, 100.0, pct[1], 1e-9)
+	assert.InDelta(t, 0.0, pct[2], 1e-9)
+}